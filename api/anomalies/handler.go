@@ -0,0 +1,103 @@
+package anomalies
+
+import (
+	"net/http"
+	"strconv"
+
+	"qubit/pkg/apperr"
+	"qubit/pkg/openapi"
+	"qubit/service/anomaly"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler handles tenant anomaly alert HTTP requests
+type Handler struct {
+	anomalyService *anomaly.Service // nil when anomaly detection is disabled
+}
+
+// NewHandler creates a new anomaly handler
+func NewHandler(anomalyService *anomaly.Service) *Handler {
+	return &Handler{
+		anomalyService: anomalyService,
+	}
+}
+
+// GetAlertsOperation documents GetAlerts in the OpenAPI spec
+var GetAlertsOperation = openapi.Operation{
+	Summary: "List tenant anomaly alerts",
+	Description: "Returns the tenants whose create or send rate spiked against their baseline, newest first, " +
+		"with the throttle applied to each until an operator acknowledges it",
+	Tags: []string{"Tenants"},
+	Responses: []openapi.Response{
+		{Status: http.StatusOK, Body: AlertListResponse{}},
+		{Status: http.StatusNotFound, Body: ErrorResponse{}},
+	},
+}
+
+// GetAlerts handles GET /tenants/anomalies
+func (h *Handler) GetAlerts(c *gin.Context) {
+	if h.anomalyService == nil {
+		respondError(c, "Failed to retrieve anomaly alerts", anomaly.ErrDisabled)
+		return
+	}
+
+	responses := ToAlertResponseList(h.anomalyService.Alerts())
+
+	c.JSON(http.StatusOK, AlertListResponse{
+		Success: true,
+		Count:   len(responses),
+		Alerts:  responses,
+	})
+}
+
+// AcknowledgeOperation documents Acknowledge in the OpenAPI spec
+var AcknowledgeOperation = openapi.Operation{
+	Summary:     "Acknowledge the anomaly alert of a tenant",
+	Description: "Marks the alert as handled and lifts the throttle; the tenant is not alerted on again within the baseline period",
+	Tags:        []string{"Tenants"},
+	Params: []openapi.Param{
+		{Name: "id", In: openapi.InPath, Type: "integer", Description: "Tenant ID"},
+	},
+	Responses: []openapi.Response{
+		{Status: http.StatusOK, Body: SuccessResponse{}},
+		{Status: http.StatusBadRequest, Body: ErrorResponse{}},
+		{Status: http.StatusNotFound, Body: ErrorResponse{}},
+		{Status: http.StatusInternalServerError, Body: ErrorResponse{}},
+	},
+}
+
+// Acknowledge handles POST /tenants/:id/anomaly/acknowledge
+func (h *Handler) Acknowledge(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Error:   "Invalid request: tenant id must be a positive integer",
+			Code:    apperr.CodeInvalidRequest,
+		})
+		return
+	}
+
+	if h.anomalyService == nil {
+		respondError(c, "Failed to acknowledge anomaly alert", anomaly.ErrDisabled)
+		return
+	}
+
+	alert, err := h.anomalyService.Acknowledge(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, "Failed to acknowledge anomaly alert", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Message: "Anomaly alert acknowledged successfully",
+		Data:    ToAlertResponse(alert),
+	})
+}
+
+// respondError records err for the error middleware, which answers it with the status and code of its kind
+func respondError(c *gin.Context, prefix string, err error) {
+	_ = c.Error(err).SetMeta(prefix)
+}
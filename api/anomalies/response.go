@@ -0,0 +1,66 @@
+package anomalies
+
+import (
+	"time"
+
+	"qubit/pkg/jsonfmt"
+	"qubit/service/anomaly"
+)
+
+// AlertResponse represents a tenant whose create or send rate spiked against its baseline
+type AlertResponse struct {
+	TenantID       int64         `json:"tenantId"`
+	Metric         string        `json:"metric"`
+	Current        int64         `json:"current"`
+	Baseline       float64       `json:"baseline"`
+	DetectedAt     jsonfmt.Time  `json:"detectedAt"`
+	InstanceID     string        `json:"instanceId"`
+	Throttled      bool          `json:"throttled"`
+	ThrottledUntil *jsonfmt.Time `json:"throttledUntil"`
+	AcknowledgedAt *jsonfmt.Time `json:"acknowledgedAt"`
+}
+
+// SuccessResponse represents a generic success response
+type SuccessResponse struct {
+	Success bool        `json:"success"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+// ErrorResponse represents an error response
+type ErrorResponse struct {
+	Success bool   `json:"success"`
+	Error   string `json:"error"`
+	Code    string `json:"code"`
+}
+
+// AlertListResponse represents a list of anomaly alerts
+type AlertListResponse struct {
+	Success bool            `json:"success"`
+	Count   int             `json:"count"`
+	Alerts  []AlertResponse `json:"alerts"`
+}
+
+// ToAlertResponse converts a domain anomaly.Alert to AlertResponse
+func ToAlertResponse(alert anomaly.Alert) AlertResponse {
+	return AlertResponse{
+		TenantID:       alert.TenantID,
+		Metric:         alert.Metric,
+		Current:        alert.Current,
+		Baseline:       alert.Baseline,
+		DetectedAt:     jsonfmt.NewTime(alert.DetectedAt),
+		InstanceID:     alert.InstanceID,
+		Throttled:      alert.Throttled(time.Now()),
+		ThrottledUntil: jsonfmt.NewTimePtr(alert.ThrottledUntil),
+		AcknowledgedAt: jsonfmt.NewTimePtr(alert.AcknowledgedAt),
+	}
+}
+
+// ToAlertResponseList converts domain alerts to responses
+func ToAlertResponseList(alerts []anomaly.Alert) []AlertResponse {
+	responses := make([]AlertResponse, 0, len(alerts))
+	for _, alert := range alerts {
+		responses = append(responses, ToAlertResponse(alert))
+	}
+	return responses
+}
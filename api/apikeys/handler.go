@@ -0,0 +1,139 @@
+package apikeys
+
+import (
+	"net/http"
+	"strconv"
+
+	"qubit/pkg/apperr"
+	"qubit/pkg/openapi"
+	"qubit/service/apikey"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler handles API key management HTTP requests
+type Handler struct {
+	apiKeyService *apikey.Service
+}
+
+// NewHandler creates a new API key handler
+func NewHandler(apiKeyService *apikey.Service) *Handler {
+	return &Handler{
+		apiKeyService: apiKeyService,
+	}
+}
+
+// CreateKeyOperation documents CreateKey in the OpenAPI spec
+var CreateKeyOperation = openapi.Operation{
+	Summary:     "Issue a new API key",
+	Description: "Creates an API key with the given scopes, isTest issues a sandbox key; the secret is only returned in this response",
+	Tags:        []string{"API Keys"},
+	Body:        CreateKeyRequest{},
+	Responses: []openapi.Response{
+		{Status: http.StatusCreated, Body: SuccessResponse{}},
+		{Status: http.StatusBadRequest, Body: ErrorResponse{}},
+		{Status: http.StatusInternalServerError, Body: ErrorResponse{}},
+	},
+}
+
+// CreateKey handles POST /api-keys
+func (h *Handler) CreateKey(c *gin.Context) {
+	var req CreateKeyRequest
+
+	// Bind and validate request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Error:   "Invalid request: " + err.Error(),
+			Code:    apperr.CodeInvalidRequest,
+		})
+		return
+	}
+
+	key, secret, err := h.apiKeyService.CreateKey(c.Request.Context(), req.Name, req.Scopes, req.IsTest)
+	if err != nil {
+		respondError(c, "Failed to create API key", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, SuccessResponse{
+		Success: true,
+		Message: "API key created successfully, store the key now as it cannot be retrieved again",
+		Data: CreatedKeyResponse{
+			KeyResponse: ToKeyResponse(key),
+			Key:         secret,
+		},
+	})
+}
+
+// GetKeysOperation documents GetKeys in the OpenAPI spec
+var GetKeysOperation = openapi.Operation{
+	Summary:     "Get all API keys",
+	Description: "Returns all API keys including revoked ones, without their secrets",
+	Tags:        []string{"API Keys"},
+	Responses: []openapi.Response{
+		{Status: http.StatusOK, Body: KeyListResponse{}},
+		{Status: http.StatusInternalServerError, Body: ErrorResponse{}},
+	},
+}
+
+// GetKeys handles GET /api-keys
+func (h *Handler) GetKeys(c *gin.Context) {
+	keys, err := h.apiKeyService.ListKeys(c.Request.Context())
+	if err != nil {
+		respondError(c, "Failed to retrieve API keys", err)
+		return
+	}
+
+	responses := ToKeyResponseList(keys)
+
+	c.JSON(http.StatusOK, KeyListResponse{
+		Success: true,
+		Count:   len(responses),
+		Keys:    responses,
+	})
+}
+
+// RevokeKeyOperation documents RevokeKey in the OpenAPI spec
+var RevokeKeyOperation = openapi.Operation{
+	Summary:     "Revoke an API key",
+	Description: "Revokes an API key so it can no longer authenticate",
+	Tags:        []string{"API Keys"},
+	Params: []openapi.Param{
+		{Name: "id", In: openapi.InPath, Type: "integer", Description: "API key ID"},
+	},
+	Responses: []openapi.Response{
+		{Status: http.StatusOK, Body: SuccessResponse{}},
+		{Status: http.StatusBadRequest, Body: ErrorResponse{}},
+		{Status: http.StatusNotFound, Body: ErrorResponse{}},
+		{Status: http.StatusInternalServerError, Body: ErrorResponse{}},
+	},
+}
+
+// RevokeKey handles DELETE /api-keys/:id
+func (h *Handler) RevokeKey(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Error:   "Invalid request: api key id must be a positive integer",
+			Code:    apperr.CodeInvalidRequest,
+		})
+		return
+	}
+
+	if err := h.apiKeyService.RevokeKey(c.Request.Context(), id); err != nil {
+		respondError(c, "Failed to revoke API key", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Message: "API key revoked successfully",
+	})
+}
+
+// respondError records err for the error middleware, which answers it with the status and code of its kind
+func respondError(c *gin.Context, prefix string, err error) {
+	_ = c.Error(err).SetMeta(prefix)
+}
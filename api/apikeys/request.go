@@ -0,0 +1,8 @@
+package apikeys
+
+// CreateKeyRequest represents the request to issue a new API key
+type CreateKeyRequest struct {
+	Name   string   `json:"name" binding:"required,max=100"`
+	Scopes []string `json:"scopes" binding:"required,min=1"`
+	IsTest bool     `json:"isTest"`
+}
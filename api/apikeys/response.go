@@ -0,0 +1,74 @@
+package apikeys
+
+import (
+	"qubit/pkg/jsonfmt"
+	"qubit/service/apikey"
+)
+
+// KeyResponse represents an API key in API responses, without its secret
+type KeyResponse struct {
+	ID        int64         `json:"id"`
+	Name      string        `json:"name"`
+	Prefix    string        `json:"prefix"`
+	Scopes    []string      `json:"scopes"`
+	CreatedAt jsonfmt.Time  `json:"createdAt"`
+	RevokedAt *jsonfmt.Time `json:"revokedAt"`
+	IsTest    bool          `json:"isTest"`
+	TenantID  *int64        `json:"tenantId"`
+}
+
+// CreatedKeyResponse represents a newly issued key, the secret is only returned once
+type CreatedKeyResponse struct {
+	KeyResponse
+	Key string `json:"key"`
+}
+
+// SuccessResponse represents a generic success response
+type SuccessResponse struct {
+	Success bool        `json:"success"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+// ErrorResponse represents an error response
+type ErrorResponse struct {
+	Success bool   `json:"success"`
+	Error   string `json:"error"`
+	Code    string `json:"code"`
+}
+
+// KeyListResponse represents a list of API keys
+type KeyListResponse struct {
+	Success bool          `json:"success"`
+	Count   int           `json:"count"`
+	Keys    []KeyResponse `json:"keys"`
+}
+
+// ToKeyResponse converts a domain apikey.Key to KeyResponse
+func ToKeyResponse(k *apikey.Key) KeyResponse {
+	scopes := make([]string, 0, len(k.Scopes))
+	for _, s := range k.Scopes {
+		scopes = append(scopes, string(s))
+	}
+
+	return KeyResponse{
+		ID:        k.ID,
+		Name:      k.Name,
+		Prefix:    k.Prefix,
+		Scopes:    scopes,
+		CreatedAt: jsonfmt.NewTime(k.CreatedAt),
+		RevokedAt: jsonfmt.NewTimePtr(k.RevokedAt),
+		IsTest:    k.IsTest,
+		TenantID:  k.TenantID,
+	}
+}
+
+// ToKeyResponseList converts a slice of domain keys to KeyResponse slice
+func ToKeyResponseList(keys []*apikey.Key) []KeyResponse {
+	responses := make([]KeyResponse, 0, len(keys))
+	for _, k := range keys {
+		responses = append(responses, ToKeyResponse(k))
+	}
+
+	return responses
+}
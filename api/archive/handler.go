@@ -0,0 +1,78 @@
+package archive
+
+import (
+	"net/http"
+
+	"qubit/pkg/apperr"
+	"qubit/pkg/openapi"
+	"qubit/service/message"
+
+	"github.com/gin-gonic/gin"
+)
+
+// userRoleHeader carries the caller role checked against provider override roles
+const userRoleHeader = "X-User-Role"
+
+// Handler handles message archive HTTP requests
+type Handler struct {
+	messageService *message.Service
+}
+
+// NewHandler creates a new archive handler
+func NewHandler(messageService *message.Service) *Handler {
+	return &Handler{
+		messageService: messageService,
+	}
+}
+
+// RedriveOperation documents Redrive in the OpenAPI spec
+var RedriveOperation = openapi.Operation{
+	Summary: "Re-drive archived messages",
+	Description: "Sends the archived messages processed in the given range again, narrowed by phone number or campaign, " +
+		"as new pending messages linked to their originals; an archived message is re-driven at most once and test messages never. " +
+		"A dry run only counts the matching messages",
+	Tags: []string{"Messages"},
+	Body: RedriveRequest{},
+	Responses: []openapi.Response{
+		{Status: http.StatusOK, Body: RedriveResponse{}},
+		{Status: http.StatusBadRequest, Body: ErrorResponse{}},
+		{Status: http.StatusForbidden, Body: ErrorResponse{}},
+		{Status: http.StatusInternalServerError, Body: ErrorResponse{}},
+	},
+}
+
+// Redrive handles POST /archive/redrive
+func (h *Handler) Redrive(c *gin.Context) {
+	var req RedriveRequest
+
+	// Bind and validate request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Error:   "Invalid request: " + err.Error(),
+			Code:    apperr.CodeInvalidRequest,
+		})
+		return
+	}
+
+	result, err := h.messageService.RedriveArchived(c.Request.Context(), message.RedriveFilter{
+		ProcessedFrom: req.ProcessedFrom,
+		ProcessedTo:   req.ProcessedTo,
+		PhoneNumber:   req.PhoneNumber,
+		CampaignID:    req.CampaignID,
+		Provider:      req.Provider,
+		CallerRole:    c.GetHeader(userRoleHeader),
+		DryRun:        req.DryRun,
+	})
+	if err != nil {
+		respondError(c, "Failed to re-drive archived messages", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ToRedriveResponse(result))
+}
+
+// respondError records err for the error middleware, which answers it with the status and code of its kind
+func respondError(c *gin.Context, prefix string, err error) {
+	_ = c.Error(err).SetMeta(prefix)
+}
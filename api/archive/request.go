@@ -0,0 +1,15 @@
+package archive
+
+import "time"
+
+// RedriveRequest selects the archived messages to send again
+type RedriveRequest struct {
+	ProcessedFrom time.Time `json:"processedFrom" binding:"required"`
+	ProcessedTo   time.Time `json:"processedTo" binding:"required"`
+	PhoneNumber   string    `json:"phoneNumber" binding:"omitempty,max=20"`
+	CampaignID    *int64    `json:"campaignId" binding:"omitempty,min=1"`
+	// Provider pins the re-driven messages, empty keeps the provider of every original
+	Provider string `json:"provider" binding:"omitempty,max=100"`
+	// DryRun only counts the messages that would be re-driven
+	DryRun bool `json:"dryRun"`
+}
@@ -0,0 +1,34 @@
+package archive
+
+import "qubit/service/message"
+
+// RedriveResponse reports the outcome of a re-drive
+type RedriveResponse struct {
+	Success  bool   `json:"success"`
+	Message  string `json:"message"`
+	Matched  int64  `json:"matched"`
+	Redriven int64  `json:"redriven"`
+	DryRun   bool   `json:"dryRun"`
+}
+
+// ErrorResponse represents an error response
+type ErrorResponse struct {
+	Success bool   `json:"success"`
+	Error   string `json:"error"`
+	Code    string `json:"code"`
+}
+
+// ToRedriveResponse converts a domain message.RedriveResult to RedriveResponse
+func ToRedriveResponse(result *message.RedriveResult) RedriveResponse {
+	resp := RedriveResponse{
+		Success:  true,
+		Message:  "Archived messages re-driven successfully",
+		Matched:  result.Matched,
+		Redriven: result.Redriven,
+		DryRun:   result.DryRun,
+	}
+	if result.DryRun {
+		resp.Message = "Dry run, no messages were re-driven"
+	}
+	return resp
+}
@@ -0,0 +1,100 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"qubit/pkg/apperr"
+	"qubit/service/apikey"
+)
+
+// APIKeyHeader carries the API key presented by a client
+const APIKeyHeader = "X-API-Key"
+
+// apiKeyContextKey stores the authenticated key in the gin context
+const apiKeyContextKey = "apiKey"
+
+// APIKeyAuth authenticates the API key of a request and stores it in the context
+// Requests without a key are rejected when required is set, otherwise they pass unauthenticated
+func APIKeyAuth(apiKeyService *apikey.Service, required bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		secret := c.GetHeader(APIKeyHeader)
+		if secret == "" {
+			if required {
+				abortWithError(c, http.StatusUnauthorized, apperr.CodeUnauthorized, "The "+APIKeyHeader+" header is required")
+				return
+			}
+			c.Next()
+			return
+		}
+
+		key, err := apiKeyService.Authenticate(c.Request.Context(), secret)
+		if err != nil {
+			respondError(c, "Failed to authenticate API key", err)
+			return
+		}
+
+		// The request context carries the key to handlers outside this package
+		c.Set(apiKeyContextKey, key)
+		c.Request = c.Request.WithContext(apikey.NewContext(c.Request.Context(), key))
+		c.Next()
+	}
+}
+
+// RequireScope restricts a route group to keys granting scope
+// Requests without a key are left to APIKeyAuth, which rejects them when keys are required
+func RequireScope(scope apikey.Scope) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if key, ok := requestKey(c); ok && !key.HasScope(scope) {
+			abortMissingScope(c, scope)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequireReadWriteScope applies read to GET requests and write to every other method
+func RequireReadWriteScope(read, write apikey.Scope) gin.HandlerFunc {
+	readScope, writeScope := RequireScope(read), RequireScope(write)
+	return func(c *gin.Context) {
+		if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+			readScope(c)
+			return
+		}
+		writeScope(c)
+	}
+}
+
+// RequireAPIKey restricts a route to requests presenting a key that grants scope
+// Unlike RequireScope, unauthenticated requests are rejected even when keys are optional
+func RequireAPIKey(scope apikey.Scope) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key, ok := requestKey(c)
+		if !ok {
+			abortWithError(c, http.StatusUnauthorized, apperr.CodeUnauthorized, "The "+APIKeyHeader+" header is required")
+			return
+		}
+		if !key.HasScope(scope) {
+			abortMissingScope(c, scope)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// requestKey returns the key authenticated by APIKeyAuth
+func requestKey(c *gin.Context) (*apikey.Key, bool) {
+	value, ok := c.Get(apiKeyContextKey)
+	if !ok {
+		return nil, false
+	}
+	key, ok := value.(*apikey.Key)
+	return key, ok
+}
+
+func abortMissingScope(c *gin.Context, scope apikey.Scope) {
+	abortWithError(c, http.StatusForbidden, apperr.CodeForbidden, "This API key lacks the "+string(scope)+" scope")
+}
@@ -0,0 +1,109 @@
+package backlogalert
+
+import (
+	"net/http"
+	"time"
+
+	"qubit/pkg/apperr"
+	"qubit/pkg/openapi"
+	"qubit/service/backlogalert"
+
+	"github.com/gin-gonic/gin"
+)
+
+// userIDHeader identifies the operator changing the thresholds, required on admin routes
+const userIDHeader = "X-User-ID"
+
+// Handler handles backlog alert HTTP requests
+type Handler struct {
+	backlogAlertService *backlogalert.Service // nil without an alert webhook
+}
+
+// NewHandler creates a new backlog alert handler
+func NewHandler(backlogAlertService *backlogalert.Service) *Handler {
+	return &Handler{
+		backlogAlertService: backlogAlertService,
+	}
+}
+
+// GetAlertOperation documents GetAlert in the OpenAPI spec
+var GetAlertOperation = openapi.Operation{
+	Summary:     "Get backlog alerting",
+	Description: "Returns the backlog age and size thresholds and whether the last check of this instance found them exceeded",
+	Tags:        []string{"Scheduler"},
+	Responses: []openapi.Response{
+		{Status: http.StatusOK, Body: SuccessResponse{}},
+		{Status: http.StatusNotFound, Body: ErrorResponse{}},
+	},
+}
+
+// GetAlert handles GET /backlog-alert
+func (h *Handler) GetAlert(c *gin.Context) {
+	if h.backlogAlertService == nil {
+		respondError(c, "Failed to retrieve backlog alerting", backlogalert.ErrDisabled)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Message: "Backlog alerting retrieved successfully",
+		Data:    ToAlertResponse(h.backlogAlertService.Thresholds(), h.backlogAlertService.State()),
+	})
+}
+
+// SetThresholdsOperation documents SetThresholds in the OpenAPI spec
+var SetThresholdsOperation = openapi.Operation{
+	Summary:     "Change the backlog alert thresholds",
+	Description: "Changes the thresholds of every instance until changed again, the change is audited with the operator",
+	Tags:        []string{"Scheduler"},
+	Body:        SetThresholdsRequest{},
+	Responses: []openapi.Response{
+		{Status: http.StatusOK, Body: SuccessResponse{}},
+		{Status: http.StatusBadRequest, Body: ErrorResponse{}},
+		{Status: http.StatusNotFound, Body: ErrorResponse{}},
+		{Status: http.StatusInternalServerError, Body: ErrorResponse{}},
+	},
+}
+
+// SetThresholds handles PUT /backlog-alert
+func (h *Handler) SetThresholds(c *gin.Context) {
+	var req SetThresholdsRequest
+
+	// Bind and validate request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Error:   "Invalid request: " + err.Error(),
+			Code:    apperr.CodeInvalidRequest,
+		})
+		return
+	}
+
+	if h.backlogAlertService == nil {
+		respondError(c, "Failed to change the backlog alert thresholds", backlogalert.ErrDisabled)
+		return
+	}
+
+	var maxAge *time.Duration
+	if req.MaxAgeSeconds != nil {
+		d := time.Duration(*req.MaxAgeSeconds) * time.Second
+		maxAge = &d
+	}
+
+	thresholds, err := h.backlogAlertService.SetThresholds(c.Request.Context(), maxAge, req.MaxSize, c.GetHeader(userIDHeader))
+	if err != nil {
+		respondError(c, "Failed to change the backlog alert thresholds", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Message: "Backlog alert thresholds changed successfully",
+		Data:    ToAlertResponse(thresholds, h.backlogAlertService.State()),
+	})
+}
+
+// respondError records err for the error middleware, which answers it with the status and code of its kind
+func respondError(c *gin.Context, prefix string, err error) {
+	_ = c.Error(err).SetMeta(prefix)
+}
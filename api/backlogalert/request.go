@@ -0,0 +1,8 @@
+package backlogalert
+
+// SetThresholdsRequest represents the request to change the backlog alert thresholds, omitted fields are kept
+// 0 disables a check
+type SetThresholdsRequest struct {
+	MaxAgeSeconds *int   `json:"maxAgeSeconds" binding:"omitempty,min=0,max=604800"`
+	MaxSize       *int64 `json:"maxSize" binding:"omitempty,min=0"`
+}
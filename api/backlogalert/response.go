@@ -0,0 +1,69 @@
+package backlogalert
+
+import (
+	"qubit/pkg/jsonfmt"
+	"qubit/service/backlogalert"
+)
+
+// AlertResponse represents the backlog alert thresholds and the outcome of the last check
+type AlertResponse struct {
+	Thresholds ThresholdsResponse `json:"thresholds"`
+	State      StateResponse      `json:"state"`
+}
+
+// ThresholdsResponse represents the thresholds above which the backlog raises an alert
+type ThresholdsResponse struct {
+	MaxAgeSeconds int64         `json:"maxAgeSeconds"`
+	MaxSize       int64         `json:"maxSize"`
+	ChangedBy     string        `json:"changedBy,omitempty"`
+	Since         *jsonfmt.Time `json:"since"`
+}
+
+// StateResponse represents the backlog measured by the last check of the instance serving the request
+type StateResponse struct {
+	Firing     bool          `json:"firing"`
+	Since      *jsonfmt.Time `json:"since"`
+	Breaches   []string      `json:"breaches"`
+	Backlog    int64         `json:"backlog"`
+	AgeSeconds float64       `json:"ageSeconds"`
+	CheckedAt  *jsonfmt.Time `json:"checkedAt"`
+}
+
+// SuccessResponse represents a generic success response
+type SuccessResponse struct {
+	Success bool        `json:"success"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+// ErrorResponse represents an error response
+type ErrorResponse struct {
+	Success bool   `json:"success"`
+	Error   string `json:"error"`
+	Code    string `json:"code"`
+}
+
+// ToAlertResponse converts the thresholds and state of the backlog alert to AlertResponse
+func ToAlertResponse(thresholds backlogalert.Thresholds, state backlogalert.State) AlertResponse {
+	breaches := state.Breaches
+	if breaches == nil {
+		breaches = []string{}
+	}
+
+	return AlertResponse{
+		Thresholds: ThresholdsResponse{
+			MaxAgeSeconds: int64(thresholds.MaxAge.Seconds()),
+			MaxSize:       thresholds.MaxSize,
+			ChangedBy:     thresholds.ChangedBy,
+			Since:         jsonfmt.NewTimePtr(thresholds.Since),
+		},
+		State: StateResponse{
+			Firing:     state.Firing,
+			Since:      jsonfmt.NewTimePtr(state.Since),
+			Breaches:   breaches,
+			Backlog:    state.Backlog,
+			AgeSeconds: state.Age.Seconds(),
+			CheckedAt:  jsonfmt.NewTimePtr(state.CheckedAt),
+		},
+	}
+}
@@ -0,0 +1,390 @@
+package campaigns
+
+import (
+	"net/http"
+	"strconv"
+
+	"qubit/pkg/apperr"
+	"qubit/pkg/openapi"
+	"qubit/pkg/pii"
+	"qubit/service/campaign"
+
+	"github.com/gin-gonic/gin"
+)
+
+// userIDHeader identifies the caller, set by the upstream gateway
+const userIDHeader = "X-User-ID"
+
+// Handler handles campaign-related HTTP requests
+type Handler struct {
+	campaignService *campaign.Service
+}
+
+// NewHandler creates a new campaign handler
+func NewHandler(campaignService *campaign.Service) *Handler {
+	return &Handler{
+		campaignService: campaignService,
+	}
+}
+
+// CreateCampaignOperation documents CreateCampaign in the OpenAPI spec
+var CreateCampaignOperation = openapi.Operation{
+	Summary:     "Create a new campaign",
+	Description: "Creates a campaign in draft status",
+	Tags:        []string{"Campaigns"},
+	Body:        CreateCampaignRequest{},
+	Responses: []openapi.Response{
+		{Status: http.StatusCreated, Body: SuccessResponse{}},
+		{Status: http.StatusBadRequest, Body: ErrorResponse{}},
+		{Status: http.StatusInternalServerError, Body: ErrorResponse{}},
+	},
+}
+
+// CreateCampaign handles POST /campaigns
+func (h *Handler) CreateCampaign(c *gin.Context) {
+	var req CreateCampaignRequest
+
+	// Bind and validate request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Error:   "Invalid request: " + err.Error(),
+			Code:    apperr.CodeInvalidRequest,
+		})
+		return
+	}
+
+	createdBy := c.GetHeader(userIDHeader)
+	if createdBy == "" {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Error:   "Invalid request: " + userIDHeader + " header is required",
+			Code:    apperr.CodeInvalidRequest,
+		})
+		return
+	}
+
+	variants := make([]campaign.Variant, 0, len(req.Variants))
+	for _, v := range req.Variants {
+		variants = append(variants, campaign.Variant{Name: v.Name, Content: v.Content, Percent: v.Percent})
+	}
+
+	created, err := h.campaignService.CreateCampaign(c.Request.Context(), req.Name, req.Content, variants, req.Recipients, createdBy)
+	if err != nil {
+		respondError(c, "Failed to create campaign", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, SuccessResponse{
+		Success: true,
+		Message: "Campaign created successfully",
+		Data:    ToCampaignResponse(created),
+	})
+}
+
+// GetCampaignsOperation documents GetCampaigns in the OpenAPI spec
+var GetCampaignsOperation = openapi.Operation{
+	Summary:     "Get all campaigns",
+	Description: "Returns a list of all campaigns",
+	Tags:        []string{"Campaigns"},
+	Params: []openapi.Param{
+		{Name: "masked", In: openapi.InQuery, Type: "boolean", Description: "Mask the recipient phone numbers, always applied to roles not listed in PII_UNMASKED_ROLES"},
+	},
+	Responses: []openapi.Response{
+		{Status: http.StatusOK, Body: CampaignListResponse{}},
+		{Status: http.StatusInternalServerError, Body: ErrorResponse{}},
+	},
+}
+
+// GetCampaigns handles GET /campaigns
+func (h *Handler) GetCampaigns(c *gin.Context) {
+	list, err := h.campaignService.GetCampaigns(c.Request.Context())
+	if err != nil {
+		respondError(c, "Failed to retrieve campaigns", err)
+		return
+	}
+
+	responses := ToCampaignResponseList(list)
+	if pii.Masked(c.Request.Context()) {
+		for i := range responses {
+			responses[i].mask()
+		}
+	}
+
+	c.JSON(http.StatusOK, CampaignListResponse{
+		Success:   true,
+		Count:     len(responses),
+		Campaigns: responses,
+	})
+}
+
+// GetCampaignOperation documents GetCampaign in the OpenAPI spec
+var GetCampaignOperation = openapi.Operation{
+	Summary:     "Get a campaign",
+	Description: "Returns a single campaign by ID",
+	Tags:        []string{"Campaigns"},
+	Params: []openapi.Param{
+		{Name: "id", In: openapi.InPath, Type: "integer", Description: "Campaign ID"},
+		{Name: "masked", In: openapi.InQuery, Type: "boolean", Description: "Mask the recipient phone numbers, always applied to roles not listed in PII_UNMASKED_ROLES"},
+	},
+	Responses: []openapi.Response{
+		{Status: http.StatusOK, Body: SuccessResponse{}},
+		{Status: http.StatusBadRequest, Body: ErrorResponse{}},
+		{Status: http.StatusNotFound, Body: ErrorResponse{}},
+	},
+}
+
+// GetCampaign handles GET /campaigns/:id
+func (h *Handler) GetCampaign(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	found, err := h.campaignService.GetCampaign(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, "Failed to retrieve campaign", err)
+		return
+	}
+
+	response := ToCampaignResponse(found)
+	if pii.Masked(c.Request.Context()) {
+		response.mask()
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Message: "Campaign retrieved successfully",
+		Data:    response,
+	})
+}
+
+// GetStatsOperation documents GetStats in the OpenAPI spec
+var GetStatsOperation = openapi.Operation{
+	Summary:     "Get campaign stats",
+	Description: "Reports the delivery and replies of the messages of a campaign, in total and per A/B variant, archived messages included",
+	Tags:        []string{"Campaigns"},
+	Params: []openapi.Param{
+		{Name: "id", In: openapi.InPath, Type: "integer", Description: "Campaign ID"},
+	},
+	Responses: []openapi.Response{
+		{Status: http.StatusOK, Body: CampaignStatsResponse{}},
+		{Status: http.StatusBadRequest, Body: ErrorResponse{}},
+		{Status: http.StatusNotFound, Body: ErrorResponse{}},
+	},
+}
+
+// GetStats handles GET /campaigns/:id/stats
+func (h *Handler) GetStats(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	stats, err := h.campaignService.GetStats(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, "Failed to retrieve campaign stats", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ToCampaignStatsResponse(stats))
+}
+
+// SubmitOperation documents Submit in the OpenAPI spec
+var SubmitOperation = openapi.Operation{
+	Summary:     "Submit a campaign for approval",
+	Description: "Moves a draft campaign to pending approval",
+	Tags:        []string{"Campaigns"},
+	Params: []openapi.Param{
+		{Name: "id", In: openapi.InPath, Type: "integer", Description: "Campaign ID"},
+	},
+	Responses: []openapi.Response{
+		{Status: http.StatusOK, Body: SuccessResponse{}},
+		{Status: http.StatusNotFound, Body: ErrorResponse{}},
+		{Status: http.StatusConflict, Body: ErrorResponse{}},
+	},
+}
+
+// Submit handles POST /campaigns/:id/submit
+func (h *Handler) Submit(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	updated, err := h.campaignService.SubmitForApproval(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, "Failed to submit campaign", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Message: "Campaign submitted for approval",
+		Data:    ToCampaignResponse(updated),
+	})
+}
+
+// ApproveOperation documents Approve in the OpenAPI spec
+var ApproveOperation = openapi.Operation{
+	Summary:      "Approve a campaign",
+	Description:  "Approves a campaign pending approval, requires the approver role",
+	Tags:         []string{"Campaigns"},
+	Body:         ReviewCampaignRequest{},
+	BodyOptional: true,
+	Params: []openapi.Param{
+		{Name: "id", In: openapi.InPath, Type: "integer", Description: "Campaign ID"},
+	},
+	Responses: []openapi.Response{
+		{Status: http.StatusOK, Body: SuccessResponse{}},
+		{Status: http.StatusForbidden, Body: ErrorResponse{}},
+		{Status: http.StatusNotFound, Body: ErrorResponse{}},
+		{Status: http.StatusConflict, Body: ErrorResponse{}},
+	},
+}
+
+// Approve handles POST /campaigns/:id/approve
+func (h *Handler) Approve(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	var req ReviewCampaignRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Success: false,
+				Error:   "Invalid request: " + err.Error(),
+				Code:    apperr.CodeInvalidRequest,
+			})
+			return
+		}
+	}
+
+	updated, err := h.campaignService.Approve(c.Request.Context(), id, c.GetHeader(userIDHeader), req.Comment)
+	if err != nil {
+		respondError(c, "Failed to approve campaign", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Message: "Campaign approved",
+		Data:    ToCampaignResponse(updated),
+	})
+}
+
+// RejectOperation documents Reject in the OpenAPI spec
+var RejectOperation = openapi.Operation{
+	Summary:     "Reject a campaign",
+	Description: "Sends a campaign pending approval back to draft, requires the approver role and a comment",
+	Tags:        []string{"Campaigns"},
+	Body:        ReviewCampaignRequest{},
+	Params: []openapi.Param{
+		{Name: "id", In: openapi.InPath, Type: "integer", Description: "Campaign ID"},
+	},
+	Responses: []openapi.Response{
+		{Status: http.StatusOK, Body: SuccessResponse{}},
+		{Status: http.StatusBadRequest, Body: ErrorResponse{}},
+		{Status: http.StatusForbidden, Body: ErrorResponse{}},
+		{Status: http.StatusNotFound, Body: ErrorResponse{}},
+		{Status: http.StatusConflict, Body: ErrorResponse{}},
+	},
+}
+
+// Reject handles POST /campaigns/:id/reject
+func (h *Handler) Reject(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	var req ReviewCampaignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Error:   "Invalid request: " + err.Error(),
+			Code:    apperr.CodeInvalidRequest,
+		})
+		return
+	}
+
+	updated, err := h.campaignService.Reject(c.Request.Context(), id, c.GetHeader(userIDHeader), req.Comment)
+	if err != nil {
+		respondError(c, "Failed to reject campaign", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Message: "Campaign rejected",
+		Data:    ToCampaignResponse(updated),
+	})
+}
+
+// ScheduleOperation documents Schedule in the OpenAPI spec
+var ScheduleOperation = openapi.Operation{
+	Summary:     "Schedule an approved campaign",
+	Description: "Sets the time at which an approved campaign starts sending",
+	Tags:        []string{"Campaigns"},
+	Body:        ScheduleCampaignRequest{},
+	Params: []openapi.Param{
+		{Name: "id", In: openapi.InPath, Type: "integer", Description: "Campaign ID"},
+	},
+	Responses: []openapi.Response{
+		{Status: http.StatusOK, Body: SuccessResponse{}},
+		{Status: http.StatusBadRequest, Body: ErrorResponse{}},
+		{Status: http.StatusNotFound, Body: ErrorResponse{}},
+		{Status: http.StatusConflict, Body: ErrorResponse{}},
+	},
+}
+
+// Schedule handles POST /campaigns/:id/schedule
+func (h *Handler) Schedule(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	var req ScheduleCampaignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Error:   "Invalid request: " + err.Error(),
+			Code:    apperr.CodeInvalidRequest,
+		})
+		return
+	}
+
+	updated, err := h.campaignService.Schedule(c.Request.Context(), id, req.ScheduledAt)
+	if err != nil {
+		respondError(c, "Failed to schedule campaign", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Message: "Campaign scheduled",
+		Data:    ToCampaignResponse(updated),
+	})
+}
+
+// parseID reads the campaign ID path parameter, responding with 400 if it is invalid
+func parseID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Error:   "Invalid request: campaign id must be a positive integer",
+			Code:    apperr.CodeInvalidRequest,
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+// respondError records err for the error middleware, which answers it with the status and code of its kind
+func respondError(c *gin.Context, prefix string, err error) {
+	_ = c.Error(err).SetMeta(prefix)
+}
@@ -0,0 +1,31 @@
+package campaigns
+
+import (
+	"time"
+)
+
+// CreateCampaignRequest represents the request to create a new campaign
+// Either content or variants is required; variants split the recipients between A/B contents
+type CreateCampaignRequest struct {
+	Name       string           `json:"name" binding:"required,max=200"`
+	Content    string           `json:"content" binding:"required_without=Variants,max=500"`
+	Variants   []VariantRequest `json:"variants" binding:"omitempty,max=10,dive"`
+	Recipients []string         `json:"recipients" binding:"required,min=1"`
+}
+
+// VariantRequest represents an A/B content variant and its share of the recipients in percent
+type VariantRequest struct {
+	Name    string `json:"name" binding:"required,max=50"`
+	Content string `json:"content" binding:"required,max=500"`
+	Percent int    `json:"percent" binding:"required,min=1,max=100"`
+}
+
+// ReviewCampaignRequest represents the request to approve or reject a campaign
+type ReviewCampaignRequest struct {
+	Comment string `json:"comment"`
+}
+
+// ScheduleCampaignRequest represents the request to schedule an approved campaign
+type ScheduleCampaignRequest struct {
+	ScheduledAt time.Time `json:"scheduledAt" binding:"required"`
+}
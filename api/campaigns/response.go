@@ -0,0 +1,165 @@
+package campaigns
+
+import (
+	"qubit/pkg/jsonfmt"
+	"qubit/pkg/pii"
+	"qubit/service/campaign"
+)
+
+// CampaignResponse represents a campaign in API responses
+type CampaignResponse struct {
+	ID             int64         `json:"id"`
+	Name           string        `json:"name"`
+	Content        string        `json:"content"`
+	Recipients     []string      `json:"recipients"`
+	RecipientCount int           `json:"recipientCount"`
+	Status         string        `json:"status"`
+	CreatedBy      string        `json:"createdBy"`
+	CreatedAt      jsonfmt.Time  `json:"createdAt"`
+	UpdatedAt      jsonfmt.Time  `json:"updatedAt"`
+	ReviewedBy     *string       `json:"reviewedBy"`
+	ReviewComment  *string       `json:"reviewComment"`
+	ReviewedAt     *jsonfmt.Time `json:"reviewedAt"`
+	ScheduledAt    *jsonfmt.Time `json:"scheduledAt"`
+	CompletedAt    *jsonfmt.Time `json:"completedAt"`
+
+	// Variants is omitted for a campaign without A/B variants
+	Variants []VariantResponse `json:"variants,omitempty"`
+}
+
+// VariantResponse represents an A/B content variant of a campaign
+type VariantResponse struct {
+	Name    string `json:"name"`
+	Content string `json:"content"`
+	Percent int    `json:"percent"`
+}
+
+// CampaignStatsResponse represents the delivery and replies of the messages of a campaign
+type CampaignStatsResponse struct {
+	Success    bool                   `json:"success"`
+	CampaignID int64                  `json:"campaignId"`
+	Totals     VariantStatsResponse   `json:"totals"`
+	Variants   []VariantStatsResponse `json:"variants"`
+}
+
+// VariantStatsResponse represents the delivery and replies of the messages of a variant
+// Failed also counts throttled and quarantined messages; rates are fractions between 0 and 1
+type VariantStatsResponse struct {
+	Variant      string  `json:"variant,omitempty"`
+	Percent      int     `json:"percent"`
+	Messages     int64   `json:"messages"`
+	Pending      int64   `json:"pending"`
+	Sent         int64   `json:"sent"`
+	Failed       int64   `json:"failed"`
+	Cancelled    int64   `json:"cancelled"`
+	Replied      int64   `json:"replied"`
+	DeliveryRate float64 `json:"deliveryRate"`
+	ReplyRate    float64 `json:"replyRate"`
+}
+
+// SuccessResponse represents a generic success response
+type SuccessResponse struct {
+	Success bool        `json:"success"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+// ErrorResponse represents an error response
+type ErrorResponse struct {
+	Success bool   `json:"success"`
+	Error   string `json:"error"`
+	Code    string `json:"code"`
+}
+
+// CampaignListResponse represents a list of campaigns
+type CampaignListResponse struct {
+	Success   bool               `json:"success"`
+	Count     int                `json:"count"`
+	Campaigns []CampaignResponse `json:"campaigns"`
+}
+
+// ToCampaignResponse converts a domain campaign.Campaign to CampaignResponse
+func ToCampaignResponse(c *campaign.Campaign) CampaignResponse {
+	return CampaignResponse{
+		ID:             c.ID,
+		Name:           c.Name,
+		Content:        c.Content,
+		Recipients:     c.Recipients,
+		RecipientCount: len(c.Recipients),
+		Status:         string(c.Status),
+		CreatedBy:      c.CreatedBy,
+		CreatedAt:      jsonfmt.NewTime(c.CreatedAt),
+		UpdatedAt:      jsonfmt.NewTime(c.UpdatedAt),
+		ReviewedBy:     c.ReviewedBy,
+		ReviewComment:  c.ReviewComment,
+		ReviewedAt:     jsonfmt.NewTimePtr(c.ReviewedAt),
+		ScheduledAt:    jsonfmt.NewTimePtr(c.ScheduledAt),
+		CompletedAt:    jsonfmt.NewTimePtr(c.CompletedAt),
+		Variants:       toVariantResponses(c.Variants),
+	}
+}
+
+// toVariantResponses converts domain variants to VariantResponse slice, nil without variants
+func toVariantResponses(variants []campaign.Variant) []VariantResponse {
+	if len(variants) == 0 {
+		return nil
+	}
+
+	responses := make([]VariantResponse, 0, len(variants))
+	for _, v := range variants {
+		responses = append(responses, VariantResponse{Name: v.Name, Content: v.Content, Percent: v.Percent})
+	}
+
+	return responses
+}
+
+// ToCampaignStatsResponse converts domain campaign stats to CampaignStatsResponse
+func ToCampaignStatsResponse(stats *campaign.Stats) CampaignStatsResponse {
+	resp := CampaignStatsResponse{
+		Success:    true,
+		CampaignID: stats.CampaignID,
+		Totals:     toVariantStatsResponse(stats.Totals),
+		Variants:   make([]VariantStatsResponse, 0, len(stats.Variants)),
+	}
+
+	for _, vs := range stats.Variants {
+		resp.Variants = append(resp.Variants, toVariantStatsResponse(vs))
+	}
+
+	return resp
+}
+
+// toVariantStatsResponse converts domain variant stats to VariantStatsResponse
+func toVariantStatsResponse(vs campaign.VariantStats) VariantStatsResponse {
+	return VariantStatsResponse{
+		Variant:      vs.Variant,
+		Percent:      vs.Percent,
+		Messages:     vs.Messages,
+		Pending:      vs.Pending,
+		Sent:         vs.Sent,
+		Failed:       vs.Failed,
+		Cancelled:    vs.Cancelled,
+		Replied:      vs.Replied,
+		DeliveryRate: vs.DeliveryRate(),
+		ReplyRate:    vs.ReplyRate(),
+	}
+}
+
+// ToCampaignResponseList converts a slice of domain campaigns to CampaignResponse slice
+func ToCampaignResponseList(campaigns []*campaign.Campaign) []CampaignResponse {
+	if campaigns == nil {
+		return []CampaignResponse{}
+	}
+
+	responses := make([]CampaignResponse, 0, len(campaigns))
+	for _, c := range campaigns {
+		responses = append(responses, ToCampaignResponse(c))
+	}
+
+	return responses
+}
+
+// mask hides the recipients of the campaign, its content is shared by all of them and stays in clear
+func (r *CampaignResponse) mask() {
+	r.Recipients = pii.MaskAll(r.Recipients)
+}
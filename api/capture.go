@@ -0,0 +1,107 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"qubit/pkg/apperr"
+	"qubit/service/apikey"
+	"qubit/service/replay"
+)
+
+// maxCapturedResponse bounds the response bytes kept to record why a request was rejected
+const maxCapturedResponse = 4 << 10
+
+// responseRecorder keeps the start of the response body next to writing it
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	w.record(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.record([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *responseRecorder) record(b []byte) {
+	if free := maxCapturedResponse - w.body.Len(); free > 0 {
+		w.body.Write(b[:min(len(b), free)])
+	}
+}
+
+// CaptureRejected stores the requests answered with 400, redacted, so support can inspect and replay them
+// A nil service disables capturing; replayed requests are never captured again
+func CaptureRejected(replayService *replay.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if replayService == nil || c.GetHeader(replay.Header) != "" {
+			c.Next()
+			return
+		}
+
+		var body []byte
+		if c.Request.Body != nil {
+			var err error
+			body, err = io.ReadAll(io.LimitReader(c.Request.Body, replay.MaxBodySize+1))
+			if err != nil {
+				abortWithError(c, http.StatusBadRequest, apperr.CodeInvalidRequest, "Failed to read request body: "+err.Error())
+				return
+			}
+			// Handlers read the body as sent, including anything past the captured prefix
+			c.Request.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), c.Request.Body))
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+
+		c.Next()
+
+		if c.Writer.Status() != http.StatusBadRequest {
+			return
+		}
+
+		truncated := len(body) > replay.MaxBodySize
+		if truncated {
+			body = body[:replay.MaxBodySize]
+		}
+
+		req := &replay.Request{
+			Method:        c.Request.Method,
+			Path:          c.Request.URL.RequestURI(),
+			Headers:       replay.RedactHeaders(c.Request.Header),
+			Body:          replay.RedactBody(body),
+			BodyTruncated: truncated,
+			Status:        c.Writer.Status(),
+			Error:         responseBody(recorder.body.Bytes()).Error,
+			CreatedAt:     time.Now(),
+		}
+		if key, ok := apikey.FromContext(c.Request.Context()); ok {
+			if key.ID > 0 {
+				id := key.ID
+				req.APIKeyID = &id
+			}
+			req.TenantID = key.TenantID
+		}
+
+		replayService.Capture(context.WithoutCancel(c.Request.Context()), req)
+	}
+}
+
+// responseBody decodes an ErrorResponse body, a body of another shape is returned as its Error
+func responseBody(body []byte) ErrorResponse {
+	var response ErrorResponse
+	if err := json.Unmarshal(body, &response); err == nil && response.Error != "" {
+		return response
+	}
+	return ErrorResponse{Error: string(body)}
+}
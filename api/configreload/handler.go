@@ -0,0 +1,54 @@
+package configreload
+
+import (
+	"net/http"
+
+	"qubit/pkg/openapi"
+	"qubit/service/configreload"
+
+	"github.com/gin-gonic/gin"
+)
+
+// userIDHeader identifies the operator reloading the configuration, required on admin routes
+const userIDHeader = "X-User-ID"
+
+// Handler handles configuration reload HTTP requests
+type Handler struct {
+	configReloadService *configreload.Service
+}
+
+// NewHandler creates a new configuration reload handler
+func NewHandler(configReloadService *configreload.Service) *Handler {
+	return &Handler{
+		configReloadService: configReloadService,
+	}
+}
+
+// ReloadOperation documents Reload in the OpenAPI spec
+var ReloadOperation = openapi.Operation{
+	Summary: "Reload the configuration",
+	Description: "Reads the configuration of the instance serving the request again, like SIGHUP. " +
+		"The scheduler interval, cron expression and batch size and the provider webhook URLs and auth keys apply right away, " +
+		"other changes are listed as requiring a restart",
+	Tags: []string{"Maintenance"},
+	Responses: []openapi.Response{
+		{Status: http.StatusOK, Body: SuccessResponse{}},
+		{Status: http.StatusBadRequest, Body: ErrorResponse{}},
+		{Status: http.StatusInternalServerError, Body: ErrorResponse{}},
+	},
+}
+
+// Reload handles POST /admin/config/reload
+func (h *Handler) Reload(c *gin.Context) {
+	result, err := h.configReloadService.Reload(c.Request.Context(), c.GetHeader(userIDHeader))
+	if err != nil {
+		_ = c.Error(err).SetMeta("Failed to reload the configuration")
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Message: "Configuration reloaded successfully",
+		Data:    ToReloadResponse(result),
+	})
+}
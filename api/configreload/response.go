@@ -0,0 +1,45 @@
+package configreload
+
+import (
+	"qubit/pkg/jsonfmt"
+	"qubit/service/configreload"
+)
+
+// ReloadResponse represents the outcome of a configuration reload
+type ReloadResponse struct {
+	ReloadedAt         jsonfmt.Time `json:"reloadedAt"`
+	Applied            []string     `json:"applied"`
+	SchedulerRestarted bool         `json:"schedulerRestarted"`
+	RestartRequired    []string     `json:"restartRequired"`
+}
+
+// SuccessResponse represents a generic success response
+type SuccessResponse struct {
+	Success bool        `json:"success"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+// ErrorResponse represents an error response
+type ErrorResponse struct {
+	Success bool   `json:"success"`
+	Error   string `json:"error"`
+	Code    string `json:"code"`
+}
+
+// ToReloadResponse converts a reload result to ReloadResponse
+func ToReloadResponse(result *configreload.Result) ReloadResponse {
+	resp := ReloadResponse{
+		ReloadedAt:         jsonfmt.NewTime(result.ReloadedAt),
+		Applied:            result.Applied,
+		SchedulerRestarted: result.SchedulerRestarted,
+		RestartRequired:    result.RestartRequired,
+	}
+	if resp.Applied == nil {
+		resp.Applied = []string{}
+	}
+	if resp.RestartRequired == nil {
+		resp.RestartRequired = []string{}
+	}
+	return resp
+}
@@ -0,0 +1,93 @@
+package diagnostics
+
+import (
+	"net/http"
+
+	"qubit/env/postgres"
+	"qubit/env/provider"
+	"qubit/pkg/buildinfo"
+	"qubit/pkg/openapi"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler handles diagnostics HTTP requests
+type Handler struct {
+	postgres  *postgres.Client
+	providers *provider.Registry
+}
+
+// NewHandler creates a new diagnostics handler
+func NewHandler(postgresClient *postgres.Client, providers *provider.Registry) *Handler {
+	return &Handler{
+		postgres:  postgresClient,
+		providers: providers,
+	}
+}
+
+// GetWebhookOperation documents GetWebhook in the OpenAPI spec
+var GetWebhookOperation = openapi.Operation{
+	Summary:     "Get webhook diagnostics",
+	Description: "Returns the build version, the identification headers, the DNS pre-resolution and connection warm-up status of every webhook provider and the circuit breaker state of every provider",
+	Tags:        []string{"Diagnostics"},
+	Responses: []openapi.Response{
+		{Status: http.StatusOK, Body: WebhookDiagnosticsResponse{}},
+	},
+}
+
+// GetWebhook handles GET /diagnostics/webhook
+func (h *Handler) GetWebhook(c *gin.Context) {
+	warmUps := make([]WarmUpResponse, 0, len(h.providers.Names()))
+	for _, name := range h.providers.Names() {
+		warmer, ok := h.providers.Warmer(name)
+		if !ok {
+			continue
+		}
+		resp := ToWarmUpResponse(warmer.WarmUpStatus())
+		resp.Provider = name
+		warmUps = append(warmUps, resp)
+	}
+
+	circuits := make([]CircuitResponse, 0, len(h.providers.Names()))
+	for _, name := range h.providers.Names() {
+		breaker, ok := h.providers.Breaker(name)
+		if !ok {
+			continue
+		}
+		resp := ToCircuitResponse(breaker.Stats())
+		resp.Provider = name
+		circuits = append(circuits, resp)
+	}
+
+	c.JSON(http.StatusOK, WebhookDiagnosticsResponse{
+		Success:   true,
+		Version:   buildinfo.Version,
+		Instance:  h.providers.Instance(),
+		UserAgent: buildinfo.UserAgent(),
+		Providers: warmUps,
+		Circuits:  circuits,
+	})
+}
+
+// GetSchemaOperation documents GetSchema in the OpenAPI spec
+var GetSchemaOperation = openapi.Operation{
+	Summary:     "Get schema drift",
+	Description: "Compares the live database schema against the migrations and lists missing tables, columns, indexes and wrong column types",
+	Tags:        []string{"Diagnostics"},
+	Responses: []openapi.Response{
+		{Status: http.StatusOK, Body: SchemaDiagnosticsResponse{}},
+		{Status: http.StatusForbidden, Body: gin.H{}},
+		{Status: http.StatusInternalServerError, Body: gin.H{}},
+	},
+}
+
+// GetSchema handles GET /diagnostics/schema
+func (h *Handler) GetSchema(c *gin.Context) {
+	drift, err := h.postgres.CheckSchema(c.Request.Context())
+	if err != nil {
+		_ = c.Error(err).SetMeta("Failed to check schema")
+		return
+	}
+
+	c.JSON(http.StatusOK, ToSchemaDiagnosticsResponse(drift))
+}
@@ -0,0 +1,116 @@
+package diagnostics
+
+import (
+	"qubit/env/postgres"
+	"qubit/env/provider"
+	"qubit/pkg/jsonfmt"
+)
+
+// WarmUpResponse represents the webhook connection warm-up status
+type WarmUpResponse struct {
+	Provider           string        `json:"provider"`
+	Host               string        `json:"host"`
+	Addresses          []string      `json:"addresses"`
+	Warm               bool          `json:"warm"`
+	WarmedAt           *jsonfmt.Time `json:"warmedAt"`
+	DurationMs         int64         `json:"durationMs"`
+	Error              *string       `json:"error"`
+	LastUsedAt         *jsonfmt.Time `json:"lastUsedAt"`
+	KeepWarmForSeconds float64       `json:"keepWarmForSeconds"`
+}
+
+// CircuitResponse represents the circuit breaker state of a provider
+// Transitions counts every state change since startup, e.g. "closed->open"
+type CircuitResponse struct {
+	Provider            string           `json:"provider"`
+	State               string           `json:"state"`
+	ConsecutiveFailures int              `json:"consecutiveFailures"`
+	OpenedAt            *jsonfmt.Time    `json:"openedAt"`
+	Transitions         map[string]int64 `json:"transitions"`
+	Rejected            int64            `json:"rejected"`
+}
+
+// WebhookDiagnosticsResponse represents webhook diagnostics
+type WebhookDiagnosticsResponse struct {
+	Success   bool              `json:"success"`
+	Version   string            `json:"version"`
+	Instance  string            `json:"instance"`
+	UserAgent string            `json:"userAgent"`
+	Providers []WarmUpResponse  `json:"providers"`
+	Circuits  []CircuitResponse `json:"circuits"`
+}
+
+// ToCircuitResponse converts circuit breaker stats to CircuitResponse
+func ToCircuitResponse(stats provider.BreakerStats) CircuitResponse {
+	return CircuitResponse{
+		State:               string(stats.State),
+		ConsecutiveFailures: stats.ConsecutiveFailures,
+		OpenedAt:            jsonfmt.NewTimePtr(stats.OpenedAt),
+		Transitions:         stats.Transitions,
+		Rejected:            stats.Rejected,
+	}
+}
+
+// ToWarmUpResponse converts a webhook warm-up status to WarmUpResponse
+func ToWarmUpResponse(status provider.WarmUpStatus) WarmUpResponse {
+	addresses := status.Addresses
+	if addresses == nil {
+		addresses = []string{}
+	}
+
+	return WarmUpResponse{
+		Host:               status.Host,
+		Addresses:          addresses,
+		Warm:               status.WarmedAt != nil && status.Error == nil,
+		WarmedAt:           jsonfmt.NewTimePtr(status.WarmedAt),
+		DurationMs:         status.Duration.Milliseconds(),
+		Error:              status.Error,
+		LastUsedAt:         jsonfmt.NewTimePtr(status.LastUsedAt),
+		KeepWarmForSeconds: status.KeepWarmFor.Seconds(),
+	}
+}
+
+// ColumnTypeDriftResponse represents a column with an unexpected type
+type ColumnTypeDriftResponse struct {
+	Column   string `json:"column"`
+	Expected string `json:"expected"`
+	Actual   string `json:"actual"`
+}
+
+// SchemaDiagnosticsResponse represents the drift between the live schema and the migrations
+type SchemaDiagnosticsResponse struct {
+	Success        bool                      `json:"success"`
+	Drift          bool                      `json:"drift"`
+	MissingTables  []string                  `json:"missingTables"`
+	MissingColumns []string                  `json:"missingColumns"`
+	WrongTypes     []ColumnTypeDriftResponse `json:"wrongTypes"`
+	MissingIndexes []string                  `json:"missingIndexes"`
+}
+
+// ToSchemaDiagnosticsResponse converts a postgres schema drift to SchemaDiagnosticsResponse
+func ToSchemaDiagnosticsResponse(drift *postgres.SchemaDrift) SchemaDiagnosticsResponse {
+	orEmpty := func(values []string) []string {
+		if values == nil {
+			return []string{}
+		}
+		return values
+	}
+
+	wrongTypes := make([]ColumnTypeDriftResponse, 0, len(drift.WrongTypes))
+	for _, w := range drift.WrongTypes {
+		wrongTypes = append(wrongTypes, ColumnTypeDriftResponse{
+			Column:   w.Column,
+			Expected: w.Expected,
+			Actual:   w.Actual,
+		})
+	}
+
+	return SchemaDiagnosticsResponse{
+		Success:        true,
+		Drift:          drift.HasDrift(),
+		MissingTables:  orEmpty(drift.MissingTables),
+		MissingColumns: orEmpty(drift.MissingColumns),
+		WrongTypes:     wrongTypes,
+		MissingIndexes: orEmpty(drift.MissingIndexes),
+	}
+}
@@ -0,0 +1,76 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"qubit/pkg/apperr"
+	"qubit/pkg/ctxerr"
+)
+
+// ErrorResponse is the body of every error answered by the API
+// Code is stable and meant for clients to match, Error is meant for humans
+type ErrorResponse struct {
+	Success bool   `json:"success"`
+	Error   string `json:"error"`
+	Code    string `json:"code"`
+}
+
+// HandleErrors answers the last error a handler recorded with c.Error when the handler wrote no response
+// The Meta of the recorded error, if a string, prefixes the message, e.g. "Failed to create message"
+// It has to be the innermost middleware, so middleware checking the status after c.Next sees the response
+func HandleErrors() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if c.Writer.Written() || len(c.Errors) == 0 {
+			return
+		}
+
+		last := c.Errors.Last()
+		prefix, _ := last.Meta.(string)
+		writeError(c, prefix, last.Err)
+	}
+}
+
+// respondError records err for Logger and answers it right away, for middleware that stops a request
+func respondError(c *gin.Context, prefix string, err error) {
+	_ = c.Error(err).SetMeta(prefix)
+	writeError(c, prefix, err)
+}
+
+// writeError answers err with the status and code of its apperr kind
+// Internal errors are answered with prefix alone, their details are only logged
+// A bodiless 499 is written if the client went away
+func writeError(c *gin.Context, prefix string, err error) {
+	if ctxerr.IsCanceled(err) {
+		c.AbortWithStatus(ctxerr.StatusClientClosedRequest)
+		return
+	}
+
+	typed, ok := apperr.From(err)
+	if !ok {
+		message := prefix
+		if message == "" {
+			message = "Internal server error"
+		}
+		abortWithError(c, http.StatusInternalServerError, apperr.CodeInternal, message)
+		return
+	}
+
+	message := err.Error()
+	if prefix != "" {
+		message = prefix + ": " + message
+	}
+	abortWithError(c, typed.Kind.HTTPStatus(), typed.Code, message)
+}
+
+// abortWithError stops the request with an error response
+func abortWithError(c *gin.Context, status int, code, message string) {
+	c.AbortWithStatusJSON(status, ErrorResponse{
+		Success: false,
+		Error:   message,
+		Code:    code,
+	})
+}
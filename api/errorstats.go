@@ -0,0 +1,59 @@
+package api
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+
+	"qubit/pkg/apperr"
+	"qubit/service/errorstats"
+	"qubit/service/message"
+)
+
+// RecordErrors counts the error responses of the API by category for GET /errors/summary
+// Rejected requests count as validation, a refused provider pin as routing and server errors as internal;
+// other client errors, such as a missing resource, are not counted
+func RecordErrors(errorStats *errorstats.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+
+		c.Next()
+
+		status := c.Writer.Status()
+		if status < http.StatusBadRequest {
+			return
+		}
+
+		body := responseBody(recorder.body.Bytes())
+		category, ok := errorCategory(status, body.Code)
+		if !ok {
+			return
+		}
+
+		// Internal errors are answered without their details, which the summary keeps for operators
+		text := body.Error
+		if last := c.Errors.Last(); category == errorstats.CategoryInternal && last != nil {
+			text = last.Error()
+			if prefix, _ := last.Meta.(string); prefix != "" {
+				text = prefix + ": " + text
+			}
+		}
+
+		errorStats.Record(category, "", body.Code, text)
+	}
+}
+
+// errorCategory returns the category an error response is counted under, false when it is not counted
+func errorCategory(status int, code string) (string, bool) {
+	switch {
+	case slices.Contains(message.RoutingCodes, code):
+		return errorstats.CategoryRouting, true
+	case status == http.StatusBadRequest, status == http.StatusConflict, status == http.StatusUnprocessableEntity:
+		return errorstats.CategoryValidation, true
+	case status >= http.StatusInternalServerError && code != apperr.CodeMaintenance:
+		return errorstats.CategoryInternal, true
+	}
+	return "", false
+}
@@ -0,0 +1,82 @@
+package errorsummary
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"qubit/pkg/apperr"
+	"qubit/pkg/openapi"
+	"qubit/service/errorstats"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Error message listing limits
+const (
+	defaultTop = 10
+	maxTop     = 100
+)
+
+// Handler handles error summary HTTP requests
+type Handler struct {
+	errorStats *errorstats.Service
+}
+
+// NewHandler creates a new error summary handler
+func NewHandler(errorStats *errorstats.Service) *Handler {
+	return &Handler{
+		errorStats: errorStats,
+	}
+}
+
+// GetSummaryOperation documents GetSummary in the OpenAPI spec
+var GetSummaryOperation = openapi.Operation{
+	Summary: "Get an error summary",
+	Description: "Returns the errors of this instance within the window counted per category (validation, routing, " +
+		"provider_permanent, provider_transient, internal) and per provider, with the most frequent error messages",
+	Tags: []string{"Diagnostics"},
+	Params: []openapi.Param{
+		{Name: "windowMinutes", In: openapi.InQuery, Type: "integer", Description: "Aggregation window in minutes, up to 1440 (default 60)"},
+		{Name: "top", In: openapi.InQuery, Type: "integer", Description: "Number of error messages listed, up to 100 (default 10)"},
+	},
+	Responses: []openapi.Response{
+		{Status: http.StatusOK, Body: SummaryResponse{}},
+		{Status: http.StatusBadRequest, Body: ErrorResponse{}},
+	},
+}
+
+// GetSummary handles GET /errors/summary
+func (h *Handler) GetSummary(c *gin.Context) {
+	windowMinutes := 60
+	if value := c.Query("windowMinutes"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 || time.Duration(parsed)*time.Minute > errorstats.MaxWindow {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Success: false,
+				Error:   "Invalid request: windowMinutes must be an integer between 1 and 1440",
+				Code:    apperr.CodeInvalidRequest,
+			})
+			return
+		}
+		windowMinutes = parsed
+	}
+
+	top := defaultTop
+	if value := c.Query("top"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 || parsed > maxTop {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Success: false,
+				Error:   "Invalid request: top must be an integer between 1 and 100",
+				Code:    apperr.CodeInvalidRequest,
+			})
+			return
+		}
+		top = parsed
+	}
+
+	summary := h.errorStats.Summarize(time.Duration(windowMinutes)*time.Minute, top)
+
+	c.JSON(http.StatusOK, ToSummaryResponse(summary))
+}
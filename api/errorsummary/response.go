@@ -0,0 +1,77 @@
+package errorsummary
+
+import (
+	"time"
+
+	"qubit/pkg/jsonfmt"
+	"qubit/service/errorstats"
+)
+
+// SummaryResponse represents the errors of this instance within a window
+type SummaryResponse struct {
+	Success       bool               `json:"success"`
+	WindowMinutes int                `json:"windowMinutes"`
+	Total         int64              `json:"total"`
+	Truncated     bool               `json:"truncated"`
+	ByCategory    map[string]int64   `json:"byCategory"`
+	ByProvider    []CountResponse    `json:"byProvider"`
+	TopErrors     []TopErrorResponse `json:"topErrors"`
+}
+
+// CountResponse represents the number of errors of a category and provider
+type CountResponse struct {
+	Category string `json:"category"`
+	Provider string `json:"provider"`
+	Count    int64  `json:"count"`
+}
+
+// TopErrorResponse represents an error message recorded repeatedly within the window
+type TopErrorResponse struct {
+	Category string       `json:"category"`
+	Provider string       `json:"provider"`
+	Code     string       `json:"code"`
+	Message  string       `json:"message"`
+	Count    int64        `json:"count"`
+	LastSeen jsonfmt.Time `json:"lastSeen"`
+}
+
+// ErrorResponse represents an error response
+type ErrorResponse struct {
+	Success bool   `json:"success"`
+	Error   string `json:"error"`
+	Code    string `json:"code"`
+}
+
+// ToSummaryResponse converts a domain errorstats.Summary to SummaryResponse
+func ToSummaryResponse(summary errorstats.Summary) SummaryResponse {
+	resp := SummaryResponse{
+		Success:       true,
+		WindowMinutes: int(summary.Window / time.Minute),
+		Total:         summary.Total,
+		Truncated:     summary.Truncated,
+		ByCategory:    summary.ByCategory,
+		ByProvider:    make([]CountResponse, len(summary.ByProvider)),
+		TopErrors:     make([]TopErrorResponse, len(summary.TopErrors)),
+	}
+
+	for i, count := range summary.ByProvider {
+		resp.ByProvider[i] = CountResponse{
+			Category: count.Category,
+			Provider: count.Provider,
+			Count:    count.Count,
+		}
+	}
+
+	for i, t := range summary.TopErrors {
+		resp.TopErrors[i] = TopErrorResponse{
+			Category: t.Category,
+			Provider: t.Provider,
+			Code:     t.Code,
+			Message:  t.Message,
+			Count:    t.Count,
+			LastSeen: jsonfmt.NewTime(t.LastSeen),
+		}
+	}
+
+	return resp
+}
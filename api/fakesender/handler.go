@@ -0,0 +1,91 @@
+package fakesender
+
+import (
+	"net/http"
+
+	"qubit/pkg/apperr"
+	"qubit/pkg/openapi"
+	"qubit/service/fakesender"
+
+	"github.com/gin-gonic/gin"
+)
+
+// userIDHeader identifies the operator switching the mode, required on admin routes
+const userIDHeader = "X-User-ID"
+
+// Handler handles fake sender HTTP requests
+type Handler struct {
+	fakeSenderService *fakesender.Service
+}
+
+// NewHandler creates a new fake sender handler
+func NewHandler(fakeSenderService *fakesender.Service) *Handler {
+	return &Handler{
+		fakeSenderService: fakeSenderService,
+	}
+}
+
+// GetModeOperation documents GetMode in the OpenAPI spec
+var GetModeOperation = openapi.Operation{
+	Summary:     "Get fake sender mode",
+	Description: "Returns whether messages go through the simulated sender instead of the real providers",
+	Tags:        []string{"Providers"},
+	Responses: []openapi.Response{
+		{Status: http.StatusOK, Body: SuccessResponse{}},
+	},
+}
+
+// GetMode handles GET /fake-sender
+func (h *Handler) GetMode(c *gin.Context) {
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Message: "Fake sender mode retrieved successfully",
+		Data:    ToModeResponse(h.fakeSenderService.Mode()),
+	})
+}
+
+// SetModeOperation documents SetMode in the OpenAPI spec
+var SetModeOperation = openapi.Operation{
+	Summary:     "Switch the fake sender",
+	Description: "Switches every instance to the simulated sender or back to the real providers, the switch is audited with the operator and reason",
+	Tags:        []string{"Providers"},
+	Body:        SetModeRequest{},
+	Responses: []openapi.Response{
+		{Status: http.StatusOK, Body: SuccessResponse{}},
+		{Status: http.StatusBadRequest, Body: ErrorResponse{}},
+		{Status: http.StatusForbidden, Body: ErrorResponse{}},
+		{Status: http.StatusInternalServerError, Body: ErrorResponse{}},
+	},
+}
+
+// SetMode handles PUT /fake-sender
+func (h *Handler) SetMode(c *gin.Context) {
+	var req SetModeRequest
+
+	// Bind and validate request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Error:   "Invalid request: " + err.Error(),
+			Code:    apperr.CodeInvalidRequest,
+		})
+		return
+	}
+
+	mode, err := h.fakeSenderService.Set(c.Request.Context(), *req.Enabled, req.Reason, c.GetHeader(userIDHeader))
+	if err != nil {
+		_ = c.Error(err).SetMeta("Failed to switch the fake sender")
+		return
+	}
+
+	text := "Messages are sent through the real providers"
+	if mode.Enabled {
+		text = "Messages are sent through the fake sender"
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Message: text,
+		Data:    ToModeResponse(mode),
+	})
+}
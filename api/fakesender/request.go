@@ -0,0 +1,7 @@
+package fakesender
+
+// SetModeRequest represents the request to switch between the fake sender and the real providers
+type SetModeRequest struct {
+	Enabled *bool  `json:"enabled" binding:"required"`
+	Reason  string `json:"reason" binding:"required,max=500"`
+}
@@ -0,0 +1,46 @@
+package fakesender
+
+import (
+	"qubit/pkg/jsonfmt"
+	"qubit/service/fakesender"
+)
+
+// ModeResponse represents the fake sender mode in API responses
+// Banner is only set while messages go through the simulated sender
+type ModeResponse struct {
+	Enabled   bool          `json:"enabled"`
+	Locked    bool          `json:"locked"`
+	Reason    string        `json:"reason,omitempty"`
+	ChangedBy string        `json:"changedBy,omitempty"`
+	Since     *jsonfmt.Time `json:"since"`
+	Banner    string        `json:"banner,omitempty"`
+}
+
+// SuccessResponse represents a generic success response
+type SuccessResponse struct {
+	Success bool        `json:"success"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+// ErrorResponse represents an error response
+type ErrorResponse struct {
+	Success bool   `json:"success"`
+	Error   string `json:"error"`
+	Code    string `json:"code"`
+}
+
+// ToModeResponse converts a fake sender mode to ModeResponse
+func ToModeResponse(mode fakesender.Mode) ModeResponse {
+	resp := ModeResponse{
+		Enabled:   mode.Enabled,
+		Locked:    mode.Locked,
+		Reason:    mode.Reason,
+		ChangedBy: mode.ChangedBy,
+		Since:     jsonfmt.NewTimePtr(mode.Since),
+	}
+	if mode.Enabled {
+		resp.Banner = fakesender.Banner
+	}
+	return resp
+}
@@ -0,0 +1,104 @@
+package health
+
+import (
+	"errors"
+	"net/http"
+
+	"qubit/pkg/apperr"
+	"qubit/pkg/openapi"
+	"qubit/service/canary"
+	"qubit/service/health"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler handles liveness and readiness probes
+type Handler struct {
+	healthService *health.Service
+	instanceID    string
+}
+
+// NewHandler creates a new health handler
+func NewHandler(healthService *health.Service, instanceID string) *Handler {
+	return &Handler{
+		healthService: healthService,
+		instanceID:    instanceID,
+	}
+}
+
+// LiveOperation documents Live in the OpenAPI spec
+var LiveOperation = openapi.Operation{
+	Summary: "Liveness probe",
+	Description: "Returns 503 once the running scheduler loop missed its next run or hangs in a tick by more than SCHEDULER_STALL_TIMEOUT, so the instance is restarted\n" +
+		"Checks no dependency; a stopped scheduler or a standby instance is alive",
+	Tags: []string{"Health"},
+	Responses: []openapi.Response{
+		{Status: http.StatusOK, Body: LiveResponse{}},
+		{Status: http.StatusServiceUnavailable, Body: LiveResponse{}},
+	},
+}
+
+// Live handles GET /health/live
+func (h *Handler) Live(c *gin.Context) {
+	liveness := h.healthService.Liveness()
+
+	status := http.StatusOK
+	if !liveness.Alive {
+		status = http.StatusServiceUnavailable
+	}
+
+	c.JSON(status, ToLiveResponse(liveness))
+}
+
+// ReadyOperation documents Ready in the OpenAPI spec
+var ReadyOperation = openapi.Operation{
+	Summary: "Readiness probe",
+	Description: "Pings the database and, when enabled, the webhook providers, and reports the scheduler state\n" +
+		"The scheduler never fails readiness, a stalled scheduler only fails liveness\n" +
+		"Returns 503 with the failed checks when a dependency is down",
+	Tags: []string{"Health"},
+	Responses: []openapi.Response{
+		{Status: http.StatusOK, Body: ReadyResponse{}},
+		{Status: http.StatusServiceUnavailable, Body: ReadyResponse{}},
+	},
+}
+
+// Ready handles GET /health/ready
+func (h *Handler) Ready(c *gin.Context) {
+	report := h.healthService.Readiness(c.Request.Context())
+
+	status := http.StatusOK
+	if !report.Ready {
+		status = http.StatusServiceUnavailable
+	}
+
+	c.JSON(status, ToReadyResponse(report, h.instanceID))
+}
+
+// RunCanaryOperation documents RunCanary in the OpenAPI spec
+var RunCanaryOperation = openapi.Operation{
+	Summary: "Run the canary",
+	Description: "Sends a canary message to the configured test number through the full pipeline and waits for its outcome\n" +
+		"The outcome is also reported by /health/ready",
+	Tags: []string{"Diagnostics"},
+	Responses: []openapi.Response{
+		{Status: http.StatusOK, Body: CanaryResponse{}},
+		{Status: http.StatusForbidden, Body: gin.H{}},
+		{Status: http.StatusNotFound, Body: gin.H{}},
+	},
+}
+
+// RunCanary handles POST /diagnostics/canary
+func (h *Handler) RunCanary(c *gin.Context) {
+	result, err := h.healthService.RunCanary(c.Request.Context())
+	if errors.Is(err, canary.ErrDisabled) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   "Canary is not configured, set CANARY_PHONE_NUMBER",
+			"code":    apperr.Code(err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, ToCanaryResponse(result))
+}
@@ -0,0 +1,166 @@
+package health
+
+import (
+	"qubit/pkg/buildinfo"
+	"qubit/pkg/jsonfmt"
+	"qubit/service/canary"
+	"qubit/service/health"
+)
+
+// Liveness statuses
+const (
+	statusAlive   = "alive"
+	statusStalled = "stalled"
+)
+
+// Readiness statuses
+const (
+	statusReady    = "ready"
+	statusDegraded = "degraded"
+)
+
+// Check statuses
+const (
+	checkUp   = "up"
+	checkDown = "down"
+)
+
+// Canary statuses
+const (
+	canaryPassed = "passed"
+	canaryFailed = "failed"
+)
+
+// LiveResponse represents the liveness of the process
+type LiveResponse struct {
+	Status    string                `json:"status"`
+	Version   string                `json:"version"`
+	Scheduler LiveSchedulerResponse `json:"scheduler"`
+}
+
+// LiveSchedulerResponse represents the heartbeat of the scheduler loop
+type LiveSchedulerResponse struct {
+	Running       bool          `json:"running"`
+	LastHeartbeat *jsonfmt.Time `json:"lastHeartbeat"`
+	// HeartbeatAgeMs is omitted before the scheduler first started
+	HeartbeatAgeMs *int64 `json:"heartbeatAgeMs,omitempty"`
+	Stalled        bool   `json:"stalled"`
+}
+
+// ReadyResponse represents the readiness of the instance with the outcome of every dependency check
+type ReadyResponse struct {
+	Status    string            `json:"status"`
+	Instance  string            `json:"instance"`
+	Checks    []CheckResponse   `json:"checks"`
+	Scheduler SchedulerResponse `json:"scheduler"`
+	// Canary is omitted when the canary is disabled or did not run yet
+	Canary *CanaryResponse `json:"canary,omitempty"`
+}
+
+// CheckResponse represents the outcome of a single dependency check
+type CheckResponse struct {
+	Name      string  `json:"name"`
+	Status    string  `json:"status"`
+	LatencyMs int64   `json:"latencyMs"`
+	Error     *string `json:"error,omitempty"`
+}
+
+// SchedulerResponse represents the scheduler state of the instance
+type SchedulerResponse struct {
+	Running  bool          `json:"running"`
+	Schedule string        `json:"schedule"`
+	NextRun  *jsonfmt.Time `json:"nextRun"`
+	Standby  bool          `json:"standby"`
+	// Leader is omitted when leader election is disabled
+	Leader *bool `json:"leader,omitempty"`
+}
+
+// CanaryResponse represents the outcome of a canary run
+type CanaryResponse struct {
+	Status        string       `json:"status"`
+	Trigger       string       `json:"trigger"`
+	MessageID     *int64       `json:"messageId"`
+	MessageStatus string       `json:"messageStatus,omitempty"`
+	StartedAt     jsonfmt.Time `json:"startedAt"`
+	DurationMs    int64        `json:"durationMs"`
+	Error         *string      `json:"error,omitempty"`
+}
+
+// ToLiveResponse converts a liveness report to LiveResponse
+func ToLiveResponse(liveness health.Liveness) LiveResponse {
+	resp := LiveResponse{
+		Status:  statusAlive,
+		Version: buildinfo.Version,
+		Scheduler: LiveSchedulerResponse{
+			Running:       liveness.Scheduler.Running,
+			LastHeartbeat: jsonfmt.NewTimePtr(liveness.Scheduler.Heartbeat),
+			Stalled:       liveness.Stalled,
+		},
+	}
+	if liveness.Scheduler.Heartbeat != nil {
+		ageMs := liveness.HeartbeatAge.Milliseconds()
+		resp.Scheduler.HeartbeatAgeMs = &ageMs
+	}
+	if !liveness.Alive {
+		resp.Status = statusStalled
+	}
+
+	return resp
+}
+
+// ToCanaryResponse converts a canary result to CanaryResponse
+func ToCanaryResponse(result *canary.Result) *CanaryResponse {
+	status := canaryPassed
+	if !result.Passed {
+		status = canaryFailed
+	}
+
+	return &CanaryResponse{
+		Status:        status,
+		Trigger:       result.Trigger,
+		MessageID:     result.MessageID,
+		MessageStatus: string(result.Status),
+		StartedAt:     jsonfmt.NewTime(result.StartedAt),
+		DurationMs:    result.Duration.Milliseconds(),
+		Error:         result.Error,
+	}
+}
+
+// ToReadyResponse converts a readiness report to ReadyResponse
+func ToReadyResponse(report health.Report, instance string) ReadyResponse {
+	resp := ReadyResponse{
+		Status:   statusReady,
+		Instance: instance,
+		Checks:   make([]CheckResponse, 0, len(report.Checks)),
+		Scheduler: SchedulerResponse{
+			Running:  report.Scheduler.Running,
+			Schedule: report.Scheduler.Schedule,
+			NextRun:  jsonfmt.NewTimePtr(report.Scheduler.NextRun),
+			Standby:  report.Scheduler.Standby,
+		},
+	}
+	if leadership := report.Scheduler.Leadership; leadership != nil {
+		resp.Scheduler.Leader = &leadership.Leader
+	}
+	if !report.Ready {
+		resp.Status = statusDegraded
+	}
+	if report.Canary != nil {
+		resp.Canary = ToCanaryResponse(report.Canary)
+	}
+
+	for _, check := range report.Checks {
+		status := checkUp
+		if !check.Healthy {
+			status = checkDown
+		}
+		resp.Checks = append(resp.Checks, CheckResponse{
+			Name:      check.Name,
+			Status:    status,
+			LatencyMs: check.Latency.Milliseconds(),
+			Error:     check.Error,
+		})
+	}
+
+	return resp
+}
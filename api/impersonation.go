@@ -0,0 +1,55 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"qubit/pkg/apperr"
+	"qubit/service/apikey"
+	"qubit/service/tenant"
+)
+
+// ActAsTenantHeader lets an admin key make a request as the given tenant
+const ActAsTenantHeader = "X-Act-As-Tenant"
+
+// ActAsTenant serves requests carrying ActAsTenantHeader with the key of the tenant instead of the admin key
+// Every such request is recorded in the impersonation audit trail before it is handled, and refused if
+// it cannot be; route scope checks then apply to the tenant key, so admin endpoints are out of reach, and
+// message endpoints scope to the tenant of the key, so only the tenant's messages are visible
+func ActAsTenant(tenantService *tenant.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value := c.GetHeader(ActAsTenantHeader)
+		if value == "" {
+			c.Next()
+			return
+		}
+
+		admin, ok := requestKey(c)
+		if !ok {
+			abortWithError(c, http.StatusUnauthorized, apperr.CodeUnauthorized, "The "+APIKeyHeader+" header is required to use "+ActAsTenantHeader)
+			return
+		}
+
+		tenantID, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || tenantID <= 0 {
+			abortWithError(c, http.StatusBadRequest, apperr.CodeInvalidRequest, "The "+ActAsTenantHeader+" header must be a positive tenant id")
+			return
+		}
+
+		imp, key, err := tenantService.StartImpersonation(c.Request.Context(), admin, tenantID, c.Request.Method, c.Request.URL.RequestURI(), c.ClientIP())
+		if err != nil {
+			respondError(c, "Failed to act as tenant "+value, err)
+			return
+		}
+
+		c.Set(apiKeyContextKey, key)
+		c.Request = c.Request.WithContext(apikey.NewContext(c.Request.Context(), key))
+
+		c.Next()
+
+		tenantService.FinishImpersonation(context.WithoutCancel(c.Request.Context()), imp, c.Writer.Status())
+	}
+}
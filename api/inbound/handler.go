@@ -0,0 +1,150 @@
+package inbound
+
+import (
+	"net/http"
+
+	"qubit/pkg/apperr"
+	"qubit/pkg/jsonstream"
+	"qubit/pkg/openapi"
+	"qubit/pkg/pii"
+	"qubit/service/message"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler handles inbound reply HTTP requests
+type Handler struct {
+	messageService *message.Service
+	listMaxItems   int
+}
+
+// NewHandler creates a new inbound handler
+// listMaxItems caps the listing unless it is requested as a stream, 0 leaves it unbounded
+func NewHandler(messageService *message.Service, listMaxItems int) *Handler {
+	return &Handler{
+		messageService: messageService,
+		listMaxItems:   listMaxItems,
+	}
+}
+
+// ReceiveReplyOperation documents ReceiveReply in the OpenAPI spec
+var ReceiveReplyOperation = openapi.Operation{
+	Summary:     "Receive an inbound reply",
+	Description: "Stores an inbound reply and correlates it to the latest outbound message sent to the number",
+	Tags:        []string{"Inbound"},
+	Body:        ReceiveReplyRequest{},
+	Responses: []openapi.Response{
+		{Status: http.StatusCreated, Body: SuccessResponse{}},
+		{Status: http.StatusBadRequest, Body: ErrorResponse{}},
+		{Status: http.StatusInternalServerError, Body: ErrorResponse{}},
+	},
+}
+
+// ReceiveReply handles POST /inbound
+func (h *Handler) ReceiveReply(c *gin.Context) {
+	var req ReceiveReplyRequest
+
+	// Bind and validate request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Error:   "Invalid request: " + err.Error(),
+			Code:    apperr.CodeInvalidRequest,
+		})
+		return
+	}
+
+	msg, err := h.messageService.ReceiveReply(c.Request.Context(), req.PhoneNumber, req.Content)
+	if err != nil {
+		respondError(c, "Failed to receive reply", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, SuccessResponse{
+		Success: true,
+		Message: "Reply received successfully",
+		Data:    ToInboundMessageResponse(msg),
+	})
+}
+
+// GetInboundMessagesOperation documents GetInboundMessages in the OpenAPI spec
+var GetInboundMessagesOperation = openapi.Operation{
+	Summary:     "Get all inbound messages",
+	Description: "Returns a list of inbound messages with the outbound message each one replies to",
+	Tags:        []string{"Inbound"},
+	Query:       ListInboundRequest{},
+	Params: []openapi.Param{
+		{Name: "stream", In: openapi.InQuery, Description: "Stream the list with chunked transfer encoding, without the LIST_MAX_ITEMS cap; count and success follow the messages"},
+		{Name: "masked", In: openapi.InQuery, Type: "boolean", Description: "Mask phone numbers and content, always applied to roles not listed in PII_UNMASKED_ROLES"},
+	},
+	Responses: []openapi.Response{
+		{Status: http.StatusOK, Body: InboundMessageListResponse{}},
+		{Status: http.StatusInternalServerError, Body: ErrorResponse{}},
+	},
+}
+
+// GetInboundMessages handles GET /inbound
+func (h *Handler) GetInboundMessages(c *gin.Context) {
+	var req ListInboundRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Error:   "Invalid request: " + err.Error(),
+			Code:    apperr.CodeInvalidRequest,
+		})
+		return
+	}
+
+	if req.Stream {
+		h.streamInboundMessages(c)
+		return
+	}
+
+	messages, err := h.messageService.GetInboundMessages(c.Request.Context(), h.listMaxItems)
+	if err != nil {
+		respondError(c, "Failed to retrieve inbound messages", err)
+		return
+	}
+
+	responses := ToInboundMessageResponseList(messages)
+	if pii.Masked(c.Request.Context()) {
+		for i := range responses {
+			responses[i].mask()
+		}
+	}
+
+	c.JSON(http.StatusOK, InboundMessageListResponse{
+		Success:  true,
+		Count:    len(responses),
+		Messages: responses,
+	})
+}
+
+// streamInboundMessages writes the inbound messages as they are read, never holding the whole list
+// An error after the first message ends the list with success false, the status line is already sent
+func (h *Handler) streamInboundMessages(c *gin.Context) {
+	masked := pii.Masked(c.Request.Context())
+	stream := jsonstream.NewList(c.Writer, "messages")
+
+	err := h.messageService.StreamInboundMessages(c.Request.Context(), func(msg *message.InboundMessage) error {
+		response := ToInboundMessageResponse(msg)
+		if masked {
+			response.mask()
+		}
+		return stream.Write(response)
+	})
+	if err != nil {
+		respondError(c, "Failed to retrieve inbound messages", err)
+		if stream.Started() {
+			_ = stream.Fail("Failed to retrieve inbound messages", err)
+		}
+		return
+	}
+
+	_ = stream.Close()
+}
+
+// respondError records err for the error middleware, which answers it with the status and code of its kind
+func respondError(c *gin.Context, prefix string, err error) {
+	_ = c.Error(err).SetMeta(prefix)
+}
@@ -0,0 +1,12 @@
+package inbound
+
+// ReceiveReplyRequest represents an inbound reply delivered by the provider
+type ReceiveReplyRequest struct {
+	PhoneNumber string `json:"phoneNumber" binding:"required"`
+	Content     string `json:"content" binding:"required,max=500"`
+}
+
+// ListInboundRequest represents the query parameters of the inbound message listing
+type ListInboundRequest struct {
+	Stream bool `form:"stream"`
+}
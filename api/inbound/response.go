@@ -0,0 +1,91 @@
+package inbound
+
+import (
+	"qubit/pkg/jsonfmt"
+	"qubit/pkg/pii"
+	"qubit/service/message"
+)
+
+// InboundMessageResponse represents an inbound message in API responses
+type InboundMessageResponse struct {
+	ID          int64            `json:"id"`
+	PhoneNumber string           `json:"phoneNumber"`
+	Content     string           `json:"content"`
+	ReceivedAt  jsonfmt.Time     `json:"receivedAt"`
+	ReplyTo     *ReplyToResponse `json:"replyTo"`
+}
+
+// ReplyToResponse represents the outbound message an inbound message replies to
+type ReplyToResponse struct {
+	ID          int64         `json:"id"`
+	Content     string        `json:"content"`
+	MessageID   *string       `json:"messageId"`
+	ProcessedAt *jsonfmt.Time `json:"processedAt"`
+}
+
+// SuccessResponse represents a generic success response
+type SuccessResponse struct {
+	Success bool        `json:"success"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+// ErrorResponse represents an error response
+type ErrorResponse struct {
+	Success bool   `json:"success"`
+	Error   string `json:"error"`
+	Code    string `json:"code"`
+}
+
+// InboundMessageListResponse represents a list of inbound messages
+type InboundMessageListResponse struct {
+	Success  bool                     `json:"success"`
+	Count    int                      `json:"count"`
+	Messages []InboundMessageResponse `json:"messages"`
+}
+
+// ToInboundMessageResponse converts a domain message.InboundMessage to InboundMessageResponse
+func ToInboundMessageResponse(msg *message.InboundMessage) InboundMessageResponse {
+	resp := InboundMessageResponse{
+		ID:          msg.ID,
+		PhoneNumber: msg.PhoneNumber,
+		Content:     msg.Content,
+		ReceivedAt:  jsonfmt.NewTime(msg.ReceivedAt),
+	}
+
+	if msg.ReplyTo != nil {
+		resp.ReplyTo = &ReplyToResponse{
+			ID:          msg.ReplyTo.ID,
+			Content:     msg.ReplyTo.Content,
+			MessageID:   msg.ReplyTo.MessageID,
+			ProcessedAt: jsonfmt.NewTimePtr(msg.ReplyTo.ProcessedAt),
+		}
+	}
+
+	return resp
+}
+
+// ToInboundMessageResponseList converts a slice of domain inbound messages to InboundMessageResponse slice
+func ToInboundMessageResponseList(messages []*message.InboundMessage) []InboundMessageResponse {
+	if messages == nil {
+		return []InboundMessageResponse{}
+	}
+
+	responses := make([]InboundMessageResponse, 0, len(messages))
+	for _, msg := range messages {
+		responses = append(responses, ToInboundMessageResponse(msg))
+	}
+
+	return responses
+}
+
+// mask hides the phone number and content of the inbound message and the content of the message it replies to
+func (r *InboundMessageResponse) mask() {
+	r.PhoneNumber = pii.MaskPhone(r.PhoneNumber)
+	r.Content = pii.MaskContent(r.Content)
+	if r.ReplyTo != nil {
+		replyTo := *r.ReplyTo
+		replyTo.Content = pii.MaskContent(replyTo.Content)
+		r.ReplyTo = &replyTo
+	}
+}
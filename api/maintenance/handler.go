@@ -0,0 +1,87 @@
+package maintenance
+
+import (
+	"net/http"
+
+	"qubit/pkg/apperr"
+	"qubit/pkg/openapi"
+	"qubit/service/maintenance"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler handles maintenance mode HTTP requests
+type Handler struct {
+	maintenanceService *maintenance.Service
+}
+
+// NewHandler creates a new maintenance handler
+func NewHandler(maintenanceService *maintenance.Service) *Handler {
+	return &Handler{
+		maintenanceService: maintenanceService,
+	}
+}
+
+// GetModeOperation documents GetMode in the OpenAPI spec
+var GetModeOperation = openapi.Operation{
+	Summary:     "Get maintenance mode",
+	Description: "Returns whether the API is read-only and the scheduler paused",
+	Tags:        []string{"Maintenance"},
+	Responses: []openapi.Response{
+		{Status: http.StatusOK, Body: SuccessResponse{}},
+	},
+}
+
+// GetMode handles GET /maintenance
+func (h *Handler) GetMode(c *gin.Context) {
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Message: "Maintenance mode retrieved successfully",
+		Data:    ToModeResponse(h.maintenanceService.Mode()),
+	})
+}
+
+// SetModeOperation documents SetMode in the OpenAPI spec
+var SetModeOperation = openapi.Operation{
+	Summary:     "Toggle maintenance mode",
+	Description: "Puts every instance in read-only mode, rejecting mutations with 503 and pausing the scheduler, or back to normal operation",
+	Tags:        []string{"Maintenance"},
+	Body:        SetModeRequest{},
+	Responses: []openapi.Response{
+		{Status: http.StatusOK, Body: SuccessResponse{}},
+		{Status: http.StatusBadRequest, Body: ErrorResponse{}},
+		{Status: http.StatusInternalServerError, Body: ErrorResponse{}},
+	},
+}
+
+// SetMode handles PUT /maintenance
+func (h *Handler) SetMode(c *gin.Context) {
+	var req SetModeRequest
+
+	// Bind and validate request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Error:   "Invalid request: " + err.Error(),
+			Code:    apperr.CodeInvalidRequest,
+		})
+		return
+	}
+
+	mode, err := h.maintenanceService.Set(c.Request.Context(), *req.Enabled, req.Message)
+	if err != nil {
+		_ = c.Error(err).SetMeta("Failed to set maintenance mode")
+		return
+	}
+
+	text := "Maintenance mode disabled"
+	if mode.Enabled {
+		text = "Maintenance mode enabled"
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Message: text,
+		Data:    ToModeResponse(mode),
+	})
+}
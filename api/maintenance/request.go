@@ -0,0 +1,7 @@
+package maintenance
+
+// SetModeRequest represents the request to toggle maintenance mode
+type SetModeRequest struct {
+	Enabled *bool  `json:"enabled" binding:"required"`
+	Message string `json:"message" binding:"omitempty,max=500"`
+}
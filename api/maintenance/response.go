@@ -0,0 +1,36 @@
+package maintenance
+
+import (
+	"qubit/pkg/jsonfmt"
+	"qubit/service/maintenance"
+)
+
+// ModeResponse represents the maintenance mode in API responses
+type ModeResponse struct {
+	Enabled bool          `json:"enabled"`
+	Message string        `json:"message,omitempty"`
+	Since   *jsonfmt.Time `json:"since"`
+}
+
+// SuccessResponse represents a generic success response
+type SuccessResponse struct {
+	Success bool        `json:"success"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+// ErrorResponse represents an error response
+type ErrorResponse struct {
+	Success bool   `json:"success"`
+	Error   string `json:"error"`
+	Code    string `json:"code"`
+}
+
+// ToModeResponse converts a maintenance mode to ModeResponse
+func ToModeResponse(mode maintenance.Mode) ModeResponse {
+	return ModeResponse{
+		Enabled: mode.Enabled,
+		Message: mode.Message,
+		Since:   jsonfmt.NewTimePtr(mode.Since),
+	}
+}
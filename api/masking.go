@@ -0,0 +1,24 @@
+package api
+
+import (
+	"slices"
+
+	"github.com/gin-gonic/gin"
+
+	"qubit/pkg/pii"
+)
+
+// MaskPII marks requests whose list responses mask phone numbers and content
+// A caller asks for it with ?masked=true; with unmaskedRoles set, every caller whose role is not listed gets it
+func MaskPII(unmaskedRoles []string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		masked := c.Query("masked") == "true" ||
+			len(unmaskedRoles) > 0 && !slices.Contains(unmaskedRoles, c.GetHeader(UserRoleHeader))
+
+		if masked {
+			c.Request = c.Request.WithContext(pii.WithMasked(c.Request.Context()))
+		}
+
+		c.Next()
+	}
+}
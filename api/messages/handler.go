@@ -1,45 +1,167 @@
 package messages
 
 import (
+	"errors"
+	"io"
+	"log"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
+	"qubit/pkg/apperr"
+	"qubit/pkg/jsonfmt"
+	"qubit/pkg/jsonstream"
+	"qubit/pkg/openapi"
+	"qubit/pkg/pii"
+	"qubit/pkg/ratelimit"
+	"qubit/service/apikey"
 	"qubit/service/message"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
+// progressHeartbeat is how often an idle progress or lifecycle stream sends a comment line
+const progressHeartbeat = 15 * time.Second
+
+// userRoleHeader carries the caller role, set by the upstream gateway
+const userRoleHeader = "X-User-Role"
+
+// adminRole is the role allowed to read raw provider exchanges
+const adminRole = "admin"
+
+// allStatuses is the status filter value listing messages in every status
+const allStatuses = "all"
+
+// metadataParamPrefix prefixes the listing parameters filtering on a metadata key, e.g. metadata.campaignId
+const metadataParamPrefix = "metadata."
+
+// maskedDescription documents the masked parameter of the listings masking phone numbers and content
+const maskedDescription = "Mask phone numbers and content, always applied to roles not listed in PII_UNMASKED_ROLES"
+
+// maxSchedulerInterval matches the 1440 minute limit of intervalMinutes
+const maxSchedulerInterval = 24 * time.Hour
+
 // Handler handles message-related HTTP requests
 type Handler struct {
-	messageService *message.Service
+	messageService    *message.Service
+	processingHeaders bool
+	listMaxItems      int
 }
 
 // NewHandler creates a new message handler
-func NewHandler(messageService *message.Service) *Handler {
+// processingHeaders adds the queue depth, estimated dispatch and remaining rate limit to created messages
+// listMaxItems caps the listings not requested as a stream, 0 leaves them unbounded
+func NewHandler(messageService *message.Service, processingHeaders bool, listMaxItems int) *Handler {
 	return &Handler{
-		messageService: messageService,
+		messageService:    messageService,
+		processingHeaders: processingHeaders,
+		listMaxItems:      listMaxItems,
 	}
 }
 
+// GetSentMessagesOperation documents GetSentMessages in the OpenAPI spec
+var GetSentMessagesOperation = openapi.Operation{
+	Summary: "Get all sent messages",
+	Description: "Returns a list of all sent messages, or of the messages matching the filters\n" +
+		"A tenant key only lists the messages of its tenant",
+	Tags:  []string{"Messages"},
+	Query: ListMessagesRequest{},
+	Params: []openapi.Param{
+		{Name: "status", In: openapi.InQuery, Description: "Message status (pending, sending, sent, failed, cancelled, throttled, all)"},
+		{Name: "phoneNumber", In: openapi.InQuery, Description: "Recipient phone number"},
+		{Name: "createdFrom", In: openapi.InQuery, Description: "Created at or after (RFC 3339)"},
+		{Name: "createdTo", In: openapi.InQuery, Description: "Created before (RFC 3339)"},
+		{Name: "processedFrom", In: openapi.InQuery, Description: "Processed at or after (RFC 3339)"},
+		{Name: "processedTo", In: openapi.InQuery, Description: "Processed before (RFC 3339)"},
+		{Name: "search", In: openapi.InQuery, Description: "Case-insensitive text contained in the content"},
+		{Name: "externalRef", In: openapi.InQuery, Description: "External reference as type:id, e.g. order:12345"},
+		{Name: "includeArchived", In: openapi.InQuery, Description: "Also list the sent messages moved to the archive"},
+		{Name: "stream", In: openapi.InQuery, Description: "Stream the list with chunked transfer encoding, without the LIST_MAX_ITEMS cap; count and success follow the messages"},
+		{Name: "metadata.campaignId", In: openapi.InQuery, Description: "Metadata value the messages hold, any key can follow metadata. and several keys must all match"},
+		{Name: "masked", In: openapi.InQuery, Type: "boolean", Description: maskedDescription},
+	},
+	Responses: []openapi.Response{
+		{Status: http.StatusOK, Body: MessageListResponse{}},
+		{Status: http.StatusBadRequest, Body: ErrorResponse{}},
+		{Status: http.StatusInternalServerError, Body: ErrorResponse{}},
+	},
+}
+
 // GetSentMessages handles GET /messages
-// @Summary Get all sent messages
-// @Description Returns a list of all sent messages
-// @Tags Messages
-// @Produce json
-// @Success 200 {object} dto.MessageListResponse
-// @Failure 500 {object} dto.ErrorResponse
-// @Router /messages [get]
 func (h *Handler) GetSentMessages(c *gin.Context) {
-	messages, err := h.messageService.GetSentMessages(c.Request.Context())
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
+	var req ListMessagesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
 			Success: false,
-			Error:   "Failed to retrieve sent messages: " + err.Error(),
+			Error:   "Invalid request: " + err.Error(),
+			Code:    apperr.CodeInvalidRequest,
 		})
 		return
 	}
 
+	filter := message.ListFilter{
+		Status:        message.StatusSent,
+		PhoneNumber:   req.PhoneNumber,
+		CreatedFrom:   req.CreatedFrom,
+		CreatedTo:     req.CreatedTo,
+		ProcessedFrom: req.ProcessedFrom,
+		ProcessedTo:   req.ProcessedTo,
+		Search:        req.Search,
+		Metadata:      metadataFilter(c),
+		TenantID:      tenantScope(c),
+
+		IncludeArchived: req.IncludeArchived,
+	}
+
+	switch req.Status {
+	case "":
+	case allStatuses:
+		filter.Status = ""
+	default:
+		parsed, err := message.ParseStatus(req.Status)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Success: false,
+				Error:   "Invalid request: " + err.Error(),
+				Code:    apperr.CodeInvalidRequest,
+			})
+			return
+		}
+		filter.Status = parsed
+	}
+
+	if req.ExternalRef != "" {
+		ref, err := message.ParseExternalRef(req.ExternalRef)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Success: false,
+				Error:   "Invalid request: " + err.Error(),
+				Code:    apperr.CodeInvalidRequest,
+			})
+			return
+		}
+		filter.ExternalRef = ref
+	}
+
+	if req.Stream {
+		h.streamMessages(c, filter)
+		return
+	}
+
+	filter.Max = h.listMaxItems
+	messages, err := h.messageService.ListMessages(c.Request.Context(), filter)
+	if err != nil {
+		respondError(c, "Failed to retrieve messages", err)
+		return
+	}
+
 	// Convert to response DTOs
 	messageResponses := ToMessageResponseList(messages)
+	if pii.Masked(c.Request.Context()) {
+		maskMessages(messageResponses)
+	}
 
 	c.JSON(http.StatusOK, MessageListResponse{
 		Success:  true,
@@ -48,17 +170,126 @@ func (h *Handler) GetSentMessages(c *gin.Context) {
 	})
 }
 
+// streamMessages writes the messages matching the filter as they are read, never holding the whole list
+// An error after the first message ends the list with success false, the status line is already sent
+func (h *Handler) streamMessages(c *gin.Context, filter message.ListFilter) {
+	masked := pii.Masked(c.Request.Context())
+	stream := jsonstream.NewList(c.Writer, "messages")
+
+	err := h.messageService.StreamMessages(c.Request.Context(), filter, func(msg *message.Message) error {
+		response := ToMessageResponse(msg)
+		if masked {
+			response.mask()
+		}
+		return stream.Write(response)
+	})
+	if err != nil {
+		respondError(c, "Failed to retrieve messages", err)
+		if stream.Started() {
+			_ = stream.Fail("Failed to retrieve messages", err)
+		}
+		return
+	}
+
+	_ = stream.Close()
+}
+
+// GetInFlightOperation documents GetInFlight in the OpenAPI spec
+var GetInFlightOperation = openapi.Operation{
+	Summary:     "Get in-flight messages",
+	Description: "Returns the messages currently claimed for sending across all instances with the instance holding each lease",
+	Tags:        []string{"Diagnostics"},
+	Params: []openapi.Param{
+		{Name: "masked", In: openapi.InQuery, Type: "boolean", Description: maskedDescription},
+	},
+	Responses: []openapi.Response{
+		{Status: http.StatusOK, Body: InFlightListResponse{}},
+		{Status: http.StatusInternalServerError, Body: ErrorResponse{}},
+	},
+}
+
+// GetInFlight handles GET /diagnostics/in-flight
+func (h *Handler) GetInFlight(c *gin.Context) {
+	messages, err := h.messageService.GetInFlightMessages(c.Request.Context())
+	if err != nil {
+		respondError(c, "Failed to retrieve in-flight messages", err)
+		return
+	}
+
+	response := ToInFlightListResponse(messages, time.Now())
+	if pii.Masked(c.Request.Context()) {
+		for i := range response.Messages {
+			response.Messages[i].mask()
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetMessageOperation documents GetMessage in the OpenAPI spec
+var GetMessageOperation = openapi.Operation{
+	Summary: "Get a message",
+	Description: "Returns a single message regardless of its status, looking it up in the archive once it was archived\n" +
+		"A tenant key gets 404 for a message of another tenant",
+	Tags: []string{"Messages"},
+	Params: []openapi.Param{
+		{Name: "id", In: openapi.InPath, Type: "integer", Description: "Message ID"},
+	},
+	Responses: []openapi.Response{
+		{Status: http.StatusOK, Body: SuccessResponse{}},
+		{Status: http.StatusBadRequest, Body: ErrorResponse{}},
+		{Status: http.StatusNotFound, Body: ErrorResponse{}},
+		{Status: http.StatusInternalServerError, Body: ErrorResponse{}},
+	},
+}
+
+// GetMessage handles GET /messages/:id
+func (h *Handler) GetMessage(c *gin.Context) {
+	id, ok := parseMessageID(c)
+	if !ok {
+		return
+	}
+
+	msg, err := h.messageService.GetMessage(c.Request.Context(), id, tenantScope(c))
+	if err != nil {
+		respondError(c, "Failed to retrieve message", err)
+		return
+	}
+
+	// An archived message takes a second lookup, callers investigating old messages are told why it was slower
+	result := "Message retrieved successfully"
+	if msg.Archived {
+		result = "Message retrieved from the archive, archived lookups are slower than live ones"
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Message: result,
+		Data:    ToMessageResponse(msg),
+	})
+}
+
+// CreateMessageOperation documents CreateMessage in the OpenAPI spec
+var CreateMessageOperation = openapi.Operation{
+	Summary: "Create a new message",
+	Description: "Creates a new message to be sent\n" +
+		"An optional uuid makes the client's identifier the message UUID, a UUID already in use is rejected with 409",
+	Tags: []string{"Messages"},
+	Body: CreateMessageRequest{},
+	Responses: []openapi.Response{
+		{Status: http.StatusCreated, Body: SuccessResponse{}, Headers: []openapi.Header{
+			{Name: "X-Queue-Depth", Type: "integer", Description: "Pending messages due now"},
+			{Name: "X-Estimated-Dispatch", Type: "string", Description: "Estimated dispatch time (RFC 3339)"},
+			{Name: "X-RateLimit-Remaining", Type: "integer", Description: "Requests left in the rate limit bucket"},
+		}},
+		{Status: http.StatusBadRequest, Body: ErrorResponse{}},
+		{Status: http.StatusConflict, Body: ErrorResponse{}},
+		{Status: http.StatusTooManyRequests, Body: ErrorResponse{}},
+		{Status: http.StatusInternalServerError, Body: ErrorResponse{}},
+	},
+}
+
 // CreateMessage handles POST /messages
-// @Summary Create a new message
-// @Description Creates a new message to be sent
-// @Tags Messages
-// @Accept json
-// @Produce json
-// @Param message body dto.CreateMessageRequest true "Message data"
-// @Success 201 {object} dto.SuccessResponse
-// @Failure 400 {object} dto.ErrorResponse
-// @Failure 500 {object} dto.ErrorResponse
-// @Router /messages [post]
 func (h *Handler) CreateMessage(c *gin.Context) {
 	var req CreateMessageRequest
 
@@ -67,22 +298,41 @@ func (h *Handler) CreateMessage(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, ErrorResponse{
 			Success: false,
 			Error:   "Invalid request: " + err.Error(),
+			Code:    apperr.CodeInvalidRequest,
 		})
 		return
 	}
 
-	// Create message
-	message, err := h.messageService.CreateMessage(c.Request.Context(), req.PhoneNumber, req.Content)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Success: false,
-			Error:   "Failed to create message: " + err.Error(),
+	// Several recipients expand into one message each, linked by a fan-out ID
+	if len(req.Recipients) > 0 {
+		fanout, err := h.messageService.CreateFanout(c.Request.Context(), req.Recipients, req.Content, createOptions(c, req.MessageFields))
+		if err != nil {
+			respondError(c, "Failed to create messages", err)
+			return
+		}
+
+		h.setProcessingHeaders(c, req.ScheduledAt)
+		c.JSON(http.StatusCreated, SuccessResponse{
+			Success: true,
+			Message: "Messages created successfully",
+			Data:    ToFanoutResponse(fanout),
 		})
 		return
 	}
 
+	// Create message, under the client's UUID when given
+	opts := createOptions(c, req.MessageFields)
+	opts.UUID = req.UUID
+
+	message, err := h.messageService.CreateMessage(c.Request.Context(), req.PhoneNumber, req.Content, opts)
+	if err != nil {
+		respondError(c, "Failed to create message", err)
+		return
+	}
+
 	messageResponse := ToMessageResponse(message)
 
+	h.setProcessingHeaders(c, message.ScheduledAt)
 	c.JSON(http.StatusCreated, SuccessResponse{
 		Success: true,
 		Message: "Message created successfully",
@@ -90,55 +340,860 @@ func (h *Handler) CreateMessage(c *gin.Context) {
 	})
 }
 
+// setProcessingHeaders adds the operational context of a created message when enabled
+// A failed estimate only drops the queue headers, the message was created anyway
+func (h *Handler) setProcessingHeaders(c *gin.Context, scheduledAt *time.Time) {
+	if !h.processingHeaders {
+		return
+	}
+
+	if decision, ok := ratelimit.FromContext(c.Request.Context()); ok {
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(decision.Remaining))
+	}
+
+	estimate, err := h.messageService.EstimateDispatch(c.Request.Context(), scheduledAt)
+	if err != nil {
+		log.Printf("Warning: %v", err)
+		return
+	}
+
+	c.Header("X-Queue-Depth", strconv.FormatInt(estimate.QueueDepth, 10))
+	if estimate.EstimatedDispatch != nil {
+		c.Header("X-Estimated-Dispatch", jsonfmt.NewTime(*estimate.EstimatedDispatch).String())
+	}
+}
+
+// UpsertMessageOperation documents UpsertMessage in the OpenAPI spec
+var UpsertMessageOperation = openapi.Operation{
+	Summary:     "Create or update a message by public UUID",
+	Description: "Idempotently syncs a message definition from an external system of record",
+	Tags:        []string{"Messages"},
+	Body:        UpsertMessageRequest{},
+	Params: []openapi.Param{
+		{Name: "id", In: openapi.InPath, Type: "string", Description: "Message UUID"},
+	},
+	Responses: []openapi.Response{
+		{Status: http.StatusOK, Body: SuccessResponse{}},
+		{Status: http.StatusCreated, Body: SuccessResponse{}},
+		{Status: http.StatusBadRequest, Body: ErrorResponse{}},
+		{Status: http.StatusConflict, Body: ErrorResponse{}},
+		{Status: http.StatusTooManyRequests, Body: ErrorResponse{}},
+		{Status: http.StatusInternalServerError, Body: ErrorResponse{}},
+	},
+}
+
+// UpsertMessage handles PUT /messages/:uuid
+func (h *Handler) UpsertMessage(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Error:   "Invalid request: message uuid must be a valid UUID",
+			Code:    apperr.CodeInvalidRequest,
+		})
+		return
+	}
+
+	var req UpsertMessageRequest
+
+	// Bind and validate request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Error:   "Invalid request: " + err.Error(),
+			Code:    apperr.CodeInvalidRequest,
+		})
+		return
+	}
+
+	if len(req.Recipients) > 0 {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Error:   "Invalid request: recipients are only supported when creating messages",
+			Code:    apperr.CodeInvalidRequest,
+		})
+		return
+	}
+
+	msg, created, err := h.messageService.UpsertMessage(c.Request.Context(), id.String(), req.PhoneNumber, req.Content, createOptions(c, req.MessageFields))
+	if err != nil {
+		respondError(c, "Failed to sync message", err)
+		return
+	}
+
+	status, text := http.StatusOK, "Message updated successfully"
+	if created {
+		status, text = http.StatusCreated, "Message created successfully"
+	}
+
+	c.JSON(status, SuccessResponse{
+		Success: true,
+		Message: text,
+		Data:    ToMessageResponse(msg),
+	})
+}
+
+// CancelMessageOperation documents CancelMessage in the OpenAPI spec
+var CancelMessageOperation = openapi.Operation{
+	Summary: "Cancel a pending message",
+	Description: "Cancels a message before it is sent; fails with 409 once delivery happened\n" +
+		"A tenant key gets 404 for a message of another tenant",
+	Tags: []string{"Messages"},
+	Params: []openapi.Param{
+		{Name: "id", In: openapi.InPath, Type: "integer", Description: "Message ID"},
+	},
+	Responses: []openapi.Response{
+		{Status: http.StatusOK, Body: SuccessResponse{}},
+		{Status: http.StatusBadRequest, Body: ErrorResponse{}},
+		{Status: http.StatusNotFound, Body: ErrorResponse{}},
+		{Status: http.StatusConflict, Body: ErrorResponse{}},
+		{Status: http.StatusInternalServerError, Body: ErrorResponse{}},
+	},
+}
+
+// CancelMessage handles DELETE /messages/:id
+func (h *Handler) CancelMessage(c *gin.Context) {
+	id, ok := parseMessageID(c)
+	if !ok {
+		return
+	}
+
+	msg, err := h.messageService.CancelMessage(c.Request.Context(), id, tenantScope(c))
+	if err != nil {
+		respondError(c, "Failed to cancel message", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Message: "Message cancelled successfully",
+		Data:    ToMessageResponse(msg),
+	})
+}
+
+// GetStreamOperation documents GetStream in the OpenAPI spec
+var GetStreamOperation = openapi.Operation{
+	Summary:     "Stream message lifecycle events",
+	Description: "Streams the message.created, message.sent and message.failed events of the messages handled by this instance as server-sent events until the client disconnects",
+	Tags:        []string{"Messages"},
+	Params: []openapi.Param{
+		{Name: "types", In: openapi.InQuery, Description: "Comma separated event types to stream (default: all)"},
+	},
+	Responses: []openapi.Response{
+		{Status: http.StatusOK, Body: message.Event{}, MediaType: "text/event-stream"},
+		{Status: http.StatusBadRequest, Body: ErrorResponse{}},
+	},
+}
+
+// GetStream handles GET /messages/stream
+func (h *Handler) GetStream(c *gin.Context) {
+	types := map[string]bool{}
+	if value := c.Query("types"); value != "" {
+		for _, eventType := range strings.Split(value, ",") {
+			eventType = strings.TrimSpace(eventType)
+			switch eventType {
+			case message.EventMessageCreated, message.EventMessageSent, message.EventMessageFailed:
+				types[eventType] = true
+			default:
+				c.JSON(http.StatusBadRequest, ErrorResponse{
+					Success: false,
+					Error:   "Invalid request: unknown event type " + eventType,
+					Code:    apperr.CodeInvalidRequest,
+				})
+				return
+			}
+		}
+	}
+
+	events, unsubscribe := h.messageService.SubscribeLifecycle()
+	defer unsubscribe()
+
+	heartbeat := time.NewTicker(progressHeartbeat)
+	defer heartbeat.Stop()
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case event := <-events:
+			if len(types) == 0 || types[event.Type] {
+				c.SSEvent(event.Type, event)
+			}
+			return true
+		case <-heartbeat.C:
+			// Keeps idle connections open through proxies
+			_, _ = io.WriteString(w, ": heartbeat\n\n")
+			return true
+		case <-c.Request.Context().Done():
+			return false
+		}
+	})
+}
+
+// GetProgressOperation documents GetProgress in the OpenAPI spec
+var GetProgressOperation = openapi.Operation{
+	Summary:     "Stream batch progress",
+	Description: "Streams the per-message progress of the batches run by this instance as server-sent events until the client disconnects",
+	Tags:        []string{"Scheduler"},
+	Responses: []openapi.Response{
+		{Status: http.StatusOK, Body: ProgressEventResponse{}, MediaType: "text/event-stream"},
+	},
+}
+
+// GetProgress handles GET /scheduler/events
+func (h *Handler) GetProgress(c *gin.Context) {
+	events, unsubscribe := h.messageService.SubscribeProgress()
+	defer unsubscribe()
+
+	heartbeat := time.NewTicker(progressHeartbeat)
+	defer heartbeat.Stop()
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case event := <-events:
+			c.SSEvent(string(event.Type), ToProgressEventResponse(event))
+			return true
+		case <-heartbeat.C:
+			// Keeps idle connections open through proxies
+			_, _ = io.WriteString(w, ": heartbeat\n\n")
+			return true
+		case <-c.Request.Context().Done():
+			return false
+		}
+	})
+}
+
+// StartOperation documents Start in the OpenAPI spec
+var StartOperation = openapi.Operation{
+	Summary:      "Start the message scheduler",
+	Description:  "Starts the automatic message sending scheduler, optionally with a custom interval and batch size",
+	Tags:         []string{"Scheduler"},
+	Body:         StartSchedulerRequest{},
+	BodyOptional: true,
+	Responses: []openapi.Response{
+		{Status: http.StatusOK, Body: SuccessResponse{}},
+		{Status: http.StatusBadRequest, Body: ErrorResponse{}},
+	},
+}
+
 // Start handles POST /scheduler/start
-// @Summary Start the message scheduler
-// @Description Starts the automatic message sending scheduler
-// @Tags Scheduler
-// @Produce json
-// @Success 200 {object} SuccessResponse
-// @Failure 400 {object} ErrorResponse
-// @Router /scheduler/start [post]
 func (h *Handler) Start(c *gin.Context) {
-	// Get configuration from context or use defaults
-	// For now, we'll hardcode reasonable defaults that match the config
-	intervalMinutes := 2
-	batchSize := 2
+	var req StartSchedulerRequest
 
-	err := h.messageService.StartScheduler(intervalMinutes, batchSize)
-	if err != nil {
+	// The body is optional, an empty one keeps the configured defaults
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
 		c.JSON(http.StatusBadRequest, ErrorResponse{
 			Success: false,
-			Error:   err.Error(),
+			Error:   "Invalid request: " + err.Error(),
+			Code:    apperr.CodeInvalidRequest,
 		})
 		return
 	}
 
+	settings := h.messageService.DefaultSchedulerSettings()
+	if req.IntervalMinutes != nil {
+		settings.Interval = time.Duration(*req.IntervalMinutes) * time.Minute
+	}
+	if req.Interval != nil {
+		interval, err := time.ParseDuration(*req.Interval)
+		if err != nil || interval > maxSchedulerInterval {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Success: false,
+				Error:   "Invalid request: interval must be a duration such as 30s or 1m30s, at most " + maxSchedulerInterval.String(),
+				Code:    apperr.CodeInvalidRequest,
+			})
+			return
+		}
+		settings.Interval = interval
+	}
+	if req.BatchSize != nil {
+		settings.BatchSize = *req.BatchSize
+	}
+	if req.Cron != nil {
+		settings.Cron = *req.Cron
+	}
+
+	err := h.messageService.StartScheduler(c.Request.Context(), settings)
+	if err != nil {
+		respondError(c, "Failed to start scheduler", err)
+		return
+	}
+
 	c.JSON(http.StatusOK, SuccessResponse{
 		Success: true,
 		Message: "Scheduler started successfully",
+		Data:    ToSchedulerSettingsResponse(h.messageService.SchedulerSettings()),
 	})
 }
 
+// GetStatusOperation documents GetStatus in the OpenAPI spec
+var GetStatusOperation = openapi.Operation{
+	Summary:     "Get the scheduler status",
+	Description: "Returns whether the scheduler of this instance runs, its interval or parsed cron schedule and its next run",
+	Tags:        []string{"Scheduler"},
+	Responses: []openapi.Response{
+		{Status: http.StatusOK, Body: SuccessResponse{}},
+	},
+}
+
+// GetStatus handles GET /scheduler/status
+func (h *Handler) GetStatus(c *gin.Context) {
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Message: "Scheduler status retrieved successfully",
+		Data:    ToSchedulerStatusResponse(h.messageService.SchedulerStatus()),
+	})
+}
+
+// GetRunsOperation documents GetRuns in the OpenAPI spec
+var GetRunsOperation = openapi.Operation{
+	Summary: "Get scheduler runs",
+	Description: "Returns the reports of past scheduler runs of every instance, newest first, with the messages claimed, attempted, sent and failed and the failed attempts per failure category\n" +
+		"Pass nextBefore of a page as before to fetch the following one",
+	Tags:  []string{"Scheduler"},
+	Query: ListRunsRequest{},
+	Params: []openapi.Param{
+		{Name: "before", In: openapi.InQuery, Description: "Only runs older than the run with this ID"},
+		{Name: "limit", In: openapi.InQuery, Description: "Maximum number of runs (default 50, max 500)"},
+	},
+	Responses: []openapi.Response{
+		{Status: http.StatusOK, Body: RunListResponse{}},
+		{Status: http.StatusBadRequest, Body: ErrorResponse{}},
+		{Status: http.StatusInternalServerError, Body: ErrorResponse{}},
+	},
+}
+
+// GetRuns handles GET /scheduler/runs
+func (h *Handler) GetRuns(c *gin.Context) {
+	var req ListRunsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Error:   "Invalid request: " + err.Error(),
+			Code:    apperr.CodeInvalidRequest,
+		})
+		return
+	}
+
+	page, err := h.messageService.ListRuns(c.Request.Context(), req.Before, req.Limit)
+	if err != nil {
+		respondError(c, "Failed to retrieve scheduler runs", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ToRunListResponse(page))
+}
+
+// ResetOperation documents Reset in the OpenAPI spec
+var ResetOperation = openapi.Operation{
+	Summary:     "Reset the scheduler to configured defaults",
+	Description: "Drops persisted runtime overrides and restarts the scheduler with the configured interval and batch size",
+	Tags:        []string{"Scheduler"},
+	Responses: []openapi.Response{
+		{Status: http.StatusOK, Body: SuccessResponse{}},
+		{Status: http.StatusInternalServerError, Body: ErrorResponse{}},
+	},
+}
+
+// Reset handles POST /scheduler/reset
+func (h *Handler) Reset(c *gin.Context) {
+	settings, err := h.messageService.ResetScheduler(c.Request.Context())
+	if err != nil {
+		respondError(c, "Failed to reset scheduler", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Message: "Scheduler reset to defaults",
+		Data:    ToSchedulerSettingsResponse(settings),
+	})
+}
+
+// StopOperation documents Stop in the OpenAPI spec
+var StopOperation = openapi.Operation{
+	Summary:     "Stop the message scheduler",
+	Description: "Stops the automatic message sending scheduler",
+	Tags:        []string{"Scheduler"},
+	Responses: []openapi.Response{
+		{Status: http.StatusOK, Body: SuccessResponse{}},
+		{Status: http.StatusInternalServerError, Body: ErrorResponse{}},
+	},
+}
+
 // Stop handles POST /scheduler/stop
-// @Summary Stop the message scheduler
-// @Description Stops the automatic message sending scheduler
-// @Tags Scheduler
-// @Produce json
-// @Success 200 {object} SuccessResponse
-// @Failure 400 {object} ErrorResponse
-// @Router /scheduler/stop [post]
 func (h *Handler) Stop(c *gin.Context) {
 	err := h.messageService.StopScheduler()
 	if err != nil {
+		respondError(c, "Failed to stop scheduler", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Message: "Scheduler stopped successfully",
+	})
+}
+
+// PauseOperation documents Pause in the OpenAPI spec
+var PauseOperation = openapi.Operation{
+	Summary: "Pause the message scheduler",
+	Description: "Pauses message processing on every instance until the scheduler is resumed\n" +
+		"Unlike stop, the pause is persisted, so restarted instances stay paused",
+	Tags:         []string{"Scheduler"},
+	Body:         PauseSchedulerRequest{},
+	BodyOptional: true,
+	Responses: []openapi.Response{
+		{Status: http.StatusOK, Body: SuccessResponse{}},
+		{Status: http.StatusBadRequest, Body: ErrorResponse{}},
+		{Status: http.StatusInternalServerError, Body: ErrorResponse{}},
+	},
+}
+
+// Pause handles POST /scheduler/pause
+func (h *Handler) Pause(c *gin.Context) {
+	var req PauseSchedulerRequest
+
+	// The body is optional, the reason is only shown in the scheduler status
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
 		c.JSON(http.StatusBadRequest, ErrorResponse{
 			Success: false,
-			Error:   err.Error(),
+			Error:   "Invalid request: " + err.Error(),
+			Code:    apperr.CodeInvalidRequest,
 		})
 		return
 	}
 
+	pause, err := h.messageService.PauseScheduler(c.Request.Context(), req.Reason)
+	if err != nil {
+		respondError(c, "Failed to pause scheduler", err)
+		return
+	}
+
 	c.JSON(http.StatusOK, SuccessResponse{
 		Success: true,
-		Message: "Scheduler stopped successfully",
+		Message: "Scheduler paused successfully",
+		Data:    ToSchedulerPauseResponse(pause),
+	})
+}
+
+// ResumeOperation documents Resume in the OpenAPI spec
+var ResumeOperation = openapi.Operation{
+	Summary:     "Resume the message scheduler",
+	Description: "Lifts a pause, every instance resumes processing on its next tick",
+	Tags:        []string{"Scheduler"},
+	Responses: []openapi.Response{
+		{Status: http.StatusOK, Body: SuccessResponse{}},
+		{Status: http.StatusInternalServerError, Body: ErrorResponse{}},
+	},
+}
+
+// Resume handles POST /scheduler/resume
+func (h *Handler) Resume(c *gin.Context) {
+	err := h.messageService.ResumeScheduler(c.Request.Context())
+	if err != nil {
+		respondError(c, "Failed to resume scheduler", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Message: "Scheduler resumed successfully",
+	})
+}
+
+// TriggerOperation documents Trigger in the OpenAPI spec
+var TriggerOperation = openapi.Operation{
+	Summary: "Run a batch now",
+	Description: "Claims and sends due messages right away on the instance serving the request, without waiting for the next tick\n" +
+		"The optional batchSize only applies to this run; the response counts the messages claimed, sent and failed\n" +
+		"Refused with 409 while the scheduler is paused, on an instance that is not the leader or while the tick lock is held",
+	Tags:         []string{"Scheduler"},
+	Body:         TriggerBatchRequest{},
+	BodyOptional: true,
+	Responses: []openapi.Response{
+		{Status: http.StatusOK, Body: SuccessResponse{}},
+		{Status: http.StatusBadRequest, Body: ErrorResponse{}},
+		{Status: http.StatusConflict, Body: ErrorResponse{}},
+		{Status: http.StatusInternalServerError, Body: ErrorResponse{}},
+	},
+}
+
+// Trigger handles POST /scheduler/trigger
+func (h *Handler) Trigger(c *gin.Context) {
+	var req TriggerBatchRequest
+
+	// The body is optional, an empty one uses the configured batch size
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Error:   "Invalid request: " + err.Error(),
+			Code:    apperr.CodeInvalidRequest,
+		})
+		return
+	}
+
+	batchSize := 0
+	if req.BatchSize != nil {
+		batchSize = *req.BatchSize
+	}
+
+	result, err := h.messageService.TriggerBatch(c.Request.Context(), batchSize)
+	if err != nil {
+		respondError(c, "Failed to run batch", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Message: "Batch processed successfully",
+		Data:    toBatchResultResponse(*result),
+	})
+}
+
+// GetAttemptsOperation documents GetAttempts in the OpenAPI spec
+var GetAttemptsOperation = openapi.Operation{
+	Summary: "Get send attempts of a message",
+	Description: "Returns every send attempt of a message with its latency breakdown\n" +
+		"With raw=true, failed attempts include the sanitized provider request and response (admin only)",
+	Tags: []string{"Messages"},
+	Params: []openapi.Param{
+		{Name: "id", In: openapi.InPath, Type: "integer", Description: "Message ID"},
+		{Name: "raw", In: openapi.InQuery, Type: "boolean", Description: "Include raw provider exchanges"},
+	},
+	Responses: []openapi.Response{
+		{Status: http.StatusOK, Body: AttemptListResponse{}},
+		{Status: http.StatusBadRequest, Body: ErrorResponse{}},
+		{Status: http.StatusForbidden, Body: ErrorResponse{}},
+		{Status: http.StatusNotFound, Body: ErrorResponse{}},
+		{Status: http.StatusInternalServerError, Body: ErrorResponse{}},
+	},
+}
+
+// GetAttempts handles GET /messages/:id/attempts
+func (h *Handler) GetAttempts(c *gin.Context) {
+	id, ok := parseMessageID(c)
+	if !ok {
+		return
+	}
+
+	includeRaw := c.Query("raw") == "true"
+	if includeRaw && c.GetHeader(userRoleHeader) != adminRole {
+		c.JSON(http.StatusForbidden, ErrorResponse{
+			Success: false,
+			Error:   "Raw provider exchanges require the " + adminRole + " role",
+			Code:    apperr.CodeForbidden,
+		})
+		return
+	}
+
+	attempts, err := h.messageService.GetAttempts(c.Request.Context(), id, tenantScope(c))
+	if err != nil {
+		respondError(c, "Failed to retrieve attempts", err)
+		return
+	}
+
+	responses := ToAttemptResponseList(attempts, includeRaw)
+
+	c.JSON(http.StatusOK, AttemptListResponse{
+		Success:  true,
+		Count:    len(responses),
+		Attempts: responses,
+	})
+}
+
+// GetDeliveryOperation documents GetDelivery in the OpenAPI spec
+var GetDeliveryOperation = openapi.Operation{
+	Summary:     "Get delivery data of a message",
+	Description: "Returns the provider message ID and sent timestamp, read from the cache first",
+	Tags:        []string{"Messages"},
+	Params: []openapi.Param{
+		{Name: "id", In: openapi.InPath, Type: "integer", Description: "Message ID"},
+	},
+	Responses: []openapi.Response{
+		{Status: http.StatusOK, Body: SuccessResponse{}},
+		{Status: http.StatusBadRequest, Body: ErrorResponse{}},
+		{Status: http.StatusNotFound, Body: ErrorResponse{}},
+		{Status: http.StatusInternalServerError, Body: ErrorResponse{}},
+	},
+}
+
+// GetDelivery handles GET /messages/:id/delivery
+func (h *Handler) GetDelivery(c *gin.Context) {
+	id, ok := parseMessageID(c)
+	if !ok {
+		return
+	}
+
+	delivery, err := h.messageService.GetDelivery(c.Request.Context(), id, tenantScope(c))
+	if err != nil {
+		respondError(c, "Failed to retrieve delivery", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Message: "Delivery retrieved successfully",
+		Data:    ToDeliveryResponse(delivery),
 	})
 }
+
+// GetTimelineOperation documents GetTimeline in the OpenAPI spec
+var GetTimelineOperation = openapi.Operation{
+	Summary:     "Get the timeline of a message",
+	Description: "Returns a chronological history of the message assembled from its attempts and replies",
+	Tags:        []string{"Messages"},
+	Params: []openapi.Param{
+		{Name: "id", In: openapi.InPath, Type: "integer", Description: "Message ID"},
+	},
+	Responses: []openapi.Response{
+		{Status: http.StatusOK, Body: SuccessResponse{}},
+		{Status: http.StatusBadRequest, Body: ErrorResponse{}},
+		{Status: http.StatusNotFound, Body: ErrorResponse{}},
+		{Status: http.StatusInternalServerError, Body: ErrorResponse{}},
+	},
+}
+
+// GetTimeline handles GET /messages/:id/timeline
+func (h *Handler) GetTimeline(c *gin.Context) {
+	id, ok := parseMessageID(c)
+	if !ok {
+		return
+	}
+
+	timeline, err := h.messageService.GetTimeline(c.Request.Context(), id, tenantScope(c))
+	if err != nil {
+		respondError(c, "Failed to retrieve timeline", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Message: "Timeline retrieved successfully",
+		Data:    ToTimelineResponse(timeline),
+	})
+}
+
+// GetAttemptStatsOperation documents GetAttemptStats in the OpenAPI spec
+var GetAttemptStatsOperation = openapi.Operation{
+	Summary:     "Get attempt latency statistics",
+	Description: "Aggregates queue wait, lock-to-send, webhook and DB update latency of recent attempts",
+	Tags:        []string{"Messages"},
+	Params: []openapi.Param{
+		{Name: "windowMinutes", In: openapi.InQuery, Type: "integer", Description: "Aggregation window in minutes (default 60)"},
+		{Name: "includeTest", In: openapi.InQuery, Type: "boolean", Description: "Include attempts of sandbox messages"},
+	},
+	Responses: []openapi.Response{
+		{Status: http.StatusOK, Body: AttemptStatsResponse{}},
+		{Status: http.StatusBadRequest, Body: ErrorResponse{}},
+		{Status: http.StatusInternalServerError, Body: ErrorResponse{}},
+	},
+}
+
+// GetAttemptStats handles GET /attempts/stats
+func (h *Handler) GetAttemptStats(c *gin.Context) {
+	windowMinutes := 60
+	if value := c.Query("windowMinutes"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Success: false,
+				Error:   "Invalid request: windowMinutes must be a positive integer",
+				Code:    apperr.CodeInvalidRequest,
+			})
+			return
+		}
+		windowMinutes = parsed
+	}
+
+	includeTest := c.Query("includeTest") == "true"
+
+	stats, err := h.messageService.GetAttemptStats(c.Request.Context(), time.Duration(windowMinutes)*time.Minute, includeTest)
+	if err != nil {
+		respondError(c, "Failed to retrieve attempt stats", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ToAttemptStatsResponse(stats))
+}
+
+// GetLiveStatsOperation documents GetLiveStats in the OpenAPI spec
+var GetLiveStatsOperation = openapi.Operation{
+	Summary:     "Get live send statistics",
+	Description: "Returns send, failure and queue drain rates of this instance over the last 1, 5 and 15 minutes",
+	Tags:        []string{"Messages"},
+	Responses: []openapi.Response{
+		{Status: http.StatusOK, Body: LiveStatsResponse{}},
+	},
+}
+
+// GetLiveStats handles GET /stats/live
+func (h *Handler) GetLiveStats(c *gin.Context) {
+	c.JSON(http.StatusOK, ToLiveStatsResponse(h.messageService.LiveStats()))
+}
+
+// GetStatsOperation documents GetStats in the OpenAPI spec
+var GetStatsOperation = openapi.Operation{
+	Summary:     "Get message pipeline statistics",
+	Description: "Returns message counts per status, messages sent in the last hour and day, the average webhook latency of the last hour, the backlog of due messages and the scheduler state",
+	Tags:        []string{"Messages"},
+	Params: []openapi.Param{
+		{Name: "includeTest", In: openapi.InQuery, Type: "boolean", Description: "Include sandbox messages"},
+	},
+	Responses: []openapi.Response{
+		{Status: http.StatusOK, Body: StatsResponse{}},
+		{Status: http.StatusInternalServerError, Body: ErrorResponse{}},
+	},
+}
+
+// GetStats handles GET /stats
+func (h *Handler) GetStats(c *gin.Context) {
+	includeTest := c.Query("includeTest") == "true"
+
+	stats, err := h.messageService.GetStats(c.Request.Context(), includeTest)
+	if err != nil {
+		respondError(c, "Failed to retrieve stats", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ToStatsResponse(stats))
+}
+
+// GetFanoutOperation documents GetFanout in the OpenAPI spec
+var GetFanoutOperation = openapi.Operation{
+	Summary:     "Get a fan-out",
+	Description: "Returns the messages created from one multi-recipient request with their combined status",
+	Tags:        []string{"Messages"},
+	Params: []openapi.Param{
+		{Name: "id", In: openapi.InPath, Type: "string", Description: "Fan-out ID"},
+		{Name: "masked", In: openapi.InQuery, Type: "boolean", Description: maskedDescription},
+	},
+	Responses: []openapi.Response{
+		{Status: http.StatusOK, Body: SuccessResponse{}},
+		{Status: http.StatusBadRequest, Body: ErrorResponse{}},
+		{Status: http.StatusNotFound, Body: ErrorResponse{}},
+		{Status: http.StatusInternalServerError, Body: ErrorResponse{}},
+	},
+}
+
+// GetFanout handles GET /fanouts/:id
+func (h *Handler) GetFanout(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Error:   "Invalid request: fan-out id must be a valid UUID",
+			Code:    apperr.CodeInvalidRequest,
+		})
+		return
+	}
+
+	fanout, err := h.messageService.GetFanout(c.Request.Context(), id.String(), tenantScope(c))
+	if err != nil {
+		respondError(c, "Failed to retrieve fan-out", err)
+		return
+	}
+
+	response := ToFanoutResponse(fanout)
+	if pii.Masked(c.Request.Context()) {
+		maskMessages(response.Messages)
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Message: "Fan-out retrieved successfully",
+		Data:    response,
+	})
+}
+
+// parseMessageID reads the message ID path parameter, responding with 400 if it is invalid
+func parseMessageID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Error:   "Invalid request: message id must be a positive integer",
+			Code:    apperr.CodeInvalidRequest,
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+// metadataFilter collects the metadata.<key> query parameters, nil when there are none
+// A key given more than once matches its first value
+func metadataFilter(c *gin.Context) message.Metadata {
+	var filter message.Metadata
+	for param, values := range c.Request.URL.Query() {
+		key, ok := strings.CutPrefix(param, metadataParamPrefix)
+		if !ok || len(values) == 0 {
+			continue
+		}
+
+		if filter == nil {
+			filter = message.Metadata{}
+		}
+		filter[key] = values[0]
+	}
+
+	return filter
+}
+
+// createOptions builds the service options of a create or sync request
+func createOptions(c *gin.Context, req MessageFields) message.CreateOptions {
+	opts := message.CreateOptions{
+		Provider:      req.Provider,
+		CallerRole:    c.GetHeader(userRoleHeader),
+		ScheduledAt:   req.ScheduledAt,
+		Transactional: req.Transactional,
+		Locale:        req.Locale,
+		Translations:  req.Translations,
+		TemplateID:    req.TemplateID,
+		Variables:     req.Variables,
+		ExternalRef:   req.ExternalRef,
+		Metadata:      req.Metadata,
+	}
+
+	if req.ProviderTemplate != nil {
+		opts.ProviderTemplate = &message.ProviderTemplate{
+			Name:       req.ProviderTemplate.Name,
+			Language:   req.ProviderTemplate.Language,
+			Parameters: req.ProviderTemplate.Parameters,
+		}
+	}
+
+	if req.RetryPolicy != nil {
+		opts.Retry = &message.RetryOverride{
+			MaxAttempts: req.RetryPolicy.MaxAttempts,
+			Backoff:     message.Backoff(req.RetryPolicy.Backoff),
+			BaseDelay:   time.Duration(req.RetryPolicy.BaseDelaySeconds) * time.Second,
+			MaxDelay:    time.Duration(req.RetryPolicy.MaxDelaySeconds) * time.Second,
+		}
+	}
+
+	// Messages created with a sandbox key are test messages, those of a tenant key are attributed to the tenant
+	if key, ok := apikey.FromContext(c.Request.Context()); ok {
+		opts.IsTest = key.IsTest
+		opts.TenantID = key.TenantID
+	}
+
+	return opts
+}
+
+// tenantScope returns the tenant whose messages the request may read or change: that of a tenant key, including
+// the key an admin acts as a tenant with; nil for a key of no tenant, which reaches the messages of every tenant
+func tenantScope(c *gin.Context) *int64 {
+	if key, ok := apikey.FromContext(c.Request.Context()); ok {
+		return key.TenantID
+	}
+	return nil
+}
+
+// respondError records err for the error middleware, which answers it with the status and code of its kind
+func respondError(c *gin.Context, prefix string, err error) {
+	_ = c.Error(err).SetMeta(prefix)
+}
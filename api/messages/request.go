@@ -1,7 +1,110 @@
 package messages
 
+import (
+	"time"
+)
+
+// MessageFields are the message settings shared by the create and sync requests
+type MessageFields struct {
+	Content     string     `json:"content" binding:"required_without_all=TemplateID ProviderTemplate,excluded_with=TemplateID ProviderTemplate,max=500"`
+	Provider    string     `json:"provider" binding:"omitempty,max=100"`
+	ScheduledAt *time.Time `json:"scheduledAt"`
+	// Transactional messages, e.g. OTPs, are exempt from the per-recipient limit
+	Transactional bool `json:"transactional"`
+	// Locale is the recipient's stored locale, picking the variant of Translations to send
+	Locale       string            `json:"locale" binding:"omitempty,max=35"`
+	Translations map[string]string `json:"translations" binding:"omitempty,max=20"`
+	// TemplateID renders the content from a stored template instead, filled in with Variables
+	TemplateID *int64            `json:"templateId" binding:"omitempty,min=1"`
+	Variables  map[string]string `json:"variables"`
+	// RetryPolicy overrides the configured retry policy for this message
+	RetryPolicy *RetryPolicyRequest `json:"retryPolicy"`
+	// ExternalRef links the message to a business object as type:id, e.g. order:12345
+	ExternalRef string `json:"externalRef" binding:"omitempty,max=306"`
+	// Metadata holds caller-defined fields such as a campaign or user ID, returned as is and filterable
+	Metadata map[string]string `json:"metadata" binding:"omitempty,max=20"`
+	// ProviderTemplate sends an approved template instead of content, required on template-only providers such as WhatsApp
+	ProviderTemplate *ProviderTemplateRequest `json:"providerTemplate" binding:"excluded_with=TemplateID"`
+}
+
+// ProviderTemplateRequest references an approved provider template, Parameters fill its {{1}}, {{2}}, ... slots in order
+type ProviderTemplateRequest struct {
+	Name       string   `json:"name" binding:"required,max=255"`
+	Language   string   `json:"language" binding:"required,max=20"`
+	Parameters []string `json:"parameters" binding:"omitempty,max=20"`
+}
+
 // CreateMessageRequest represents the request to create a new message
 type CreateMessageRequest struct {
+	PhoneNumber string `json:"phoneNumber" binding:"required_without=Recipients,excluded_with=Recipients"`
+	// Recipients fans the message out to several phone numbers instead of PhoneNumber
+	Recipients []string `json:"recipients" binding:"omitempty,min=1,max=100"`
+	// UUID is the client's own identifier for the message, shared with its system of record; not allowed with Recipients
+	UUID string `json:"uuid" binding:"omitempty,uuid,excluded_with=Recipients"`
+
+	MessageFields
+}
+
+// UpsertMessageRequest represents the request to create or update the message with a given UUID
+// A synced message always has a single recipient
+type UpsertMessageRequest struct {
 	PhoneNumber string `json:"phoneNumber" binding:"required"`
-	Content     string `json:"content" binding:"required,max=500"`
+	// Recipients is only declared to reject fan-outs, which cannot be synced
+	Recipients []string `json:"recipients" openapi:"-"`
+
+	MessageFields
+}
+
+// RetryPolicyRequest represents a per-message retry policy, omitted fields fall back to the configuration
+type RetryPolicyRequest struct {
+	MaxAttempts      int    `json:"maxAttempts" binding:"omitempty,min=1,max=20"`
+	Backoff          string `json:"backoff" binding:"omitempty,oneof=exponential linear fixed"`
+	BaseDelaySeconds int    `json:"baseDelaySeconds" binding:"omitempty,min=1,max=86400"`
+	MaxDelaySeconds  int    `json:"maxDelaySeconds" binding:"omitempty,min=1,max=86400"`
+}
+
+// StartSchedulerRequest represents the optional settings for starting the scheduler
+// Omitted fields fall back to the configured defaults
+type StartSchedulerRequest struct {
+	IntervalMinutes *int `json:"intervalMinutes" binding:"omitempty,min=1,max=1440"`
+	// Interval is a Go duration (e.g. 30s, 1m30s) for sub-minute intervals, exclusive with intervalMinutes
+	Interval  *string `json:"interval" binding:"omitempty,excluded_with=IntervalMinutes"`
+	BatchSize *int    `json:"batchSize" binding:"omitempty,min=1,max=1000"`
+	// Cron replaces the interval with a cron expression (seconds first), an empty string clears it
+	Cron *string `json:"cron" binding:"omitempty,max=200"`
+}
+
+// PauseSchedulerRequest represents the optional reason for pausing the scheduler
+type PauseSchedulerRequest struct {
+	Reason string `json:"reason" binding:"omitempty,max=500"`
+}
+
+// TriggerBatchRequest represents the optional one-off batch size of a triggered batch
+type TriggerBatchRequest struct {
+	BatchSize *int `json:"batchSize" binding:"omitempty,min=1,max=1000"`
+}
+
+// ListMessagesRequest represents the query parameters of a message listing
+// Times are RFC 3339; status defaults to sent, all lists every status
+// IncludeArchived also lists the sent messages moved to the archive
+// Metadata filters are read from the metadata.<key> parameters, see metadataFilter
+type ListMessagesRequest struct {
+	Status        string     `form:"status"`
+	PhoneNumber   string     `form:"phoneNumber" binding:"omitempty,max=20"`
+	CreatedFrom   *time.Time `form:"createdFrom" time_format:"2006-01-02T15:04:05Z07:00"`
+	CreatedTo     *time.Time `form:"createdTo" time_format:"2006-01-02T15:04:05Z07:00"`
+	ProcessedFrom *time.Time `form:"processedFrom" time_format:"2006-01-02T15:04:05Z07:00"`
+	ProcessedTo   *time.Time `form:"processedTo" time_format:"2006-01-02T15:04:05Z07:00"`
+	Search        string     `form:"search" binding:"omitempty,max=500"`
+	ExternalRef   string     `form:"externalRef" binding:"omitempty,max=306"`
+
+	IncludeArchived bool `form:"includeArchived"`
+	Stream          bool `form:"stream"`
+}
+
+// ListRunsRequest represents the query parameters of a scheduler run listing
+// Before is the nextBefore of the previous page, omitted for the newest runs
+type ListRunsRequest struct {
+	Before int64 `form:"before" binding:"omitempty,min=1"`
+	Limit  int   `form:"limit" binding:"omitempty,min=1,max=500"`
 }
@@ -3,17 +3,62 @@ package messages
 import (
 	"time"
 
+	"qubit/pkg/jsonfmt"
+	"qubit/pkg/pii"
 	"qubit/service/message"
 )
 
 // MessageResponse represents a message in API responses
 type MessageResponse struct {
-	ID          int64      `json:"id"`
-	PhoneNumber string     `json:"phoneNumber"`
-	Content     string     `json:"content"`
-	CreatedAt   time.Time  `json:"createdAt"`
-	MessageID   *string    `json:"messageId"`
-	ProcessedAt *time.Time `json:"processedAt"`
+	ID            int64         `json:"id"`
+	UUID          string        `json:"uuid"`
+	PhoneNumber   string        `json:"phoneNumber"`
+	Content       string        `json:"content"`
+	CreatedAt     jsonfmt.Time  `json:"createdAt"`
+	MessageID     *string       `json:"messageId"`
+	ProcessedAt   *jsonfmt.Time `json:"processedAt"`
+	RetryCount    int           `json:"retryCount"`
+	NextAttemptAt *jsonfmt.Time `json:"nextAttemptAt"`
+	Status        string        `json:"status"`
+	Provider      *string       `json:"provider"`
+	ScheduledAt   *jsonfmt.Time `json:"scheduledAt"`
+	IsTest        bool          `json:"isTest"`
+	Transactional bool          `json:"transactional"`
+	FanoutID      *string       `json:"fanoutId"`
+	ExternalRef   *string       `json:"externalRef"`
+	// Metadata holds the caller-defined fields given on creation
+	Metadata map[string]string `json:"metadata"`
+	// RetryPolicy is set when the message overrides the configured retry policy
+	RetryPolicy *RetryPolicyResponse `json:"retryPolicy"`
+	// ProviderTemplate is set on messages of template-only providers, content then holds the rendered template
+	ProviderTemplate *ProviderTemplateResponse `json:"providerTemplate"`
+	ContentLocale    *string                   `json:"contentLocale"`
+	Segments         SegmentsResponse          `json:"segments"`
+	// Archived is only set on a message read back from the archive
+	Archived bool `json:"archived,omitempty"`
+}
+
+// SegmentsResponse represents the SMS parts the content of a message is sent in
+// Length counts GSM-7 septets or UCS-2 code units, depending on the encoding
+type SegmentsResponse struct {
+	Encoding string `json:"encoding"`
+	Length   int    `json:"length"`
+	Count    int    `json:"count"`
+}
+
+// RetryPolicyResponse represents a per-message retry policy
+type RetryPolicyResponse struct {
+	MaxAttempts      int    `json:"maxAttempts"`
+	Backoff          string `json:"backoff"`
+	BaseDelaySeconds int    `json:"baseDelaySeconds"`
+	MaxDelaySeconds  int    `json:"maxDelaySeconds"`
+}
+
+// ProviderTemplateResponse represents the provider template a message is sent as
+type ProviderTemplateResponse struct {
+	Name       string   `json:"name"`
+	Language   string   `json:"language"`
+	Parameters []string `json:"parameters"`
 }
 
 // SuccessResponse represents a generic success response
@@ -27,13 +72,147 @@ type SuccessResponse struct {
 type ErrorResponse struct {
 	Success bool   `json:"success"`
 	Error   string `json:"error"`
+	Code    string `json:"code"`
 }
 
-// SchedulerStatusResponse represents the scheduler status
-type SchedulerStatusResponse struct {
-	Running         bool    `json:"running"`
+// SchedulerSettingsResponse represents the settings the scheduler runs with
+// IntervalMinutes is fractional for sub-minute intervals
+type SchedulerSettingsResponse struct {
 	Interval        string  `json:"interval"`
 	IntervalMinutes float64 `json:"intervalMinutes"`
+	BatchSize       int     `json:"batchSize"`
+	Cron            string  `json:"cron,omitempty"`
+}
+
+// ToSchedulerSettingsResponse converts domain scheduler settings to SchedulerSettingsResponse
+func ToSchedulerSettingsResponse(settings message.SchedulerSettings) SchedulerSettingsResponse {
+	return SchedulerSettingsResponse{
+		Interval:        settings.Interval.String(),
+		IntervalMinutes: settings.Interval.Minutes(),
+		BatchSize:       settings.BatchSize,
+		Cron:            settings.Cron,
+	}
+}
+
+// SchedulerStatusResponse represents the scheduler status
+// Schedule is the parsed schedule, e.g. "every 2m0s" or the normalized cron expression
+type SchedulerStatusResponse struct {
+	Running         bool          `json:"running"`
+	Interval        string        `json:"interval"`
+	IntervalMinutes float64       `json:"intervalMinutes"`
+	Cron            string        `json:"cron,omitempty"`
+	Schedule        string        `json:"schedule"`
+	NextRun         *jsonfmt.Time `json:"nextRun"`
+	BatchSize       int           `json:"batchSize"`
+	Standby         bool          `json:"standby"`
+	// Leadership is omitted when leader election is disabled
+	Leadership *LeadershipResponse `json:"leadership,omitempty"`
+	// Sharding is omitted when every instance claims from the whole queue
+	Sharding *ShardingResponse `json:"sharding,omitempty"`
+
+	// Paused is omitted unless an operator paused the scheduler
+	Paused *SchedulerPauseResponse `json:"paused,omitempty"`
+	// Throttled is omitted unless a provider answered 429 or 503 with a Retry-After header
+	Throttled []ProviderThrottleResponse `json:"throttled,omitempty"`
+}
+
+// SchedulerPauseResponse represents an operator pause of the scheduler
+type SchedulerPauseResponse struct {
+	Reason     string       `json:"reason,omitempty"`
+	Since      jsonfmt.Time `json:"since"`
+	InstanceID string       `json:"instanceId"`
+}
+
+// ToSchedulerPauseResponse converts a scheduler pause to its response
+func ToSchedulerPauseResponse(pause message.SchedulerPause) SchedulerPauseResponse {
+	return SchedulerPauseResponse{
+		Reason:     pause.Reason,
+		Since:      jsonfmt.NewTime(pause.Since),
+		InstanceID: pause.InstanceID,
+	}
+}
+
+// ProviderThrottleResponse represents a provider that asked to back off, its messages wait until the throttle ends
+type ProviderThrottleResponse struct {
+	Provider   string       `json:"provider"`
+	StatusCode int          `json:"statusCode"`
+	Since      jsonfmt.Time `json:"since"`
+	Until      jsonfmt.Time `json:"until"`
+	Deferred   int64        `json:"deferred"`
+}
+
+// LeadershipResponse represents the scheduler leader election state of the instance
+type LeadershipResponse struct {
+	Backend   string       `json:"backend"`
+	Leader    bool         `json:"leader"`
+	Since     jsonfmt.Time `json:"since"`
+	LastError *string      `json:"lastError,omitempty"`
+}
+
+// ShardingResponse represents the queue shards assigned to the instance
+type ShardingResponse struct {
+	Mode      string       `json:"mode"`
+	Ranges    []string     `json:"ranges"`
+	Shards    int          `json:"shards"`
+	Members   int          `json:"members,omitempty"`
+	Index     int          `json:"index,omitempty"`
+	Since     jsonfmt.Time `json:"since"`
+	LastError *string      `json:"lastError,omitempty"`
+}
+
+// ToSchedulerStatusResponse converts the domain scheduler status to SchedulerStatusResponse
+func ToSchedulerStatusResponse(status message.SchedulerStatus) SchedulerStatusResponse {
+	resp := SchedulerStatusResponse{
+		Running:         status.Running,
+		Interval:        status.Settings.Interval.String(),
+		IntervalMinutes: status.Settings.Interval.Minutes(),
+		Cron:            status.Settings.Cron,
+		Schedule:        status.Schedule,
+		NextRun:         jsonfmt.NewTimePtr(status.NextRun),
+		BatchSize:       status.Settings.BatchSize,
+		Standby:         status.Standby,
+	}
+
+	if leadership := status.Leadership; leadership != nil {
+		resp.Leadership = &LeadershipResponse{
+			Backend:   leadership.Backend,
+			Leader:    leadership.Leader,
+			Since:     jsonfmt.NewTime(leadership.Since),
+			LastError: leadership.LastError,
+		}
+	}
+
+	if sharding := status.Sharding; sharding != nil {
+		resp.Sharding = &ShardingResponse{
+			Mode:      sharding.Mode,
+			Ranges:    make([]string, 0, len(sharding.Ranges)),
+			Members:   sharding.Members,
+			Index:     sharding.Index,
+			Since:     jsonfmt.NewTime(sharding.Since),
+			LastError: sharding.LastError,
+		}
+		for _, r := range sharding.Ranges {
+			resp.Sharding.Ranges = append(resp.Sharding.Ranges, r.String())
+			resp.Sharding.Shards += r.To - r.From + 1
+		}
+	}
+
+	if status.Paused != nil {
+		paused := ToSchedulerPauseResponse(*status.Paused)
+		resp.Paused = &paused
+	}
+
+	for _, throttle := range status.Throttled {
+		resp.Throttled = append(resp.Throttled, ProviderThrottleResponse{
+			Provider:   throttle.Provider,
+			StatusCode: throttle.StatusCode,
+			Since:      jsonfmt.NewTime(throttle.Since),
+			Until:      jsonfmt.NewTime(throttle.Until),
+			Deferred:   throttle.Deferred,
+		})
+	}
+
+	return resp
 }
 
 // MessageListResponse represents a list of messages
@@ -47,16 +226,61 @@ type MessageListResponse struct {
 func ToMessageResponse(msg *message.Message) MessageResponse {
 	resp := MessageResponse{
 		ID:          msg.ID,
+		UUID:        msg.UUID,
 		PhoneNumber: msg.PhoneNumber,
 		Content:     msg.Content,
-		CreatedAt:   msg.CreatedAt,
+		CreatedAt:   jsonfmt.NewTime(msg.CreatedAt),
 		MessageID:   msg.MessageID,
-		ProcessedAt: msg.ProcessedAt,
+		ProcessedAt: jsonfmt.NewTimePtr(msg.ProcessedAt),
+
+		RetryCount:    msg.RetryCount,
+		NextAttemptAt: jsonfmt.NewTimePtr(msg.NextAttemptAt),
+		Status:        string(msg.Status),
+		Provider:      msg.Provider,
+		ScheduledAt:   jsonfmt.NewTimePtr(msg.ScheduledAt),
+		IsTest:        msg.IsTest,
+		Transactional: msg.Transactional,
+		FanoutID:      msg.FanoutID,
+		Metadata:      msg.Metadata,
+		ContentLocale: msg.ContentLocale,
+		Segments:      ToSegmentsResponse(msg.Segments()),
+		Archived:      msg.Archived,
+	}
+
+	if msg.ExternalRef != nil {
+		ref := msg.ExternalRef.String()
+		resp.ExternalRef = &ref
+	}
+
+	if t := msg.ProviderTemplate; t != nil {
+		parameters := t.Parameters
+		if parameters == nil {
+			parameters = []string{}
+		}
+		resp.ProviderTemplate = &ProviderTemplateResponse{Name: t.Name, Language: t.Language, Parameters: parameters}
+	}
+
+	if policy := msg.RetryPolicy; policy != nil {
+		resp.RetryPolicy = &RetryPolicyResponse{
+			MaxAttempts:      policy.MaxRetries + 1,
+			Backoff:          string(policy.Backoff),
+			BaseDelaySeconds: int(policy.BaseDelay.Seconds()),
+			MaxDelaySeconds:  int(policy.MaxDelay.Seconds()),
+		}
 	}
 
 	return resp
 }
 
+// ToSegmentsResponse converts the SMS parts of a message to SegmentsResponse
+func ToSegmentsResponse(segments message.Segments) SegmentsResponse {
+	return SegmentsResponse{
+		Encoding: string(segments.Encoding),
+		Length:   segments.Length,
+		Count:    segments.Count,
+	}
+}
+
 // ToMessageResponseList converts a slice of domain messages to MessageResponse slice
 func ToMessageResponseList(messages []*message.Message) []MessageResponse {
 	if messages == nil {
@@ -70,3 +294,464 @@ func ToMessageResponseList(messages []*message.Message) []MessageResponse {
 
 	return responses
 }
+
+// mask hides the phone number and content of the message, including the parameters of its provider template
+func (r *MessageResponse) mask() {
+	r.PhoneNumber = pii.MaskPhone(r.PhoneNumber)
+	r.Content = pii.MaskContent(r.Content)
+	if r.ProviderTemplate != nil {
+		template := *r.ProviderTemplate
+		template.Parameters = make([]string, len(r.ProviderTemplate.Parameters))
+		for i, value := range r.ProviderTemplate.Parameters {
+			template.Parameters[i] = pii.MaskContent(value)
+		}
+		r.ProviderTemplate = &template
+	}
+}
+
+// maskMessages masks every message of a list response, see mask
+func maskMessages(responses []MessageResponse) {
+	for i := range responses {
+		responses[i].mask()
+	}
+}
+
+// FanoutResponse represents a fan-out with the combined status of its messages
+type FanoutResponse struct {
+	FanoutID     string            `json:"fanoutId"`
+	Count        int               `json:"count"`
+	Done         bool              `json:"done"`
+	StatusCounts map[string]int    `json:"statusCounts"`
+	Messages     []MessageResponse `json:"messages"`
+}
+
+// ToFanoutResponse converts a domain fan-out to FanoutResponse
+func ToFanoutResponse(fanout *message.Fanout) FanoutResponse {
+	counts := make(map[string]int)
+	for status, count := range fanout.StatusCounts() {
+		counts[string(status)] = count
+	}
+
+	return FanoutResponse{
+		FanoutID:     fanout.ID,
+		Count:        len(fanout.Messages),
+		Done:         fanout.Done(),
+		StatusCounts: counts,
+		Messages:     ToMessageResponseList(fanout.Messages),
+	}
+}
+
+// InFlightMessageResponse represents a message claimed for sending together with its lease
+type InFlightMessageResponse struct {
+	MessageResponse
+	LockedBy       *string       `json:"lockedBy"`
+	LockedAt       *jsonfmt.Time `json:"lockedAt"`
+	LeaseExpiresAt *jsonfmt.Time `json:"leaseExpiresAt"`
+	LeaseExpired   bool          `json:"leaseExpired"`
+}
+
+// InFlightListResponse represents the messages in flight across all instances
+type InFlightListResponse struct {
+	Success  bool                      `json:"success"`
+	Count    int                       `json:"count"`
+	Messages []InFlightMessageResponse `json:"messages"`
+}
+
+// ToInFlightListResponse converts claimed domain messages to InFlightListResponse
+// A lease without expiry counts as expired, the reaper releases it on its next run
+func ToInFlightListResponse(messages []*message.Message, now time.Time) InFlightListResponse {
+	responses := make([]InFlightMessageResponse, 0, len(messages))
+	for _, msg := range messages {
+		responses = append(responses, InFlightMessageResponse{
+			MessageResponse: ToMessageResponse(msg),
+			LockedBy:        msg.LockedBy,
+			LockedAt:        jsonfmt.NewTimePtr(msg.LockedAt),
+			LeaseExpiresAt:  jsonfmt.NewTimePtr(msg.LeaseExpiresAt),
+			LeaseExpired:    msg.LeaseExpiresAt == nil || msg.LeaseExpiresAt.Before(now),
+		})
+	}
+
+	return InFlightListResponse{
+		Success:  true,
+		Count:    len(responses),
+		Messages: responses,
+	}
+}
+
+// DeliveryResponse represents the delivery data of a message
+type DeliveryResponse struct {
+	ID        int64        `json:"id"`
+	MessageID string       `json:"messageId"`
+	SentAt    jsonfmt.Time `json:"sentAt"`
+	Source    string       `json:"source"`
+}
+
+// ToDeliveryResponse converts a domain message.Delivery to DeliveryResponse
+func ToDeliveryResponse(delivery *message.Delivery) DeliveryResponse {
+	return DeliveryResponse{
+		ID:        delivery.ID,
+		MessageID: delivery.MessageID,
+		SentAt:    jsonfmt.NewTime(delivery.SentAt),
+		Source:    delivery.Source,
+	}
+}
+
+// TimelineEventResponse represents a single step in the life of a message
+type TimelineEventResponse struct {
+	At     jsonfmt.Time `json:"at"`
+	Type   string       `json:"type"`
+	Detail string       `json:"detail"`
+}
+
+// TimelineResponse represents the chronological history of a message
+type TimelineResponse struct {
+	Message MessageResponse         `json:"message"`
+	Events  []TimelineEventResponse `json:"events"`
+}
+
+// ToTimelineResponse converts a domain message.Timeline to TimelineResponse
+func ToTimelineResponse(timeline *message.Timeline) TimelineResponse {
+	events := make([]TimelineEventResponse, 0, len(timeline.Events))
+	for _, e := range timeline.Events {
+		events = append(events, TimelineEventResponse{
+			At:     jsonfmt.NewTime(e.At),
+			Type:   e.Type,
+			Detail: e.Detail,
+		})
+	}
+
+	return TimelineResponse{
+		Message: ToMessageResponse(timeline.Message),
+		Events:  events,
+	}
+}
+
+// AttemptResponse represents a send attempt with its latency breakdown in milliseconds
+type AttemptResponse struct {
+	ID            int64        `json:"id"`
+	MessageID     int64        `json:"messageId"`
+	AttemptNumber int          `json:"attemptNumber"`
+	StartedAt     jsonfmt.Time `json:"startedAt"`
+	Success       bool         `json:"success"`
+	Error         *string      `json:"error"`
+	QueueWaitMs   int64        `json:"queueWaitMs"`
+	LockToSendMs  int64        `json:"lockToSendMs"`
+	WebhookMs     int64        `json:"webhookMs"`
+	DBUpdateMs    int64        `json:"dbUpdateMs"`
+
+	FailureCategory *string `json:"failureCategory"`
+
+	RequestPayload *string `json:"requestPayload,omitempty"`
+	ResponseBody   *string `json:"responseBody,omitempty"`
+}
+
+// AttemptListResponse represents a list of send attempts
+type AttemptListResponse struct {
+	Success  bool              `json:"success"`
+	Count    int               `json:"count"`
+	Attempts []AttemptResponse `json:"attempts"`
+}
+
+// PhaseStatsResponse represents aggregated latency of an attempt phase in milliseconds
+type PhaseStatsResponse struct {
+	AvgMs float64 `json:"avgMs"`
+	P95Ms float64 `json:"p95Ms"`
+	MaxMs float64 `json:"maxMs"`
+}
+
+// AttemptStatsResponse represents aggregated attempt latency
+type AttemptStatsResponse struct {
+	Success    bool               `json:"success"`
+	Since      jsonfmt.Time       `json:"since"`
+	Attempts   int64              `json:"attempts"`
+	Succeeded  int64              `json:"succeeded"`
+	Failures   map[string]int64   `json:"failures"`
+	QueueWait  PhaseStatsResponse `json:"queueWait"`
+	LockToSend PhaseStatsResponse `json:"lockToSend"`
+	Webhook    PhaseStatsResponse `json:"webhook"`
+	DBUpdate   PhaseStatsResponse `json:"dbUpdate"`
+}
+
+// ToAttemptResponseList converts a slice of domain attempts to AttemptResponse slice
+// Raw provider exchanges are only included when includeRaw is set
+func ToAttemptResponseList(attempts []*message.Attempt, includeRaw bool) []AttemptResponse {
+	responses := make([]AttemptResponse, 0, len(attempts))
+	for _, a := range attempts {
+		response := AttemptResponse{
+			ID:            a.ID,
+			MessageID:     a.MessageID,
+			AttemptNumber: a.AttemptNumber,
+			StartedAt:     jsonfmt.NewTime(a.StartedAt),
+			Success:       a.Success,
+			Error:         a.Error,
+			QueueWaitMs:   a.QueueWait.Milliseconds(),
+			LockToSendMs:  a.LockToSend.Milliseconds(),
+			WebhookMs:     a.Webhook.Milliseconds(),
+			DBUpdateMs:    a.DBUpdate.Milliseconds(),
+
+			FailureCategory: a.FailureCategory,
+		}
+		if includeRaw {
+			response.RequestPayload = a.RequestPayload
+			response.ResponseBody = a.ResponseBody
+		}
+
+		responses = append(responses, response)
+	}
+
+	return responses
+}
+
+// ToAttemptStatsResponse converts domain attempt stats to AttemptStatsResponse
+func ToAttemptStatsResponse(stats *message.AttemptStats) AttemptStatsResponse {
+	toPhase := func(p message.PhaseStats) PhaseStatsResponse {
+		return PhaseStatsResponse{
+			AvgMs: float64(p.Avg) / float64(time.Millisecond),
+			P95Ms: float64(p.P95) / float64(time.Millisecond),
+			MaxMs: float64(p.Max) / float64(time.Millisecond),
+		}
+	}
+
+	return AttemptStatsResponse{
+		Success:    true,
+		Since:      jsonfmt.NewTime(stats.Since),
+		Attempts:   stats.Attempts,
+		Succeeded:  stats.Succeeded,
+		Failures:   stats.Failures,
+		QueueWait:  toPhase(stats.QueueWait),
+		LockToSend: toPhase(stats.LockToSend),
+		Webhook:    toPhase(stats.Webhook),
+		DBUpdate:   toPhase(stats.DBUpdate),
+	}
+}
+
+// WindowStatsResponse represents send activity over a rolling window
+type WindowStatsResponse struct {
+	WindowMinutes int     `json:"windowMinutes"`
+	Sent          int64   `json:"sent"`
+	Failed        int64   `json:"failed"`
+	Drained       int64   `json:"drained"`
+	SendRate      float64 `json:"sendRatePerSecond"`
+	FailureRate   float64 `json:"failureRate"`
+	DrainRate     float64 `json:"drainRatePerSecond"`
+}
+
+// LiveStatsResponse represents the in-memory rolling snapshot of this instance
+type LiveStatsResponse struct {
+	Success bool                  `json:"success"`
+	At      jsonfmt.Time          `json:"at"`
+	Windows []WindowStatsResponse `json:"windows"`
+}
+
+// ToLiveStatsResponse converts domain window stats to LiveStatsResponse
+func ToLiveStatsResponse(snapshot []message.WindowStats) LiveStatsResponse {
+	windows := make([]WindowStatsResponse, 0, len(snapshot))
+	for _, w := range snapshot {
+		windows = append(windows, WindowStatsResponse{
+			WindowMinutes: int(w.Window / time.Minute),
+			Sent:          w.Sent,
+			Failed:        w.Failed,
+			Drained:       w.Drained,
+			SendRate:      w.SendRate,
+			FailureRate:   w.FailureRate,
+			DrainRate:     w.DrainRate,
+		})
+	}
+
+	return LiveStatsResponse{
+		Success: true,
+		At:      jsonfmt.NewTime(time.Now()),
+		Windows: windows,
+	}
+}
+
+// BatchResultResponse represents the summary of a finished batch
+// Errors counts the failed attempts per failure category and is omitted when none failed
+type BatchResultResponse struct {
+	Claimed     int            `json:"claimed"`
+	Attempted   int            `json:"attempted"`
+	Sent        int            `json:"sent"`
+	Retried     int            `json:"retried"`
+	Failed      int            `json:"failed"`
+	Throttled   int            `json:"throttled"`
+	Deferred    int            `json:"deferred"`
+	Quarantined int            `json:"quarantined"`
+	DurationMs  int64          `json:"durationMs"`
+	Errors      map[string]int `json:"errors,omitempty"`
+}
+
+// ProgressEventResponse represents a batch progress event sent over SSE
+type ProgressEventResponse struct {
+	Type      string               `json:"type"`
+	BatchID   string               `json:"batchId"`
+	At        jsonfmt.Time         `json:"at"`
+	Done      int                  `json:"done"`
+	Total     int                  `json:"total"`
+	MessageID *int64               `json:"messageId,omitempty"`
+	Error     *string              `json:"error,omitempty"`
+	Result    *BatchResultResponse `json:"result,omitempty"`
+}
+
+// ToProgressEventResponse converts a domain progress event to ProgressEventResponse
+func ToProgressEventResponse(event message.ProgressEvent) ProgressEventResponse {
+	resp := ProgressEventResponse{
+		Type:    string(event.Type),
+		BatchID: event.BatchID,
+		At:      jsonfmt.NewTime(event.At),
+		Done:    event.Done,
+		Total:   event.Total,
+		Error:   event.Error,
+	}
+
+	if event.MessageID != 0 {
+		messageID := event.MessageID
+		resp.MessageID = &messageID
+	}
+
+	if event.Result != nil {
+		result := toBatchResultResponse(*event.Result)
+		resp.Result = &result
+	}
+
+	return resp
+}
+
+// toBatchResultResponse converts a domain batch result to BatchResultResponse
+func toBatchResultResponse(result message.BatchResult) BatchResultResponse {
+	return BatchResultResponse{
+		Claimed:     result.Claimed,
+		Attempted:   result.Attempted(),
+		Sent:        result.Sent,
+		Retried:     result.Retried,
+		Failed:      result.Failed,
+		Throttled:   result.Throttled,
+		Deferred:    result.Deferred,
+		Quarantined: result.Quarantined,
+		DurationMs:  result.Duration.Milliseconds(),
+		Errors:      result.Errors,
+	}
+}
+
+// RunResponse represents the report of a scheduler run
+// Error is set when the run ended early, result then counts the messages handled before
+type RunResponse struct {
+	ID         int64               `json:"id"`
+	Instance   string              `json:"instance"`
+	StartedAt  jsonfmt.Time        `json:"startedAt"`
+	FinishedAt jsonfmt.Time        `json:"finishedAt"`
+	Result     BatchResultResponse `json:"result"`
+	Error      *string             `json:"error"`
+}
+
+// RunListResponse represents a page of scheduler runs, newest first
+// NextBefore is passed as before to fetch the following page, null on the last page
+type RunListResponse struct {
+	Success    bool          `json:"success"`
+	Count      int           `json:"count"`
+	Runs       []RunResponse `json:"runs"`
+	NextBefore *int64        `json:"nextBefore"`
+}
+
+// ToRunListResponse converts a page of scheduler runs to RunListResponse
+func ToRunListResponse(page *message.RunPage) RunListResponse {
+	runs := make([]RunResponse, 0, len(page.Runs))
+	for _, run := range page.Runs {
+		runs = append(runs, RunResponse{
+			ID:         run.ID,
+			Instance:   run.InstanceID,
+			StartedAt:  jsonfmt.NewTime(run.StartedAt),
+			FinishedAt: jsonfmt.NewTime(run.FinishedAt),
+			Result:     toBatchResultResponse(run.Result),
+			Error:      run.Error,
+		})
+	}
+
+	return RunListResponse{
+		Success:    true,
+		Count:      len(runs),
+		Runs:       runs,
+		NextBefore: page.NextBefore,
+	}
+}
+
+// StatsResponse represents the admin overview of the message pipeline
+// Message counts, recent sends, latency and backlog cover the whole deployment,
+// scheduler, lastBatch and live only this instance
+type StatsResponse struct {
+	Success  bool                  `json:"success"`
+	At       jsonfmt.Time          `json:"at"`
+	Messages MessageCountsResponse `json:"messages"`
+
+	SentLastHour int64 `json:"sentLastHour"`
+	SentLastDay  int64 `json:"sentLastDay"`
+
+	AvgWebhookLatencyMs float64 `json:"avgWebhookLatencyMs"`
+
+	Backlog BacklogResponse `json:"backlog"`
+
+	Scheduler SchedulerStatusResponse `json:"scheduler"`
+	// LastBatch is omitted until this instance completed a batch that claimed messages
+	LastBatch *LastBatchResponse    `json:"lastBatch,omitempty"`
+	Live      []WindowStatsResponse `json:"live"`
+}
+
+// MessageCountsResponse represents the number of messages per status
+type MessageCountsResponse struct {
+	Pending     int64 `json:"pending"`
+	Sending     int64 `json:"sending"`
+	Sent        int64 `json:"sent"`
+	Failed      int64 `json:"failed"`
+	Cancelled   int64 `json:"cancelled"`
+	Throttled   int64 `json:"throttled"`
+	Quarantined int64 `json:"quarantined"`
+}
+
+// BacklogResponse represents the pending messages that are due now
+// OldestDueAt is null and ageSeconds 0 without backlog
+type BacklogResponse struct {
+	Count       int64         `json:"count"`
+	OldestDueAt *jsonfmt.Time `json:"oldestDueAt"`
+	AgeSeconds  float64       `json:"ageSeconds"`
+}
+
+// LastBatchResponse represents the last completed batch of this instance
+type LastBatchResponse struct {
+	FinishedAt jsonfmt.Time        `json:"finishedAt"`
+	Result     BatchResultResponse `json:"result"`
+}
+
+// ToStatsResponse converts domain stats to StatsResponse
+func ToStatsResponse(stats *message.Stats) StatsResponse {
+	resp := StatsResponse{
+		Success: true,
+		At:      jsonfmt.NewTime(time.Now()),
+		Messages: MessageCountsResponse{
+			Pending:     stats.ByStatus[message.StatusPending],
+			Sending:     stats.ByStatus[message.StatusSending],
+			Sent:        stats.ByStatus[message.StatusSent],
+			Failed:      stats.ByStatus[message.StatusFailed],
+			Cancelled:   stats.ByStatus[message.StatusCancelled],
+			Throttled:   stats.ByStatus[message.StatusThrottled],
+			Quarantined: stats.ByStatus[message.StatusQuarantined],
+		},
+		SentLastHour:        stats.SentLastHour,
+		SentLastDay:         stats.SentLastDay,
+		AvgWebhookLatencyMs: float64(stats.AvgWebhookLatency) / float64(time.Millisecond),
+		Backlog: BacklogResponse{
+			Count:       stats.Backlog,
+			OldestDueAt: jsonfmt.NewTimePtr(stats.OldestDue),
+			AgeSeconds:  stats.BacklogAge.Seconds(),
+		},
+		Scheduler: ToSchedulerStatusResponse(stats.Scheduler),
+		Live:      ToLiveStatsResponse(stats.Live).Windows,
+	}
+
+	if batch := stats.LastBatch; batch != nil {
+		resp.LastBatch = &LastBatchResponse{
+			FinishedAt: jsonfmt.NewTime(batch.FinishedAt),
+			Result:     toBatchResultResponse(batch.Result),
+		}
+	}
+
+	return resp
+}
@@ -2,9 +2,13 @@ package api
 
 import (
 	"log"
+	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
+
+	"qubit/pkg/apperr"
+	"qubit/pkg/ctxerr"
 )
 
 // Logger is a custom logging middleware for Gin
@@ -25,6 +29,18 @@ func Logger() gin.HandlerFunc {
 		path := c.Request.URL.Path
 		clientIP := c.ClientIP()
 
+		// Client went away before the response, nothing else is worth logging
+		if statusCode == ctxerr.StatusClientClosedRequest {
+			log.Printf("[%s] %s %s | Client closed request | Latency: %v | IP: %s",
+				method,
+				path,
+				c.Request.Proto,
+				latency,
+				clientIP,
+			)
+			return
+		}
+
 		// Log the request
 		log.Printf("[%s] %s %s | Status: %d | Latency: %v | IP: %s",
 			method,
@@ -50,11 +66,7 @@ func Recovery() gin.HandlerFunc {
 		defer func() {
 			if err := recover(); err != nil {
 				log.Printf("Panic recovered: %v", err)
-				c.JSON(500, gin.H{
-					"success": false,
-					"error":   "Internal server error",
-				})
-				c.Abort()
+				abortWithError(c, http.StatusInternalServerError, apperr.CodeInternal, "Internal server error")
 			}
 		}()
 
@@ -67,7 +79,7 @@ func CORS() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-API-Key, X-Act-As-Tenant")
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
@@ -77,3 +89,21 @@ func CORS() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// Identity headers set by the upstream gateway
+const (
+	UserIDHeader   = "X-User-ID"
+	UserRoleHeader = "X-User-Role"
+)
+
+// RequireRole restricts a route to callers whose role header matches role
+func RequireRole(role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader(UserIDHeader) == "" || c.GetHeader(UserRoleHeader) != role {
+			abortWithError(c, http.StatusForbidden, apperr.CodeForbidden, "This action requires the "+role+" role")
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,42 @@
+package api
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"qubit/env/mirror"
+	"qubit/pkg/apperr"
+	"qubit/service/replay"
+)
+
+// maxMirroredBody bounds the request bodies mirrored, larger ones are only served
+const maxMirroredBody = 64 << 10
+
+// Mirror forwards a sample of the requests to staging once they were answered, see mirror.Client
+// A nil client disables mirroring; replayed and mirrored requests are never mirrored again
+func Mirror(client *mirror.Client) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if client == nil || c.Request.Body == nil || c.GetHeader(replay.Header) != "" || c.GetHeader(mirror.Header) != "" || !client.Sample() {
+			c.Next()
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxMirroredBody+1))
+		if err != nil {
+			abortWithError(c, http.StatusBadRequest, apperr.CodeInvalidRequest, "Failed to read request body: "+err.Error())
+			return
+		}
+		// Handlers read the body as sent, including anything past the mirrored prefix
+		c.Request.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), c.Request.Body))
+
+		c.Next()
+
+		if len(body) > maxMirroredBody {
+			return
+		}
+		client.Mirror(c.Request.Method, c.Request.URL.RequestURI(), body, c.Writer.Status())
+	}
+}
@@ -0,0 +1,44 @@
+package providers
+
+import (
+	"net/http"
+
+	"qubit/env/provider"
+	"qubit/pkg/openapi"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler handles provider administration HTTP requests
+type Handler struct {
+	providers *provider.Registry
+}
+
+// NewHandler creates a new provider handler
+func NewHandler(providers *provider.Registry) *Handler {
+	return &Handler{
+		providers: providers,
+	}
+}
+
+// GetProvidersOperation documents GetProviders in the OpenAPI spec
+var GetProvidersOperation = openapi.Operation{
+	Summary:     "List configured providers",
+	Description: "Returns the configured message providers with secrets redacted (requires an admin key and role)",
+	Tags:        []string{"Providers"},
+	Responses: []openapi.Response{
+		{Status: http.StatusOK, Body: ProviderListResponse{}},
+		{Status: http.StatusForbidden, Body: gin.H{}},
+	},
+}
+
+// GetProviders handles GET /providers
+func (h *Handler) GetProviders(c *gin.Context) {
+	responses := ToProviderResponseList(h.providers.Configs())
+
+	c.JSON(http.StatusOK, ProviderListResponse{
+		Success:   true,
+		Count:     len(responses),
+		Providers: responses,
+	})
+}
@@ -0,0 +1,64 @@
+package providers
+
+import (
+	"qubit/env/config"
+)
+
+// redacted replaces secret values in API responses
+const redacted = "********"
+
+// ProviderResponse represents a provider in API responses, with secrets redacted
+type ProviderResponse struct {
+	Name               string   `json:"name"`
+	Type               string   `json:"type"`
+	URL                string   `json:"url"`
+	AuthKey            string   `json:"authKey"`
+	TimeoutSeconds     int      `json:"timeoutSeconds"`
+	RateLimitPerSecond int      `json:"rateLimitPerSecond"`
+	Capabilities       []string `json:"capabilities"`
+	Default            bool     `json:"default"`
+	Sandbox            bool     `json:"sandbox"`
+}
+
+// ProviderListResponse represents a list of providers
+type ProviderListResponse struct {
+	Success   bool               `json:"success"`
+	Count     int                `json:"count"`
+	Providers []ProviderResponse `json:"providers"`
+}
+
+// ToProviderResponse converts a provider configuration to ProviderResponse
+func ToProviderResponse(provider config.ProviderConfig) ProviderResponse {
+	resp := ProviderResponse{
+		Name:               provider.Name,
+		Type:               provider.Kind(),
+		URL:                provider.URL,
+		TimeoutSeconds:     provider.TimeoutSeconds,
+		RateLimitPerSecond: provider.RateLimitPerSecond,
+		Capabilities:       provider.Capabilities,
+		Sandbox:            provider.Sandbox,
+	}
+
+	if provider.AuthKey != "" {
+		resp.AuthKey = redacted
+	}
+
+	if resp.Capabilities == nil {
+		resp.Capabilities = []string{}
+	}
+
+	return resp
+}
+
+// ToProviderResponseList converts provider configurations to ProviderResponse slice
+// The first provider is flagged as the default one
+func ToProviderResponseList(providers []config.ProviderConfig) []ProviderResponse {
+	responses := make([]ProviderResponse, 0, len(providers))
+	for i, provider := range providers {
+		resp := ToProviderResponse(provider)
+		resp.Default = i == 0
+		responses = append(responses, resp)
+	}
+
+	return responses
+}
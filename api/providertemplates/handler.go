@@ -0,0 +1,87 @@
+package providertemplates
+
+import (
+	"net/http"
+
+	"qubit/pkg/openapi"
+	"qubit/service/providertemplate"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler handles provider template HTTP requests
+type Handler struct {
+	templateService *providertemplate.Service
+}
+
+// NewHandler creates a new provider template handler
+func NewHandler(templateService *providertemplate.Service) *Handler {
+	return &Handler{
+		templateService: templateService,
+	}
+}
+
+// GetTemplatesOperation documents GetTemplates in the OpenAPI spec
+var GetTemplatesOperation = openapi.Operation{
+	Summary:     "List provider templates",
+	Description: "Returns the templates of template-only providers such as WhatsApp with the approval status of the last sync; only approved templates can be sent",
+	Tags:        []string{"Providers"},
+	Responses: []openapi.Response{
+		{Status: http.StatusOK, Body: TemplateListResponse{}},
+		{Status: http.StatusInternalServerError, Body: ErrorResponse{}},
+	},
+}
+
+// GetTemplates handles GET /provider-templates
+func (h *Handler) GetTemplates(c *gin.Context) {
+	found, err := h.templateService.ListTemplates(c.Request.Context())
+	if err != nil {
+		respondError(c, "Failed to retrieve provider templates", err)
+		return
+	}
+
+	responses := ToTemplateResponseList(found)
+
+	c.JSON(http.StatusOK, TemplateListResponse{
+		Success:   true,
+		Count:     len(responses),
+		Templates: responses,
+	})
+}
+
+// SyncOperation documents Sync in the OpenAPI spec
+var SyncOperation = openapi.Operation{
+	Summary:     "Sync provider templates",
+	Description: "Fetches the templates and approval status of every template-only provider right away instead of waiting for the periodic sync",
+	Tags:        []string{"Providers"},
+	Responses: []openapi.Response{
+		{Status: http.StatusOK, Body: SyncResponse{}},
+		{Status: http.StatusNotFound, Body: ErrorResponse{}},
+		{Status: http.StatusInternalServerError, Body: ErrorResponse{}},
+	},
+}
+
+// Sync handles POST /provider-templates/sync
+// A provider failing to sync is reported in its result, the others are still synced
+func (h *Handler) Sync(c *gin.Context) {
+	results, err := h.templateService.Sync(c.Request.Context())
+	if err != nil {
+		respondError(c, "Failed to sync provider templates", err)
+		return
+	}
+
+	responses := make([]SyncResultResponse, 0, len(results))
+	for _, result := range results {
+		responses = append(responses, ToSyncResultResponse(result))
+	}
+
+	c.JSON(http.StatusOK, SyncResponse{
+		Success:   true,
+		Providers: responses,
+	})
+}
+
+// respondError records err for the error middleware, which answers it with the status and code of its kind
+func respondError(c *gin.Context, prefix string, err error) {
+	_ = c.Error(err).SetMeta(prefix)
+}
@@ -0,0 +1,86 @@
+package providertemplates
+
+import (
+	"qubit/pkg/jsonfmt"
+	"qubit/service/providertemplate"
+)
+
+// TemplateResponse represents a provider template with its synced approval status in API responses
+type TemplateResponse struct {
+	Provider   string       `json:"provider"`
+	Name       string       `json:"name"`
+	Language   string       `json:"language"`
+	ExternalID *string      `json:"externalId"`
+	Body       string       `json:"body"`
+	Parameters int          `json:"parameters"`
+	Status     string       `json:"status"`
+	SyncedAt   jsonfmt.Time `json:"syncedAt"`
+}
+
+// TemplateListResponse represents the synced templates of all providers
+type TemplateListResponse struct {
+	Success   bool               `json:"success"`
+	Count     int                `json:"count"`
+	Templates []TemplateResponse `json:"templates"`
+}
+
+// SyncResultResponse represents the outcome of syncing the templates of one provider
+type SyncResultResponse struct {
+	Provider  string `json:"provider"`
+	Templates int    `json:"templates"`
+	Approved  int    `json:"approved"`
+	Deleted   int64  `json:"deleted"`
+	// Error is set when the provider could not be synced, its stored templates are left unchanged
+	Error *string `json:"error,omitempty"`
+}
+
+// SyncResponse represents the outcome of a template sync
+type SyncResponse struct {
+	Success   bool                 `json:"success"`
+	Providers []SyncResultResponse `json:"providers"`
+}
+
+// ErrorResponse represents an error response
+type ErrorResponse struct {
+	Success bool   `json:"success"`
+	Error   string `json:"error"`
+	Code    string `json:"code"`
+}
+
+// ToTemplateResponse converts a domain providertemplate.Template to TemplateResponse
+func ToTemplateResponse(t *providertemplate.Template) TemplateResponse {
+	return TemplateResponse{
+		Provider:   t.Provider,
+		Name:       t.Name,
+		Language:   t.Language,
+		ExternalID: t.ExternalID,
+		Body:       t.Body,
+		Parameters: t.Parameters,
+		Status:     t.Status,
+		SyncedAt:   jsonfmt.NewTime(t.SyncedAt),
+	}
+}
+
+// ToTemplateResponseList converts a slice of domain templates to TemplateResponse slice
+func ToTemplateResponseList(templates []*providertemplate.Template) []TemplateResponse {
+	responses := make([]TemplateResponse, 0, len(templates))
+	for _, t := range templates {
+		responses = append(responses, ToTemplateResponse(t))
+	}
+	return responses
+}
+
+// ToSyncResultResponse converts a providertemplate.SyncResult to SyncResultResponse
+func ToSyncResultResponse(r providertemplate.SyncResult) SyncResultResponse {
+	resp := SyncResultResponse{
+		Provider:  r.Provider,
+		Templates: r.Templates,
+		Approved:  r.Approved,
+		Deleted:   r.Deleted,
+	}
+	if r.Err != nil {
+		msg := r.Err.Error()
+		resp.Error = &msg
+	}
+	return resp
+}
@@ -0,0 +1,85 @@
+package api
+
+import (
+	"errors"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"qubit/env/redis"
+	"qubit/pkg/apperr"
+	"qubit/pkg/ratelimit"
+	"qubit/service/anomaly"
+	"qubit/service/apikey"
+)
+
+// RateLimit limits requests per API key, or per client IP for unauthenticated requests
+// Rejected requests get 429 with Retry-After; a nil limiter disables limiting
+// Requests pass when the limiter fails, a broken limiter must not take the API down
+func RateLimit(limiter ratelimit.Limiter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if limiter == nil {
+			c.Next()
+			return
+		}
+
+		decision, err := limiter.Allow(c.Request.Context(), rateLimitKey(c))
+		if err != nil {
+			// An open Redis circuit was already logged once by the breaker
+			if !errors.Is(err, redis.ErrCircuitOpen) {
+				log.Printf("Warning: rate limiter unavailable: %v", err)
+			}
+			c.Next()
+			return
+		}
+
+		if !decision.Allowed {
+			retryAfter := int(math.Ceil(decision.RetryAfter.Seconds()))
+			c.Header("Retry-After", strconv.Itoa(max(retryAfter, 1)))
+			abortWithError(c, http.StatusTooManyRequests, apperr.CodeRateLimited, "Rate limit exceeded, retry later")
+			return
+		}
+
+		c.Request = c.Request.WithContext(ratelimit.NewContext(c.Request.Context(), decision))
+		c.Next()
+	}
+}
+
+// TenantThrottle rejects message creation by a tenant throttled after an anomaly alert until it is acknowledged
+// Operator keys are never throttled; a nil service disables the check
+func TenantThrottle(anomalyService *anomaly.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if anomalyService == nil {
+			c.Next()
+			return
+		}
+
+		key, ok := apikey.FromContext(c.Request.Context())
+		if !ok || key.TenantID == nil {
+			c.Next()
+			return
+		}
+
+		alert, throttled := anomalyService.Throttled(*key.TenantID)
+		if !throttled {
+			c.Next()
+			return
+		}
+
+		retryAfter := int(math.Ceil(time.Until(*alert.ThrottledUntil).Seconds()))
+		c.Header("Retry-After", strconv.Itoa(max(retryAfter, 1)))
+		abortWithError(c, http.StatusTooManyRequests, apperr.CodeThrottled, "Tenant throttled after unusual message volume, pending operator acknowledgment")
+	}
+}
+
+// rateLimitKey identifies the caller of a request
+func rateLimitKey(c *gin.Context) string {
+	if key, ok := requestKey(c); ok {
+		return "key:" + strconv.FormatInt(key.ID, 10)
+	}
+	return "ip:" + c.ClientIP()
+}
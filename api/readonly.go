@@ -0,0 +1,33 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"qubit/pkg/apperr"
+	"qubit/service/maintenance"
+)
+
+// maintenancePath is the route toggling maintenance mode, it stays writable so the mode can be lifted
+const maintenancePath = "/api/v1/maintenance"
+
+// ReadOnly rejects mutating requests with 503 while maintenance mode is enabled
+// Reads keep working so dashboards and status checks stay available
+func ReadOnly(maintenanceService *maintenance.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
+
+		mode := maintenanceService.Mode()
+		if !mode.Enabled || c.FullPath() == maintenancePath {
+			c.Next()
+			return
+		}
+
+		abortWithError(c, http.StatusServiceUnavailable, apperr.CodeMaintenance, mode.Message)
+	}
+}
@@ -0,0 +1,185 @@
+package replays
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+
+	"qubit/pkg/apperr"
+	"qubit/pkg/openapi"
+	"qubit/service/replay"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler handles the inspection and replay of rejected requests
+type Handler struct {
+	replayService *replay.Service
+	router        http.Handler
+	actAsHeader   string
+}
+
+// NewHandler creates a new replay handler
+// Replays are served by router, requests of a tenant are replayed acting as the tenant through actAsHeader
+func NewHandler(replayService *replay.Service, router http.Handler, actAsHeader string) *Handler {
+	return &Handler{
+		replayService: replayService,
+		router:        router,
+		actAsHeader:   actAsHeader,
+	}
+}
+
+// GetRequestsOperation documents GetRequests in the OpenAPI spec
+var GetRequestsOperation = openapi.Operation{
+	Summary:     "Get rejected requests",
+	Description: "Returns the 100 most recent API requests rejected with 400, newest first, with credentials redacted",
+	Tags:        []string{"Diagnostics"},
+	Responses: []openapi.Response{
+		{Status: http.StatusOK, Body: RejectedRequestListResponse{}},
+		{Status: http.StatusNotFound, Body: ErrorResponse{}},
+		{Status: http.StatusInternalServerError, Body: ErrorResponse{}},
+	},
+}
+
+// GetRequests handles GET /diagnostics/rejected-requests
+func (h *Handler) GetRequests(c *gin.Context) {
+	if !h.enabled(c) {
+		return
+	}
+
+	found, err := h.replayService.ListRequests(c.Request.Context())
+	if err != nil {
+		respondError(c, "Failed to retrieve rejected requests", err)
+		return
+	}
+
+	responses := ToRejectedRequestResponseList(found)
+
+	c.JSON(http.StatusOK, RejectedRequestListResponse{
+		Success:  true,
+		Count:    len(responses),
+		Requests: responses,
+	})
+}
+
+// GetRequestOperation documents GetRequest in the OpenAPI spec
+var GetRequestOperation = openapi.Operation{
+	Summary:     "Get a rejected request",
+	Description: "Returns a single rejected request with its redacted headers and body and the error it was rejected with",
+	Tags:        []string{"Diagnostics"},
+	Params: []openapi.Param{
+		{Name: "id", In: openapi.InPath, Type: "integer", Description: "Rejected request ID"},
+	},
+	Responses: []openapi.Response{
+		{Status: http.StatusOK, Body: SuccessResponse{}},
+		{Status: http.StatusBadRequest, Body: ErrorResponse{}},
+		{Status: http.StatusNotFound, Body: ErrorResponse{}},
+		{Status: http.StatusInternalServerError, Body: ErrorResponse{}},
+	},
+}
+
+// GetRequest handles GET /diagnostics/rejected-requests/:id
+func (h *Handler) GetRequest(c *gin.Context) {
+	req, ok := h.loadRequest(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Message: "Rejected request retrieved successfully",
+		Data:    ToRejectedRequestResponse(req),
+	})
+}
+
+// ReplayOperation documents Replay in the OpenAPI spec
+var ReplayOperation = openapi.Operation{
+	Summary:     "Replay a rejected request",
+	Description: "Sends a rejected request through the API again, authenticated with the key of the caller and acting as the tenant of the original key; a successful replay takes effect, e.g. creates the message",
+	Tags:        []string{"Diagnostics"},
+	Params: []openapi.Param{
+		{Name: "id", In: openapi.InPath, Type: "integer", Description: "Rejected request ID"},
+	},
+	Responses: []openapi.Response{
+		{Status: http.StatusOK, Body: SuccessResponse{}},
+		{Status: http.StatusBadRequest, Body: ErrorResponse{}},
+		{Status: http.StatusNotFound, Body: ErrorResponse{}},
+		{Status: http.StatusConflict, Body: ErrorResponse{}},
+		{Status: http.StatusInternalServerError, Body: ErrorResponse{}},
+	},
+}
+
+// Replay handles POST /diagnostics/rejected-requests/:id/replay
+func (h *Handler) Replay(c *gin.Context) {
+	req, ok := h.loadRequest(c)
+	if !ok {
+		return
+	}
+
+	httpReq, err := req.NewHTTPRequest(c.Request.Context(), c.Request.Header)
+	if err != nil {
+		respondError(c, "Failed to replay request", err)
+		return
+	}
+	if req.TenantID != nil {
+		httpReq.Header.Set(h.actAsHeader, strconv.FormatInt(*req.TenantID, 10))
+	}
+
+	recorder := httptest.NewRecorder()
+	h.router.ServeHTTP(recorder, httpReq)
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Message: "Request replayed",
+		Data: ReplayResponse{
+			RequestID: req.ID,
+			Status:    recorder.Code,
+			Body:      recorder.Body.String(),
+		},
+	})
+}
+
+// loadRequest loads the rejected request named by the id parameter
+// It writes the error response and returns false when it cannot be loaded
+func (h *Handler) loadRequest(c *gin.Context) (*replay.Request, bool) {
+	if !h.enabled(c) {
+		return nil, false
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Error:   "Invalid request: rejected request id must be a positive integer",
+			Code:    apperr.CodeInvalidRequest,
+		})
+		return nil, false
+	}
+
+	req, err := h.replayService.GetRequest(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, "Failed to retrieve rejected request", err)
+		return nil, false
+	}
+
+	return req, true
+}
+
+// enabled writes a 404 response and returns false when requests are not captured
+func (h *Handler) enabled(c *gin.Context) bool {
+	if h.replayService != nil {
+		return true
+	}
+
+	c.JSON(http.StatusNotFound, ErrorResponse{
+		Success: false,
+		Error:   "Rejected requests are not captured, set REQUEST_CAPTURE_RETENTION",
+		Code:    apperr.CodeNotFound,
+	})
+	return false
+}
+
+// respondError records err for the error middleware, which answers it with the status and code of its kind
+func respondError(c *gin.Context, prefix string, err error) {
+	_ = c.Error(err).SetMeta(prefix)
+}
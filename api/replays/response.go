@@ -0,0 +1,81 @@
+package replays
+
+import (
+	"qubit/pkg/jsonfmt"
+	"qubit/service/replay"
+)
+
+// RejectedRequestResponse represents a request rejected as invalid, with its credentials redacted
+type RejectedRequestResponse struct {
+	ID            int64             `json:"id"`
+	Method        string            `json:"method"`
+	Path          string            `json:"path"`
+	Headers       map[string]string `json:"headers"`
+	Body          string            `json:"body"`
+	BodyTruncated bool              `json:"bodyTruncated"`
+	Status        int               `json:"status"`
+	Error         string            `json:"error"`
+	APIKeyID      *int64            `json:"apiKeyId"`
+	TenantID      *int64            `json:"tenantId"`
+	CreatedAt     jsonfmt.Time      `json:"createdAt"`
+}
+
+// ReplayResponse represents the outcome of replaying a rejected request
+type ReplayResponse struct {
+	RequestID int64  `json:"requestId"`
+	Status    int    `json:"status"`
+	Body      string `json:"body"`
+}
+
+// SuccessResponse represents a generic success response
+type SuccessResponse struct {
+	Success bool        `json:"success"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+// ErrorResponse represents an error response
+type ErrorResponse struct {
+	Success bool   `json:"success"`
+	Error   string `json:"error"`
+	Code    string `json:"code"`
+}
+
+// RejectedRequestListResponse represents a list of rejected requests
+type RejectedRequestListResponse struct {
+	Success  bool                      `json:"success"`
+	Count    int                       `json:"count"`
+	Requests []RejectedRequestResponse `json:"requests"`
+}
+
+// ToRejectedRequestResponse converts a domain replay.Request to RejectedRequestResponse
+func ToRejectedRequestResponse(r *replay.Request) RejectedRequestResponse {
+	headers := r.Headers
+	if headers == nil {
+		headers = map[string]string{}
+	}
+
+	return RejectedRequestResponse{
+		ID:            r.ID,
+		Method:        r.Method,
+		Path:          r.Path,
+		Headers:       headers,
+		Body:          r.Body,
+		BodyTruncated: r.BodyTruncated,
+		Status:        r.Status,
+		Error:         r.Error,
+		APIKeyID:      r.APIKeyID,
+		TenantID:      r.TenantID,
+		CreatedAt:     jsonfmt.NewTime(r.CreatedAt),
+	}
+}
+
+// ToRejectedRequestResponseList converts a slice of domain requests to RejectedRequestResponse slice
+func ToRejectedRequestResponseList(requests []*replay.Request) []RejectedRequestResponse {
+	responses := make([]RejectedRequestResponse, 0, len(requests))
+	for _, r := range requests {
+		responses = append(responses, ToRejectedRequestResponse(r))
+	}
+
+	return responses
+}
@@ -1,15 +1,115 @@
 package api
 
 import (
+	"net/http"
+
 	"github.com/gin-gonic/gin"
 
-	"qubit/api/messages"
+	anomaliesapi "qubit/api/anomalies"
+	apikeysapi "qubit/api/apikeys"
+	archiveapi "qubit/api/archive"
+	backlogalertapi "qubit/api/backlogalert"
+	campaignsapi "qubit/api/campaigns"
+	configreloadapi "qubit/api/configreload"
+	diagnosticsapi "qubit/api/diagnostics"
+	errorsummaryapi "qubit/api/errorsummary"
+	fakesenderapi "qubit/api/fakesender"
+	healthapi "qubit/api/health"
+	inboundapi "qubit/api/inbound"
+	maintenanceapi "qubit/api/maintenance"
+	messagesapi "qubit/api/messages"
+	providersapi "qubit/api/providers"
+	providertemplatesapi "qubit/api/providertemplates"
+	replaysapi "qubit/api/replays"
+	routingapi "qubit/api/routing"
+	templatesapi "qubit/api/templates"
+	tenantsapi "qubit/api/tenants"
+	"qubit/env/mirror"
+	"qubit/env/postgres"
+	"qubit/env/provider"
+	"qubit/pkg/buildinfo"
+	"qubit/pkg/jsonfmt"
+	"qubit/pkg/openapi"
+	"qubit/pkg/ratelimit"
+	"qubit/service/anomaly"
+	"qubit/service/apikey"
+	"qubit/service/backlogalert"
+	"qubit/service/campaign"
+	"qubit/service/configreload"
+	"qubit/service/errorstats"
+	"qubit/service/fakesender"
+	"qubit/service/health"
+	"qubit/service/maintenance"
 	"qubit/service/message"
+	"qubit/service/providertemplate"
+	"qubit/service/replay"
+	"qubit/service/template"
+	"qubit/service/tenant"
 )
 
+// ApproverRole is the role required to approve or reject campaigns
+const ApproverRole = "approver"
+
+// AdminRole is the role required for administrative endpoints
+const AdminRole = "admin"
+
+// RouterDeps are the services and clients the routes are served by
+type RouterDeps struct {
+	MessageService          *message.Service
+	CampaignService         *campaign.Service
+	APIKeyService           *apikey.Service
+	TemplateService         *template.Service
+	MaintenanceService      *maintenance.Service
+	TenantService           *tenant.Service
+	HealthService           *health.Service
+	ReplayService           *replay.Service   // nil unless rejected requests are captured
+	RateLimiter             ratelimit.Limiter // nil disables the rate limit
+	PostgresClient          *postgres.Client
+	Providers               *provider.Registry
+	Mirror                  *mirror.Client   // nil unless created messages are mirrored to staging
+	AnomalyService          *anomaly.Service // nil unless tenant volume spikes are detected
+	ProviderTemplateService *providertemplate.Service
+	ErrorStats              *errorstats.Service
+	FakeSenderService       *fakesender.Service
+	BacklogAlertService     *backlogalert.Service // nil unless a backlog alert webhook is configured
+	ConfigReloadService     *configreload.Service
+}
+
+// RouterOptions configure the routes
+type RouterOptions struct {
+	APIKeysRequired   bool // rejects requests without an API key
+	ProcessingHeaders bool // adds X-Queue-Depth, X-Estimated-Dispatch and X-RateLimit-Remaining to POST /messages responses
+	InstanceID        string
+	PIIUnmaskedRoles  []string // roles that see phone numbers unmasked in list responses
+	ListMaxItems      int      // cap of non-streamed message and inbound lists
+}
+
 // SetupRouter creates and configures the Gin router
-func SetupRouter(messageService *message.Service) *gin.Engine {
-	messagesHandler := messages.NewHandler(messageService)
+// It panics when a response DTO has a JSON field that is not lowerCamelCase
+// Every route is documented in the OpenAPI spec served at /openapi.json
+func SetupRouter(deps RouterDeps, opts RouterOptions) *gin.Engine {
+	if err := jsonfmt.CheckFieldNames(responseTypes...); err != nil {
+		panic("inconsistent response field naming: " + err.Error())
+	}
+
+	messagesHandler := messagesapi.NewHandler(deps.MessageService, opts.ProcessingHeaders, opts.ListMaxItems)
+	inboundHandler := inboundapi.NewHandler(deps.MessageService, opts.ListMaxItems)
+	providersHandler := providersapi.NewHandler(deps.Providers)
+	providerTemplatesHandler := providertemplatesapi.NewHandler(deps.ProviderTemplateService)
+	routingHandler := routingapi.NewHandler(deps.MessageService)
+	archiveHandler := archiveapi.NewHandler(deps.MessageService)
+	errorSummaryHandler := errorsummaryapi.NewHandler(deps.ErrorStats)
+	campaignsHandler := campaignsapi.NewHandler(deps.CampaignService)
+	diagnosticsHandler := diagnosticsapi.NewHandler(deps.PostgresClient, deps.Providers)
+	apiKeysHandler := apikeysapi.NewHandler(deps.APIKeyService)
+	templatesHandler := templatesapi.NewHandler(deps.TemplateService)
+	maintenanceHandler := maintenanceapi.NewHandler(deps.MaintenanceService)
+	fakeSenderHandler := fakesenderapi.NewHandler(deps.FakeSenderService)
+	backlogAlertHandler := backlogalertapi.NewHandler(deps.BacklogAlertService)
+	configReloadHandler := configreloadapi.NewHandler(deps.ConfigReloadService)
+	tenantsHandler := tenantsapi.NewHandler(deps.TenantService)
+	anomaliesHandler := anomaliesapi.NewHandler(deps.AnomalyService)
+	healthHandler := healthapi.NewHandler(deps.HealthService, opts.InstanceID)
 
 	// Set Gin to release mode for production
 	// gin.SetMode(gin.ReleaseMode)
@@ -17,36 +117,203 @@ func SetupRouter(messageService *message.Service) *gin.Engine {
 	// Create router
 	router := gin.New()
 
+	replaysHandler := replaysapi.NewHandler(deps.ReplayService, router, ActAsTenantHeader)
+
 	// Apply global middleware
 	router.Use(Recovery())
 	router.Use(Logger())
 	router.Use(CORS())
 
-	// Health check endpoint
-	router.GET("/health", func(c *gin.Context) {
+	spec := openapi.New("Qubit Message Service API", buildinfo.Version, "Schedules and sends SMS messages through the configured providers")
+	spec.AddAPIKeyScheme(apiKeyScheme, APIKeyHeader)
+	public := &routes{group: &router.RouterGroup, spec: spec}
+
+	// Health check endpoints, /health only reports the process and does not probe dependencies
+	public.GET("/health/live", healthapi.LiveOperation, healthHandler.Live)
+	public.GET("/health/ready", healthapi.ReadyOperation, healthHandler.Ready)
+	public.GET("/health", healthOperation, func(c *gin.Context) {
 		c.JSON(200, gin.H{
-			"status":  "healthy",
-			"service": "qubit-message-service",
+			"status":      "healthy",
+			"service":     "qubit-message-service",
+			"version":     buildinfo.Version,
+			"instance":    opts.InstanceID,
+			"maintenance": maintenanceapi.ToModeResponse(deps.MaintenanceService.Mode()),
+			"fakeSender":  fakesenderapi.ToModeResponse(deps.FakeSenderService.Mode()),
 		})
 	})
 
 	// API v1 group
-	v1 := router.Group("/api/v1")
+	v1Group := router.Group("/api/v1")
+	v1Group.Use(APIKeyAuth(deps.APIKeyService, opts.APIKeysRequired))
+	v1Group.Use(ActAsTenant(deps.TenantService))
+	v1Group.Use(MaskPII(opts.PIIUnmaskedRoles))
+	v1Group.Use(ReadOnly(deps.MaintenanceService))
+	v1Group.Use(RecordErrors(deps.ErrorStats))
+	v1Group.Use(CaptureRejected(deps.ReplayService))
+	// Innermost, so the middleware above sees the status of errors recorded by handlers
+	v1Group.Use(HandleErrors())
+	v1 := &routes{group: v1Group, spec: spec}
+	if opts.APIKeysRequired {
+		v1.security = []string{apiKeyScheme}
+	}
 	{
 		// Message endpoints
-		messages := v1.Group("/messages")
+		messages := v1.Group("/messages", RequireReadWriteScope(apikey.ScopeMessagesRead, apikey.ScopeMessagesWrite))
+		{
+			messages.GET("/", messagesapi.GetSentMessagesOperation, messagesHandler.GetSentMessages)
+			messages.POST("", messagesapi.CreateMessageOperation, RateLimit(deps.RateLimiter), TenantThrottle(deps.AnomalyService), Mirror(deps.Mirror), messagesHandler.CreateMessage)
+			messages.GET("/stream", messagesapi.GetStreamOperation, messagesHandler.GetStream)
+			messages.GET("/:id", messagesapi.GetMessageOperation, messagesHandler.GetMessage)
+			messages.PUT("/:id", messagesapi.UpsertMessageOperation, RateLimit(deps.RateLimiter), TenantThrottle(deps.AnomalyService), messagesHandler.UpsertMessage)
+			messages.DELETE("/:id", messagesapi.CancelMessageOperation, messagesHandler.CancelMessage)
+			messages.GET("/:id/attempts", messagesapi.GetAttemptsOperation, messagesHandler.GetAttempts)
+			messages.GET("/:id/delivery", messagesapi.GetDeliveryOperation, messagesHandler.GetDelivery)
+			messages.GET("/:id/timeline", messagesapi.GetTimelineOperation, messagesHandler.GetTimeline)
+		}
+
+		// Fan-out endpoints
+		v1.GET("/fanouts/:id", messagesapi.GetFanoutOperation, RequireScope(apikey.ScopeMessagesRead), messagesHandler.GetFanout)
+
+		// Attempt endpoints
+		v1.GET("/attempts/stats", messagesapi.GetAttemptStatsOperation, RequireScope(apikey.ScopeMessagesRead), messagesHandler.GetAttemptStats)
+
+		// Live statistics endpoints
+		v1.GET("/stats", messagesapi.GetStatsOperation, RequireScope(apikey.ScopeAdmin), RequireRole(AdminRole), messagesHandler.GetStats)
+		v1.GET("/stats/live", messagesapi.GetLiveStatsOperation, RequireScope(apikey.ScopeMessagesRead), messagesHandler.GetLiveStats)
+
+		// Error summary endpoints
+		v1.GET("/errors/summary", errorsummaryapi.GetSummaryOperation, RequireScope(apikey.ScopeAdmin), RequireRole(AdminRole), errorSummaryHandler.GetSummary)
+
+		// Archive endpoints
+		v1.POST("/archive/redrive", archiveapi.RedriveOperation, RequireScope(apikey.ScopeAdmin), RequireRole(AdminRole), archiveHandler.Redrive)
+
+		// Inbound reply endpoints
+		inbound := v1.Group("/inbound", RequireReadWriteScope(apikey.ScopeMessagesRead, apikey.ScopeMessagesWrite))
+		{
+			inbound.GET("", inboundapi.GetInboundMessagesOperation, inboundHandler.GetInboundMessages)
+			inbound.POST("", inboundapi.ReceiveReplyOperation, inboundHandler.ReceiveReply)
+		}
+
+		// Campaign endpoints
+		campaigns := v1.Group("/campaigns", RequireReadWriteScope(apikey.ScopeMessagesRead, apikey.ScopeMessagesWrite))
+		{
+			campaigns.GET("", campaignsapi.GetCampaignsOperation, campaignsHandler.GetCampaigns)
+			campaigns.POST("", campaignsapi.CreateCampaignOperation, campaignsHandler.CreateCampaign)
+			campaigns.GET("/:id", campaignsapi.GetCampaignOperation, campaignsHandler.GetCampaign)
+			campaigns.GET("/:id/stats", campaignsapi.GetStatsOperation, campaignsHandler.GetStats)
+			campaigns.POST("/:id/submit", campaignsapi.SubmitOperation, campaignsHandler.Submit)
+			campaigns.POST("/:id/approve", campaignsapi.ApproveOperation, RequireRole(ApproverRole), campaignsHandler.Approve)
+			campaigns.POST("/:id/reject", campaignsapi.RejectOperation, RequireRole(ApproverRole), campaignsHandler.Reject)
+			campaigns.POST("/:id/schedule", campaignsapi.ScheduleOperation, campaignsHandler.Schedule)
+		}
+
+		// Template endpoints
+		templates := v1.Group("/templates", RequireReadWriteScope(apikey.ScopeMessagesRead, apikey.ScopeMessagesWrite))
+		{
+			templates.GET("", templatesapi.GetTemplatesOperation, templatesHandler.GetTemplates)
+			templates.POST("", templatesapi.CreateTemplateOperation, templatesHandler.CreateTemplate)
+			templates.GET("/:id", templatesapi.GetTemplateOperation, templatesHandler.GetTemplate)
+		}
+
+		// Provider endpoints, the configuration exposes provider endpoints and is limited to admins
+		v1.GET("/providers", providersapi.GetProvidersOperation, RequireScope(apikey.ScopeAdmin), RequireRole(AdminRole), providersHandler.GetProviders)
+		v1.GET("/provider-templates", providertemplatesapi.GetTemplatesOperation, RequireScope(apikey.ScopeMessagesRead), providerTemplatesHandler.GetTemplates)
+		v1.POST("/provider-templates/sync", providertemplatesapi.SyncOperation, RequireScope(apikey.ScopeAdmin), providerTemplatesHandler.Sync)
+
+		// Routing endpoints, the caller role is not required so pins can be simulated for other roles
+		v1.POST("/routing/simulate", routingapi.SimulateOperation, RequireScope(apikey.ScopeAdmin), routingHandler.Simulate)
+
+		// Diagnostics endpoints
+		diagnostics := v1.Group("/diagnostics", RequireScope(apikey.ScopeAdmin))
+		{
+			diagnostics.GET("/webhook", diagnosticsapi.GetWebhookOperation, diagnosticsHandler.GetWebhook)
+			diagnostics.GET("/schema", diagnosticsapi.GetSchemaOperation, RequireRole(AdminRole), diagnosticsHandler.GetSchema)
+			diagnostics.GET("/in-flight", messagesapi.GetInFlightOperation, RequireRole(AdminRole), messagesHandler.GetInFlight)
+			diagnostics.POST("/canary", healthapi.RunCanaryOperation, RequireRole(AdminRole), healthHandler.RunCanary)
+			diagnostics.GET("/rejected-requests", replaysapi.GetRequestsOperation, RequireRole(AdminRole), replaysHandler.GetRequests)
+			diagnostics.GET("/rejected-requests/:id", replaysapi.GetRequestOperation, RequireRole(AdminRole), replaysHandler.GetRequest)
+			diagnostics.POST("/rejected-requests/:id/replay", replaysapi.ReplayOperation, RequireRole(AdminRole), replaysHandler.Replay)
+		}
+
+		// Maintenance endpoints
+		maintenance := v1.Group("/maintenance", RequireScope(apikey.ScopeAdmin), RequireRole(AdminRole))
 		{
-			messages.GET("/", messagesHandler.GetSentMessages)
-			messages.POST("", messagesHandler.CreateMessage)
+			maintenance.GET("", maintenanceapi.GetModeOperation, maintenanceHandler.GetMode)
+			maintenance.PUT("", maintenanceapi.SetModeOperation, maintenanceHandler.SetMode)
+		}
+
+		// Admin endpoints acting on the instance serving the request
+		admin := v1.Group("/admin", RequireScope(apikey.ScopeAdmin), RequireRole(AdminRole))
+		{
+			admin.POST("/config/reload", configreloadapi.ReloadOperation, configReloadHandler.Reload)
+		}
+
+		// Fake sender endpoints, switching every instance between the simulated sender and the real providers
+		fakeSender := v1.Group("/fake-sender", RequireScope(apikey.ScopeAdmin), RequireRole(AdminRole))
+		{
+			fakeSender.GET("", fakesenderapi.GetModeOperation, fakeSenderHandler.GetMode)
+			fakeSender.PUT("", fakesenderapi.SetModeOperation, fakeSenderHandler.SetMode)
+		}
+
+		// Backlog alerting endpoints, the thresholds apply to every instance
+		backlogAlert := v1.Group("/backlog-alert", RequireScope(apikey.ScopeAdmin), RequireRole(AdminRole))
+		{
+			backlogAlert.GET("", backlogalertapi.GetAlertOperation, backlogAlertHandler.GetAlert)
+			backlogAlert.PUT("", backlogalertapi.SetThresholdsOperation, backlogAlertHandler.SetThresholds)
 		}
 
 		// Scheduler endpoints
-		scheduler := v1.Group("/scheduler")
+		scheduler := v1.Group("/scheduler", RequireScope(apikey.ScopeSchedulerManage))
 		{
-			scheduler.POST("/start", messagesHandler.Start)
-			scheduler.POST("/stop", messagesHandler.Stop)
+			scheduler.POST("/start", messagesapi.StartOperation, messagesHandler.Start)
+			scheduler.POST("/stop", messagesapi.StopOperation, messagesHandler.Stop)
+			scheduler.POST("/reset", messagesapi.ResetOperation, messagesHandler.Reset)
+			scheduler.POST("/pause", messagesapi.PauseOperation, messagesHandler.Pause)
+			scheduler.POST("/resume", messagesapi.ResumeOperation, messagesHandler.Resume)
+			scheduler.POST("/trigger", messagesapi.TriggerOperation, messagesHandler.Trigger)
+			scheduler.GET("/events", messagesapi.GetProgressOperation, messagesHandler.GetProgress)
+			scheduler.GET("/status", messagesapi.GetStatusOperation, messagesHandler.GetStatus)
+			scheduler.GET("/runs", messagesapi.GetRunsOperation, messagesHandler.GetRuns)
 		}
+
+		// API key management endpoints, always require an admin key
+		apiKeys := v1.authenticated().Group("/api-keys", RequireAPIKey(apikey.ScopeAdmin))
+		{
+			apiKeys.GET("", apikeysapi.GetKeysOperation, apiKeysHandler.GetKeys)
+			apiKeys.POST("", apikeysapi.CreateKeyOperation, apiKeysHandler.CreateKey)
+			apiKeys.DELETE("/:id", apikeysapi.RevokeKeyOperation, apiKeysHandler.RevokeKey)
+		}
+
+		// Tenant onboarding endpoints, always require an admin key
+		tenants := v1.authenticated().Group("/tenants", RequireAPIKey(apikey.ScopeAdmin))
+		{
+			tenants.GET("", tenantsapi.GetTenantsOperation, tenantsHandler.GetTenants)
+			tenants.POST("", tenantsapi.OnboardOperation, tenantsHandler.Onboard)
+			tenants.GET("/:id", tenantsapi.GetTenantOperation, tenantsHandler.GetTenant)
+			tenants.GET("/:id/impersonations", tenantsapi.GetImpersonationsOperation, tenantsHandler.GetImpersonations)
+			tenants.GET("/anomalies", anomaliesapi.GetAlertsOperation, anomaliesHandler.GetAlerts)
+			tenants.POST("/:id/anomaly/acknowledge", anomaliesapi.AcknowledgeOperation, anomaliesHandler.Acknowledge)
+		}
+
+		// Callback signing keys of the tenant of the API key
+		signingKeys := v1.authenticated()
+		signingKeys.GET("/signing-keys", tenantsapi.GetSigningKeysOperation, RequireAPIKey(apikey.ScopeMessagesRead), tenantsHandler.GetSigningKeys)
+		signingKeys.POST("/signing-keys", tenantsapi.RotateSigningKeyOperation, RequireAPIKey(apikey.ScopeMessagesWrite), tenantsHandler.RotateSigningKey)
+
+		// Default metadata of the tenant of the API key, merged into the messages it creates
+		defaultMetadata := v1.authenticated()
+		defaultMetadata.GET("/default-metadata", tenantsapi.GetDefaultMetadataOperation, RequireAPIKey(apikey.ScopeMessagesRead), tenantsHandler.GetDefaultMetadata)
+		defaultMetadata.PUT("/default-metadata", tenantsapi.SetDefaultMetadataOperation, RequireAPIKey(apikey.ScopeMessagesWrite), tenantsHandler.SetDefaultMetadata)
 	}
 
+	// The spec is complete once every route is registered
+	specJSON, err := spec.MarshalJSON()
+	if err != nil {
+		panic("failed to render the OpenAPI spec: " + err.Error())
+	}
+	router.GET("/openapi.json", func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/json", specJSON)
+	})
+
 	return router
 }
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"qubit/pkg/openapi"
+)
+
+// apiKeyScheme is the OpenAPI security scheme of routes behind APIKeyAuth
+const apiKeyScheme = "apiKey"
+
+// routes registers handlers on a router group and documents each one in the OpenAPI spec
+// A route cannot be added without its operation, so the spec lists exactly the routes served
+type routes struct {
+	group    *gin.RouterGroup
+	spec     *openapi.Spec
+	security []string
+}
+
+// Group creates a group of routes under path, sharing the middleware handlers
+func (r *routes) Group(path string, handlers ...gin.HandlerFunc) *routes {
+	return &routes{
+		group:    r.group.Group(path, handlers...),
+		spec:     r.spec,
+		security: r.security,
+	}
+}
+
+// authenticated returns the same routes documented as always requiring an API key, for groups behind RequireAPIKey
+func (r *routes) authenticated() *routes {
+	return &routes{
+		group:    r.group,
+		spec:     r.spec,
+		security: []string{apiKeyScheme},
+	}
+}
+
+// GET registers a GET route documented by op
+func (r *routes) GET(path string, op openapi.Operation, handlers ...gin.HandlerFunc) {
+	r.handle(http.MethodGet, path, op, handlers)
+}
+
+// POST registers a POST route documented by op
+func (r *routes) POST(path string, op openapi.Operation, handlers ...gin.HandlerFunc) {
+	r.handle(http.MethodPost, path, op, handlers)
+}
+
+// PUT registers a PUT route documented by op
+func (r *routes) PUT(path string, op openapi.Operation, handlers ...gin.HandlerFunc) {
+	r.handle(http.MethodPut, path, op, handlers)
+}
+
+// DELETE registers a DELETE route documented by op
+func (r *routes) DELETE(path string, op openapi.Operation, handlers ...gin.HandlerFunc) {
+	r.handle(http.MethodDelete, path, op, handlers)
+}
+
+func (r *routes) handle(method, path string, op openapi.Operation, handlers []gin.HandlerFunc) {
+	r.group.Handle(method, path, handlers...)
+
+	op.Security = r.security
+	r.spec.Add(method, joinPath(r.group.BasePath(), path), op)
+}
+
+// joinPath joins a group base path and a route path the way gin does, keeping a trailing slash of path
+func joinPath(base, path string) string {
+	if path == "" {
+		return base
+	}
+	if base == "/" {
+		return path
+	}
+	return base + path
+}
+
+// healthOperation documents the inline GET /health handler
+var healthOperation = openapi.Operation{
+	Summary:     "Health check",
+	Description: "Reports the build version, instance ID and maintenance mode and fake sender mode, with a banner while messages are simulated, without probing any dependency",
+	Tags:        []string{"Health"},
+	Responses: []openapi.Response{
+		{Status: http.StatusOK, Body: gin.H{}},
+	},
+}
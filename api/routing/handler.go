@@ -0,0 +1,84 @@
+package routing
+
+import (
+	"net/http"
+
+	"qubit/pkg/apperr"
+	"qubit/pkg/openapi"
+	"qubit/service/apikey"
+	"qubit/service/message"
+
+	"github.com/gin-gonic/gin"
+)
+
+// userRoleHeader carries the caller role checked against provider override roles
+const userRoleHeader = "X-User-Role"
+
+// Handler handles routing diagnostics HTTP requests
+type Handler struct {
+	messageService *message.Service
+}
+
+// NewHandler creates a new routing handler
+func NewHandler(messageService *message.Service) *Handler {
+	return &Handler{
+		messageService: messageService,
+	}
+}
+
+// SimulateOperation documents Simulate in the OpenAPI spec
+var SimulateOperation = openapi.Operation{
+	Summary: "Simulate the routing of a message",
+	Description: "Returns the provider a message to the phone number would go through and why, whether the provider's circuit is open " +
+		"or it is throttled, the send rate and the recipient limit bucket of the number, without creating a message",
+	Tags: []string{"Routing"},
+	Body: SimulateRequest{},
+	Responses: []openapi.Response{
+		{Status: http.StatusOK, Body: SuccessResponse{}},
+		{Status: http.StatusBadRequest, Body: ErrorResponse{}},
+		{Status: http.StatusForbidden, Body: ErrorResponse{}},
+		{Status: http.StatusInternalServerError, Body: ErrorResponse{}},
+	},
+}
+
+// Simulate handles POST /routing/simulate
+func (h *Handler) Simulate(c *gin.Context) {
+	var req SimulateRequest
+
+	// Bind and validate request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Error:   "Invalid request: " + err.Error(),
+			Code:    apperr.CodeInvalidRequest,
+		})
+		return
+	}
+
+	// The pin is checked as the caller, with a sandbox key routing like a test message
+	opts := message.CreateOptions{
+		Provider:      req.Provider,
+		CallerRole:    c.GetHeader(userRoleHeader),
+		Transactional: req.Transactional,
+	}
+	if key, ok := apikey.FromContext(c.Request.Context()); ok {
+		opts.IsTest = key.IsTest
+	}
+
+	decision, err := h.messageService.SimulateRouting(c.Request.Context(), req.PhoneNumber, opts)
+	if err != nil {
+		respondError(c, "Failed to simulate routing", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Message: "Routing simulated successfully",
+		Data:    ToRoutingDecisionResponse(decision),
+	})
+}
+
+// respondError records err for the error middleware, which answers it with the status and code of its kind
+func respondError(c *gin.Context, prefix string, err error) {
+	_ = c.Error(err).SetMeta(prefix)
+}
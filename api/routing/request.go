@@ -0,0 +1,10 @@
+package routing
+
+// SimulateRequest describes the message whose routing is simulated
+type SimulateRequest struct {
+	PhoneNumber string `json:"phoneNumber" binding:"required"`
+	// Provider simulates a pinned message, empty routes like a message without a pin
+	Provider string `json:"provider" binding:"omitempty,max=100"`
+	// Transactional messages are exempt from the per-recipient limit
+	Transactional bool `json:"transactional"`
+}
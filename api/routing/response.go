@@ -0,0 +1,68 @@
+package routing
+
+import (
+	"qubit/pkg/jsonfmt"
+	"qubit/service/message"
+)
+
+// RoutingDecisionResponse represents how a message would be dispatched right now
+type RoutingDecisionResponse struct {
+	PhoneNumber       string                   `json:"phoneNumber"`
+	Provider          string                   `json:"provider"`
+	Reason            string                   `json:"reason"`
+	CircuitOpen       bool                     `json:"circuitOpen"`
+	ThrottledUntil    *jsonfmt.Time            `json:"throttledUntil"`
+	SendRatePerSecond int                      `json:"sendRatePerSecond"`
+	RecipientLimit    *RecipientBucketResponse `json:"recipientLimit"`
+	SchedulerPaused   bool                     `json:"schedulerPaused"`
+}
+
+// RecipientBucketResponse represents the per-recipient limit of a phone number
+type RecipientBucketResponse struct {
+	Max       int           `json:"max"`
+	Window    string        `json:"window"`
+	Sent      int           `json:"sent"`
+	Exempt    bool          `json:"exempt"`
+	Action    string        `json:"action"`
+	AllowedAt *jsonfmt.Time `json:"allowedAt"`
+}
+
+// SuccessResponse represents a generic success response
+type SuccessResponse struct {
+	Success bool        `json:"success"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+// ErrorResponse represents an error response
+type ErrorResponse struct {
+	Success bool   `json:"success"`
+	Error   string `json:"error"`
+	Code    string `json:"code"`
+}
+
+// ToRoutingDecisionResponse converts a domain message.RoutingDecision to RoutingDecisionResponse
+func ToRoutingDecisionResponse(decision *message.RoutingDecision) RoutingDecisionResponse {
+	resp := RoutingDecisionResponse{
+		PhoneNumber:       decision.PhoneNumber,
+		Provider:          decision.Provider,
+		Reason:            decision.Reason,
+		CircuitOpen:       decision.CircuitOpen,
+		ThrottledUntil:    jsonfmt.NewTimePtr(decision.ThrottledUntil),
+		SendRatePerSecond: decision.SendRatePerSecond,
+		SchedulerPaused:   decision.SchedulerPaused,
+	}
+
+	if bucket := decision.RecipientLimit; bucket != nil {
+		resp.RecipientLimit = &RecipientBucketResponse{
+			Max:       bucket.Max,
+			Window:    bucket.Window.String(),
+			Sent:      bucket.Sent,
+			Exempt:    bucket.Exempt,
+			Action:    bucket.Action,
+			AllowedAt: jsonfmt.NewTimePtr(bucket.AllowedAt),
+		}
+	}
+
+	return resp
+}
@@ -0,0 +1,224 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"net"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"qubit/api/rpc/qubitv1"
+	"qubit/pkg/ctxerr"
+	"qubit/pkg/ratelimit"
+	"qubit/service/anomaly"
+	"qubit/service/apikey"
+	"qubit/service/maintenance"
+)
+
+// Metadata keys read from incoming calls, the gRPC counterparts of the REST headers
+const (
+	apiKeyMetadata   = "x-api-key"
+	userRoleMetadata = "x-user-role"
+)
+
+// methodPolicy is the access rule of a method, mirroring the middleware of its REST route
+type methodPolicy struct {
+	scope apikey.Scope
+	// mutating methods are rejected while maintenance mode is enabled
+	mutating bool
+	// rateLimited methods share the per-key rate limit of the REST API and the throttle of tenants after an anomaly alert
+	rateLimited bool
+}
+
+// methodPolicies lists the policy of every served method, methods without one are denied
+var methodPolicies = map[string]methodPolicy{
+	qubitv1.MessageService_CreateMessage_FullMethodName: {scope: apikey.ScopeMessagesWrite, mutating: true, rateLimited: true},
+	qubitv1.MessageService_GetMessage_FullMethodName:    {scope: apikey.ScopeMessagesRead},
+	qubitv1.MessageService_ListSent_FullMethodName:      {scope: apikey.ScopeMessagesRead},
+
+	qubitv1.SchedulerService_StartScheduler_FullMethodName:     {scope: apikey.ScopeSchedulerManage, mutating: true},
+	qubitv1.SchedulerService_StopScheduler_FullMethodName:      {scope: apikey.ScopeSchedulerManage, mutating: true},
+	qubitv1.SchedulerService_ResetScheduler_FullMethodName:     {scope: apikey.ScopeSchedulerManage, mutating: true},
+	qubitv1.SchedulerService_GetSchedulerStatus_FullMethodName: {scope: apikey.ScopeSchedulerManage},
+}
+
+// guard applies authentication, scopes, maintenance mode and rate limiting to incoming calls
+type guard struct {
+	apiKeyService      *apikey.Service
+	maintenanceService *maintenance.Service
+	apiKeysRequired    bool
+	rateLimiter        ratelimit.Limiter
+	anomalyService     *anomaly.Service // nil when anomaly detection is disabled
+}
+
+// unary guards a unary call
+func (g *guard) unary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, err := g.authorize(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, req)
+}
+
+// stream guards a streaming call
+func (g *guard) stream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := g.authorize(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+
+	return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+}
+
+// authorize checks a call against the policy of its method
+// The returned context carries the authenticated key, like the request context of the REST API
+func (g *guard) authorize(ctx context.Context, method string) (context.Context, error) {
+	policy, ok := methodPolicies[method]
+	if !ok {
+		return nil, status.Errorf(codes.PermissionDenied, "method %s has no access policy", method)
+	}
+
+	key, err := g.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if key != nil {
+		if !key.HasScope(policy.scope) {
+			return nil, status.Errorf(codes.PermissionDenied, "This API key lacks the %s scope", policy.scope)
+		}
+		ctx = apikey.NewContext(ctx, key)
+	}
+
+	if policy.mutating {
+		if mode := g.maintenanceService.Mode(); mode.Enabled {
+			return nil, status.Error(codes.Unavailable, mode.Message)
+		}
+	}
+
+	if policy.rateLimited {
+		if err := g.limit(ctx, key); err != nil {
+			return nil, err
+		}
+		if err := g.throttle(ctx, key); err != nil {
+			return nil, err
+		}
+	}
+
+	return ctx, nil
+}
+
+// authenticate returns the key presented in the call metadata, nil for an unauthenticated call
+// Calls without a key are rejected when keys are required
+func (g *guard) authenticate(ctx context.Context) (*apikey.Key, error) {
+	secret := metadataValue(ctx, apiKeyMetadata)
+	if secret == "" {
+		if g.apiKeysRequired {
+			return nil, status.Errorf(codes.Unauthenticated, "The %s metadata is required", apiKeyMetadata)
+		}
+		return nil, nil
+	}
+
+	key, err := g.apiKeyService.Authenticate(ctx, secret)
+	if err != nil {
+		switch {
+		case ctxerr.IsCanceled(err):
+			return nil, status.FromContextError(err).Err()
+		case errors.Is(err, apikey.ErrInvalidKey):
+			return nil, status.Error(codes.Unauthenticated, "Invalid API key")
+		default:
+			return nil, status.Errorf(codes.Internal, "Failed to authenticate API key: %v", err)
+		}
+	}
+
+	return key, nil
+}
+
+// limit applies the rate limit per API key, or per client IP for unauthenticated calls
+// Calls pass when the limiter fails, a broken limiter must not take the API down
+func (g *guard) limit(ctx context.Context, key *apikey.Key) error {
+	if g.rateLimiter == nil {
+		return nil
+	}
+
+	decision, err := g.rateLimiter.Allow(ctx, rateLimitKey(ctx, key))
+	if err != nil {
+		log.Printf("Warning: rate limiter unavailable: %v", err)
+		return nil
+	}
+
+	if !decision.Allowed {
+		retryAfter := int(math.Ceil(decision.RetryAfter.Seconds()))
+		_ = grpc.SetHeader(ctx, metadata.Pairs("retry-after", strconv.Itoa(max(retryAfter, 1))))
+		return status.Error(codes.ResourceExhausted, "Rate limit exceeded, retry later")
+	}
+
+	return nil
+}
+
+// throttle rejects calls of a tenant throttled after an anomaly alert, like the TenantThrottle middleware
+func (g *guard) throttle(ctx context.Context, key *apikey.Key) error {
+	if g.anomalyService == nil || key == nil || key.TenantID == nil {
+		return nil
+	}
+
+	alert, throttled := g.anomalyService.Throttled(*key.TenantID)
+	if !throttled {
+		return nil
+	}
+
+	retryAfter := int(math.Ceil(time.Until(*alert.ThrottledUntil).Seconds()))
+	_ = grpc.SetHeader(ctx, metadata.Pairs("retry-after", strconv.Itoa(max(retryAfter, 1))))
+	return status.Error(codes.ResourceExhausted, "Tenant throttled after unusual message volume, pending operator acknowledgment")
+}
+
+// rateLimitKey identifies the caller, sharing the buckets of the REST API
+func rateLimitKey(ctx context.Context, key *apikey.Key) string {
+	if key != nil {
+		return "key:" + strconv.FormatInt(key.ID, 10)
+	}
+	return "ip:" + clientIP(ctx)
+}
+
+// clientIP returns the address of the peer without its port
+func clientIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
+
+// metadataValue returns the first value of an incoming metadata key, empty when absent
+func metadataValue(ctx context.Context, key string) string {
+	values := metadata.ValueFromIncomingContext(ctx, key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// contextStream replaces the context of a server stream
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the replaced context
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,120 @@
+package rpc
+
+import (
+	"time"
+
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"qubit/api/rpc/qubitv1"
+	"qubit/service/message"
+)
+
+// statusToProto maps message statuses to their protobuf enum values
+var statusToProto = map[message.Status]qubitv1.MessageStatus{
+	message.StatusPending:     qubitv1.MessageStatus_MESSAGE_STATUS_PENDING,
+	message.StatusSending:     qubitv1.MessageStatus_MESSAGE_STATUS_SENDING,
+	message.StatusSent:        qubitv1.MessageStatus_MESSAGE_STATUS_SENT,
+	message.StatusFailed:      qubitv1.MessageStatus_MESSAGE_STATUS_FAILED,
+	message.StatusCancelled:   qubitv1.MessageStatus_MESSAGE_STATUS_CANCELLED,
+	message.StatusThrottled:   qubitv1.MessageStatus_MESSAGE_STATUS_THROTTLED,
+	message.StatusQuarantined: qubitv1.MessageStatus_MESSAGE_STATUS_QUARANTINED,
+}
+
+// statusFromProto is the inverse of statusToProto
+var statusFromProto = func() map[qubitv1.MessageStatus]message.Status {
+	statuses := make(map[qubitv1.MessageStatus]message.Status, len(statusToProto))
+	for status, value := range statusToProto {
+		statuses[value] = status
+	}
+	return statuses
+}()
+
+// toProtoMessage converts a domain message.Message to its protobuf form
+func toProtoMessage(msg *message.Message) *qubitv1.Message {
+	resp := &qubitv1.Message{
+		Id:          msg.ID,
+		Uuid:        msg.UUID,
+		PhoneNumber: msg.PhoneNumber,
+		Content:     msg.Content,
+		CreatedAt:   timestamppb.New(msg.CreatedAt),
+		MessageId:   msg.MessageID,
+		ProcessedAt: timeToProto(msg.ProcessedAt),
+
+		RetryCount:    int32(msg.RetryCount),
+		NextAttemptAt: timeToProto(msg.NextAttemptAt),
+		Status:        statusToProto[msg.Status],
+		Provider:      msg.Provider,
+		ScheduledAt:   timeToProto(msg.ScheduledAt),
+		IsTest:        msg.IsTest,
+		Transactional: msg.Transactional,
+		FanoutId:      msg.FanoutID,
+		Segments:      toProtoSegments(msg.Segments()),
+	}
+
+	if msg.ExternalRef != nil {
+		ref := msg.ExternalRef.String()
+		resp.ExternalRef = &ref
+	}
+
+	if policy := msg.RetryPolicy; policy != nil {
+		resp.RetryPolicy = &qubitv1.RetryPolicy{
+			MaxAttempts: int32(policy.MaxRetries + 1),
+			Backoff:     string(policy.Backoff),
+			BaseDelay:   durationpb.New(policy.BaseDelay),
+			MaxDelay:    durationpb.New(policy.MaxDelay),
+		}
+	}
+
+	return resp
+}
+
+// toProtoSegments converts the SMS parts of a message to their protobuf form
+func toProtoSegments(segments message.Segments) *qubitv1.Segments {
+	return &qubitv1.Segments{
+		Encoding: string(segments.Encoding),
+		Length:   int32(segments.Length),
+		Count:    int32(segments.Count),
+	}
+}
+
+// toProtoSchedulerStatus converts the domain scheduler status to its protobuf form
+func toProtoSchedulerStatus(status message.SchedulerStatus) *qubitv1.SchedulerStatus {
+	resp := &qubitv1.SchedulerStatus{
+		Running:   status.Running,
+		Interval:  durationpb.New(status.Settings.Interval),
+		BatchSize: int32(status.Settings.BatchSize),
+		Cron:      status.Settings.Cron,
+		Schedule:  status.Schedule,
+		NextRun:   timeToProto(status.NextRun),
+		Standby:   status.Standby,
+	}
+
+	if leadership := status.Leadership; leadership != nil {
+		resp.Leadership = &qubitv1.Leadership{
+			Backend:   leadership.Backend,
+			Leader:    leadership.Leader,
+			Since:     timestamppb.New(leadership.Since),
+			LastError: leadership.LastError,
+		}
+	}
+
+	return resp
+}
+
+// timeToProto converts an optional time, nil stays unset
+func timeToProto(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+// timeFromProto converts an optional timestamp, unset returns nil
+func timeFromProto(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+	t := ts.AsTime()
+	return &t
+}
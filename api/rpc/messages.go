@@ -0,0 +1,148 @@
+package rpc
+
+import (
+	"context"
+	"log"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"qubit/api/rpc/qubitv1"
+	"qubit/pkg/apperr"
+	"qubit/pkg/ctxerr"
+	"qubit/service/apikey"
+	"qubit/service/message"
+)
+
+// messageServer implements qubitv1.MessageServiceServer on top of the message service
+type messageServer struct {
+	qubitv1.UnimplementedMessageServiceServer
+
+	messageService *message.Service
+}
+
+// CreateMessage creates a message to be sent
+func (s *messageServer) CreateMessage(ctx context.Context, req *qubitv1.CreateMessageRequest) (*qubitv1.Message, error) {
+	if req.GetContent() != "" && req.TemplateId != nil {
+		return nil, status.Error(codes.InvalidArgument, "Invalid request: content and template_id are mutually exclusive")
+	}
+
+	opts := message.CreateOptions{
+		Provider:      req.GetProvider(),
+		CallerRole:    metadataValue(ctx, userRoleMetadata),
+		ScheduledAt:   timeFromProto(req.GetScheduledAt()),
+		Transactional: req.GetTransactional(),
+		TemplateID:    req.TemplateId,
+		Variables:     req.GetVariables(),
+		ExternalRef:   req.GetExternalRef(),
+	}
+
+	if policy := req.GetRetryPolicy(); policy != nil {
+		opts.Retry = &message.RetryOverride{
+			MaxAttempts: int(policy.GetMaxAttempts()),
+			Backoff:     message.Backoff(policy.GetBackoff()),
+			BaseDelay:   policy.GetBaseDelay().AsDuration(),
+			MaxDelay:    policy.GetMaxDelay().AsDuration(),
+		}
+	}
+
+	// Messages created with a sandbox key are test messages, those of a tenant key are attributed to the tenant
+	if key, ok := apikey.FromContext(ctx); ok {
+		opts.IsTest = key.IsTest
+		opts.TenantID = key.TenantID
+	}
+
+	msg, err := s.messageService.CreateMessage(ctx, req.GetPhoneNumber(), req.GetContent(), opts)
+	if err != nil {
+		return nil, statusError(err, "Failed to create message")
+	}
+
+	return toProtoMessage(msg), nil
+}
+
+// GetMessage returns a single message by ID
+func (s *messageServer) GetMessage(ctx context.Context, req *qubitv1.GetMessageRequest) (*qubitv1.Message, error) {
+	if req.GetId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "Invalid request: message id must be a positive integer")
+	}
+
+	msg, err := s.messageService.GetMessage(ctx, req.GetId(), tenantScope(ctx))
+	if err != nil {
+		return nil, statusError(err, "Failed to retrieve message")
+	}
+
+	return toProtoMessage(msg), nil
+}
+
+// ListSent streams the messages matching the filters, sent messages unless another status is requested
+func (s *messageServer) ListSent(req *qubitv1.ListSentRequest, stream grpc.ServerStreamingServer[qubitv1.Message]) error {
+	filter := message.ListFilter{
+		Status:        message.StatusSent,
+		PhoneNumber:   req.GetPhoneNumber(),
+		CreatedFrom:   timeFromProto(req.GetCreatedFrom()),
+		CreatedTo:     timeFromProto(req.GetCreatedTo()),
+		ProcessedFrom: timeFromProto(req.GetProcessedFrom()),
+		ProcessedTo:   timeFromProto(req.GetProcessedTo()),
+		Search:        req.GetSearch(),
+		TenantID:      tenantScope(stream.Context()),
+
+		IncludeArchived: req.GetIncludeArchived(),
+	}
+
+	switch {
+	case req.GetAnyStatus() && req.GetStatus() != qubitv1.MessageStatus_MESSAGE_STATUS_UNSPECIFIED:
+		return status.Error(codes.InvalidArgument, "Invalid request: status and any_status are mutually exclusive")
+	case req.GetAnyStatus():
+		filter.Status = ""
+	case req.GetStatus() != qubitv1.MessageStatus_MESSAGE_STATUS_UNSPECIFIED:
+		parsed, ok := statusFromProto[req.GetStatus()]
+		if !ok {
+			return status.Errorf(codes.InvalidArgument, "Invalid request: unknown message status %s", req.GetStatus())
+		}
+		filter.Status = parsed
+	}
+
+	if req.GetExternalRef() != "" {
+		ref, err := message.ParseExternalRef(req.GetExternalRef())
+		if err != nil {
+			return status.Error(codes.InvalidArgument, "Invalid request: "+err.Error())
+		}
+		filter.ExternalRef = ref
+	}
+
+	// Each message is sent as it is read, so the listing is never held in memory
+	err := s.messageService.StreamMessages(stream.Context(), filter, func(msg *message.Message) error {
+		return stream.Send(toProtoMessage(msg))
+	})
+	if err != nil {
+		return statusError(err, "Failed to retrieve messages")
+	}
+
+	return nil
+}
+
+// tenantScope returns the tenant whose messages the call may read, that of a tenant key
+// nil for a key of no tenant, which reaches the messages of every tenant
+func tenantScope(ctx context.Context) *int64 {
+	if key, ok := apikey.FromContext(ctx); ok {
+		return key.TenantID
+	}
+	return nil
+}
+
+// statusError maps a service error to a gRPC status with the code of its apperr kind, the gRPC counterpart of respondError
+// Internal errors are logged and answered with prefix alone
+func statusError(err error, prefix string) error {
+	if ctxerr.IsCanceled(err) {
+		return status.FromContextError(err).Err()
+	}
+
+	typed, ok := apperr.From(err)
+	if !ok {
+		log.Printf("%s: %v", prefix, err)
+		return status.Error(codes.Internal, prefix)
+	}
+
+	return status.Error(typed.Kind.GRPCCode(), prefix+": "+err.Error())
+}
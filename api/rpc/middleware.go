@@ -0,0 +1,71 @@
+package rpc
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// logUnary logs every unary call with its status code and latency
+func logUnary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	startTime := time.Now()
+
+	resp, err := handler(ctx, req)
+
+	logCall(info.FullMethod, err, time.Since(startTime), clientIP(ctx))
+	return resp, err
+}
+
+// logStream logs every streaming call with its status code and latency
+func logStream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	startTime := time.Now()
+
+	err := handler(srv, ss)
+
+	logCall(info.FullMethod, err, time.Since(startTime), clientIP(ss.Context()))
+	return err
+}
+
+// logCall logs a finished call in the format of the HTTP request log
+func logCall(method string, err error, latency time.Duration, clientIP string) {
+	code := status.Code(err)
+
+	log.Printf("[gRPC] %s | Status: %s | Latency: %v | IP: %s",
+		method,
+		code,
+		latency,
+		clientIP,
+	)
+
+	if code == codes.Internal || code == codes.Unknown {
+		log.Printf("Error: %v", err)
+	}
+}
+
+// recoverUnary turns a panic of a unary handler into an Internal error
+func recoverUnary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Panic recovered: %v", r)
+			err = status.Error(codes.Internal, "Internal server error")
+		}
+	}()
+
+	return handler(ctx, req)
+}
+
+// recoverStream turns a panic of a stream handler into an Internal error
+func recoverStream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Panic recovered: %v", r)
+			err = status.Error(codes.Internal, "Internal server error")
+		}
+	}()
+
+	return handler(srv, ss)
+}
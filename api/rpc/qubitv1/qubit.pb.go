@@ -0,0 +1,1256 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        (unknown)
+// source: qubit/v1/qubit.proto
+
+package qubitv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// MessageStatus is the lifecycle state of a message
+type MessageStatus int32
+
+const (
+	MessageStatus_MESSAGE_STATUS_UNSPECIFIED MessageStatus = 0
+	MessageStatus_MESSAGE_STATUS_PENDING     MessageStatus = 1
+	MessageStatus_MESSAGE_STATUS_SENDING     MessageStatus = 2
+	MessageStatus_MESSAGE_STATUS_SENT        MessageStatus = 3
+	MessageStatus_MESSAGE_STATUS_FAILED      MessageStatus = 4
+	MessageStatus_MESSAGE_STATUS_CANCELLED   MessageStatus = 5
+	MessageStatus_MESSAGE_STATUS_THROTTLED   MessageStatus = 6
+	MessageStatus_MESSAGE_STATUS_QUARANTINED MessageStatus = 7
+)
+
+// Enum value maps for MessageStatus.
+var (
+	MessageStatus_name = map[int32]string{
+		0: "MESSAGE_STATUS_UNSPECIFIED",
+		1: "MESSAGE_STATUS_PENDING",
+		2: "MESSAGE_STATUS_SENDING",
+		3: "MESSAGE_STATUS_SENT",
+		4: "MESSAGE_STATUS_FAILED",
+		5: "MESSAGE_STATUS_CANCELLED",
+		6: "MESSAGE_STATUS_THROTTLED",
+		7: "MESSAGE_STATUS_QUARANTINED",
+	}
+	MessageStatus_value = map[string]int32{
+		"MESSAGE_STATUS_UNSPECIFIED": 0,
+		"MESSAGE_STATUS_PENDING":     1,
+		"MESSAGE_STATUS_SENDING":     2,
+		"MESSAGE_STATUS_SENT":        3,
+		"MESSAGE_STATUS_FAILED":      4,
+		"MESSAGE_STATUS_CANCELLED":   5,
+		"MESSAGE_STATUS_THROTTLED":   6,
+		"MESSAGE_STATUS_QUARANTINED": 7,
+	}
+)
+
+func (x MessageStatus) Enum() *MessageStatus {
+	p := new(MessageStatus)
+	*p = x
+	return p
+}
+
+func (x MessageStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (MessageStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_qubit_v1_qubit_proto_enumTypes[0].Descriptor()
+}
+
+func (MessageStatus) Type() protoreflect.EnumType {
+	return &file_qubit_v1_qubit_proto_enumTypes[0]
+}
+
+func (x MessageStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use MessageStatus.Descriptor instead.
+func (MessageStatus) EnumDescriptor() ([]byte, []int) {
+	return file_qubit_v1_qubit_proto_rawDescGZIP(), []int{0}
+}
+
+// Message is a message with its delivery state
+type Message struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Uuid          string                 `protobuf:"bytes,2,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	PhoneNumber   string                 `protobuf:"bytes,3,opt,name=phone_number,json=phoneNumber,proto3" json:"phone_number,omitempty"`
+	Content       string                 `protobuf:"bytes,4,opt,name=content,proto3" json:"content,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	MessageId     *string                `protobuf:"bytes,6,opt,name=message_id,json=messageId,proto3,oneof" json:"message_id,omitempty"`
+	ProcessedAt   *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=processed_at,json=processedAt,proto3" json:"processed_at,omitempty"`
+	RetryCount    int32                  `protobuf:"varint,8,opt,name=retry_count,json=retryCount,proto3" json:"retry_count,omitempty"`
+	NextAttemptAt *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=next_attempt_at,json=nextAttemptAt,proto3" json:"next_attempt_at,omitempty"`
+	Status        MessageStatus          `protobuf:"varint,10,opt,name=status,proto3,enum=qubit.v1.MessageStatus" json:"status,omitempty"`
+	Provider      *string                `protobuf:"bytes,11,opt,name=provider,proto3,oneof" json:"provider,omitempty"`
+	ScheduledAt   *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=scheduled_at,json=scheduledAt,proto3" json:"scheduled_at,omitempty"`
+	IsTest        bool                   `protobuf:"varint,13,opt,name=is_test,json=isTest,proto3" json:"is_test,omitempty"`
+	Transactional bool                   `protobuf:"varint,14,opt,name=transactional,proto3" json:"transactional,omitempty"`
+	FanoutId      *string                `protobuf:"bytes,15,opt,name=fanout_id,json=fanoutId,proto3,oneof" json:"fanout_id,omitempty"`
+	ExternalRef   *string                `protobuf:"bytes,16,opt,name=external_ref,json=externalRef,proto3,oneof" json:"external_ref,omitempty"`
+	// retry_policy is set when the message overrides the configured retry policy
+	RetryPolicy   *RetryPolicy `protobuf:"bytes,17,opt,name=retry_policy,json=retryPolicy,proto3" json:"retry_policy,omitempty"`
+	Segments      *Segments    `protobuf:"bytes,18,opt,name=segments,proto3" json:"segments,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Message) Reset() {
+	*x = Message{}
+	mi := &file_qubit_v1_qubit_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Message) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Message) ProtoMessage() {}
+
+func (x *Message) ProtoReflect() protoreflect.Message {
+	mi := &file_qubit_v1_qubit_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Message.ProtoReflect.Descriptor instead.
+func (*Message) Descriptor() ([]byte, []int) {
+	return file_qubit_v1_qubit_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Message) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Message) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *Message) GetPhoneNumber() string {
+	if x != nil {
+		return x.PhoneNumber
+	}
+	return ""
+}
+
+func (x *Message) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *Message) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Message) GetMessageId() string {
+	if x != nil && x.MessageId != nil {
+		return *x.MessageId
+	}
+	return ""
+}
+
+func (x *Message) GetProcessedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ProcessedAt
+	}
+	return nil
+}
+
+func (x *Message) GetRetryCount() int32 {
+	if x != nil {
+		return x.RetryCount
+	}
+	return 0
+}
+
+func (x *Message) GetNextAttemptAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NextAttemptAt
+	}
+	return nil
+}
+
+func (x *Message) GetStatus() MessageStatus {
+	if x != nil {
+		return x.Status
+	}
+	return MessageStatus_MESSAGE_STATUS_UNSPECIFIED
+}
+
+func (x *Message) GetProvider() string {
+	if x != nil && x.Provider != nil {
+		return *x.Provider
+	}
+	return ""
+}
+
+func (x *Message) GetScheduledAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ScheduledAt
+	}
+	return nil
+}
+
+func (x *Message) GetIsTest() bool {
+	if x != nil {
+		return x.IsTest
+	}
+	return false
+}
+
+func (x *Message) GetTransactional() bool {
+	if x != nil {
+		return x.Transactional
+	}
+	return false
+}
+
+func (x *Message) GetFanoutId() string {
+	if x != nil && x.FanoutId != nil {
+		return *x.FanoutId
+	}
+	return ""
+}
+
+func (x *Message) GetExternalRef() string {
+	if x != nil && x.ExternalRef != nil {
+		return *x.ExternalRef
+	}
+	return ""
+}
+
+func (x *Message) GetRetryPolicy() *RetryPolicy {
+	if x != nil {
+		return x.RetryPolicy
+	}
+	return nil
+}
+
+func (x *Message) GetSegments() *Segments {
+	if x != nil {
+		return x.Segments
+	}
+	return nil
+}
+
+// Segments are the SMS parts the content of a message is sent in
+type Segments struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// encoding is gsm7 or ucs2
+	Encoding string `protobuf:"bytes,1,opt,name=encoding,proto3" json:"encoding,omitempty"`
+	// length counts GSM-7 septets or UCS-2 code units, depending on the encoding
+	Length        int32 `protobuf:"varint,2,opt,name=length,proto3" json:"length,omitempty"`
+	Count         int32 `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Segments) Reset() {
+	*x = Segments{}
+	mi := &file_qubit_v1_qubit_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Segments) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Segments) ProtoMessage() {}
+
+func (x *Segments) ProtoReflect() protoreflect.Message {
+	mi := &file_qubit_v1_qubit_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Segments.ProtoReflect.Descriptor instead.
+func (*Segments) Descriptor() ([]byte, []int) {
+	return file_qubit_v1_qubit_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Segments) GetEncoding() string {
+	if x != nil {
+		return x.Encoding
+	}
+	return ""
+}
+
+func (x *Segments) GetLength() int32 {
+	if x != nil {
+		return x.Length
+	}
+	return 0
+}
+
+func (x *Segments) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+// RetryPolicy is a per-message retry policy, zero fields fall back to the configuration on create
+type RetryPolicy struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	MaxAttempts int32                  `protobuf:"varint,1,opt,name=max_attempts,json=maxAttempts,proto3" json:"max_attempts,omitempty"`
+	// backoff is exponential, linear or fixed
+	Backoff       string               `protobuf:"bytes,2,opt,name=backoff,proto3" json:"backoff,omitempty"`
+	BaseDelay     *durationpb.Duration `protobuf:"bytes,3,opt,name=base_delay,json=baseDelay,proto3" json:"base_delay,omitempty"`
+	MaxDelay      *durationpb.Duration `protobuf:"bytes,4,opt,name=max_delay,json=maxDelay,proto3" json:"max_delay,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RetryPolicy) Reset() {
+	*x = RetryPolicy{}
+	mi := &file_qubit_v1_qubit_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RetryPolicy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RetryPolicy) ProtoMessage() {}
+
+func (x *RetryPolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_qubit_v1_qubit_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RetryPolicy.ProtoReflect.Descriptor instead.
+func (*RetryPolicy) Descriptor() ([]byte, []int) {
+	return file_qubit_v1_qubit_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *RetryPolicy) GetMaxAttempts() int32 {
+	if x != nil {
+		return x.MaxAttempts
+	}
+	return 0
+}
+
+func (x *RetryPolicy) GetBackoff() string {
+	if x != nil {
+		return x.Backoff
+	}
+	return ""
+}
+
+func (x *RetryPolicy) GetBaseDelay() *durationpb.Duration {
+	if x != nil {
+		return x.BaseDelay
+	}
+	return nil
+}
+
+func (x *RetryPolicy) GetMaxDelay() *durationpb.Duration {
+	if x != nil {
+		return x.MaxDelay
+	}
+	return nil
+}
+
+type CreateMessageRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	PhoneNumber string                 `protobuf:"bytes,1,opt,name=phone_number,json=phoneNumber,proto3" json:"phone_number,omitempty"`
+	// content is exclusive with template_id
+	Content string `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
+	// provider pins the message to a configured provider, empty uses routing
+	Provider string `protobuf:"bytes,3,opt,name=provider,proto3" json:"provider,omitempty"`
+	// scheduled_at delays delivery until that moment
+	ScheduledAt *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=scheduled_at,json=scheduledAt,proto3" json:"scheduled_at,omitempty"`
+	// transactional messages are exempt from the per-recipient limit
+	Transactional bool `protobuf:"varint,5,opt,name=transactional,proto3" json:"transactional,omitempty"`
+	// template_id renders the content from a stored template filled in with variables
+	TemplateId  *int64            `protobuf:"varint,6,opt,name=template_id,json=templateId,proto3,oneof" json:"template_id,omitempty"`
+	Variables   map[string]string `protobuf:"bytes,7,rep,name=variables,proto3" json:"variables,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	RetryPolicy *RetryPolicy      `protobuf:"bytes,8,opt,name=retry_policy,json=retryPolicy,proto3" json:"retry_policy,omitempty"`
+	// external_ref links the message to a business object as type:id, e.g. order:12345
+	ExternalRef   string `protobuf:"bytes,9,opt,name=external_ref,json=externalRef,proto3" json:"external_ref,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateMessageRequest) Reset() {
+	*x = CreateMessageRequest{}
+	mi := &file_qubit_v1_qubit_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateMessageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateMessageRequest) ProtoMessage() {}
+
+func (x *CreateMessageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_qubit_v1_qubit_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateMessageRequest.ProtoReflect.Descriptor instead.
+func (*CreateMessageRequest) Descriptor() ([]byte, []int) {
+	return file_qubit_v1_qubit_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CreateMessageRequest) GetPhoneNumber() string {
+	if x != nil {
+		return x.PhoneNumber
+	}
+	return ""
+}
+
+func (x *CreateMessageRequest) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *CreateMessageRequest) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *CreateMessageRequest) GetScheduledAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ScheduledAt
+	}
+	return nil
+}
+
+func (x *CreateMessageRequest) GetTransactional() bool {
+	if x != nil {
+		return x.Transactional
+	}
+	return false
+}
+
+func (x *CreateMessageRequest) GetTemplateId() int64 {
+	if x != nil && x.TemplateId != nil {
+		return *x.TemplateId
+	}
+	return 0
+}
+
+func (x *CreateMessageRequest) GetVariables() map[string]string {
+	if x != nil {
+		return x.Variables
+	}
+	return nil
+}
+
+func (x *CreateMessageRequest) GetRetryPolicy() *RetryPolicy {
+	if x != nil {
+		return x.RetryPolicy
+	}
+	return nil
+}
+
+func (x *CreateMessageRequest) GetExternalRef() string {
+	if x != nil {
+		return x.ExternalRef
+	}
+	return ""
+}
+
+type GetMessageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMessageRequest) Reset() {
+	*x = GetMessageRequest{}
+	mi := &file_qubit_v1_qubit_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMessageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMessageRequest) ProtoMessage() {}
+
+func (x *GetMessageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_qubit_v1_qubit_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMessageRequest.ProtoReflect.Descriptor instead.
+func (*GetMessageRequest) Descriptor() ([]byte, []int) {
+	return file_qubit_v1_qubit_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetMessageRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+// ListSentRequest filters a listing, time ranges include their start and exclude their end
+type ListSentRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// status defaults to sent
+	Status MessageStatus `protobuf:"varint,1,opt,name=status,proto3,enum=qubit.v1.MessageStatus" json:"status,omitempty"`
+	// any_status lists every status, status must then be unspecified
+	AnyStatus     bool                   `protobuf:"varint,2,opt,name=any_status,json=anyStatus,proto3" json:"any_status,omitempty"`
+	PhoneNumber   string                 `protobuf:"bytes,3,opt,name=phone_number,json=phoneNumber,proto3" json:"phone_number,omitempty"`
+	CreatedFrom   *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_from,json=createdFrom,proto3" json:"created_from,omitempty"`
+	CreatedTo     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_to,json=createdTo,proto3" json:"created_to,omitempty"`
+	ProcessedFrom *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=processed_from,json=processedFrom,proto3" json:"processed_from,omitempty"`
+	ProcessedTo   *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=processed_to,json=processedTo,proto3" json:"processed_to,omitempty"`
+	// search is a case-insensitive substring of the content
+	Search string `protobuf:"bytes,8,opt,name=search,proto3" json:"search,omitempty"`
+	// external_ref is written as type:id, e.g. order:12345
+	ExternalRef string `protobuf:"bytes,9,opt,name=external_ref,json=externalRef,proto3" json:"external_ref,omitempty"`
+	// include_archived also lists the sent messages moved to the archive
+	IncludeArchived bool `protobuf:"varint,10,opt,name=include_archived,json=includeArchived,proto3" json:"include_archived,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ListSentRequest) Reset() {
+	*x = ListSentRequest{}
+	mi := &file_qubit_v1_qubit_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSentRequest) ProtoMessage() {}
+
+func (x *ListSentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_qubit_v1_qubit_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSentRequest.ProtoReflect.Descriptor instead.
+func (*ListSentRequest) Descriptor() ([]byte, []int) {
+	return file_qubit_v1_qubit_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListSentRequest) GetStatus() MessageStatus {
+	if x != nil {
+		return x.Status
+	}
+	return MessageStatus_MESSAGE_STATUS_UNSPECIFIED
+}
+
+func (x *ListSentRequest) GetAnyStatus() bool {
+	if x != nil {
+		return x.AnyStatus
+	}
+	return false
+}
+
+func (x *ListSentRequest) GetPhoneNumber() string {
+	if x != nil {
+		return x.PhoneNumber
+	}
+	return ""
+}
+
+func (x *ListSentRequest) GetCreatedFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedFrom
+	}
+	return nil
+}
+
+func (x *ListSentRequest) GetCreatedTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedTo
+	}
+	return nil
+}
+
+func (x *ListSentRequest) GetProcessedFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ProcessedFrom
+	}
+	return nil
+}
+
+func (x *ListSentRequest) GetProcessedTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ProcessedTo
+	}
+	return nil
+}
+
+func (x *ListSentRequest) GetSearch() string {
+	if x != nil {
+		return x.Search
+	}
+	return ""
+}
+
+func (x *ListSentRequest) GetExternalRef() string {
+	if x != nil {
+		return x.ExternalRef
+	}
+	return ""
+}
+
+func (x *ListSentRequest) GetIncludeArchived() bool {
+	if x != nil {
+		return x.IncludeArchived
+	}
+	return false
+}
+
+type StartSchedulerRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Interval  *durationpb.Duration   `protobuf:"bytes,1,opt,name=interval,proto3" json:"interval,omitempty"`
+	BatchSize int32                  `protobuf:"varint,2,opt,name=batch_size,json=batchSize,proto3" json:"batch_size,omitempty"`
+	// cron replaces the interval with a cron expression (seconds first), an empty string clears it
+	Cron          *string `protobuf:"bytes,3,opt,name=cron,proto3,oneof" json:"cron,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StartSchedulerRequest) Reset() {
+	*x = StartSchedulerRequest{}
+	mi := &file_qubit_v1_qubit_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartSchedulerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartSchedulerRequest) ProtoMessage() {}
+
+func (x *StartSchedulerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_qubit_v1_qubit_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartSchedulerRequest.ProtoReflect.Descriptor instead.
+func (*StartSchedulerRequest) Descriptor() ([]byte, []int) {
+	return file_qubit_v1_qubit_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *StartSchedulerRequest) GetInterval() *durationpb.Duration {
+	if x != nil {
+		return x.Interval
+	}
+	return nil
+}
+
+func (x *StartSchedulerRequest) GetBatchSize() int32 {
+	if x != nil {
+		return x.BatchSize
+	}
+	return 0
+}
+
+func (x *StartSchedulerRequest) GetCron() string {
+	if x != nil && x.Cron != nil {
+		return *x.Cron
+	}
+	return ""
+}
+
+type StopSchedulerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StopSchedulerRequest) Reset() {
+	*x = StopSchedulerRequest{}
+	mi := &file_qubit_v1_qubit_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StopSchedulerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StopSchedulerRequest) ProtoMessage() {}
+
+func (x *StopSchedulerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_qubit_v1_qubit_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StopSchedulerRequest.ProtoReflect.Descriptor instead.
+func (*StopSchedulerRequest) Descriptor() ([]byte, []int) {
+	return file_qubit_v1_qubit_proto_rawDescGZIP(), []int{7}
+}
+
+type ResetSchedulerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResetSchedulerRequest) Reset() {
+	*x = ResetSchedulerRequest{}
+	mi := &file_qubit_v1_qubit_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResetSchedulerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResetSchedulerRequest) ProtoMessage() {}
+
+func (x *ResetSchedulerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_qubit_v1_qubit_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResetSchedulerRequest.ProtoReflect.Descriptor instead.
+func (*ResetSchedulerRequest) Descriptor() ([]byte, []int) {
+	return file_qubit_v1_qubit_proto_rawDescGZIP(), []int{8}
+}
+
+type GetSchedulerStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSchedulerStatusRequest) Reset() {
+	*x = GetSchedulerStatusRequest{}
+	mi := &file_qubit_v1_qubit_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSchedulerStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSchedulerStatusRequest) ProtoMessage() {}
+
+func (x *GetSchedulerStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_qubit_v1_qubit_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSchedulerStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetSchedulerStatusRequest) Descriptor() ([]byte, []int) {
+	return file_qubit_v1_qubit_proto_rawDescGZIP(), []int{9}
+}
+
+// SchedulerStatus is the state of the scheduler of the instance serving the call
+type SchedulerStatus struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Running   bool                   `protobuf:"varint,1,opt,name=running,proto3" json:"running,omitempty"`
+	Interval  *durationpb.Duration   `protobuf:"bytes,2,opt,name=interval,proto3" json:"interval,omitempty"`
+	BatchSize int32                  `protobuf:"varint,3,opt,name=batch_size,json=batchSize,proto3" json:"batch_size,omitempty"`
+	Cron      string                 `protobuf:"bytes,4,opt,name=cron,proto3" json:"cron,omitempty"`
+	// schedule is the parsed schedule, e.g. "every 2m0s" or the normalized cron expression
+	Schedule string                 `protobuf:"bytes,5,opt,name=schedule,proto3" json:"schedule,omitempty"`
+	NextRun  *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=next_run,json=nextRun,proto3" json:"next_run,omitempty"`
+	// standby is set while another instance holds the scheduler tick lock or is the elected leader
+	Standby bool `protobuf:"varint,7,opt,name=standby,proto3" json:"standby,omitempty"`
+	// leadership is unset when leader election is disabled
+	Leadership    *Leadership `protobuf:"bytes,8,opt,name=leadership,proto3" json:"leadership,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SchedulerStatus) Reset() {
+	*x = SchedulerStatus{}
+	mi := &file_qubit_v1_qubit_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SchedulerStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SchedulerStatus) ProtoMessage() {}
+
+func (x *SchedulerStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_qubit_v1_qubit_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SchedulerStatus.ProtoReflect.Descriptor instead.
+func (*SchedulerStatus) Descriptor() ([]byte, []int) {
+	return file_qubit_v1_qubit_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *SchedulerStatus) GetRunning() bool {
+	if x != nil {
+		return x.Running
+	}
+	return false
+}
+
+func (x *SchedulerStatus) GetInterval() *durationpb.Duration {
+	if x != nil {
+		return x.Interval
+	}
+	return nil
+}
+
+func (x *SchedulerStatus) GetBatchSize() int32 {
+	if x != nil {
+		return x.BatchSize
+	}
+	return 0
+}
+
+func (x *SchedulerStatus) GetCron() string {
+	if x != nil {
+		return x.Cron
+	}
+	return ""
+}
+
+func (x *SchedulerStatus) GetSchedule() string {
+	if x != nil {
+		return x.Schedule
+	}
+	return ""
+}
+
+func (x *SchedulerStatus) GetNextRun() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NextRun
+	}
+	return nil
+}
+
+func (x *SchedulerStatus) GetStandby() bool {
+	if x != nil {
+		return x.Standby
+	}
+	return false
+}
+
+func (x *SchedulerStatus) GetLeadership() *Leadership {
+	if x != nil {
+		return x.Leadership
+	}
+	return nil
+}
+
+// Leadership is the scheduler leader election state of the serving instance
+type Leadership struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// backend is postgres or redis
+	Backend string `protobuf:"bytes,1,opt,name=backend,proto3" json:"backend,omitempty"`
+	Leader  bool   `protobuf:"varint,2,opt,name=leader,proto3" json:"leader,omitempty"`
+	// since is when the instance became leader or follower
+	Since         *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=since,proto3" json:"since,omitempty"`
+	LastError     *string                `protobuf:"bytes,4,opt,name=last_error,json=lastError,proto3,oneof" json:"last_error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Leadership) Reset() {
+	*x = Leadership{}
+	mi := &file_qubit_v1_qubit_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Leadership) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Leadership) ProtoMessage() {}
+
+func (x *Leadership) ProtoReflect() protoreflect.Message {
+	mi := &file_qubit_v1_qubit_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Leadership.ProtoReflect.Descriptor instead.
+func (*Leadership) Descriptor() ([]byte, []int) {
+	return file_qubit_v1_qubit_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Leadership) GetBackend() string {
+	if x != nil {
+		return x.Backend
+	}
+	return ""
+}
+
+func (x *Leadership) GetLeader() bool {
+	if x != nil {
+		return x.Leader
+	}
+	return false
+}
+
+func (x *Leadership) GetSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Since
+	}
+	return nil
+}
+
+func (x *Leadership) GetLastError() string {
+	if x != nil && x.LastError != nil {
+		return *x.LastError
+	}
+	return ""
+}
+
+var File_qubit_v1_qubit_proto protoreflect.FileDescriptor
+
+const file_qubit_v1_qubit_proto_rawDesc = "" +
+	"\n" +
+	"\x14qubit/v1/qubit.proto\x12\bqubit.v1\x1a\x1egoogle/protobuf/duration.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xac\x06\n" +
+	"\aMessage\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04uuid\x18\x02 \x01(\tR\x04uuid\x12!\n" +
+	"\fphone_number\x18\x03 \x01(\tR\vphoneNumber\x12\x18\n" +
+	"\acontent\x18\x04 \x01(\tR\acontent\x129\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12\"\n" +
+	"\n" +
+	"message_id\x18\x06 \x01(\tH\x00R\tmessageId\x88\x01\x01\x12=\n" +
+	"\fprocessed_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\vprocessedAt\x12\x1f\n" +
+	"\vretry_count\x18\b \x01(\x05R\n" +
+	"retryCount\x12B\n" +
+	"\x0fnext_attempt_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\rnextAttemptAt\x12/\n" +
+	"\x06status\x18\n" +
+	" \x01(\x0e2\x17.qubit.v1.MessageStatusR\x06status\x12\x1f\n" +
+	"\bprovider\x18\v \x01(\tH\x01R\bprovider\x88\x01\x01\x12=\n" +
+	"\fscheduled_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\vscheduledAt\x12\x17\n" +
+	"\ais_test\x18\r \x01(\bR\x06isTest\x12$\n" +
+	"\rtransactional\x18\x0e \x01(\bR\rtransactional\x12 \n" +
+	"\tfanout_id\x18\x0f \x01(\tH\x02R\bfanoutId\x88\x01\x01\x12&\n" +
+	"\fexternal_ref\x18\x10 \x01(\tH\x03R\vexternalRef\x88\x01\x01\x128\n" +
+	"\fretry_policy\x18\x11 \x01(\v2\x15.qubit.v1.RetryPolicyR\vretryPolicy\x12.\n" +
+	"\bsegments\x18\x12 \x01(\v2\x12.qubit.v1.SegmentsR\bsegmentsB\r\n" +
+	"\v_message_idB\v\n" +
+	"\t_providerB\f\n" +
+	"\n" +
+	"_fanout_idB\x0f\n" +
+	"\r_external_ref\"T\n" +
+	"\bSegments\x12\x1a\n" +
+	"\bencoding\x18\x01 \x01(\tR\bencoding\x12\x16\n" +
+	"\x06length\x18\x02 \x01(\x05R\x06length\x12\x14\n" +
+	"\x05count\x18\x03 \x01(\x05R\x05count\"\xbc\x01\n" +
+	"\vRetryPolicy\x12!\n" +
+	"\fmax_attempts\x18\x01 \x01(\x05R\vmaxAttempts\x12\x18\n" +
+	"\abackoff\x18\x02 \x01(\tR\abackoff\x128\n" +
+	"\n" +
+	"base_delay\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\tbaseDelay\x126\n" +
+	"\tmax_delay\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\bmaxDelay\"\xf2\x03\n" +
+	"\x14CreateMessageRequest\x12!\n" +
+	"\fphone_number\x18\x01 \x01(\tR\vphoneNumber\x12\x18\n" +
+	"\acontent\x18\x02 \x01(\tR\acontent\x12\x1a\n" +
+	"\bprovider\x18\x03 \x01(\tR\bprovider\x12=\n" +
+	"\fscheduled_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\vscheduledAt\x12$\n" +
+	"\rtransactional\x18\x05 \x01(\bR\rtransactional\x12$\n" +
+	"\vtemplate_id\x18\x06 \x01(\x03H\x00R\n" +
+	"templateId\x88\x01\x01\x12K\n" +
+	"\tvariables\x18\a \x03(\v2-.qubit.v1.CreateMessageRequest.VariablesEntryR\tvariables\x128\n" +
+	"\fretry_policy\x18\b \x01(\v2\x15.qubit.v1.RetryPolicyR\vretryPolicy\x12!\n" +
+	"\fexternal_ref\x18\t \x01(\tR\vexternalRef\x1a<\n" +
+	"\x0eVariablesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B\x0e\n" +
+	"\f_template_id\"#\n" +
+	"\x11GetMessageRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"\xe6\x03\n" +
+	"\x0fListSentRequest\x12/\n" +
+	"\x06status\x18\x01 \x01(\x0e2\x17.qubit.v1.MessageStatusR\x06status\x12\x1d\n" +
+	"\n" +
+	"any_status\x18\x02 \x01(\bR\tanyStatus\x12!\n" +
+	"\fphone_number\x18\x03 \x01(\tR\vphoneNumber\x12=\n" +
+	"\fcreated_from\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\vcreatedFrom\x129\n" +
+	"\n" +
+	"created_to\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedTo\x12A\n" +
+	"\x0eprocessed_from\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\rprocessedFrom\x12=\n" +
+	"\fprocessed_to\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\vprocessedTo\x12\x16\n" +
+	"\x06search\x18\b \x01(\tR\x06search\x12!\n" +
+	"\fexternal_ref\x18\t \x01(\tR\vexternalRef\x12)\n" +
+	"\x10include_archived\x18\n" +
+	" \x01(\bR\x0fincludeArchived\"\x8f\x01\n" +
+	"\x15StartSchedulerRequest\x125\n" +
+	"\binterval\x18\x01 \x01(\v2\x19.google.protobuf.DurationR\binterval\x12\x1d\n" +
+	"\n" +
+	"batch_size\x18\x02 \x01(\x05R\tbatchSize\x12\x17\n" +
+	"\x04cron\x18\x03 \x01(\tH\x00R\x04cron\x88\x01\x01B\a\n" +
+	"\x05_cron\"\x16\n" +
+	"\x14StopSchedulerRequest\"\x17\n" +
+	"\x15ResetSchedulerRequest\"\x1b\n" +
+	"\x19GetSchedulerStatusRequest\"\xb8\x02\n" +
+	"\x0fSchedulerStatus\x12\x18\n" +
+	"\arunning\x18\x01 \x01(\bR\arunning\x125\n" +
+	"\binterval\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\binterval\x12\x1d\n" +
+	"\n" +
+	"batch_size\x18\x03 \x01(\x05R\tbatchSize\x12\x12\n" +
+	"\x04cron\x18\x04 \x01(\tR\x04cron\x12\x1a\n" +
+	"\bschedule\x18\x05 \x01(\tR\bschedule\x125\n" +
+	"\bnext_run\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\anextRun\x12\x18\n" +
+	"\astandby\x18\a \x01(\bR\astandby\x124\n" +
+	"\n" +
+	"leadership\x18\b \x01(\v2\x14.qubit.v1.LeadershipR\n" +
+	"leadership\"\xa3\x01\n" +
+	"\n" +
+	"Leadership\x12\x18\n" +
+	"\abackend\x18\x01 \x01(\tR\abackend\x12\x16\n" +
+	"\x06leader\x18\x02 \x01(\bR\x06leader\x120\n" +
+	"\x05since\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x05since\x12\"\n" +
+	"\n" +
+	"last_error\x18\x04 \x01(\tH\x00R\tlastError\x88\x01\x01B\r\n" +
+	"\v_last_error*\xf7\x01\n" +
+	"\rMessageStatus\x12\x1e\n" +
+	"\x1aMESSAGE_STATUS_UNSPECIFIED\x10\x00\x12\x1a\n" +
+	"\x16MESSAGE_STATUS_PENDING\x10\x01\x12\x1a\n" +
+	"\x16MESSAGE_STATUS_SENDING\x10\x02\x12\x17\n" +
+	"\x13MESSAGE_STATUS_SENT\x10\x03\x12\x19\n" +
+	"\x15MESSAGE_STATUS_FAILED\x10\x04\x12\x1c\n" +
+	"\x18MESSAGE_STATUS_CANCELLED\x10\x05\x12\x1c\n" +
+	"\x18MESSAGE_STATUS_THROTTLED\x10\x06\x12\x1e\n" +
+	"\x1aMESSAGE_STATUS_QUARANTINED\x10\a2\xce\x01\n" +
+	"\x0eMessageService\x12B\n" +
+	"\rCreateMessage\x12\x1e.qubit.v1.CreateMessageRequest\x1a\x11.qubit.v1.Message\x12<\n" +
+	"\n" +
+	"GetMessage\x12\x1b.qubit.v1.GetMessageRequest\x1a\x11.qubit.v1.Message\x12:\n" +
+	"\bListSent\x12\x19.qubit.v1.ListSentRequest\x1a\x11.qubit.v1.Message0\x012\xd0\x02\n" +
+	"\x10SchedulerService\x12L\n" +
+	"\x0eStartScheduler\x12\x1f.qubit.v1.StartSchedulerRequest\x1a\x19.qubit.v1.SchedulerStatus\x12J\n" +
+	"\rStopScheduler\x12\x1e.qubit.v1.StopSchedulerRequest\x1a\x19.qubit.v1.SchedulerStatus\x12L\n" +
+	"\x0eResetScheduler\x12\x1f.qubit.v1.ResetSchedulerRequest\x1a\x19.qubit.v1.SchedulerStatus\x12T\n" +
+	"\x12GetSchedulerStatus\x12#.qubit.v1.GetSchedulerStatusRequest\x1a\x19.qubit.v1.SchedulerStatusB\x1fZ\x1dqubit/api/rpc/qubitv1;qubitv1b\x06proto3"
+
+var (
+	file_qubit_v1_qubit_proto_rawDescOnce sync.Once
+	file_qubit_v1_qubit_proto_rawDescData []byte
+)
+
+func file_qubit_v1_qubit_proto_rawDescGZIP() []byte {
+	file_qubit_v1_qubit_proto_rawDescOnce.Do(func() {
+		file_qubit_v1_qubit_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_qubit_v1_qubit_proto_rawDesc), len(file_qubit_v1_qubit_proto_rawDesc)))
+	})
+	return file_qubit_v1_qubit_proto_rawDescData
+}
+
+var file_qubit_v1_qubit_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_qubit_v1_qubit_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_qubit_v1_qubit_proto_goTypes = []any{
+	(MessageStatus)(0),                // 0: qubit.v1.MessageStatus
+	(*Message)(nil),                   // 1: qubit.v1.Message
+	(*Segments)(nil),                  // 2: qubit.v1.Segments
+	(*RetryPolicy)(nil),               // 3: qubit.v1.RetryPolicy
+	(*CreateMessageRequest)(nil),      // 4: qubit.v1.CreateMessageRequest
+	(*GetMessageRequest)(nil),         // 5: qubit.v1.GetMessageRequest
+	(*ListSentRequest)(nil),           // 6: qubit.v1.ListSentRequest
+	(*StartSchedulerRequest)(nil),     // 7: qubit.v1.StartSchedulerRequest
+	(*StopSchedulerRequest)(nil),      // 8: qubit.v1.StopSchedulerRequest
+	(*ResetSchedulerRequest)(nil),     // 9: qubit.v1.ResetSchedulerRequest
+	(*GetSchedulerStatusRequest)(nil), // 10: qubit.v1.GetSchedulerStatusRequest
+	(*SchedulerStatus)(nil),           // 11: qubit.v1.SchedulerStatus
+	(*Leadership)(nil),                // 12: qubit.v1.Leadership
+	nil,                               // 13: qubit.v1.CreateMessageRequest.VariablesEntry
+	(*timestamppb.Timestamp)(nil),     // 14: google.protobuf.Timestamp
+	(*durationpb.Duration)(nil),       // 15: google.protobuf.Duration
+}
+var file_qubit_v1_qubit_proto_depIdxs = []int32{
+	14, // 0: qubit.v1.Message.created_at:type_name -> google.protobuf.Timestamp
+	14, // 1: qubit.v1.Message.processed_at:type_name -> google.protobuf.Timestamp
+	14, // 2: qubit.v1.Message.next_attempt_at:type_name -> google.protobuf.Timestamp
+	0,  // 3: qubit.v1.Message.status:type_name -> qubit.v1.MessageStatus
+	14, // 4: qubit.v1.Message.scheduled_at:type_name -> google.protobuf.Timestamp
+	3,  // 5: qubit.v1.Message.retry_policy:type_name -> qubit.v1.RetryPolicy
+	2,  // 6: qubit.v1.Message.segments:type_name -> qubit.v1.Segments
+	15, // 7: qubit.v1.RetryPolicy.base_delay:type_name -> google.protobuf.Duration
+	15, // 8: qubit.v1.RetryPolicy.max_delay:type_name -> google.protobuf.Duration
+	14, // 9: qubit.v1.CreateMessageRequest.scheduled_at:type_name -> google.protobuf.Timestamp
+	13, // 10: qubit.v1.CreateMessageRequest.variables:type_name -> qubit.v1.CreateMessageRequest.VariablesEntry
+	3,  // 11: qubit.v1.CreateMessageRequest.retry_policy:type_name -> qubit.v1.RetryPolicy
+	0,  // 12: qubit.v1.ListSentRequest.status:type_name -> qubit.v1.MessageStatus
+	14, // 13: qubit.v1.ListSentRequest.created_from:type_name -> google.protobuf.Timestamp
+	14, // 14: qubit.v1.ListSentRequest.created_to:type_name -> google.protobuf.Timestamp
+	14, // 15: qubit.v1.ListSentRequest.processed_from:type_name -> google.protobuf.Timestamp
+	14, // 16: qubit.v1.ListSentRequest.processed_to:type_name -> google.protobuf.Timestamp
+	15, // 17: qubit.v1.StartSchedulerRequest.interval:type_name -> google.protobuf.Duration
+	15, // 18: qubit.v1.SchedulerStatus.interval:type_name -> google.protobuf.Duration
+	14, // 19: qubit.v1.SchedulerStatus.next_run:type_name -> google.protobuf.Timestamp
+	12, // 20: qubit.v1.SchedulerStatus.leadership:type_name -> qubit.v1.Leadership
+	14, // 21: qubit.v1.Leadership.since:type_name -> google.protobuf.Timestamp
+	4,  // 22: qubit.v1.MessageService.CreateMessage:input_type -> qubit.v1.CreateMessageRequest
+	5,  // 23: qubit.v1.MessageService.GetMessage:input_type -> qubit.v1.GetMessageRequest
+	6,  // 24: qubit.v1.MessageService.ListSent:input_type -> qubit.v1.ListSentRequest
+	7,  // 25: qubit.v1.SchedulerService.StartScheduler:input_type -> qubit.v1.StartSchedulerRequest
+	8,  // 26: qubit.v1.SchedulerService.StopScheduler:input_type -> qubit.v1.StopSchedulerRequest
+	9,  // 27: qubit.v1.SchedulerService.ResetScheduler:input_type -> qubit.v1.ResetSchedulerRequest
+	10, // 28: qubit.v1.SchedulerService.GetSchedulerStatus:input_type -> qubit.v1.GetSchedulerStatusRequest
+	1,  // 29: qubit.v1.MessageService.CreateMessage:output_type -> qubit.v1.Message
+	1,  // 30: qubit.v1.MessageService.GetMessage:output_type -> qubit.v1.Message
+	1,  // 31: qubit.v1.MessageService.ListSent:output_type -> qubit.v1.Message
+	11, // 32: qubit.v1.SchedulerService.StartScheduler:output_type -> qubit.v1.SchedulerStatus
+	11, // 33: qubit.v1.SchedulerService.StopScheduler:output_type -> qubit.v1.SchedulerStatus
+	11, // 34: qubit.v1.SchedulerService.ResetScheduler:output_type -> qubit.v1.SchedulerStatus
+	11, // 35: qubit.v1.SchedulerService.GetSchedulerStatus:output_type -> qubit.v1.SchedulerStatus
+	29, // [29:36] is the sub-list for method output_type
+	22, // [22:29] is the sub-list for method input_type
+	22, // [22:22] is the sub-list for extension type_name
+	22, // [22:22] is the sub-list for extension extendee
+	0,  // [0:22] is the sub-list for field type_name
+}
+
+func init() { file_qubit_v1_qubit_proto_init() }
+func file_qubit_v1_qubit_proto_init() {
+	if File_qubit_v1_qubit_proto != nil {
+		return
+	}
+	file_qubit_v1_qubit_proto_msgTypes[0].OneofWrappers = []any{}
+	file_qubit_v1_qubit_proto_msgTypes[3].OneofWrappers = []any{}
+	file_qubit_v1_qubit_proto_msgTypes[6].OneofWrappers = []any{}
+	file_qubit_v1_qubit_proto_msgTypes[11].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_qubit_v1_qubit_proto_rawDesc), len(file_qubit_v1_qubit_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   13,
+			NumExtensions: 0,
+			NumServices:   2,
+		},
+		GoTypes:           file_qubit_v1_qubit_proto_goTypes,
+		DependencyIndexes: file_qubit_v1_qubit_proto_depIdxs,
+		EnumInfos:         file_qubit_v1_qubit_proto_enumTypes,
+		MessageInfos:      file_qubit_v1_qubit_proto_msgTypes,
+	}.Build()
+	File_qubit_v1_qubit_proto = out.File
+	file_qubit_v1_qubit_proto_goTypes = nil
+	file_qubit_v1_qubit_proto_depIdxs = nil
+}
@@ -0,0 +1,79 @@
+package rpc
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"qubit/api/rpc/qubitv1"
+	"qubit/service/message"
+)
+
+// Scheduler setting bounds, matching the REST API
+const (
+	maxSchedulerInterval  = 24 * time.Hour
+	maxSchedulerBatchSize = 1000
+	maxSchedulerCron      = 200
+)
+
+// schedulerServer implements qubitv1.SchedulerServiceServer on top of the message service
+type schedulerServer struct {
+	qubitv1.UnimplementedSchedulerServiceServer
+
+	messageService *message.Service
+}
+
+// StartScheduler starts the scheduler, omitted settings keep the configured defaults
+func (s *schedulerServer) StartScheduler(ctx context.Context, req *qubitv1.StartSchedulerRequest) (*qubitv1.SchedulerStatus, error) {
+	settings := s.messageService.DefaultSchedulerSettings()
+	if req.Interval != nil {
+		interval := req.GetInterval().AsDuration()
+		if interval > maxSchedulerInterval {
+			return nil, status.Errorf(codes.InvalidArgument, "Invalid request: interval must be at most %s", maxSchedulerInterval)
+		}
+		settings.Interval = interval
+	}
+	if req.GetBatchSize() != 0 {
+		if req.GetBatchSize() < 0 || req.GetBatchSize() > maxSchedulerBatchSize {
+			return nil, status.Errorf(codes.InvalidArgument, "Invalid request: batch_size must be between 1 and %d", maxSchedulerBatchSize)
+		}
+		settings.BatchSize = int(req.GetBatchSize())
+	}
+	if req.Cron != nil {
+		if len(req.GetCron()) > maxSchedulerCron {
+			return nil, status.Errorf(codes.InvalidArgument, "Invalid request: cron must be at most %d characters", maxSchedulerCron)
+		}
+		settings.Cron = req.GetCron()
+	}
+
+	if err := s.messageService.StartScheduler(ctx, settings); err != nil {
+		return nil, statusError(err, "Failed to start scheduler")
+	}
+
+	return toProtoSchedulerStatus(s.messageService.SchedulerStatus()), nil
+}
+
+// StopScheduler stops the scheduler of this instance
+func (s *schedulerServer) StopScheduler(ctx context.Context, req *qubitv1.StopSchedulerRequest) (*qubitv1.SchedulerStatus, error) {
+	if err := s.messageService.StopScheduler(); err != nil {
+		return nil, statusError(err, "Failed to stop scheduler")
+	}
+
+	return toProtoSchedulerStatus(s.messageService.SchedulerStatus()), nil
+}
+
+// ResetScheduler drops the runtime overrides and restarts the scheduler with the configured defaults
+func (s *schedulerServer) ResetScheduler(ctx context.Context, req *qubitv1.ResetSchedulerRequest) (*qubitv1.SchedulerStatus, error) {
+	if _, err := s.messageService.ResetScheduler(ctx); err != nil {
+		return nil, statusError(err, "Failed to reset scheduler")
+	}
+
+	return toProtoSchedulerStatus(s.messageService.SchedulerStatus()), nil
+}
+
+// GetSchedulerStatus returns whether the scheduler runs, its schedule and its next run
+func (s *schedulerServer) GetSchedulerStatus(ctx context.Context, req *qubitv1.GetSchedulerStatusRequest) (*qubitv1.SchedulerStatus, error) {
+	return toProtoSchedulerStatus(s.messageService.SchedulerStatus()), nil
+}
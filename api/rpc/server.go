@@ -0,0 +1,44 @@
+// Package rpc serves the message service over gRPC alongside the REST API
+package rpc
+
+//go:generate protoc -I ../../proto --go_out=../.. --go_opt=module=qubit --go-grpc_out=../.. --go-grpc_opt=module=qubit qubit/v1/qubit.proto
+
+import (
+	"google.golang.org/grpc"
+
+	"qubit/api/rpc/qubitv1"
+	"qubit/pkg/ratelimit"
+	"qubit/service/anomaly"
+	"qubit/service/apikey"
+	"qubit/service/maintenance"
+	"qubit/service/message"
+)
+
+// NewServer creates the gRPC server with the message and scheduler services registered
+// Calls are authenticated, scoped, rate limited and blocked in maintenance mode like their REST routes
+func NewServer(
+	messageService *message.Service,
+	apiKeyService *apikey.Service,
+	maintenanceService *maintenance.Service,
+	apiKeysRequired bool,
+	rateLimiter ratelimit.Limiter,
+	anomalyService *anomaly.Service,
+) *grpc.Server {
+	guard := &guard{
+		apiKeyService:      apiKeyService,
+		maintenanceService: maintenanceService,
+		apiKeysRequired:    apiKeysRequired,
+		rateLimiter:        rateLimiter,
+		anomalyService:     anomalyService,
+	}
+
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(logUnary, recoverUnary, guard.unary),
+		grpc.ChainStreamInterceptor(logStream, recoverStream, guard.stream),
+	)
+
+	qubitv1.RegisterMessageServiceServer(server, &messageServer{messageService: messageService})
+	qubitv1.RegisterSchedulerServiceServer(server, &schedulerServer{messageService: messageService})
+
+	return server
+}
@@ -0,0 +1,149 @@
+package api
+
+import (
+	"qubit/api/anomalies"
+	"qubit/api/apikeys"
+	"qubit/api/archive"
+	backlogalertapi "qubit/api/backlogalert"
+	"qubit/api/campaigns"
+	configreloadapi "qubit/api/configreload"
+	"qubit/api/diagnostics"
+	"qubit/api/errorsummary"
+	fakesenderapi "qubit/api/fakesender"
+	healthapi "qubit/api/health"
+	"qubit/api/inbound"
+	maintenanceapi "qubit/api/maintenance"
+	"qubit/api/messages"
+	"qubit/api/providers"
+	"qubit/api/providertemplates"
+	"qubit/api/replays"
+	"qubit/api/routing"
+	"qubit/api/templates"
+	"qubit/api/tenants"
+	"qubit/service/message"
+)
+
+// responseTypes lists the response DTOs of every endpoint
+// SetupRouter checks their JSON field names, add new response types here
+var responseTypes = []any{
+	ErrorResponse{},
+	anomalies.AlertResponse{},
+	anomalies.SuccessResponse{},
+	anomalies.ErrorResponse{},
+	anomalies.AlertListResponse{},
+	apikeys.KeyResponse{},
+	apikeys.CreatedKeyResponse{},
+	apikeys.SuccessResponse{},
+	apikeys.ErrorResponse{},
+	apikeys.KeyListResponse{},
+	archive.RedriveResponse{},
+	archive.ErrorResponse{},
+	backlogalertapi.AlertResponse{},
+	backlogalertapi.ThresholdsResponse{},
+	backlogalertapi.StateResponse{},
+	backlogalertapi.SuccessResponse{},
+	backlogalertapi.ErrorResponse{},
+	campaigns.CampaignResponse{},
+	campaigns.SuccessResponse{},
+	campaigns.ErrorResponse{},
+	campaigns.CampaignListResponse{},
+	campaigns.VariantResponse{},
+	campaigns.CampaignStatsResponse{},
+	campaigns.VariantStatsResponse{},
+	configreloadapi.ReloadResponse{},
+	configreloadapi.SuccessResponse{},
+	configreloadapi.ErrorResponse{},
+	diagnostics.WarmUpResponse{},
+	diagnostics.CircuitResponse{},
+	diagnostics.WebhookDiagnosticsResponse{},
+	diagnostics.ColumnTypeDriftResponse{},
+	diagnostics.SchemaDiagnosticsResponse{},
+	errorsummary.SummaryResponse{},
+	errorsummary.CountResponse{},
+	errorsummary.TopErrorResponse{},
+	errorsummary.ErrorResponse{},
+	healthapi.LiveResponse{},
+	fakesenderapi.ModeResponse{},
+	fakesenderapi.SuccessResponse{},
+	fakesenderapi.ErrorResponse{},
+	healthapi.ReadyResponse{},
+	healthapi.CheckResponse{},
+	healthapi.SchedulerResponse{},
+	healthapi.CanaryResponse{},
+	inbound.InboundMessageResponse{},
+	inbound.ReplyToResponse{},
+	inbound.SuccessResponse{},
+	inbound.ErrorResponse{},
+	inbound.InboundMessageListResponse{},
+	maintenanceapi.ModeResponse{},
+	maintenanceapi.SuccessResponse{},
+	maintenanceapi.ErrorResponse{},
+	messages.MessageResponse{},
+	messages.RetryPolicyResponse{},
+	messages.ProviderTemplateResponse{},
+	messages.SegmentsResponse{},
+	messages.SuccessResponse{},
+	messages.ErrorResponse{},
+	messages.SchedulerSettingsResponse{},
+	messages.SchedulerStatusResponse{},
+	messages.RunResponse{},
+	messages.RunListResponse{},
+	messages.LeadershipResponse{},
+	messages.ShardingResponse{},
+	messages.SchedulerPauseResponse{},
+	messages.ProviderThrottleResponse{},
+	messages.MessageListResponse{},
+	messages.FanoutResponse{},
+	messages.InFlightMessageResponse{},
+	messages.InFlightListResponse{},
+	messages.DeliveryResponse{},
+	messages.TimelineEventResponse{},
+	messages.TimelineResponse{},
+	messages.AttemptResponse{},
+	messages.AttemptListResponse{},
+	messages.PhaseStatsResponse{},
+	messages.AttemptStatsResponse{},
+	messages.WindowStatsResponse{},
+	messages.LiveStatsResponse{},
+	messages.BatchResultResponse{},
+	messages.StatsResponse{},
+	messages.MessageCountsResponse{},
+	messages.BacklogResponse{},
+	messages.LastBatchResponse{},
+	messages.ProgressEventResponse{},
+	message.Event{},
+	message.EventMessage{},
+	message.EventFailure{},
+	providers.ProviderResponse{},
+	providers.ProviderListResponse{},
+	providertemplates.TemplateResponse{},
+	providertemplates.TemplateListResponse{},
+	providertemplates.SyncResultResponse{},
+	providertemplates.SyncResponse{},
+	providertemplates.ErrorResponse{},
+	replays.RejectedRequestResponse{},
+	replays.ReplayResponse{},
+	replays.SuccessResponse{},
+	replays.ErrorResponse{},
+	replays.RejectedRequestListResponse{},
+	routing.RoutingDecisionResponse{},
+	routing.RecipientBucketResponse{},
+	routing.SuccessResponse{},
+	routing.ErrorResponse{},
+	templates.TemplateResponse{},
+	templates.SuccessResponse{},
+	templates.ErrorResponse{},
+	templates.TemplateListResponse{},
+	tenants.TenantResponse{},
+	tenants.QuotasResponse{},
+	tenants.OnboardingResponse{},
+	tenants.SuccessResponse{},
+	tenants.ErrorResponse{},
+	tenants.TenantListResponse{},
+	tenants.SigningKeyResponse{},
+	tenants.CreatedSigningKeyResponse{},
+	tenants.SigningVerificationResponse{},
+	tenants.DefaultMetadataResponse{},
+	tenants.ImpersonationResponse{},
+	tenants.ImpersonationListResponse{},
+}
@@ -0,0 +1,139 @@
+package templates
+
+import (
+	"net/http"
+	"strconv"
+
+	"qubit/pkg/apperr"
+	"qubit/pkg/openapi"
+	"qubit/service/template"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler handles template-related HTTP requests
+type Handler struct {
+	templateService *template.Service
+}
+
+// NewHandler creates a new template handler
+func NewHandler(templateService *template.Service) *Handler {
+	return &Handler{
+		templateService: templateService,
+	}
+}
+
+// CreateTemplateOperation documents CreateTemplate in the OpenAPI spec
+var CreateTemplateOperation = openapi.Operation{
+	Summary:     "Create a message template",
+	Description: "Stores a named template whose {{placeholders}} are filled in when a message references it; translations per locale are picked by the locale of the message and LOCALE_FALLBACK",
+	Tags:        []string{"Templates"},
+	Body:        CreateTemplateRequest{},
+	Responses: []openapi.Response{
+		{Status: http.StatusCreated, Body: SuccessResponse{}},
+		{Status: http.StatusBadRequest, Body: ErrorResponse{}},
+		{Status: http.StatusConflict, Body: ErrorResponse{}},
+		{Status: http.StatusInternalServerError, Body: ErrorResponse{}},
+	},
+}
+
+// CreateTemplate handles POST /templates
+func (h *Handler) CreateTemplate(c *gin.Context) {
+	var req CreateTemplateRequest
+
+	// Bind and validate request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Error:   "Invalid request: " + err.Error(),
+			Code:    apperr.CodeInvalidRequest,
+		})
+		return
+	}
+
+	created, err := h.templateService.CreateTemplate(c.Request.Context(), req.Name, req.Content, req.Translations)
+	if err != nil {
+		respondError(c, "Failed to create template", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, SuccessResponse{
+		Success: true,
+		Message: "Template created successfully",
+		Data:    ToTemplateResponse(created),
+	})
+}
+
+// GetTemplatesOperation documents GetTemplates in the OpenAPI spec
+var GetTemplatesOperation = openapi.Operation{
+	Summary:     "Get all templates",
+	Description: "Returns all templates with their placeholders",
+	Tags:        []string{"Templates"},
+	Responses: []openapi.Response{
+		{Status: http.StatusOK, Body: TemplateListResponse{}},
+		{Status: http.StatusInternalServerError, Body: ErrorResponse{}},
+	},
+}
+
+// GetTemplates handles GET /templates
+func (h *Handler) GetTemplates(c *gin.Context) {
+	found, err := h.templateService.ListTemplates(c.Request.Context())
+	if err != nil {
+		respondError(c, "Failed to retrieve templates", err)
+		return
+	}
+
+	responses := ToTemplateResponseList(found)
+
+	c.JSON(http.StatusOK, TemplateListResponse{
+		Success:   true,
+		Count:     len(responses),
+		Templates: responses,
+	})
+}
+
+// GetTemplateOperation documents GetTemplate in the OpenAPI spec
+var GetTemplateOperation = openapi.Operation{
+	Summary:     "Get a template",
+	Description: "Returns a single template with its placeholders",
+	Tags:        []string{"Templates"},
+	Params: []openapi.Param{
+		{Name: "id", In: openapi.InPath, Type: "integer", Description: "Template ID"},
+	},
+	Responses: []openapi.Response{
+		{Status: http.StatusOK, Body: SuccessResponse{}},
+		{Status: http.StatusBadRequest, Body: ErrorResponse{}},
+		{Status: http.StatusNotFound, Body: ErrorResponse{}},
+		{Status: http.StatusInternalServerError, Body: ErrorResponse{}},
+	},
+}
+
+// GetTemplate handles GET /templates/:id
+func (h *Handler) GetTemplate(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Error:   "Invalid request: template id must be a positive integer",
+			Code:    apperr.CodeInvalidRequest,
+		})
+		return
+	}
+
+	found, err := h.templateService.GetTemplate(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, "Failed to retrieve template", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Message: "Template retrieved successfully",
+		Data:    ToTemplateResponse(found),
+	})
+}
+
+// respondError records err for the error middleware, which answers it with the status and code of its kind
+func respondError(c *gin.Context, prefix string, err error) {
+	_ = c.Error(err).SetMeta(prefix)
+}
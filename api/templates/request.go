@@ -0,0 +1,9 @@
+package templates
+
+// CreateTemplateRequest represents the request to store a new template
+type CreateTemplateRequest struct {
+	Name    string `json:"name" binding:"required,max=100"`
+	Content string `json:"content" binding:"required,max=1000"`
+	// Translations hold the content per locale, e.g. {"tr": "...", "en": "..."}; content is used when none matches
+	Translations map[string]string `json:"translations" binding:"omitempty,max=20"`
+}
@@ -0,0 +1,69 @@
+package templates
+
+import (
+	"qubit/pkg/jsonfmt"
+	"qubit/service/template"
+)
+
+// TemplateResponse represents a template in API responses
+type TemplateResponse struct {
+	ID           int64             `json:"id"`
+	Name         string            `json:"name"`
+	Content      string            `json:"content"`
+	Translations map[string]string `json:"translations"`
+	Placeholders []string          `json:"placeholders"`
+	CreatedAt    jsonfmt.Time      `json:"createdAt"`
+}
+
+// SuccessResponse represents a generic success response
+type SuccessResponse struct {
+	Success bool        `json:"success"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+// ErrorResponse represents an error response
+type ErrorResponse struct {
+	Success bool   `json:"success"`
+	Error   string `json:"error"`
+	Code    string `json:"code"`
+}
+
+// TemplateListResponse represents a list of templates
+type TemplateListResponse struct {
+	Success   bool               `json:"success"`
+	Count     int                `json:"count"`
+	Templates []TemplateResponse `json:"templates"`
+}
+
+// ToTemplateResponse converts a domain template.Template to TemplateResponse
+func ToTemplateResponse(t *template.Template) TemplateResponse {
+	placeholders := t.Placeholders()
+	if placeholders == nil {
+		placeholders = []string{}
+	}
+
+	translations := t.Translations
+	if translations == nil {
+		translations = map[string]string{}
+	}
+
+	return TemplateResponse{
+		ID:           t.ID,
+		Name:         t.Name,
+		Content:      t.Content,
+		Translations: translations,
+		Placeholders: placeholders,
+		CreatedAt:    jsonfmt.NewTime(t.CreatedAt),
+	}
+}
+
+// ToTemplateResponseList converts a slice of domain templates to TemplateResponse slice
+func ToTemplateResponseList(templates []*template.Template) []TemplateResponse {
+	responses := make([]TemplateResponse, 0, len(templates))
+	for _, t := range templates {
+		responses = append(responses, ToTemplateResponse(t))
+	}
+
+	return responses
+}
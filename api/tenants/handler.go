@@ -0,0 +1,361 @@
+package tenants
+
+import (
+	"net/http"
+	"strconv"
+
+	"qubit/pkg/apperr"
+	"qubit/pkg/openapi"
+	"qubit/service/apikey"
+	"qubit/service/tenant"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler handles tenant onboarding HTTP requests
+type Handler struct {
+	tenantService *tenant.Service
+}
+
+// NewHandler creates a new tenant handler
+func NewHandler(tenantService *tenant.Service) *Handler {
+	return &Handler{
+		tenantService: tenantService,
+	}
+}
+
+// OnboardOperation documents Onboard in the OpenAPI spec
+var OnboardOperation = openapi.Operation{
+	Summary:     "Onboard a tenant",
+	Description: "Provisions a tenant with its settings, quotas (defaults unless overridden) and initial API key in one transaction; the key secret is only returned in this response",
+	Tags:        []string{"Tenants"},
+	Body:        OnboardRequest{},
+	Responses: []openapi.Response{
+		{Status: http.StatusCreated, Body: SuccessResponse{}},
+		{Status: http.StatusBadRequest, Body: ErrorResponse{}},
+		{Status: http.StatusConflict, Body: ErrorResponse{}},
+		{Status: http.StatusInternalServerError, Body: ErrorResponse{}},
+	},
+}
+
+// Onboard handles POST /tenants
+func (h *Handler) Onboard(c *gin.Context) {
+	var req OnboardRequest
+
+	// Bind and validate request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Error:   "Invalid request: " + err.Error(),
+			Code:    apperr.CodeInvalidRequest,
+		})
+		return
+	}
+
+	opts := tenant.OnboardOptions{
+		Name:            req.Name,
+		Settings:        req.Settings,
+		DefaultMetadata: req.DefaultMetadata,
+		KeyScopes:       req.KeyScopes,
+		IsTest:          req.IsTest,
+	}
+	if req.Quotas != nil {
+		opts.Quotas = &tenant.QuotaOverride{
+			DailyMessages:      req.Quotas.DailyMessages,
+			RateLimitPerMinute: req.Quotas.RateLimitPerMinute,
+		}
+	}
+
+	onboarding, err := h.tenantService.Onboard(c.Request.Context(), opts)
+	if err != nil {
+		respondError(c, "Failed to onboard tenant", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, SuccessResponse{
+		Success: true,
+		Message: "Tenant onboarded successfully, store the API key now as it cannot be retrieved again",
+		Data:    ToOnboardingResponse(onboarding),
+	})
+}
+
+// GetTenantsOperation documents GetTenants in the OpenAPI spec
+var GetTenantsOperation = openapi.Operation{
+	Summary:     "Get all tenants",
+	Description: "Returns all tenants with their settings and quotas",
+	Tags:        []string{"Tenants"},
+	Responses: []openapi.Response{
+		{Status: http.StatusOK, Body: TenantListResponse{}},
+		{Status: http.StatusInternalServerError, Body: ErrorResponse{}},
+	},
+}
+
+// GetTenants handles GET /tenants
+func (h *Handler) GetTenants(c *gin.Context) {
+	found, err := h.tenantService.ListTenants(c.Request.Context())
+	if err != nil {
+		respondError(c, "Failed to retrieve tenants", err)
+		return
+	}
+
+	responses := ToTenantResponseList(found)
+
+	c.JSON(http.StatusOK, TenantListResponse{
+		Success: true,
+		Count:   len(responses),
+		Tenants: responses,
+	})
+}
+
+// GetTenantOperation documents GetTenant in the OpenAPI spec
+var GetTenantOperation = openapi.Operation{
+	Summary:     "Get a tenant",
+	Description: "Returns a single tenant with its settings and quotas",
+	Tags:        []string{"Tenants"},
+	Params: []openapi.Param{
+		{Name: "id", In: openapi.InPath, Type: "integer", Description: "Tenant ID"},
+	},
+	Responses: []openapi.Response{
+		{Status: http.StatusOK, Body: SuccessResponse{}},
+		{Status: http.StatusBadRequest, Body: ErrorResponse{}},
+		{Status: http.StatusNotFound, Body: ErrorResponse{}},
+		{Status: http.StatusInternalServerError, Body: ErrorResponse{}},
+	},
+}
+
+// GetTenant handles GET /tenants/:id
+func (h *Handler) GetTenant(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Error:   "Invalid request: tenant id must be a positive integer",
+			Code:    apperr.CodeInvalidRequest,
+		})
+		return
+	}
+
+	found, err := h.tenantService.GetTenant(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, "Failed to retrieve tenant", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Message: "Tenant retrieved successfully",
+		Data:    ToTenantResponse(found),
+	})
+}
+
+// GetImpersonationsOperation documents GetImpersonations in the OpenAPI spec
+var GetImpersonationsOperation = openapi.Operation{
+	Summary:     "Get the impersonation audit trail of a tenant",
+	Description: "Returns the 100 most recent requests made by admin keys acting as the tenant, newest first",
+	Tags:        []string{"Tenants"},
+	Params: []openapi.Param{
+		{Name: "id", In: openapi.InPath, Type: "integer", Description: "Tenant ID"},
+	},
+	Responses: []openapi.Response{
+		{Status: http.StatusOK, Body: ImpersonationListResponse{}},
+		{Status: http.StatusBadRequest, Body: ErrorResponse{}},
+		{Status: http.StatusNotFound, Body: ErrorResponse{}},
+		{Status: http.StatusInternalServerError, Body: ErrorResponse{}},
+	},
+}
+
+// GetImpersonations handles GET /tenants/:id/impersonations
+func (h *Handler) GetImpersonations(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Error:   "Invalid request: tenant id must be a positive integer",
+			Code:    apperr.CodeInvalidRequest,
+		})
+		return
+	}
+
+	found, err := h.tenantService.ListImpersonations(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, "Failed to retrieve impersonations", err)
+		return
+	}
+
+	responses := ToImpersonationResponseList(found)
+
+	c.JSON(http.StatusOK, ImpersonationListResponse{
+		Success:        true,
+		Count:          len(responses),
+		Impersonations: responses,
+	})
+}
+
+// GetSigningKeysOperation documents GetSigningKeys in the OpenAPI spec
+var GetSigningKeysOperation = openapi.Operation{
+	Summary:     "Get the callback verification instructions",
+	Description: "Returns how to verify the signed callbacks of the tenant of the API key, with the valid signing keys and the public keys of ed25519 keys; admins act as the tenant",
+	Tags:        []string{"Tenants"},
+	Responses: []openapi.Response{
+		{Status: http.StatusOK, Body: SigningVerificationResponse{}},
+		{Status: http.StatusForbidden, Body: ErrorResponse{}},
+		{Status: http.StatusInternalServerError, Body: ErrorResponse{}},
+	},
+}
+
+// GetSigningKeys handles GET /signing-keys
+func (h *Handler) GetSigningKeys(c *gin.Context) {
+	tenantID, ok := requestTenant(c)
+	if !ok {
+		return
+	}
+
+	keys, err := h.tenantService.SigningKeys(c.Request.Context(), tenantID)
+	if err != nil {
+		respondError(c, "Failed to retrieve signing keys", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ToSigningVerificationResponse(keys))
+}
+
+// RotateSigningKeyOperation documents RotateSigningKey in the OpenAPI spec
+var RotateSigningKeyOperation = openapi.Operation{
+	Summary:     "Rotate the callback signing key",
+	Description: "Creates a new signing key for the tenant of the API key with the requested algorithm; the previous key keeps signing for 24 hours and an HMAC secret is only returned in this response",
+	Tags:        []string{"Tenants"},
+	Body:        RotateSigningKeyRequest{},
+	Responses: []openapi.Response{
+		{Status: http.StatusCreated, Body: SuccessResponse{}},
+		{Status: http.StatusBadRequest, Body: ErrorResponse{}},
+		{Status: http.StatusForbidden, Body: ErrorResponse{}},
+		{Status: http.StatusInternalServerError, Body: ErrorResponse{}},
+	},
+}
+
+// RotateSigningKey handles POST /signing-keys
+func (h *Handler) RotateSigningKey(c *gin.Context) {
+	tenantID, ok := requestTenant(c)
+	if !ok {
+		return
+	}
+
+	var req RotateSigningKeyRequest
+
+	// Bind and validate request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Error:   "Invalid request: " + err.Error(),
+			Code:    apperr.CodeInvalidRequest,
+		})
+		return
+	}
+
+	key, err := h.tenantService.RotateSigningKey(c.Request.Context(), tenantID, req.Algorithm)
+	if err != nil {
+		respondError(c, "Failed to rotate signing key", err)
+		return
+	}
+
+	message := "Signing key created successfully"
+	if !key.Algorithm.Asymmetric() {
+		message += ", store the secret now as it cannot be retrieved again"
+	}
+
+	c.JSON(http.StatusCreated, SuccessResponse{
+		Success: true,
+		Message: message,
+		Data:    ToCreatedSigningKeyResponse(key),
+	})
+}
+
+// GetDefaultMetadataOperation documents GetDefaultMetadata in the OpenAPI spec
+var GetDefaultMetadataOperation = openapi.Operation{
+	Summary:     "Get the default message metadata",
+	Description: "Returns the metadata merged into every message the tenant of the API key creates; admins act as the tenant",
+	Tags:        []string{"Tenants"},
+	Responses: []openapi.Response{
+		{Status: http.StatusOK, Body: DefaultMetadataResponse{}},
+		{Status: http.StatusForbidden, Body: ErrorResponse{}},
+		{Status: http.StatusNotFound, Body: ErrorResponse{}},
+		{Status: http.StatusInternalServerError, Body: ErrorResponse{}},
+	},
+}
+
+// GetDefaultMetadata handles GET /default-metadata
+func (h *Handler) GetDefaultMetadata(c *gin.Context) {
+	tenantID, ok := requestTenant(c)
+	if !ok {
+		return
+	}
+
+	found, err := h.tenantService.GetTenant(c.Request.Context(), tenantID)
+	if err != nil {
+		respondError(c, "Failed to retrieve default metadata", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ToDefaultMetadataResponse(found))
+}
+
+// SetDefaultMetadataOperation documents SetDefaultMetadata in the OpenAPI spec
+var SetDefaultMetadataOperation = openapi.Operation{
+	Summary: "Set the default message metadata",
+	Description: "Replaces the metadata merged into every message the tenant of the API key creates, e.g. a cost center or environment; " +
+		"fields given with a message take precedence and messages created before keep their metadata",
+	Tags: []string{"Tenants"},
+	Body: SetDefaultMetadataRequest{},
+	Responses: []openapi.Response{
+		{Status: http.StatusOK, Body: DefaultMetadataResponse{}},
+		{Status: http.StatusBadRequest, Body: ErrorResponse{}},
+		{Status: http.StatusForbidden, Body: ErrorResponse{}},
+		{Status: http.StatusNotFound, Body: ErrorResponse{}},
+		{Status: http.StatusInternalServerError, Body: ErrorResponse{}},
+	},
+}
+
+// SetDefaultMetadata handles PUT /default-metadata
+func (h *Handler) SetDefaultMetadata(c *gin.Context) {
+	tenantID, ok := requestTenant(c)
+	if !ok {
+		return
+	}
+
+	var req SetDefaultMetadataRequest
+
+	// Bind and validate request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Error:   "Invalid request: " + err.Error(),
+			Code:    apperr.CodeInvalidRequest,
+		})
+		return
+	}
+
+	updated, err := h.tenantService.SetDefaultMetadata(c.Request.Context(), tenantID, req.DefaultMetadata)
+	if err != nil {
+		respondError(c, "Failed to set default metadata", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ToDefaultMetadataResponse(updated))
+}
+
+// requestTenant returns the tenant of the API key of the request
+// It writes the error response and returns false for operator keys
+func requestTenant(c *gin.Context) (int64, bool) {
+	key, ok := apikey.FromContext(c.Request.Context())
+	if !ok || key.TenantID == nil {
+		respondError(c, "Invalid request", tenant.ErrNoTenant)
+		return 0, false
+	}
+
+	return *key.TenantID, true
+}
+
+// respondError records err for the error middleware, which answers it with the status and code of its kind
+func respondError(c *gin.Context, prefix string, err error) {
+	_ = c.Error(err).SetMeta(prefix)
+}
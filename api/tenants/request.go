@@ -0,0 +1,30 @@
+package tenants
+
+// OnboardRequest represents the request to provision a new tenant
+type OnboardRequest struct {
+	Name     string            `json:"name" binding:"required,max=100"`
+	Settings map[string]string `json:"settings" binding:"omitempty,max=50"`
+	// DefaultMetadata is merged into the metadata of every message the tenant creates
+	DefaultMetadata map[string]string `json:"defaultMetadata" binding:"omitempty,max=20"`
+	Quotas          *QuotasRequest    `json:"quotas"`
+
+	// KeyScopes are granted to the initial API key, messages:read and messages:write when empty
+	KeyScopes []string `json:"keyScopes"`
+	IsTest    bool     `json:"isTest"`
+}
+
+// QuotasRequest overrides the default quotas of a new tenant, omitted fields keep the default
+type QuotasRequest struct {
+	DailyMessages      *int `json:"dailyMessages" binding:"omitempty,min=1"`
+	RateLimitPerMinute *int `json:"rateLimitPerMinute" binding:"omitempty,min=1,max=60000"`
+}
+
+// RotateSigningKeyRequest represents the request to create a new callback signing key
+type RotateSigningKeyRequest struct {
+	Algorithm string `json:"algorithm" binding:"required,oneof=hmac-sha256 hmac-sha512 ed25519"`
+}
+
+// SetDefaultMetadataRequest replaces the default metadata of the tenant, {} removes it
+type SetDefaultMetadataRequest struct {
+	DefaultMetadata map[string]string `json:"defaultMetadata" binding:"required,max=20"`
+}
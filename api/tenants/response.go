@@ -0,0 +1,230 @@
+package tenants
+
+import (
+	"encoding/base64"
+
+	"qubit/api/apikeys"
+	"qubit/pkg/jsonfmt"
+	"qubit/service/tenant"
+)
+
+// TenantResponse represents a tenant in API responses
+type TenantResponse struct {
+	ID              int64             `json:"id"`
+	Name            string            `json:"name"`
+	Settings        map[string]string `json:"settings"`
+	DefaultMetadata map[string]string `json:"defaultMetadata"`
+	Quotas          QuotasResponse    `json:"quotas"`
+	CreatedAt       jsonfmt.Time      `json:"createdAt"`
+}
+
+// QuotasResponse represents the quotas of a tenant
+type QuotasResponse struct {
+	DailyMessages      int `json:"dailyMessages"`
+	RateLimitPerMinute int `json:"rateLimitPerMinute"`
+}
+
+// OnboardingResponse represents a provisioned tenant, the key secret is only returned once
+type OnboardingResponse struct {
+	Tenant TenantResponse             `json:"tenant"`
+	APIKey apikeys.CreatedKeyResponse `json:"apiKey"`
+}
+
+// ImpersonationResponse represents an audited request of an admin key acting as the tenant
+type ImpersonationResponse struct {
+	ID         int64        `json:"id"`
+	ActorKeyID *int64       `json:"actorKeyId"`
+	ActorName  string       `json:"actorName"`
+	Method     string       `json:"method"`
+	Path       string       `json:"path"`
+	ClientIP   string       `json:"clientIp"`
+	Status     *int         `json:"status"`
+	CreatedAt  jsonfmt.Time `json:"createdAt"`
+}
+
+// SigningKeyResponse represents a callback signing key, publicKey is the base64 Ed25519 public key
+type SigningKeyResponse struct {
+	ID        int64         `json:"id"`
+	Algorithm string        `json:"algorithm"`
+	PublicKey *string       `json:"publicKey"`
+	Active    bool          `json:"active"`
+	CreatedAt jsonfmt.Time  `json:"createdAt"`
+	ExpiresAt *jsonfmt.Time `json:"expiresAt"`
+}
+
+// CreatedSigningKeyResponse represents a new signing key, the base64 HMAC secret is only returned once
+// Ed25519 private keys never leave the service
+type CreatedSigningKeyResponse struct {
+	SigningKeyResponse
+	Secret *string `json:"secret"`
+}
+
+// SigningVerificationResponse describes how the tenant verifies the callbacks it receives
+type SigningVerificationResponse struct {
+	Success       bool                 `json:"success"`
+	Header        string               `json:"header"`
+	SignedPayload string               `json:"signedPayload"`
+	Instructions  []string             `json:"instructions"`
+	Keys          []SigningKeyResponse `json:"keys"`
+}
+
+// DefaultMetadataResponse represents the default metadata merged into the messages of the tenant
+type DefaultMetadataResponse struct {
+	Success         bool              `json:"success"`
+	DefaultMetadata map[string]string `json:"defaultMetadata"`
+}
+
+// SuccessResponse represents a generic success response
+type SuccessResponse struct {
+	Success bool        `json:"success"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+// ErrorResponse represents an error response
+type ErrorResponse struct {
+	Success bool   `json:"success"`
+	Error   string `json:"error"`
+	Code    string `json:"code"`
+}
+
+// TenantListResponse represents a list of tenants
+type TenantListResponse struct {
+	Success bool             `json:"success"`
+	Count   int              `json:"count"`
+	Tenants []TenantResponse `json:"tenants"`
+}
+
+// ImpersonationListResponse represents the impersonation audit trail of a tenant
+type ImpersonationListResponse struct {
+	Success        bool                    `json:"success"`
+	Count          int                     `json:"count"`
+	Impersonations []ImpersonationResponse `json:"impersonations"`
+}
+
+// ToTenantResponse converts a domain tenant.Tenant to TenantResponse
+func ToTenantResponse(t *tenant.Tenant) TenantResponse {
+	settings := t.Settings
+	if settings == nil {
+		settings = map[string]string{}
+	}
+
+	return TenantResponse{
+		ID:              t.ID,
+		Name:            t.Name,
+		Settings:        settings,
+		DefaultMetadata: defaultMetadata(t),
+		Quotas: QuotasResponse{
+			DailyMessages:      t.Quotas.DailyMessages,
+			RateLimitPerMinute: t.Quotas.RateLimitPerMinute,
+		},
+		CreatedAt: jsonfmt.NewTime(t.CreatedAt),
+	}
+}
+
+// ToDefaultMetadataResponse converts the default metadata of a domain tenant.Tenant to DefaultMetadataResponse
+func ToDefaultMetadataResponse(t *tenant.Tenant) DefaultMetadataResponse {
+	return DefaultMetadataResponse{
+		Success:         true,
+		DefaultMetadata: defaultMetadata(t),
+	}
+}
+
+// defaultMetadata returns the default metadata of a tenant, empty rather than nil
+func defaultMetadata(t *tenant.Tenant) map[string]string {
+	if t.DefaultMetadata == nil {
+		return map[string]string{}
+	}
+	return t.DefaultMetadata
+}
+
+// ToTenantResponseList converts a slice of domain tenants to TenantResponse slice
+func ToTenantResponseList(tenants []*tenant.Tenant) []TenantResponse {
+	responses := make([]TenantResponse, 0, len(tenants))
+	for _, t := range tenants {
+		responses = append(responses, ToTenantResponse(t))
+	}
+
+	return responses
+}
+
+// ToOnboardingResponse converts a tenant onboarding to OnboardingResponse
+func ToOnboardingResponse(o *tenant.Onboarding) OnboardingResponse {
+	return OnboardingResponse{
+		Tenant: ToTenantResponse(o.Tenant),
+		APIKey: apikeys.CreatedKeyResponse{
+			KeyResponse: apikeys.ToKeyResponse(o.Key),
+			Key:         o.Secret,
+		},
+	}
+}
+
+// ToImpersonationResponseList converts a slice of domain impersonations to ImpersonationResponse slice
+func ToImpersonationResponseList(imps []*tenant.Impersonation) []ImpersonationResponse {
+	responses := make([]ImpersonationResponse, 0, len(imps))
+	for _, i := range imps {
+		responses = append(responses, ImpersonationResponse{
+			ID:         i.ID,
+			ActorKeyID: i.ActorKeyID,
+			ActorName:  i.ActorName,
+			Method:     i.Method,
+			Path:       i.Path,
+			ClientIP:   i.ClientIP,
+			Status:     i.Status,
+			CreatedAt:  jsonfmt.NewTime(i.CreatedAt),
+		})
+	}
+
+	return responses
+}
+
+// ToSigningKeyResponse converts a domain tenant.SigningKey to SigningKeyResponse
+func ToSigningKeyResponse(k *tenant.SigningKey) SigningKeyResponse {
+	response := SigningKeyResponse{
+		ID:        k.ID,
+		Algorithm: string(k.Algorithm),
+		Active:    k.Active(),
+		CreatedAt: jsonfmt.NewTime(k.CreatedAt),
+		ExpiresAt: jsonfmt.NewTimePtr(k.ExpiresAt),
+	}
+	if k.PublicKey != nil {
+		publicKey := base64.StdEncoding.EncodeToString(k.PublicKey)
+		response.PublicKey = &publicKey
+	}
+
+	return response
+}
+
+// ToCreatedSigningKeyResponse converts a newly created signing key, revealing the secret of HMAC keys
+func ToCreatedSigningKeyResponse(k *tenant.SigningKey) CreatedSigningKeyResponse {
+	response := CreatedSigningKeyResponse{SigningKeyResponse: ToSigningKeyResponse(k)}
+	if !k.Algorithm.Asymmetric() {
+		secret := base64.StdEncoding.EncodeToString(k.Secret)
+		response.Secret = &secret
+	}
+
+	return response
+}
+
+// ToSigningVerificationResponse describes the verification of callbacks signed with keys
+func ToSigningVerificationResponse(keys []*tenant.SigningKey) SigningVerificationResponse {
+	responses := make([]SigningKeyResponse, 0, len(keys))
+	for _, k := range keys {
+		responses = append(responses, ToSigningKeyResponse(k))
+	}
+
+	return SigningVerificationResponse{
+		Success:       true,
+		Header:        tenant.SignatureHeader,
+		SignedPayload: "<t>.<raw request body>",
+		Instructions: []string{
+			"Read the " + tenant.SignatureHeader + " header, formatted as t=<unix seconds>,v1=<key id>:<base64 signature> with one v1 entry per valid key",
+			"Reject callbacks whose t is more than 5 minutes away from your clock to prevent replays",
+			"Build the signed payload from t, a dot and the raw request body",
+			"hmac-sha256 and hmac-sha512: compute the HMAC of the signed payload with the base64-decoded secret returned when the key was created and compare it to the signature in constant time",
+			"ed25519: verify the signature of the signed payload with the base64-decoded publicKey",
+			"Accept the callback when the signature of any key you hold verifies; after a rotation the previous key keeps signing until its expiresAt",
+		},
+		Keys: responses,
+	}
+}
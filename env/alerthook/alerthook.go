@@ -0,0 +1,131 @@
+// Package alerthook posts operational alerts to a chat or paging webhook, e.g. Slack or PagerDuty
+package alerthook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"qubit/pkg/buildinfo"
+)
+
+// Webhook formats
+const (
+	// FormatSlack posts {"text": ...}, understood by Slack incoming webhooks and most chat tools
+	FormatSlack = "slack"
+	// FormatPagerDuty posts a PagerDuty Events API v2 event, triggered and resolved by the alert key
+	FormatPagerDuty = "pagerduty"
+)
+
+// Config configures the alert webhook
+type Config struct {
+	URL        string
+	Format     string // FormatSlack or FormatPagerDuty
+	RoutingKey string // integration key of the PagerDuty service, only used with FormatPagerDuty
+}
+
+// Alert is a condition raised or resolved
+type Alert struct {
+	// Key identifies the condition, so the resolution closes the alert it raised
+	Key      string
+	Summary  string
+	Resolved bool
+	// Details are attached as custom details where the format supports them
+	Details map[string]any
+}
+
+// Client posts alerts to the configured webhook
+type Client struct {
+	url        string
+	format     string
+	routingKey string
+	instance   string
+	http       *http.Client
+}
+
+// NewClient creates a client posting to cfg.URL as the instance instanceID
+// Calls are bounded by the caller's context
+func NewClient(cfg Config, instanceID string) *Client {
+	return &Client{
+		url:        cfg.URL,
+		format:     cfg.Format,
+		routingKey: cfg.RoutingKey,
+		instance:   instanceID,
+		http:       &http.Client{},
+	}
+}
+
+// slackMessage is the body of a Slack incoming webhook
+type slackMessage struct {
+	Text string `json:"text"`
+}
+
+// pagerDutyEvent is the body of a PagerDuty Events API v2 event
+type pagerDutyEvent struct {
+	RoutingKey  string            `json:"routing_key"`
+	EventAction string            `json:"event_action"`
+	DedupKey    string            `json:"dedup_key"`
+	Payload     *pagerDutyPayload `json:"payload,omitempty"`
+}
+
+// pagerDutyPayload describes a triggered PagerDuty event
+type pagerDutyPayload struct {
+	Summary       string         `json:"summary"`
+	Source        string         `json:"source"`
+	Severity      string         `json:"severity"`
+	CustomDetails map[string]any `json:"custom_details,omitempty"`
+}
+
+// Notify posts the alert, a response outside 2xx is an error
+func (c *Client) Notify(ctx context.Context, alert Alert) error {
+	body, err := json.Marshal(c.body(alert))
+	if err != nil {
+		return fmt.Errorf("failed to encode alert: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", buildinfo.UserAgent())
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("alert webhook answered %s", resp.Status)
+	}
+
+	return nil
+}
+
+// body returns the request body of the alert in the configured format
+func (c *Client) body(alert Alert) any {
+	if c.format != FormatPagerDuty {
+		text := "🚨 " + alert.Summary
+		if alert.Resolved {
+			text = "✅ " + alert.Summary
+		}
+		return slackMessage{Text: fmt.Sprintf("[%s] %s", c.instance, text)}
+	}
+
+	event := pagerDutyEvent{RoutingKey: c.routingKey, EventAction: "resolve", DedupKey: alert.Key}
+	if !alert.Resolved {
+		event.EventAction = "trigger"
+		event.Payload = &pagerDutyPayload{
+			Summary:       alert.Summary,
+			Source:        c.instance,
+			Severity:      "error",
+			CustomDetails: alert.Details,
+		}
+	}
+	return event
+}
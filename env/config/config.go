@@ -1,9 +1,18 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
+	"net/url"
 	"os"
 	"strconv"
+	"strings"
+	"time"
+
+	"qubit/env/alerthook"
+	"qubit/pkg/scheduler"
+	"qubit/service/shard"
 
 	"github.com/joho/godotenv"
 )
@@ -11,18 +20,202 @@ import (
 // Config holds all application configuration
 type Config struct {
 	// Database configuration
-	DatabaseURL string
+	DatabaseURL   string
+	RunMigrations bool
+
+	// MigrationLockTimeout is how long a migrator waits for another replica migrating the same database
+	MigrationLockTimeout time.Duration
+
+	// Redis configuration, an empty URL disables the delivery cache
+	RedisURL              string
+	DeliveryCacheTTLHours int
+
+	// Deadline of a delivery cache or rate limit call, and the consecutive failures opening the Redis circuit (0 disables it)
+	RedisTimeout          time.Duration
+	RedisCircuitThreshold int
+	RedisCircuitOpen      time.Duration
+
+	// Provider configuration, the first provider is the default one
+	Providers []ProviderConfig
+
+	// How often the templates of template-only providers and their approval status are synced
+	ProvidersTemplateSyncInterval time.Duration
+
+	// Webhook connection warm-up, re-warm after this many idle seconds (0 disables)
+	WebhookKeepWarmSeconds int
+
+	// Deadline of a single provider call, on top of the provider's own timeoutSeconds
+	WebhookTimeout time.Duration
 
-	// Webhook configuration
-	WebhookURL     string
-	WebhookAuthKey string
+	// Include the reachability of every webhook provider in the readiness probe
+	HealthCheckWebhook bool
 
-	// Server configuration
+	// Send through simulated senders instead of the real providers until an operator switches it at runtime
+	FakeSender bool
+	// Keep the simulated senders, switching to the real providers at runtime is refused
+	FakeSenderLocked bool
+
+	// Canary message sent through the full pipeline, an empty CanaryPhoneNumber disables it
+	// An empty CanaryProvider uses the default provider
+	CanaryPhoneNumber string
+	CanaryProvider    string
+	CanaryOnStartup   bool
+	CanaryTimeout     time.Duration
+
+	// Circuit breaker around every provider, a threshold of 0 disables it
+	CircuitBreakerThreshold      int
+	CircuitBreakerOpenSeconds    int
+	CircuitBreakerHalfOpenProbes int
+
+	// Server configuration, InstanceID identifies this instance on outbound calls
+	// An empty GRPCPort disables the gRPC API
 	ServerPort string
+	GRPCPort   string
+	InstanceID string
+
+	// API key configuration, AdminAPIKey is a bootstrap key with the admin:* scope
+	AdminAPIKey     string
+	APIKeysRequired bool
+
+	// API timestamps are RFC 3339 in UTC, with milliseconds when set
+	APITimestampMillis bool
+
+	// Messages a list response holds unless streamed, more fail with list_too_large (0 disables the cap)
+	ListMaxItems int
+
+	// Rate limiting of message creation per API key or client IP, 0 disables it
+	RateLimitPerMinute int
+	RateLimitBurst     int
+	RateLimitRedis     bool
+
+	// Operational headers on POST /messages, each response costs an extra count of the due messages
+	MessageProcessingHeaders bool
+
+	// Scheduler configuration, SchedulerTickLock lets a single instance at a time run a tick
+	SchedulerInterval     time.Duration
+	SchedulerCron         string
+	SchedulerTickLock     bool
+	MessageBatchSize      int
+	DispatchWorkers       int
+	PersistChunkSize      int
+	SendingTimeoutMinutes int
+
+	// Webhook calls per second of this instance, messages beyond it wait for the next tick (0 disables the limit)
+	SendRatePerSecond int
+
+	// Scheduler leader election through postgres or redis, only the elected instance runs the scheduler
+	// An empty SchedulerLeaderElection disables it; the leadership is renewed every third of SchedulerLeaderTTL
+	SchedulerLeaderElection string
+	SchedulerLeaderTTL      time.Duration
+
+	// Queue shards this instance claims from: "auto" splits them between the live instances, or ranges like "0-511,768-1023"
+	// An empty SchedulerShards claims from the whole queue; auto membership is refreshed every third of SchedulerShardTTL
+	SchedulerShards   string
+	SchedulerShardTTL time.Duration
+
+	// Longest interval the scheduler stretches to while the queue stays empty, 0 disables adaptive polling
+	SchedulerIdleMaxInterval time.Duration
+	// Run a batch as soon as messages are inserted, announced through PostgreSQL NOTIFY
+	SchedulerNotifyDispatch bool
+	// How long the report of every scheduler run is kept in dispatch_runs, 0 keeps them forever
+	SchedulerRunRetention time.Duration
+	// How overdue the scheduler loop may be before /health/live fails, 0 keeps the scheduler out of liveness
+	SchedulerStallTimeout time.Duration
+
+	// Retry configuration
+	MaxRetries            int
+	RetryBaseDelaySeconds int
+	RetryMaxDelaySeconds  int
+
+	// Per-recipient limit, RecipientLimitAction is defer or reject (0 disables the limit)
+	RecipientLimitMax           int
+	RecipientLimitWindowMinutes int
+	RecipientLimitAction        string
+
+	// Domains links in message content may point to, URLAllowlistAction is reject or quarantine (empty disables the check)
+	URLAllowlist       []string
+	URLAllowlistAction string
+
+	// URLAllowlistRecheck is how claimed messages are checked again before sending: policy, quarantine or off
+	URLAllowlistRecheck string
+
+	// Archival of sent messages older than MessageRetentionDays, MessageRetentionAction is archive or delete (0 disables it)
+	MessageRetentionDays   int
+	MessageRetentionAction string
+	MessageArchiveInterval time.Duration
+
+	// How long API requests rejected with 400 are kept for support to inspect and replay, 0 disables capturing
+	RequestCaptureRetention time.Duration
+
+	// Masking of phone numbers in the log output
+	LogMaskPII bool
+	// Roles seeing phone numbers and content in list responses unmasked, empty masks only on ?masked=true
+	PIIUnmaskedRoles []string
+
+	// Mirroring of a sample of created messages to a staging deployment as test messages, an empty MirrorURL disables it
+	MirrorURL     string
+	MirrorAPIKey  string
+	MirrorPercent int
+	MirrorTimeout time.Duration
 
-	// Scheduler configuration
-	SchedulerIntervalMinutes int
-	MessageBatchSize         int
+	// Campaign configuration
+	CampaignLaunchIntervalMinutes int
+
+	// Inbound reply configuration
+	ReplyWindowMinutes int
+
+	// Locales tried after the recipient's own when picking a translated content variant
+	LocaleFallback []string
+
+	// Lifecycle event publishing through the outbox, an empty broker disables it
+	EventsBroker         string
+	EventsURLs           []string
+	EventsTopic          string
+	EventsRelayInterval  time.Duration
+	EventsBatchSize      int
+	EventsRetentionHours int
+
+	// Queue ingestion of message creation requests, an empty broker disables it
+	IngestBroker      string
+	IngestURLs        []string
+	IngestTopic       string
+	IngestGroupID     string
+	IngestConcurrency int
+
+	// In-memory caches of rarely changing tables, reloaded on change notifications and at least this often
+	CacheRefreshInterval time.Duration
+
+	// Default quotas of onboarded tenants
+	TenantDailyMessageQuota  int
+	TenantRateLimitPerMinute int
+
+	// Detection of tenants whose create or send rate spikes against their baseline, disabled unless TenantAnomalyDetection
+	// A positive TenantAnomalyThrottle blocks message creation of an alerted tenant that long unless acknowledged earlier
+	TenantAnomalyDetection   bool
+	TenantAnomalyInterval    time.Duration
+	TenantAnomalyBaseline    time.Duration
+	TenantAnomalyFactor      int
+	TenantAnomalyMinMessages int
+	TenantAnomalyThrottle    time.Duration
+
+	// Alerting on a backlog older or larger than its thresholds through a webhook, an empty BacklogAlertWebhookURL disables it
+	// A zero threshold disables its check; both can be changed at runtime
+	BacklogAlertWebhookURL string
+	BacklogAlertFormat     string
+	BacklogAlertRoutingKey string
+	BacklogAlertInterval   time.Duration
+	BacklogAlertMaxAge     time.Duration
+	BacklogAlertMaxSize    int
+
+	// Watchdog logging goroutine stacks and alerting when the scheduler stalls past SchedulerStallTimeout,
+	// a batch holds its lock longer than WatchdogLockHold or goroutines grow by WatchdogGoroutineGrowth
+	// A zero WatchdogInterval disables it, a zero threshold skips its check
+	WatchdogInterval        time.Duration
+	WatchdogLockHold        time.Duration
+	WatchdogGoroutineGrowth int
+
+	// Strict mode fails startup on unrecognized application environment variables
+	Strict bool
 }
 
 // Load reads configuration from environment variables
@@ -34,15 +227,176 @@ func Load() (*Config, error) {
 	// 3. Missing required variables will be caught by Validate() below
 	// Note: This also silently ignores syntax errors in .env - if variables seem missing,
 	// check your .env file for typos or malformed lines
-	_ = godotenv.Load()
+	_ = loadDotenv()
+
+	return load()
+}
+
+// Reload reads the configuration again, picking up changes of the .env file and PROVIDERS_FILE
+// Variables set in the process environment keep precedence over the .env file like with Load,
+// variables removed from the .env file are unset; a malformed .env file fails the reload
+func Reload() (*Config, error) {
+	if err := loadDotenv(); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read .env: %w", err)
+	}
+
+	return load()
+}
+
+// processEnv holds the variables set in the process environment before .env was first read
+var processEnv map[string]bool
+
+// dotenvKeys holds the variables last set from the .env file
+var dotenvKeys = map[string]bool{}
+
+// loadDotenv sets the variables of the .env file that the process environment does not set
+func loadDotenv() error {
+	if processEnv == nil {
+		processEnv = make(map[string]bool)
+		for _, kv := range os.Environ() {
+			key, _, _ := strings.Cut(kv, "=")
+			processEnv[key] = true
+		}
+	}
+
+	values, err := godotenv.Read()
+	if err != nil {
+		return err
+	}
+
+	for key := range dotenvKeys {
+		if _, ok := values[key]; !ok {
+			_ = os.Unsetenv(key)
+			delete(dotenvKeys, key)
+		}
+	}
+	for key, value := range values {
+		if processEnv[key] {
+			continue
+		}
+		_ = os.Setenv(key, value)
+		dotenvKeys[key] = true
+	}
+
+	return nil
+}
+
+// load reads the configuration from the environment and validates it
+func load() (*Config, error) {
+	providers, err := loadProviders()
+	if err != nil {
+		return nil, err
+	}
 
 	cfg := &Config{
-		DatabaseURL:              getEnv("DATABASE_URL", ""),
-		WebhookURL:               getEnv("WEBHOOK_URL", ""),
-		WebhookAuthKey:           getEnv("WEBHOOK_AUTH_KEY", ""),
-		ServerPort:               getEnv("SERVER_PORT", "8080"),
-		SchedulerIntervalMinutes: getEnvAsInt("SCHEDULER_INTERVAL_MINUTES", 2),
-		MessageBatchSize:         getEnvAsInt("MESSAGE_BATCH_SIZE", 2),
+		DatabaseURL:                   getEnv("DATABASE_URL", ""),
+		RunMigrations:                 getEnvAsBool("RUN_MIGRATIONS", false),
+		MigrationLockTimeout:          getEnvAsDuration("MIGRATION_LOCK_TIMEOUT", 5*time.Minute),
+		RedisURL:                      getEnv("REDIS_URL", ""),
+		RedisTimeout:                  getEnvAsDuration("REDIS_TIMEOUT", 500*time.Millisecond),
+		RedisCircuitThreshold:         getEnvAsInt("REDIS_CIRCUIT_THRESHOLD", 5),
+		RedisCircuitOpen:              getEnvAsDuration("REDIS_CIRCUIT_OPEN", 30*time.Second),
+		DeliveryCacheTTLHours:         getEnvAsInt("DELIVERY_CACHE_TTL_HOURS", 24),
+		Providers:                     providers,
+		ProvidersTemplateSyncInterval: getEnvAsDuration("PROVIDERS_TEMPLATE_SYNC_INTERVAL", 15*time.Minute),
+		WebhookKeepWarmSeconds:        getEnvAsInt("WEBHOOK_KEEP_WARM_SECONDS", 60),
+		WebhookTimeout:                getEnvAsDuration("WEBHOOK_TIMEOUT", 30*time.Second),
+		HealthCheckWebhook:            getEnvAsBool("HEALTH_CHECK_WEBHOOK", false),
+		FakeSender:                    getEnvAsBool("FAKE_SENDER", false),
+		FakeSenderLocked:              getEnvAsBool("FAKE_SENDER_LOCKED", false),
+		CanaryPhoneNumber:             getEnv("CANARY_PHONE_NUMBER", ""),
+		CanaryProvider:                getEnv("CANARY_PROVIDER", ""),
+		CanaryOnStartup:               getEnvAsBool("CANARY_ON_STARTUP", true),
+		CanaryTimeout:                 getEnvAsDuration("CANARY_TIMEOUT", time.Minute),
+		CircuitBreakerThreshold:       getEnvAsInt("CIRCUIT_BREAKER_THRESHOLD", 5),
+		CircuitBreakerOpenSeconds:     getEnvAsInt("CIRCUIT_BREAKER_OPEN_SECONDS", 30),
+		CircuitBreakerHalfOpenProbes:  getEnvAsInt("CIRCUIT_BREAKER_HALF_OPEN_PROBES", 1),
+		ServerPort:                    getEnv("SERVER_PORT", "8080"),
+		GRPCPort:                      getEnv("GRPC_PORT", ""),
+		InstanceID:                    getEnv("INSTANCE_ID", defaultInstanceID()),
+		AdminAPIKey:                   getEnv("ADMIN_API_KEY", ""),
+		APIKeysRequired:               getEnvAsBool("API_KEYS_REQUIRED", false),
+		APITimestampMillis:            getEnvAsBool("API_TIMESTAMP_MILLIS", false),
+		ListMaxItems:                  getEnvAsInt("LIST_MAX_ITEMS", 10000),
+		RateLimitPerMinute:            getEnvAsInt("RATE_LIMIT_PER_MINUTE", 0),
+		RateLimitBurst:                getEnvAsInt("RATE_LIMIT_BURST", 0),
+		RateLimitRedis:                getEnvAsBool("RATE_LIMIT_REDIS", false),
+		MessageProcessingHeaders:      getEnvAsBool("MESSAGE_PROCESSING_HEADERS", true),
+		SchedulerInterval:             getEnvAsDuration("SCHEDULER_INTERVAL", time.Duration(getEnvAsInt("SCHEDULER_INTERVAL_MINUTES", 2))*time.Minute),
+		SchedulerCron:                 getEnv("SCHEDULER_CRON", ""),
+		SchedulerTickLock:             getEnvAsBool("SCHEDULER_TICK_LOCK", false),
+		SchedulerLeaderElection:       getEnv("SCHEDULER_LEADER_ELECTION", ""),
+		SchedulerLeaderTTL:            getEnvAsDuration("SCHEDULER_LEADER_TTL", 15*time.Second),
+		SchedulerShards:               getEnv("SCHEDULER_SHARDS", ""),
+		SchedulerShardTTL:             getEnvAsDuration("SCHEDULER_SHARD_TTL", 30*time.Second),
+		SchedulerIdleMaxInterval:      getEnvAsDuration("SCHEDULER_IDLE_MAX_INTERVAL", 0),
+		SchedulerNotifyDispatch:       getEnvAsBool("SCHEDULER_NOTIFY_DISPATCH", false),
+		SchedulerStallTimeout:         getEnvAsDuration("SCHEDULER_STALL_TIMEOUT", 10*time.Minute),
+		SchedulerRunRetention:         getEnvAsDuration("SCHEDULER_RUN_RETENTION", 30*24*time.Hour),
+		MessageBatchSize:              getEnvAsInt("MESSAGE_BATCH_SIZE", 2),
+		DispatchWorkers:               getEnvAsInt("DISPATCH_WORKERS", 4),
+		SendRatePerSecond:             getEnvAsInt("SEND_RATE_PER_SECOND", 0),
+		PersistChunkSize:              getEnvAsInt("MESSAGE_PERSIST_CHUNK_SIZE", 100),
+		SendingTimeoutMinutes:         getEnvAsInt("SENDING_TIMEOUT_MINUTES", 10),
+		MaxRetries:                    getEnvAsInt("MAX_RETRIES", 5),
+		RetryBaseDelaySeconds:         getEnvAsInt("RETRY_BASE_DELAY_SECONDS", 30),
+		RetryMaxDelaySeconds:          getEnvAsInt("RETRY_MAX_DELAY_SECONDS", 3600),
+		RecipientLimitMax:             getEnvAsInt("RECIPIENT_LIMIT_MAX", 0),
+		RecipientLimitWindowMinutes:   getEnvAsInt("RECIPIENT_LIMIT_WINDOW_MINUTES", 60),
+		RecipientLimitAction:          getEnv("RECIPIENT_LIMIT_ACTION", "defer"),
+		URLAllowlist:                  getEnvAsList("URL_ALLOWLIST"),
+		URLAllowlistAction:            getEnv("URL_ALLOWLIST_ACTION", "reject"),
+		URLAllowlistRecheck:           getEnv("URL_ALLOWLIST_RECHECK", "policy"),
+		MessageRetentionDays:          getEnvAsInt("MESSAGE_RETENTION_DAYS", 0),
+		MessageRetentionAction:        getEnv("MESSAGE_RETENTION_ACTION", "archive"),
+		MessageArchiveInterval:        getEnvAsDuration("MESSAGE_ARCHIVE_INTERVAL", time.Hour),
+		RequestCaptureRetention:       getEnvAsDuration("REQUEST_CAPTURE_RETENTION", 0),
+		LogMaskPII:                    getEnvAsBool("LOG_MASK_PII", true),
+		PIIUnmaskedRoles:              getEnvAsList("PII_UNMASKED_ROLES"),
+		MirrorURL:                     strings.TrimSuffix(getEnv("MIRROR_URL", ""), "/"),
+		MirrorAPIKey:                  getEnv("MIRROR_API_KEY", ""),
+		MirrorPercent:                 getEnvAsInt("MIRROR_PERCENT", 1),
+		MirrorTimeout:                 getEnvAsDuration("MIRROR_TIMEOUT", 5*time.Second),
+		CampaignLaunchIntervalMinutes: getEnvAsInt("CAMPAIGN_LAUNCH_INTERVAL_MINUTES", 1),
+		ReplyWindowMinutes:            getEnvAsInt("REPLY_WINDOW_MINUTES", 1440),
+		LocaleFallback:                getEnvAsListOr("LOCALE_FALLBACK", []string{"en"}),
+		EventsBroker:                  getEnv("EVENTS_BROKER", ""),
+		EventsURLs:                    getEnvAsList("EVENTS_URL"),
+		EventsTopic:                   getEnv("EVENTS_TOPIC", "qubit.events"),
+		EventsRelayInterval:           getEnvAsDuration("EVENTS_RELAY_INTERVAL", time.Second),
+		EventsBatchSize:               getEnvAsInt("EVENTS_BATCH_SIZE", 100),
+		EventsRetentionHours:          getEnvAsInt("EVENTS_RETENTION_HOURS", 72),
+		IngestBroker:                  getEnv("INGEST_BROKER", ""),
+		IngestURLs:                    getEnvAsList("INGEST_URL"),
+		IngestTopic:                   getEnv("INGEST_TOPIC", "qubit.messages"),
+		IngestGroupID:                 getEnv("INGEST_GROUP_ID", "qubit"),
+		IngestConcurrency:             getEnvAsInt("INGEST_CONCURRENCY", 10),
+		CacheRefreshInterval:          getEnvAsDuration("CACHE_REFRESH_INTERVAL", 5*time.Minute),
+		TenantDailyMessageQuota:       getEnvAsInt("TENANT_DAILY_MESSAGE_QUOTA", 10000),
+		TenantRateLimitPerMinute:      getEnvAsInt("TENANT_RATE_LIMIT_PER_MINUTE", 60),
+		TenantAnomalyDetection:        getEnvAsBool("TENANT_ANOMALY_DETECTION", false),
+		TenantAnomalyInterval:         getEnvAsDuration("TENANT_ANOMALY_INTERVAL", 5*time.Minute),
+		TenantAnomalyBaseline:         getEnvAsDuration("TENANT_ANOMALY_BASELINE", time.Hour),
+		TenantAnomalyFactor:           getEnvAsInt("TENANT_ANOMALY_FACTOR", 20),
+		TenantAnomalyMinMessages:      getEnvAsInt("TENANT_ANOMALY_MIN_MESSAGES", 100),
+		TenantAnomalyThrottle:         getEnvAsDuration("TENANT_ANOMALY_THROTTLE", 0),
+		BacklogAlertWebhookURL:        getEnv("BACKLOG_ALERT_WEBHOOK_URL", ""),
+		BacklogAlertFormat:            getEnv("BACKLOG_ALERT_FORMAT", alerthook.FormatSlack),
+		BacklogAlertRoutingKey:        getEnv("BACKLOG_ALERT_ROUTING_KEY", ""),
+		BacklogAlertInterval:          getEnvAsDuration("BACKLOG_ALERT_INTERVAL", time.Minute),
+		BacklogAlertMaxAge:            getEnvAsDuration("BACKLOG_ALERT_MAX_AGE", 15*time.Minute),
+		BacklogAlertMaxSize:           getEnvAsInt("BACKLOG_ALERT_MAX_SIZE", 10000),
+		WatchdogInterval:              getEnvAsDuration("WATCHDOG_INTERVAL", time.Minute),
+		WatchdogLockHold:              getEnvAsDuration("WATCHDOG_LOCK_HOLD", 10*time.Minute),
+		WatchdogGoroutineGrowth:       getEnvAsInt("WATCHDOG_GOROUTINE_GROWTH", 5000),
+		Strict:                        getEnvAsBool("CONFIG_STRICT", false),
+	}
+
+	// Every variable has been read at this point, anything else with an application prefix is a typo
+	if cfg.Strict {
+		if err := checkUnknown(); err != nil {
+			return nil, err
+		}
 	}
 
 	// Validate required fields
@@ -59,27 +413,354 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("DATABASE_URL is required")
 	}
 
-	if c.WebhookURL == "" {
-		return fmt.Errorf("WEBHOOK_URL is required")
+	if err := validateProviders(c.Providers); err != nil {
+		return err
+	}
+
+	if c.ProvidersTemplateSyncInterval < scheduler.MinInterval {
+		return fmt.Errorf("PROVIDERS_TEMPLATE_SYNC_INTERVAL must be at least %s", scheduler.MinInterval)
+	}
+
+	if c.WebhookKeepWarmSeconds < 0 {
+		return fmt.Errorf("WEBHOOK_KEEP_WARM_SECONDS must not be negative")
+	}
+
+	if c.WebhookTimeout <= 0 {
+		return fmt.Errorf("WEBHOOK_TIMEOUT must be greater than 0")
+	}
+
+	if c.CircuitBreakerThreshold < 0 {
+		return fmt.Errorf("CIRCUIT_BREAKER_THRESHOLD must not be negative")
+	}
+
+	if c.CircuitBreakerOpenSeconds <= 0 {
+		return fmt.Errorf("CIRCUIT_BREAKER_OPEN_SECONDS must be greater than 0")
+	}
+
+	if c.CircuitBreakerHalfOpenProbes <= 0 {
+		return fmt.Errorf("CIRCUIT_BREAKER_HALF_OPEN_PROBES must be greater than 0")
+	}
+
+	if c.MigrationLockTimeout <= 0 {
+		return fmt.Errorf("MIGRATION_LOCK_TIMEOUT must be greater than 0")
+	}
+
+	if c.DeliveryCacheTTLHours <= 0 {
+		return fmt.Errorf("DELIVERY_CACHE_TTL_HOURS must be greater than 0")
+	}
+
+	if c.RedisTimeout <= 0 {
+		return fmt.Errorf("REDIS_TIMEOUT must be greater than 0")
+	}
+
+	if c.RedisCircuitThreshold < 0 {
+		return fmt.Errorf("REDIS_CIRCUIT_THRESHOLD must not be negative")
+	}
+
+	if c.RedisCircuitOpen <= 0 {
+		return fmt.Errorf("REDIS_CIRCUIT_OPEN must be greater than 0")
+	}
+
+	if c.SchedulerInterval < scheduler.MinInterval {
+		return fmt.Errorf("SCHEDULER_INTERVAL (or SCHEDULER_INTERVAL_MINUTES) must be at least %s", scheduler.MinInterval)
+	}
+
+	if c.SchedulerCron != "" {
+		if _, err := scheduler.ParseCron(c.SchedulerCron); err != nil {
+			return fmt.Errorf("SCHEDULER_CRON is invalid: %w", err)
+		}
+	}
+
+	if c.SchedulerLeaderElection != "" {
+		if c.SchedulerLeaderElection != "postgres" && c.SchedulerLeaderElection != "redis" {
+			return fmt.Errorf("SCHEDULER_LEADER_ELECTION must be postgres or redis")
+		}
+
+		if c.SchedulerLeaderElection == "redis" && c.RedisURL == "" {
+			return fmt.Errorf("REDIS_URL is required when SCHEDULER_LEADER_ELECTION is redis")
+		}
+
+		// The lease is renewed every third of its TTL
+		if c.SchedulerLeaderTTL < 3*scheduler.MinInterval {
+			return fmt.Errorf("SCHEDULER_LEADER_TTL must be at least %s", 3*scheduler.MinInterval)
+		}
+	}
+
+	if c.SchedulerShards != "" {
+		if c.SchedulerLeaderElection != "" {
+			return fmt.Errorf("SCHEDULER_SHARDS cannot be combined with SCHEDULER_LEADER_ELECTION, only the leader would claim")
+		}
+
+		if c.SchedulerShards == shard.ModeAuto {
+			// Membership is refreshed every third of the TTL
+			if c.SchedulerShardTTL < 3*scheduler.MinInterval {
+				return fmt.Errorf("SCHEDULER_SHARD_TTL must be at least %s", 3*scheduler.MinInterval)
+			}
+		} else if _, err := shard.ParseRanges(c.SchedulerShards); err != nil {
+			return fmt.Errorf("SCHEDULER_SHARDS must be auto or shard ranges: %w", err)
+		}
+	}
+
+	if c.SchedulerIdleMaxInterval < 0 {
+		return fmt.Errorf("SCHEDULER_IDLE_MAX_INTERVAL must not be negative")
 	}
 
-	if c.WebhookAuthKey == "" {
-		return fmt.Errorf("WEBHOOK_AUTH_KEY is required")
+	if c.SchedulerRunRetention < 0 {
+		return fmt.Errorf("SCHEDULER_RUN_RETENTION must not be negative")
 	}
 
-	if c.SchedulerIntervalMinutes <= 0 {
-		return fmt.Errorf("SCHEDULER_INTERVAL_MINUTES must be greater than 0")
+	// A tick may legitimately run until it times out, a shorter timeout would restart busy instances
+	if c.SchedulerStallTimeout != 0 && c.SchedulerStallTimeout <= scheduler.TaskTimeout {
+		return fmt.Errorf("SCHEDULER_STALL_TIMEOUT must be longer than %s, or 0 to disable", scheduler.TaskTimeout)
 	}
 
 	if c.MessageBatchSize <= 0 {
 		return fmt.Errorf("MESSAGE_BATCH_SIZE must be greater than 0")
 	}
 
+	if c.DispatchWorkers <= 0 {
+		return fmt.Errorf("DISPATCH_WORKERS must be greater than 0")
+	}
+
+	if c.PersistChunkSize <= 0 {
+		return fmt.Errorf("MESSAGE_PERSIST_CHUNK_SIZE must be greater than 0")
+	}
+
+	if c.SendRatePerSecond < 0 {
+		return fmt.Errorf("SEND_RATE_PER_SECOND must not be negative")
+	}
+
+	if c.RateLimitPerMinute < 0 || c.RateLimitPerMinute > 60000 {
+		return fmt.Errorf("RATE_LIMIT_PER_MINUTE must be between 0 and 60000")
+	}
+
+	if c.RateLimitBurst < 0 {
+		return fmt.Errorf("RATE_LIMIT_BURST must not be negative")
+	}
+
+	// A lease shorter than a tick would let the reaper hand a message to another instance while it is still being sent
+	if time.Duration(c.SendingTimeoutMinutes)*time.Minute <= scheduler.TaskTimeout {
+		return fmt.Errorf("SENDING_TIMEOUT_MINUTES must be longer than %s", scheduler.TaskTimeout)
+	}
+
+	if c.MaxRetries < 0 {
+		return fmt.Errorf("MAX_RETRIES must not be negative")
+	}
+
+	if c.RetryBaseDelaySeconds <= 0 {
+		return fmt.Errorf("RETRY_BASE_DELAY_SECONDS must be greater than 0")
+	}
+
+	if c.RetryMaxDelaySeconds < c.RetryBaseDelaySeconds {
+		return fmt.Errorf("RETRY_MAX_DELAY_SECONDS must not be less than RETRY_BASE_DELAY_SECONDS")
+	}
+
+	if c.RecipientLimitMax < 0 {
+		return fmt.Errorf("RECIPIENT_LIMIT_MAX must not be negative")
+	}
+
+	if c.RecipientLimitWindowMinutes <= 0 {
+		return fmt.Errorf("RECIPIENT_LIMIT_WINDOW_MINUTES must be greater than 0")
+	}
+
+	if c.RecipientLimitAction != "defer" && c.RecipientLimitAction != "reject" {
+		return fmt.Errorf("RECIPIENT_LIMIT_ACTION must be defer or reject")
+	}
+
+	for _, domain := range c.URLAllowlist {
+		if strings.ContainsAny(domain, "/:*@ ") {
+			return fmt.Errorf("URL_ALLOWLIST entry %q must be a domain without scheme, port, path or wildcard", domain)
+		}
+	}
+
+	if c.URLAllowlistAction != "reject" && c.URLAllowlistAction != "quarantine" {
+		return fmt.Errorf("URL_ALLOWLIST_ACTION must be reject or quarantine")
+	}
+
+	switch c.URLAllowlistRecheck {
+	case "policy", "quarantine", "off":
+	default:
+		return fmt.Errorf("URL_ALLOWLIST_RECHECK must be policy, quarantine or off")
+	}
+
+	if c.MessageRetentionDays < 0 {
+		return fmt.Errorf("MESSAGE_RETENTION_DAYS must not be negative")
+	}
+
+	if c.MessageRetentionAction != "archive" && c.MessageRetentionAction != "delete" {
+		return fmt.Errorf("MESSAGE_RETENTION_ACTION must be archive or delete")
+	}
+
+	if c.MessageArchiveInterval < scheduler.MinInterval {
+		return fmt.Errorf("MESSAGE_ARCHIVE_INTERVAL must be at least %s", scheduler.MinInterval)
+	}
+
+	if c.ListMaxItems < 0 {
+		return fmt.Errorf("LIST_MAX_ITEMS must not be negative")
+	}
+
+	if c.RequestCaptureRetention < 0 {
+		return fmt.Errorf("REQUEST_CAPTURE_RETENTION must not be negative")
+	}
+
+	if c.MirrorURL != "" {
+		if u, err := url.Parse(c.MirrorURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("MIRROR_URL must be an http or https URL")
+		}
+
+		// Without a sandbox key the mirrored messages would be sent for real by staging
+		if c.MirrorAPIKey == "" {
+			return fmt.Errorf("MIRROR_API_KEY is required when MIRROR_URL is set")
+		}
+
+		if c.MirrorPercent < 1 || c.MirrorPercent > 100 {
+			return fmt.Errorf("MIRROR_PERCENT must be between 1 and 100")
+		}
+
+		if c.MirrorTimeout <= 0 {
+			return fmt.Errorf("MIRROR_TIMEOUT must be greater than 0")
+		}
+	}
+
+	if c.CampaignLaunchIntervalMinutes <= 0 {
+		return fmt.Errorf("CAMPAIGN_LAUNCH_INTERVAL_MINUTES must be greater than 0")
+	}
+
+	if c.ReplyWindowMinutes <= 0 {
+		return fmt.Errorf("REPLY_WINDOW_MINUTES must be greater than 0")
+	}
+
+	if c.EventsBroker != "" {
+		if c.EventsBroker != "nats" && c.EventsBroker != "kafka" {
+			return fmt.Errorf("EVENTS_BROKER must be nats or kafka")
+		}
+
+		if len(c.EventsURLs) == 0 {
+			return fmt.Errorf("EVENTS_URL is required when EVENTS_BROKER is set")
+		}
+
+		if c.EventsTopic == "" {
+			return fmt.Errorf("EVENTS_TOPIC must not be empty")
+		}
+
+		if c.EventsRelayInterval < scheduler.MinInterval {
+			return fmt.Errorf("EVENTS_RELAY_INTERVAL must be at least %s", scheduler.MinInterval)
+		}
+
+		if c.EventsBatchSize <= 0 {
+			return fmt.Errorf("EVENTS_BATCH_SIZE must be greater than 0")
+		}
+
+		if c.EventsRetentionHours <= 0 {
+			return fmt.Errorf("EVENTS_RETENTION_HOURS must be greater than 0")
+		}
+	}
+
+	if c.IngestBroker != "" {
+		if c.IngestBroker != "kafka" && c.IngestBroker != "rabbitmq" {
+			return fmt.Errorf("INGEST_BROKER must be kafka or rabbitmq")
+		}
+
+		if len(c.IngestURLs) == 0 {
+			return fmt.Errorf("INGEST_URL is required when INGEST_BROKER is set")
+		}
+
+		if c.IngestTopic == "" {
+			return fmt.Errorf("INGEST_TOPIC must not be empty")
+		}
+
+		if c.IngestGroupID == "" {
+			return fmt.Errorf("INGEST_GROUP_ID must not be empty")
+		}
+
+		if c.IngestConcurrency <= 0 {
+			return fmt.Errorf("INGEST_CONCURRENCY must be greater than 0")
+		}
+	}
+
+	if c.CacheRefreshInterval < time.Second {
+		return fmt.Errorf("CACHE_REFRESH_INTERVAL must be at least 1s")
+	}
+
+	if c.TenantDailyMessageQuota <= 0 {
+		return fmt.Errorf("TENANT_DAILY_MESSAGE_QUOTA must be greater than 0")
+	}
+
+	if c.TenantRateLimitPerMinute <= 0 || c.TenantRateLimitPerMinute > 60000 {
+		return fmt.Errorf("TENANT_RATE_LIMIT_PER_MINUTE must be between 1 and 60000")
+	}
+
+	if c.TenantAnomalyDetection {
+		if c.TenantAnomalyInterval < scheduler.MinInterval {
+			return fmt.Errorf("TENANT_ANOMALY_INTERVAL must be at least %s", scheduler.MinInterval)
+		}
+
+		if c.TenantAnomalyBaseline < c.TenantAnomalyInterval {
+			return fmt.Errorf("TENANT_ANOMALY_BASELINE must not be shorter than TENANT_ANOMALY_INTERVAL")
+		}
+
+		if c.TenantAnomalyFactor < 2 {
+			return fmt.Errorf("TENANT_ANOMALY_FACTOR must be at least 2")
+		}
+
+		if c.TenantAnomalyMinMessages <= 0 {
+			return fmt.Errorf("TENANT_ANOMALY_MIN_MESSAGES must be greater than 0")
+		}
+
+		if c.TenantAnomalyThrottle < 0 {
+			return fmt.Errorf("TENANT_ANOMALY_THROTTLE must not be negative")
+		}
+	}
+
+	if c.BacklogAlertWebhookURL != "" {
+		if u, err := url.Parse(c.BacklogAlertWebhookURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("BACKLOG_ALERT_WEBHOOK_URL must be an http or https URL")
+		}
+
+		if c.BacklogAlertFormat != alerthook.FormatSlack && c.BacklogAlertFormat != alerthook.FormatPagerDuty {
+			return fmt.Errorf("BACKLOG_ALERT_FORMAT must be %s or %s", alerthook.FormatSlack, alerthook.FormatPagerDuty)
+		}
+
+		if c.BacklogAlertFormat == alerthook.FormatPagerDuty && c.BacklogAlertRoutingKey == "" {
+			return fmt.Errorf("BACKLOG_ALERT_ROUTING_KEY is required with BACKLOG_ALERT_FORMAT=pagerduty")
+		}
+
+		if c.BacklogAlertInterval < scheduler.MinInterval {
+			return fmt.Errorf("BACKLOG_ALERT_INTERVAL must be at least %s", scheduler.MinInterval)
+		}
+
+		if c.BacklogAlertMaxAge < 0 {
+			return fmt.Errorf("BACKLOG_ALERT_MAX_AGE must not be negative")
+		}
+
+		if c.BacklogAlertMaxSize < 0 {
+			return fmt.Errorf("BACKLOG_ALERT_MAX_SIZE must not be negative")
+		}
+	}
+
+	if c.WatchdogInterval != 0 && c.WatchdogInterval < scheduler.MinInterval {
+		return fmt.Errorf("WATCHDOG_INTERVAL must be at least %s, or 0 to disable", scheduler.MinInterval)
+	}
+
+	if c.WatchdogLockHold != 0 && c.WatchdogLockHold <= scheduler.TaskTimeout {
+		return fmt.Errorf("WATCHDOG_LOCK_HOLD must be longer than %s, or 0 to disable", scheduler.TaskTimeout)
+	}
+
+	if c.WatchdogGoroutineGrowth < 0 {
+		return fmt.Errorf("WATCHDOG_GOROUTINE_GROWTH must not be negative")
+	}
+
 	return nil
 }
 
+// DefaultProvider returns the provider used when a message does not select one
+func (c *Config) DefaultProvider() ProviderConfig {
+	return c.Providers[0]
+}
+
 // getEnv retrieves an environment variable or returns a default value
 func getEnv(key, defaultValue string) string {
+	markRecognized(key)
 	if value := os.Getenv(key); value != "" {
 		return value
 	}
@@ -88,6 +769,7 @@ func getEnv(key, defaultValue string) string {
 
 // getEnvAsInt retrieves an environment variable as int or returns a default value
 func getEnvAsInt(key string, defaultValue int) int {
+	markRecognized(key)
 	valueStr := os.Getenv(key)
 	if valueStr == "" {
 		return defaultValue
@@ -100,3 +782,75 @@ func getEnvAsInt(key string, defaultValue int) int {
 
 	return value
 }
+
+// getEnvAsDuration retrieves an environment variable as a Go duration (e.g. 30s, 1m30s) or returns a default value
+func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
+	markRecognized(key)
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return defaultValue
+	}
+
+	value, err := time.ParseDuration(valueStr)
+	if err != nil {
+		return defaultValue
+	}
+
+	return value
+}
+
+// getEnvAsList retrieves a comma-separated environment variable as a list, empty entries are skipped
+func getEnvAsList(key string) []string {
+	markRecognized(key)
+
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// getEnvAsBool retrieves an environment variable as bool or returns a default value
+func getEnvAsBool(key string, defaultValue bool) bool {
+	markRecognized(key)
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return defaultValue
+	}
+
+	value, err := strconv.ParseBool(valueStr)
+	if err != nil {
+		return defaultValue
+	}
+
+	return value
+}
+
+// getEnvAsListOr retrieves a comma-separated environment variable as a list or returns a default list when unset
+// Set to an empty value it returns an empty list
+func getEnvAsListOr(key string, defaultValue []string) []string {
+	markRecognized(key)
+	valueStr, ok := os.LookupEnv(key)
+	if !ok {
+		return defaultValue
+	}
+
+	var values []string
+	for _, value := range strings.Split(valueStr, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// defaultInstanceID returns the hostname, which is the container ID under Docker
+func defaultInstanceID() string {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		return "unknown"
+	}
+	return hostname
+}
@@ -0,0 +1,17 @@
+package config
+
+import "reflect"
+
+// Diff returns the names of the fields whose value differs in other, in declaration order
+// Values are not returned, so secrets never end up in a log line or response
+func (c *Config) Diff(other *Config) []string {
+	a, b := reflect.ValueOf(c).Elem(), reflect.ValueOf(other).Elem()
+
+	var changed []string
+	for i := 0; i < a.NumField(); i++ {
+		if !reflect.DeepEqual(a.Field(i).Interface(), b.Field(i).Interface()) {
+			changed = append(changed, a.Type().Field(i).Name)
+		}
+	}
+	return changed
+}
@@ -0,0 +1,240 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-yaml"
+)
+
+// Provider types, selecting the sender implementation of a provider
+const (
+	ProviderTypeWebhook  = "webhook"
+	ProviderTypeTwilio   = "twilio"
+	ProviderTypeSMTP     = "smtp"
+	ProviderTypeWhatsApp = "whatsapp"
+)
+
+// ProviderConfig holds the configuration of a single message provider
+type ProviderConfig struct {
+	Name string `json:"name" yaml:"name"`
+
+	// Type selects the sender implementation, empty means webhook
+	Type string `json:"type" yaml:"type"`
+
+	URL                string   `json:"url" yaml:"url"`
+	AuthKey            string   `json:"authKey" yaml:"authKey"`
+	TimeoutSeconds     int      `json:"timeoutSeconds" yaml:"timeoutSeconds"`
+	RateLimitPerSecond int      `json:"rateLimitPerSecond" yaml:"rateLimitPerSecond"`
+	Capabilities       []string `json:"capabilities" yaml:"capabilities"`
+
+	// OverrideRoles lists the caller roles allowed to pin messages to this provider, empty allows everyone
+	OverrideRoles []string `json:"overrideRoles" yaml:"overrideRoles"`
+
+	// Sandbox providers only receive messages created with sandbox API keys
+	Sandbox bool `json:"sandbox" yaml:"sandbox"`
+
+	// AccountSID is the Twilio account, AuthKey holds its auth token
+	// For WhatsApp it is the business account whose templates are synced, AuthKey holds the access token
+	AccountSID string `json:"accountSid" yaml:"accountSid"`
+
+	// From is the Twilio sender number, the SMTP envelope sender or the WhatsApp phone number ID
+	From string `json:"from" yaml:"from"`
+
+	// SMTP server settings, AuthKey holds the password
+	SMTPHost string `json:"smtpHost" yaml:"smtpHost"`
+	SMTPPort int    `json:"smtpPort" yaml:"smtpPort"`
+	Username string `json:"username" yaml:"username"`
+
+	// To is the SMTP recipient address, {phone} is replaced with the message phone number
+	To string `json:"to" yaml:"to"`
+}
+
+// Kind returns the provider type, defaulting to webhook
+func (p ProviderConfig) Kind() string {
+	if p.Type == "" {
+		return ProviderTypeWebhook
+	}
+	return p.Type
+}
+
+// RequiresTemplate reports whether the provider only sends pre-approved provider templates
+func (p ProviderConfig) RequiresTemplate() bool {
+	return p.Kind() == ProviderTypeWhatsApp
+}
+
+// Timeout returns the provider request timeout, zero meaning no timeout
+func (p ProviderConfig) Timeout() time.Duration {
+	return time.Duration(p.TimeoutSeconds) * time.Second
+}
+
+// HasCapability reports whether the provider declares the given capability
+func (p ProviderConfig) HasCapability(capability string) bool {
+	for _, c := range p.Capabilities {
+		if c == capability {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsOverride reports whether a caller with the given role may pin messages to this provider
+func (p ProviderConfig) AllowsOverride(role string) bool {
+	if len(p.OverrideRoles) == 0 {
+		return true
+	}
+
+	for _, r := range p.OverrideRoles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks if the provider configuration is valid
+func (p ProviderConfig) Validate() error {
+	if p.Name == "" {
+		return fmt.Errorf("provider name is required")
+	}
+
+	switch p.Kind() {
+	case ProviderTypeWebhook:
+		if p.URL == "" {
+			return fmt.Errorf("provider %q: url is required", p.Name)
+		}
+		if p.AuthKey == "" {
+			return fmt.Errorf("provider %q: authKey is required", p.Name)
+		}
+	case ProviderTypeTwilio:
+		if p.AccountSID == "" || p.AuthKey == "" {
+			return fmt.Errorf("provider %q: accountSid and authKey are required", p.Name)
+		}
+		if p.From == "" {
+			return fmt.Errorf("provider %q: from is required", p.Name)
+		}
+	case ProviderTypeSMTP:
+		if p.SMTPHost == "" {
+			return fmt.Errorf("provider %q: smtpHost is required", p.Name)
+		}
+		if p.SMTPPort < 0 || p.SMTPPort > 65535 {
+			return fmt.Errorf("provider %q: smtpPort must be between 0 and 65535", p.Name)
+		}
+		if p.From == "" {
+			return fmt.Errorf("provider %q: from is required", p.Name)
+		}
+		if !strings.Contains(p.To, "{phone}") {
+			return fmt.Errorf("provider %q: to must contain the {phone} placeholder", p.Name)
+		}
+	case ProviderTypeWhatsApp:
+		if p.AccountSID == "" || p.AuthKey == "" {
+			return fmt.Errorf("provider %q: accountSid and authKey are required", p.Name)
+		}
+		if p.From == "" {
+			return fmt.Errorf("provider %q: from is required", p.Name)
+		}
+	default:
+		return fmt.Errorf("provider %q: unknown type %q (expected webhook, twilio, smtp or whatsapp)", p.Name, p.Type)
+	}
+
+	if p.TimeoutSeconds < 0 {
+		return fmt.Errorf("provider %q: timeoutSeconds must not be negative", p.Name)
+	}
+
+	if p.RateLimitPerSecond < 0 {
+		return fmt.Errorf("provider %q: rateLimitPerSecond must not be negative", p.Name)
+	}
+
+	return nil
+}
+
+// loadProviders reads the provider list from the first available source:
+// 1. PROVIDERS_FILE - path to a JSON or YAML file
+// 2. PROVIDERS_JSON - inline JSON array
+// 3. WEBHOOK_URL / WEBHOOK_AUTH_KEY - a single provider named "default"
+func loadProviders() ([]ProviderConfig, error) {
+	// Only one source is read, the others are still known to strict mode
+	markRecognized("PROVIDERS_FILE", "PROVIDERS_JSON", "WEBHOOK_URL", "WEBHOOK_AUTH_KEY")
+
+	if path := getEnv("PROVIDERS_FILE", ""); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read PROVIDERS_FILE: %w", err)
+		}
+
+		providers, err := parseProviders(data, filepath.Ext(path))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse PROVIDERS_FILE: %w", err)
+		}
+		return providers, nil
+	}
+
+	if raw := getEnv("PROVIDERS_JSON", ""); raw != "" {
+		providers, err := parseProviders([]byte(raw), ".json")
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse PROVIDERS_JSON: %w", err)
+		}
+		return providers, nil
+	}
+
+	webhookURL := getEnv("WEBHOOK_URL", "")
+	webhookAuthKey := getEnv("WEBHOOK_AUTH_KEY", "")
+	if webhookURL == "" && webhookAuthKey == "" {
+		return nil, nil
+	}
+
+	return []ProviderConfig{{
+		Name:    "default",
+		URL:     webhookURL,
+		AuthKey: webhookAuthKey,
+	}}, nil
+}
+
+// parseProviders decodes a provider list in the format implied by the file extension
+func parseProviders(data []byte, ext string) ([]ProviderConfig, error) {
+	var providers []ProviderConfig
+
+	switch strings.ToLower(ext) {
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(data, &providers); err != nil {
+			return nil, err
+		}
+	case ".json":
+		if err := json.Unmarshal(data, &providers); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported providers format %q (expected .json, .yaml or .yml)", ext)
+	}
+
+	return providers, nil
+}
+
+// validateProviders checks every provider and that provider names are unique
+func validateProviders(providers []ProviderConfig) error {
+	if len(providers) == 0 {
+		return fmt.Errorf("at least one provider is required (set PROVIDERS_FILE, PROVIDERS_JSON or WEBHOOK_URL)")
+	}
+
+	if providers[0].Sandbox {
+		return fmt.Errorf("provider %q: the default provider must not be a sandbox provider", providers[0].Name)
+	}
+
+	seen := make(map[string]bool, len(providers))
+	for _, p := range providers {
+		if err := p.Validate(); err != nil {
+			return err
+		}
+
+		if seen[p.Name] {
+			return fmt.Errorf("provider %q is defined more than once", p.Name)
+		}
+		seen[p.Name] = true
+	}
+
+	return nil
+}
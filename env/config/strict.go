@@ -0,0 +1,88 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// strictPrefixes are the environment variable prefixes owned by the application
+// In strict mode a variable with one of these prefixes that is never read fails startup
+var strictPrefixes = []string{
+	"QUBIT_",
+	"DATABASE_",
+	"MIGRATION_",
+	"REDIS_",
+	"DELIVERY_CACHE_",
+	"PROVIDERS_",
+	"WEBHOOK_",
+	"CIRCUIT_BREAKER_",
+	"HEALTH_",
+	"CANARY_",
+	"SERVER_",
+	"GRPC_",
+	"ADMIN_",
+	"API_KEYS_",
+	"API_TIMESTAMP_",
+	"RATE_LIMIT_",
+	"SCHEDULER_",
+	"MESSAGE_",
+	"DISPATCH_",
+	"SENDING_",
+	"SEND_RATE_",
+	"RETRY_",
+	"RECIPIENT_LIMIT_",
+	"URL_",
+	"REQUEST_",
+	"MIRROR_",
+	"CAMPAIGN_",
+	"REPLY_",
+	"LOCALE_",
+	"EVENTS_",
+	"CACHE_",
+	"INGEST_",
+	"TENANT_",
+	"BACKLOG_ALERT_",
+	"WATCHDOG_",
+	"CONFIG_",
+}
+
+// recognized holds every environment variable the configuration reads
+var recognized = map[string]bool{}
+
+// markRecognized records variables as known even when the current setup does not read them
+func markRecognized(keys ...string) {
+	for _, key := range keys {
+		recognized[key] = true
+	}
+}
+
+// checkUnknown returns an error listing the set variables with an application prefix that are never read
+func checkUnknown() error {
+	var unknown []string
+	for _, entry := range os.Environ() {
+		key, _, _ := strings.Cut(entry, "=")
+		if recognized[key] || !hasStrictPrefix(key) {
+			continue
+		}
+		unknown = append(unknown, key)
+	}
+
+	if len(unknown) == 0 {
+		return nil
+	}
+
+	sort.Strings(unknown)
+	return fmt.Errorf("CONFIG_STRICT: unrecognized environment variables %s (check for typos or unset them)", strings.Join(unknown, ", "))
+}
+
+// hasStrictPrefix reports whether key starts with an application prefix
+func hasStrictPrefix(key string) bool {
+	for _, prefix := range strictPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,65 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// kafkaPublisher publishes all events on one topic, keyed by message so the events of a message share a partition
+type kafkaPublisher struct {
+	writer *kafka.Writer
+}
+
+// newKafkaPublisher creates the writer and verifies that a bootstrap broker is reachable
+func newKafkaPublisher(ctx context.Context, cfg Config) (*kafkaPublisher, error) {
+	transport := &kafka.Transport{ClientID: cfg.ClientID}
+
+	conn, err := kafka.DialContext(ctx, "tcp", cfg.URLs[0])
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Kafka: %w", err)
+	}
+	_ = conn.Close()
+
+	log.Printf("✓ Kafka connection established successfully (topic: %s)", cfg.Topic)
+
+	return &kafkaPublisher{
+		writer: &kafka.Writer{
+			Addr:         kafka.TCP(cfg.URLs...),
+			Topic:        cfg.Topic,
+			Balancer:     &kafka.Hash{},
+			RequiredAcks: kafka.RequireAll,
+			BatchTimeout: 10 * time.Millisecond, // Publish already hands over whole batches
+			Transport:    transport,
+		},
+	}, nil
+}
+
+// Publish writes the events and waits until all in-sync replicas acknowledged them
+func (p *kafkaPublisher) Publish(ctx context.Context, events []Event) error {
+	msgs := make([]kafka.Message, len(events))
+	for i, e := range events {
+		msgs[i] = kafka.Message{
+			Key:   []byte(e.Key),
+			Value: e.Payload,
+			Headers: []kafka.Header{
+				{Key: HeaderEventID, Value: []byte(e.ID)},
+				{Key: HeaderEventType, Value: []byte(e.Type)},
+			},
+		}
+	}
+
+	if err := p.writer.WriteMessages(ctx, msgs...); err != nil {
+		return fmt.Errorf("failed to write events: %w", err)
+	}
+
+	return nil
+}
+
+// Close flushes pending writes and closes the writer
+func (p *kafkaPublisher) Close() error {
+	return p.writer.Close()
+}
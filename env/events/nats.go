@@ -0,0 +1,58 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/nats-io/nats.go"
+)
+
+// natsPublisher publishes each event on the subject <topic>.<event type>
+// The event ID is also sent as Nats-Msg-Id, so JetStream streams deduplicate redeliveries
+type natsPublisher struct {
+	conn   *nats.Conn
+	prefix string
+}
+
+// newNATSPublisher connects to the NATS servers
+func newNATSPublisher(cfg Config) (*natsPublisher, error) {
+	conn, err := nats.Connect(strings.Join(cfg.URLs, ","), nats.Name(cfg.ClientID), nats.MaxReconnects(-1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to NATS: %w", err)
+	}
+
+	log.Printf("✓ NATS connection established successfully (%s)", conn.ConnectedUrlRedacted())
+
+	return &natsPublisher{
+		conn:   conn,
+		prefix: cfg.Topic,
+	}, nil
+}
+
+// Publish sends the events and waits until the server processed them
+func (p *natsPublisher) Publish(ctx context.Context, events []Event) error {
+	for _, e := range events {
+		msg := nats.NewMsg(p.prefix + "." + e.Type)
+		msg.Header.Set(nats.MsgIdHdr, e.ID)
+		msg.Header.Set(HeaderEventID, e.ID)
+		msg.Header.Set(HeaderEventType, e.Type)
+		msg.Data = e.Payload
+
+		if err := p.conn.PublishMsg(msg); err != nil {
+			return fmt.Errorf("failed to publish event %s: %w", e.ID, err)
+		}
+	}
+
+	if err := p.conn.FlushWithContext(ctx); err != nil {
+		return fmt.Errorf("failed to flush events: %w", err)
+	}
+
+	return nil
+}
+
+// Close drains pending events and closes the connection
+func (p *natsPublisher) Close() error {
+	return p.conn.Drain()
+}
@@ -0,0 +1,53 @@
+package events
+
+import (
+	"context"
+	"fmt"
+)
+
+// Supported brokers
+const (
+	BrokerNATS  = "nats"
+	BrokerKafka = "kafka"
+)
+
+// Header names set on every published event
+const (
+	HeaderEventID   = "Qubit-Event-Id"
+	HeaderEventType = "Qubit-Event-Type"
+)
+
+// Event is a message lifecycle event ready to be published
+type Event struct {
+	ID      string // unique event ID, consumers deduplicate redeliveries on it
+	Type    string // e.g. message.created
+	Key     string // ID of the message, keeps the events of a message in order
+	Payload []byte // JSON document
+}
+
+// Publisher delivers events to a broker
+// Publish returns once the broker accepted every event of the batch, a failed batch may be published again
+type Publisher interface {
+	Publish(ctx context.Context, events []Event) error
+	Close() error
+}
+
+// Config selects and configures the broker events are published to
+type Config struct {
+	Broker   string   // nats or kafka
+	URLs     []string // NATS server URLs or Kafka bootstrap brokers
+	Topic    string   // Kafka topic, or NATS subject prefix followed by the event type
+	ClientID string   // identifies this instance to the broker
+}
+
+// NewPublisher connects to the configured broker
+func NewPublisher(ctx context.Context, cfg Config) (Publisher, error) {
+	switch cfg.Broker {
+	case BrokerNATS:
+		return newNATSPublisher(cfg)
+	case BrokerKafka:
+		return newKafkaPublisher(ctx, cfg)
+	default:
+		return nil, fmt.Errorf("unknown event broker %q, expected %s or %s", cfg.Broker, BrokerNATS, BrokerKafka)
+	}
+}
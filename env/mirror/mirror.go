@@ -0,0 +1,111 @@
+package mirror
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"qubit/pkg/buildinfo"
+)
+
+// Header marks a mirrored request with the instance it was mirrored from, so staging can tell it from its own traffic
+const Header = "X-Qubit-Mirror"
+
+// maxInFlight bounds the mirrored requests waiting for staging, further requests are not mirrored
+const maxInFlight = 32
+
+// Config configures mirroring of create requests to a staging deployment
+type Config struct {
+	URL     string // base URL of the staging deployment, e.g. https://qubit.staging.example.com
+	APIKey  string // sandbox API key of the staging deployment, so mirrored messages are test messages
+	Percent int    // share of the requests mirrored, 0 to 100
+	Timeout time.Duration
+}
+
+// Client forwards a sample of requests to staging in the background
+// Mirroring never delays or fails the request it copies; staging errors are only logged
+type Client struct {
+	url      string
+	apiKey   string
+	percent  int
+	instance string
+	http     *http.Client
+
+	slots chan struct{}
+	wg    sync.WaitGroup
+}
+
+// NewClient creates a client mirroring to cfg.URL from the instance instanceID
+func NewClient(cfg Config, instanceID string) *Client {
+	return &Client{
+		url:      cfg.URL,
+		apiKey:   cfg.APIKey,
+		percent:  cfg.Percent,
+		instance: instanceID,
+		http:     &http.Client{Timeout: cfg.Timeout},
+		slots:    make(chan struct{}, maxInFlight),
+	}
+}
+
+// Sample reports whether a request is picked for mirroring
+func (c *Client) Sample() bool {
+	return rand.Intn(100) < c.percent
+}
+
+// Mirror sends body to path on staging in the background, e.g. /api/v1/messages
+// status is the status production answered with; a staging response of another class is logged as a divergence
+// The request is dropped when too many mirrored requests are in flight
+func (c *Client) Mirror(method, path string, body []byte, status int) {
+	select {
+	case c.slots <- struct{}{}:
+	default:
+		return
+	}
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		defer func() { <-c.slots }()
+
+		stagingStatus, err := c.send(method, path, body)
+		if err != nil {
+			log.Printf("Warning: failed to mirror %s %s: %v", method, path, err)
+			return
+		}
+		if stagingStatus/100 != status/100 {
+			log.Printf("⚠ Mirrored %s %s diverged: production answered %d, staging %d", method, path, status, stagingStatus)
+		}
+	}()
+}
+
+// Close waits for the mirrored requests in flight, each bounded by the timeout
+func (c *Client) Close() {
+	c.wg.Wait()
+}
+
+// send makes the mirrored request and returns the staging status
+func (c *Client) send(method, path string, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(context.Background(), method, c.url+path, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-API-Key", c.apiKey)
+	req.Header.Set("User-Agent", buildinfo.UserAgent())
+	req.Header.Set(Header, c.instance)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	return resp.StatusCode, nil
+}
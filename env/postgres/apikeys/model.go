@@ -0,0 +1,20 @@
+package apikeys
+
+import (
+	"time"
+)
+
+// Key represents an API key data model for PostgreSQL persistence
+// This is a pure data structure with no business logic
+type Key struct {
+	ID        int64     `db:"id"`
+	Name      string    `db:"name"`
+	Prefix    string    `db:"key_prefix"`
+	Hash      string    `db:"key_hash"`
+	Scopes    []string  `db:"scopes"`
+	CreatedAt time.Time `db:"created_at"`
+	IsTest    bool      `db:"is_test"`
+	TenantID  *int64    `db:"tenant_id"`
+
+	RevokedAt *time.Time `db:"revoked_at"`
+}
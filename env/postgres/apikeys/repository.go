@@ -0,0 +1,113 @@
+package apikeys
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"qubit/env/postgres/scan"
+)
+
+// ErrNotFound is returned when an API key does not exist or was revoked
+var ErrNotFound = errors.New("api key not found")
+
+// keyColumns is the column list selected for a Key, matching its db tags
+const keyColumns = `id, name, key_prefix, key_hash, scopes, created_at, is_test, tenant_id, revoked_at`
+
+// querier is implemented by both the pool and a transaction
+type querier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// Repository handles API key data access operations
+type Repository struct {
+	pool *pgxpool.Pool
+}
+
+// NewRepository creates a new API key repository
+func NewRepository(pool *pgxpool.Pool) *Repository {
+	return &Repository{
+		pool: pool,
+	}
+}
+
+// Create inserts a new API key into the database
+// The ID will be populated after successful insertion
+func (r *Repository) Create(ctx context.Context, k *Key) error {
+	return create(ctx, r.pool, k)
+}
+
+// CreateWithTx inserts a new API key within a transaction
+// The ID will be populated after successful insertion
+func (r *Repository) CreateWithTx(ctx context.Context, tx pgx.Tx, k *Key) error {
+	return create(ctx, tx, k)
+}
+
+// create inserts k using q, either the pool or a transaction
+func create(ctx context.Context, q querier, k *Key) error {
+	query := `
+		INSERT INTO api_keys (name, key_prefix, key_hash, scopes, created_at, is_test, tenant_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`
+
+	if k.CreatedAt.IsZero() {
+		k.CreatedAt = time.Now()
+	}
+
+	err := q.QueryRow(ctx, query, k.Name, k.Prefix, k.Hash, k.Scopes, k.CreatedAt, k.IsTest, k.TenantID).Scan(&k.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create api key: %w", err)
+	}
+
+	return nil
+}
+
+// GetActiveByHash retrieves a non-revoked API key by the hash of its secret
+// Returns ErrNotFound if no active key matches
+func (r *Repository) GetActiveByHash(ctx context.Context, hash string) (*Key, error) {
+	query := `SELECT ` + keyColumns + ` FROM api_keys WHERE key_hash = $1 AND revoked_at IS NULL`
+
+	k, err := scan.One[Key](r.pool.Query(ctx, query, hash))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get api key: %w", err)
+	}
+
+	return k, nil
+}
+
+// List retrieves all API keys, including revoked ones, ordered by creation time
+func (r *Repository) List(ctx context.Context) ([]*Key, error) {
+	query := `SELECT ` + keyColumns + ` FROM api_keys ORDER BY created_at ASC`
+
+	keys, err := scan.All[Key](r.pool.Query(ctx, query))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query api keys: %w", err)
+	}
+
+	return keys, nil
+}
+
+// Revoke marks an active API key as revoked
+// Returns ErrNotFound if the key does not exist or is already revoked
+func (r *Repository) Revoke(ctx context.Context, id int64) error {
+	query := `UPDATE api_keys SET revoked_at = NOW() WHERE id = $1 AND revoked_at IS NULL`
+
+	result, err := r.pool.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to revoke api key: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
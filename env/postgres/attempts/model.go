@@ -0,0 +1,47 @@
+package attempts
+
+import (
+	"time"
+)
+
+// Attempt represents a single send attempt data model for PostgreSQL persistence
+// This is a pure data structure with no business logic
+type Attempt struct {
+	ID            int64     `db:"id"`
+	MessageID     int64     `db:"message_id"`
+	AttemptNumber int       `db:"attempt_number"`
+	StartedAt     time.Time `db:"started_at"`
+	Success       bool      `db:"success"`
+	Error         *string   `db:"error"`
+
+	FailureCategory *string `db:"failure_category"`
+
+	QueueWaitMs  int64 `db:"queue_wait_ms"`
+	LockToSendMs int64 `db:"lock_to_send_ms"`
+	WebhookMs    int64 `db:"webhook_ms"`
+	DBUpdateMs   int64 `db:"db_update_ms"`
+
+	RequestPayload *string `db:"request_payload"`
+	ResponseBody   *string `db:"response_body"`
+}
+
+// PhaseStats holds aggregated latency of a single phase in milliseconds
+type PhaseStats struct {
+	AvgMs float64
+	P95Ms float64
+	MaxMs int64
+}
+
+// Stats holds aggregated latency of all attempts in a time window
+type Stats struct {
+	Attempts  int64
+	Succeeded int64
+
+	// Failures counts the failed attempts per failure category
+	Failures map[string]int64
+
+	QueueWait  PhaseStats
+	LockToSend PhaseStats
+	Webhook    PhaseStats
+	DBUpdate   PhaseStats
+}
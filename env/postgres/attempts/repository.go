@@ -0,0 +1,167 @@
+package attempts
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"qubit/env/postgres/scan"
+)
+
+// Repository handles send attempt data access operations
+type Repository struct {
+	pool *pgxpool.Pool
+}
+
+// NewRepository creates a new attempt repository
+func NewRepository(pool *pgxpool.Pool) *Repository {
+	return &Repository{
+		pool: pool,
+	}
+}
+
+// CreateWithTx inserts a new attempt within a transaction
+// The ID will be populated after successful insertion
+func (r *Repository) CreateWithTx(ctx context.Context, tx pgx.Tx, a *Attempt) error {
+	query := `
+		INSERT INTO message_attempts (
+			message_id, attempt_number, started_at, success, error,
+			queue_wait_ms, lock_to_send_ms, webhook_ms, db_update_ms,
+			request_payload, response_body, failure_category
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		RETURNING id
+	`
+
+	err := tx.QueryRow(
+		ctx,
+		query,
+		a.MessageID,
+		a.AttemptNumber,
+		a.StartedAt,
+		a.Success,
+		a.Error,
+		a.QueueWaitMs,
+		a.LockToSendMs,
+		a.WebhookMs,
+		a.DBUpdateMs,
+		a.RequestPayload,
+		a.ResponseBody,
+		a.FailureCategory,
+	).Scan(&a.ID)
+
+	if err != nil {
+		return fmt.Errorf("failed to create attempt: %w", err)
+	}
+
+	return nil
+}
+
+// ListByMessage retrieves all attempts of a message ordered by attempt number
+func (r *Repository) ListByMessage(ctx context.Context, messageID int64) ([]*Attempt, error) {
+	query := `
+		SELECT id, message_id, attempt_number, started_at, success, error,
+		       queue_wait_ms, lock_to_send_ms, webhook_ms, db_update_ms,
+		       request_payload, response_body, failure_category
+		FROM message_attempts
+		WHERE message_id = $1
+		ORDER BY attempt_number ASC
+	`
+
+	attempts, err := scan.All[Attempt](r.pool.Query(ctx, query, messageID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query attempts: %w", err)
+	}
+
+	return attempts, nil
+}
+
+// Stats aggregates the latency breakdown of all attempts started at or after since
+// Attempts of test messages are skipped unless includeTest is set
+func (r *Repository) Stats(ctx context.Context, since time.Time, includeTest bool) (*Stats, error) {
+	query := `
+		SELECT
+			COUNT(*),
+			COUNT(*) FILTER (WHERE success),
+			COALESCE(AVG(queue_wait_ms), 0),
+			COALESCE(percentile_cont(0.95) WITHIN GROUP (ORDER BY queue_wait_ms), 0),
+			COALESCE(MAX(queue_wait_ms), 0),
+			COALESCE(AVG(lock_to_send_ms), 0),
+			COALESCE(percentile_cont(0.95) WITHIN GROUP (ORDER BY lock_to_send_ms), 0),
+			COALESCE(MAX(lock_to_send_ms), 0),
+			COALESCE(AVG(webhook_ms), 0),
+			COALESCE(percentile_cont(0.95) WITHIN GROUP (ORDER BY webhook_ms), 0),
+			COALESCE(MAX(webhook_ms), 0),
+			COALESCE(AVG(db_update_ms), 0),
+			COALESCE(percentile_cont(0.95) WITHIN GROUP (ORDER BY db_update_ms), 0),
+			COALESCE(MAX(db_update_ms), 0)
+		FROM message_attempts a
+		JOIN messages m ON m.id = a.message_id
+		WHERE a.started_at >= $1 AND ($2 OR NOT m.is_test)
+	`
+
+	stats := &Stats{}
+	err := r.pool.QueryRow(ctx, query, since, includeTest).Scan(
+		&stats.Attempts,
+		&stats.Succeeded,
+		&stats.QueueWait.AvgMs,
+		&stats.QueueWait.P95Ms,
+		&stats.QueueWait.MaxMs,
+		&stats.LockToSend.AvgMs,
+		&stats.LockToSend.P95Ms,
+		&stats.LockToSend.MaxMs,
+		&stats.Webhook.AvgMs,
+		&stats.Webhook.P95Ms,
+		&stats.Webhook.MaxMs,
+		&stats.DBUpdate.AvgMs,
+		&stats.DBUpdate.P95Ms,
+		&stats.DBUpdate.MaxMs,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate attempt stats: %w", err)
+	}
+
+	stats.Failures, err = r.failureCounts(ctx, since, includeTest)
+	if err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// failureCounts counts the failed attempts started at or after since per failure category
+// Failures recorded before classification existed are counted as other
+func (r *Repository) failureCounts(ctx context.Context, since time.Time, includeTest bool) (map[string]int64, error) {
+	query := `
+		SELECT COALESCE(a.failure_category, 'other'), COUNT(*)
+		FROM message_attempts a
+		JOIN messages m ON m.id = a.message_id
+		WHERE a.started_at >= $1 AND ($2 OR NOT m.is_test) AND NOT a.success
+		GROUP BY 1
+	`
+
+	rows, err := r.pool.Query(ctx, query, since, includeTest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count attempt failures: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int64)
+	for rows.Next() {
+		var category string
+		var count int64
+		if err := rows.Scan(&category, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan attempt failures: %w", err)
+		}
+		counts[category] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating attempt failures: %w", err)
+	}
+
+	return counts, nil
+}
@@ -0,0 +1,63 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// DefaultTenantName is the tenant seeded into a database without tenants
+const DefaultTenantName = "default"
+
+// Seed is the reference data a fresh environment needs to work without manual SQL
+type Seed struct {
+	// Quotas of the default tenant
+	DailyMessageQuota  int
+	RateLimitPerMinute int
+}
+
+// seedSettings are the runtime settings rows present from the start, matching the behavior of a missing row
+// The scheduler key is not seeded, its absence means the configured settings apply
+var seedSettings = map[string]string{
+	"maintenance": `{"enabled": false, "message": "", "since": null}`,
+}
+
+// Bootstrap inserts the reference data of seed in one transaction, after the migrations were applied
+// Rows that already exist are left untouched and the default tenant is only seeded while no tenant exists,
+// so it is safe to run on every start and against databases that predate the bootstrap
+// Returns the number of rows inserted, zero once the database was bootstrapped
+func (c *Client) Bootstrap(ctx context.Context, seed Seed) (int64, error) {
+	var inserted int64
+	err := pgx.BeginFunc(ctx, c.pool, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, `
+			INSERT INTO tenants (name, daily_message_quota, rate_limit_per_minute)
+			SELECT $1::text, $2::integer, $3::integer
+			WHERE NOT EXISTS (SELECT 1 FROM tenants)
+			ON CONFLICT (name) DO NOTHING
+		`, DefaultTenantName, seed.DailyMessageQuota, seed.RateLimitPerMinute)
+		if err != nil {
+			return fmt.Errorf("failed to seed default tenant: %w", err)
+		}
+		inserted += tag.RowsAffected()
+
+		for key, value := range seedSettings {
+			tag, err := tx.Exec(ctx, `
+				INSERT INTO settings (key, value)
+				VALUES ($1, $2)
+				ON CONFLICT (key) DO NOTHING
+			`, key, value)
+			if err != nil {
+				return fmt.Errorf("failed to seed setting %s: %w", key, err)
+			}
+			inserted += tag.RowsAffected()
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to bootstrap database: %w", err)
+	}
+
+	return inserted, nil
+}
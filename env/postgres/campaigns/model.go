@@ -0,0 +1,46 @@
+package campaigns
+
+import (
+	"time"
+)
+
+// Campaign represents a campaign data model for PostgreSQL persistence
+// This is a pure data structure with no business logic
+type Campaign struct {
+	ID         int64     `db:"id"`
+	Name       string    `db:"name"`
+	Content    string    `db:"content"`
+	Recipients []string  `db:"recipients"`
+	Status     string    `db:"status"`
+	CreatedBy  string    `db:"created_by"`
+	CreatedAt  time.Time `db:"created_at"`
+	UpdatedAt  time.Time `db:"updated_at"`
+
+	ReviewedBy    *string    `db:"reviewed_by"`
+	ReviewComment *string    `db:"review_comment"`
+	ReviewedAt    *time.Time `db:"reviewed_at"`
+
+	ScheduledAt *time.Time `db:"scheduled_at"`
+	CompletedAt *time.Time `db:"completed_at"`
+
+	Variants []Variant `db:"variants"`
+}
+
+// Variant is an A/B content variant of a campaign stored as JSON
+type Variant struct {
+	Name    string `json:"name"`
+	Content string `json:"content"`
+	Percent int    `json:"percent"`
+}
+
+// VariantStats aggregates the messages of a campaign created from one variant
+// Variant is empty for the messages of a campaign without variants
+type VariantStats struct {
+	Variant   string `db:"variant"`
+	Messages  int64  `db:"messages"`
+	Pending   int64  `db:"pending"`
+	Sent      int64  `db:"sent"`
+	Failed    int64  `db:"failed"`
+	Cancelled int64  `db:"cancelled"`
+	Replied   int64  `db:"replied"`
+}
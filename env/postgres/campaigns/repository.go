@@ -0,0 +1,228 @@
+package campaigns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"qubit/env/postgres/scan"
+)
+
+// ErrNotFound is returned when a campaign does not exist
+var ErrNotFound = errors.New("campaign not found")
+
+// ErrStatusConflict is returned when a campaign is not in the expected status
+var ErrStatusConflict = errors.New("campaign status changed concurrently")
+
+// campaignColumns is the column list selected for a Campaign, matching its db tags
+const campaignColumns = `id, name, content, recipients, status, created_by, created_at, updated_at,
+		reviewed_by, review_comment, reviewed_at, scheduled_at, completed_at, variants`
+
+// Repository handles campaign data access operations
+type Repository struct {
+	pool *pgxpool.Pool
+}
+
+// NewRepository creates a new campaign repository
+func NewRepository(pool *pgxpool.Pool) *Repository {
+	return &Repository{
+		pool: pool,
+	}
+}
+
+// Create inserts a new campaign into the database
+// The ID will be populated after successful insertion
+func (r *Repository) Create(ctx context.Context, c *Campaign) error {
+	query := `
+		INSERT INTO campaigns (name, content, recipients, status, created_by, created_at, updated_at, variants)
+		VALUES ($1, $2, $3, $4, $5, $6, $6, $7)
+		RETURNING id
+	`
+
+	if c.CreatedAt.IsZero() {
+		c.CreatedAt = time.Now()
+	}
+	c.UpdatedAt = c.CreatedAt
+	if c.Variants == nil {
+		c.Variants = []Variant{}
+	}
+
+	err := r.pool.QueryRow(
+		ctx,
+		query,
+		c.Name,
+		c.Content,
+		c.Recipients,
+		c.Status,
+		c.CreatedBy,
+		c.CreatedAt,
+		c.Variants,
+	).Scan(&c.ID)
+
+	if err != nil {
+		return fmt.Errorf("failed to create campaign: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a campaign by its ID
+// Returns ErrNotFound if the campaign does not exist
+func (r *Repository) GetByID(ctx context.Context, id int64) (*Campaign, error) {
+	query := `SELECT ` + campaignColumns + ` FROM campaigns WHERE id = $1`
+
+	c, err := scan.One[Campaign](r.pool.Query(ctx, query, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get campaign: %w", err)
+	}
+
+	return c, nil
+}
+
+// List retrieves all campaigns ordered by creation time
+func (r *Repository) List(ctx context.Context) ([]*Campaign, error) {
+	query := `SELECT ` + campaignColumns + ` FROM campaigns ORDER BY created_at ASC`
+
+	campaigns, err := scan.All[Campaign](r.pool.Query(ctx, query))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query campaigns: %w", err)
+	}
+
+	return campaigns, nil
+}
+
+// Update persists the mutable fields of a campaign if it is still in fromStatus
+// Returns ErrStatusConflict if the campaign moved to another status in the meantime
+func (r *Repository) Update(ctx context.Context, c *Campaign, fromStatus string) error {
+	query := `
+		UPDATE campaigns
+		SET status = $1, reviewed_by = $2, review_comment = $3, reviewed_at = $4,
+		    scheduled_at = $5, completed_at = $6, updated_at = $7
+		WHERE id = $8 AND status = $9
+	`
+
+	c.UpdatedAt = time.Now()
+
+	result, err := r.pool.Exec(
+		ctx,
+		query,
+		c.Status,
+		c.ReviewedBy,
+		c.ReviewComment,
+		c.ReviewedAt,
+		c.ScheduledAt,
+		c.CompletedAt,
+		c.UpdatedAt,
+		c.ID,
+		fromStatus,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update campaign: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrStatusConflict
+	}
+
+	return nil
+}
+
+// ListAndLockDue retrieves scheduled campaigns whose start time has passed and locks them
+// This method MUST be called within a transaction
+func (r *Repository) ListAndLockDue(ctx context.Context, tx pgx.Tx, limit int) ([]*Campaign, error) {
+	query := `
+		SELECT ` + campaignColumns + `
+		FROM campaigns
+		WHERE status = 'scheduled' AND scheduled_at <= NOW()
+		ORDER BY scheduled_at ASC
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED
+	`
+
+	campaigns, err := scan.All[Campaign](tx.Query(ctx, query, limit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query due campaigns: %w", err)
+	}
+
+	return campaigns, nil
+}
+
+// UpdateStatusWithTx changes the status of a campaign within a transaction
+func (r *Repository) UpdateStatusWithTx(ctx context.Context, tx pgx.Tx, id int64, status string, completedAt *time.Time) error {
+	query := `
+		UPDATE campaigns
+		SET status = $1, completed_at = $2, updated_at = NOW()
+		WHERE id = $3
+	`
+
+	result, err := tx.Exec(ctx, query, status, completedAt, id)
+	if err != nil {
+		return fmt.Errorf("failed to update campaign status: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// EnqueueMessagesWithTx creates one pending message per campaign recipient within a transaction
+// Each message gets the content and variant at the index of its recipient, an empty variant is stored as NULL
+// Returns the number of messages created
+func (r *Repository) EnqueueMessagesWithTx(ctx context.Context, tx pgx.Tx, campaignID int64, recipients, contents, variants []string) (int64, error) {
+	query := `
+		INSERT INTO messages (phone_number, content, created_at, status, campaign_id, variant)
+		SELECT recipient, content, NOW(), 'pending', $1, NULLIF(variant, '')
+		FROM unnest($2::text[], $3::text[], $4::text[]) AS m(recipient, content, variant)
+	`
+
+	result, err := tx.Exec(ctx, query, campaignID, recipients, contents, variants)
+	if err != nil {
+		return 0, fmt.Errorf("failed to enqueue campaign messages: %w", err)
+	}
+
+	return result.RowsAffected(), nil
+}
+
+// Stats aggregates the messages of a campaign per variant, archived messages included
+// Replied counts the messages with at least one inbound reply linked to them
+func (r *Repository) Stats(ctx context.Context, id int64) ([]*VariantStats, error) {
+	query := `
+		WITH campaign_messages AS (
+			SELECT id, status, variant FROM messages WHERE campaign_id = $1
+			UNION ALL
+			SELECT id, status, variant FROM messages_archive WHERE campaign_id = $1
+		),
+		replied AS (
+			SELECT DISTINCT reply_to_id FROM inbound_messages
+			WHERE reply_to_id IN (SELECT id FROM campaign_messages)
+		)
+		SELECT
+			COALESCE(m.variant, '') AS variant,
+			COUNT(*) AS messages,
+			COUNT(*) FILTER (WHERE m.status IN ('pending', 'sending')) AS pending,
+			COUNT(*) FILTER (WHERE m.status = 'sent') AS sent,
+			COUNT(*) FILTER (WHERE m.status IN ('failed', 'throttled', 'quarantined')) AS failed,
+			COUNT(*) FILTER (WHERE m.status = 'cancelled') AS cancelled,
+			COUNT(r.reply_to_id) AS replied
+		FROM campaign_messages m
+		LEFT JOIN replied r ON r.reply_to_id = m.id
+		GROUP BY 1
+		ORDER BY 1
+	`
+
+	stats, err := scan.All[VariantStats](r.pool.Query(ctx, query, id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate campaign stats: %w", err)
+	}
+
+	return stats, nil
+}
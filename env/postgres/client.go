@@ -9,13 +9,42 @@ import (
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 
+	"qubit/env/postgres/apikeys"
+	"qubit/env/postgres/attempts"
+	"qubit/env/postgres/campaigns"
+	"qubit/env/postgres/dispatchruns"
+	"qubit/env/postgres/impersonations"
+	"qubit/env/postgres/inbound"
 	"qubit/env/postgres/messages"
+	"qubit/env/postgres/migrations"
+	"qubit/env/postgres/outbox"
+	"qubit/env/postgres/providertemplates"
+	"qubit/env/postgres/rejections"
+	"qubit/env/postgres/schedulermembers"
+	"qubit/env/postgres/settings"
+	"qubit/env/postgres/signingkeys"
+	"qubit/env/postgres/templates"
+	"qubit/env/postgres/tenants"
 )
 
 // Client wraps the PostgreSQL connection pool and repositories
 type Client struct {
-	pool     *pgxpool.Pool
-	Messages *messages.Repository
+	pool              *pgxpool.Pool
+	Messages          *messages.Repository
+	Inbound           *inbound.Repository
+	Campaigns         *campaigns.Repository
+	Attempts          *attempts.Repository
+	Settings          *settings.Repository
+	APIKeys           *apikeys.Repository
+	Templates         *templates.Repository
+	Tenants           *tenants.Repository
+	Outbox            *outbox.Repository
+	Impersonations    *impersonations.Repository
+	SigningKeys       *signingkeys.Repository
+	Rejections        *rejections.Repository
+	DispatchRuns      *dispatchruns.Repository
+	Members           *schedulermembers.Repository
+	ProviderTemplates *providertemplates.Repository
 }
 
 // NewClient creates a new PostgreSQL client with connection pool
@@ -48,8 +77,22 @@ func NewClient(ctx context.Context, databaseURL string) (*Client, error) {
 	log.Println("✓ PostgreSQL connection established successfully")
 
 	client := &Client{
-		pool:     pool,
-		Messages: messages.NewRepository(pool),
+		pool:              pool,
+		Messages:          messages.NewRepository(pool),
+		Inbound:           inbound.NewRepository(pool),
+		Campaigns:         campaigns.NewRepository(pool),
+		Attempts:          attempts.NewRepository(pool),
+		Settings:          settings.NewRepository(pool),
+		APIKeys:           apikeys.NewRepository(pool),
+		Templates:         templates.NewRepository(pool),
+		Tenants:           tenants.NewRepository(pool),
+		Outbox:            outbox.NewRepository(pool),
+		Impersonations:    impersonations.NewRepository(pool),
+		SigningKeys:       signingkeys.NewRepository(pool),
+		Rejections:        rejections.NewRepository(pool),
+		DispatchRuns:      dispatchruns.NewRepository(pool),
+		ProviderTemplates: providertemplates.NewRepository(pool),
+		Members:           schedulermembers.NewRepository(pool),
 	}
 
 	return client, nil
@@ -64,6 +107,22 @@ func (c *Client) BeginTx(ctx context.Context) (pgx.Tx, error) {
 	return tx, nil
 }
 
+// Ping checks that a pooled connection to the database answers
+func (c *Client) Ping(ctx context.Context) error {
+	return c.pool.Ping(ctx)
+}
+
+// Migrate applies all pending schema migrations and returns their versions
+// Concurrent migrators wait for each other up to lockTimeout, owner identifies this one in their logs
+func (c *Client) Migrate(ctx context.Context, owner string, lockTimeout time.Duration) ([]string, error) {
+	return migrations.Run(ctx, c.pool, owner, lockTimeout)
+}
+
+// MigrationStatus lists every schema migration with the time it was applied
+func (c *Client) MigrationStatus(ctx context.Context) ([]migrations.Migration, error) {
+	return migrations.Status(ctx, c.pool)
+}
+
 // Close gracefully closes the database connection pool
 func (c *Client) Close() {
 	if c.pool != nil {
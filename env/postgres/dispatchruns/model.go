@@ -0,0 +1,27 @@
+package dispatchruns
+
+import (
+	"time"
+)
+
+// Run represents the report of a scheduler run data model for PostgreSQL persistence
+// This is a pure data structure with no business logic
+type Run struct {
+	ID          int64     `db:"id"`
+	InstanceID  string    `db:"instance_id"`
+	StartedAt   time.Time `db:"started_at"`
+	FinishedAt  time.Time `db:"finished_at"`
+	Claimed     int       `db:"claimed"`
+	Attempted   int       `db:"attempted"`
+	Sent        int       `db:"sent"`
+	Retried     int       `db:"retried"`
+	Failed      int       `db:"failed"`
+	Throttled   int       `db:"throttled"`
+	Deferred    int       `db:"deferred"`
+	Quarantined int       `db:"quarantined"`
+
+	// Errors counts the failed attempts per failure category
+	Errors map[string]int `db:"errors"`
+	// Error is the error that ended the run early, nil when it completed
+	Error *string `db:"error"`
+}
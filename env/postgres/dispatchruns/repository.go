@@ -0,0 +1,83 @@
+package dispatchruns
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"qubit/env/postgres/scan"
+)
+
+// runColumns is the column list selected for a Run, matching its db tags
+const runColumns = `id, instance_id, started_at, finished_at, claimed, attempted, sent, retried, failed, throttled, deferred, quarantined, errors, error`
+
+// Repository handles scheduler run data access operations
+type Repository struct {
+	pool *pgxpool.Pool
+}
+
+// NewRepository creates a new scheduler run repository
+func NewRepository(pool *pgxpool.Pool) *Repository {
+	return &Repository{
+		pool: pool,
+	}
+}
+
+// Create stores the report of a scheduler run
+// The ID will be populated after successful insertion
+func (r *Repository) Create(ctx context.Context, run *Run) error {
+	query := `
+		INSERT INTO dispatch_runs (instance_id, started_at, finished_at, claimed, attempted, sent, retried, failed, throttled, deferred, quarantined, errors, error)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		RETURNING id
+	`
+
+	if run.Errors == nil {
+		run.Errors = map[string]int{}
+	}
+
+	err := r.pool.QueryRow(ctx, query,
+		run.InstanceID, run.StartedAt, run.FinishedAt,
+		run.Claimed, run.Attempted, run.Sent, run.Retried, run.Failed, run.Throttled, run.Deferred, run.Quarantined,
+		run.Errors, run.Error,
+	).Scan(&run.ID)
+	if err != nil {
+		return fmt.Errorf("failed to store scheduler run: %w", err)
+	}
+
+	return nil
+}
+
+// List retrieves up to limit runs, newest first
+// A before greater than 0 only lists the runs with a lower ID, continuing a previous page
+func (r *Repository) List(ctx context.Context, before int64, limit int) ([]*Run, error) {
+	query := `
+		SELECT ` + runColumns + `
+		FROM dispatch_runs
+		WHERE $1::bigint = 0 OR id < $1
+		ORDER BY id DESC
+		LIMIT $2
+	`
+
+	runs, err := scan.All[Run](r.pool.Query(ctx, query, before, limit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query scheduler runs: %w", err)
+	}
+
+	return runs, nil
+}
+
+// DeleteBefore deletes the runs started before the cutoff
+// Returns the number of runs deleted
+func (r *Repository) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	query := `DELETE FROM dispatch_runs WHERE started_at < $1`
+
+	result, err := r.pool.Exec(ctx, query, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete scheduler runs: %w", err)
+	}
+
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,19 @@
+package impersonations
+
+import (
+	"time"
+)
+
+// Impersonation represents an audited request of an admin key acting as a tenant
+// This is a pure data structure with no business logic
+type Impersonation struct {
+	ID         int64     `db:"id"`
+	TenantID   int64     `db:"tenant_id"`
+	ActorKeyID *int64    `db:"actor_key_id"`
+	ActorName  string    `db:"actor_name"`
+	Method     string    `db:"method"`
+	Path       string    `db:"path"`
+	ClientIP   string    `db:"client_ip"`
+	Status     *int      `db:"status"`
+	CreatedAt  time.Time `db:"created_at"`
+}
@@ -0,0 +1,76 @@
+package impersonations
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"qubit/env/postgres/scan"
+)
+
+// impersonationColumns is the column list selected for an Impersonation, matching its db tags
+const impersonationColumns = `id, tenant_id, actor_key_id, actor_name, method, path, client_ip, status, created_at`
+
+// Repository handles impersonation audit data access operations
+type Repository struct {
+	pool *pgxpool.Pool
+}
+
+// NewRepository creates a new impersonation repository
+func NewRepository(pool *pgxpool.Pool) *Repository {
+	return &Repository{
+		pool: pool,
+	}
+}
+
+// Create records the start of an impersonated request
+// The ID will be populated after successful insertion
+func (r *Repository) Create(ctx context.Context, i *Impersonation) error {
+	query := `
+		INSERT INTO impersonations (tenant_id, actor_key_id, actor_name, method, path, client_ip, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`
+
+	if i.CreatedAt.IsZero() {
+		i.CreatedAt = time.Now()
+	}
+
+	err := r.pool.QueryRow(ctx, query, i.TenantID, i.ActorKeyID, i.ActorName, i.Method, i.Path, i.ClientIP, i.CreatedAt).Scan(&i.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create impersonation: %w", err)
+	}
+
+	return nil
+}
+
+// SetStatus records the response status of an impersonated request
+func (r *Repository) SetStatus(ctx context.Context, id int64, status int) error {
+	query := `UPDATE impersonations SET status = $1 WHERE id = $2`
+
+	if _, err := r.pool.Exec(ctx, query, status, id); err != nil {
+		return fmt.Errorf("failed to set impersonation status: %w", err)
+	}
+
+	return nil
+}
+
+// ListByTenant retrieves the impersonations of a tenant, newest first
+func (r *Repository) ListByTenant(ctx context.Context, tenantID int64, limit int) ([]*Impersonation, error) {
+	query := `
+		SELECT ` + impersonationColumns + `
+		FROM impersonations
+		WHERE tenant_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2
+	`
+
+	found, err := scan.All[Impersonation](r.pool.Query(ctx, query, tenantID, limit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query impersonations: %w", err)
+	}
+
+	return found, nil
+}
@@ -0,0 +1,30 @@
+package inbound
+
+import (
+	"time"
+)
+
+// Message represents an inbound message data model for PostgreSQL persistence
+// This is a pure data structure with no business logic
+type Message struct {
+	ID          int64     `db:"id"`
+	PhoneNumber string    `db:"phone_number"`
+	Content     string    `db:"content"`
+	ReceivedAt  time.Time `db:"received_at"`
+
+	ReplyToID *int64 `db:"reply_to_id"`
+}
+
+// ReplyTo holds the outbound message an inbound message was correlated to
+type ReplyTo struct {
+	ID          int64      `db:"id"`
+	Content     string     `db:"content"`
+	MessageID   *string    `db:"message_id"`
+	ProcessedAt *time.Time `db:"processed_at"`
+}
+
+// MessageWithReplyTo is an inbound message joined with its correlated outbound message
+type MessageWithReplyTo struct {
+	Message
+	ReplyTo *ReplyTo
+}
@@ -0,0 +1,148 @@
+package inbound
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"qubit/env/postgres/scan"
+)
+
+// replyToRow is an inbound message row joined with the columns of the message it replies to
+type replyToRow struct {
+	Message
+	ReplyContent     *string    `db:"reply_content"`
+	ReplyMessageID   *string    `db:"reply_message_id"`
+	ReplyProcessedAt *time.Time `db:"reply_processed_at"`
+}
+
+// Repository handles inbound message data access operations
+type Repository struct {
+	pool *pgxpool.Pool
+}
+
+// NewRepository creates a new inbound message repository
+func NewRepository(pool *pgxpool.Pool) *Repository {
+	return &Repository{
+		pool: pool,
+	}
+}
+
+// Create inserts a new inbound message into the database
+// The ID will be populated after successful insertion
+func (r *Repository) Create(ctx context.Context, msg *Message) error {
+	query := `
+		INSERT INTO inbound_messages (phone_number, content, received_at, reply_to_id)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`
+
+	if msg.ReceivedAt.IsZero() {
+		msg.ReceivedAt = time.Now()
+	}
+
+	err := r.pool.QueryRow(
+		ctx,
+		query,
+		msg.PhoneNumber,
+		msg.Content,
+		msg.ReceivedAt,
+		msg.ReplyToID,
+	).Scan(&msg.ID)
+
+	if err != nil {
+		return fmt.Errorf("failed to create inbound message: %w", err)
+	}
+
+	return nil
+}
+
+// List retrieves inbound messages together with the outbound message they reply to
+// If limit is 0, all inbound messages are returned
+func (r *Repository) List(ctx context.Context, limit int) ([]*MessageWithReplyTo, error) {
+	query, args := listQuery(limit)
+
+	rows, err := scan.All[replyToRow](r.pool.Query(ctx, query, args...))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query inbound messages: %w", err)
+	}
+
+	messages := make([]*MessageWithReplyTo, 0, len(rows))
+	for _, row := range rows {
+		messages = append(messages, row.withReplyTo())
+	}
+
+	return messages, nil
+}
+
+// Each calls fn with every inbound message and the outbound message it replies to, one at a time
+// Only the current row is held in memory, so it suits listings of any size
+func (r *Repository) Each(ctx context.Context, fn func(*MessageWithReplyTo) error) error {
+	query, args := listQuery(0)
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query inbound messages: %w", err)
+	}
+
+	err = scan.Each(rows, func(row *replyToRow) error {
+		return fn(row.withReplyTo())
+	})
+	if err != nil {
+		return fmt.Errorf("failed to query inbound messages: %w", err)
+	}
+
+	return nil
+}
+
+// listQuery renders the listing query of inbound messages with their reply, oldest first
+func listQuery(limit int) (string, []interface{}) {
+	query := `
+		SELECT i.id, i.phone_number, i.content, i.received_at, i.reply_to_id,
+		       m.content AS reply_content, m.message_id AS reply_message_id, m.processed_at AS reply_processed_at
+		FROM inbound_messages i
+		LEFT JOIN messages m ON m.id = i.reply_to_id
+		ORDER BY i.received_at ASC
+	`
+
+	args := []interface{}{}
+	if limit > 0 {
+		query += " LIMIT $1"
+		args = append(args, limit)
+	}
+
+	return query, args
+}
+
+// withReplyTo converts the row, the reply is only set when the outbound message still exists
+func (row *replyToRow) withReplyTo() *MessageWithReplyTo {
+	msg := &MessageWithReplyTo{Message: row.Message}
+	if row.ReplyToID != nil && row.ReplyContent != nil {
+		msg.ReplyTo = &ReplyTo{
+			ID:          *row.ReplyToID,
+			Content:     *row.ReplyContent,
+			MessageID:   row.ReplyMessageID,
+			ProcessedAt: row.ReplyProcessedAt,
+		}
+	}
+	return msg
+}
+
+// ListByReplyTo retrieves inbound messages correlated to the given outbound message
+func (r *Repository) ListByReplyTo(ctx context.Context, messageID int64) ([]*Message, error) {
+	query := `
+		SELECT id, phone_number, content, received_at, reply_to_id
+		FROM inbound_messages
+		WHERE reply_to_id = $1
+		ORDER BY received_at ASC
+	`
+
+	messages, err := scan.All[Message](r.pool.Query(ctx, query, messageID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query replies: %w", err)
+	}
+
+	return messages, nil
+}
@@ -0,0 +1,86 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// LeaderLock is leadership held as a session-level advisory lock on a dedicated connection
+// The server drops the lock as soon as that connection is lost, so a dead leader is replaced without waiting for a lease
+type LeaderLock struct {
+	pool *pgxpool.Pool
+	key  int64
+
+	mu   sync.Mutex
+	conn *pgxpool.Conn // holds the lock, nil while another session is the leader
+}
+
+// NewLeaderLock creates a leader lock on the advisory lock key
+func (c *Client) NewLeaderLock(key int64) *LeaderLock {
+	return &LeaderLock{
+		pool: c.pool,
+		key:  key,
+	}
+}
+
+// Campaign takes the lock unless another session holds it
+// While leading it verifies that the connection holding the lock is still alive
+func (l *LeaderLock) Campaign(ctx context.Context) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn != nil {
+		if err := l.conn.Ping(ctx); err == nil {
+			return true, nil
+		}
+
+		// The lock went with the connection, close it so the pool does not reuse it
+		_ = l.conn.Conn().Close(context.Background())
+		l.conn.Release()
+		l.conn = nil
+	}
+
+	conn, err := l.pool.Acquire(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire leader lock connection: %w", err)
+	}
+
+	var acquired bool
+	if err := conn.QueryRow(ctx, `SELECT pg_try_advisory_lock($1)`, l.key).Scan(&acquired); err != nil {
+		conn.Release()
+		return false, fmt.Errorf("failed to take leader lock: %w", err)
+	}
+
+	if !acquired {
+		conn.Release()
+		return false, nil
+	}
+
+	l.conn = conn
+	return true, nil
+}
+
+// Resign releases the lock so another instance can take over right away
+func (l *LeaderLock) Resign(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn == nil {
+		return nil
+	}
+
+	conn := l.conn
+	l.conn = nil
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, `SELECT pg_advisory_unlock($1)`, l.key); err != nil {
+		// Closing the connection releases the lock as well
+		_ = conn.Conn().Close(context.Background())
+		return fmt.Errorf("failed to release leader lock: %w", err)
+	}
+
+	return nil
+}
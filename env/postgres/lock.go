@@ -0,0 +1,41 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+)
+
+// Advisory lock keys, one per task serialized across instances
+const (
+	// SchedulerLockKey is held by the instance running a message scheduler tick
+	SchedulerLockKey int64 = 0x7175626974010001
+	// SchedulerLeaderKey is held by the instance elected to run the message scheduler, see LeaderLock
+	SchedulerLeaderKey int64 = 0x7175626974010002
+)
+
+// TryLock takes the advisory lock key without waiting, in a transaction held until release is called
+// acquired is false when another session holds the lock; release is nil in that case
+// The lock is dropped by the server as well when the connection is lost
+func (c *Client) TryLock(ctx context.Context, key int64) (release func(), acquired bool, err error) {
+	tx, err := c.pool.Begin(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to begin lock transaction: %w", err)
+	}
+
+	if err := tx.QueryRow(ctx, `SELECT pg_try_advisory_xact_lock($1)`, key).Scan(&acquired); err != nil {
+		_ = tx.Rollback(context.Background())
+		return nil, false, fmt.Errorf("failed to take advisory lock: %w", err)
+	}
+
+	if !acquired {
+		_ = tx.Rollback(context.Background())
+		return nil, false, nil
+	}
+
+	release = func() {
+		// Ending the transaction releases the lock, even when the work was cancelled
+		_ = tx.Rollback(context.Background())
+	}
+
+	return release, true, nil
+}
@@ -0,0 +1,88 @@
+package messages
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"qubit/env/postgres/scan"
+)
+
+// archivableMessages selects up to $2 sent messages processed before $1, oldest first
+// Rows locked by a concurrent archiver are skipped
+const archivableMessages = `
+	SELECT id FROM messages
+	WHERE status = 'sent' AND processed_at < $1
+	ORDER BY processed_at ASC
+	LIMIT $2
+	FOR UPDATE SKIP LOCKED
+`
+
+// archiveColumns are the columns copied into messages_archive, which has no shard_key
+const archiveColumns = messageColumns + `, campaign_id, variant, redriven_from`
+
+// ArchiveSent moves up to limit sent messages processed before the cutoff into messages_archive
+// Their attempts and replies stay in place, linked by the unchanged message id
+// Returns the number of messages archived
+func (r *Repository) ArchiveSent(ctx context.Context, before time.Time, limit int) (int64, error) {
+	query := `
+		WITH moved AS (
+			DELETE FROM messages
+			WHERE id IN (` + archivableMessages + `)
+			RETURNING ` + archiveColumns + `
+		)
+		INSERT INTO messages_archive (` + archiveColumns + `)
+		SELECT ` + archiveColumns + ` FROM moved
+	`
+
+	result, err := r.pool.Exec(ctx, query, before, limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to archive sent messages: %w", err)
+	}
+
+	return result.RowsAffected(), nil
+}
+
+// GetArchivedByID retrieves a message moved to messages_archive by its ID
+// A non-nil tenantID only matches a message of that tenant
+// Returns ErrNotFound if no archived message has the ID
+func (r *Repository) GetArchivedByID(ctx context.Context, id int64, tenantID *int64) (*Message, error) {
+	query := `SELECT ` + messageColumns + ` FROM messages_archive WHERE id = $1 AND ($2::bigint IS NULL OR tenant_id = $2)`
+
+	msg, err := scan.One[Message](r.pool.Query(ctx, query, id, tenantID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get archived message: %w", err)
+	}
+
+	return msg, nil
+}
+
+// DeleteSent deletes up to limit sent messages processed before the cutoff together with their attempts
+// Replies to a deleted message are kept without the link
+// Returns the number of messages deleted
+func (r *Repository) DeleteSent(ctx context.Context, before time.Time, limit int) (int64, error) {
+	query := `
+		WITH doomed AS (` + archivableMessages + `),
+		attempts AS (
+			DELETE FROM message_attempts WHERE message_id IN (SELECT id FROM doomed)
+		),
+		replies AS (
+			UPDATE inbound_messages SET reply_to_id = NULL WHERE reply_to_id IN (SELECT id FROM doomed)
+		)
+		DELETE FROM messages
+		WHERE id IN (SELECT id FROM doomed)
+	`
+
+	result, err := r.pool.Exec(ctx, query, before, limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete sent messages: %w", err)
+	}
+
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,153 @@
+package messages
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"qubit/env/postgres/scan"
+)
+
+// Filter narrows a message listing, zero fields are ignored
+// Search matches content case-insensitively as a substring
+// ExternalRefType and ExternalRefID are only applied together
+// Metadata matches messages whose metadata contains every given key and value
+// IncludeArchived also lists the messages moved to messages_archive
+type Filter struct {
+	Status        string
+	PhoneNumber   string
+	CreatedFrom   *time.Time
+	CreatedTo     *time.Time
+	ProcessedFrom *time.Time
+	ProcessedTo   *time.Time
+	Search        string
+
+	ExternalRefType string
+	ExternalRefID   string
+
+	Metadata map[string]string
+
+	// TenantID limits the list to the messages of a tenant, nil lists those of every tenant
+	TenantID *int64
+
+	IncludeArchived bool
+
+	Limit int
+}
+
+// queryBuilder collects WHERE conditions together with their bound arguments
+type queryBuilder struct {
+	conditions []string
+	args       []interface{}
+}
+
+// where adds a condition whose single placeholder is written as ?, bound to arg
+func (b *queryBuilder) where(condition string, arg interface{}) {
+	b.args = append(b.args, arg)
+	b.conditions = append(b.conditions, strings.Replace(condition, "?", fmt.Sprintf("$%d", len(b.args)), 1))
+}
+
+// clause renders the WHERE clause, empty when there are no conditions
+func (b *queryBuilder) clause() string {
+	if len(b.conditions) == 0 {
+		return ""
+	}
+	return "WHERE " + strings.Join(b.conditions, " AND ")
+}
+
+// escapeLike escapes the LIKE wildcards in s so it matches literally
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// List retrieves the messages matching the filter ordered by creation time
+// If Limit is 0, all matching messages are returned
+func (r *Repository) List(ctx context.Context, f Filter) ([]*Message, error) {
+	query, args := f.query()
+
+	messages, err := scan.All[Message](r.pool.Query(ctx, query, args...))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query messages: %w", err)
+	}
+
+	return messages, nil
+}
+
+// Each calls fn with the messages matching the filter one at a time, ordered by creation time
+// Only the current row is held in memory, so it suits listings of any size
+func (r *Repository) Each(ctx context.Context, f Filter, fn func(*Message) error) error {
+	query, args := f.query()
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query messages: %w", err)
+	}
+
+	if err := scan.Each(rows, fn); err != nil {
+		return fmt.Errorf("failed to query messages: %w", err)
+	}
+
+	return nil
+}
+
+// query renders the listing query of the filter with its arguments
+func (f Filter) query() (string, []interface{}) {
+	var b queryBuilder
+
+	if f.Status != "" {
+		b.where("status = ?", f.Status)
+	}
+	if f.PhoneNumber != "" {
+		b.where("phone_number = ?", f.PhoneNumber)
+	}
+	if f.CreatedFrom != nil {
+		b.where("created_at >= ?", *f.CreatedFrom)
+	}
+	if f.CreatedTo != nil {
+		b.where("created_at < ?", *f.CreatedTo)
+	}
+	if f.ProcessedFrom != nil {
+		b.where("processed_at >= ?", *f.ProcessedFrom)
+	}
+	if f.ProcessedTo != nil {
+		b.where("processed_at < ?", *f.ProcessedTo)
+	}
+	if f.Search != "" {
+		b.where("content ILIKE '%' || ? || '%'", escapeLike(f.Search))
+	}
+	if f.ExternalRefType != "" && f.ExternalRefID != "" {
+		b.where("external_ref_type = ?", f.ExternalRefType)
+		b.where("external_ref_id = ?", f.ExternalRefID)
+	}
+	if len(f.Metadata) > 0 {
+		b.where("metadata @> ?", f.Metadata)
+	}
+	if f.TenantID != nil {
+		b.where("tenant_id = ?", *f.TenantID)
+	}
+
+	source := "messages"
+	if f.IncludeArchived {
+		source = `(
+			SELECT ` + messageColumns + ` FROM messages
+			UNION ALL
+			SELECT ` + messageColumns + ` FROM messages_archive
+		) AS messages`
+	}
+
+	query := `
+		SELECT ` + messageColumns + `
+		FROM ` + source + `
+		` + b.clause() + `
+		ORDER BY created_at ASC
+	`
+
+	args := b.args
+	if f.Limit > 0 {
+		args = append(args, f.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	return query, args
+}
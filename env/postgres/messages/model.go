@@ -8,10 +8,59 @@ import (
 // This is a pure data structure with no business logic
 type Message struct {
 	ID          int64     `db:"id"`
+	UUID        string    `db:"uuid"`
 	PhoneNumber string    `db:"phone_number"`
 	Content     string    `db:"content"`
 	CreatedAt   time.Time `db:"created_at"`
 
 	MessageID   *string    `db:"message_id"`
 	ProcessedAt *time.Time `db:"processed_at"`
+
+	RetryCount    int        `db:"retry_count"`
+	NextAttemptAt *time.Time `db:"next_attempt_at"`
+
+	Status   string  `db:"status"`
+	Provider *string `db:"provider"`
+
+	ScheduledAt *time.Time `db:"scheduled_at"`
+	LockedAt    *time.Time `db:"locked_at"`
+
+	LockedBy       *string    `db:"locked_by"`
+	LeaseExpiresAt *time.Time `db:"lease_expires_at"`
+
+	IsTest        bool `db:"is_test"`
+	Transactional bool `db:"transactional"`
+
+	FanoutID *string `db:"fanout_id"`
+
+	RetryPolicy *RetryPolicy `db:"retry_policy"`
+
+	ExternalRefType *string `db:"external_ref_type"`
+	ExternalRefID   *string `db:"external_ref_id"`
+
+	// Metadata holds caller-defined fields, stored as a JSON object
+	Metadata map[string]string `db:"metadata"`
+
+	// TenantID is the tenant whose API key created the message
+	TenantID *int64 `db:"tenant_id"`
+
+	// ProviderTemplate is the approved template of a template-only provider the message is sent as
+	ProviderTemplate *ProviderTemplate `db:"provider_template"`
+
+	ContentLocale *string `db:"content_locale"`
+}
+
+// RetryPolicy is the per-message retry policy stored as JSON
+type RetryPolicy struct {
+	MaxRetries       int    `json:"maxRetries"`
+	Backoff          string `json:"backoff"`
+	BaseDelaySeconds int    `json:"baseDelaySeconds"`
+	MaxDelaySeconds  int    `json:"maxDelaySeconds"`
+}
+
+// ProviderTemplate references a provider template and its parameters, stored as JSON
+type ProviderTemplate struct {
+	Name       string   `json:"name"`
+	Language   string   `json:"language"`
+	Parameters []string `json:"parameters"`
 }
@@ -0,0 +1,80 @@
+package messages
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// RedriveFilter selects the archived messages sent again by a re-drive
+// The processed range is required, PhoneNumber and CampaignID narrow it when set
+// Test messages and messages re-driven before are never selected
+type RedriveFilter struct {
+	ProcessedFrom time.Time
+	ProcessedTo   time.Time
+	PhoneNumber   string
+	CampaignID    *int64
+}
+
+// where renders the conditions of the filter on messages_archive aliased as a
+func (f RedriveFilter) where() queryBuilder {
+	var b queryBuilder
+
+	b.where("a.processed_at >= ?", f.ProcessedFrom)
+	b.where("a.processed_at < ?", f.ProcessedTo)
+	if f.PhoneNumber != "" {
+		b.where("a.phone_number = ?", f.PhoneNumber)
+	}
+	if f.CampaignID != nil {
+		b.where("a.campaign_id = ?", *f.CampaignID)
+	}
+	b.conditions = append(b.conditions,
+		"NOT a.is_test",
+		"NOT EXISTS (SELECT 1 FROM messages m WHERE m.redriven_from = a.id)",
+		"NOT EXISTS (SELECT 1 FROM messages_archive r WHERE r.redriven_from = a.id)",
+	)
+
+	return b
+}
+
+// CountRedrivable counts the archived messages a re-drive with the filter would send again
+func (r *Repository) CountRedrivable(ctx context.Context, f RedriveFilter) (int64, error) {
+	b := f.where()
+	query := `SELECT COUNT(*) FROM messages_archive a ` + b.clause()
+
+	var count int64
+	if err := r.pool.QueryRow(ctx, query, b.args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count redrivable messages: %w", err)
+	}
+
+	return count, nil
+}
+
+// RedriveArchived copies up to limit archived messages matching the filter into messages as new pending messages,
+// oldest first; each copy keeps the recipient, content and attributes of its original and links it in redriven_from
+// A non-nil provider pins the copies to it instead of the provider of their original
+// Returns the number of messages created, an archived message is never copied twice
+func (r *Repository) RedriveArchived(ctx context.Context, f RedriveFilter, provider *string, limit int) (int64, error) {
+	b := f.where()
+	b.args = append(b.args, limit, provider, time.Now())
+	n := len(b.args)
+
+	query := fmt.Sprintf(`
+		INSERT INTO messages (phone_number, content, created_at, status, provider, is_test, transactional, retry_policy,
+			external_ref_type, external_ref_id, metadata, tenant_id, provider_template, campaign_id, variant, redriven_from, content_locale)
+		SELECT a.phone_number, a.content, $%[3]d, 'pending', COALESCE($%[2]d, a.provider), a.is_test, a.transactional, a.retry_policy,
+			a.external_ref_type, a.external_ref_id, a.metadata, a.tenant_id, a.provider_template, a.campaign_id, a.variant, a.id, a.content_locale
+		FROM messages_archive a
+		%[4]s
+		ORDER BY a.processed_at ASC, a.id ASC
+		LIMIT $%[1]d
+		ON CONFLICT (redriven_from) WHERE redriven_from IS NOT NULL DO NOTHING
+	`, n-2, n-1, n, b.clause())
+
+	result, err := r.pool.Exec(ctx, query, b.args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to redrive archived messages: %w", err)
+	}
+
+	return result.RowsAffected(), nil
+}
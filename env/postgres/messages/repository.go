@@ -2,13 +2,55 @@ package messages
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
+
+	"qubit/env/postgres/scan"
+)
+
+// Message statuses as stored in the status column
+const (
+	StatusPending   = "pending"
+	StatusSending   = "sending"
+	StatusSent      = "sent"
+	StatusFailed    = "failed"
+	StatusCancelled = "cancelled"
+	StatusThrottled = "throttled"
+	// StatusQuarantined is set when the content links outside the URL allow-list
+	StatusQuarantined = "quarantined"
 )
 
+// ErrNotFound is returned when a message does not exist
+var ErrNotFound = errors.New("message not found")
+
+// ErrNotPending is returned when a message can no longer be modified because it left the pending status
+var ErrNotPending = errors.New("message is no longer pending")
+
+// ErrDuplicateUUID is returned when a message with the same UUID already exists
+var ErrDuplicateUUID = errors.New("message uuid already exists")
+
+// ErrLeaseLost is returned when the outcome of a send is stored for a message no longer claimed by the instance,
+// e.g. after its lease expired and another instance claimed it again
+var ErrLeaseLost = errors.New("message lease was lost")
+
+// uniqueViolation is the Postgres error code of a unique constraint violation
+const uniqueViolation = "23505"
+
+// messageColumns is the column list selected for a Message, matching its db tags
+const messageColumns = `id, uuid, phone_number, content, created_at, message_id, processed_at, retry_count, next_attempt_at, status, provider, scheduled_at, locked_at, locked_by, lease_expires_at, is_test, transactional, fanout_id, retry_policy, external_ref_type, external_ref_id, metadata, tenant_id, provider_template, content_locale`
+
+// querier is implemented by both the pool and a transaction
+type querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
 // Repository handles message data access operations
 type Repository struct {
 	pool *pgxpool.Pool
@@ -21,140 +63,601 @@ func NewRepository(pool *pgxpool.Pool) *Repository {
 	}
 }
 
-// ListSent retrieves only sent messages from the database (where processed_at IS NOT NULL)
+// upserted is a message returned by an upsert along with whether its row was inserted
+type upserted struct {
+	Message
+	Inserted bool `db:"inserted"`
+}
+
+// GetByID retrieves a message by its ID
+// A non-nil tenantID only matches a message of that tenant
+// Returns ErrNotFound if the message does not exist
+func (r *Repository) GetByID(ctx context.Context, id int64, tenantID *int64) (*Message, error) {
+	query := `SELECT ` + messageColumns + ` FROM messages WHERE id = $1 AND ($2::bigint IS NULL OR tenant_id = $2)`
+
+	msg, err := scan.One[Message](r.pool.Query(ctx, query, id, tenantID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get message: %w", err)
+	}
+
+	return msg, nil
+}
+
+// ListSent retrieves only sent messages from the database
 // If limit is 0, all sent messages are returned
 func (r *Repository) ListSent(ctx context.Context, limit int) ([]*Message, error) {
+	return r.ListByStatus(ctx, StatusSent, limit)
+}
+
+// ListByStatus retrieves messages in the given status ordered by creation time
+// If limit is 0, all matching messages are returned
+func (r *Repository) ListByStatus(ctx context.Context, status string, limit int) ([]*Message, error) {
 	query := `
-		SELECT id, phone_number, content, created_at, message_id, processed_at
+		SELECT ` + messageColumns + `
 		FROM messages
-		WHERE processed_at IS NOT NULL
+		WHERE status = $1
 		ORDER BY created_at ASC
 	`
 
-	args := []interface{}{}
+	args := []interface{}{status}
 	if limit > 0 {
-		query += " LIMIT $1"
+		query += " LIMIT $2"
 		args = append(args, limit)
 	}
 
-	rows, err := r.pool.Query(ctx, query, args...)
+	messages, err := scan.All[Message](r.pool.Query(ctx, query, args...))
 	if err != nil {
 		return nil, fmt.Errorf("failed to query messages: %w", err)
 	}
+
+	return messages, nil
+}
+
+// ClaimUnsent marks due pending messages as sending and returns them, oldest first
+// Uses FOR UPDATE SKIP LOCKED so concurrent instances claim disjoint messages
+// Messages still waiting out their retry backoff or scheduled for later are skipped
+// The claim is committed on return, no row locks are held while the messages are sent
+// Each claimed message records lockedBy and a lease expiring after lease
+// Messages of skipProviders stay pending, messages without a provider belong to defaultProvider
+// Only messages whose shard_key is in shards are claimed, any shard when shards is empty
+func (r *Repository) ClaimUnsent(ctx context.Context, limit int, lockedBy string, lease time.Duration, defaultProvider string, skipProviders []string, shards []int) ([]*Message, error) {
+	query := `
+		WITH due AS (
+			SELECT id AS due_id
+			FROM messages
+			WHERE status = 'pending'
+			  AND (next_attempt_at IS NULL OR next_attempt_at <= NOW())
+			  AND (scheduled_at IS NULL OR scheduled_at <= NOW())
+			  AND COALESCE(provider, $4) <> ALL($5)
+			  AND (cardinality($6::int[]) = 0 OR shard_key = ANY($6))
+			ORDER BY created_at ASC
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE messages
+		SET status = 'sending', locked_at = NOW(), locked_by = $2,
+		    lease_expires_at = NOW() + make_interval(secs => $3)
+		FROM due
+		WHERE id = due.due_id
+		RETURNING ` + messageColumns
+
+	if skipProviders == nil {
+		skipProviders = []string{}
+	}
+	if shards == nil {
+		shards = []int{}
+	}
+
+	claimed, err := scan.All[Message](r.pool.Query(ctx, query, limit, lockedBy, lease.Seconds(), defaultProvider, skipProviders, shards))
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim unsent messages: %w", err)
+	}
+
+	// UPDATE ... RETURNING does not keep the CTE order
+	sort.Slice(claimed, func(i, j int) bool {
+		return claimed[i].CreatedAt.Before(claimed[j].CreatedAt)
+	})
+
+	return claimed, nil
+}
+
+// ClaimByID marks a single pending message as sending regardless of its schedule or backoff and returns it
+// Returns ErrNotFound if the message does not exist and ErrNotPending if it is not pending
+func (r *Repository) ClaimByID(ctx context.Context, id int64, lockedBy string, lease time.Duration) (*Message, error) {
+	query := `
+		UPDATE messages
+		SET status = 'sending', locked_at = NOW(), locked_by = $2,
+		    lease_expires_at = NOW() + make_interval(secs => $3)
+		WHERE id = $1 AND status = 'pending'
+		RETURNING ` + messageColumns
+
+	msg, err := scan.One[Message](r.pool.Query(ctx, query, id, lockedBy, lease.Seconds()))
+	if err == nil {
+		return msg, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("failed to claim message: %w", err)
+	}
+
+	// Nothing was claimed, tell a missing message apart from one that is not pending
+	if _, err := r.GetByID(ctx, id, nil); err != nil {
+		return nil, err
+	}
+
+	return nil, ErrNotPending
+}
+
+// Release returns claimed messages that were not sent back to pending
+func (r *Repository) Release(ctx context.Context, ids []int64) error {
+	query := `
+		UPDATE messages
+		SET status = 'pending', locked_at = NULL, locked_by = NULL, lease_expires_at = NULL
+		WHERE id = ANY($1) AND status = 'sending'
+	`
+
+	if _, err := r.pool.Exec(ctx, query, ids); err != nil {
+		return fmt.Errorf("failed to release messages: %w", err)
+	}
+
+	return nil
+}
+
+// ReapStuck returns messages whose lease expired before they left sending back to pending
+// Returns the number of reaped messages
+func (r *Repository) ReapStuck(ctx context.Context) (int64, error) {
+	query := `
+		UPDATE messages
+		SET status = 'pending', locked_at = NULL, locked_by = NULL, lease_expires_at = NULL
+		WHERE status = 'sending' AND (lease_expires_at IS NULL OR lease_expires_at < NOW())
+	`
+
+	result, err := r.pool.Exec(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to reap stuck messages: %w", err)
+	}
+
+	return result.RowsAffected(), nil
+}
+
+// ListInFlight retrieves the messages currently claimed by any instance, soonest lease expiry first
+func (r *Repository) ListInFlight(ctx context.Context) ([]*Message, error) {
+	query := `
+		SELECT ` + messageColumns + `
+		FROM messages
+		WHERE status = 'sending'
+		ORDER BY lease_expires_at ASC NULLS FIRST
+	`
+
+	messages, err := scan.All[Message](r.pool.Query(ctx, query))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query in-flight messages: %w", err)
+	}
+
+	return messages, nil
+}
+
+// RecipientCount holds the messages sent to a phone number within a window
+type RecipientCount struct {
+	Count  int
+	Oldest time.Time
+}
+
+// CountSentTo counts the messages sent to each of phoneNumbers at or after since
+// Phone numbers without sent messages are missing from the result
+func (r *Repository) CountSentTo(ctx context.Context, phoneNumbers []string, since time.Time) (map[string]RecipientCount, error) {
+	query := `
+		SELECT phone_number, COUNT(*), MIN(processed_at)
+		FROM messages
+		WHERE status = 'sent' AND phone_number = ANY($1) AND processed_at >= $2
+		GROUP BY phone_number
+	`
+
+	rows, err := r.pool.Query(ctx, query, phoneNumbers, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count sent messages: %w", err)
+	}
 	defer rows.Close()
 
-	var messages []*Message
+	counts := make(map[string]RecipientCount, len(phoneNumbers))
 	for rows.Next() {
-		msg := &Message{}
-		err := rows.Scan(
-			&msg.ID,
-			&msg.PhoneNumber,
-			&msg.Content,
-			&msg.CreatedAt,
-			&msg.MessageID,
-			&msg.ProcessedAt,
+		var (
+			phoneNumber string
+			count       RecipientCount
 		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan message: %w", err)
+		if err := rows.Scan(&phoneNumber, &count.Count, &count.Oldest); err != nil {
+			return nil, fmt.Errorf("failed to scan sent count: %w", err)
 		}
-		messages = append(messages, msg)
+		counts[phoneNumber] = count
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating messages: %w", err)
+		return nil, fmt.Errorf("error iterating sent counts: %w", err)
 	}
 
-	return messages, nil
+	return counts, nil
+}
+
+// TenantUsage counts the messages a tenant created since the start of the UTC day and within the last minute
+type TenantUsage struct {
+	Today      int64 `db:"today"`
+	LastMinute int64 `db:"last_minute"`
 }
 
-// ListAndLockUnsent retrieves unsent messages and locks them for processing
-// Uses SELECT FOR UPDATE SKIP LOCKED to prevent multiple instances from processing the same messages
-// This method MUST be called within a transaction
-func (r *Repository) ListAndLockUnsent(ctx context.Context, tx pgx.Tx, limit int) ([]*Message, error) {
+// CountTenantUsage counts the messages of a tenant created since the start of the UTC day and within the last minute
+// Both windows are taken from the database clock, so instances with skewed clocks count the same day
+func (r *Repository) CountTenantUsage(ctx context.Context, tenantID int64) (*TenantUsage, error) {
 	query := `
-		SELECT id, phone_number, content, created_at, message_id, processed_at
+		SELECT COUNT(*) FILTER (WHERE created_at >= date_trunc('day', NOW() AT TIME ZONE 'UTC')) AS today,
+			COUNT(*) FILTER (WHERE created_at >= NOW() - INTERVAL '1 minute') AS last_minute
 		FROM messages
-		WHERE processed_at IS NULL
-		ORDER BY created_at ASC
-		LIMIT $1
-		FOR UPDATE SKIP LOCKED
+		WHERE tenant_id = $1 AND created_at >= LEAST(date_trunc('day', NOW() AT TIME ZONE 'UTC'), NOW() - INTERVAL '1 minute')
+	`
+
+	usage, err := scan.One[TenantUsage](r.pool.Query(ctx, query, tenantID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to count tenant usage: %w", err)
+	}
+
+	return usage, nil
+}
+
+// TenantActivity counts the messages of a tenant created and sent in a baseline window and the current window
+type TenantActivity struct {
+	CreatedBaseline int64
+	CreatedCurrent  int64
+	SentBaseline    int64
+	SentCurrent     int64
+}
+
+// CountTenantActivity counts the messages of every tenant created and sent in the baseline window [from, split)
+// and the current window [split, to); tenants without messages in either window are missing from the result
+func (r *Repository) CountTenantActivity(ctx context.Context, from, split, to time.Time) (map[int64]TenantActivity, error) {
+	query := `
+		SELECT tenant_id,
+		       COUNT(*) FILTER (WHERE created_at >= $1 AND created_at < $2),
+		       COUNT(*) FILTER (WHERE created_at >= $2 AND created_at < $3),
+		       COUNT(*) FILTER (WHERE status = 'sent' AND processed_at >= $1 AND processed_at < $2),
+		       COUNT(*) FILTER (WHERE status = 'sent' AND processed_at >= $2 AND processed_at < $3)
+		FROM messages
+		WHERE tenant_id IS NOT NULL AND (created_at >= $1 OR processed_at >= $1)
+		GROUP BY tenant_id
 	`
 
-	rows, err := tx.Query(ctx, query, limit)
+	rows, err := r.pool.Query(ctx, query, from, split, to)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query and lock unsent messages: %w", err)
+		return nil, fmt.Errorf("failed to count tenant activity: %w", err)
 	}
 	defer rows.Close()
 
-	var messages []*Message
+	activity := make(map[int64]TenantActivity)
 	for rows.Next() {
-		msg := &Message{}
-		err := rows.Scan(
-			&msg.ID,
-			&msg.PhoneNumber,
-			&msg.Content,
-			&msg.CreatedAt,
-			&msg.MessageID,
-			&msg.ProcessedAt,
+		var (
+			tenantID int64
+			a        TenantActivity
 		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan message: %w", err)
+		if err := rows.Scan(&tenantID, &a.CreatedBaseline, &a.CreatedCurrent, &a.SentBaseline, &a.SentCurrent); err != nil {
+			return nil, fmt.Errorf("failed to scan tenant activity: %w", err)
 		}
-		messages = append(messages, msg)
+		activity[tenantID] = a
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating messages: %w", err)
+		return nil, fmt.Errorf("error iterating tenant activity: %w", err)
 	}
 
-	return messages, nil
+	return activity, nil
+}
+
+// Throttle moves a claimed message that may not be sent yet to status and releases its claim
+// Deferred messages go back to pending with nextAttemptAt, rejected ones to throttled
+func (r *Repository) Throttle(ctx context.Context, id int64, status string, nextAttemptAt *time.Time) error {
+	query := `
+		UPDATE messages
+		SET status = $1, next_attempt_at = $2, locked_at = NULL, locked_by = NULL, lease_expires_at = NULL
+		WHERE id = $3 AND status = 'sending'
+	`
+
+	result, err := r.pool.Exec(ctx, query, status, nextAttemptAt, id)
+	if err != nil {
+		return fmt.Errorf("failed to throttle message: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("message with id %d is not being sent", id)
+	}
+
+	return nil
+}
+
+// FindLatestSentTo retrieves the most recent sent message to phoneNumber processed at or after since
+// Returns nil if no such message exists
+func (r *Repository) FindLatestSentTo(ctx context.Context, phoneNumber string, since time.Time) (*Message, error) {
+	query := `
+		SELECT ` + messageColumns + `
+		FROM messages
+		WHERE phone_number = $1
+		  AND status = 'sent'
+		  AND processed_at >= $2
+		ORDER BY processed_at DESC
+		LIMIT 1
+	`
+
+	msg, err := scan.One[Message](r.pool.Query(ctx, query, phoneNumber, since))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find latest sent message: %w", err)
+	}
+
+	return msg, nil
 }
 
 // Create inserts a new message into the database
 // The ID will be populated after successful insertion
 func (r *Repository) Create(ctx context.Context, msg *Message) error {
+	return create(ctx, r.pool, msg)
+}
+
+// CreateWithTx inserts a new message within a transaction
+// The ID will be populated after successful insertion
+func (r *Repository) CreateWithTx(ctx context.Context, tx pgx.Tx, msg *Message) error {
+	return create(ctx, tx, msg)
+}
+
+// create inserts msg through q, shared by Create and CreateWithTx
+// A UUID set on msg is kept, otherwise the database generates one; returns ErrDuplicateUUID if it is taken
+func create(ctx context.Context, q querier, msg *Message) error {
 	query := `
-		INSERT INTO messages (phone_number, content, created_at)
-		VALUES ($1, $2, $3)
-		RETURNING id
+		INSERT INTO messages (phone_number, content, created_at, status, provider, scheduled_at, is_test, transactional, retry_policy, external_ref_type, external_ref_id, metadata, tenant_id, provider_template, uuid, content_locale)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, COALESCE(NULLIF($15, '')::uuid, gen_random_uuid()), $16)
+		RETURNING id, uuid
 	`
 
 	if msg.CreatedAt.IsZero() {
 		msg.CreatedAt = time.Now()
 	}
 
-	err := r.pool.QueryRow(
+	if msg.Status == "" {
+		msg.Status = StatusPending
+	}
+
+	err := q.QueryRow(
 		ctx,
 		query,
 		msg.PhoneNumber,
 		msg.Content,
 		msg.CreatedAt,
-	).Scan(&msg.ID)
+		msg.Status,
+		msg.Provider,
+		msg.ScheduledAt,
+		msg.IsTest,
+		msg.Transactional,
+		msg.RetryPolicy,
+		msg.ExternalRefType,
+		msg.ExternalRefID,
+		msg.Metadata,
+		msg.TenantID,
+		msg.ProviderTemplate,
+		msg.UUID,
+		msg.ContentLocale,
+	).Scan(&msg.ID, &msg.UUID)
 
 	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+			return ErrDuplicateUUID
+		}
 		return fmt.Errorf("failed to create message: %w", err)
 	}
 
 	return nil
 }
 
-// UpdateWithTx modifies an existing message in the database within a transaction
-// Only updates message_id and processed_at fields
-func (r *Repository) UpdateWithTx(ctx context.Context, tx pgx.Tx, id int64, messageID *string, processedAt *time.Time) error {
+// CreateFanout inserts one message per phone number, all sharing the settings of msg and linked by fanoutID
+// The messages are inserted in a single statement and returned in phone number order
+func (r *Repository) CreateFanout(ctx context.Context, msg *Message, fanoutID string, phoneNumbers []string) ([]*Message, error) {
+	return createFanout(ctx, r.pool, msg, fanoutID, phoneNumbers)
+}
+
+// CreateFanoutWithTx inserts the messages of a fan-out within a transaction, see CreateFanout
+func (r *Repository) CreateFanoutWithTx(ctx context.Context, tx pgx.Tx, msg *Message, fanoutID string, phoneNumbers []string) ([]*Message, error) {
+	return createFanout(ctx, tx, msg, fanoutID, phoneNumbers)
+}
+
+// createFanout inserts the messages of a fan-out through q, shared by CreateFanout and CreateFanoutWithTx
+func createFanout(ctx context.Context, q querier, msg *Message, fanoutID string, phoneNumbers []string) ([]*Message, error) {
+	query := `
+		INSERT INTO messages (phone_number, content, created_at, status, provider, scheduled_at, is_test, transactional, fanout_id, retry_policy, external_ref_type, external_ref_id, metadata, tenant_id, provider_template, content_locale)
+		SELECT recipient.phone_number, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16
+		FROM unnest($1::text[]) WITH ORDINALITY AS recipient(phone_number, position)
+		ORDER BY recipient.position
+		RETURNING ` + messageColumns + `
+	`
+
+	if msg.CreatedAt.IsZero() {
+		msg.CreatedAt = time.Now()
+	}
+
+	if msg.Status == "" {
+		msg.Status = StatusPending
+	}
+
+	created, err := scan.All[Message](q.Query(ctx, query, phoneNumbers, msg.Content, msg.CreatedAt, msg.Status, msg.Provider, msg.ScheduledAt, msg.IsTest, msg.Transactional, fanoutID, msg.RetryPolicy, msg.ExternalRefType, msg.ExternalRefID, msg.Metadata, msg.TenantID, msg.ProviderTemplate, msg.ContentLocale))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create fan-out messages: %w", err)
+	}
+
+	sort.Slice(created, func(i, j int) bool { return created[i].ID < created[j].ID })
+
+	return created, nil
+}
+
+// ListByFanout retrieves the messages of a fan-out in creation order
+func (r *Repository) ListByFanout(ctx context.Context, fanoutID string) ([]*Message, error) {
+	query := `
+		SELECT ` + messageColumns + `
+		FROM messages
+		WHERE fanout_id = $1
+		ORDER BY id ASC
+	`
+
+	messages, err := scan.All[Message](r.pool.Query(ctx, query, fanoutID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query fan-out messages: %w", err)
+	}
+
+	return messages, nil
+}
+
+// Upsert inserts a message identified by its UUID or updates the existing one
+// Existing messages are only updated while pending, otherwise ErrNotPending is returned
+// A UUID held by a message of another tenant is never updated, ErrDuplicateUUID is returned instead
+// The message is refreshed from the stored row; created reports whether a new row was inserted
+// The sandbox flag is fixed when the message is inserted
+func (r *Repository) Upsert(ctx context.Context, msg *Message) (created bool, err error) {
+	return upsert(ctx, r.pool, msg)
+}
+
+// UpsertWithTx inserts or updates a message within a transaction, see Upsert
+func (r *Repository) UpsertWithTx(ctx context.Context, tx pgx.Tx, msg *Message) (created bool, err error) {
+	return upsert(ctx, tx, msg)
+}
+
+// upsert inserts or updates msg through q, shared by Upsert and UpsertWithTx
+func upsert(ctx context.Context, q querier, msg *Message) (created bool, err error) {
+	query := `
+		INSERT INTO messages (uuid, phone_number, content, created_at, status, provider, scheduled_at, is_test, transactional, retry_policy, external_ref_type, external_ref_id, metadata, tenant_id, provider_template, content_locale)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+		ON CONFLICT (uuid) DO UPDATE
+		SET phone_number = EXCLUDED.phone_number, content = EXCLUDED.content, status = EXCLUDED.status,
+		    provider = EXCLUDED.provider, scheduled_at = EXCLUDED.scheduled_at,
+		    transactional = EXCLUDED.transactional, retry_policy = EXCLUDED.retry_policy,
+		    external_ref_type = EXCLUDED.external_ref_type, external_ref_id = EXCLUDED.external_ref_id,
+		    metadata = EXCLUDED.metadata, provider_template = EXCLUDED.provider_template,
+		    content_locale = EXCLUDED.content_locale
+		WHERE messages.status = 'pending' AND messages.tenant_id IS NOT DISTINCT FROM EXCLUDED.tenant_id
+		RETURNING ` + messageColumns + `, (xmax = 0) AS inserted
+	`
+
+	if msg.CreatedAt.IsZero() {
+		msg.CreatedAt = time.Now()
+	}
+
+	if msg.Status == "" {
+		msg.Status = StatusPending
+	}
+
+	stored, err := scan.One[upserted](q.Query(ctx, query, msg.UUID, msg.PhoneNumber, msg.Content, msg.CreatedAt, msg.Status, msg.Provider, msg.ScheduledAt, msg.IsTest, msg.Transactional, msg.RetryPolicy, msg.ExternalRefType, msg.ExternalRefID, msg.Metadata, msg.TenantID, msg.ProviderTemplate, msg.ContentLocale))
+	if errors.Is(err, pgx.ErrNoRows) {
+		// The conflicting row exists but is not pending or belongs to another tenant, so the update was skipped
+		return false, upsertConflict(ctx, q, msg)
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to upsert message: %w", err)
+	}
+
+	*msg = stored.Message
+
+	return stored.Inserted, nil
+}
+
+// upsertConflict tells why the upsert of msg skipped the row holding its UUID
+// Returns ErrDuplicateUUID if the row belongs to another tenant and ErrNotPending otherwise
+func upsertConflict(ctx context.Context, q querier, msg *Message) error {
+	var sameTenant bool
+	err := q.QueryRow(ctx, `SELECT tenant_id IS NOT DISTINCT FROM $2 FROM messages WHERE uuid = $1`, msg.UUID, msg.TenantID).Scan(&sameTenant)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("failed to check upsert conflict: %w", err)
+	}
+	if err == nil && !sameTenant {
+		return ErrDuplicateUUID
+	}
+	return ErrNotPending
+}
+
+// ExistsByUUID reports whether a message with the given UUID exists
+func (r *Repository) ExistsByUUID(ctx context.Context, uuid string) (bool, error) {
+	var exists bool
+	err := r.pool.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM messages WHERE uuid = $1)`, uuid).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to look up message uuid: %w", err)
+	}
+
+	return exists, nil
+}
+
+// UpdateWithTx marks an existing message as sent within a transaction
+// Only updates message_id, processed_at and status fields
+// Returns ErrLeaseLost unless the message is still sending under the claim of lockedBy
+func (r *Repository) UpdateWithTx(ctx context.Context, tx pgx.Tx, id int64, lockedBy string, messageID *string, processedAt *time.Time) error {
 	query := `
 		UPDATE messages
-		SET message_id = $1, processed_at = $2
-		WHERE id = $3
+		SET message_id = $1, processed_at = $2, status = 'sent', next_attempt_at = NULL
+		WHERE id = $3 AND status = 'sending' AND locked_by = $4
 	`
 
-	result, err := tx.Exec(ctx, query, messageID, processedAt, id)
+	result, err := tx.Exec(ctx, query, messageID, processedAt, id, lockedBy)
 	if err != nil {
 		return fmt.Errorf("failed to update message: %w", err)
 	}
 
 	if result.RowsAffected() == 0 {
-		return fmt.Errorf("message with id %d not found", id)
+		return fmt.Errorf("message %d: %w", id, ErrLeaseLost)
+	}
+
+	return nil
+}
+
+// MarkFailedWithTx records a failed send attempt within a transaction
+// Stores the resulting status, the new retry count and the earliest time of the next attempt
+// Returns ErrLeaseLost unless the message is still sending under the claim of lockedBy
+func (r *Repository) MarkFailedWithTx(ctx context.Context, tx pgx.Tx, id int64, lockedBy string, status string, retryCount int, nextAttemptAt *time.Time) error {
+	query := `
+		UPDATE messages
+		SET status = $1, retry_count = $2, next_attempt_at = $3
+		WHERE id = $4 AND status = 'sending' AND locked_by = $5
+	`
+
+	result, err := tx.Exec(ctx, query, status, retryCount, nextAttemptAt, id, lockedBy)
+	if err != nil {
+		return fmt.Errorf("failed to mark message as failed: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("message %d: %w", id, ErrLeaseLost)
 	}
 
 	return nil
 }
+
+// Cancel marks a pending message as cancelled and returns the updated row
+// The update only applies while the message is pending; a message locked by a running batch
+// is re-checked once that batch commits
+// A non-nil tenantID only matches a message of that tenant
+// Returns ErrNotFound if the message does not exist and ErrNotPending if it already left pending
+func (r *Repository) Cancel(ctx context.Context, id int64, tenantID *int64) (*Message, error) {
+	query := `
+		UPDATE messages
+		SET status = $1, next_attempt_at = NULL
+		WHERE id = $2 AND status = $3 AND ($4::bigint IS NULL OR tenant_id = $4)
+		RETURNING ` + messageColumns
+
+	msg, err := scan.One[Message](r.pool.Query(ctx, query, StatusCancelled, id, StatusPending, tenantID))
+	if err == nil {
+		return msg, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("failed to cancel message: %w", err)
+	}
+
+	// Nothing was updated, tell a missing message apart from one that already left pending
+	if _, err := r.GetByID(ctx, id, tenantID); err != nil {
+		return nil, err
+	}
+
+	return nil, ErrNotPending
+}
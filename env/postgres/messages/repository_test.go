@@ -0,0 +1,292 @@
+package messages_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"qubit/env/postgres/messages"
+	"qubit/env/postgres/migrations"
+)
+
+// testRepository returns a repository on a schema of its own in TEST_DATABASE_URL with every migration applied
+// The test is skipped when TEST_DATABASE_URL is not set
+func testRepository(t *testing.T) (*messages.Repository, *pgxpool.Pool) {
+	t.Helper()
+
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	ctx := context.Background()
+	schema := fmt.Sprintf("test_%d", time.Now().UnixNano())
+
+	admin, err := pgx.Connect(ctx, url)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	if _, err := admin.Exec(ctx, "CREATE SCHEMA "+schema); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+	t.Cleanup(func() {
+		if _, err := admin.Exec(context.Background(), "DROP SCHEMA "+schema+" CASCADE"); err != nil {
+			t.Errorf("failed to drop schema: %v", err)
+		}
+		admin.Close(context.Background())
+	})
+
+	config, err := pgxpool.ParseConfig(url)
+	if err != nil {
+		t.Fatalf("failed to parse TEST_DATABASE_URL: %v", err)
+	}
+	config.ConnConfig.RuntimeParams["search_path"] = schema
+
+	pool, err := pgxpool.NewWithConfig(ctx, config)
+	if err != nil {
+		t.Fatalf("failed to create pool: %v", err)
+	}
+	t.Cleanup(pool.Close)
+
+	if _, err := migrations.Run(ctx, pool, testInstance, time.Minute); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+
+	return messages.NewRepository(pool), pool
+}
+
+// testInstance is the instance ID claims are made under
+const testInstance = "test-instance"
+
+// testProvider is the provider of messages that name none
+const testProvider = "default"
+
+// createMessage inserts a pending message to phoneNumber
+func createMessage(t *testing.T, repo *messages.Repository, phoneNumber string) *messages.Message {
+	t.Helper()
+
+	msg := &messages.Message{PhoneNumber: phoneNumber, Content: "hello"}
+	if err := repo.Create(context.Background(), msg); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	return msg
+}
+
+// getMessage reads a message back
+func getMessage(t *testing.T, repo *messages.Repository, id int64) *messages.Message {
+	t.Helper()
+
+	msg, err := repo.GetByID(context.Background(), id, nil)
+	if err != nil {
+		t.Fatalf("GetByID(%d) error = %v", id, err)
+	}
+	return msg
+}
+
+func TestClaimUnsentClaimsDueMessagesOnce(t *testing.T) {
+	ctx := context.Background()
+	repo, _ := testRepository(t)
+
+	due := createMessage(t, repo, "+15550000001")
+	later := time.Now().Add(time.Hour)
+	scheduled := &messages.Message{PhoneNumber: "+15550000002", Content: "hello", ScheduledAt: &later}
+	if err := repo.Create(ctx, scheduled); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	claimed, err := repo.ClaimUnsent(ctx, 10, testInstance, time.Minute, testProvider, nil, nil)
+	if err != nil {
+		t.Fatalf("ClaimUnsent() error = %v", err)
+	}
+	if len(claimed) != 1 || claimed[0].ID != due.ID {
+		t.Fatalf("ClaimUnsent() claimed %d messages, want only message %d", len(claimed), due.ID)
+	}
+	if claimed[0].Status != messages.StatusSending || claimed[0].LockedAt == nil {
+		t.Errorf("claimed status = %s, lockedAt = %v, want sending and locked", claimed[0].Status, claimed[0].LockedAt)
+	}
+	if claimed[0].LockedBy == nil || *claimed[0].LockedBy != testInstance || claimed[0].LeaseExpiresAt == nil {
+		t.Errorf("claimed lockedBy = %v, leaseExpiresAt = %v, want a lease held by %s", claimed[0].LockedBy, claimed[0].LeaseExpiresAt, testInstance)
+	}
+
+	// A claimed message is not claimed again, a scheduled one waits for its time
+	claimed, err = repo.ClaimUnsent(ctx, 10, testInstance, time.Minute, testProvider, nil, nil)
+	if err != nil {
+		t.Fatalf("ClaimUnsent() error = %v", err)
+	}
+	if len(claimed) != 0 {
+		t.Errorf("second ClaimUnsent() claimed %d messages, want 0", len(claimed))
+	}
+	if got := getMessage(t, repo, scheduled.ID); got.Status != messages.StatusPending {
+		t.Errorf("scheduled message status = %s, want %s", got.Status, messages.StatusPending)
+	}
+}
+
+func TestClaimUnsentSkipsPausedProviders(t *testing.T) {
+	ctx := context.Background()
+	repo, _ := testRepository(t)
+
+	unnamed := createMessage(t, repo, "+15550000001")
+	backup := "backup"
+	routed := &messages.Message{PhoneNumber: "+15550000002", Content: "hello", Provider: &backup}
+	if err := repo.Create(ctx, routed); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	// Messages without a provider go through the default one, so they wait while it is paused
+	claimed, err := repo.ClaimUnsent(ctx, 10, testInstance, time.Minute, testProvider, []string{testProvider}, nil)
+	if err != nil {
+		t.Fatalf("ClaimUnsent() error = %v", err)
+	}
+	if len(claimed) != 1 || claimed[0].ID != routed.ID {
+		t.Fatalf("ClaimUnsent() claimed %d messages, want only message %d", len(claimed), routed.ID)
+	}
+	if got := getMessage(t, repo, unnamed.ID); got.Status != messages.StatusPending {
+		t.Errorf("message of the paused provider status = %s, want %s", got.Status, messages.StatusPending)
+	}
+}
+
+func TestClaimUnsentClaimsOnlyAssignedShards(t *testing.T) {
+	ctx := context.Background()
+	repo, pool := testRepository(t)
+
+	assigned := createMessage(t, repo, "+15550000001")
+	other := createMessage(t, repo, "+15550000002")
+	var shardKey, otherKey int
+	if err := pool.QueryRow(ctx, `SELECT shard_key FROM messages WHERE id = $1`, assigned.ID).Scan(&shardKey); err != nil {
+		t.Fatalf("failed to read the shard key: %v", err)
+	}
+	if err := pool.QueryRow(ctx, `SELECT shard_key FROM messages WHERE id = $1`, other.ID).Scan(&otherKey); err != nil {
+		t.Fatalf("failed to read the shard key: %v", err)
+	}
+	if shardKey == otherKey {
+		t.Skip("both phone numbers hash to the same shard")
+	}
+
+	claimed, err := repo.ClaimUnsent(ctx, 10, testInstance, time.Minute, testProvider, nil, []int{shardKey})
+	if err != nil {
+		t.Fatalf("ClaimUnsent() error = %v", err)
+	}
+	if len(claimed) != 1 || claimed[0].ID != assigned.ID {
+		t.Fatalf("ClaimUnsent() claimed %d messages, want only message %d", len(claimed), assigned.ID)
+	}
+	if got := getMessage(t, repo, other.ID); got.Status != messages.StatusPending {
+		t.Errorf("message of another shard status = %s, want %s", got.Status, messages.StatusPending)
+	}
+}
+
+func TestReleaseReturnsClaimsToPending(t *testing.T) {
+	ctx := context.Background()
+	repo, _ := testRepository(t)
+	msg := createMessage(t, repo, "+15550000001")
+
+	if _, err := repo.ClaimUnsent(ctx, 10, testInstance, time.Minute, testProvider, nil, nil); err != nil {
+		t.Fatalf("ClaimUnsent() error = %v", err)
+	}
+	if err := repo.Release(ctx, []int64{msg.ID}); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+
+	got := getMessage(t, repo, msg.ID)
+	if got.Status != messages.StatusPending || got.LockedBy != nil || got.LeaseExpiresAt != nil {
+		t.Errorf("after release status = %s, lockedBy = %v, want pending and unleased", got.Status, got.LockedBy)
+	}
+}
+
+func TestReapStuckReturnsExpiredLeasesToPending(t *testing.T) {
+	ctx := context.Background()
+	repo, pool := testRepository(t)
+	expired := createMessage(t, repo, "+15550000001")
+	leased := createMessage(t, repo, "+15550000002")
+
+	if _, err := repo.ClaimUnsent(ctx, 10, testInstance, time.Minute, testProvider, nil, nil); err != nil {
+		t.Fatalf("ClaimUnsent() error = %v", err)
+	}
+	if _, err := pool.Exec(ctx, `UPDATE messages SET lease_expires_at = NOW() - INTERVAL '1 second' WHERE id = $1`, expired.ID); err != nil {
+		t.Fatalf("failed to expire the lease: %v", err)
+	}
+
+	reaped, err := repo.ReapStuck(ctx)
+	if err != nil {
+		t.Fatalf("ReapStuck() error = %v", err)
+	}
+	if reaped != 1 {
+		t.Errorf("ReapStuck() = %d, want 1", reaped)
+	}
+
+	if got := getMessage(t, repo, expired.ID); got.Status != messages.StatusPending || got.LockedBy != nil {
+		t.Errorf("expired claim status = %s, lockedBy = %v, want pending and unleased", got.Status, got.LockedBy)
+	}
+	if got := getMessage(t, repo, leased.ID); got.Status != messages.StatusSending {
+		t.Errorf("leased claim status = %s, want %s", got.Status, messages.StatusSending)
+	}
+}
+
+func TestUpsertKeepsMessagesOfAnotherTenant(t *testing.T) {
+	ctx := context.Background()
+	repo, _ := testRepository(t)
+
+	const uuid = "4b0e7c1e-6a53-4d6e-9a3e-1f2a7c9d8e01"
+	tenantA, tenantB := int64(1), int64(2)
+	owned := &messages.Message{UUID: uuid, PhoneNumber: "+15550000001", Content: "hello", TenantID: &tenantA}
+	if created, err := repo.Upsert(ctx, owned); err != nil || !created {
+		t.Fatalf("Upsert() created = %v, error = %v, want an insert", created, err)
+	}
+
+	// Neither another tenant nor an operator key may overwrite the message through its UUID
+	for _, tenantID := range []*int64{&tenantB, nil} {
+		foreign := &messages.Message{UUID: uuid, PhoneNumber: "+15550000002", Content: "hijacked", TenantID: tenantID}
+		if _, err := repo.Upsert(ctx, foreign); !errors.Is(err, messages.ErrDuplicateUUID) {
+			t.Errorf("Upsert() by tenant %v error = %v, want %v", tenantID, err, messages.ErrDuplicateUUID)
+		}
+	}
+	if got := getMessage(t, repo, owned.ID); got.Content != "hello" || got.PhoneNumber != "+15550000001" {
+		t.Errorf("after foreign upserts content = %q, phoneNumber = %s, want the original message", got.Content, got.PhoneNumber)
+	}
+
+	// The owning tenant still re-syncs it
+	owned.Content = "updated"
+	if created, err := repo.Upsert(ctx, owned); err != nil || created {
+		t.Fatalf("Upsert() by the owner created = %v, error = %v, want an update", created, err)
+	}
+	if got := getMessage(t, repo, owned.ID); got.Content != "updated" {
+		t.Errorf("content = %q, want updated", got.Content)
+	}
+}
+
+func TestCountTenantUsageCountsTheUTCDayAndLastMinute(t *testing.T) {
+	ctx := context.Background()
+	repo, pool := testRepository(t)
+
+	tenantID := int64(1)
+	var ids []int64
+	for _, phoneNumber := range []string{"+15550000001", "+15550000002", "+15550000003"} {
+		msg := &messages.Message{PhoneNumber: phoneNumber, Content: "hello", TenantID: &tenantID}
+		if err := repo.Create(ctx, msg); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		ids = append(ids, msg.ID)
+	}
+	createMessage(t, repo, "+15550000004")
+
+	// One message is older than a minute but from today, one from the previous UTC day
+	if _, err := pool.Exec(ctx, `UPDATE messages SET created_at = GREATEST(date_trunc('day', NOW() AT TIME ZONE 'UTC'), (NOW() AT TIME ZONE 'UTC') - INTERVAL '2 minutes') WHERE id = $1`, ids[1]); err != nil {
+		t.Fatalf("failed to age the message: %v", err)
+	}
+	if _, err := pool.Exec(ctx, `UPDATE messages SET created_at = date_trunc('day', NOW() AT TIME ZONE 'UTC') - INTERVAL '1 second' WHERE id = $1`, ids[2]); err != nil {
+		t.Fatalf("failed to age the message: %v", err)
+	}
+
+	usage, err := repo.CountTenantUsage(ctx, tenantID)
+	if err != nil {
+		t.Fatalf("CountTenantUsage() error = %v", err)
+	}
+	if usage.Today != 2 || usage.LastMinute < 1 {
+		t.Errorf("CountTenantUsage() = %+v, want 2 today and the new message within the last minute", usage)
+	}
+}
@@ -0,0 +1,138 @@
+package messages
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Stats is an aggregate snapshot of the messages table
+type Stats struct {
+	// ByStatus counts the messages per status, statuses without messages are missing
+	ByStatus map[string]int64
+
+	SentLastHour int64
+	SentLastDay  int64
+
+	// Backlog counts the pending messages that are due now
+	Backlog int64
+	// OldestDue is when the oldest due pending message became due, nil without backlog
+	OldestDue *time.Time
+}
+
+// Stats aggregates message counts per status, recent sends and the backlog of due messages
+// Test messages are skipped unless includeTest is set
+func (r *Repository) Stats(ctx context.Context, includeTest bool) (*Stats, error) {
+	stats := &Stats{}
+
+	var err error
+	stats.ByStatus, err = r.statusCounts(ctx, includeTest)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM messages
+			 WHERE status = 'sent' AND processed_at >= NOW() - INTERVAL '1 hour' AND ($1 OR NOT is_test)),
+			(SELECT COUNT(*) FROM messages
+			 WHERE status = 'sent' AND processed_at >= NOW() - INTERVAL '1 day' AND ($1 OR NOT is_test)),
+			due.count,
+			due.oldest
+		FROM (
+			SELECT COUNT(*) AS count, MIN(GREATEST(created_at, scheduled_at, next_attempt_at)) AS oldest
+			FROM messages
+			WHERE status = 'pending'
+			  AND (next_attempt_at IS NULL OR next_attempt_at <= NOW())
+			  AND (scheduled_at IS NULL OR scheduled_at <= NOW())
+			  AND ($1 OR NOT is_test)
+		) due
+	`
+
+	err = r.pool.QueryRow(ctx, query, includeTest).Scan(
+		&stats.SentLastHour,
+		&stats.SentLastDay,
+		&stats.Backlog,
+		&stats.OldestDue,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate message stats: %w", err)
+	}
+
+	return stats, nil
+}
+
+// CountDue counts the pending messages that are due now, test messages included
+func (r *Repository) CountDue(ctx context.Context) (int64, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM messages
+		WHERE status = 'pending'
+		  AND (next_attempt_at IS NULL OR next_attempt_at <= NOW())
+		  AND (scheduled_at IS NULL OR scheduled_at <= NOW())
+	`
+
+	var count int64
+	if err := r.pool.QueryRow(ctx, query).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count due messages: %w", err)
+	}
+
+	return count, nil
+}
+
+// Backlog is the pending messages that are due now
+type Backlog struct {
+	Count int64
+	// OldestDue is when the oldest of them became due, nil without backlog
+	OldestDue *time.Time
+}
+
+// Backlog counts the pending messages that are due now and finds the oldest, test messages included
+func (r *Repository) Backlog(ctx context.Context) (*Backlog, error) {
+	query := `
+		SELECT COUNT(*), MIN(GREATEST(created_at, scheduled_at, next_attempt_at))
+		FROM messages
+		WHERE status = 'pending'
+		  AND (next_attempt_at IS NULL OR next_attempt_at <= NOW())
+		  AND (scheduled_at IS NULL OR scheduled_at <= NOW())
+	`
+
+	backlog := &Backlog{}
+	if err := r.pool.QueryRow(ctx, query).Scan(&backlog.Count, &backlog.OldestDue); err != nil {
+		return nil, fmt.Errorf("failed to measure the backlog: %w", err)
+	}
+
+	return backlog, nil
+}
+
+// statusCounts counts the messages per status
+func (r *Repository) statusCounts(ctx context.Context, includeTest bool) (map[string]int64, error) {
+	query := `
+		SELECT status, COUNT(*)
+		FROM messages
+		WHERE $1 OR NOT is_test
+		GROUP BY status
+	`
+
+	rows, err := r.pool.Query(ctx, query, includeTest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count messages by status: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int64)
+	for rows.Next() {
+		var status string
+		var count int64
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan message counts: %w", err)
+		}
+		counts[status] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating message counts: %w", err)
+	}
+
+	return counts, nil
+}
@@ -0,0 +1,6 @@
+-- Add retry accounting columns
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS retry_count INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS next_attempt_at TIMESTAMP;
+
+-- Create index on next_attempt_at for skipping messages still in backoff
+CREATE INDEX IF NOT EXISTS idx_messages_next_attempt_at ON messages(next_attempt_at) WHERE processed_at IS NULL;
@@ -0,0 +1,15 @@
+-- Create inbound messages table for replies received from recipients
+CREATE TABLE IF NOT EXISTS inbound_messages (
+    id SERIAL PRIMARY KEY,
+    phone_number VARCHAR(20) NOT NULL,
+    content VARCHAR(500) NOT NULL,
+    received_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    reply_to_id INTEGER REFERENCES messages(id)
+);
+
+-- Create index on received_at for efficient ordering
+CREATE INDEX IF NOT EXISTS idx_inbound_messages_received_at ON inbound_messages(received_at);
+
+-- Create index for finding the latest outbound message sent to a number
+CREATE INDEX IF NOT EXISTS idx_messages_phone_number_processed_at ON messages(phone_number, processed_at DESC) WHERE processed_at IS NOT NULL;
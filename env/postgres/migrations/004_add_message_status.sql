@@ -0,0 +1,11 @@
+-- Add explicit message status column
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'pending';
+
+-- Backfill status for messages that were already sent
+UPDATE messages SET status = 'sent' WHERE processed_at IS NOT NULL AND status = 'pending';
+
+-- Backfill status for messages that exhausted their retries
+UPDATE messages SET status = 'failed' WHERE processed_at IS NULL AND retry_count > 0 AND next_attempt_at IS NULL AND status = 'pending';
+
+-- Create index on status for status-based queries
+CREATE INDEX IF NOT EXISTS idx_messages_status ON messages(status);
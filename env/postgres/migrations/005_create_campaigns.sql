@@ -0,0 +1,24 @@
+-- Create campaigns table for bulk sends that require approval
+CREATE TABLE IF NOT EXISTS campaigns (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(200) NOT NULL,
+    content VARCHAR(500) NOT NULL,
+    recipients TEXT[] NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'draft',
+    created_by TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    reviewed_by TEXT,
+    review_comment TEXT,
+    reviewed_at TIMESTAMP,
+
+    scheduled_at TIMESTAMP,
+    completed_at TIMESTAMP
+);
+
+-- Create index for finding campaigns that are due to run
+CREATE INDEX IF NOT EXISTS idx_campaigns_scheduled ON campaigns(scheduled_at) WHERE status = 'scheduled';
+
+-- Link messages to the campaign that created them
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS campaign_id INTEGER REFERENCES campaigns(id);
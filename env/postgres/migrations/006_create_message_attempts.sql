@@ -0,0 +1,20 @@
+-- Create message attempts table with per-attempt latency breakdown
+CREATE TABLE IF NOT EXISTS message_attempts (
+    id SERIAL PRIMARY KEY,
+    message_id INTEGER NOT NULL REFERENCES messages(id),
+    attempt_number INTEGER NOT NULL,
+    started_at TIMESTAMP NOT NULL,
+    success BOOLEAN NOT NULL,
+    error TEXT,
+
+    queue_wait_ms BIGINT NOT NULL,
+    lock_to_send_ms BIGINT NOT NULL,
+    webhook_ms BIGINT NOT NULL,
+    db_update_ms BIGINT NOT NULL
+);
+
+-- Create index for listing the attempts of a message
+CREATE INDEX IF NOT EXISTS idx_message_attempts_message_id ON message_attempts(message_id);
+
+-- Create index for time-bounded latency statistics
+CREATE INDEX IF NOT EXISTS idx_message_attempts_started_at ON message_attempts(started_at);
@@ -0,0 +1,5 @@
+-- Add public UUID used by external systems to reference messages
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS uuid UUID NOT NULL DEFAULT gen_random_uuid();
+
+-- Create unique index on uuid for idempotent upserts
+CREATE UNIQUE INDEX IF NOT EXISTS idx_messages_uuid ON messages(uuid);
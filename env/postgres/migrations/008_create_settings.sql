@@ -0,0 +1,6 @@
+-- Create settings table for runtime overrides persisted across restarts
+CREATE TABLE IF NOT EXISTS settings (
+    key TEXT PRIMARY KEY,
+    value JSONB NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
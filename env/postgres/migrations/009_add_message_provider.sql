@@ -0,0 +1,2 @@
+-- Add optional provider pin, NULL means the default provider is used
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS provider VARCHAR(100);
@@ -0,0 +1,5 @@
+-- Add optional earliest send time, NULL means the message is due immediately
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS scheduled_at TIMESTAMP;
+
+-- Create index for skipping messages that are not due yet
+CREATE INDEX IF NOT EXISTS idx_messages_scheduled_at ON messages(scheduled_at) WHERE status = 'pending';
@@ -0,0 +1,3 @@
+-- Add sanitized raw provider exchange of failed attempts for support tickets
+ALTER TABLE message_attempts ADD COLUMN IF NOT EXISTS request_payload TEXT;
+ALTER TABLE message_attempts ADD COLUMN IF NOT EXISTS response_body TEXT;
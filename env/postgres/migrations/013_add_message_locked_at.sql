@@ -0,0 +1,5 @@
+-- Add the time a message was last claimed for sending
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS locked_at TIMESTAMP;
+
+-- Create index for reaping messages stuck in sending
+CREATE INDEX IF NOT EXISTS idx_messages_locked_at ON messages(locked_at) WHERE status = 'sending';
@@ -0,0 +1,11 @@
+-- Add the instance holding a claimed message and when its lease expires
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS locked_by VARCHAR(255);
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS lease_expires_at TIMESTAMP;
+
+-- Give messages claimed before leases existed the default lease
+UPDATE messages
+SET lease_expires_at = COALESCE(locked_at, NOW()) + INTERVAL '10 minutes'
+WHERE status = 'sending' AND lease_expires_at IS NULL;
+
+-- Create index for reaping messages whose lease expired
+CREATE INDEX IF NOT EXISTS idx_messages_lease_expires_at ON messages(lease_expires_at) WHERE status = 'sending';
@@ -0,0 +1,3 @@
+-- Mark API keys issued for sandbox integration and the messages created with them
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS is_test BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS is_test BOOLEAN NOT NULL DEFAULT FALSE;
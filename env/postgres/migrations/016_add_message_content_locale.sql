@@ -0,0 +1,2 @@
+-- Record which localized content variant a message was created with
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS content_locale VARCHAR(35);
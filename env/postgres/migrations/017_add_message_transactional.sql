@@ -0,0 +1,2 @@
+-- Mark transactional messages, which are exempt from the per-recipient limit
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS transactional BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,12 @@
+-- Create message templates table, content holds {{placeholders}} filled in at message creation
+-- translations hold the content per locale, e.g. {"tr": "...", "en": "..."}, content remains the default variant
+CREATE TABLE IF NOT EXISTS templates (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    content TEXT NOT NULL,
+    translations JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create unique index on name, templates are looked up by operators by name
+CREATE UNIQUE INDEX IF NOT EXISTS idx_templates_name ON templates(name);
@@ -0,0 +1,5 @@
+-- Link the messages expanded from a single multi-recipient request
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS fanout_id UUID;
+
+-- Create index for the combined status of a fan-out
+CREATE INDEX IF NOT EXISTS idx_messages_fanout_id ON messages(fanout_id) WHERE fanout_id IS NOT NULL;
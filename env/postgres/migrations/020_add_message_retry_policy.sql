@@ -0,0 +1,2 @@
+-- Store the retry policy a message was created with, NULL uses the configured policy
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS retry_policy JSONB;
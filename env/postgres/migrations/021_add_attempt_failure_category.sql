@@ -0,0 +1,2 @@
+-- Classify failed attempts by transport failure (dns, tls, connect_timeout, http_5xx, ...)
+ALTER TABLE message_attempts ADD COLUMN IF NOT EXISTS failure_category VARCHAR(50);
@@ -0,0 +1,16 @@
+-- Create tenants table, settings holds free-form tenant settings and the quotas are set at onboarding
+CREATE TABLE IF NOT EXISTS tenants (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    settings JSONB NOT NULL DEFAULT '{}',
+    daily_message_quota INTEGER NOT NULL,
+    rate_limit_per_minute INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create unique index on name, a tenant is onboarded once
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tenants_name ON tenants(name);
+
+-- Link API keys to the tenant they were issued for, operator keys have no tenant
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS tenant_id INTEGER REFERENCES tenants(id);
+CREATE INDEX IF NOT EXISTS idx_api_keys_tenant_id ON api_keys(tenant_id);
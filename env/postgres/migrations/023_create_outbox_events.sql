@@ -0,0 +1,18 @@
+-- Create transactional outbox, events are inserted with the message change and relayed to the event broker
+CREATE TABLE IF NOT EXISTS outbox_events (
+    id BIGSERIAL PRIMARY KEY,
+    event_id UUID NOT NULL DEFAULT gen_random_uuid(),
+    event_type VARCHAR(50) NOT NULL,
+    message_id INTEGER NOT NULL,
+    payload JSONB NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    published_at TIMESTAMP,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT
+);
+
+-- Create partial index on unpublished events, the relay reads them in id order
+CREATE INDEX IF NOT EXISTS idx_outbox_events_unpublished ON outbox_events(id) WHERE published_at IS NULL;
+
+-- Create index on published_at for pruning relayed events
+CREATE INDEX IF NOT EXISTS idx_outbox_events_published_at ON outbox_events(published_at);
@@ -0,0 +1,13 @@
+-- Notify listeners whenever a cached table changes, the payload is the table name
+CREATE OR REPLACE FUNCTION notify_cache_change() RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_notify('qubit_cache', TG_TABLE_NAME);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Tenant settings are served from the in-memory cache
+DROP TRIGGER IF EXISTS trg_tenants_notify_cache_change ON tenants;
+CREATE TRIGGER trg_tenants_notify_cache_change
+    AFTER INSERT OR UPDATE OR DELETE ON tenants
+    FOR EACH STATEMENT EXECUTE FUNCTION notify_cache_change();
@@ -0,0 +1,16 @@
+-- Create audit trail of requests made by admin keys acting as a tenant
+-- actor_key_id is NULL for the bootstrap admin key, status is set once the request completed
+CREATE TABLE IF NOT EXISTS impersonations (
+    id SERIAL PRIMARY KEY,
+    tenant_id INTEGER NOT NULL REFERENCES tenants(id),
+    actor_key_id INTEGER REFERENCES api_keys(id),
+    actor_name VARCHAR(100) NOT NULL,
+    method VARCHAR(10) NOT NULL,
+    path TEXT NOT NULL,
+    client_ip VARCHAR(64) NOT NULL,
+    status INTEGER,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create index on tenant_id and created_at for listing the impersonations of a tenant
+CREATE INDEX IF NOT EXISTS idx_impersonations_tenant_id_created_at ON impersonations(tenant_id, created_at);
@@ -0,0 +1,5 @@
+-- Reference to the business object a message was sent for, e.g. order:12345
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS external_ref_type VARCHAR(50);
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS external_ref_id VARCHAR(255);
+
+CREATE INDEX IF NOT EXISTS idx_messages_external_ref ON messages(external_ref_type, external_ref_id) WHERE external_ref_type IS NOT NULL;
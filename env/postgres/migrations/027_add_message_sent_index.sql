@@ -0,0 +1,2 @@
+-- Supports counting recent sends for the admin statistics
+CREATE INDEX IF NOT EXISTS idx_messages_sent_processed_at ON messages(processed_at) WHERE status = 'sent';
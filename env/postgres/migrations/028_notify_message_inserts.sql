@@ -0,0 +1,13 @@
+-- Notify listeners whenever messages are inserted, so an idle scheduler polls at its base interval again
+CREATE OR REPLACE FUNCTION notify_message_insert() RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_notify('qubit_messages', '');
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+-- One notification per statement, a fan-out or campaign launch inserting many rows notifies once
+DROP TRIGGER IF EXISTS trg_messages_notify_insert ON messages;
+CREATE TRIGGER trg_messages_notify_insert
+    AFTER INSERT ON messages
+    FOR EACH STATEMENT EXECUTE FUNCTION notify_message_insert();
@@ -0,0 +1,17 @@
+-- Create the archive of old sent messages, with the columns of messages in the same order
+-- Columns added to messages later must be added here too, archiving copies rows by position
+CREATE TABLE IF NOT EXISTS messages_archive (
+    LIKE messages,
+    archived_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (id)
+);
+
+-- Create index on created_at for listings including archived messages
+CREATE INDEX IF NOT EXISTS idx_messages_archive_created_at ON messages_archive(created_at);
+
+-- Create index on external_ref for lookups of archived messages by reference
+CREATE INDEX IF NOT EXISTS idx_messages_archive_external_ref ON messages_archive(external_ref_type, external_ref_id) WHERE external_ref_type IS NOT NULL;
+
+-- Attempts and replies outlive the archived message they belong to, which keeps its id in messages_archive
+ALTER TABLE message_attempts DROP CONSTRAINT IF EXISTS message_attempts_message_id_fkey;
+ALTER TABLE inbound_messages DROP CONSTRAINT IF EXISTS inbound_messages_reply_to_id_fkey;
@@ -0,0 +1,17 @@
+-- Create callback signing keys of tenants, secret holds the HMAC secret or the Ed25519 private key seed
+-- The active key has no expires_at, a rotated key keeps signing next to its successor until it expires
+CREATE TABLE IF NOT EXISTS tenant_signing_keys (
+    id SERIAL PRIMARY KEY,
+    tenant_id INTEGER NOT NULL REFERENCES tenants(id),
+    algorithm VARCHAR(20) NOT NULL,
+    secret BYTEA NOT NULL,
+    public_key BYTEA,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP
+);
+
+-- Create unique index on tenant_id of active keys, a tenant has at most one
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tenant_signing_keys_active ON tenant_signing_keys(tenant_id) WHERE expires_at IS NULL;
+
+-- Create index on tenant_id and expires_at for loading the valid keys of a tenant
+CREATE INDEX IF NOT EXISTS idx_tenant_signing_keys_tenant_id_expires_at ON tenant_signing_keys(tenant_id, expires_at);
@@ -0,0 +1,18 @@
+-- Create store of API requests rejected as invalid, kept for a short window to reproduce and replay them
+-- Credentials are redacted before storing, api_key_id and tenant_id identify the caller
+CREATE TABLE IF NOT EXISTS rejected_requests (
+    id SERIAL PRIMARY KEY,
+    method VARCHAR(10) NOT NULL,
+    path TEXT NOT NULL,
+    headers JSONB NOT NULL DEFAULT '{}',
+    body TEXT NOT NULL,
+    body_truncated BOOLEAN NOT NULL DEFAULT FALSE,
+    status INTEGER NOT NULL,
+    error TEXT NOT NULL,
+    api_key_id INTEGER,
+    tenant_id INTEGER,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create index on created_at for listing and pruning
+CREATE INDEX IF NOT EXISTS idx_rejected_requests_created_at ON rejected_requests(created_at);
@@ -0,0 +1,21 @@
+-- Create report of every scheduler run, for auditing past processing without the logs
+-- errors counts the failed attempts per failure category, error is set when the run ended early
+CREATE TABLE IF NOT EXISTS dispatch_runs (
+    id BIGSERIAL PRIMARY KEY,
+    instance_id VARCHAR(255) NOT NULL,
+    started_at TIMESTAMP NOT NULL,
+    finished_at TIMESTAMP NOT NULL,
+    claimed INTEGER NOT NULL DEFAULT 0,
+    attempted INTEGER NOT NULL DEFAULT 0,
+    sent INTEGER NOT NULL DEFAULT 0,
+    retried INTEGER NOT NULL DEFAULT 0,
+    failed INTEGER NOT NULL DEFAULT 0,
+    throttled INTEGER NOT NULL DEFAULT 0,
+    deferred INTEGER NOT NULL DEFAULT 0,
+    quarantined INTEGER NOT NULL DEFAULT 0,
+    errors JSONB NOT NULL DEFAULT '{}',
+    error TEXT
+);
+
+-- Create index on started_at for pruning old runs
+CREATE INDEX IF NOT EXISTS idx_dispatch_runs_started_at ON dispatch_runs(started_at);
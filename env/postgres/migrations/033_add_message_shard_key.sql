@@ -0,0 +1,14 @@
+-- Add shard key of messages, derived from the phone number so all messages to a number share a shard
+-- Instances assigned disjoint shard ranges claim disjoint slices of the queue
+-- Not added to messages_archive, archiving lists the columns it copies instead of copying by position
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS shard_key INTEGER GENERATED ALWAYS AS (hashtext(phone_number) & 1023) STORED;
+
+-- Create index on shard_key for claiming the pending messages of a shard range
+CREATE INDEX IF NOT EXISTS idx_messages_shard_key ON messages(shard_key, created_at) WHERE status = 'pending';
+
+-- Create registry of the instances sharing the queue, for splitting the shards between them
+-- heartbeat_at is refreshed by every live instance, rows not refreshed within the TTL are pruned
+CREATE TABLE IF NOT EXISTS scheduler_members (
+    instance_id VARCHAR(255) PRIMARY KEY,
+    heartbeat_at TIMESTAMP NOT NULL
+);
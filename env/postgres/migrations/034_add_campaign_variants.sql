@@ -0,0 +1,14 @@
+-- Add A/B content variants of campaigns, each with its share of the recipients in percent
+-- An empty list sends the campaign content to every recipient
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS variants JSONB NOT NULL DEFAULT '[]';
+
+-- Record the variant a campaign message was created from, NULL outside A/B campaigns
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS variant VARCHAR(50);
+ALTER TABLE messages_archive ADD COLUMN IF NOT EXISTS variant VARCHAR(50);
+
+-- Create index on campaign_id for the stats of a campaign
+CREATE INDEX IF NOT EXISTS idx_messages_campaign_id ON messages(campaign_id) WHERE campaign_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_messages_archive_campaign_id ON messages_archive(campaign_id) WHERE campaign_id IS NOT NULL;
+
+-- Create index on reply_to_id for counting the replies to campaign messages
+CREATE INDEX IF NOT EXISTS idx_inbound_messages_reply_to_id ON inbound_messages(reply_to_id) WHERE reply_to_id IS NOT NULL;
@@ -0,0 +1,7 @@
+-- Caller-defined fields of a message, e.g. {"campaignId": "spring", "userId": "42"}
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS metadata JSONB;
+ALTER TABLE messages_archive ADD COLUMN IF NOT EXISTS metadata JSONB;
+
+-- Create GIN index for containment filters on metadata
+CREATE INDEX IF NOT EXISTS idx_messages_metadata ON messages USING GIN (metadata jsonb_path_ops);
+CREATE INDEX IF NOT EXISTS idx_messages_archive_metadata ON messages_archive USING GIN (metadata jsonb_path_ops);
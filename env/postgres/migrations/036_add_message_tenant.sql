@@ -0,0 +1,6 @@
+-- Tenant whose API key created a message, NULL for operator keys, campaigns and queue ingestion
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS tenant_id BIGINT;
+ALTER TABLE messages_archive ADD COLUMN IF NOT EXISTS tenant_id BIGINT;
+
+-- Create partial index for the per-tenant create and send rates of the anomaly detector
+CREATE INDEX IF NOT EXISTS idx_messages_tenant_created_at ON messages(tenant_id, created_at) WHERE tenant_id IS NOT NULL;
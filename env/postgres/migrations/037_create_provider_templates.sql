@@ -0,0 +1,17 @@
+-- Create the templates of template-only providers, e.g. WhatsApp, with the approval status last synced from the provider
+-- A template is identified by its provider, name and language; templates removed at the provider are deleted on sync
+CREATE TABLE IF NOT EXISTS provider_templates (
+    provider VARCHAR(100) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    language VARCHAR(20) NOT NULL,
+    external_id TEXT,
+    body TEXT NOT NULL,
+    parameters INTEGER NOT NULL DEFAULT 0,
+    status VARCHAR(20) NOT NULL,
+    synced_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (provider, name, language)
+);
+
+-- Provider template a message is sent as, e.g. {"name": "order_shipped", "language": "en", "parameters": ["Ada", "#123"]}
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS provider_template JSONB;
+ALTER TABLE messages_archive ADD COLUMN IF NOT EXISTS provider_template JSONB;
@@ -0,0 +1,10 @@
+-- Archived message a re-driven message was copied from, NULL for every other message
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS redriven_from INTEGER;
+ALTER TABLE messages_archive ADD COLUMN IF NOT EXISTS redriven_from INTEGER;
+
+-- Create unique index so an archived message is re-driven at most once, even by concurrent runs
+CREATE UNIQUE INDEX IF NOT EXISTS idx_messages_redriven_from ON messages(redriven_from) WHERE redriven_from IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_messages_archive_redriven_from ON messages_archive(redriven_from) WHERE redriven_from IS NOT NULL;
+
+-- Create index on processed_at for selecting the archived messages of a period
+CREATE INDEX IF NOT EXISTS idx_messages_archive_processed_at ON messages_archive(processed_at);
@@ -0,0 +1,2 @@
+-- Default metadata merged into every message created by the tenant, fields given with a message take precedence
+ALTER TABLE tenants ADD COLUMN IF NOT EXISTS default_metadata JSONB NOT NULL DEFAULT '{}';
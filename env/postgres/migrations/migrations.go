@@ -0,0 +1,242 @@
+// Package migrations embeds the SQL schema migrations and applies them in order
+package migrations
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+//go:embed *.sql
+var files embed.FS
+
+// Migration is a single embedded SQL file, identified by its file name without extension
+type Migration struct {
+	Version   string
+	AppliedAt *time.Time
+}
+
+// createVersionsTable records which migrations were applied
+const createVersionsTable = `
+	CREATE TABLE IF NOT EXISTS schema_migrations (
+		version TEXT PRIMARY KEY,
+		applied_at TIMESTAMP NOT NULL DEFAULT NOW()
+	)
+`
+
+// LockKey is the advisory lock key held while migrating, next to the keys of package postgres
+const LockKey int64 = 0x7175626974010003
+
+// lockPollInterval is how often a waiting migrator retries the lock
+const lockPollInterval = 2 * time.Second
+
+// lockLogInterval is how often a waiting migrator logs who holds the lock
+const lockLogInterval = 30 * time.Second
+
+// Run applies every embedded migration that was not applied yet, each in its own transaction
+// Replicas starting together take turns: the migrator holds LockKey on a dedicated connection,
+// the others wait up to lockTimeout and then find the migrations applied; owner names this migrator to them
+// Returns the versions applied by this call
+func Run(ctx context.Context, pool *pgxpool.Pool, owner string, lockTimeout time.Duration) ([]string, error) {
+	conn, err := lock(ctx, pool, owner, lockTimeout)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock(conn)
+
+	status, err := Status(ctx, pool)
+	if err != nil {
+		return nil, err
+	}
+
+	var applied []string
+	for _, m := range status {
+		if m.AppliedAt != nil {
+			continue
+		}
+
+		if err := apply(ctx, pool, m.Version); err != nil {
+			return applied, err
+		}
+		log.Printf("✓ Applied migration %s", m.Version)
+		applied = append(applied, m.Version)
+	}
+
+	return applied, nil
+}
+
+// lock takes the migration lock on a dedicated connection, waiting up to timeout while another session holds it
+// The connection is named after owner so waiting migrators can log who is migrating
+func lock(ctx context.Context, pool *pgxpool.Pool, owner string, timeout time.Duration) (*pgxpool.Conn, error) {
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire migration lock connection: %w", err)
+	}
+
+	if _, err := conn.Exec(ctx, `SELECT set_config('application_name', $1, false)`, "qubit migrate "+owner); err != nil {
+		conn.Release()
+		return nil, fmt.Errorf("failed to name migration lock connection: %w", err)
+	}
+
+	deadline := time.Now().Add(timeout)
+	var lastLog time.Time
+	for {
+		var acquired bool
+		if err := conn.QueryRow(ctx, `SELECT pg_try_advisory_lock($1)`, LockKey).Scan(&acquired); err != nil {
+			unlock(conn)
+			return nil, fmt.Errorf("failed to take migration lock: %w", err)
+		}
+
+		if acquired {
+			if !lastLog.IsZero() {
+				log.Println("✓ Migration lock acquired")
+			}
+			return conn, nil
+		}
+
+		holder := lockHolder(ctx, conn)
+		if time.Now().After(deadline) {
+			unlock(conn)
+			return nil, fmt.Errorf("timed out after %s waiting for the migration lock held by %s", timeout, holder)
+		}
+
+		if time.Since(lastLog) >= lockLogInterval {
+			log.Printf("Waiting for the migration lock held by %s", holder)
+			lastLog = time.Now()
+		}
+
+		select {
+		case <-ctx.Done():
+			unlock(conn)
+			return nil, ctx.Err()
+		case <-time.After(lockPollInterval):
+		}
+	}
+}
+
+// lockHolder describes the session holding the migration lock, for logging
+func lockHolder(ctx context.Context, conn *pgxpool.Conn) string {
+	var (
+		pid     int
+		name    string
+		addr    string
+		started time.Time
+	)
+	err := conn.QueryRow(ctx, `
+		SELECT a.pid, a.application_name, COALESCE(host(a.client_addr), 'local'), a.backend_start
+		FROM pg_locks l
+		JOIN pg_stat_activity a ON a.pid = l.pid
+		WHERE l.locktype = 'advisory' AND l.granted
+			AND l.classid = ($1::bigint >> 32)::oid
+			AND l.objid = ($1::bigint & 4294967295)::oid
+			AND l.objsubid = 1
+	`, LockKey).Scan(&pid, &name, &addr, &started)
+	if err != nil {
+		// The holder may have just released the lock, or pg_stat_activity is restricted
+		return "another session"
+	}
+
+	return fmt.Sprintf("%q (pid %d from %s, connected %s)", name, pid, addr, started.Format(time.RFC3339))
+}
+
+// unlock releases the migration lock and returns the connection to the pool
+// A connection that cannot be reset is closed, which drops the lock as well
+func unlock(conn *pgxpool.Conn) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := conn.Exec(ctx, `SELECT pg_advisory_unlock_all(), set_config('application_name', '', false)`); err != nil {
+		_ = conn.Conn().Close(ctx)
+	}
+	conn.Release()
+}
+
+// Status lists every embedded migration in order with the time it was applied, if ever
+func Status(ctx context.Context, pool *pgxpool.Pool) ([]Migration, error) {
+	if _, err := pool.Exec(ctx, createVersionsTable); err != nil {
+		return nil, fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	versions, err := embeddedVersions()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := pool.Query(ctx, `SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	appliedAt := make(map[string]time.Time)
+	for rows.Next() {
+		var (
+			version string
+			at      time.Time
+		)
+		if err := rows.Scan(&version, &at); err != nil {
+			return nil, fmt.Errorf("failed to scan applied migration: %w", err)
+		}
+		appliedAt[version] = at
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating applied migrations: %w", err)
+	}
+
+	migrations := make([]Migration, 0, len(versions))
+	for _, version := range versions {
+		m := Migration{Version: version}
+		if at, ok := appliedAt[version]; ok {
+			m.AppliedAt = &at
+		}
+		migrations = append(migrations, m)
+	}
+
+	return migrations, nil
+}
+
+// embeddedVersions returns the versions of all embedded migrations in apply order
+func embeddedVersions() ([]string, error) {
+	names, err := fs.Glob(files, "*.sql")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list migrations: %w", err)
+	}
+	sort.Strings(names)
+
+	versions := make([]string, 0, len(names))
+	for _, name := range names {
+		versions = append(versions, strings.TrimSuffix(name, ".sql"))
+	}
+
+	return versions, nil
+}
+
+// apply runs a single migration and records it within the same transaction
+func apply(ctx context.Context, pool *pgxpool.Pool, version string) error {
+	sql, err := files.ReadFile(version + ".sql")
+	if err != nil {
+		return fmt.Errorf("failed to read migration %s: %w", version, err)
+	}
+
+	err = pgx.BeginFunc(ctx, pool, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, string(sql)); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx, `INSERT INTO schema_migrations (version) VALUES ($1)`, version)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to apply migration %s: %w", version, err)
+	}
+
+	return nil
+}
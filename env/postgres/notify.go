@@ -0,0 +1,71 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// CacheChannel is notified by the cache triggers whenever a cached table changes, the payload is the table name
+const CacheChannel = "qubit_cache"
+
+// MessagesChannel is notified whenever messages are inserted, the payload is empty
+const MessagesChannel = "qubit_messages"
+
+// listenRetryDelay is the pause before re-establishing a lost LISTEN connection
+const listenRetryDelay = 5 * time.Second
+
+// Listen calls handle with the payload of every notification on channel until ctx is cancelled
+// It holds one pool connection while listening and reconnects after errors; since notifications
+// sent while disconnected are lost, handle is called with an empty payload after every reconnect
+func (c *Client) Listen(ctx context.Context, channel string, handle func(payload string)) {
+	go func() {
+		reconnected := false
+		for ctx.Err() == nil {
+			err := c.listen(ctx, channel, handle, reconnected)
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("Warning: listening on %s failed, reconnecting in %s: %v", channel, listenRetryDelay, err)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(listenRetryDelay):
+			}
+			reconnected = true
+		}
+	}()
+}
+
+// listen subscribes a pooled connection to channel and delivers notifications until an error occurs
+func (c *Client) listen(ctx context.Context, channel string, handle func(payload string), reconnected bool) error {
+	conn, err := c.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer func() {
+		// A connection left listening must not return to the pool
+		_ = conn.Conn().Close(context.Background())
+		conn.Release()
+	}()
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize()); err != nil {
+		return fmt.Errorf("failed to listen: %w", err)
+	}
+
+	if reconnected {
+		handle("")
+	}
+
+	for {
+		notification, err := conn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		handle(notification.Payload)
+	}
+}
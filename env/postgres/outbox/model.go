@@ -0,0 +1,19 @@
+package outbox
+
+import (
+	"time"
+)
+
+// Event represents an outbox event data model for PostgreSQL persistence
+// This is a pure data structure with no business logic
+type Event struct {
+	ID          int64      `db:"id"`
+	EventID     string     `db:"event_id"`
+	EventType   string     `db:"event_type"`
+	MessageID   int64      `db:"message_id"`
+	Payload     []byte     `db:"payload"`
+	CreatedAt   time.Time  `db:"created_at"`
+	PublishedAt *time.Time `db:"published_at"`
+	Attempts    int        `db:"attempts"`
+	LastError   *string    `db:"last_error"`
+}
@@ -0,0 +1,136 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"qubit/env/postgres/scan"
+)
+
+// eventColumns is the column list selected for an Event, matching its db tags
+const eventColumns = `id, event_id, event_type, message_id, payload, created_at, published_at, attempts, last_error`
+
+// Repository handles outbox event data access operations
+type Repository struct {
+	pool *pgxpool.Pool
+}
+
+// NewRepository creates a new outbox repository
+func NewRepository(pool *pgxpool.Pool) *Repository {
+	return &Repository{
+		pool: pool,
+	}
+}
+
+// CreateWithTx inserts events within the transaction changing their messages
+// The events are inserted in a single statement, in order; EventID must be set by the caller
+func (r *Repository) CreateWithTx(ctx context.Context, tx pgx.Tx, events []*Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO outbox_events (event_id, event_type, message_id, payload, created_at)
+		SELECT event.event_id::uuid, event.event_type, event.message_id, event.payload::jsonb, $5
+		FROM unnest($1::text[], $2::text[], $3::integer[], $4::text[]) WITH ORDINALITY AS event(event_id, event_type, message_id, payload, position)
+		ORDER BY event.position
+	`
+
+	eventIDs := make([]string, len(events))
+	eventTypes := make([]string, len(events))
+	messageIDs := make([]int64, len(events))
+	payloads := make([]string, len(events))
+	for i, e := range events {
+		eventIDs[i] = e.EventID
+		eventTypes[i] = e.EventType
+		messageIDs[i] = e.MessageID
+		payloads[i] = string(e.Payload)
+	}
+
+	if _, err := tx.Exec(ctx, query, eventIDs, eventTypes, messageIDs, payloads, time.Now()); err != nil {
+		return fmt.Errorf("failed to create outbox events: %w", err)
+	}
+
+	return nil
+}
+
+// ClaimUnpublishedWithTx locks up to limit unpublished events in insertion order
+// Events locked by another relay are skipped, the locks are held until tx ends
+func (r *Repository) ClaimUnpublishedWithTx(ctx context.Context, tx pgx.Tx, limit int) ([]*Event, error) {
+	query := `
+		SELECT ` + eventColumns + `
+		FROM outbox_events
+		WHERE published_at IS NULL
+		ORDER BY id ASC
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED
+	`
+
+	events, err := scan.All[Event](tx.Query(ctx, query, limit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query unpublished events: %w", err)
+	}
+
+	return events, nil
+}
+
+// MarkPublishedWithTx records that the events were accepted by the broker
+func (r *Repository) MarkPublishedWithTx(ctx context.Context, tx pgx.Tx, ids []int64, publishedAt time.Time) error {
+	query := `
+		UPDATE outbox_events
+		SET published_at = $1, attempts = attempts + 1, last_error = NULL
+		WHERE id = ANY($2)
+	`
+
+	if _, err := tx.Exec(ctx, query, publishedAt, ids); err != nil {
+		return fmt.Errorf("failed to mark outbox events as published: %w", err)
+	}
+
+	return nil
+}
+
+// MarkFailedWithTx records a failed publish of the events, they stay unpublished
+func (r *Repository) MarkFailedWithTx(ctx context.Context, tx pgx.Tx, ids []int64, lastError string) error {
+	query := `
+		UPDATE outbox_events
+		SET attempts = attempts + 1, last_error = $1
+		WHERE id = ANY($2)
+	`
+
+	if _, err := tx.Exec(ctx, query, lastError, ids); err != nil {
+		return fmt.Errorf("failed to record outbox publish failure: %w", err)
+	}
+
+	return nil
+}
+
+// DeletePublished removes events published before the given time and returns how many were removed
+func (r *Repository) DeletePublished(ctx context.Context, before time.Time) (int64, error) {
+	query := `
+		DELETE FROM outbox_events
+		WHERE published_at < $1
+	`
+
+	result, err := r.pool.Exec(ctx, query, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete published outbox events: %w", err)
+	}
+
+	return result.RowsAffected(), nil
+}
+
+// CountUnpublished returns the number of events waiting to be published
+func (r *Repository) CountUnpublished(ctx context.Context) (int64, error) {
+	query := `SELECT COUNT(*) FROM outbox_events WHERE published_at IS NULL`
+
+	var count int64
+	if err := r.pool.QueryRow(ctx, query).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count unpublished outbox events: %w", err)
+	}
+
+	return count, nil
+}
@@ -0,0 +1,18 @@
+package providertemplates
+
+import (
+	"time"
+)
+
+// Template represents a provider template data model for PostgreSQL persistence
+// This is a pure data structure with no business logic
+type Template struct {
+	Provider   string    `db:"provider"`
+	Name       string    `db:"name"`
+	Language   string    `db:"language"`
+	ExternalID *string   `db:"external_id"`
+	Body       string    `db:"body"`
+	Parameters int       `db:"parameters"`
+	Status     string    `db:"status"`
+	SyncedAt   time.Time `db:"synced_at"`
+}
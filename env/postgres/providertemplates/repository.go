@@ -0,0 +1,104 @@
+package providertemplates
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"qubit/env/postgres/scan"
+)
+
+// ErrNotFound is returned when a provider template does not exist
+var ErrNotFound = errors.New("provider template not found")
+
+// templateColumns is the column list selected for a Template, matching its db tags
+const templateColumns = `provider, name, language, external_id, body, parameters, status, synced_at`
+
+// Repository handles provider template data access operations
+type Repository struct {
+	pool *pgxpool.Pool
+}
+
+// NewRepository creates a new provider template repository
+func NewRepository(pool *pgxpool.Pool) *Repository {
+	return &Repository{
+		pool: pool,
+	}
+}
+
+// Get retrieves the template of a provider by its name and language
+// Returns ErrNotFound if the template does not exist
+func (r *Repository) Get(ctx context.Context, provider, name, language string) (*Template, error) {
+	query := `SELECT ` + templateColumns + ` FROM provider_templates WHERE provider = $1 AND name = $2 AND language = $3`
+
+	t, err := scan.One[Template](r.pool.Query(ctx, query, provider, name, language))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get provider template: %w", err)
+	}
+
+	return t, nil
+}
+
+// List retrieves the templates of all providers ordered by provider, name and language
+func (r *Repository) List(ctx context.Context) ([]*Template, error) {
+	query := `SELECT ` + templateColumns + ` FROM provider_templates ORDER BY provider, name, language`
+
+	templates, err := scan.All[Template](r.pool.Query(ctx, query))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query provider templates: %w", err)
+	}
+
+	return templates, nil
+}
+
+// Replace stores the templates of a provider as synced, deleting the ones it no longer lists
+// Returns the number of templates deleted
+func (r *Repository) Replace(ctx context.Context, provider string, templates []*Template) (int64, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		// Rollback is a no-op once the transaction is committed
+		_ = tx.Rollback(ctx)
+	}()
+
+	upsert := `
+		INSERT INTO provider_templates (` + templateColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (provider, name, language) DO UPDATE
+		SET external_id = EXCLUDED.external_id, body = EXCLUDED.body, parameters = EXCLUDED.parameters,
+		    status = EXCLUDED.status, synced_at = EXCLUDED.synced_at
+	`
+
+	names := make([]string, 0, len(templates))
+	languages := make([]string, 0, len(templates))
+	for _, t := range templates {
+		if _, err := tx.Exec(ctx, upsert, provider, t.Name, t.Language, t.ExternalID, t.Body, t.Parameters, t.Status, t.SyncedAt); err != nil {
+			return 0, fmt.Errorf("failed to store provider template %s (%s): %w", t.Name, t.Language, err)
+		}
+		names = append(names, t.Name)
+		languages = append(languages, t.Language)
+	}
+
+	deleted, err := tx.Exec(ctx, `
+		DELETE FROM provider_templates
+		WHERE provider = $1
+		  AND (name, language) NOT IN (SELECT * FROM unnest($2::text[], $3::text[]))
+	`, provider, names, languages)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete removed provider templates: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit provider templates: %w", err)
+	}
+
+	return deleted.RowsAffected(), nil
+}
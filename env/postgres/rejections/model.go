@@ -0,0 +1,21 @@
+package rejections
+
+import (
+	"time"
+)
+
+// RejectedRequest represents an API request rejected as invalid
+// This is a pure data structure with no business logic
+type RejectedRequest struct {
+	ID            int64             `db:"id"`
+	Method        string            `db:"method"`
+	Path          string            `db:"path"`
+	Headers       map[string]string `db:"headers"`
+	Body          string            `db:"body"`
+	BodyTruncated bool              `db:"body_truncated"`
+	Status        int               `db:"status"`
+	Error         string            `db:"error"`
+	APIKeyID      *int64            `db:"api_key_id"`
+	TenantID      *int64            `db:"tenant_id"`
+	CreatedAt     time.Time         `db:"created_at"`
+}
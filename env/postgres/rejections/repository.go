@@ -0,0 +1,101 @@
+package rejections
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"qubit/env/postgres/scan"
+)
+
+// ErrNotFound is returned when a rejected request does not exist
+var ErrNotFound = errors.New("rejected request not found")
+
+// rejectedRequestColumns is the column list selected for a RejectedRequest, matching its db tags
+const rejectedRequestColumns = `id, method, path, headers, body, body_truncated, status, error, api_key_id, tenant_id, created_at`
+
+// Repository handles rejected request data access operations
+type Repository struct {
+	pool *pgxpool.Pool
+}
+
+// NewRepository creates a new rejected request repository
+func NewRepository(pool *pgxpool.Pool) *Repository {
+	return &Repository{
+		pool: pool,
+	}
+}
+
+// Create stores a rejected request
+// The ID will be populated after successful insertion
+func (r *Repository) Create(ctx context.Context, req *RejectedRequest) error {
+	query := `
+		INSERT INTO rejected_requests (method, path, headers, body, body_truncated, status, error, api_key_id, tenant_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id
+	`
+
+	if req.CreatedAt.IsZero() {
+		req.CreatedAt = time.Now()
+	}
+	if req.Headers == nil {
+		req.Headers = map[string]string{}
+	}
+
+	err := r.pool.QueryRow(ctx, query, req.Method, req.Path, req.Headers, req.Body, req.BodyTruncated, req.Status, req.Error, req.APIKeyID, req.TenantID, req.CreatedAt).Scan(&req.ID)
+	if err != nil {
+		return fmt.Errorf("failed to store rejected request: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a rejected request by its ID
+// Returns ErrNotFound if it does not exist
+func (r *Repository) GetByID(ctx context.Context, id int64) (*RejectedRequest, error) {
+	query := `SELECT ` + rejectedRequestColumns + ` FROM rejected_requests WHERE id = $1`
+
+	req, err := scan.One[RejectedRequest](r.pool.Query(ctx, query, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rejected request: %w", err)
+	}
+
+	return req, nil
+}
+
+// List retrieves the most recent rejected requests, newest first
+func (r *Repository) List(ctx context.Context, limit int) ([]*RejectedRequest, error) {
+	query := `
+		SELECT ` + rejectedRequestColumns + `
+		FROM rejected_requests
+		ORDER BY created_at DESC, id DESC
+		LIMIT $1
+	`
+
+	found, err := scan.All[RejectedRequest](r.pool.Query(ctx, query, limit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rejected requests: %w", err)
+	}
+
+	return found, nil
+}
+
+// DeleteBefore deletes the rejected requests stored before the cutoff
+// Returns the number of requests deleted
+func (r *Repository) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	query := `DELETE FROM rejected_requests WHERE created_at < $1`
+
+	result, err := r.pool.Exec(ctx, query, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete rejected requests: %w", err)
+	}
+
+	return result.RowsAffected(), nil
+}
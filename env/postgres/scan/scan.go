@@ -0,0 +1,44 @@
+// Package scan maps query rows onto the model structs of the repositories by their db tags
+//
+// Every selected column must have a field with a matching db tag and every field a selected column,
+// so a column added to a model but missing from a query fails loudly instead of shifting the values.
+package scan
+
+import (
+	"github.com/jackc/pgx/v5"
+)
+
+// One returns the first row of a query as a T, closing the rows
+// It takes the result of Query directly; returns an error wrapping pgx.ErrNoRows when no row was returned
+func One[T any](rows pgx.Rows, err error) (*T, error) {
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectOneRow(rows, pgx.RowToAddrOfStructByName[T])
+}
+
+// All returns every row of a query as a T, closing the rows
+// It takes the result of Query directly; returns an empty slice when no row was returned
+func All[T any](rows pgx.Rows, err error) ([]*T, error) {
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToAddrOfStructByName[T])
+}
+
+// Each calls fn with every row of a query as a T, one at a time, closing the rows
+// Iteration stops at the first error of fn, which is returned
+func Each[T any](rows pgx.Rows, fn func(*T) error) error {
+	defer rows.Close()
+
+	for rows.Next() {
+		row, err := pgx.RowToAddrOfStructByName[T](rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
@@ -0,0 +1,67 @@
+package schedulermembers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Repository handles the registry of the instances sharing the queue
+type Repository struct {
+	pool *pgxpool.Pool
+}
+
+// NewRepository creates a new scheduler member repository
+func NewRepository(pool *pgxpool.Pool) *Repository {
+	return &Repository{
+		pool: pool,
+	}
+}
+
+// Heartbeat registers instanceID as live and returns the IDs of all live instances, sorted
+// Instances without a heartbeat within ttl are pruned, so their shards go to the others
+func (r *Repository) Heartbeat(ctx context.Context, instanceID string, ttl time.Duration) ([]string, error) {
+	query := `
+		WITH beat AS (
+			INSERT INTO scheduler_members (instance_id, heartbeat_at)
+			VALUES ($1, NOW())
+			ON CONFLICT (instance_id) DO UPDATE SET heartbeat_at = NOW()
+			RETURNING instance_id
+		),
+		pruned AS (
+			DELETE FROM scheduler_members
+			WHERE heartbeat_at < NOW() - make_interval(secs => $2) AND instance_id <> $1
+		)
+		SELECT instance_id FROM scheduler_members
+		WHERE heartbeat_at >= NOW() - make_interval(secs => $2)
+		UNION
+		SELECT instance_id FROM beat
+		ORDER BY instance_id
+	`
+
+	rows, err := r.pool.Query(ctx, query, instanceID, ttl.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("failed to record scheduler heartbeat: %w", err)
+	}
+
+	members, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("failed to record scheduler heartbeat: %w", err)
+	}
+
+	return members, nil
+}
+
+// Leave removes instanceID from the registry, so the others take over its shards on their next heartbeat
+func (r *Repository) Leave(ctx context.Context, instanceID string) error {
+	query := `DELETE FROM scheduler_members WHERE instance_id = $1`
+
+	if _, err := r.pool.Exec(ctx, query, instanceID); err != nil {
+		return fmt.Errorf("failed to leave scheduler members: %w", err)
+	}
+
+	return nil
+}
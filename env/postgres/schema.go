@@ -0,0 +1,478 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// column types as reported by information_schema.columns.data_type
+const (
+	typeInteger   = "integer"
+	typeBigint    = "bigint"
+	typeBoolean   = "boolean"
+	typeText      = "text"
+	typeVarchar   = "character varying"
+	typeTimestamp = "timestamp without time zone"
+	typeUUID      = "uuid"
+	typeJSONB     = "jsonb"
+	typeArray     = "ARRAY"
+	typeBytea     = "bytea"
+)
+
+// expectedTable describes a table as created by the migrations
+type expectedTable struct {
+	columns map[string]string // column name -> data type
+	indexes []string
+}
+
+// expectedSchema is the schema the repositories rely on
+// Keep it in sync with the SQL files in env/postgres/migrations
+var expectedSchema = map[string]expectedTable{
+	"messages": {
+		columns: map[string]string{
+			"id":                typeInteger,
+			"uuid":              typeUUID,
+			"phone_number":      typeVarchar,
+			"content":           typeVarchar,
+			"created_at":        typeTimestamp,
+			"message_id":        typeText,
+			"processed_at":      typeTimestamp,
+			"retry_count":       typeInteger,
+			"next_attempt_at":   typeTimestamp,
+			"status":            typeVarchar,
+			"campaign_id":       typeInteger,
+			"provider":          typeVarchar,
+			"scheduled_at":      typeTimestamp,
+			"locked_at":         typeTimestamp,
+			"locked_by":         typeVarchar,
+			"lease_expires_at":  typeTimestamp,
+			"is_test":           typeBoolean,
+			"transactional":     typeBoolean,
+			"fanout_id":         typeUUID,
+			"retry_policy":      typeJSONB,
+			"external_ref_type": typeVarchar,
+			"external_ref_id":   typeVarchar,
+			"shard_key":         typeInteger,
+			"variant":           typeVarchar,
+			"metadata":          typeJSONB,
+			"tenant_id":         typeBigint,
+			"provider_template": typeJSONB,
+			"redriven_from":     typeInteger,
+			"content_locale":    typeVarchar,
+		},
+		indexes: []string{
+			"idx_messages_processed_at",
+			"idx_messages_created_at",
+			"idx_messages_next_attempt_at",
+			"idx_messages_phone_number_processed_at",
+			"idx_messages_status",
+			"idx_messages_uuid",
+			"idx_messages_scheduled_at",
+			"idx_messages_locked_at",
+			"idx_messages_lease_expires_at",
+			"idx_messages_fanout_id",
+			"idx_messages_external_ref",
+			"idx_messages_sent_processed_at",
+			"idx_messages_shard_key",
+			"idx_messages_campaign_id",
+			"idx_messages_metadata",
+			"idx_messages_tenant_created_at",
+			"idx_messages_redriven_from",
+		},
+	},
+	"messages_archive": {
+		columns: map[string]string{
+			"id":                typeInteger,
+			"uuid":              typeUUID,
+			"phone_number":      typeVarchar,
+			"content":           typeVarchar,
+			"created_at":        typeTimestamp,
+			"message_id":        typeText,
+			"processed_at":      typeTimestamp,
+			"retry_count":       typeInteger,
+			"next_attempt_at":   typeTimestamp,
+			"status":            typeVarchar,
+			"campaign_id":       typeInteger,
+			"provider":          typeVarchar,
+			"scheduled_at":      typeTimestamp,
+			"locked_at":         typeTimestamp,
+			"locked_by":         typeVarchar,
+			"lease_expires_at":  typeTimestamp,
+			"is_test":           typeBoolean,
+			"transactional":     typeBoolean,
+			"fanout_id":         typeUUID,
+			"retry_policy":      typeJSONB,
+			"external_ref_type": typeVarchar,
+			"external_ref_id":   typeVarchar,
+			"content_locale":    typeVarchar,
+			"archived_at":       typeTimestamp,
+			"variant":           typeVarchar,
+			"metadata":          typeJSONB,
+			"tenant_id":         typeBigint,
+			"provider_template": typeJSONB,
+			"redriven_from":     typeInteger,
+		},
+		indexes: []string{
+			"idx_messages_archive_created_at",
+			"idx_messages_archive_external_ref",
+			"idx_messages_archive_campaign_id",
+			"idx_messages_archive_metadata",
+			"idx_messages_archive_redriven_from",
+			"idx_messages_archive_processed_at",
+		},
+	},
+	"inbound_messages": {
+		columns: map[string]string{
+			"id":           typeInteger,
+			"phone_number": typeVarchar,
+			"content":      typeVarchar,
+			"received_at":  typeTimestamp,
+			"reply_to_id":  typeInteger,
+		},
+		indexes: []string{
+			"idx_inbound_messages_received_at",
+			"idx_inbound_messages_reply_to_id",
+		},
+	},
+	"campaigns": {
+		columns: map[string]string{
+			"id":             typeInteger,
+			"name":           typeVarchar,
+			"content":        typeVarchar,
+			"recipients":     typeArray,
+			"status":         typeVarchar,
+			"created_by":     typeText,
+			"created_at":     typeTimestamp,
+			"updated_at":     typeTimestamp,
+			"reviewed_by":    typeText,
+			"review_comment": typeText,
+			"reviewed_at":    typeTimestamp,
+			"scheduled_at":   typeTimestamp,
+			"completed_at":   typeTimestamp,
+			"variants":       typeJSONB,
+		},
+		indexes: []string{
+			"idx_campaigns_scheduled",
+		},
+	},
+	"message_attempts": {
+		columns: map[string]string{
+			"id":               typeInteger,
+			"message_id":       typeInteger,
+			"attempt_number":   typeInteger,
+			"started_at":       typeTimestamp,
+			"success":          typeBoolean,
+			"error":            typeText,
+			"queue_wait_ms":    typeBigint,
+			"lock_to_send_ms":  typeBigint,
+			"webhook_ms":       typeBigint,
+			"db_update_ms":     typeBigint,
+			"request_payload":  typeText,
+			"response_body":    typeText,
+			"failure_category": typeVarchar,
+		},
+		indexes: []string{
+			"idx_message_attempts_message_id",
+			"idx_message_attempts_started_at",
+		},
+	},
+	"settings": {
+		columns: map[string]string{
+			"key":        typeText,
+			"value":      typeJSONB,
+			"updated_at": typeTimestamp,
+		},
+	},
+	"api_keys": {
+		columns: map[string]string{
+			"id":         typeInteger,
+			"name":       typeVarchar,
+			"key_prefix": typeVarchar,
+			"key_hash":   typeText,
+			"scopes":     typeArray,
+			"created_at": typeTimestamp,
+			"revoked_at": typeTimestamp,
+			"is_test":    typeBoolean,
+			"tenant_id":  typeInteger,
+		},
+		indexes: []string{
+			"idx_api_keys_key_hash",
+			"idx_api_keys_tenant_id",
+		},
+	},
+	"templates": {
+		columns: map[string]string{
+			"id":           typeInteger,
+			"name":         typeVarchar,
+			"content":      typeText,
+			"translations": typeJSONB,
+			"created_at":   typeTimestamp,
+		},
+		indexes: []string{
+			"idx_templates_name",
+		},
+	},
+	"tenants": {
+		columns: map[string]string{
+			"id":                    typeInteger,
+			"name":                  typeVarchar,
+			"settings":              typeJSONB,
+			"daily_message_quota":   typeInteger,
+			"rate_limit_per_minute": typeInteger,
+			"created_at":            typeTimestamp,
+		},
+		indexes: []string{
+			"idx_tenants_name",
+		},
+	},
+	"tenant_signing_keys": {
+		columns: map[string]string{
+			"id":         typeInteger,
+			"tenant_id":  typeInteger,
+			"algorithm":  typeVarchar,
+			"secret":     typeBytea,
+			"public_key": typeBytea,
+			"created_at": typeTimestamp,
+			"expires_at": typeTimestamp,
+		},
+		indexes: []string{
+			"idx_tenant_signing_keys_active",
+			"idx_tenant_signing_keys_tenant_id_expires_at",
+		},
+	},
+	"rejected_requests": {
+		columns: map[string]string{
+			"id":             typeInteger,
+			"method":         typeVarchar,
+			"path":           typeText,
+			"headers":        typeJSONB,
+			"body":           typeText,
+			"body_truncated": typeBoolean,
+			"status":         typeInteger,
+			"error":          typeText,
+			"api_key_id":     typeInteger,
+			"tenant_id":      typeInteger,
+			"created_at":     typeTimestamp,
+		},
+		indexes: []string{
+			"idx_rejected_requests_created_at",
+		},
+	},
+	"dispatch_runs": {
+		columns: map[string]string{
+			"id":          typeBigint,
+			"instance_id": typeVarchar,
+			"started_at":  typeTimestamp,
+			"finished_at": typeTimestamp,
+			"claimed":     typeInteger,
+			"attempted":   typeInteger,
+			"sent":        typeInteger,
+			"retried":     typeInteger,
+			"failed":      typeInteger,
+			"throttled":   typeInteger,
+			"deferred":    typeInteger,
+			"quarantined": typeInteger,
+			"errors":      typeJSONB,
+			"error":       typeText,
+		},
+		indexes: []string{
+			"idx_dispatch_runs_started_at",
+		},
+	},
+	"scheduler_members": {
+		columns: map[string]string{
+			"instance_id":  typeVarchar,
+			"heartbeat_at": typeTimestamp,
+		},
+	},
+	"outbox_events": {
+		columns: map[string]string{
+			"id":           typeBigint,
+			"event_id":     typeUUID,
+			"event_type":   typeVarchar,
+			"message_id":   typeInteger,
+			"payload":      typeJSONB,
+			"created_at":   typeTimestamp,
+			"published_at": typeTimestamp,
+			"attempts":     typeInteger,
+			"last_error":   typeText,
+		},
+		indexes: []string{
+			"idx_outbox_events_unpublished",
+			"idx_outbox_events_published_at",
+		},
+	},
+	"impersonations": {
+		columns: map[string]string{
+			"id":           typeInteger,
+			"tenant_id":    typeInteger,
+			"actor_key_id": typeInteger,
+			"actor_name":   typeVarchar,
+			"method":       typeVarchar,
+			"path":         typeText,
+			"client_ip":    typeVarchar,
+			"status":       typeInteger,
+			"created_at":   typeTimestamp,
+		},
+		indexes: []string{
+			"idx_impersonations_tenant_id_created_at",
+		},
+	},
+	"provider_templates": {
+		columns: map[string]string{
+			"provider":    typeVarchar,
+			"name":        typeVarchar,
+			"language":    typeVarchar,
+			"external_id": typeText,
+			"body":        typeText,
+			"parameters":  typeInteger,
+			"status":      typeVarchar,
+			"synced_at":   typeTimestamp,
+		},
+	},
+}
+
+// ColumnTypeDrift describes a column whose live type differs from the expected one
+type ColumnTypeDrift struct {
+	Column   string // table.column
+	Expected string
+	Actual   string
+}
+
+// SchemaDrift lists the differences between the live schema and the expected one
+// Extra tables, columns and indexes are not reported
+type SchemaDrift struct {
+	MissingTables  []string
+	MissingColumns []string // table.column
+	WrongTypes     []ColumnTypeDrift
+	MissingIndexes []string
+}
+
+// HasDrift reports whether any difference was found
+func (d *SchemaDrift) HasDrift() bool {
+	return len(d.MissingTables) > 0 || len(d.MissingColumns) > 0 ||
+		len(d.WrongTypes) > 0 || len(d.MissingIndexes) > 0
+}
+
+// CheckSchema compares the live schema of the current search path against the expected schema
+func (c *Client) CheckSchema(ctx context.Context) (*SchemaDrift, error) {
+	liveColumns, err := c.liveColumns(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	liveIndexes, err := c.liveIndexes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tables := make([]string, 0, len(expectedSchema))
+	for table := range expectedSchema {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+
+	drift := &SchemaDrift{}
+	for _, table := range tables {
+		expected := expectedSchema[table]
+
+		columns, ok := liveColumns[table]
+		if !ok {
+			drift.MissingTables = append(drift.MissingTables, table)
+			continue
+		}
+
+		names := make([]string, 0, len(expected.columns))
+		for name := range expected.columns {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			actual, ok := columns[name]
+			if !ok {
+				drift.MissingColumns = append(drift.MissingColumns, table+"."+name)
+				continue
+			}
+			if actual != expected.columns[name] {
+				drift.WrongTypes = append(drift.WrongTypes, ColumnTypeDrift{
+					Column:   table + "." + name,
+					Expected: expected.columns[name],
+					Actual:   actual,
+				})
+			}
+		}
+
+		for _, index := range expected.indexes {
+			if !liveIndexes[index] {
+				drift.MissingIndexes = append(drift.MissingIndexes, index)
+			}
+		}
+	}
+
+	return drift, nil
+}
+
+// liveColumns returns the data type of every column, keyed by table and column name
+func (c *Client) liveColumns(ctx context.Context) (map[string]map[string]string, error) {
+	query := `
+		SELECT table_name, column_name, data_type
+		FROM information_schema.columns
+		WHERE table_schema = current_schema()
+	`
+
+	rows, err := c.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query columns: %w", err)
+	}
+	defer rows.Close()
+
+	columns := make(map[string]map[string]string)
+	for rows.Next() {
+		var table, column, dataType string
+		if err := rows.Scan(&table, &column, &dataType); err != nil {
+			return nil, fmt.Errorf("failed to scan column: %w", err)
+		}
+		if columns[table] == nil {
+			columns[table] = make(map[string]string)
+		}
+		columns[table][column] = dataType
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating columns: %w", err)
+	}
+
+	return columns, nil
+}
+
+// liveIndexes returns the names of all indexes
+func (c *Client) liveIndexes(ctx context.Context) (map[string]bool, error) {
+	query := `
+		SELECT indexname
+		FROM pg_indexes
+		WHERE schemaname = current_schema()
+	`
+
+	rows, err := c.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query indexes: %w", err)
+	}
+	defer rows.Close()
+
+	indexes := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to scan index: %w", err)
+		}
+		indexes[name] = true
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating indexes: %w", err)
+	}
+
+	return indexes, nil
+}
@@ -0,0 +1,76 @@
+package settings
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Repository handles runtime settings stored as JSON values by key
+type Repository struct {
+	pool *pgxpool.Pool
+}
+
+// NewRepository creates a new settings repository
+func NewRepository(pool *pgxpool.Pool) *Repository {
+	return &Repository{
+		pool: pool,
+	}
+}
+
+// Get decodes the value stored under key into dest
+// Returns false if the key is not set
+func (r *Repository) Get(ctx context.Context, key string, dest interface{}) (bool, error) {
+	query := `SELECT value FROM settings WHERE key = $1`
+
+	var raw []byte
+	err := r.pool.QueryRow(ctx, query, key).Scan(&raw)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get setting %s: %w", key, err)
+	}
+
+	if err := json.Unmarshal(raw, dest); err != nil {
+		return false, fmt.Errorf("failed to decode setting %s: %w", key, err)
+	}
+
+	return true, nil
+}
+
+// Set stores value under key, replacing any previous value
+func (r *Repository) Set(ctx context.Context, key string, value interface{}) error {
+	query := `
+		INSERT INTO settings (key, value, updated_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (key) DO UPDATE
+		SET value = EXCLUDED.value, updated_at = EXCLUDED.updated_at
+	`
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode setting %s: %w", key, err)
+	}
+
+	if _, err := r.pool.Exec(ctx, query, key, raw); err != nil {
+		return fmt.Errorf("failed to set setting %s: %w", key, err)
+	}
+
+	return nil
+}
+
+// Delete removes the value stored under key
+func (r *Repository) Delete(ctx context.Context, key string) error {
+	query := `DELETE FROM settings WHERE key = $1`
+
+	if _, err := r.pool.Exec(ctx, query, key); err != nil {
+		return fmt.Errorf("failed to delete setting %s: %w", key, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,17 @@
+package signingkeys
+
+import (
+	"time"
+)
+
+// SigningKey represents a callback signing key of a tenant
+// This is a pure data structure with no business logic
+type SigningKey struct {
+	ID        int64      `db:"id"`
+	TenantID  int64      `db:"tenant_id"`
+	Algorithm string     `db:"algorithm"`
+	Secret    []byte     `db:"secret"`
+	PublicKey []byte     `db:"public_key"`
+	CreatedAt time.Time  `db:"created_at"`
+	ExpiresAt *time.Time `db:"expires_at"`
+}
@@ -0,0 +1,85 @@
+package signingkeys
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"qubit/env/postgres/scan"
+)
+
+// signingKeyColumns is the column list selected for a SigningKey, matching its db tags
+const signingKeyColumns = `id, tenant_id, algorithm, secret, public_key, created_at, expires_at`
+
+// Repository handles tenant signing key data access operations
+type Repository struct {
+	pool *pgxpool.Pool
+}
+
+// NewRepository creates a new signing key repository
+func NewRepository(pool *pgxpool.Pool) *Repository {
+	return &Repository{
+		pool: pool,
+	}
+}
+
+// ExpireActiveWithTx lets the active key of a tenant expire at expiresAt within a transaction
+// The tenant row is locked first, so concurrent rotations of the same tenant run one after the other
+func (r *Repository) ExpireActiveWithTx(ctx context.Context, tx pgx.Tx, tenantID int64, expiresAt time.Time) error {
+	if _, err := tx.Exec(ctx, `SELECT 1 FROM tenants WHERE id = $1 FOR UPDATE`, tenantID); err != nil {
+		return fmt.Errorf("failed to lock tenant: %w", err)
+	}
+
+	query := `
+		UPDATE tenant_signing_keys
+		SET expires_at = $2
+		WHERE tenant_id = $1 AND expires_at IS NULL
+	`
+
+	if _, err := tx.Exec(ctx, query, tenantID, expiresAt); err != nil {
+		return fmt.Errorf("failed to expire active signing key: %w", err)
+	}
+
+	return nil
+}
+
+// CreateWithTx inserts a new active signing key within a transaction
+// The ID will be populated after successful insertion
+func (r *Repository) CreateWithTx(ctx context.Context, tx pgx.Tx, k *SigningKey) error {
+	query := `
+		INSERT INTO tenant_signing_keys (tenant_id, algorithm, secret, public_key, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`
+
+	if k.CreatedAt.IsZero() {
+		k.CreatedAt = time.Now()
+	}
+
+	err := tx.QueryRow(ctx, query, k.TenantID, k.Algorithm, k.Secret, k.PublicKey, k.CreatedAt).Scan(&k.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create signing key: %w", err)
+	}
+
+	return nil
+}
+
+// ListValid retrieves the keys of a tenant that did not expire yet, the active key first
+func (r *Repository) ListValid(ctx context.Context, tenantID int64) ([]*SigningKey, error) {
+	query := `
+		SELECT ` + signingKeyColumns + `
+		FROM tenant_signing_keys
+		WHERE tenant_id = $1 AND (expires_at IS NULL OR expires_at > NOW())
+		ORDER BY created_at DESC, id DESC
+	`
+
+	found, err := scan.All[SigningKey](r.pool.Query(ctx, query, tenantID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query signing keys: %w", err)
+	}
+
+	return found, nil
+}
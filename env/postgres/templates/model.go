@@ -0,0 +1,15 @@
+package templates
+
+import (
+	"time"
+)
+
+// Template represents a message template data model for PostgreSQL persistence
+// This is a pure data structure with no business logic
+type Template struct {
+	ID           int64             `db:"id"`
+	Name         string            `db:"name"`
+	Content      string            `db:"content"`
+	Translations map[string]string `db:"translations"`
+	CreatedAt    time.Time         `db:"created_at"`
+}
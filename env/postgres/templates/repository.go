@@ -0,0 +1,96 @@
+package templates
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"qubit/env/postgres/scan"
+)
+
+// ErrNotFound is returned when a template does not exist
+var ErrNotFound = errors.New("template not found")
+
+// ErrDuplicateName is returned when a template with the same name already exists
+var ErrDuplicateName = errors.New("template name already exists")
+
+// uniqueViolation is the PostgreSQL error code of a unique constraint violation
+const uniqueViolation = "23505"
+
+// templateColumns is the column list selected for a Template, matching its db tags
+const templateColumns = `id, name, content, translations, created_at`
+
+// Repository handles template data access operations
+type Repository struct {
+	pool *pgxpool.Pool
+}
+
+// NewRepository creates a new template repository
+func NewRepository(pool *pgxpool.Pool) *Repository {
+	return &Repository{
+		pool: pool,
+	}
+}
+
+// Create inserts a new template into the database
+// The ID will be populated after successful insertion
+// Returns ErrDuplicateName if the name is taken
+func (r *Repository) Create(ctx context.Context, t *Template) error {
+	query := `
+		INSERT INTO templates (name, content, translations, created_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`
+
+	if t.CreatedAt.IsZero() {
+		t.CreatedAt = time.Now()
+	}
+
+	if t.Translations == nil {
+		t.Translations = map[string]string{}
+	}
+
+	err := r.pool.QueryRow(ctx, query, t.Name, t.Content, t.Translations, t.CreatedAt).Scan(&t.ID)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+			return ErrDuplicateName
+		}
+		return fmt.Errorf("failed to create template: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a template by its ID
+// Returns ErrNotFound if the template does not exist
+func (r *Repository) GetByID(ctx context.Context, id int64) (*Template, error) {
+	query := `SELECT ` + templateColumns + ` FROM templates WHERE id = $1`
+
+	t, err := scan.One[Template](r.pool.Query(ctx, query, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get template: %w", err)
+	}
+
+	return t, nil
+}
+
+// List retrieves all templates ordered by name
+func (r *Repository) List(ctx context.Context) ([]*Template, error) {
+	query := `SELECT ` + templateColumns + ` FROM templates ORDER BY name ASC`
+
+	templates, err := scan.All[Template](r.pool.Query(ctx, query))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query templates: %w", err)
+	}
+
+	return templates, nil
+}
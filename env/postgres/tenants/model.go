@@ -0,0 +1,17 @@
+package tenants
+
+import (
+	"time"
+)
+
+// Tenant represents a tenant data model for PostgreSQL persistence
+// This is a pure data structure with no business logic
+type Tenant struct {
+	ID                 int64             `db:"id"`
+	Name               string            `db:"name"`
+	Settings           map[string]string `db:"settings"`
+	DefaultMetadata    map[string]string `db:"default_metadata"`
+	DailyMessageQuota  int               `db:"daily_message_quota"`
+	RateLimitPerMinute int               `db:"rate_limit_per_minute"`
+	CreatedAt          time.Time         `db:"created_at"`
+}
@@ -0,0 +1,118 @@
+package tenants
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"qubit/env/postgres/scan"
+)
+
+// ErrNotFound is returned when a tenant does not exist
+var ErrNotFound = errors.New("tenant not found")
+
+// ErrDuplicateName is returned when a tenant with the same name already exists
+var ErrDuplicateName = errors.New("tenant name already exists")
+
+// uniqueViolation is the PostgreSQL error code of a unique constraint violation
+const uniqueViolation = "23505"
+
+// tenantColumns is the column list selected for a Tenant, matching its db tags
+const tenantColumns = `id, name, settings, default_metadata, daily_message_quota, rate_limit_per_minute, created_at`
+
+// Repository handles tenant data access operations
+type Repository struct {
+	pool *pgxpool.Pool
+}
+
+// NewRepository creates a new tenant repository
+func NewRepository(pool *pgxpool.Pool) *Repository {
+	return &Repository{
+		pool: pool,
+	}
+}
+
+// CreateWithTx inserts a new tenant within a transaction
+// The ID will be populated after successful insertion
+// Returns ErrDuplicateName if the name is taken
+func (r *Repository) CreateWithTx(ctx context.Context, tx pgx.Tx, t *Tenant) error {
+	query := `
+		INSERT INTO tenants (name, settings, default_metadata, daily_message_quota, rate_limit_per_minute, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`
+
+	if t.CreatedAt.IsZero() {
+		t.CreatedAt = time.Now()
+	}
+	if t.Settings == nil {
+		t.Settings = map[string]string{}
+	}
+	if t.DefaultMetadata == nil {
+		t.DefaultMetadata = map[string]string{}
+	}
+
+	err := tx.QueryRow(ctx, query, t.Name, t.Settings, t.DefaultMetadata, t.DailyMessageQuota, t.RateLimitPerMinute, t.CreatedAt).Scan(&t.ID)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+			return ErrDuplicateName
+		}
+		return fmt.Errorf("failed to create tenant: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a tenant by its ID
+// Returns ErrNotFound if the tenant does not exist
+func (r *Repository) GetByID(ctx context.Context, id int64) (*Tenant, error) {
+	query := `SELECT ` + tenantColumns + ` FROM tenants WHERE id = $1`
+
+	t, err := scan.One[Tenant](r.pool.Query(ctx, query, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant: %w", err)
+	}
+
+	return t, nil
+}
+
+// SetDefaultMetadata replaces the default metadata of a tenant
+// Returns ErrNotFound if the tenant does not exist
+func (r *Repository) SetDefaultMetadata(ctx context.Context, id int64, metadata map[string]string) (*Tenant, error) {
+	query := `UPDATE tenants SET default_metadata = $2 WHERE id = $1 RETURNING ` + tenantColumns
+
+	if metadata == nil {
+		metadata = map[string]string{}
+	}
+
+	t, err := scan.One[Tenant](r.pool.Query(ctx, query, id, metadata))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to set tenant default metadata: %w", err)
+	}
+
+	return t, nil
+}
+
+// List retrieves all tenants ordered by name
+func (r *Repository) List(ctx context.Context) ([]*Tenant, error) {
+	query := `SELECT ` + tenantColumns + ` FROM tenants ORDER BY name ASC`
+
+	tenants, err := scan.All[Tenant](r.pool.Query(ctx, query))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tenants: %w", err)
+	}
+
+	return tenants, nil
+}
@@ -0,0 +1,213 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without calling the provider while its circuit is open
+var ErrCircuitOpen = errors.New("provider circuit is open")
+
+// CircuitState is the state of a provider circuit breaker
+type CircuitState string
+
+// Circuit states
+const (
+	CircuitClosed   CircuitState = "closed"
+	CircuitOpen     CircuitState = "open"
+	CircuitHalfOpen CircuitState = "half_open"
+)
+
+// BreakerSettings configure the circuit breaker wrapped around every provider sender
+type BreakerSettings struct {
+	Threshold      int           // consecutive failures opening the circuit, 0 disables the breaker
+	OpenFor        time.Duration // how long the circuit stays open before probing the provider
+	HalfOpenProbes int           // successful probes closing the circuit again
+}
+
+// BreakerStats describes the state of a circuit and the transitions it went through
+type BreakerStats struct {
+	State               CircuitState
+	ConsecutiveFailures int
+	OpenedAt            *time.Time
+	Transitions         map[string]int64 // "closed->open" -> count
+	Rejected            int64            // calls refused while the circuit was open
+}
+
+// Breaker is a circuit breaker around a Sender
+// After Threshold consecutive provider failures the circuit opens and calls fail fast with ErrCircuitOpen;
+// once OpenFor has passed, one probe at a time is let through until HalfOpenProbes succeed in a row
+// Only failures of the provider count, rejected messages (4xx, SMTP rejections) and cancelled calls do not
+type Breaker struct {
+	sender   Sender
+	name     string
+	settings BreakerSettings
+
+	mu          sync.Mutex
+	state       CircuitState
+	failures    int  // consecutive failures while closed
+	successes   int  // consecutive successful probes while half-open
+	probing     bool // a half-open probe is in flight
+	openedAt    time.Time
+	transitions map[string]int64
+	rejected    int64
+}
+
+// NewBreaker wraps sender, the provider name is used in logs and errors
+func NewBreaker(name string, sender Sender, settings BreakerSettings) *Breaker {
+	return &Breaker{
+		sender:      sender,
+		name:        name,
+		settings:    settings,
+		state:       CircuitClosed,
+		transitions: make(map[string]int64),
+	}
+}
+
+// SendMessage calls the wrapped sender unless the circuit is open
+func (b *Breaker) SendMessage(ctx context.Context, phoneNumber, content string) (string, error) {
+	if !b.allow() {
+		return "", fmt.Errorf("%w: %s", ErrCircuitOpen, b.name)
+	}
+
+	messageID, err := b.sender.SendMessage(ctx, phoneNumber, content)
+	b.record(err)
+
+	return messageID, err
+}
+
+// SendTemplate calls the wrapped template sender unless the circuit is open
+func (b *Breaker) SendTemplate(ctx context.Context, phoneNumber string, tmpl TemplateMessage) (string, error) {
+	sender, ok := b.sender.(TemplateSender)
+	if !ok {
+		return "", fmt.Errorf("provider %s does not send templates", b.name)
+	}
+
+	if !b.allow() {
+		return "", fmt.Errorf("%w: %s", ErrCircuitOpen, b.name)
+	}
+
+	messageID, err := sender.SendTemplate(ctx, phoneNumber, tmpl)
+	b.record(err)
+
+	return messageID, err
+}
+
+// Unwrap returns the wrapped sender
+func (b *Breaker) Unwrap() Sender {
+	return b.sender
+}
+
+// Open reports whether calls are currently refused without probing the provider
+func (b *Breaker) Open() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state == CircuitOpen && time.Since(b.openedAt) < b.settings.OpenFor
+}
+
+// Stats returns a snapshot of the circuit state and its transition counters
+func (b *Breaker) Stats() BreakerStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	stats := BreakerStats{
+		State:               b.state,
+		ConsecutiveFailures: b.failures,
+		Transitions:         make(map[string]int64, len(b.transitions)),
+		Rejected:            b.rejected,
+	}
+	if b.state != CircuitClosed {
+		openedAt := b.openedAt
+		stats.OpenedAt = &openedAt
+	}
+	for transition, count := range b.transitions {
+		stats.Transitions[transition] = count
+	}
+
+	return stats
+}
+
+// allow decides whether a call may go through, moving an expired open circuit to half-open
+func (b *Breaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == CircuitOpen && time.Since(b.openedAt) >= b.settings.OpenFor {
+		b.transition(CircuitHalfOpen)
+	}
+
+	switch b.state {
+	case CircuitClosed:
+		return true
+	case CircuitHalfOpen:
+		if !b.probing {
+			b.probing = true
+			return true
+		}
+	}
+
+	b.rejected++
+	return false
+}
+
+// record updates the circuit with the result of a call
+func (b *Breaker) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	category := Classify(err)
+	failed := err != nil && category != FailureHTTP4xx && category != FailureRejected && category != FailureCancelled
+
+	switch b.state {
+	case CircuitClosed:
+		switch {
+		case failed:
+			b.failures++
+			if b.failures >= b.settings.Threshold {
+				b.open()
+			}
+		case category != FailureCancelled:
+			b.failures = 0
+		}
+
+	case CircuitHalfOpen:
+		b.probing = false
+		switch {
+		case failed:
+			b.open()
+		case category != FailureCancelled:
+			b.successes++
+			if b.successes >= b.settings.HalfOpenProbes {
+				b.failures = 0
+				b.transition(CircuitClosed)
+			}
+		}
+	}
+}
+
+// open moves the circuit to open, b.mu must be held
+func (b *Breaker) open() {
+	b.openedAt = time.Now()
+	b.transition(CircuitOpen)
+}
+
+// transition moves the circuit to state and counts the transition, b.mu must be held
+func (b *Breaker) transition(state CircuitState) {
+	from := b.state
+	b.state = state
+	b.successes = 0
+	b.transitions[string(from)+"->"+string(state)]++
+
+	switch {
+	case state == CircuitOpen && from == CircuitHalfOpen:
+		log.Printf("⚠ Circuit of provider %s re-opened after a failed probe, retrying in %s", b.name, b.settings.OpenFor)
+	case state == CircuitOpen:
+		log.Printf("⚠ Circuit of provider %s opened after %d consecutive failures (%s -> %s), retrying in %s", b.name, b.failures, from, state, b.settings.OpenFor)
+	default:
+		log.Printf("Circuit of provider %s moved %s -> %s", b.name, from, state)
+	}
+}
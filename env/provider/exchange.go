@@ -0,0 +1,117 @@
+package provider
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxExchangeBytes caps each stored side of a provider exchange
+const maxExchangeBytes = 4096
+
+// authHeader is the header carrying the provider auth key
+const authHeader = "x-ins-auth-key"
+
+// redacted replaces secrets in stored exchanges
+const redacted = "[REDACTED]"
+
+// Exchange is the sanitized raw request and response of a failed provider call
+// Response is empty when the provider never answered
+type Exchange struct {
+	Request  string
+	Response string
+}
+
+// ExchangeError is returned by SendMessage when the provider call failed
+// It carries the raw exchange so it can be attached to support tickets
+// StatusCode is the HTTP status the provider answered with, 0 when there was no HTTP answer
+// RetryAfter is the delay the provider asked for in a Retry-After header, 0 without one
+type ExchangeError struct {
+	Err        error
+	Exchange   Exchange
+	StatusCode int
+	RetryAfter time.Duration
+}
+
+func (e *ExchangeError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExchangeError) Unwrap() error {
+	return e.Err
+}
+
+// ExchangeOf extracts the raw exchange from an error returned by SendMessage
+func ExchangeOf(err error) (*Exchange, bool) {
+	var exErr *ExchangeError
+	if !errors.As(err, &exErr) {
+		return nil, false
+	}
+	return &exErr.Exchange, true
+}
+
+// RetryAfterOf returns the delay a provider asked for when it throttled a call with 429 or 503 and a Retry-After header
+func RetryAfterOf(err error) (time.Duration, bool) {
+	var exErr *ExchangeError
+	if !errors.As(err, &exErr) || exErr.RetryAfter <= 0 {
+		return 0, false
+	}
+	if exErr.StatusCode != http.StatusTooManyRequests && exErr.StatusCode != http.StatusServiceUnavailable {
+		return 0, false
+	}
+	return exErr.RetryAfter, true
+}
+
+// newExchangeError wraps err with the exchange, stripped of the given secrets
+func newExchangeError(err error, request, response string, secrets ...string) *ExchangeError {
+	return &ExchangeError{
+		Err: err,
+		Exchange: Exchange{
+			Request:  sanitize(request, secrets),
+			Response: sanitize(response, secrets),
+		},
+	}
+}
+
+// withStatus records the HTTP status the provider answered with
+func (e *ExchangeError) withStatus(statusCode int) *ExchangeError {
+	e.StatusCode = statusCode
+	return e
+}
+
+// withRetryAfter records the delay of a Retry-After header, given in seconds or as an HTTP date
+// A missing, malformed or past value is ignored
+func (e *ExchangeError) withRetryAfter(header string) *ExchangeError {
+	header = strings.TrimSpace(header)
+	if header == "" {
+		return e
+	}
+
+	if seconds, err := strconv.Atoi(header); err == nil {
+		e.RetryAfter = max(time.Duration(seconds)*time.Second, 0)
+		return e
+	}
+
+	if at, err := http.ParseTime(header); err == nil {
+		e.RetryAfter = max(time.Until(at), 0)
+	}
+
+	return e
+}
+
+// sanitize strips the secrets and caps the size of a raw payload
+func sanitize(raw string, secrets []string) string {
+	for _, secret := range secrets {
+		if secret != "" {
+			raw = strings.ReplaceAll(raw, secret, redacted)
+		}
+	}
+
+	if len(raw) > maxExchangeBytes {
+		raw = raw[:maxExchangeBytes] + "...[truncated]"
+	}
+
+	return raw
+}
@@ -0,0 +1,89 @@
+package provider
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"net/textproto"
+)
+
+// FailureCategory classifies why a provider call failed
+type FailureCategory string
+
+// Failure categories, from our network up to the provider's answer
+const (
+	FailureDNS            FailureCategory = "dns"
+	FailureTLS            FailureCategory = "tls"
+	FailureConnectTimeout FailureCategory = "connect_timeout"
+	FailureConnect        FailureCategory = "connect"
+	FailureReadTimeout    FailureCategory = "read_timeout"
+	FailureHTTP4xx        FailureCategory = "http_4xx"
+	FailureHTTP5xx        FailureCategory = "http_5xx"
+	FailureRejected       FailureCategory = "rejected"
+	FailureCancelled      FailureCategory = "cancelled"
+	FailureOther          FailureCategory = "other"
+)
+
+// Classify returns the category of an error returned by SendMessage
+// HTTP statuses win over transport errors, a status means the provider answered
+func Classify(err error) FailureCategory {
+	var exErr *ExchangeError
+	if errors.As(err, &exErr) {
+		switch {
+		case exErr.StatusCode >= 500:
+			return FailureHTTP5xx
+		case exErr.StatusCode >= 400:
+			return FailureHTTP4xx
+		}
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return FailureDNS
+	}
+
+	if isTLSError(err) {
+		return FailureTLS
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		if opErr.Timeout() {
+			return FailureConnectTimeout
+		}
+		return FailureConnect
+	}
+
+	var smtpErr *textproto.Error
+	if errors.As(err, &smtpErr) {
+		return FailureRejected
+	}
+
+	if errors.Is(err, context.Canceled) {
+		return FailureCancelled
+	}
+
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return FailureReadTimeout
+	}
+
+	return FailureOther
+}
+
+// isTLSError reports whether err happened during the TLS handshake or certificate verification
+func isTLSError(err error) bool {
+	var (
+		recordErr tls.RecordHeaderError
+		alertErr  tls.AlertError
+		verifyErr *tls.CertificateVerificationError
+		unknownCA x509.UnknownAuthorityError
+		hostErr   x509.HostnameError
+		invalidCA x509.CertificateInvalidError
+	)
+
+	return errors.As(err, &recordErr) || errors.As(err, &alertErr) || errors.As(err, &verifyErr) ||
+		errors.As(err, &unknownCA) || errors.As(err, &hostErr) || errors.As(err, &invalidCA)
+}
@@ -0,0 +1,25 @@
+package provider
+
+import (
+	"net/http"
+
+	"qubit/pkg/buildinfo"
+)
+
+// InstanceHeader identifies the qubit instance making an outbound call
+const InstanceHeader = "X-Qubit-Instance"
+
+// identityTransport adds the identification headers to every outbound request
+type identityTransport struct {
+	base     http.RoundTripper
+	instance string
+}
+
+// RoundTrip sets User-Agent and X-Qubit-Instance on a copy of req
+func (t *identityTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("User-Agent", buildinfo.UserAgent())
+	req.Header.Set(InstanceHeader, t.instance)
+
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,20 @@
+package provider
+
+import (
+	"context"
+)
+
+// metadataKey is the context key carrying the metadata of the message being sent
+type metadataKey struct{}
+
+// WithMetadata returns a context carrying the metadata of the message being sent
+// Senders whose provider accepts custom fields forward it in the request, the others ignore it
+func WithMetadata(ctx context.Context, metadata map[string]string) context.Context {
+	return context.WithValue(ctx, metadataKey{}, metadata)
+}
+
+// metadataFrom returns the message metadata carried by ctx, see WithMetadata
+func metadataFrom(ctx context.Context) map[string]string {
+	metadata, _ := ctx.Value(metadataKey{}).(map[string]string)
+	return metadata
+}
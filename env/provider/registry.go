@@ -0,0 +1,247 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"qubit/env/config"
+)
+
+// Registry holds one sender per configured provider
+// In fake mode every provider is replaced by a simulated sender, see SetFake
+type Registry struct {
+	senders     map[string]Sender
+	fakes       map[string]Sender
+	fake        atomic.Bool
+	breakers    map[string]*Breaker // empty when the circuit breaker is disabled
+	names       []string
+	defaultName string
+	instance    string
+
+	mu      sync.RWMutex // guards configs, which Reconfigure updates
+	configs map[string]config.ProviderConfig
+}
+
+// NewRegistry creates a sender for every provider according to its type, the first provider is the default one
+// instance is sent as X-Qubit-Instance on every outbound call
+// Each sender is wrapped in a circuit breaker unless breaker.Threshold is 0
+func NewRegistry(providers []config.ProviderConfig, instance string, breaker BreakerSettings) *Registry {
+	r := &Registry{
+		senders:  make(map[string]Sender, len(providers)),
+		fakes:    make(map[string]Sender, len(providers)),
+		breakers: make(map[string]*Breaker, len(providers)),
+		configs:  make(map[string]config.ProviderConfig, len(providers)),
+		instance: instance,
+	}
+
+	for i, p := range providers {
+		if i == 0 {
+			r.defaultName = p.Name
+		}
+		sender := NewSender(p, instance)
+		if breaker.Threshold > 0 {
+			b := NewBreaker(p.Name, sender, breaker)
+			r.breakers[p.Name] = b
+			sender = b
+		}
+		r.senders[p.Name] = sender
+		r.fakes[p.Name] = NewWebhook(fakeURLPrefix+p.Name, "", p.Timeout(), instance)
+		r.configs[p.Name] = p
+		r.names = append(r.names, p.Name)
+	}
+
+	return r
+}
+
+// Get returns the sender of the named provider, its simulated sender in fake mode
+func (r *Registry) Get(name string) (Sender, bool) {
+	if r.fake.Load() {
+		s, ok := r.fakes[name]
+		return s, ok
+	}
+	s, ok := r.senders[name]
+	return s, ok
+}
+
+// Default returns the sender of the default provider, its simulated sender in fake mode
+func (r *Registry) Default() Sender {
+	sender, _ := r.Get(r.defaultName)
+	return sender
+}
+
+// SetFake switches every provider to its simulated sender or back to the real one
+// Messages sent in fake mode never reach a provider, their exchanges show a fake:// URL
+func (r *Registry) SetFake(enabled bool) {
+	r.fake.Store(enabled)
+}
+
+// Fake reports whether the providers are replaced by simulated senders
+func (r *Registry) Fake() bool {
+	return r.fake.Load()
+}
+
+// DefaultName returns the name of the default provider
+func (r *Registry) DefaultName() string {
+	return r.defaultName
+}
+
+// SandboxName returns the name of the first sandbox provider
+func (r *Registry) SandboxName() (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, name := range r.names {
+		if r.configs[name].Sandbox {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// Names returns the provider names in configuration order
+func (r *Registry) Names() []string {
+	return append([]string(nil), r.names...)
+}
+
+// Instance returns the instance identifier sent on outbound calls
+func (r *Registry) Instance() string {
+	return r.instance
+}
+
+// Config returns the configuration of the named provider
+func (r *Registry) Config(name string) (config.ProviderConfig, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	p, ok := r.configs[name]
+	return p, ok
+}
+
+// Configs returns the configuration of every provider in configuration order
+func (r *Registry) Configs() []config.ProviderConfig {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	configs := make([]config.ProviderConfig, 0, len(r.names))
+	for _, name := range r.names {
+		configs = append(configs, r.configs[name])
+	}
+	return configs
+}
+
+// Reconfigure applies the webhook URLs and auth keys of providers to their running senders, e.g. after a configuration reload
+// It returns the providers it updated and describes the changes it cannot apply, such as an added, removed or
+// reordered provider or a change of any other setting, which need a restart; those providers keep their configuration
+func (r *Registry) Reconfigure(providers []config.ProviderConfig) (updated, restartRequired []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	names := make([]string, 0, len(providers))
+	for _, p := range providers {
+		names = append(names, p.Name)
+
+		current, ok := r.configs[p.Name]
+		if !ok {
+			restartRequired = append(restartRequired, fmt.Sprintf("provider %s added", p.Name))
+			continue
+		}
+		if reflect.DeepEqual(p, current) {
+			continue
+		}
+
+		// Only the endpoint of a webhook provider can change in place
+		endpointOnly := current
+		endpointOnly.URL, endpointOnly.AuthKey = p.URL, p.AuthKey
+		webhook, isWebhook := r.webhook(p.Name)
+		if !isWebhook || !reflect.DeepEqual(p, endpointOnly) {
+			restartRequired = append(restartRequired, fmt.Sprintf("provider %s changed", p.Name))
+			continue
+		}
+
+		webhook.SetEndpoint(p.URL, p.AuthKey)
+		r.configs[p.Name] = endpointOnly
+		updated = append(updated, p.Name)
+	}
+
+	for _, name := range r.names {
+		if !slices.Contains(names, name) {
+			restartRequired = append(restartRequired, fmt.Sprintf("provider %s removed", name))
+		}
+	}
+	if len(names) == len(r.names) && !slices.Equal(names, r.names) {
+		restartRequired = append(restartRequired, "provider order changed")
+	}
+
+	return updated, restartRequired
+}
+
+// webhook returns the named provider sender when it is a webhook
+func (r *Registry) webhook(name string) (*Webhook, bool) {
+	sender := r.senders[name]
+	if b, ok := sender.(*Breaker); ok {
+		sender = b.Unwrap()
+	}
+	w, ok := sender.(*Webhook)
+	return w, ok
+}
+
+// Warmer returns the named provider sender when it keeps its connection warm
+func (r *Registry) Warmer(name string) (Warmer, bool) {
+	sender := r.senders[name]
+	if b, ok := sender.(*Breaker); ok {
+		sender = b.Unwrap()
+	}
+	w, ok := sender.(Warmer)
+	return w, ok
+}
+
+// Prober returns the named provider sender when its endpoint can be probed
+func (r *Registry) Prober(name string) (Prober, bool) {
+	sender := r.senders[name]
+	if b, ok := sender.(*Breaker); ok {
+		sender = b.Unwrap()
+	}
+	p, ok := sender.(Prober)
+	return p, ok
+}
+
+// TemplateLister returns the named provider sender when its provider reports its templates
+func (r *Registry) TemplateLister(name string) (TemplateLister, bool) {
+	sender := r.senders[name]
+	if b, ok := sender.(*Breaker); ok {
+		sender = b.Unwrap()
+	}
+	l, ok := sender.(TemplateLister)
+	return l, ok
+}
+
+// Breaker returns the circuit breaker of the named provider, false when the breaker is disabled
+func (r *Registry) Breaker(name string) (*Breaker, bool) {
+	b, ok := r.breakers[name]
+	return b, ok
+}
+
+// OpenCircuits returns the names of the providers whose circuit is currently open
+func (r *Registry) OpenCircuits() []string {
+	var open []string
+	for _, name := range r.names {
+		if b, ok := r.breakers[name]; ok && b.Open() {
+			open = append(open, name)
+		}
+	}
+	return open
+}
+
+// KeepWarm keeps the connections of all warmable providers warm until ctx is cancelled
+func (r *Registry) KeepWarm(ctx context.Context, idle time.Duration) {
+	for _, name := range r.names {
+		if w, ok := r.Warmer(name); ok {
+			go w.KeepWarm(ctx, idle)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package provider
+
+import (
+	"context"
+	"time"
+
+	"qubit/env/config"
+)
+
+// Sender delivers a single message through a provider channel
+// Failed calls return an *ExchangeError carrying the raw exchange when one took place
+type Sender interface {
+	SendMessage(ctx context.Context, phoneNumber, content string) (string, error)
+}
+
+// Warmer is implemented by senders that keep their provider connection warm
+type Warmer interface {
+	KeepWarm(ctx context.Context, idle time.Duration)
+	WarmUpStatus() WarmUpStatus
+}
+
+// Prober is implemented by senders whose provider endpoint can be checked for reachability
+type Prober interface {
+	Probe(ctx context.Context) error
+}
+
+// TemplateSender is implemented by senders of template-only providers
+type TemplateSender interface {
+	SendTemplate(ctx context.Context, phoneNumber string, tmpl TemplateMessage) (string, error)
+}
+
+// TemplateLister is implemented by senders whose provider reports its templates and their approval status
+type TemplateLister interface {
+	ListTemplates(ctx context.Context) ([]Template, error)
+}
+
+// NewSender creates the sender matching the provider type
+func NewSender(p config.ProviderConfig, instance string) Sender {
+	switch p.Kind() {
+	case config.ProviderTypeTwilio:
+		return NewTwilio(p.URL, p.AccountSID, p.AuthKey, p.From, p.Timeout(), instance)
+	case config.ProviderTypeSMTP:
+		return NewSMTP(p.SMTPHost, p.SMTPPort, p.Username, p.AuthKey, p.From, p.To, p.Timeout())
+	case config.ProviderTypeWhatsApp:
+		return NewWhatsApp(p.URL, p.AccountSID, p.AuthKey, p.From, p.Timeout(), instance)
+	default:
+		return NewWebhook(p.URL, p.AuthKey, p.Timeout(), instance)
+	}
+}
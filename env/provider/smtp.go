@@ -0,0 +1,120 @@
+package provider
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// defaultSMTPPort is the submission port used when the provider sets no smtpPort
+const defaultSMTPPort = 587
+
+// SMTP sends messages as plain-text emails, typically to an email-to-SMS gateway
+type SMTP struct {
+	addr     string
+	host     string
+	username string
+	password string
+	from     string
+	to       string
+	timeout  time.Duration
+}
+
+// NewSMTP creates a new SMTP sender
+// to is the recipient address template, {phone} is replaced with the message phone number
+func NewSMTP(host string, port int, username, password, from, to string, timeout time.Duration) *SMTP {
+	if port == 0 {
+		port = defaultSMTPPort
+	}
+
+	return &SMTP{
+		addr:     net.JoinHostPort(host, strconv.Itoa(port)),
+		host:     host,
+		username: username,
+		password: password,
+		from:     from,
+		to:       to,
+		timeout:  timeout,
+	}
+}
+
+// SendMessage delivers the content as an email and returns the generated Message-ID
+func (s *SMTP) SendMessage(ctx context.Context, phoneNumber, content string) (string, error) {
+	if s.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.timeout)
+		defer cancel()
+	}
+
+	to := strings.ReplaceAll(s.to, "{phone}", strings.TrimPrefix(phoneNumber, "+"))
+	messageID := uuid.New().String()
+
+	msg := fmt.Sprintf("From: %s\r\nTo: %s\r\nMessage-ID: <%s@%s>\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n%s\r\n",
+		s.from, to, messageID, s.host, content)
+	request := fmt.Sprintf("SMTP %s\r\nMAIL FROM:<%s>\r\nRCPT TO:<%s>\r\n\r\n%s", s.addr, s.from, to, msg)
+
+	if err := s.send(ctx, to, []byte(msg)); err != nil {
+		return "", newExchangeError(fmt.Errorf("smtp send failed: %w", err), request, err.Error(), s.password)
+	}
+
+	return messageID, nil
+}
+
+// send runs a single SMTP session, upgrading to TLS and authenticating when the server supports it
+func (s *SMTP) send(ctx context.Context, to string, msg []byte) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", s.addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, s.host)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: s.host}); err != nil {
+			return err
+		}
+	}
+
+	if s.username != "" {
+		if err := client.Auth(smtp.PlainAuth("", s.username, s.password, s.host)); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(s.from); err != nil {
+		return err
+	}
+	if err := client.Rcpt(to); err != nil {
+		return err
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
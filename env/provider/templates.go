@@ -0,0 +1,63 @@
+package provider
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+)
+
+// ErrTemplateRequired is returned by template-only providers for free-form content
+var ErrTemplateRequired = errors.New("provider only sends approved templates")
+
+// Template approval states, as reported by the provider in lowercase
+const (
+	TemplateApproved = "approved"
+	TemplatePending  = "pending"
+	TemplateRejected = "rejected"
+	TemplatePaused   = "paused"
+	TemplateDisabled = "disabled"
+)
+
+// TemplateMessage is a pre-approved provider template with the values of its parameter slots
+type TemplateMessage struct {
+	Name       string
+	Language   string
+	Parameters []string
+}
+
+// Template is a template registered at a provider with its approval status
+type Template struct {
+	ID       string
+	Name     string
+	Language string
+	Status   string
+	// Body is the template text with numbered parameter slots, e.g. "Hi {{1}}, order {{2}} has shipped"
+	Body string
+	// Parameters is the number of parameter slots, the highest slot number in Body
+	Parameters int
+}
+
+// templateSlotRegex matches a numbered parameter slot of a template body, e.g. {{1}}
+var templateSlotRegex = regexp.MustCompile(`\{\{(\d+)\}\}`)
+
+// TemplateParameters returns the number of parameter slots of a template body, the highest slot number
+func TemplateParameters(body string) int {
+	count := 0
+	for _, match := range templateSlotRegex.FindAllStringSubmatch(body, -1) {
+		if n, err := strconv.Atoi(match[1]); err == nil {
+			count = max(count, n)
+		}
+	}
+	return count
+}
+
+// RenderTemplate fills the parameter slots of a template body with parameters, slot {{1}} taking the first one
+func RenderTemplate(body string, parameters []string) string {
+	return templateSlotRegex.ReplaceAllStringFunc(body, func(slot string) string {
+		n, err := strconv.Atoi(slot[2 : len(slot)-2])
+		if err != nil || n < 1 || n > len(parameters) {
+			return slot
+		}
+		return parameters[n-1]
+	})
+}
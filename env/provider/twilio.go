@@ -0,0 +1,107 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// defaultTwilioURL is the Twilio REST API base used when the provider sets no url
+const defaultTwilioURL = "https://api.twilio.com"
+
+// Twilio sends messages through the Twilio Messages API
+type Twilio struct {
+	messagesURL string
+	accountSID  string
+	authToken   string
+	from        string
+	timeout     time.Duration
+	httpClient  *http.Client
+}
+
+// NewTwilio creates a new Twilio sender
+// An empty baseURL uses the public Twilio API, a zero timeout means requests are bounded only by the caller's context
+func NewTwilio(baseURL, accountSID, authToken, from string, timeout time.Duration, instance string) *Twilio {
+	if baseURL == "" {
+		baseURL = defaultTwilioURL
+	}
+
+	return &Twilio{
+		messagesURL: fmt.Sprintf("%s/2010-04-01/Accounts/%s/Messages.json", strings.TrimRight(baseURL, "/"), url.PathEscape(accountSID)),
+		accountSID:  accountSID,
+		authToken:   authToken,
+		from:        from,
+		timeout:     timeout,
+		httpClient: &http.Client{Transport: &identityTransport{
+			base:     newTransport(),
+			instance: instance,
+		}},
+	}
+}
+
+// twilioResponse is the part of the Twilio message resource and error body we use
+type twilioResponse struct {
+	SID     string `json:"sid"`
+	Message string `json:"message"`
+}
+
+// SendMessage creates a Twilio message and returns its SID
+func (t *Twilio) SendMessage(ctx context.Context, phoneNumber, content string) (string, error) {
+	if t.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, t.timeout)
+		defer cancel()
+	}
+
+	form := url.Values{
+		"To":   {phoneNumber},
+		"From": {t.from},
+		"Body": {content},
+	}.Encode()
+
+	request := fmt.Sprintf("POST %s HTTP/1.1\r\nContent-Type: application/x-www-form-urlencoded\r\nAuthorization: Basic %s\r\n\r\n%s",
+		t.messagesURL, redacted, form)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.messagesURL, strings.NewReader(form))
+	if err != nil {
+		return "", fmt.Errorf("failed to build twilio request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(t.accountSID, t.authToken)
+
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		return "", newExchangeError(fmt.Errorf("twilio call failed: %w", err), request, "", t.authToken)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxExchangeBytes))
+	if err != nil {
+		return "", newExchangeError(fmt.Errorf("failed to read twilio response: %w", err), request, "", t.authToken)
+	}
+
+	response := fmt.Sprintf("HTTP/1.1 %s\r\nContent-Type: %s\r\n\r\n%s", resp.Status, resp.Header.Get("Content-Type"), body)
+
+	var parsed twilioResponse
+	_ = json.Unmarshal(body, &parsed)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		reason := parsed.Message
+		if reason == "" {
+			reason = resp.Status
+		}
+		return "", newExchangeError(fmt.Errorf("twilio call failed: %s", reason), request, response, t.authToken).
+			withStatus(resp.StatusCode).withRetryAfter(resp.Header.Get("Retry-After"))
+	}
+
+	if parsed.SID == "" {
+		return "", newExchangeError(fmt.Errorf("twilio response carries no message sid"), request, response, t.authToken)
+	}
+
+	return parsed.SID, nil
+}
@@ -0,0 +1,178 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// warmUpTimeout bounds a single warm-up round
+const warmUpTimeout = 10 * time.Second
+
+// WarmUpStatus describes the outcome of the latest connection warm-up
+type WarmUpStatus struct {
+	Host        string
+	Addresses   []string
+	WarmedAt    *time.Time
+	Duration    time.Duration
+	Error       *string
+	LastUsedAt  *time.Time
+	KeepWarmFor time.Duration
+}
+
+// newTransport creates an HTTP transport that keeps provider connections open between ticks
+func newTransport() *http.Transport {
+	return &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   10 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		ForceAttemptHTTP2:   true,
+		MaxIdleConns:        20,
+		MaxIdleConnsPerHost: 10,
+		IdleConnTimeout:     5 * time.Minute,
+		TLSHandshakeTimeout: 10 * time.Second,
+	}
+}
+
+// WarmUp pre-resolves the provider host and opens a connection so the next send can reuse it
+func (c *Webhook) WarmUp(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, warmUpTimeout)
+	defer cancel()
+
+	start := time.Now()
+	addresses, err := c.warmUp(ctx)
+	duration := time.Since(start)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.warmUpStatus.Addresses = addresses
+	c.warmUpStatus.Duration = duration
+	c.warmUpStatus.Error = nil
+	if err != nil {
+		errMsg := err.Error()
+		c.warmUpStatus.Error = &errMsg
+		return err
+	}
+
+	warmedAt := time.Now()
+	c.warmUpStatus.WarmedAt = &warmedAt
+
+	return nil
+}
+
+// Probe checks that the webhook endpoint resolves and answers, without touching the warm-up status
+func (c *Webhook) Probe(ctx context.Context) error {
+	_, err := c.warmUp(ctx)
+	return err
+}
+
+// warmUp resolves the host and issues a HEAD request, any HTTP response counts as a warm connection
+func (c *Webhook) warmUp(ctx context.Context) ([]string, error) {
+	webhookURL, _ := c.endpoint()
+	u, err := url.Parse(webhookURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid webhook URL: %w", err)
+	}
+
+	addresses, err := net.DefaultResolver.LookupHost(ctx, u.Hostname())
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", u.Hostname(), err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, webhookURL, nil)
+	if err != nil {
+		return addresses, fmt.Errorf("failed to build warm-up request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return addresses, fmt.Errorf("failed to connect to %s: %w", u.Host, err)
+	}
+
+	// Drain the body so the connection goes back to the idle pool
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+
+	return addresses, nil
+}
+
+// KeepWarm warms the connection immediately and again whenever it was idle for longer than idle
+// It blocks until ctx is cancelled; a zero idle only performs the initial warm-up
+func (c *Webhook) KeepWarm(ctx context.Context, idle time.Duration) {
+	c.mu.Lock()
+	c.warmUpStatus.KeepWarmFor = idle
+	c.mu.Unlock()
+
+	if err := c.WarmUp(ctx); err != nil {
+		log.Printf("Warning: webhook warm-up failed: %v", err)
+	} else {
+		log.Println("✓ Webhook connection warmed up")
+	}
+
+	if idle <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(idle / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if c.idleFor() < idle {
+				continue
+			}
+			if err := c.WarmUp(ctx); err != nil {
+				log.Printf("Warning: webhook warm-up failed: %v", err)
+			}
+
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// WarmUpStatus returns the outcome of the latest warm-up
+func (c *Webhook) WarmUpStatus() WarmUpStatus {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	status := c.warmUpStatus
+	status.Addresses = append([]string(nil), c.warmUpStatus.Addresses...)
+
+	return status
+}
+
+// idleFor returns how long the provider connection has not been used by a send or warm-up
+func (c *Webhook) idleFor() time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	last := c.warmUpStatus.LastUsedAt
+	if c.warmUpStatus.WarmedAt != nil && (last == nil || c.warmUpStatus.WarmedAt.After(*last)) {
+		last = c.warmUpStatus.WarmedAt
+	}
+
+	if last == nil {
+		return time.Duration(1<<63 - 1)
+	}
+
+	return time.Since(*last)
+}
+
+// markUsed records that the provider connection was just used
+func (c *Webhook) markUsed() {
+	now := time.Now()
+
+	c.mu.Lock()
+	c.warmUpStatus.LastUsedAt = &now
+	c.mu.Unlock()
+}
@@ -0,0 +1,130 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"qubit/pkg/buildinfo"
+
+	"github.com/google/uuid"
+)
+
+// fakeURLPrefix is prepended to the provider name for the URL of its simulated sender in fake mode
+const fakeURLPrefix = "fake://"
+
+// Webhook sends messages to a generic webhook provider (fake implementation for testing)
+type Webhook struct {
+	webhookURL     string
+	webhookAuthKey string
+	timeout        time.Duration
+	instance       string
+	httpClient     *http.Client
+
+	mu           sync.RWMutex
+	warmUpStatus WarmUpStatus
+}
+
+// NewWebhook creates a new webhook sender
+// A zero timeout means requests are bounded only by the caller's context
+// Every request carries the User-Agent and X-Qubit-Instance identification headers
+func NewWebhook(webhookURL, webhookAuthKey string, timeout time.Duration, instance string) *Webhook {
+	c := &Webhook{
+		webhookURL:     webhookURL,
+		webhookAuthKey: webhookAuthKey,
+		timeout:        timeout,
+		instance:       instance,
+		httpClient: &http.Client{Transport: &identityTransport{
+			base:     newTransport(),
+			instance: instance,
+		}},
+	}
+
+	if u, err := url.Parse(webhookURL); err == nil {
+		c.warmUpStatus.Host = u.Host
+	}
+
+	return c
+}
+
+// SetEndpoint points the webhook at another URL and auth key, e.g. after a configuration reload
+// Sends in flight finish with the previous endpoint; open connections to the previous host idle out
+func (c *Webhook) SetEndpoint(webhookURL, webhookAuthKey string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.webhookURL = webhookURL
+	c.webhookAuthKey = webhookAuthKey
+	c.warmUpStatus = WarmUpStatus{KeepWarmFor: c.warmUpStatus.KeepWarmFor, LastUsedAt: c.warmUpStatus.LastUsedAt}
+	if u, err := url.Parse(webhookURL); err == nil {
+		c.warmUpStatus.Host = u.Host
+	}
+}
+
+// endpoint returns the URL and auth key the webhook currently sends to
+func (c *Webhook) endpoint() (string, string) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.webhookURL, c.webhookAuthKey
+}
+
+// SendMessage sends a message via the webhook (simulated)
+// The message metadata carried by ctx is sent along, see WithMetadata
+// Waits 0-5 seconds and fails 20% of requests
+func (c *Webhook) SendMessage(ctx context.Context, phoneNumber, content string) (string, error) {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	c.markUsed()
+
+	webhookURL, authKey := c.endpoint()
+	request := c.rawRequest(webhookURL, phoneNumber, content, metadataFrom(ctx))
+
+	// Random timeout between 0 and 5 seconds
+	timeoutDuration := time.Duration(rand.Intn(5000)) * time.Millisecond
+
+	// Simulate the timeout
+	select {
+	case <-time.After(timeoutDuration):
+		// Continue after timeout
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return "", newExchangeError(fmt.Errorf("webhook call timed out: %w", ctx.Err()), request, "", authKey)
+		}
+		return "", newExchangeError(fmt.Errorf("webhook call cancelled: %w", ctx.Err()), request, "", authKey)
+	}
+
+	// 20% chance of failure
+	if rand.Intn(100) < 20 {
+		response := "HTTP/1.1 500 Internal Server Error\r\nContent-Type: application/json\r\n\r\n" +
+			`{"message":"random failure occurred"}`
+		return "", newExchangeError(fmt.Errorf("webhook call failed: random failure occurred"), request, response, authKey).withStatus(http.StatusInternalServerError)
+	}
+
+	// Return success with UUID
+	return uuid.New().String(), nil
+}
+
+// rawRequest renders the HTTP request that would be sent to the provider
+func (c *Webhook) rawRequest(webhookURL, phoneNumber, content string, metadata map[string]string) string {
+	payload := map[string]any{
+		"to":      phoneNumber,
+		"content": content,
+	}
+	if len(metadata) > 0 {
+		payload["metadata"] = metadata
+	}
+	body, _ := json.Marshal(payload)
+
+	return fmt.Sprintf("POST %s HTTP/1.1\r\nContent-Type: application/json\r\nUser-Agent: %s\r\n%s: %s\r\n%s: %s\r\n\r\n%s",
+		webhookURL, buildinfo.UserAgent(), InstanceHeader, c.instance, authHeader, redacted, body)
+}
@@ -0,0 +1,246 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// defaultWhatsAppURL is the WhatsApp Cloud API base used when the provider sets no url
+const defaultWhatsAppURL = "https://graph.facebook.com/v19.0"
+
+// maxTemplatePages bounds the pages followed when listing templates
+const maxTemplatePages = 50
+
+// WhatsApp sends pre-approved templates through the WhatsApp Cloud API
+// Free-form content is rejected, a business may only start a conversation with an approved template
+type WhatsApp struct {
+	messagesURL  string
+	templatesURL string
+	accessToken  string
+	timeout      time.Duration
+	httpClient   *http.Client
+}
+
+// NewWhatsApp creates a new WhatsApp sender
+// accountID is the business account whose templates are listed, phoneNumberID the sender number messages go out from
+// An empty baseURL uses the public Cloud API, a zero timeout means requests are bounded only by the caller's context
+func NewWhatsApp(baseURL, accountID, accessToken, phoneNumberID string, timeout time.Duration, instance string) *WhatsApp {
+	if baseURL == "" {
+		baseURL = defaultWhatsAppURL
+	}
+	baseURL = strings.TrimRight(baseURL, "/")
+
+	return &WhatsApp{
+		messagesURL:  fmt.Sprintf("%s/%s/messages", baseURL, url.PathEscape(phoneNumberID)),
+		templatesURL: fmt.Sprintf("%s/%s/message_templates?limit=100", baseURL, url.PathEscape(accountID)),
+		accessToken:  accessToken,
+		timeout:      timeout,
+		httpClient: &http.Client{Transport: &identityTransport{
+			base:     newTransport(),
+			instance: instance,
+		}},
+	}
+}
+
+// whatsAppParameter is a text value of a template parameter slot
+type whatsAppParameter struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// whatsAppComponent is a part of a template, the body carries the parameters
+type whatsAppComponent struct {
+	Type       string              `json:"type"`
+	Text       string              `json:"text,omitempty"`
+	Parameters []whatsAppParameter `json:"parameters,omitempty"`
+}
+
+// whatsAppTemplateRequest is the template message sent to the Cloud API
+type whatsAppTemplateRequest struct {
+	MessagingProduct string `json:"messaging_product"`
+	To               string `json:"to"`
+	Type             string `json:"type"`
+	Template         struct {
+		Name     string `json:"name"`
+		Language struct {
+			Code string `json:"code"`
+		} `json:"language"`
+		Components []whatsAppComponent `json:"components,omitempty"`
+	} `json:"template"`
+}
+
+// whatsAppResponse is the part of the Cloud API message and error bodies we use
+type whatsAppResponse struct {
+	Messages []struct {
+		ID string `json:"id"`
+	} `json:"messages"`
+	Error struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// whatsAppTemplatesResponse is a page of the message templates of a business account
+type whatsAppTemplatesResponse struct {
+	Data []struct {
+		ID         string              `json:"id"`
+		Name       string              `json:"name"`
+		Language   string              `json:"language"`
+		Status     string              `json:"status"`
+		Components []whatsAppComponent `json:"components"`
+	} `json:"data"`
+	Paging struct {
+		Next string `json:"next"`
+	} `json:"paging"`
+	Error struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// SendMessage rejects free-form content, WhatsApp messages are sent with SendTemplate
+func (w *WhatsApp) SendMessage(ctx context.Context, phoneNumber, content string) (string, error) {
+	return "", fmt.Errorf("whatsapp: %w", ErrTemplateRequired)
+}
+
+// SendTemplate sends an approved template filled in with its parameters and returns the WhatsApp message ID
+func (w *WhatsApp) SendTemplate(ctx context.Context, phoneNumber string, tmpl TemplateMessage) (string, error) {
+	if w.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, w.timeout)
+		defer cancel()
+	}
+
+	var payload whatsAppTemplateRequest
+	payload.MessagingProduct = "whatsapp"
+	payload.To = strings.TrimPrefix(phoneNumber, "+")
+	payload.Type = "template"
+	payload.Template.Name = tmpl.Name
+	payload.Template.Language.Code = tmpl.Language
+	if len(tmpl.Parameters) > 0 {
+		body := whatsAppComponent{Type: "body"}
+		for _, value := range tmpl.Parameters {
+			body.Parameters = append(body.Parameters, whatsAppParameter{Type: "text", Text: value})
+		}
+		payload.Template.Components = []whatsAppComponent{body}
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode whatsapp request: %w", err)
+	}
+
+	request := fmt.Sprintf("POST %s HTTP/1.1\r\nContent-Type: application/json\r\nAuthorization: Bearer %s\r\n\r\n%s",
+		w.messagesURL, redacted, data)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.messagesURL, bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("failed to build whatsapp request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+w.accessToken)
+
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		return "", newExchangeError(fmt.Errorf("whatsapp call failed: %w", err), request, "", w.accessToken)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxExchangeBytes))
+	if err != nil {
+		return "", newExchangeError(fmt.Errorf("failed to read whatsapp response: %w", err), request, "", w.accessToken)
+	}
+
+	response := fmt.Sprintf("HTTP/1.1 %s\r\nContent-Type: %s\r\n\r\n%s", resp.Status, resp.Header.Get("Content-Type"), body)
+
+	var parsed whatsAppResponse
+	_ = json.Unmarshal(body, &parsed)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		reason := parsed.Error.Message
+		if reason == "" {
+			reason = resp.Status
+		}
+		return "", newExchangeError(fmt.Errorf("whatsapp call failed: %s", reason), request, response, w.accessToken).
+			withStatus(resp.StatusCode).withRetryAfter(resp.Header.Get("Retry-After"))
+	}
+
+	if len(parsed.Messages) == 0 || parsed.Messages[0].ID == "" {
+		return "", newExchangeError(fmt.Errorf("whatsapp response carries no message id"), request, response, w.accessToken)
+	}
+
+	return parsed.Messages[0].ID, nil
+}
+
+// ListTemplates returns the message templates of the business account with their approval status
+func (w *WhatsApp) ListTemplates(ctx context.Context) ([]Template, error) {
+	if w.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, w.timeout)
+		defer cancel()
+	}
+
+	var templates []Template
+	next := w.templatesURL
+	for page := 0; next != "" && page < maxTemplatePages; page++ {
+		parsed, err := w.templatesPage(ctx, next)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, t := range parsed.Data {
+			template := Template{
+				ID:       t.ID,
+				Name:     t.Name,
+				Language: t.Language,
+				Status:   strings.ToLower(t.Status),
+			}
+			for _, component := range t.Components {
+				if strings.EqualFold(component.Type, "body") {
+					template.Body = component.Text
+				}
+			}
+			template.Parameters = TemplateParameters(template.Body)
+			templates = append(templates, template)
+		}
+
+		next = parsed.Paging.Next
+	}
+
+	return templates, nil
+}
+
+// templatesPage fetches a single page of templates
+func (w *WhatsApp) templatesPage(ctx context.Context, pageURL string) (*whatsAppTemplatesResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build whatsapp request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+w.accessToken)
+
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("whatsapp template listing failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var parsed whatsAppTemplatesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil && resp.StatusCode < 300 {
+		return nil, fmt.Errorf("failed to decode whatsapp templates: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		reason := parsed.Error.Message
+		if reason == "" {
+			reason = resp.Status
+		}
+		return nil, fmt.Errorf("whatsapp template listing failed: %s", reason)
+	}
+
+	return &parsed, nil
+}
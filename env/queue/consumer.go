@@ -0,0 +1,88 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Supported brokers
+const (
+	BrokerKafka    = "kafka"
+	BrokerRabbitMQ = "rabbitmq"
+)
+
+// retryDelay is the pause before a delivery that failed with a transient error is handled again
+const retryDelay = 5 * time.Second
+
+// Delivery is a message creation request read from the broker
+type Delivery struct {
+	Body   []byte
+	Source string // where the delivery came from, e.g. topic/partition@offset, for logs
+}
+
+// Handler ingests one delivery
+// Returning nil acknowledges the delivery, a Permanent error drops it (RabbitMQ dead-letters it)
+// and any other error hands it to the handler again after a pause, e.g. while the database is down
+type Handler func(ctx context.Context, d Delivery) error
+
+// Consumer feeds deliveries of a broker to a handler
+type Consumer interface {
+	// Run consumes until ctx is cancelled or the connection fails, it may be called again after an error
+	Run(ctx context.Context, handle Handler) error
+	Close() error
+}
+
+// Config selects and configures the broker message creation requests are consumed from
+type Config struct {
+	Broker   string   // kafka or rabbitmq
+	URLs     []string // Kafka bootstrap brokers or the RabbitMQ URL
+	Topic    string   // Kafka topic or RabbitMQ queue
+	GroupID  string   // Kafka consumer group, RabbitMQ consumer tag
+	Prefetch int      // RabbitMQ deliveries handled concurrently
+}
+
+// NewConsumer creates a consumer for the configured broker
+func NewConsumer(cfg Config) (Consumer, error) {
+	switch cfg.Broker {
+	case BrokerKafka:
+		return newKafkaConsumer(cfg), nil
+	case BrokerRabbitMQ:
+		return newRabbitMQConsumer(cfg), nil
+	default:
+		return nil, fmt.Errorf("unknown queue broker %q, expected %s or %s", cfg.Broker, BrokerKafka, BrokerRabbitMQ)
+	}
+}
+
+// permanentError marks a delivery that can never be ingested
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent marks err as not worth retrying, e.g. an invalid request
+func Permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+// IsPermanent reports whether err was marked with Permanent
+func IsPermanent(err error) bool {
+	var permanent *permanentError
+	return errors.As(err, &permanent)
+}
+
+// sleep pauses for d unless ctx is cancelled first
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
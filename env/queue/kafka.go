@@ -0,0 +1,71 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// kafkaConsumer reads a topic as a member of a consumer group
+// Deliveries of a partition are handled in order; offsets are committed once a delivery was handled,
+// so a crash redelivers at most the deliveries of the last commit interval
+type kafkaConsumer struct {
+	reader *kafka.Reader
+}
+
+// newKafkaConsumer creates the group reader, it connects lazily on the first fetch
+func newKafkaConsumer(cfg Config) *kafkaConsumer {
+	return &kafkaConsumer{
+		reader: kafka.NewReader(kafka.ReaderConfig{
+			Brokers:        cfg.URLs,
+			GroupID:        cfg.GroupID,
+			Topic:          cfg.Topic,
+			MaxBytes:       10e6,
+			CommitInterval: time.Second,
+		}),
+	}
+}
+
+// Run fetches deliveries and hands them to handle one at a time
+// A delivery failing with a transient error is retried until it succeeds, holding back its partition
+func (c *kafkaConsumer) Run(ctx context.Context, handle Handler) error {
+	for {
+		msg, err := c.reader.FetchMessage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to fetch from kafka: %w", err)
+		}
+
+		d := Delivery{
+			Body:   msg.Value,
+			Source: fmt.Sprintf("%s/%d@%d", msg.Topic, msg.Partition, msg.Offset),
+		}
+
+		for {
+			err := handle(ctx, d)
+			if err == nil {
+				break
+			}
+			if IsPermanent(err) {
+				log.Printf("⚠ Dropping queued request %s: %v", d.Source, err)
+				break
+			}
+
+			log.Printf("Warning: failed to ingest queued request %s, retrying in %s: %v", d.Source, retryDelay, err)
+			if err := sleep(ctx, retryDelay); err != nil {
+				return err
+			}
+		}
+
+		if err := c.reader.CommitMessages(ctx, msg); err != nil {
+			return fmt.Errorf("failed to commit kafka offset: %w", err)
+		}
+	}
+}
+
+// Close leaves the consumer group and commits the pending offsets
+func (c *kafkaConsumer) Close() error {
+	return c.reader.Close()
+}
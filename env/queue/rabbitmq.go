@@ -0,0 +1,125 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// rabbitMQConsumer consumes a queue with manual acknowledgements
+// Up to Prefetch deliveries are handled concurrently, so their order is not preserved
+type rabbitMQConsumer struct {
+	url      string
+	queue    string
+	tag      string
+	prefetch int
+
+	mu   sync.Mutex
+	conn *amqp.Connection
+}
+
+// newRabbitMQConsumer creates the consumer, it connects on every Run
+func newRabbitMQConsumer(cfg Config) *rabbitMQConsumer {
+	return &rabbitMQConsumer{
+		url:      cfg.URLs[0],
+		queue:    cfg.Topic,
+		tag:      cfg.GroupID,
+		prefetch: max(cfg.Prefetch, 1),
+	}
+}
+
+// Run connects, consumes the queue and returns once ctx is cancelled or the connection is lost
+// Deliveries failing with a transient error are requeued after a pause, permanent failures are
+// rejected without requeueing, which dead-letters them when the queue has a dead letter exchange
+func (c *rabbitMQConsumer) Run(ctx context.Context, handle Handler) error {
+	conn, err := amqp.Dial(c.url)
+	if err != nil {
+		return fmt.Errorf("failed to connect to RabbitMQ: %w", err)
+	}
+	c.mu.Lock()
+	c.conn = conn
+	c.mu.Unlock()
+	defer conn.Close()
+
+	ch, err := conn.Channel()
+	if err != nil {
+		return fmt.Errorf("failed to open RabbitMQ channel: %w", err)
+	}
+	defer ch.Close()
+
+	if err := ch.Qos(c.prefetch, 0, false); err != nil {
+		return fmt.Errorf("failed to set RabbitMQ prefetch: %w", err)
+	}
+
+	deliveries, err := ch.Consume(c.queue, c.tag, false, false, false, false, nil)
+	if err != nil {
+		return fmt.Errorf("failed to consume RabbitMQ queue %s: %w", c.queue, err)
+	}
+
+	log.Printf("✓ Consuming RabbitMQ queue %s (prefetch: %d)", c.queue, c.prefetch)
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	// Every worker takes deliveries from the same channel, the prefetch bounds how many are in flight
+	for range c.prefetch {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for d := range deliveries {
+				c.handle(ctx, handle, d)
+			}
+		}()
+	}
+
+	closed := conn.NotifyClose(make(chan *amqp.Error, 1))
+	select {
+	case <-ctx.Done():
+		_ = ch.Cancel(c.tag, false)
+		return ctx.Err()
+	case amqpErr := <-closed:
+		if amqpErr == nil {
+			return fmt.Errorf("RabbitMQ connection closed")
+		}
+		return fmt.Errorf("RabbitMQ connection lost: %w", amqpErr)
+	}
+}
+
+// handle ingests one delivery and acknowledges, requeues or rejects it
+func (c *rabbitMQConsumer) handle(ctx context.Context, handle Handler, d amqp.Delivery) {
+	source := fmt.Sprintf("%s#%d", c.queue, d.DeliveryTag)
+
+	err := handle(ctx, Delivery{Body: d.Body, Source: source})
+	switch {
+	case err == nil:
+		if ackErr := d.Ack(false); ackErr != nil {
+			log.Printf("Warning: failed to acknowledge queued request %s: %v", source, ackErr)
+		}
+	case IsPermanent(err):
+		log.Printf("⚠ Rejecting queued request %s: %v", source, err)
+		if nackErr := d.Nack(false, false); nackErr != nil {
+			log.Printf("Warning: failed to reject queued request %s: %v", source, nackErr)
+		}
+	default:
+		log.Printf("Warning: failed to ingest queued request %s, requeueing in %s: %v", source, retryDelay, err)
+		// Requeueing right away would spin on the same delivery while e.g. the database is down
+		_ = sleep(ctx, retryDelay)
+		if nackErr := d.Nack(false, true); nackErr != nil {
+			log.Printf("Warning: failed to requeue queued request %s: %v", source, nackErr)
+		}
+	}
+}
+
+// Close closes the current connection, ending a running Run
+func (c *rabbitMQConsumer) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == nil || c.conn.IsClosed() {
+		return nil
+	}
+	return c.conn.Close()
+}
@@ -0,0 +1,107 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// ErrCircuitOpen is returned without calling Redis while its circuit is open
+var ErrCircuitOpen = errors.New("redis circuit is open")
+
+// Resilience bounds the delivery cache and rate limit calls, so a slow Redis cannot stall sending or the API
+// Leader leases are not covered, they have their own TTL
+type Resilience struct {
+	Timeout   time.Duration // deadline of a single call, 0 keeps the client defaults
+	Threshold int           // consecutive failures opening the circuit, 0 disables the breaker
+	OpenFor   time.Duration // how long the circuit stays open before a single probe is let through
+}
+
+// breaker is a circuit breaker around the Redis calls of a client
+// After Threshold consecutive failures calls fail fast with ErrCircuitOpen; once OpenFor has passed,
+// one probe at a time is let through and the first one that succeeds closes the circuit
+type breaker struct {
+	settings Resilience
+
+	mu       sync.Mutex
+	open     bool
+	probing  bool
+	failures int
+	openedAt time.Time
+}
+
+// do runs call with the call timeout unless the circuit is open
+// A missing key and a caller giving up are not failures of Redis
+func (b *breaker) do(ctx context.Context, call func(ctx context.Context) error) error {
+	if !b.allow() {
+		return ErrCircuitOpen
+	}
+
+	if b.settings.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, b.settings.Timeout)
+		defer cancel()
+	}
+
+	err := call(ctx)
+	if !errors.Is(err, context.Canceled) {
+		b.record(err == nil || errors.Is(err, goredis.Nil))
+	}
+
+	return err
+}
+
+// allow decides whether a call may go through, letting a single probe through an expired open circuit
+func (b *breaker) allow() bool {
+	if b.settings.Threshold <= 0 {
+		return true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.open {
+		return true
+	}
+	if b.probing || time.Since(b.openedAt) < b.settings.OpenFor {
+		return false
+	}
+
+	b.probing = true
+	return true
+}
+
+// record updates the circuit with the result of a call
+func (b *breaker) record(ok bool) {
+	if b.settings.Threshold <= 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	wasOpen := b.open
+	b.probing = false
+
+	if ok {
+		b.failures = 0
+		b.open = false
+		if wasOpen {
+			log.Println("Redis circuit closed, delivery cache and shared rate limits are back")
+		}
+		return
+	}
+
+	b.failures++
+	if wasOpen || b.failures >= b.settings.Threshold {
+		b.open = true
+		b.openedAt = time.Now()
+		if !wasOpen {
+			log.Printf("⚠ Redis circuit opened after %d consecutive failures, bypassing the delivery cache and shared rate limits for %s", b.failures, b.settings.OpenFor)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// deliveryKeyPrefix namespaces delivery cache keys
+const deliveryKeyPrefix = "qubit:delivery:"
+
+// Delivery holds the cached delivery data of a sent message
+type Delivery struct {
+	MessageID string
+	SentAt    time.Time
+}
+
+// Client wraps the Redis connection used as a delivery cache
+type Client struct {
+	rdb     *goredis.Client
+	ttl     time.Duration
+	breaker *breaker
+}
+
+// NewClient creates a new Redis client and verifies the connection
+func NewClient(ctx context.Context, redisURL string, ttl time.Duration, resilience Resilience) (*Client, error) {
+	opts, err := goredis.ParseURL(redisURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse redis URL: %w", err)
+	}
+
+	rdb := goredis.NewClient(opts)
+
+	if err := rdb.Ping(ctx).Err(); err != nil {
+		_ = rdb.Close()
+		return nil, fmt.Errorf("failed to ping redis: %w", err)
+	}
+
+	log.Println("✓ Redis connection established successfully")
+
+	return &Client{
+		rdb:     rdb,
+		ttl:     ttl,
+		breaker: &breaker{settings: resilience},
+	}, nil
+}
+
+// SetDelivery caches the delivery data of a sent message
+// Returns ErrCircuitOpen without calling Redis while its circuit is open, as does GetDelivery
+func (c *Client) SetDelivery(ctx context.Context, id int64, delivery Delivery) error {
+	key := deliveryKey(id)
+
+	err := c.breaker.do(ctx, func(ctx context.Context) error {
+		pipe := c.rdb.TxPipeline()
+		pipe.HSet(ctx, key,
+			"messageId", delivery.MessageID,
+			"sentAt", delivery.SentAt.UTC().Format(time.RFC3339Nano),
+		)
+		pipe.Expire(ctx, key, c.ttl)
+
+		_, err := pipe.Exec(ctx)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to cache delivery: %w", err)
+	}
+
+	return nil
+}
+
+// GetDelivery reads the cached delivery data of a message
+// Returns nil if the message is not cached
+func (c *Client) GetDelivery(ctx context.Context, id int64) (*Delivery, error) {
+	var values map[string]string
+	err := c.breaker.do(ctx, func(ctx context.Context) (err error) {
+		values, err = c.rdb.HGetAll(ctx, deliveryKey(id)).Result()
+		return err
+	})
+	if errors.Is(err, goredis.Nil) || (err == nil && len(values) == 0) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read delivery from cache: %w", err)
+	}
+
+	sentAt, err := time.Parse(time.RFC3339Nano, values["sentAt"])
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse cached sentAt: %w", err)
+	}
+
+	return &Delivery{
+		MessageID: values["messageId"],
+		SentAt:    sentAt,
+	}, nil
+}
+
+// Close gracefully closes the Redis connection
+func (c *Client) Close() {
+	if c.rdb != nil {
+		if err := c.rdb.Close(); err != nil {
+			log.Printf("Warning: failed to close redis connection: %v", err)
+			return
+		}
+		log.Println("✓ Redis connection closed")
+	}
+}
+
+// deliveryKey builds the cache key for a message ID
+func deliveryKey(id int64) string {
+	return deliveryKeyPrefix + strconv.FormatInt(id, 10)
+}
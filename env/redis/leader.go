@@ -0,0 +1,77 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// leaderKeyPrefix namespaces leader leases
+const leaderKeyPrefix = "qubit:leader:"
+
+// renewLeaseScript extends a lease still held by the caller
+// KEYS[1] lease, ARGV[1] holder, ARGV[2] TTL in milliseconds
+// Returns 1 when the lease was renewed, 0 when it expired or belongs to another holder
+var renewLeaseScript = goredis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// releaseLeaseScript deletes a lease still held by the caller
+// KEYS[1] lease, ARGV[1] holder
+var releaseLeaseScript = goredis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// LeaderLease is leadership held as an expiring key naming the leader
+// A dead leader stops renewing, so another instance takes over once the TTL elapsed
+type LeaderLease struct {
+	client *Client
+	key    string
+	holder string
+	ttl    time.Duration
+}
+
+// NewLeaderLease creates a lease on the named leadership, held by holder for ttl after every renewal
+func (c *Client) NewLeaderLease(name, holder string, ttl time.Duration) *LeaderLease {
+	return &LeaderLease{
+		client: c,
+		key:    leaderKeyPrefix + name,
+		holder: holder,
+		ttl:    ttl,
+	}
+}
+
+// Campaign renews the lease while holding it, otherwise takes it unless another holder has it
+func (l *LeaderLease) Campaign(ctx context.Context) (bool, error) {
+	renewed, err := renewLeaseScript.Run(ctx, l.client.rdb, []string{l.key}, l.holder, l.ttl.Milliseconds()).Int64()
+	if err != nil {
+		return false, fmt.Errorf("failed to renew leader lease: %w", err)
+	}
+	if renewed == 1 {
+		return true, nil
+	}
+
+	acquired, err := l.client.rdb.SetNX(ctx, l.key, l.holder, l.ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to take leader lease: %w", err)
+	}
+
+	return acquired, nil
+}
+
+// Resign deletes the lease so another instance can take over right away
+func (l *LeaderLease) Resign(ctx context.Context) error {
+	if err := releaseLeaseScript.Run(ctx, l.client.rdb, []string{l.key}, l.holder).Err(); err != nil {
+		return fmt.Errorf("failed to release leader lease: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	"qubit/pkg/ratelimit"
+)
+
+// rateLimitKeyPrefix namespaces rate limit buckets
+const rateLimitKeyPrefix = "qubit:ratelimit:"
+
+// takeTokenScript refills and takes a token from a bucket atomically
+// KEYS[1] bucket, ARGV[1] capacity, ARGV[2] milliseconds per token
+// Returns the milliseconds until the next token, 0 when allowed, and the whole tokens left
+var takeTokenScript = goredis.NewScript(`
+local capacity = tonumber(ARGV[1])
+local per_token = tonumber(ARGV[2])
+local now = redis.call('TIME')
+local now_ms = now[1] * 1000 + math.floor(now[2] / 1000)
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'updated')
+local tokens = tonumber(state[1]) or capacity
+local updated = tonumber(state[2]) or now_ms
+
+tokens = math.min(capacity, tokens + (now_ms - updated) / per_token)
+
+local wait = 0
+if tokens < 1 then
+	wait = math.ceil((1 - tokens) * per_token)
+else
+	tokens = tokens - 1
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'updated', now_ms)
+redis.call('PEXPIRE', KEYS[1], math.ceil(capacity * per_token))
+
+return {wait, math.floor(tokens)}
+`)
+
+// RateLimiter is a token bucket limiter shared by all instances using the same Redis
+type RateLimiter struct {
+	client *Client
+	rate   ratelimit.Rate
+}
+
+// NewRateLimiter creates a Redis backed limiter
+func (c *Client) NewRateLimiter(rate ratelimit.Rate) *RateLimiter {
+	return &RateLimiter{
+		client: c,
+		rate:   rate,
+	}
+}
+
+// Allow takes a token from the bucket of key
+// Returns ErrCircuitOpen without calling Redis while its circuit is open, the API then lets requests pass
+func (l *RateLimiter) Allow(ctx context.Context, key string) (ratelimit.Decision, error) {
+	perToken := time.Minute / time.Duration(l.rate.PerMinute)
+
+	var result []int64
+	err := l.client.breaker.do(ctx, func(ctx context.Context) (err error) {
+		result, err = takeTokenScript.Run(ctx, l.client.rdb, []string{rateLimitKeyPrefix + key},
+			l.rate.Burst, perToken.Milliseconds()).Int64Slice()
+		return err
+	})
+	if err != nil {
+		return ratelimit.Decision{}, fmt.Errorf("failed to take rate limit token: %w", err)
+	}
+	wait, remaining := result[0], int(result[1])
+
+	if wait > 0 {
+		return ratelimit.Decision{RetryAfter: time.Duration(wait) * time.Millisecond, Remaining: remaining}, nil
+	}
+
+	return ratelimit.Decision{Allowed: true, Remaining: remaining}, nil
+}
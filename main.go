@@ -4,19 +4,56 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
+	"google.golang.org/grpc"
+
 	"qubit/api"
+	"qubit/api/rpc"
+	"qubit/env/alerthook"
 	"qubit/env/config"
+	"qubit/env/events"
+	"qubit/env/mirror"
 	"qubit/env/postgres"
-	"qubit/env/webhook"
+	"qubit/env/provider"
+	"qubit/env/queue"
+	"qubit/env/redis"
+	"qubit/pkg/jsonfmt"
+	"qubit/pkg/pii"
+	"qubit/pkg/ratelimit"
+	"qubit/service/anomaly"
+	"qubit/service/apikey"
+	"qubit/service/archive"
+	"qubit/service/backlogalert"
+	"qubit/service/campaign"
+	"qubit/service/canary"
+	"qubit/service/configreload"
+	"qubit/service/errorstats"
+	"qubit/service/event"
+	"qubit/service/fakesender"
+	"qubit/service/health"
+	"qubit/service/ingest"
+	"qubit/service/leader"
+	"qubit/service/maintenance"
 	"qubit/service/message"
+	"qubit/service/providertemplate"
+	"qubit/service/replay"
+	"qubit/service/shard"
+	"qubit/service/template"
+	"qubit/service/tenant"
+	"qubit/service/watchdog"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		runMigrate(os.Args[2:])
+		return
+	}
+
 	log.Println("Starting Qubit Message Service...")
 
 	// Load configuration
@@ -27,6 +64,13 @@ func main() {
 
 	log.Println("✓ Configuration loaded")
 
+	jsonfmt.SetMilliseconds(cfg.APITimestampMillis)
+
+	// Phone numbers in log lines, e.g. of the dispatcher or of request errors, are masked from here on
+	if cfg.LogMaskPII {
+		log.SetOutput(pii.NewWriter(os.Stderr))
+	}
+
 	// Initialize context
 	ctx := context.Background()
 
@@ -37,18 +81,371 @@ func main() {
 	}
 	defer postgresClient.Close()
 
-	// Initialize webhook client
-	webhookClient := webhook.NewClient(cfg.WebhookURL, cfg.WebhookAuthKey)
+	// Apply pending migrations when enabled, otherwise the schema is managed by `qubit migrate`
+	if cfg.RunMigrations {
+		applied, err := postgresClient.Migrate(ctx, cfg.InstanceID, cfg.MigrationLockTimeout)
+		if err != nil {
+			log.Fatalf("Failed to run migrations: %v", err)
+		}
+		log.Printf("✓ Migrations up to date (%d applied)", len(applied))
+
+		seeded, err := postgresClient.Bootstrap(ctx, postgres.Seed{
+			DailyMessageQuota:  cfg.TenantDailyMessageQuota,
+			RateLimitPerMinute: cfg.TenantRateLimitPerMinute,
+		})
+		if err != nil {
+			log.Fatalf("Failed to bootstrap database: %v", err)
+		}
+		if seeded > 0 {
+			log.Printf("✓ Database bootstrapped (%d reference rows seeded)", seeded)
+		}
+	}
+
+	// Report schema drift up front instead of failing later with scan errors
+	drift, err := postgresClient.CheckSchema(ctx)
+	if err != nil {
+		log.Printf("Warning: failed to check database schema: %v", err)
+	} else if drift.HasDrift() {
+		for _, table := range drift.MissingTables {
+			log.Printf("⚠ Schema drift: missing table %s", table)
+		}
+		for _, column := range drift.MissingColumns {
+			log.Printf("⚠ Schema drift: missing column %s", column)
+		}
+		for _, wrong := range drift.WrongTypes {
+			log.Printf("⚠ Schema drift: column %s is %s, expected %s", wrong.Column, wrong.Actual, wrong.Expected)
+		}
+		for _, index := range drift.MissingIndexes {
+			log.Printf("⚠ Schema drift: missing index %s", index)
+		}
+	} else {
+		log.Println("✓ Database schema matches the migrations")
+	}
+
+	// Initialize the senders of all providers
+	webhookProviders := provider.NewRegistry(cfg.Providers, cfg.InstanceID, provider.BreakerSettings{
+		Threshold:      cfg.CircuitBreakerThreshold,
+		OpenFor:        time.Duration(cfg.CircuitBreakerOpenSeconds) * time.Second,
+		HalfOpenProbes: cfg.CircuitBreakerHalfOpenProbes,
+	})
+
+	// Pre-resolve and warm the provider connections, re-warming them after idle periods
+	warmCtx, stopWarm := context.WithCancel(ctx)
+	defer stopWarm()
+	webhookProviders.KeepWarm(warmCtx, time.Duration(cfg.WebhookKeepWarmSeconds)*time.Second)
+
+	// Initialize optional Redis delivery cache
+	var redisClient *redis.Client
+	if cfg.RedisURL != "" {
+		redisClient, err = redis.NewClient(ctx, cfg.RedisURL, time.Duration(cfg.DeliveryCacheTTLHours)*time.Hour, redis.Resilience{
+			Timeout:   cfg.RedisTimeout,
+			Threshold: cfg.RedisCircuitThreshold,
+			OpenFor:   cfg.RedisCircuitOpen,
+		})
+		if err != nil {
+			log.Fatalf("Failed to connect to Redis: %v", err)
+		}
+		defer redisClient.Close()
+	} else {
+		log.Println("Redis is not configured, delivery cache disabled")
+	}
+
+	// Initialize optional event publishing, lifecycle events are only written to the outbox when enabled
+	var publisher events.Publisher
+	if cfg.EventsBroker != "" {
+		publisher, err = events.NewPublisher(ctx, events.Config{
+			Broker:   cfg.EventsBroker,
+			URLs:     cfg.EventsURLs,
+			Topic:    cfg.EventsTopic,
+			ClientID: "qubit-" + cfg.InstanceID,
+		})
+		if err != nil {
+			log.Fatalf("Failed to connect to the event broker: %v", err)
+		}
+	} else {
+		log.Println("Event broker is not configured, event publishing disabled")
+	}
 
 	log.Println("✓ Environment initialized")
 
 	// Initialize services
-	messageService := message.NewService(postgresClient, webhookClient, cfg.SchedulerIntervalMinutes, cfg.MessageBatchSize)
+	maintenanceService := maintenance.NewService(postgresClient)
+
+	// Switch the providers to the simulated sender before the scheduler can send anything
+	fakeSenderService := fakesender.NewService(postgresClient, webhookProviders, cfg.FakeSender, cfg.FakeSenderLocked)
+
+	retryPolicy := message.RetryPolicy{
+		MaxRetries: cfg.MaxRetries,
+		BaseDelay:  time.Duration(cfg.RetryBaseDelaySeconds) * time.Second,
+		MaxDelay:   time.Duration(cfg.RetryMaxDelaySeconds) * time.Second,
+	}
+	recipientLimit := message.RecipientLimit{
+		Max:    cfg.RecipientLimitMax,
+		Window: time.Duration(cfg.RecipientLimitWindowMinutes) * time.Minute,
+		Reject: cfg.RecipientLimitAction == "reject",
+	}
+	urlPolicy := message.URLPolicy{
+		Domains:    cfg.URLAllowlist,
+		Quarantine: cfg.URLAllowlistAction == "quarantine",
+		Recheck:    cfg.URLAllowlistRecheck != "off",
+
+		QuarantineAtSend: cfg.URLAllowlistRecheck == "quarantine",
+	}
+	replyWindow := time.Duration(cfg.ReplyWindowMinutes) * time.Minute
+
+	// Elect a single instance to run the scheduler, renewing the leadership every third of its TTL
+	var leaderService *leader.Service
+	var leadership message.Leadership
+	if cfg.SchedulerLeaderElection != "" {
+		var elector leader.Elector
+		switch cfg.SchedulerLeaderElection {
+		case leader.BackendRedis:
+			elector = redisClient.NewLeaderLease("scheduler", cfg.InstanceID, cfg.SchedulerLeaderTTL)
+		default:
+			elector = postgresClient.NewLeaderLock(postgres.SchedulerLeaderKey)
+		}
+		leaderService = leader.NewService(elector, cfg.SchedulerLeaderElection, cfg.SchedulerLeaderTTL/3)
+		leadership = leaderService
+	}
+
+	// Split the queue into shards so each instance claims a disjoint slice of it
+	var shardService *shard.Service
+	var sharding message.Sharding
+	if cfg.SchedulerShards != "" {
+		if cfg.SchedulerShards == shard.ModeAuto {
+			shardService = shard.NewAuto(postgresClient.Members, cfg.InstanceID, cfg.SchedulerShardTTL)
+		} else {
+			// Validated with the configuration
+			ranges, _ := shard.ParseRanges(cfg.SchedulerShards)
+			shardService = shard.NewStatic(ranges)
+		}
+		sharding = shardService
+	}
+
+	// Errors of the API and the send pipeline, summarized by GET /api/v1/errors/summary
+	errorStats := errorstats.NewService()
+
+	// Tenants are loaded first, their default metadata is merged into the messages they create
+	tenantService := tenant.NewService(postgresClient, tenant.Quotas{
+		DailyMessages:      cfg.TenantDailyMessageQuota,
+		RateLimitPerMinute: cfg.TenantRateLimitPerMinute,
+	}, cfg.CacheRefreshInterval)
+
+	messageService := message.NewService(message.Deps{
+		Repo:          message.NewPostgresRepository(postgresClient),
+		Providers:     webhookProviders,
+		DeliveryCache: redisClient,
+		Maintenance:   maintenanceService,
+		Leadership:    leadership,
+		Sharding:      sharding,
+		ErrorStats:    errorStats,
+		Tenants:       tenantService,
+	}, message.Options{
+		Interval:          cfg.SchedulerInterval,
+		Cron:              cfg.SchedulerCron,
+		BatchSize:         cfg.MessageBatchSize,
+		DispatchWorkers:   cfg.DispatchWorkers,
+		SendRatePerSecond: cfg.SendRatePerSecond,
+		PersistChunkSize:  cfg.PersistChunkSize,
+		SendingTimeout:    time.Duration(cfg.SendingTimeoutMinutes) * time.Minute,
+		WebhookTimeout:    cfg.WebhookTimeout,
+		InstanceID:        cfg.InstanceID,
+		RetryPolicy:       retryPolicy,
+		RecipientLimit:    recipientLimit,
+		URLPolicy:         urlPolicy,
+		ReplyWindow:       replyWindow,
+		LocaleFallback:    cfg.LocaleFallback,
+		PublishEvents:     publisher != nil,
+		TickLock:          cfg.SchedulerTickLock,
+		IdleMaxInterval:   cfg.SchedulerIdleMaxInterval,
+		NotifyDispatch:    cfg.SchedulerNotifyDispatch,
+		RunRetention:      cfg.SchedulerRunRetention,
+	})
+
+	campaignService := campaign.NewService(postgresClient, cfg.CampaignLaunchIntervalMinutes, maintenanceService)
+
+	apiKeyService := apikey.NewService(postgresClient, cfg.AdminAPIKey)
+
+	templateService := template.NewService(postgresClient)
+
+	// Reload the caches of changed tables right away instead of waiting for their refresh interval
+	listenCtx, stopListening := context.WithCancel(ctx)
+	defer stopListening()
+	postgresClient.Listen(listenCtx, postgres.CacheChannel, func(table string) {
+		// An empty table follows a reconnect, changes may have been missed meanwhile
+		if table == "" || table == "tenants" {
+			tenantService.InvalidateCache()
+		}
+	})
+
+	// Messages inserted by any instance or campaign return an idle scheduler to its base interval
+	// and, with immediate dispatch, are processed right away; the periodic tick remains the safety net
+	if cfg.SchedulerIdleMaxInterval > 0 || cfg.SchedulerNotifyDispatch {
+		postgresClient.Listen(listenCtx, postgres.MessagesChannel, func(string) {
+			messageService.WakeScheduler()
+		})
+	}
+
+	var eventService *event.Service
+	if publisher != nil {
+		eventService = event.NewService(postgresClient, publisher, event.Settings{
+			Interval:  cfg.EventsRelayInterval,
+			BatchSize: cfg.EventsBatchSize,
+			Retention: time.Duration(cfg.EventsRetentionHours) * time.Hour,
+		})
+	}
+
+	// Sync the templates of template-only providers such as WhatsApp and their approval status
+	providerTemplateService := providertemplate.NewService(postgresClient, webhookProviders, cfg.ProvidersTemplateSyncInterval)
+
+	// Alert on tenants whose message volume spikes against their baseline, optionally throttling them
+	var anomalyService *anomaly.Service
+	if cfg.TenantAnomalyDetection {
+		anomalyService = anomaly.NewService(postgresClient, publisher, cfg.InstanceID, anomaly.Settings{
+			Interval:    cfg.TenantAnomalyInterval,
+			Baseline:    cfg.TenantAnomalyBaseline,
+			Factor:      cfg.TenantAnomalyFactor,
+			MinMessages: int64(cfg.TenantAnomalyMinMessages),
+			Throttle:    cfg.TenantAnomalyThrottle,
+		})
+	}
+
+	// Call the alert webhook when the backlog grows older or larger than its thresholds
+	// The watchdog reports to the same webhook
+	var alertHook *alerthook.Client
+	var backlogAlertService *backlogalert.Service
+	if cfg.BacklogAlertWebhookURL != "" {
+		alertHook = alerthook.NewClient(alerthook.Config{
+			URL:        cfg.BacklogAlertWebhookURL,
+			Format:     cfg.BacklogAlertFormat,
+			RoutingKey: cfg.BacklogAlertRoutingKey,
+		}, cfg.InstanceID)
+		backlogAlertService = backlogalert.NewService(postgresClient, alertHook, cfg.BacklogAlertInterval, backlogalert.Thresholds{
+			MaxAge:  cfg.BacklogAlertMaxAge,
+			MaxSize: int64(cfg.BacklogAlertMaxSize),
+		})
+	}
+
+	// Watch for a wedged scheduler, a batch holding its lock for too long and leaking goroutines
+	var watchdogService *watchdog.Service
+	if cfg.WatchdogInterval > 0 {
+		watchdogService = watchdog.NewService(messageService, alertHook, cfg.InstanceID, watchdog.Settings{
+			Interval:        cfg.WatchdogInterval,
+			StallTimeout:    cfg.SchedulerStallTimeout,
+			LockHold:        cfg.WatchdogLockHold,
+			GoroutineGrowth: cfg.WatchdogGoroutineGrowth,
+		})
+	}
+
+	// Move sent messages past the retention out of the messages table
+	var archiveService *archive.Service
+	if cfg.MessageRetentionDays > 0 {
+		archiveService = archive.NewService(postgresClient, maintenanceService, archive.Settings{
+			Interval:  cfg.MessageArchiveInterval,
+			Retention: time.Duration(cfg.MessageRetentionDays) * 24 * time.Hour,
+			Delete:    cfg.MessageRetentionAction == "delete",
+		})
+	}
+
+	// Ingest message creation requests from a queue besides the HTTP API
+	var ingestService *ingest.Service
+	if cfg.IngestBroker != "" {
+		consumer, err := queue.NewConsumer(queue.Config{
+			Broker:   cfg.IngestBroker,
+			URLs:     cfg.IngestURLs,
+			Topic:    cfg.IngestTopic,
+			GroupID:  cfg.IngestGroupID,
+			Prefetch: cfg.IngestConcurrency,
+		})
+		if err != nil {
+			log.Fatalf("Failed to create queue consumer: %v", err)
+		}
+		ingestService = ingest.NewService(messageService, maintenanceService, consumer)
+	}
+
+	// Send a canary message through the full pipeline when a test number is configured
+	var canaryService *canary.Service
+	if cfg.CanaryPhoneNumber != "" {
+		canaryService = canary.NewService(messageService, maintenanceService, canary.Settings{
+			PhoneNumber: cfg.CanaryPhoneNumber,
+			Provider:    cfg.CanaryProvider,
+			Timeout:     cfg.CanaryTimeout,
+		}, cfg.InstanceID)
+	}
+
+	// Keep requests rejected as invalid for support to inspect and replay
+	var replayService *replay.Service
+	if cfg.RequestCaptureRetention > 0 {
+		replayService = replay.NewService(postgresClient, cfg.RequestCaptureRetention)
+	}
+
+	healthService := health.NewService(postgresClient, webhookProviders, messageService, canaryService, cfg.HealthCheckWebhook, cfg.SchedulerStallTimeout)
 
 	log.Println("✓ Services initialized")
 
+	// Initialize optional rate limiting, shared through Redis when requested and available
+	var rateLimiter ratelimit.Limiter
+	if cfg.RateLimitPerMinute > 0 {
+		rate := ratelimit.Rate{PerMinute: cfg.RateLimitPerMinute, Burst: cfg.RateLimitBurst}
+		if rate.Burst == 0 {
+			rate.Burst = rate.PerMinute
+		}
+
+		switch {
+		case cfg.RateLimitRedis && redisClient != nil:
+			rateLimiter = redisClient.NewRateLimiter(rate)
+			log.Printf("✓ Rate limiting enabled (%d/min, burst %d, shared via Redis)", rate.PerMinute, rate.Burst)
+		default:
+			if cfg.RateLimitRedis {
+				log.Println("Warning: RATE_LIMIT_REDIS is set but Redis is not configured, limiting per instance")
+			}
+			rateLimiter = ratelimit.NewMemory(rate)
+			log.Printf("✓ Rate limiting enabled (%d/min, burst %d, per instance)", rate.PerMinute, rate.Burst)
+		}
+	}
+
+	// Mirror a sample of created messages to staging as test messages
+	var mirrorClient *mirror.Client
+	if cfg.MirrorURL != "" {
+		mirrorClient = mirror.NewClient(mirror.Config{
+			URL:     cfg.MirrorURL,
+			APIKey:  cfg.MirrorAPIKey,
+			Percent: cfg.MirrorPercent,
+			Timeout: cfg.MirrorTimeout,
+		}, cfg.InstanceID)
+		log.Printf("✓ Mirroring %d%% of created messages to %s", cfg.MirrorPercent, cfg.MirrorURL)
+	}
+
+	// Reload the scheduler settings and provider endpoints on SIGHUP or POST /api/v1/admin/config/reload
+	configReloadService := configreload.NewService(cfg, messageService, webhookProviders)
+
 	// Setup router (handlers are initialized inside)
-	router := api.SetupRouter(messageService)
+	router := api.SetupRouter(api.RouterDeps{
+		MessageService:          messageService,
+		CampaignService:         campaignService,
+		APIKeyService:           apiKeyService,
+		TemplateService:         templateService,
+		MaintenanceService:      maintenanceService,
+		TenantService:           tenantService,
+		HealthService:           healthService,
+		ReplayService:           replayService,
+		RateLimiter:             rateLimiter,
+		PostgresClient:          postgresClient,
+		Providers:               webhookProviders,
+		Mirror:                  mirrorClient,
+		AnomalyService:          anomalyService,
+		ProviderTemplateService: providerTemplateService,
+		ErrorStats:              errorStats,
+		FakeSenderService:       fakeSenderService,
+		BacklogAlertService:     backlogAlertService,
+		ConfigReloadService:     configReloadService,
+	}, api.RouterOptions{
+		APIKeysRequired:   cfg.APIKeysRequired,
+		ProcessingHeaders: cfg.MessageProcessingHeaders,
+		InstanceID:        cfg.InstanceID,
+		PIIUnmaskedRoles:  cfg.PIIUnmaskedRoles,
+		ListMaxItems:      cfg.ListMaxItems,
+	})
 	log.Println("✓ Router configured")
 
 	// Start HTTP server in a goroutine
@@ -61,8 +458,47 @@ func main() {
 		}
 	}()
 
+	// Serve the gRPC API alongside REST when a port is configured
+	var grpcServer *grpc.Server
+	if cfg.GRPCPort != "" {
+		grpcAddr := fmt.Sprintf(":%s", cfg.GRPCPort)
+		listener, err := net.Listen("tcp", grpcAddr)
+		if err != nil {
+			log.Fatalf("Failed to listen for gRPC on %s: %v", grpcAddr, err)
+		}
+
+		grpcServer = rpc.NewServer(messageService, apiKeyService, maintenanceService, cfg.APIKeysRequired, rateLimiter, anomalyService)
+		log.Printf("Starting gRPC server on %s", grpcAddr)
+
+		go func() {
+			if err := grpcServer.Serve(listener); err != nil {
+				log.Fatalf("Failed to start gRPC server: %v", err)
+			}
+		}()
+	}
+
 	log.Println("✓ Qubit Message Service is running!")
 
+	// Verify the deploy end to end, the outcome is reported by /health/ready
+	canaryCtx, stopCanary := context.WithCancel(ctx)
+	defer stopCanary()
+	if canaryService != nil && cfg.CanaryOnStartup {
+		go canaryService.Run(canaryCtx, canary.TriggerStartup)
+	}
+
+	// Reload the configuration on SIGHUP until shutdown
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	go func() {
+		for range hup {
+			reloadCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+			if _, err := configReloadService.Reload(reloadCtx, "SIGHUP"); err != nil {
+				log.Printf("Warning: failed to reload the configuration: %v", err)
+			}
+			cancel()
+		}
+	}()
+
 	// Wait for interrupt signal to gracefully shutdown
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -70,11 +506,120 @@ func main() {
 
 	log.Println("Shutting down server...")
 
+	// Let running gRPC calls finish, open ListSent streams are cut off after a grace period
+	if grpcServer != nil {
+		stopped := make(chan struct{})
+		go func() {
+			grpcServer.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-time.After(10 * time.Second):
+			grpcServer.Stop()
+		}
+	}
+
+	// Abort a running canary, its message is left to the scheduler
+	stopCanary()
+
+	// Stop queue ingestion before the services it writes through
+	if ingestService != nil {
+		if err := ingestService.Stop(); err != nil {
+			log.Printf("Warning: failed to stop queue ingestion: %v", err)
+		}
+	}
+
 	// Stop scheduler gracefully
 	if err := messageService.StopScheduler(); err != nil {
 		log.Printf("Warning: failed to stop scheduler: %v", err)
 	}
 
+	// Resign after the scheduler stopped, so a follower takes over without waiting for the lease to expire
+	if leaderService != nil {
+		if err := leaderService.Stop(); err != nil {
+			log.Printf("Warning: failed to resign scheduler leadership: %v", err)
+		}
+	}
+
+	// Leave after the scheduler stopped, so the other instances take over the shards on their next heartbeat
+	if shardService != nil {
+		if err := shardService.Stop(); err != nil {
+			log.Printf("Warning: failed to leave scheduler shards: %v", err)
+		}
+	}
+
+	// Stop campaign launcher gracefully
+	if err := campaignService.Stop(); err != nil {
+		log.Printf("Warning: failed to stop campaign launcher: %v", err)
+	}
+
+	// Stop maintenance mode sync
+	if err := maintenanceService.Stop(); err != nil {
+		log.Printf("Warning: failed to stop maintenance mode sync: %v", err)
+	}
+
+	// Stop fake sender mode sync
+	if err := fakeSenderService.Stop(); err != nil {
+		log.Printf("Warning: failed to stop fake sender mode sync: %v", err)
+	}
+
+	// Stop the provider template sync
+	if err := providerTemplateService.Stop(); err != nil {
+		log.Printf("Warning: failed to stop provider template sync: %v", err)
+	}
+
+	// Stop anomaly detection before the publisher is closed, alerts and throttles stay persisted
+	if anomalyService != nil {
+		if err := anomalyService.Stop(); err != nil {
+			log.Printf("Warning: failed to stop tenant anomaly detection: %v", err)
+		}
+	}
+
+	// Stop the watchdog
+	if watchdogService != nil {
+		if err := watchdogService.Stop(); err != nil {
+			log.Printf("Warning: failed to stop watchdog: %v", err)
+		}
+	}
+
+	// Stop backlog alerting, the alert state stays persisted
+	if backlogAlertService != nil {
+		if err := backlogAlertService.Stop(); err != nil {
+			log.Printf("Warning: failed to stop backlog alerting: %v", err)
+		}
+	}
+
+	// Stop outbox relay, unpublished events are relayed after the restart
+	if eventService != nil {
+		if err := eventService.Stop(); err != nil {
+			log.Printf("Warning: failed to stop outbox relay: %v", err)
+		}
+	}
+
+	// Stop message archival, the remaining old messages are archived after the restart
+	if archiveService != nil {
+		if err := archiveService.Stop(); err != nil {
+			log.Printf("Warning: failed to stop message archival: %v", err)
+		}
+	}
+
+	// Stop pruning rejected requests
+	if replayService != nil {
+		if err := replayService.Stop(); err != nil {
+			log.Printf("Warning: failed to stop rejected request pruning: %v", err)
+		}
+	}
+
+	// Finish the requests mirrored to staging
+	if mirrorClient != nil {
+		mirrorClient.Close()
+	}
+
+	// Stop listening for cache changes and refreshing the caches
+	stopListening()
+	tenantService.Stop()
+
 	// Give some time for cleanup
 	time.Sleep(2 * time.Second)
 
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"log"
+
+	"qubit/env/config"
+	"qubit/env/postgres"
+)
+
+// runMigrate implements the migrate subcommand
+// "migrate" applies pending migrations and seeds the reference data, "migrate status" lists them without applying anything
+func runMigrate(args []string) {
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	ctx := context.Background()
+
+	postgresClient, err := postgres.NewClient(ctx, cfg.DatabaseURL)
+	if err != nil {
+		log.Fatalf("Failed to connect to PostgreSQL: %v", err)
+	}
+	defer postgresClient.Close()
+
+	if len(args) > 0 && args[0] == "status" {
+		status, err := postgresClient.MigrationStatus(ctx)
+		if err != nil {
+			log.Fatalf("Failed to get migration status: %v", err)
+		}
+		for _, m := range status {
+			if m.AppliedAt != nil {
+				log.Printf("%s applied at %s", m.Version, m.AppliedAt.Format("2006-01-02 15:04:05"))
+			} else {
+				log.Printf("%s pending", m.Version)
+			}
+		}
+		return
+	}
+
+	if len(args) > 0 {
+		log.Fatalf("Unknown migrate command %q, expected no argument or \"status\"", args[0])
+	}
+
+	applied, err := postgresClient.Migrate(ctx, cfg.InstanceID, cfg.MigrationLockTimeout)
+	if err != nil {
+		log.Fatalf("Failed to run migrations: %v", err)
+	}
+
+	log.Printf("✓ %d migrations applied", len(applied))
+
+	seeded, err := postgresClient.Bootstrap(ctx, postgres.Seed{
+		DailyMessageQuota:  cfg.TenantDailyMessageQuota,
+		RateLimitPerMinute: cfg.TenantRateLimitPerMinute,
+	})
+	if err != nil {
+		log.Fatalf("Failed to bootstrap database: %v", err)
+	}
+
+	log.Printf("✓ %d reference rows seeded", seeded)
+}
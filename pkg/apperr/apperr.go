@@ -0,0 +1,141 @@
+// Package apperr types the errors services return for callers to act on
+//
+// Each error has a Kind, which decides the HTTP status and gRPC code it is answered with,
+// and a stable machine-readable Code clients can match instead of parsing the message.
+// Errors that are not typed are internal: their details are logged, never returned to clients.
+package apperr
+
+import (
+	"errors"
+	"net/http"
+
+	"google.golang.org/grpc/codes"
+)
+
+// Kind classifies an error by what the caller can do about it
+type Kind int
+
+// Error kinds
+const (
+	Internal Kind = iota
+	Validation
+	NotFound
+	Conflict
+	Forbidden
+	Unauthorized
+	RateLimited
+)
+
+// Codes shared by the API for errors raised outside the services
+const (
+	CodeInternal       = "internal_error"
+	CodeInvalidRequest = "invalid_request"
+	CodeValidation     = "validation_failed"
+	CodeUnauthorized   = "unauthorized"
+	CodeForbidden      = "forbidden"
+	CodeNotFound       = "not_found"
+	CodeRateLimited    = "rate_limited"
+	CodeThrottled      = "tenant_throttled"
+	CodeMaintenance    = "maintenance"
+)
+
+// Error is an error a caller can act on, identified by its code
+// Sentinel errors are declared once per service and wrapped with details using fmt.Errorf("%w: ...")
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+}
+
+// Error returns the message of the error
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// NewValidation creates an error for a request the caller has to correct
+func NewValidation(code, message string) *Error {
+	return &Error{Kind: Validation, Code: code, Message: message}
+}
+
+// NewNotFound creates an error for a resource that does not exist
+func NewNotFound(code, message string) *Error {
+	return &Error{Kind: NotFound, Code: code, Message: message}
+}
+
+// NewConflict creates an error for a request conflicting with the current state of a resource
+func NewConflict(code, message string) *Error {
+	return &Error{Kind: Conflict, Code: code, Message: message}
+}
+
+// NewForbidden creates an error for a request the caller is not allowed to make
+func NewForbidden(code, message string) *Error {
+	return &Error{Kind: Forbidden, Code: code, Message: message}
+}
+
+// NewUnauthorized creates an error for a caller that could not be authenticated
+func NewUnauthorized(code, message string) *Error {
+	return &Error{Kind: Unauthorized, Code: code, Message: message}
+}
+
+// NewRateLimited creates an error for a request over a rate or quota, the caller has to retry later
+func NewRateLimited(code, message string) *Error {
+	return &Error{Kind: RateLimited, Code: code, Message: message}
+}
+
+// From returns the typed error wrapped by err
+// Returns false for internal errors, including nil
+func From(err error) (*Error, bool) {
+	var typed *Error
+	if errors.As(err, &typed) && typed.Kind != Internal {
+		return typed, true
+	}
+	return nil, false
+}
+
+// Code returns the code of err, CodeInternal for internal errors
+func Code(err error) string {
+	if typed, ok := From(err); ok {
+		return typed.Code
+	}
+	return CodeInternal
+}
+
+// HTTPStatus returns the HTTP status an error of kind k is answered with
+func (k Kind) HTTPStatus() int {
+	switch k {
+	case Validation:
+		return http.StatusBadRequest
+	case NotFound:
+		return http.StatusNotFound
+	case Conflict:
+		return http.StatusConflict
+	case Forbidden:
+		return http.StatusForbidden
+	case Unauthorized:
+		return http.StatusUnauthorized
+	case RateLimited:
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// GRPCCode returns the gRPC code an error of kind k is answered with
+func (k Kind) GRPCCode() codes.Code {
+	switch k {
+	case Validation:
+		return codes.InvalidArgument
+	case NotFound:
+		return codes.NotFound
+	case Conflict:
+		return codes.FailedPrecondition
+	case Forbidden:
+		return codes.PermissionDenied
+	case Unauthorized:
+		return codes.Unauthenticated
+	case RateLimited:
+		return codes.ResourceExhausted
+	default:
+		return codes.Internal
+	}
+}
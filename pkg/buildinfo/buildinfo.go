@@ -0,0 +1,11 @@
+package buildinfo
+
+// Version is the application version, injected at build time:
+//
+//	go build -ldflags "-X qubit/pkg/buildinfo.Version=1.2.3" .
+var Version = "dev"
+
+// UserAgent returns the User-Agent sent on outbound HTTP calls
+func UserAgent() string {
+	return "qubit/" + Version
+}
@@ -0,0 +1,16 @@
+package ctxerr
+
+import (
+	"context"
+	"errors"
+)
+
+// StatusClientClosedRequest is the non-standard status used when the client went away
+// before the response was written (nginx convention)
+const StatusClientClosedRequest = 499
+
+// IsCanceled reports whether err was caused by a cancelled context
+// Deadline expiry is not treated as cancellation
+func IsCanceled(err error) bool {
+	return errors.Is(err, context.Canceled)
+}
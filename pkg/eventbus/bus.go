@@ -0,0 +1,52 @@
+package eventbus
+
+import "sync"
+
+// Bus fans published events out to all current subscribers
+// Publishing never blocks: a subscriber whose buffer is full misses the event
+type Bus[T any] struct {
+	mu          sync.RWMutex
+	subscribers map[chan T]struct{}
+}
+
+// New creates an empty bus
+func New[T any]() *Bus[T] {
+	return &Bus[T]{
+		subscribers: make(map[chan T]struct{}),
+	}
+}
+
+// Subscribe registers a subscriber receiving events on a channel buffered to buffer events
+// The returned function unsubscribes and closes the channel, it is safe to call more than once
+func (b *Bus[T]) Subscribe(buffer int) (<-chan T, func()) {
+	ch := make(chan T, buffer)
+
+	b.mu.Lock()
+	b.subscribers[ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subscribers, ch)
+			b.mu.Unlock()
+			close(ch)
+		})
+	}
+
+	return ch, unsubscribe
+}
+
+// Publish delivers event to every subscriber with room in its buffer
+func (b *Bus[T]) Publish(event T) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package jsonfmt
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// marshalerType is the json.Marshaler interface type
+var marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+
+// CheckFieldNames verifies that every JSON field of the given values, including nested structs,
+// is named in lowerCamelCase, so one DTO drifting to snake_case or PascalCase is caught at startup
+func CheckFieldNames(values ...any) error {
+	seen := make(map[reflect.Type]bool)
+	for _, v := range values {
+		if err := checkType(reflect.TypeOf(v), seen); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkType walks t and the types of its fields
+func checkType(t reflect.Type, seen map[reflect.Type]bool) error {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || seen[t] {
+		return nil
+	}
+	seen[t] = true
+
+	// Types with their own encoding, such as Time, are not walked
+	if t.Implements(marshalerType) || reflect.PointerTo(t).Implements(marshalerType) {
+		return nil
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+
+		// Embedded structs without a name are inlined by encoding/json
+		if field.Anonymous && name == "" {
+			if err := checkType(field.Type, seen); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+		if !isLowerCamelCase(name) {
+			return fmt.Errorf("%s.%s: JSON field %q is not lowerCamelCase", t.Name(), field.Name, name)
+		}
+
+		if err := checkType(field.Type, seen); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// isLowerCamelCase reports whether name starts with a lowercase letter and only holds letters and digits
+func isLowerCamelCase(name string) bool {
+	for i, r := range name {
+		if i == 0 && !unicode.IsLower(r) {
+			return false
+		}
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return name != ""
+}
@@ -0,0 +1,59 @@
+package jsonfmt
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Timestamp layouts, always rendered in UTC
+const (
+	LayoutSeconds      = "2006-01-02T15:04:05Z07:00"
+	LayoutMilliseconds = "2006-01-02T15:04:05.000Z07:00"
+)
+
+// millis selects LayoutMilliseconds for every Time, set once at startup
+var millis atomic.Bool
+
+// SetMilliseconds switches every Time between second and millisecond precision
+func SetMilliseconds(enabled bool) {
+	millis.Store(enabled)
+}
+
+// Layout returns the layout timestamps are currently rendered with
+func Layout() string {
+	if millis.Load() {
+		return LayoutMilliseconds
+	}
+	return LayoutSeconds
+}
+
+// Time is a timestamp in API responses, rendered as RFC 3339 in UTC
+// Use it instead of time.Time in every response DTO so all endpoints agree on the format
+type Time time.Time
+
+// NewTime converts a time.Time
+func NewTime(t time.Time) Time {
+	return Time(t)
+}
+
+// NewTimePtr converts an optional time.Time, nil stays nil and renders as null
+func NewTimePtr(t *time.Time) *Time {
+	if t == nil {
+		return nil
+	}
+	converted := Time(*t)
+	return &converted
+}
+
+// MarshalJSON renders the timestamp with the configured layout
+func (t Time) MarshalJSON() ([]byte, error) {
+	layout := Layout()
+	buf := make([]byte, 0, len(layout)+2)
+	buf = append(buf, '"')
+	buf = time.Time(t).UTC().AppendFormat(buf, layout)
+	return append(buf, '"'), nil
+}
+
+func (t Time) String() string {
+	return time.Time(t).UTC().Format(Layout())
+}
@@ -0,0 +1,122 @@
+// Package jsonstream writes list responses item by item with chunked transfer encoding,
+// so a list of any length is answered without holding it in memory
+package jsonstream
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"qubit/pkg/apperr"
+)
+
+// flushEvery is the number of items written between flushes to the client
+const flushEvery = 100
+
+// List writes the envelope {"<field>": [...], "count": n, "success": true} of a list response
+// The status line is only written with the first item or by Close, so an error before it can still be answered normally
+// count and success follow the items, a list failing midway ends with success false and the error
+type List struct {
+	w       http.ResponseWriter
+	enc     *json.Encoder
+	field   string
+	count   int
+	started bool
+}
+
+// NewList creates a List writing the items under field to w
+func NewList(w http.ResponseWriter, field string) *List {
+	return &List{w: w, enc: json.NewEncoder(w), field: field}
+}
+
+// Started reports whether the status line and the first bytes of the list were written
+func (l *List) Started() bool {
+	return l.started
+}
+
+// Write encodes the next item, flushing every few items
+func (l *List) Write(item any) error {
+	if err := l.start(); err != nil {
+		return err
+	}
+
+	if l.count > 0 {
+		if _, err := l.w.Write([]byte(",")); err != nil {
+			return err
+		}
+	}
+	if err := l.enc.Encode(item); err != nil {
+		return err
+	}
+
+	l.count++
+	if l.count%flushEvery == 0 {
+		l.flush()
+	}
+	return nil
+}
+
+// trailer holds the fields following the items
+type trailer struct {
+	Count   int    `json:"count"`
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+	Code    string `json:"code,omitempty"`
+}
+
+// Close ends the list as successful
+func (l *List) Close() error {
+	return l.end(trailer{Count: l.count, Success: true})
+}
+
+// Fail ends a started list with the error, answered like an error response
+// Internal errors are reported with prefix alone, their details are only logged
+func (l *List) Fail(prefix string, err error) error {
+	t := trailer{Count: l.count, Error: prefix, Code: apperr.CodeInternal}
+	if typed, ok := apperr.From(err); ok {
+		t.Error, t.Code = prefix+": "+err.Error(), typed.Code
+	}
+	return l.end(t)
+}
+
+// start writes the status line and opens the envelope
+func (l *List) start() error {
+	if l.started {
+		return nil
+	}
+	l.started = true
+
+	l.w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	l.w.WriteHeader(http.StatusOK)
+
+	field, err := json.Marshal(l.field)
+	if err != nil {
+		return err
+	}
+	_, err = l.w.Write(append(append([]byte("{"), field...), ":["...))
+	return err
+}
+
+// end closes the array and continues the envelope with the trailer, which closes it
+func (l *List) end(t trailer) error {
+	if err := l.start(); err != nil {
+		return err
+	}
+
+	encoded, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+	if _, err := l.w.Write(append([]byte("],"), encoded[1:]...)); err != nil {
+		return err
+	}
+
+	l.flush()
+	return nil
+}
+
+// flush sends the buffered items to the client when the writer supports it
+func (l *List) flush() {
+	if f, ok := l.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
@@ -0,0 +1,56 @@
+package locale
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Default names the default variant of localized content, used when no translation matches
+const Default = "default"
+
+// localeRegex matches a normalized locale, a language optionally followed by subtags, e.g. tr or pt-br
+var localeRegex = regexp.MustCompile(`^[a-z]{2,3}(-[a-z0-9]{2,8})*$`)
+
+// Normalize lowercases a locale and separates its subtags with '-', e.g. tr_TR becomes tr-tr
+func Normalize(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+}
+
+// Valid reports whether locale is a normalized language optionally followed by subtags
+func Valid(locale string) bool {
+	return localeRegex.MatchString(locale)
+}
+
+// NormalizeAll normalizes a list of locales, dropping blanks
+func NormalizeAll(locales []string) []string {
+	normalized := make([]string, 0, len(locales))
+	for _, locale := range locales {
+		if locale = Normalize(locale); locale != "" {
+			normalized = append(normalized, locale)
+		}
+	}
+	return normalized
+}
+
+// Pick returns the first locale of chain with a variant, keyed by normalized locale
+// A locale with subtags is followed by its language, e.g. pt-br by pt
+// Returns false when no locale of the chain has a variant
+func Pick(variants map[string]string, chain []string) (string, bool) {
+	for _, candidate := range chain {
+		candidate = Normalize(candidate)
+		if candidate == "" {
+			continue
+		}
+
+		if _, ok := variants[candidate]; ok {
+			return candidate, true
+		}
+		if language, _, found := strings.Cut(candidate, "-"); found {
+			if _, ok := variants[language]; ok {
+				return language, true
+			}
+		}
+	}
+
+	return "", false
+}
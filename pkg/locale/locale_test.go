@@ -0,0 +1,46 @@
+package locale
+
+import "testing"
+
+func TestPick(t *testing.T) {
+	variants := map[string]string{"tr": "Merhaba", "pt": "Olá", "en": "Hello"}
+
+	tests := []struct {
+		name   string
+		chain  []string
+		want   string
+		wantOK bool
+	}{
+		{"exact match", []string{"tr", "en"}, "tr", true},
+		{"normalized", []string{"TR_tr"}, "tr", true},
+		{"region falls back to language", []string{"pt-BR", "en"}, "pt", true},
+		{"fallback chain", []string{"de", "en"}, "en", true},
+		{"blanks skipped", []string{"", " ", "en"}, "en", true},
+		{"no match", []string{"de", "fr"}, "", false},
+		{"empty chain", nil, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Pick(variants, tt.chain)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("Pick(%v) = %q, %v, want %q, %v", tt.chain, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestValid(t *testing.T) {
+	for locale, want := range map[string]bool{
+		"tr":      true,
+		"pt-br":   true,
+		"zh-hant": true,
+		"pt-BR":   false,
+		"english": false,
+		"":        false,
+	} {
+		if got := Valid(locale); got != want {
+			t.Errorf("Valid(%q) = %v, want %v", locale, got, want)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+// Package lockwatch provides a mutex reporting how long it has been held, so a wedged holder can be spotted
+package lockwatch
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Mutex is a sync.Mutex that records when it was locked
+// The zero value is an unlocked mutex
+type Mutex struct {
+	mu       sync.Mutex
+	lockedAt atomic.Int64 // unix nanoseconds, 0 while unlocked
+}
+
+// Lock locks the mutex
+func (m *Mutex) Lock() {
+	m.mu.Lock()
+	m.lockedAt.Store(time.Now().UnixNano())
+}
+
+// TryLock locks the mutex if it is free and reports whether it did
+func (m *Mutex) TryLock() bool {
+	if !m.mu.TryLock() {
+		return false
+	}
+	m.lockedAt.Store(time.Now().UnixNano())
+	return true
+}
+
+// Unlock unlocks the mutex
+func (m *Mutex) Unlock() {
+	m.lockedAt.Store(0)
+	m.mu.Unlock()
+}
+
+// Held returns how long the mutex has been held, zero while unlocked
+// It never blocks, so it can be called while the holder is stuck
+func (m *Mutex) Held() time.Duration {
+	lockedAt := m.lockedAt.Load()
+	if lockedAt == 0 {
+		return 0
+	}
+	return time.Since(time.Unix(0, lockedAt))
+}
@@ -0,0 +1,272 @@
+// Package openapi builds an OpenAPI 3.1 document from the Go types the handlers bind and answer with
+//
+// Routes are described by an Operation registered together with the handler, so the document cannot
+// list an endpoint that does not exist. Request schemas are derived from the json, form and binding tags
+// gin validates, so the documented constraints are the enforced ones.
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Version is the OpenAPI version of the generated document
+const Version = "3.1.0"
+
+// Parameter locations
+const (
+	InPath  = "path"
+	InQuery = "query"
+)
+
+// Operation documents a single endpoint
+type Operation struct {
+	Summary     string
+	Description string
+	Tags        []string
+
+	// Body is a value of the type the handler binds the request body into, nil for none
+	Body any
+	// BodyOptional marks a body the handler also accepts missing
+	BodyOptional bool
+	// Query is a value of the struct the handler binds the query string into, nil for none
+	Query any
+	// Params are the parameters the handler reads one by one, e.g. with c.Param
+	// A param named like a field of Query only adds its description; path parameters not listed are strings
+	Params []Param
+
+	Responses []Response
+
+	// Security names the security schemes accepted, set by the router for authenticated groups
+	Security []string
+}
+
+// Param is a single path or query parameter
+type Param struct {
+	Name        string
+	In          string
+	Type        string // JSON Schema type, e.g. integer, string or boolean
+	Description string
+	Required    bool
+}
+
+// Response is a possible response of an operation
+type Response struct {
+	Status int
+	// Body is a value of the response type, nil for a response without body
+	Body any
+	// MediaType is the content type of Body, application/json when empty
+	MediaType string
+	Headers   []Header
+}
+
+// Header is a response header
+type Header struct {
+	Name        string
+	Type        string
+	Description string
+}
+
+// Spec is an OpenAPI document under construction
+type Spec struct {
+	title       string
+	version     string
+	description string
+	paths       map[string]map[string]*operation
+	schemas     *schemas
+	security    map[string]securityScheme
+}
+
+// New creates an empty document
+func New(title, version, description string) *Spec {
+	return &Spec{
+		title:       title,
+		version:     version,
+		description: description,
+		paths:       make(map[string]map[string]*operation),
+		schemas:     newSchemas(),
+		security:    make(map[string]securityScheme),
+	}
+}
+
+// AddAPIKeyScheme declares a security scheme authenticating with an API key sent in header
+func (s *Spec) AddAPIKeyScheme(name, header string) {
+	s.security[name] = securityScheme{Type: "apiKey", In: "header", Name: header}
+}
+
+// Add documents op as the handler of method on path, a gin path such as /messages/:id
+func (s *Spec) Add(method, path string, op Operation) {
+	path, pathParams := convertPath(path)
+
+	doc := &operation{
+		Summary:     op.Summary,
+		Description: op.Description,
+		Tags:        op.Tags,
+		Responses:   make(map[string]*response),
+	}
+
+	for _, name := range pathParams {
+		doc.Parameters = append(doc.Parameters, &parameter{
+			Name:     name,
+			In:       InPath,
+			Required: true,
+			Schema:   &Schema{Type: "string"},
+		})
+	}
+	if op.Query != nil {
+		doc.Parameters = append(doc.Parameters, s.schemas.queryParams(reflect.TypeOf(op.Query))...)
+	}
+	for _, p := range op.Params {
+		doc.addParam(p)
+	}
+
+	if op.Body != nil {
+		doc.RequestBody = &requestBody{
+			Required: !op.BodyOptional,
+			Content:  map[string]mediaType{"application/json": {Schema: s.schemas.request(reflect.TypeOf(op.Body))}},
+		}
+	}
+
+	for _, r := range op.Responses {
+		resp := &response{Description: http.StatusText(r.Status)}
+		if r.Body != nil {
+			mt := r.MediaType
+			if mt == "" {
+				mt = "application/json"
+			}
+			resp.Content = map[string]mediaType{mt: {Schema: s.schemas.response(reflect.TypeOf(r.Body))}}
+		}
+		for _, h := range r.Headers {
+			if resp.Headers == nil {
+				resp.Headers = make(map[string]header)
+			}
+			resp.Headers[h.Name] = header{Description: h.Description, Schema: &Schema{Type: h.Type}}
+		}
+		doc.Responses[strconv.Itoa(r.Status)] = resp
+	}
+
+	for _, name := range op.Security {
+		doc.Security = append(doc.Security, map[string][]string{name: {}})
+	}
+
+	if s.paths[path] == nil {
+		s.paths[path] = make(map[string]*operation)
+	}
+	s.paths[path][strings.ToLower(method)] = doc
+}
+
+// MarshalJSON renders the document
+func (s *Spec) MarshalJSON() ([]byte, error) {
+	doc := document{
+		OpenAPI: Version,
+		Info:    info{Title: s.title, Version: s.version, Description: s.description},
+		Paths:   s.paths,
+		Components: components{
+			Schemas:         s.schemas.components,
+			SecuritySchemes: s.security,
+		},
+	}
+	return json.Marshal(doc)
+}
+
+// addParam adds p, or describes the generated parameter of the same name
+func (o *operation) addParam(p Param) {
+	for _, existing := range o.Parameters {
+		if existing.Name != p.Name || existing.In != p.In {
+			continue
+		}
+		existing.Description = p.Description
+		if p.Type != "" && p.In == InPath {
+			existing.Schema = &Schema{Type: p.Type}
+		}
+		existing.Required = existing.Required || p.Required
+		return
+	}
+
+	o.Parameters = append(o.Parameters, &parameter{
+		Name:        p.Name,
+		In:          p.In,
+		Description: p.Description,
+		Required:    p.Required || p.In == InPath,
+		Schema:      &Schema{Type: p.Type},
+	})
+}
+
+// convertPath turns the gin path parameters of path into OpenAPI ones, e.g. /messages/:id into /messages/{id}
+func convertPath(path string) (string, []string) {
+	segments := strings.Split(path, "/")
+	var params []string
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			params = append(params, segment[1:])
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/"), params
+}
+
+// document is the root of an OpenAPI document
+type document struct {
+	OpenAPI    string                           `json:"openapi"`
+	Info       info                             `json:"info"`
+	Paths      map[string]map[string]*operation `json:"paths"`
+	Components components                       `json:"components"`
+}
+
+type info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+type components struct {
+	Schemas         map[string]*Schema        `json:"schemas,omitempty"`
+	SecuritySchemes map[string]securityScheme `json:"securitySchemes,omitempty"`
+}
+
+type securityScheme struct {
+	Type string `json:"type"`
+	In   string `json:"in"`
+	Name string `json:"name"`
+}
+
+type operation struct {
+	Summary     string                `json:"summary,omitempty"`
+	Description string                `json:"description,omitempty"`
+	Tags        []string              `json:"tags,omitempty"`
+	Parameters  []*parameter          `json:"parameters,omitempty"`
+	RequestBody *requestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]*response  `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+}
+
+type parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type requestBody struct {
+	Required bool                 `json:"required,omitempty"`
+	Content  map[string]mediaType `json:"content"`
+}
+
+type response struct {
+	Description string               `json:"description"`
+	Headers     map[string]header    `json:"headers,omitempty"`
+	Content     map[string]mediaType `json:"content,omitempty"`
+}
+
+type header struct {
+	Description string  `json:"description,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type mediaType struct {
+	Schema *Schema `json:"schema"`
+}
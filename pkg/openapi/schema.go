@@ -0,0 +1,414 @@
+package openapi
+
+import (
+	"encoding"
+	"encoding/json"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schema is a JSON Schema 2020-12 schema, the dialect of OpenAPI 3.1
+// Type is a string, or a list of strings for nullable values
+type Schema struct {
+	Ref    string `json:"$ref,omitempty"`
+	Type   any    `json:"type,omitempty"`
+	Format string `json:"format,omitempty"`
+	Enum   []any  `json:"enum,omitempty"`
+
+	Minimum          *float64 `json:"minimum,omitempty"`
+	Maximum          *float64 `json:"maximum,omitempty"`
+	ExclusiveMinimum *float64 `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum *float64 `json:"exclusiveMaximum,omitempty"`
+	MinLength        *int     `json:"minLength,omitempty"`
+	MaxLength        *int     `json:"maxLength,omitempty"`
+	MinItems         *int     `json:"minItems,omitempty"`
+	MaxItems         *int     `json:"maxItems,omitempty"`
+	MinProperties    *int     `json:"minProperties,omitempty"`
+	MaxProperties    *int     `json:"maxProperties,omitempty"`
+
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+
+	OneOf []*Schema `json:"oneOf,omitempty"`
+	AnyOf []*Schema `json:"anyOf,omitempty"`
+	AllOf []*Schema `json:"allOf,omitempty"`
+	Not   *Schema   `json:"not,omitempty"`
+}
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// formats maps the binding tags checking a string format onto JSON Schema formats
+var formats = map[string]string{
+	"email":    "email",
+	"url":      "uri",
+	"uri":      "uri",
+	"uuid":     "uuid",
+	"hostname": "hostname",
+	"ip":       "ip",
+}
+
+// schemas collects the named struct types of a document as components
+// A struct is described the way it is first used: as a request, with the constraints of its binding tags
+// and the fields tagged required, or as a response, with every field not omitted when empty required
+type schemas struct {
+	components map[string]*Schema
+	names      map[reflect.Type]string
+}
+
+func newSchemas() *schemas {
+	return &schemas{
+		components: make(map[string]*Schema),
+		names:      make(map[reflect.Type]string),
+	}
+}
+
+// request returns the schema of a request body of type t
+func (s *schemas) request(t reflect.Type) *Schema {
+	return s.of(t, true)
+}
+
+// response returns the schema of a response body of type t
+func (s *schemas) response(t reflect.Type) *Schema {
+	return s.of(t, false)
+}
+
+// of returns the schema of t, a reference for named structs
+func (s *schemas) of(t reflect.Type, input bool) *Schema {
+	if t.Kind() == reflect.Pointer {
+		return nullable(s.of(t.Elem(), input))
+	}
+
+	switch {
+	case t.ConvertibleTo(timeType) && t.Kind() == reflect.Struct:
+		return &Schema{Type: "string", Format: "date-time"}
+	case implements(t, jsonMarshalerType) || implements(t, textMarshalerType):
+		// Types with their own encoding are assumed to encode as text
+		return &Schema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: s.of(t.Elem(), input)}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: s.of(t.Elem(), input)}
+	case reflect.Struct:
+		return s.ref(t, input)
+	default:
+		// Interfaces hold any value
+		return &Schema{}
+	}
+}
+
+// ref returns a reference to the component describing struct t, adding it on first use
+func (s *schemas) ref(t reflect.Type, input bool) *Schema {
+	if t.Name() == "" {
+		return s.object(t, input)
+	}
+
+	name, ok := s.names[t]
+	if !ok {
+		name = path.Base(t.PkgPath()) + "." + t.Name()
+		s.names[t] = name
+		// Named before the fields are walked, so recursive types end in a reference
+		s.components[name] = s.object(t, input)
+	}
+
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+// object returns the schema of the JSON fields of struct t
+func (s *schemas) object(t reflect.Type, input bool) *Schema {
+	obj := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+
+	fields := jsonFields(t, "json")
+	for _, f := range fields {
+		obj.Properties[f.name] = s.field(f, input)
+
+		if f.required(input) {
+			obj.Required = append(obj.Required, f.name)
+		}
+		if !input {
+			continue
+		}
+
+		// Rules spanning fields name the other fields by their Go names, separated by spaces
+		for _, rule := range f.rules {
+			key, value, _ := strings.Cut(rule, "=")
+			var others []field
+			for _, name := range strings.Fields(value) {
+				if other, ok := findField(fields, name); ok {
+					others = append(others, other)
+				}
+			}
+			if len(others) == 0 {
+				continue
+			}
+			switch key {
+			case "required_without", "required_without_all":
+				anyOf := []*Schema{{Required: []string{f.name}}}
+				for _, other := range others {
+					anyOf = append(anyOf, &Schema{Required: []string{other.name}})
+				}
+				obj.AllOf = append(obj.AllOf, &Schema{AnyOf: anyOf})
+			case "excluded_with":
+				for _, other := range others {
+					obj.AllOf = append(obj.AllOf, &Schema{Not: &Schema{Required: []string{f.name, other.name}}})
+				}
+			}
+		}
+	}
+
+	return obj
+}
+
+// field returns the schema of a field with the constraints of its binding tag applied
+func (s *schemas) field(f field, input bool) *Schema {
+	t := f.typ
+	pointer := t.Kind() == reflect.Pointer
+	if pointer {
+		t = t.Elem()
+	}
+
+	schema := s.of(t, input)
+	if input && schema.Ref == "" {
+		constrain(schema, t, f.rules)
+	}
+	if pointer {
+		return nullable(schema)
+	}
+	return schema
+}
+
+// queryParams returns the parameters of the query string bound into struct t by its form tags
+func (s *schemas) queryParams(t reflect.Type) []*parameter {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	var params []*parameter
+	for _, f := range jsonFields(t, "form") {
+		typ := f.typ
+		if typ.Kind() == reflect.Pointer {
+			typ = typ.Elem()
+		}
+
+		schema := s.of(typ, true)
+		constrain(schema, typ, f.rules)
+
+		params = append(params, &parameter{
+			Name:     f.name,
+			In:       InQuery,
+			Required: f.required(true),
+			Schema:   schema,
+		})
+	}
+	return params
+}
+
+// constrain applies the validation rules of a binding tag to the schema of a value of type t
+func constrain(schema *Schema, t reflect.Type, rules []string) {
+	for _, rule := range rules {
+		key, value, _ := strings.Cut(rule, "=")
+		switch key {
+		case "min", "gte":
+			setBound(schema, t, value, true)
+		case "max", "lte":
+			setBound(schema, t, value, false)
+		case "len":
+			setBound(schema, t, value, true)
+			setBound(schema, t, value, false)
+		case "gt":
+			if n, err := strconv.ParseFloat(value, 64); err == nil && isNumber(t) {
+				schema.ExclusiveMinimum = &n
+			}
+		case "lt":
+			if n, err := strconv.ParseFloat(value, 64); err == nil && isNumber(t) {
+				schema.ExclusiveMaximum = &n
+			}
+		case "oneof":
+			for _, option := range strings.Fields(value) {
+				if n, err := strconv.ParseFloat(option, 64); err == nil && isNumber(t) {
+					schema.Enum = append(schema.Enum, n)
+					continue
+				}
+				schema.Enum = append(schema.Enum, option)
+			}
+		case "dive":
+			// The remaining rules apply to the elements
+			return
+		default:
+			if format, ok := formats[key]; ok {
+				schema.Format = format
+			}
+		}
+	}
+}
+
+// setBound sets the lower or upper bound a min or max rule puts on a value of type t
+func setBound(schema *Schema, t reflect.Type, value string, lower bool) {
+	if isNumber(t) {
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return
+		}
+		if lower {
+			schema.Minimum = &n
+		} else {
+			schema.Maximum = &n
+		}
+		return
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return
+	}
+	switch t.Kind() {
+	case reflect.String:
+		if lower {
+			schema.MinLength = &n
+		} else {
+			schema.MaxLength = &n
+		}
+	case reflect.Slice, reflect.Array:
+		if lower {
+			schema.MinItems = &n
+		} else {
+			schema.MaxItems = &n
+		}
+	case reflect.Map:
+		if lower {
+			schema.MinProperties = &n
+		} else {
+			schema.MaxProperties = &n
+		}
+	}
+}
+
+// implements reports whether t or a pointer to t implements iface
+func implements(t, iface reflect.Type) bool {
+	return t.Implements(iface) || reflect.PointerTo(t).Implements(iface)
+}
+
+// isNumber reports whether values of t are JSON numbers
+func isNumber(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// nullable allows null besides the values of schema
+func nullable(schema *Schema) *Schema {
+	switch typ := schema.Type.(type) {
+	case string:
+		schema.Type = []string{typ, "null"}
+		return schema
+	case nil:
+		if schema.Ref == "" {
+			// Anything, including null
+			return schema
+		}
+	}
+	return &Schema{OneOf: []*Schema{schema, {Type: "null"}}}
+}
+
+// field is a struct field as encoded in JSON or bound from a form
+type field struct {
+	name      string
+	goName    string
+	typ       reflect.Type
+	omitempty bool
+	rules     []string
+}
+
+// required reports whether the field is always present: tagged required in requests, never omitted in responses
+func (f field) required(input bool) bool {
+	if input {
+		for _, rule := range f.rules {
+			if rule == "required" {
+				return true
+			}
+		}
+		return false
+	}
+	return !f.omitempty
+}
+
+// jsonFields returns the fields of struct t named by tag, inlining embedded structs like encoding/json
+// Fields tagged openapi:"-" are left out of the document
+func jsonFields(t reflect.Type, tag string) []field {
+	var fields []field
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() || sf.Tag.Get("openapi") == "-" {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(sf.Tag.Get(tag), ",")
+		if name == "-" {
+			continue
+		}
+
+		if sf.Anonymous && name == "" && sf.Type.Kind() == reflect.Struct {
+			fields = append(fields, jsonFields(sf.Type, tag)...)
+			continue
+		}
+
+		if name == "" {
+			if tag != "json" {
+				continue
+			}
+			name = sf.Name
+		}
+
+		var rules []string
+		if binding := sf.Tag.Get("binding"); binding != "" {
+			rules = strings.Split(binding, ",")
+		}
+
+		fields = append(fields, field{
+			name:      name,
+			goName:    sf.Name,
+			typ:       sf.Type,
+			omitempty: strings.Contains(opts, "omitempty"),
+			rules:     rules,
+		})
+	}
+	return fields
+}
+
+// findField returns the field with the Go name goName
+func findField(fields []field, goName string) (field, bool) {
+	for _, f := range fields {
+		if f.goName == goName {
+			return f, true
+		}
+	}
+	return field{}, false
+}
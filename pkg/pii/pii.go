@@ -0,0 +1,97 @@
+// Package pii masks the personal data of message recipients, phone numbers and content,
+// in log output and in API responses
+package pii
+
+import (
+	"context"
+	"io"
+	"regexp"
+	"unicode/utf8"
+)
+
+// Characters of a phone number kept in clear, e.g. +9055******11
+const (
+	keepPrefix = 5
+	keepSuffix = 2
+)
+
+// maskedContent replaces the content of a message
+const maskedContent = "[masked]"
+
+// phonePattern finds phone numbers in free text: a + followed by 8 to 15 digits
+// The + is required, so IDs, timestamps and counts in the log are left alone
+var phonePattern = regexp.MustCompile(`\+[1-9]\d{7,14}\b`)
+
+// MaskPhone masks all but the country code area and the last digits of a phone number
+// Numbers too short to keep a prefix are masked except for their last digits
+func MaskPhone(phoneNumber string) string {
+	n := utf8.RuneCountInString(phoneNumber)
+	if n == 0 {
+		return ""
+	}
+
+	runes := []rune(phoneNumber)
+	prefix := keepPrefix
+	if n <= keepPrefix+keepSuffix {
+		prefix = 0
+	}
+	for i := prefix; i < n-min(keepSuffix, n-1); i++ {
+		runes[i] = '*'
+	}
+	return string(runes)
+}
+
+// MaskContent hides the content of a message, an empty content stays empty
+func MaskContent(content string) string {
+	if content == "" {
+		return ""
+	}
+	return maskedContent
+}
+
+// MaskText masks every phone number found in free text, e.g. a log line or an error message
+func MaskText(text string) string {
+	return phonePattern.ReplaceAllStringFunc(text, MaskPhone)
+}
+
+// Writer masks the phone numbers of everything written through it, e.g. as the output of the log package
+type Writer struct {
+	out io.Writer
+}
+
+// NewWriter creates a Writer writing the masked text to out
+func NewWriter(out io.Writer) *Writer {
+	return &Writer{out: out}
+}
+
+// Write masks p and writes it to the underlying writer
+// It reports the length of p, so callers see their whole write consumed even though the masked text may differ
+func (w *Writer) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(w.out, MaskText(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// maskedKey is the context key marking a request whose responses are masked
+type maskedKey struct{}
+
+// WithMasked returns a context marking the responses of the request as masked
+func WithMasked(ctx context.Context) context.Context {
+	return context.WithValue(ctx, maskedKey{}, true)
+}
+
+// Masked reports whether the responses of the request are masked, see WithMasked
+func Masked(ctx context.Context) bool {
+	masked, _ := ctx.Value(maskedKey{}).(bool)
+	return masked
+}
+
+// MaskAll masks every phone number in a list, e.g. the recipients of a campaign
+func MaskAll(phoneNumbers []string) []string {
+	masked := make([]string, len(phoneNumbers))
+	for i, phoneNumber := range phoneNumbers {
+		masked[i] = MaskPhone(phoneNumber)
+	}
+	return masked
+}
@@ -0,0 +1,28 @@
+package pii_test
+
+import (
+	"testing"
+
+	"qubit/pkg/pii"
+)
+
+func TestMaskText(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"international number", "Sending message 1 to +905551234511", "Sending message 1 to +9055******11"},
+		{"number in an error", `send to "+15551234567": 503`, `send to "+1555*****67": 503`},
+		{"digits without a plus", "message 1760000000123 at 1760000000", "message 1760000000123 at 1760000000"},
+		{"too short", "retry +1234567 later", "retry +1234567 later"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pii.MaskText(tt.text); got != tt.want {
+				t.Errorf("MaskText(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
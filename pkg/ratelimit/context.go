@@ -0,0 +1,17 @@
+package ratelimit
+
+import "context"
+
+// contextKey is the context key of the decision taken for a request
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying the decision taken for the request
+func NewContext(ctx context.Context, decision Decision) context.Context {
+	return context.WithValue(ctx, contextKey{}, decision)
+}
+
+// FromContext returns the decision carried by ctx, false when the request was not rate limited
+func FromContext(ctx context.Context) (Decision, bool) {
+	decision, ok := ctx.Value(contextKey{}).(Decision)
+	return decision, ok
+}
@@ -0,0 +1,99 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Rate configures a token bucket refilled at PerMinute tokens per minute holding at most Burst tokens
+type Rate struct {
+	PerMinute int
+	Burst     int
+}
+
+// interval returns the time needed to refill a single token
+func (r Rate) interval() time.Duration {
+	return time.Minute / time.Duration(r.PerMinute)
+}
+
+// Decision is the outcome of taking a token
+type Decision struct {
+	Allowed bool
+	// RetryAfter is how long until the next token is available, zero when allowed
+	RetryAfter time.Duration
+	// Remaining is the number of whole tokens left in the bucket
+	Remaining int
+}
+
+// Limiter takes tokens from the bucket identified by key
+type Limiter interface {
+	Allow(ctx context.Context, key string) (Decision, error)
+}
+
+// bucket holds the tokens of a single key
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// Memory is an in-process token bucket limiter, buckets are not shared between instances
+type Memory struct {
+	rate Rate
+
+	mu         sync.Mutex
+	buckets    map[string]*bucket
+	lastPruned time.Time
+}
+
+// NewMemory creates an in-process limiter
+func NewMemory(rate Rate) *Memory {
+	return &Memory{
+		rate:    rate,
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// Allow takes a token from the bucket of key
+func (m *Memory) Allow(_ context.Context, key string) (Decision, error) {
+	now := time.Now()
+	capacity := float64(m.rate.Burst)
+	perToken := m.rate.interval()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	b, ok := m.buckets[key]
+	if !ok {
+		m.prune(now)
+		b = &bucket{tokens: capacity, updated: now}
+		m.buckets[key] = b
+	}
+
+	b.tokens = math.Min(capacity, b.tokens+float64(now.Sub(b.updated))/float64(perToken))
+	b.updated = now
+
+	if b.tokens < 1 {
+		return Decision{RetryAfter: time.Duration((1 - b.tokens) * float64(perToken))}, nil
+	}
+
+	b.tokens--
+	return Decision{Allowed: true, Remaining: int(b.tokens)}, nil
+}
+
+// prune drops buckets that refilled completely, they behave exactly like a new bucket
+// Runs at most once a minute
+func (m *Memory) prune(now time.Time) {
+	if now.Sub(m.lastPruned) < time.Minute {
+		return
+	}
+	m.lastPruned = now
+
+	full := time.Duration(m.rate.Burst) * m.rate.interval()
+	for key, b := range m.buckets {
+		if now.Sub(b.updated) >= full {
+			delete(m.buckets, key)
+		}
+	}
+}
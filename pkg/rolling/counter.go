@@ -0,0 +1,81 @@
+package rolling
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// bucket holds the count of a single second
+type bucket struct {
+	second atomic.Int64
+	count  atomic.Int64
+}
+
+// Counter counts events over a sliding window using one bucket per second
+// All methods are safe for concurrent use without locks
+type Counter struct {
+	buckets []bucket
+}
+
+// NewCounter creates a counter able to answer sums over windows up to span
+func NewCounter(span time.Duration) *Counter {
+	size := int(span / time.Second)
+	if size < 1 {
+		size = 1
+	}
+
+	return &Counter{
+		buckets: make([]bucket, size),
+	}
+}
+
+// Add records n events at the current time
+func (c *Counter) Add(n int64) {
+	c.addAt(time.Now(), n)
+}
+
+// Sum returns the number of events recorded within the last window
+// Windows longer than the counter span are capped at the span
+func (c *Counter) Sum(window time.Duration) int64 {
+	return c.sumAt(time.Now(), window)
+}
+
+func (c *Counter) addAt(now time.Time, n int64) {
+	second := now.Unix()
+	b := &c.buckets[second%int64(len(c.buckets))]
+
+	// Claim a stale bucket for the current second
+	// An add racing the reset may be dropped, which is acceptable for live dashboards
+	for {
+		current := b.second.Load()
+		if current == second {
+			break
+		}
+		if b.second.CompareAndSwap(current, second) {
+			b.count.Store(0)
+			break
+		}
+	}
+
+	b.count.Add(n)
+}
+
+func (c *Counter) sumAt(now time.Time, window time.Duration) int64 {
+	seconds := int64(window / time.Second)
+	if seconds > int64(len(c.buckets)) {
+		seconds = int64(len(c.buckets))
+	}
+
+	newest := now.Unix()
+	oldest := newest - seconds
+
+	var sum int64
+	for i := range c.buckets {
+		b := &c.buckets[i]
+		if second := b.second.Load(); second > oldest && second <= newest {
+			sum += b.count.Load()
+		}
+	}
+
+	return sum
+}
@@ -0,0 +1,75 @@
+package scheduler
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Adaptive runs the task at an interval that stretches while the task finds no work
+// After idleRuns consecutive idle runs every further idle run doubles the interval, up to max;
+// a busy run or Reset returns to the base interval
+type Adaptive struct {
+	base     time.Duration
+	max      time.Duration
+	idleRuns int
+
+	mu      sync.Mutex
+	idle    int
+	current time.Duration
+}
+
+// NewAdaptive creates an adaptive schedule starting at the base interval
+func NewAdaptive(base, max time.Duration, idleRuns int) *Adaptive {
+	return &Adaptive{
+		base:     base,
+		max:      max,
+		idleRuns: idleRuns,
+		current:  base,
+	}
+}
+
+// Next returns t plus the current interval
+func (a *Adaptive) Next(t time.Time) time.Time {
+	return t.Add(a.Interval())
+}
+
+// Interval returns the current interval
+func (a *Adaptive) Interval() time.Duration {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.current
+}
+
+// Idle records a run that found no work, stretching the interval once enough idle runs followed each other
+func (a *Adaptive) Idle() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.idle++
+	if a.idle > a.idleRuns && a.current < a.max {
+		a.current = min(a.current*2, a.max)
+	}
+}
+
+// Reset returns to the base interval after a busy run or when new work arrives
+// It reports whether the interval was stretched, i.e. whether the next run should be rescheduled
+func (a *Adaptive) Reset() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	stretched := a.current != a.base
+	a.idle = 0
+	a.current = a.base
+	return stretched
+}
+
+func (a *Adaptive) String() string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.current == a.base {
+		return fmt.Sprintf("every %s, up to %s while idle", a.base, a.max)
+	}
+	return fmt.Sprintf("every %s, up to %s while idle (idle: every %s)", a.base, a.max, a.current)
+}
@@ -0,0 +1,221 @@
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule decides when the task runs next
+type Schedule interface {
+	// Next returns the first run time strictly after t, zero if there is none
+	Next(t time.Time) time.Time
+	String() string
+}
+
+// MinInterval is the shortest interval accepted for an Every schedule
+const MinInterval = time.Second
+
+// Every runs the task at a fixed interval
+type Every time.Duration
+
+// Next returns t plus the interval
+func (e Every) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(e))
+}
+
+func (e Every) String() string {
+	return "every " + time.Duration(e).String()
+}
+
+// Cron runs the task at the times matching a cron expression with second granularity
+// Fields are second, minute, hour, day of month, month and day of week; a 5-field expression runs at second 0
+type Cron struct {
+	expr   string
+	second bitset
+	minute bitset
+	hour   bitset
+	dom    bitset
+	month  bitset
+	dow    bitset
+	anyDom bool
+	anyDow bool
+}
+
+// maxCronLookahead bounds the search for the next run of an expression that never matches, e.g. February 30
+const maxCronLookahead = 5 * 366 * 24 * time.Hour
+
+// cronField describes the range of one cron field
+type cronField struct {
+	name     string
+	min, max int
+}
+
+var cronFields = []cronField{
+	{"second", 0, 59},
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7},
+}
+
+// ParseCron parses a 6-field (with seconds) or 5-field cron expression
+// Each field accepts *, ?, single values, ranges a-b, steps */n, a/n or a-b/n and comma-separated lists
+// Day of week 0 and 7 are both Sunday
+func ParseCron(expr string) (*Cron, error) {
+	fields := strings.Fields(expr)
+	switch len(fields) {
+	case 5:
+		fields = append([]string{"0"}, fields...)
+	case 6:
+	default:
+		return nil, fmt.Errorf("cron expression %q must have 5 or 6 fields, got %d", expr, len(fields))
+	}
+
+	sets := make([]bitset, len(fields))
+	for i, field := range fields {
+		set, err := parseCronField(field, cronFields[i])
+		if err != nil {
+			return nil, fmt.Errorf("cron expression %q: %w", expr, err)
+		}
+		sets[i] = set
+	}
+
+	// Sunday may be written as 7
+	if sets[5].has(7) {
+		sets[5] |= 1
+	}
+
+	return &Cron{
+		expr:   strings.Join(strings.Fields(expr), " "),
+		second: sets[0],
+		minute: sets[1],
+		hour:   sets[2],
+		dom:    sets[3],
+		month:  sets[4],
+		dow:    sets[5],
+		anyDom: isWildcard(fields[3]),
+		anyDow: isWildcard(fields[5]),
+	}, nil
+}
+
+// Next returns the first matching time strictly after t in t's location
+func (c *Cron) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Truncate(time.Second).Add(time.Second)
+	limit := t.Add(maxCronLookahead)
+
+	for t.Before(limit) {
+		switch {
+		case !c.month.has(int(t.Month())):
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !c.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		case !c.hour.has(t.Hour()):
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		case !c.minute.has(t.Minute()):
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute()+1, 0, 0, loc)
+		case !c.second.has(t.Second()):
+			t = t.Add(time.Second)
+		default:
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+func (c *Cron) String() string {
+	return c.expr
+}
+
+// dayMatches applies the cron day rule: when both day fields are restricted either one may match
+func (c *Cron) dayMatches(t time.Time) bool {
+	dom := c.dom.has(t.Day())
+	dow := c.dow.has(int(t.Weekday()))
+
+	switch {
+	case c.anyDom && c.anyDow:
+		return true
+	case c.anyDom:
+		return dow
+	case c.anyDow:
+		return dom
+	default:
+		return dom || dow
+	}
+}
+
+// bitset holds the allowed values of a cron field
+type bitset uint64
+
+func (b bitset) has(v int) bool {
+	return b&(1<<uint(v)) != 0
+}
+
+// isWildcard reports whether a field matches every value
+func isWildcard(field string) bool {
+	return field == "*" || field == "?"
+}
+
+// parseCronField parses one comma-separated cron field
+func parseCronField(field string, f cronField) (bitset, error) {
+	var set bitset
+
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step %q in %s field", stepPart, f.name)
+			}
+			step = n
+		}
+
+		lo, hi := f.min, f.max
+		switch {
+		case isWildcard(rangePart):
+		case strings.Contains(rangePart, "-"):
+			from, to, _ := strings.Cut(rangePart, "-")
+			var err error
+			if lo, err = parseCronValue(from, f); err != nil {
+				return 0, err
+			}
+			if hi, err = parseCronValue(to, f); err != nil {
+				return 0, err
+			}
+			if lo > hi {
+				return 0, fmt.Errorf("invalid range %q in %s field", rangePart, f.name)
+			}
+		default:
+			v, err := parseCronValue(rangePart, f)
+			if err != nil {
+				return 0, err
+			}
+			lo = v
+			// A single value only extends to the end of the range with a step, as in 5/15
+			if !hasStep {
+				hi = v
+			}
+		}
+
+		for v := lo; v <= hi; v += step {
+			set |= 1 << uint(v)
+		}
+	}
+
+	return set, nil
+}
+
+// parseCronValue parses a single value and checks it is within the field range
+func parseCronValue(s string, f cronField) (int, error) {
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("invalid value %q in %s field (expected %d-%d)", s, f.name, f.min, f.max)
+	}
+	return v, nil
+}
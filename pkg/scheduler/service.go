@@ -5,47 +5,170 @@ import (
 	"log"
 	"sync"
 	"time"
+
+	"qubit/pkg/ctxerr"
 )
 
+// TaskTimeout bounds a single run of the task, which is cancelled once it runs longer
+const TaskTimeout = 5 * time.Minute
+
 // Client manages the automatic task execution
 type Client struct {
 	task     func(context.Context) error
-	interval time.Duration
+	schedule Schedule
 
 	// Scheduler state
-	ticker      *time.Ticker
-	ctx         context.Context
+	running     bool
+	ctx         context.Context // Parent of every task run, cancelled by Stop
 	cancel      context.CancelFunc
+	stopLoop    context.CancelFunc // Ends the loop without cancelling the task in progress
 	mu          sync.RWMutex
 	wg          sync.WaitGroup
 	taskRunning sync.Mutex // Prevents concurrent task executions
+
+	nextMu    sync.Mutex // Guards nextRun and the heartbeat, which the loop updates while Stop holds mu
+	nextRun   time.Time
+	heartbeat time.Time // When the loop last ran the task or scheduled its next run
+	due       time.Time // When the loop is due to beat again
+
+	wake    chan struct{} // Makes the loop recompute its next run
+	trigger chan struct{} // Makes the loop run the task right away
 }
 
 // Run starts a new scheduler client
 func Run() *Client {
-	return &Client{}
+	return &Client{
+		wake:    make(chan struct{}, 1),
+		trigger: make(chan struct{}, 1),
+	}
 }
 
 // Start starts the scheduler with the given task and interval
-func (c *Client) Start(task func(context.Context) error, intervalMinutes int) error {
+// The task runs right away and then every interval
+func (c *Client) Start(task func(context.Context) error, interval time.Duration) error {
+	return c.start(task, Every(interval), true)
+}
+
+// StartSchedule starts the scheduler with the given task and schedule
+// The task first runs at the next scheduled time
+func (c *Client) StartSchedule(task func(context.Context) error, schedule Schedule) error {
+	return c.start(task, schedule, false)
+}
+
+// StartAdaptive starts the scheduler with the given task on an adaptive schedule
+// The task runs right away like with Start
+func (c *Client) StartAdaptive(task func(context.Context) error, schedule *Adaptive) error {
+	return c.start(task, schedule, true)
+}
+
+// Trigger makes a running loop run the task right away, outside its schedule
+// Triggers arriving while the task runs are coalesced into a single further run; the scheduled runs are unaffected
+func (c *Client) Trigger() {
+	select {
+	case c.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// Wake makes a running loop recompute its next run, e.g. after an adaptive schedule was reset
+// The next run is then scheduled from the previous one, or from now if that time already passed
+func (c *Client) Wake() {
+	select {
+	case c.wake <- struct{}{}:
+	default:
+	}
+}
+
+// start launches the scheduler loop
+func (c *Client) start(task func(context.Context) error, schedule Schedule, immediate bool) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	c.task = task
-	c.interval = time.Duration(intervalMinutes) * time.Minute
+	c.schedule = schedule
+	c.running = true
+	// A heartbeat left by a previous run must not make the restarted loop look stalled
+	c.beat(time.Now())
 
 	c.ctx, c.cancel = context.WithCancel(context.Background())
-
-	c.ticker = time.NewTicker(c.interval)
+	var loopCtx context.Context
+	loopCtx, c.stopLoop = context.WithCancel(c.ctx)
 
 	c.wg.Add(1)
-	go c.run()
+	go c.run(loopCtx, schedule, immediate)
 
-	log.Printf("✓ Scheduler started (interval: %v)", c.interval)
+	log.Printf("✓ Scheduler started (schedule: %s)", c.schedule)
 
 	return nil
 }
 
+// Running reports whether the scheduler loop is active
+func (c *Client) Running() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.running
+}
+
+// Schedule returns the schedule the scheduler was last started with, nil before the first start
+func (c *Client) Schedule() Schedule {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.schedule
+}
+
+// NextRun returns the next scheduled run, nil while stopped
+func (c *Client) NextRun() *time.Time {
+	if !c.Running() {
+		return nil
+	}
+
+	c.nextMu.Lock()
+	defer c.nextMu.Unlock()
+
+	if c.nextRun.IsZero() {
+		return nil
+	}
+
+	next := c.nextRun
+	return &next
+}
+
+// Heartbeat returns when the loop last ran the task or scheduled its next run, nil before the first start
+func (c *Client) Heartbeat() *time.Time {
+	c.nextMu.Lock()
+	defer c.nextMu.Unlock()
+
+	if c.heartbeat.IsZero() {
+		return nil
+	}
+
+	heartbeat := c.heartbeat
+	return &heartbeat
+}
+
+// Stalled reports whether the running loop is overdue by more than timeout,
+// because it missed its next run or the task has been running that long
+// A stopped scheduler never stalls; timeout should exceed TaskTimeout
+func (c *Client) Stalled(timeout time.Duration) bool {
+	if !c.Running() {
+		return false
+	}
+
+	c.nextMu.Lock()
+	defer c.nextMu.Unlock()
+
+	return !c.due.IsZero() && time.Since(c.due) > timeout
+}
+
+// beat records that the loop is alive and due to beat again at due
+func (c *Client) beat(due time.Time) {
+	c.nextMu.Lock()
+	defer c.nextMu.Unlock()
+
+	c.heartbeat = time.Now()
+	c.due = due
+}
+
 // Stop stops the scheduler gracefully
 func (c *Client) Stop() error {
 	c.mu.Lock()
@@ -53,9 +176,7 @@ func (c *Client) Stop() error {
 
 	log.Println("Stopping scheduler...")
 
-	if c.ticker != nil {
-		c.ticker.Stop()
-	}
+	c.running = false
 
 	if c.cancel != nil {
 		c.cancel()
@@ -68,20 +189,82 @@ func (c *Client) Stop() error {
 	return nil
 }
 
+// Drain stops the scheduler once the task in progress, if any, has finished
+// Unlike Stop it never cancels a running task, e.g. to restart the scheduler with new settings mid-batch
+// The wait happens without holding mu, so Running, Schedule and NextRun answer while a long task drains
+func (c *Client) Drain() error {
+	c.mu.Lock()
+
+	log.Println("Draining scheduler...")
+
+	c.running = false
+
+	if c.stopLoop != nil {
+		c.stopLoop()
+	}
+	cancel := c.cancel
+
+	c.mu.Unlock()
+
+	c.wg.Wait()
+
+	if cancel != nil {
+		cancel()
+	}
+
+	log.Println("✓ Scheduler drained")
+
+	return nil
+}
+
 // run is the main scheduler loop
-func (c *Client) run() {
+// immediate runs the task once right away, before the first scheduled time
+func (c *Client) run(ctx context.Context, schedule Schedule, immediate bool) {
 	defer c.wg.Done()
 
 	log.Println("Scheduler loop started")
 
-	c.processTask()
+	last := time.Now()
+	if immediate {
+		c.processTask()
+	}
 
 	for {
+		// Runs are scheduled from the previous one; runs missed while a task was running are skipped
+		next := schedule.Next(last)
+		if now := time.Now(); !next.IsZero() && next.Before(now) {
+			next = schedule.Next(now)
+		}
+		if next.IsZero() {
+			log.Printf("⚠ Schedule %s has no upcoming run, exiting loop", schedule)
+			return
+		}
+
+		c.nextMu.Lock()
+		c.nextRun = next
+		c.nextMu.Unlock()
+		c.beat(next)
+
+		timer := time.NewTimer(time.Until(next))
+
 		select {
-		case <-c.ticker.C:
-			c.processTask()
+		case <-timer.C:
+			last = next
+			if ctx.Err() == nil {
+				c.processTask()
+			}
+
+		case <-c.wake:
+			timer.Stop()
 
-		case <-c.ctx.Done():
+		case <-c.trigger:
+			timer.Stop()
+			if ctx.Err() == nil {
+				c.processTask()
+			}
+
+		case <-ctx.Done():
+			timer.Stop()
 			log.Println("Scheduler context cancelled, exiting loop")
 			return
 		}
@@ -89,7 +272,10 @@ func (c *Client) run() {
 }
 
 // processTask executes the scheduled task
+// The loop beats before every tick, so a task hanging past TaskTimeout makes it stall
 func (c *Client) processTask() {
+	c.beat(time.Now())
+
 	if !c.taskRunning.TryLock() {
 		log.Println("⚠ Scheduler tick skipped: previous task still running")
 		return
@@ -98,10 +284,15 @@ func (c *Client) processTask() {
 
 	log.Printf("--- Scheduler tick at %s ---", time.Now().Format(time.RFC3339))
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	// Derive from the scheduler context so Stop cancels an in-flight task, Drain lets it finish
+	ctx, cancel := context.WithTimeout(c.ctx, TaskTimeout)
 	defer cancel()
 
 	err := c.task(ctx)
+	if ctxerr.IsCanceled(err) {
+		log.Println("Scheduler task cancelled")
+		return
+	}
 	if err != nil {
 		log.Printf("Error executing task: %v", err)
 		return
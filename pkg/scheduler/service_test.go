@@ -0,0 +1,67 @@
+package scheduler_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"qubit/pkg/scheduler"
+)
+
+// running calls c.Running, failing the test when it blocks
+func running(t *testing.T, c *scheduler.Client) bool {
+	t.Helper()
+
+	result := make(chan bool, 1)
+	go func() { result <- c.Running() }()
+
+	select {
+	case r := <-result:
+		return r
+	case <-time.After(5 * time.Second):
+		t.Fatal("Running() blocked")
+		return false
+	}
+}
+
+func TestDrainReportsStoppedWhileTheTaskFinishes(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	task := func(ctx context.Context) error {
+		close(started)
+		<-release
+		return ctx.Err()
+	}
+
+	c := scheduler.Run()
+	if err := c.Start(task, time.Hour); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	<-started
+
+	drained := make(chan error, 1)
+	go func() { drained <- c.Drain() }()
+
+	// Running must answer while the task in progress keeps Drain waiting
+	deadline := time.Now().Add(5 * time.Second)
+	for running(t, c) {
+		if time.Now().After(deadline) {
+			t.Fatal("Running() still reports true while draining")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	select {
+	case <-drained:
+		t.Fatal("Drain() returned before the task finished")
+	default:
+	}
+
+	close(release)
+	select {
+	case err := <-drained:
+		if err != nil {
+			t.Errorf("Drain() error = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Drain() never returned after the task finished")
+	}
+}
@@ -0,0 +1,111 @@
+package signing
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"hash"
+	"strconv"
+)
+
+// Algorithm is a signature algorithm for callback payloads
+type Algorithm string
+
+// Supported algorithms, HMAC keys are shared secrets while Ed25519 signatures are verified with a public key
+const (
+	HMACSHA256 Algorithm = "hmac-sha256"
+	HMACSHA512 Algorithm = "hmac-sha512"
+	Ed25519    Algorithm = "ed25519"
+)
+
+// hmacSecretSize is the length of generated HMAC secrets in bytes
+const hmacSecretSize = 32
+
+// ErrUnknownAlgorithm is returned for an algorithm outside the supported ones
+var ErrUnknownAlgorithm = errors.New("unknown signature algorithm")
+
+// ParseAlgorithm validates an algorithm name
+func ParseAlgorithm(s string) (Algorithm, error) {
+	switch a := Algorithm(s); a {
+	case HMACSHA256, HMACSHA512, Ed25519:
+		return a, nil
+	default:
+		return "", fmt.Errorf("%w %q, expected %s, %s or %s", ErrUnknownAlgorithm, s, HMACSHA256, HMACSHA512, Ed25519)
+	}
+}
+
+// Asymmetric reports whether signatures are verified with a public key instead of the signing secret
+func (a Algorithm) Asymmetric() bool {
+	return a == Ed25519
+}
+
+// GenerateKey creates a new signing secret, and for Ed25519 the public key verifying its signatures
+// The Ed25519 secret is the private key seed
+func GenerateKey(a Algorithm) (secret, publicKey []byte, err error) {
+	switch a {
+	case HMACSHA256, HMACSHA512:
+		secret = make([]byte, hmacSecretSize)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, nil, fmt.Errorf("failed to generate signing secret: %w", err)
+		}
+		return secret, nil, nil
+	case Ed25519:
+		public, private, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to generate signing key: %w", err)
+		}
+		return private.Seed(), public, nil
+	default:
+		return nil, nil, fmt.Errorf("%w %q", ErrUnknownAlgorithm, a)
+	}
+}
+
+// Sign signs payload with the secret of a key generated for algorithm a
+func Sign(a Algorithm, secret, payload []byte) ([]byte, error) {
+	switch a {
+	case HMACSHA256:
+		return mac(sha256.New, secret, payload), nil
+	case HMACSHA512:
+		return mac(sha512.New, secret, payload), nil
+	case Ed25519:
+		if len(secret) != ed25519.SeedSize {
+			return nil, fmt.Errorf("invalid %s seed length %d", a, len(secret))
+		}
+		return ed25519.Sign(ed25519.NewKeyFromSeed(secret), payload), nil
+	default:
+		return nil, fmt.Errorf("%w %q", ErrUnknownAlgorithm, a)
+	}
+}
+
+// Verify reports whether signature is valid for payload
+// key is the secret for HMAC algorithms and the public key for Ed25519
+func Verify(a Algorithm, key, payload, signature []byte) bool {
+	switch a {
+	case HMACSHA256:
+		return hmac.Equal(mac(sha256.New, key, payload), signature)
+	case HMACSHA512:
+		return hmac.Equal(mac(sha512.New, key, payload), signature)
+	case Ed25519:
+		return len(key) == ed25519.PublicKeySize && ed25519.Verify(key, payload, signature)
+	default:
+		return false
+	}
+}
+
+// SignedPayload is the message actually signed: the Unix timestamp and the body joined by a dot
+// Binding the timestamp lets receivers reject replayed callbacks
+func SignedPayload(timestamp int64, body []byte) []byte {
+	payload := strconv.AppendInt(nil, timestamp, 10)
+	payload = append(payload, '.')
+	return append(payload, body...)
+}
+
+func mac(h func() hash.Hash, secret, payload []byte) []byte {
+	m := hmac.New(h, secret)
+	m.Write(payload)
+	return m.Sum(nil)
+}
@@ -0,0 +1,91 @@
+package urlcheck
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// linkPattern matches links starting with a scheme or www., bare domains are not recognized as links
+var linkPattern = regexp.MustCompile(`(?i)\b(?:[a-z][a-z0-9+.-]*://|www\.)[^\s<>"']+`)
+
+// trailingPunctuation is stripped from a match, it usually ends the sentence rather than the link
+const trailingPunctuation = `.,;:!?)]}'"`
+
+// AllowList holds the domains links may point to, a domain also approves its subdomains
+type AllowList []string
+
+// NewAllowList normalizes domains to lower case without leading dots, empty entries are dropped
+func NewAllowList(domains []string) AllowList {
+	list := make(AllowList, 0, len(domains))
+	for _, domain := range domains {
+		domain = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(domain)), ".")
+		if domain != "" {
+			list = append(list, domain)
+		}
+	}
+	return list
+}
+
+// Enabled reports whether any domain is approved, an empty list approves every link
+func (a AllowList) Enabled() bool {
+	return len(a) > 0
+}
+
+// Violations returns the links in text whose host is not approved, in order of appearance
+func (a AllowList) Violations(text string) []string {
+	if !a.Enabled() {
+		return nil
+	}
+
+	var violations []string
+	for _, link := range Links(text) {
+		if !a.Allows(link) {
+			violations = append(violations, link)
+		}
+	}
+	return violations
+}
+
+// Allows reports whether the host of link is an approved domain or one of its subdomains
+// A link without a parsable host is never approved
+func (a AllowList) Allows(link string) bool {
+	host := hostOf(link)
+	if host == "" {
+		return false
+	}
+
+	for _, domain := range a {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// Links returns the links found in text
+func Links(text string) []string {
+	matches := linkPattern.FindAllString(text, -1)
+
+	links := make([]string, 0, len(matches))
+	for _, match := range matches {
+		if link := strings.TrimRight(match, trailingPunctuation); link != "" {
+			links = append(links, link)
+		}
+	}
+	return links
+}
+
+// hostOf returns the lower-case host of link without port and trailing dot
+func hostOf(link string) string {
+	if !strings.Contains(link, "://") {
+		link = "http://" + link
+	}
+
+	parsed, err := url.Parse(link)
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSuffix(strings.ToLower(parsed.Hostname()), ".")
+}
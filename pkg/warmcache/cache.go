@@ -0,0 +1,137 @@
+package warmcache
+
+import (
+	"context"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// loadTimeout bounds a single reload of the cache
+const loadTimeout = 30 * time.Second
+
+// LoadFunc loads the full contents of the cached table
+type LoadFunc[K comparable, V any] func(ctx context.Context) (map[K]V, error)
+
+// Cache keeps a read-only in-memory copy of a small, rarely changing table
+// Lookups never reach the database; the copy is reloaded when invalidated (e.g. on LISTEN/NOTIFY)
+// and at least every refresh interval, so a missed invalidation is corrected after one interval
+// A failed reload keeps serving the previous copy
+type Cache[K comparable, V any] struct {
+	name     string
+	load     LoadFunc[K, V]
+	interval time.Duration
+
+	current    atomic.Pointer[map[K]V] // immutable copy, replaced as a whole on every reload
+	invalidate chan struct{}
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// New creates a cache named name for logs, reloaded with load every interval
+func New[K comparable, V any](name string, interval time.Duration, load LoadFunc[K, V]) *Cache[K, V] {
+	return &Cache[K, V]{
+		name:       name,
+		load:       load,
+		interval:   interval,
+		invalidate: make(chan struct{}, 1),
+	}
+}
+
+// Start loads the table once and keeps it fresh in the background until Stop is called
+// A failing initial load is logged and retried on the next refresh, lookups miss meanwhile
+func (c *Cache[K, V]) Start() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.cancel != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+	c.done = make(chan struct{})
+
+	if err := c.reload(ctx); err != nil {
+		log.Printf("Warning: failed to warm %s cache: %v", c.name, err)
+	} else {
+		log.Printf("✓ %s cache warmed (%d entries)", c.name, len(*c.current.Load()))
+	}
+
+	go c.run(ctx)
+}
+
+// Stop stops the background refresh, the last copy stays readable
+func (c *Cache[K, V]) Stop() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.cancel == nil {
+		return
+	}
+
+	c.cancel()
+	<-c.done
+	c.cancel = nil
+}
+
+// Get returns the cached value of key
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	var zero V
+
+	entries := c.current.Load()
+	if entries == nil {
+		return zero, false
+	}
+
+	value, ok := (*entries)[key]
+	return value, ok
+}
+
+// Invalidate schedules a reload without waiting for it
+// Invalidations arriving while a reload is pending are coalesced into it
+func (c *Cache[K, V]) Invalidate() {
+	select {
+	case c.invalidate <- struct{}{}:
+	default:
+	}
+}
+
+// run reloads the table on every invalidation and refresh tick until ctx is cancelled
+func (c *Cache[K, V]) run(ctx context.Context) {
+	defer close(c.done)
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-c.invalidate:
+		}
+
+		if err := c.reload(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("Warning: failed to refresh %s cache, serving the previous copy: %v", c.name, err)
+		}
+	}
+}
+
+// reload replaces the current copy with a fresh load of the table
+func (c *Cache[K, V]) reload(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, loadTimeout)
+	defer cancel()
+
+	entries, err := c.load(ctx)
+	if err != nil {
+		return err
+	}
+
+	c.current.Store(&entries)
+
+	return nil
+}
@@ -0,0 +1,320 @@
+package anomaly
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"qubit/env/events"
+	"qubit/env/postgres"
+	"qubit/pkg/apperr"
+	"qubit/pkg/jsonfmt"
+	"qubit/pkg/scheduler"
+)
+
+// alertsKey is the settings key holding the alerts shared by all instances
+const alertsKey = "tenant_anomaly_alerts"
+
+// publishTimeout bounds publishing the events of new alerts
+const publishTimeout = 10 * time.Second
+
+// EventType is the type of the event published for a new alert
+const EventType = "tenant.anomaly"
+
+// Rates compared against their baseline
+const (
+	MetricCreated = "created"
+	MetricSent    = "sent"
+)
+
+var (
+	// ErrDisabled is returned when alerts are requested without anomaly detection enabled
+	ErrDisabled = apperr.NewNotFound("anomaly_detection_disabled", "tenant anomaly detection is not enabled")
+	// ErrAlertNotFound is returned when acknowledging a tenant without an alert
+	ErrAlertNotFound = apperr.NewNotFound("anomaly_alert_not_found", "tenant has no anomaly alert")
+)
+
+// Settings configure the anomaly detector
+type Settings struct {
+	Interval    time.Duration // length of the current window, compared every interval
+	Baseline    time.Duration // the period before the current window the baseline rate is averaged over
+	Factor      int           // ratio of the current to the baseline rate raising an alert
+	MinMessages int64         // messages in the current window below which no alert is raised
+	Throttle    time.Duration // how long an alerted tenant may not create messages unless acknowledged, 0 only alerts
+}
+
+// Alert is a tenant whose create or send rate deviated from its baseline
+type Alert struct {
+	TenantID int64  `json:"tenantId"`
+	Metric   string `json:"metric"`
+	// Current counts the messages of the current window, Baseline the average per window before it
+	Current    int64     `json:"current"`
+	Baseline   float64   `json:"baseline"`
+	DetectedAt time.Time `json:"detectedAt"`
+	InstanceID string    `json:"instanceId"`
+	// ThrottledUntil is when the automatic throttle ends, nil when the alert did not throttle the tenant
+	ThrottledUntil *time.Time `json:"throttledUntil,omitempty"`
+	// AcknowledgedAt lifts the throttle; the tenant is not alerted on again until a baseline period has passed
+	AcknowledgedAt *time.Time `json:"acknowledgedAt,omitempty"`
+}
+
+// Throttled reports whether the alert blocks message creation of the tenant at now
+func (a Alert) Throttled(now time.Time) bool {
+	return a.AcknowledgedAt == nil && a.ThrottledUntil != nil && a.ThrottledUntil.After(now)
+}
+
+// Service compares the create and send rates of every tenant against their recent baseline
+// A spike raises an alert, published as a tenant.anomaly event, and optionally throttles the tenant until acknowledged
+// Alerts are persisted, so every instance enforces a throttle within one interval
+type Service struct {
+	postgres   *postgres.Client
+	publisher  events.Publisher // nil without event publishing
+	scheduler  *scheduler.Client
+	settings   Settings
+	instanceID string
+
+	mu     sync.RWMutex
+	alerts map[int64]Alert
+}
+
+// NewService creates a new anomaly service and starts the detector
+func NewService(postgresClient *postgres.Client, publisher events.Publisher, instanceID string, settings Settings) *Service {
+	s := &Service{
+		postgres:   postgresClient,
+		publisher:  publisher,
+		scheduler:  scheduler.Run(),
+		settings:   settings,
+		instanceID: instanceID,
+		alerts:     make(map[int64]Alert),
+	}
+
+	if err := s.scheduler.Start(s.detectTask, settings.Interval); err != nil {
+		log.Printf("Warning: failed to start tenant anomaly detection: %v", err)
+	} else {
+		log.Printf("✓ Tenant anomaly detection started (interval: %s, baseline: %s, factor: %dx, throttle: %s)",
+			settings.Interval, settings.Baseline, settings.Factor, settings.Throttle)
+	}
+
+	return s
+}
+
+// Stop stops the detector, open alerts and throttles stay persisted
+func (s *Service) Stop() error {
+	return s.scheduler.Stop()
+}
+
+// Alerts returns the alerts known to this instance, newest first
+func (s *Service) Alerts() []Alert {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	alerts := make([]Alert, 0, len(s.alerts))
+	for _, alert := range s.alerts {
+		alerts = append(alerts, alert)
+	}
+	slices.SortFunc(alerts, func(a, b Alert) int { return b.DetectedAt.Compare(a.DetectedAt) })
+
+	return alerts
+}
+
+// Throttled returns the alert throttling a tenant, false when the tenant may create messages
+func (s *Service) Throttled(tenantID int64) (Alert, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	alert, ok := s.alerts[tenantID]
+	if !ok || !alert.Throttled(time.Now()) {
+		return Alert{}, false
+	}
+	return alert, true
+}
+
+// Acknowledge marks the alert of a tenant as handled by an operator and lifts its throttle
+// Acknowledging again keeps the first acknowledgment
+func (s *Service) Acknowledge(ctx context.Context, tenantID int64) (Alert, error) {
+	alerts, err := s.load(ctx)
+	if err != nil {
+		return Alert{}, err
+	}
+
+	alert, ok := alerts[tenantID]
+	if !ok {
+		return Alert{}, fmt.Errorf("%w: %d", ErrAlertNotFound, tenantID)
+	}
+
+	if alert.AcknowledgedAt == nil {
+		now := time.Now()
+		alert.AcknowledgedAt = &now
+		alerts[tenantID] = alert
+
+		if err := s.store(ctx, alerts); err != nil {
+			return Alert{}, err
+		}
+		log.Printf("Anomaly alert of tenant %d acknowledged", tenantID)
+	}
+
+	s.replace(alerts)
+
+	return alert, nil
+}
+
+// detectTask compares the current window of every tenant against its baseline and raises alerts on spikes
+func (s *Service) detectTask(ctx context.Context) error {
+	alerts, err := s.load(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	split := now.Add(-s.settings.Interval)
+	from := split.Add(-s.settings.Baseline)
+
+	// Acknowledged alerts are dropped once the baseline no longer holds the spike they were raised for
+	for tenantID, alert := range alerts {
+		if alert.AcknowledgedAt != nil && alert.AcknowledgedAt.Before(from) {
+			delete(alerts, tenantID)
+		}
+	}
+
+	activity, err := s.postgres.Messages.CountTenantActivity(ctx, from, split, now)
+	if err != nil {
+		return err
+	}
+
+	var raised []Alert
+	for tenantID, a := range activity {
+		if _, ok := alerts[tenantID]; ok {
+			continue
+		}
+
+		alert, ok := s.detect(tenantID, MetricCreated, a.CreatedCurrent, a.CreatedBaseline)
+		if !ok {
+			alert, ok = s.detect(tenantID, MetricSent, a.SentCurrent, a.SentBaseline)
+		}
+		if !ok {
+			continue
+		}
+
+		alerts[tenantID] = alert
+		raised = append(raised, alert)
+	}
+
+	if len(raised) > 0 {
+		if err := s.store(ctx, alerts); err != nil {
+			return err
+		}
+		s.publish(ctx, raised)
+	}
+
+	s.replace(alerts)
+
+	return nil
+}
+
+// detect raises an alert when current deviates from the baseline count by the configured factor
+// The baseline is scaled to the length of the current window; a tenant without history counts as one message
+func (s *Service) detect(tenantID int64, metric string, current, baselineCount int64) (Alert, bool) {
+	baseline := float64(baselineCount) * float64(s.settings.Interval) / float64(s.settings.Baseline)
+	if current < s.settings.MinMessages || float64(current) < float64(s.settings.Factor)*max(baseline, 1) {
+		return Alert{}, false
+	}
+
+	now := time.Now()
+	alert := Alert{
+		TenantID:   tenantID,
+		Metric:     metric,
+		Current:    current,
+		Baseline:   baseline,
+		DetectedAt: now,
+		InstanceID: s.instanceID,
+	}
+
+	if s.settings.Throttle > 0 {
+		until := now.Add(s.settings.Throttle)
+		alert.ThrottledUntil = &until
+		log.Printf("⚠ Tenant %d %s %d messages in %s against a baseline of %.1f, throttled until %s pending acknowledgment",
+			tenantID, metric, current, s.settings.Interval, baseline, until.Format(time.RFC3339))
+	} else {
+		log.Printf("⚠ Tenant %d %s %d messages in %s against a baseline of %.1f",
+			tenantID, metric, current, s.settings.Interval, baseline)
+	}
+
+	return alert, true
+}
+
+// event is the JSON document published for a new alert
+type event struct {
+	EventID    string       `json:"eventId"`
+	Type       string       `json:"type"`
+	OccurredAt jsonfmt.Time `json:"occurredAt"`
+	Alert      Alert        `json:"alert"`
+}
+
+// publish sends an event per new alert to the broker; alerts are persisted already, so a failure is only logged
+func (s *Service) publish(ctx context.Context, alerts []Alert) {
+	if s.publisher == nil {
+		return
+	}
+
+	batch := make([]events.Event, 0, len(alerts))
+	for _, alert := range alerts {
+		e := event{EventID: uuid.NewString(), Type: EventType, OccurredAt: jsonfmt.NewTime(alert.DetectedAt), Alert: alert}
+		payload, err := json.Marshal(e)
+		if err != nil {
+			log.Printf("Warning: failed to encode anomaly event: %v", err)
+			continue
+		}
+		batch = append(batch, events.Event{ID: e.EventID, Type: EventType, Key: "tenant:" + strconv.FormatInt(alert.TenantID, 10), Payload: payload})
+	}
+
+	publishCtx, cancel := context.WithTimeout(ctx, publishTimeout)
+	defer cancel()
+
+	if err := s.publisher.Publish(publishCtx, batch); err != nil {
+		log.Printf("Warning: failed to publish %d anomaly events: %v", len(batch), err)
+	}
+}
+
+// load reads the alerts persisted by any instance
+func (s *Service) load(ctx context.Context) (map[int64]Alert, error) {
+	var stored []Alert
+	if _, err := s.postgres.Settings.Get(ctx, alertsKey, &stored); err != nil {
+		return nil, fmt.Errorf("failed to load anomaly alerts: %w", err)
+	}
+
+	alerts := make(map[int64]Alert, len(stored))
+	for _, alert := range stored {
+		alerts[alert.TenantID] = alert
+	}
+	return alerts, nil
+}
+
+// store persists the alerts for every instance
+func (s *Service) store(ctx context.Context, alerts map[int64]Alert) error {
+	stored := make([]Alert, 0, len(alerts))
+	for _, alert := range alerts {
+		stored = append(stored, alert)
+	}
+	slices.SortFunc(stored, func(a, b Alert) int { return cmp.Compare(a.TenantID, b.TenantID) })
+
+	if err := s.postgres.Settings.Set(ctx, alertsKey, stored); err != nil {
+		return fmt.Errorf("failed to persist anomaly alerts: %w", err)
+	}
+	return nil
+}
+
+// replace swaps the alerts served by Alerts and Throttled
+func (s *Service) replace(alerts map[int64]Alert) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.alerts = alerts
+}
@@ -0,0 +1,17 @@
+package apikey
+
+import "context"
+
+// contextKey is the context key of the authenticated API key
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying the authenticated key
+func NewContext(ctx context.Context, key *Key) context.Context {
+	return context.WithValue(ctx, contextKey{}, key)
+}
+
+// FromContext returns the authenticated key carried by ctx
+func FromContext(ctx context.Context) (*Key, bool) {
+	key, ok := ctx.Value(contextKey{}).(*Key)
+	return key, ok
+}
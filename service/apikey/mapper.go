@@ -0,0 +1,61 @@
+package apikey
+
+import (
+	"qubit/env/postgres/apikeys"
+)
+
+// ToDomain converts a postgres Key model to a domain Key
+func ToDomain(k *apikeys.Key) *Key {
+	if k == nil {
+		return nil
+	}
+
+	scopes := make([]Scope, 0, len(k.Scopes))
+	for _, s := range k.Scopes {
+		scopes = append(scopes, Scope(s))
+	}
+
+	return &Key{
+		ID:        k.ID,
+		Name:      k.Name,
+		Prefix:    k.Prefix,
+		Scopes:    scopes,
+		CreatedAt: k.CreatedAt,
+		RevokedAt: k.RevokedAt,
+		IsTest:    k.IsTest,
+		TenantID:  k.TenantID,
+	}
+}
+
+// ToPostgres converts a domain Key and its secret to a postgres Key model
+func ToPostgres(k *Key, secret string) *apikeys.Key {
+	scopes := make([]string, 0, len(k.Scopes))
+	for _, s := range k.Scopes {
+		scopes = append(scopes, string(s))
+	}
+
+	return &apikeys.Key{
+		ID:        k.ID,
+		Name:      k.Name,
+		Prefix:    k.Prefix,
+		Hash:      hashSecret(secret),
+		Scopes:    scopes,
+		CreatedAt: k.CreatedAt,
+		IsTest:    k.IsTest,
+		TenantID:  k.TenantID,
+	}
+}
+
+// ToDomainSlice converts a slice of postgres Keys to domain Keys
+func ToDomainSlice(keys []*apikeys.Key) []*Key {
+	if keys == nil {
+		return nil
+	}
+
+	domainKeys := make([]*Key, 0, len(keys))
+	for _, k := range keys {
+		domainKeys = append(domainKeys, ToDomain(k))
+	}
+
+	return domainKeys
+}
@@ -0,0 +1,146 @@
+package apikey
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"qubit/env/postgres"
+	"qubit/env/postgres/apikeys"
+)
+
+// keyPrefix marks secrets issued by this service
+const keyPrefix = "qk_"
+
+// prefixLength is the number of leading characters kept to identify a key
+const prefixLength = 11
+
+// Service handles the business logic for API key management and authentication
+type Service struct {
+	postgres *postgres.Client
+	adminKey string // bootstrap key with admin:* scope, empty when disabled
+}
+
+// NewService creates a new API key service
+// A non-empty adminKey is accepted as a bootstrap key with the admin:* scope
+func NewService(postgresClient *postgres.Client, adminKey string) *Service {
+	return &Service{
+		postgres: postgresClient,
+		adminKey: adminKey,
+	}
+}
+
+// CreateKey issues a new API key with the given scopes, isTest issues a sandbox key
+// The returned secret is shown only once, only its hash is stored
+func (s *Service) CreateKey(ctx context.Context, name string, scopes []string, isTest bool) (*Key, string, error) {
+	k, secret, err := NewKey(name, scopes, isTest)
+	if err != nil {
+		return nil, "", err
+	}
+
+	dbKey := ToPostgres(k, secret)
+	if err := s.postgres.APIKeys.Create(ctx, dbKey); err != nil {
+		return nil, "", fmt.Errorf("failed to create api key: %w", err)
+	}
+	k.ID = dbKey.ID
+
+	log.Printf("API key %d (%s) created with scopes %v (sandbox: %t)", k.ID, k.Name, scopes, k.IsTest)
+
+	return k, secret, nil
+}
+
+// NewKey validates a key definition and generates its secret without storing the key
+// Callers persisting the key within their own transaction use ToPostgres to get the stored model
+func NewKey(name string, scopes []string, isTest bool) (*Key, string, error) {
+	k := &Key{
+		Name:      name,
+		CreatedAt: time.Now(),
+		IsTest:    isTest,
+	}
+	for _, value := range scopes {
+		scope, err := ParseScope(value)
+		if err != nil {
+			return nil, "", fmt.Errorf("%w: %v", ErrValidation, err)
+		}
+		k.Scopes = append(k.Scopes, scope)
+	}
+
+	if err := k.Validate(); err != nil {
+		return nil, "", fmt.Errorf("%w: %v", ErrValidation, err)
+	}
+
+	secret, err := newSecret()
+	if err != nil {
+		return nil, "", err
+	}
+	k.Prefix = secret[:prefixLength]
+
+	return k, secret, nil
+}
+
+// ListKeys retrieves all API keys, including revoked ones
+func (s *Service) ListKeys(ctx context.Context) ([]*Key, error) {
+	dbKeys, err := s.postgres.APIKeys.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get api keys: %w", err)
+	}
+
+	return ToDomainSlice(dbKeys), nil
+}
+
+// RevokeKey revokes an API key so it can no longer authenticate
+func (s *Service) RevokeKey(ctx context.Context, id int64) error {
+	err := s.postgres.APIKeys.Revoke(ctx, id)
+	if errors.Is(err, apikeys.ErrNotFound) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to revoke api key: %w", err)
+	}
+
+	log.Printf("API key %d revoked", id)
+
+	return nil
+}
+
+// Authenticate resolves the key presented by a client
+// Returns ErrInvalidKey if the secret is unknown or revoked
+func (s *Service) Authenticate(ctx context.Context, secret string) (*Key, error) {
+	if s.adminKey != "" && subtle.ConstantTimeCompare([]byte(secret), []byte(s.adminKey)) == 1 {
+		return &Key{
+			Name:   "bootstrap admin",
+			Scopes: []Scope{ScopeAdmin},
+		}, nil
+	}
+
+	dbKey, err := s.postgres.APIKeys.GetActiveByHash(ctx, hashSecret(secret))
+	if errors.Is(err, apikeys.ErrNotFound) {
+		return nil, ErrInvalidKey
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to authenticate api key: %w", err)
+	}
+
+	return ToDomain(dbKey), nil
+}
+
+// newSecret generates a random API key secret
+func newSecret() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate api key: %w", err)
+	}
+	return keyPrefix + hex.EncodeToString(buf), nil
+}
+
+// hashSecret returns the stored representation of a secret
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,96 @@
+package archive
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"qubit/env/postgres"
+	"qubit/pkg/scheduler"
+	"qubit/service/maintenance"
+)
+
+// batchSize is the number of messages moved per statement, keeping each transaction short
+const batchSize = 1000
+
+// Settings configure the archival of old sent messages
+type Settings struct {
+	Interval  time.Duration // how often old messages are archived
+	Retention time.Duration // how long sent messages stay in the messages table
+	Delete    bool          // delete old messages instead of moving them to messages_archive
+}
+
+// Service moves sent messages older than the retention out of the messages table,
+// keeping the table the scheduler and the API work on small
+type Service struct {
+	postgres    *postgres.Client
+	scheduler   *scheduler.Client
+	maintenance *maintenance.Service
+	settings    Settings
+}
+
+// NewService creates a new archive service and starts the archival job
+func NewService(postgresClient *postgres.Client, maintenanceService *maintenance.Service, settings Settings) *Service {
+	s := &Service{
+		postgres:    postgresClient,
+		scheduler:   scheduler.Run(),
+		maintenance: maintenanceService,
+		settings:    settings,
+	}
+
+	action := "archive"
+	if settings.Delete {
+		action = "delete"
+	}
+
+	if err := s.scheduler.Start(s.archiveTask, settings.Interval); err != nil {
+		log.Printf("Warning: failed to start message archival: %v", err)
+	} else {
+		log.Printf("✓ Message archival started (interval: %s, retention: %s, action: %s)", settings.Interval, settings.Retention, action)
+	}
+
+	return s
+}
+
+// Stop stops the archival job, a partly archived backlog is continued on the next run
+func (s *Service) Stop() error {
+	return s.scheduler.Stop()
+}
+
+// archiveTask moves every sent message older than the retention batch by batch
+func (s *Service) archiveTask(ctx context.Context) error {
+	if s.maintenance.Enabled() {
+		log.Println("Maintenance mode is enabled, skipping message archival")
+		return nil
+	}
+
+	before := time.Now().Add(-s.settings.Retention)
+
+	var total int64
+	for {
+		var moved int64
+		var err error
+		if s.settings.Delete {
+			moved, err = s.postgres.Messages.DeleteSent(ctx, before, batchSize)
+		} else {
+			moved, err = s.postgres.Messages.ArchiveSent(ctx, before, batchSize)
+		}
+		total += moved
+		if err != nil {
+			return err
+		}
+		if moved < batchSize {
+			break
+		}
+	}
+
+	if total > 0 {
+		if s.settings.Delete {
+			log.Printf("Deleted %d sent messages older than %s", total, s.settings.Retention)
+		} else {
+			log.Printf("Archived %d sent messages older than %s", total, s.settings.Retention)
+		}
+	}
+
+	return nil
+}
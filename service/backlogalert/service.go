@@ -0,0 +1,240 @@
+package backlogalert
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"qubit/env/alerthook"
+	"qubit/env/postgres"
+	"qubit/pkg/apperr"
+	"qubit/pkg/scheduler"
+)
+
+// Settings keys of the values shared by all instances
+const (
+	thresholdsKey = "backlog_alert_thresholds"
+	stateKey      = "backlog_alert_state"
+)
+
+// alertKey identifies the backlog alert at the webhook, so its resolution closes it
+const alertKey = "qubit-backlog"
+
+// notifyTimeout bounds calling the alert webhook
+const notifyTimeout = 10 * time.Second
+
+// ErrDisabled is returned when the thresholds are requested without an alert webhook configured
+var ErrDisabled = apperr.NewNotFound("backlog_alert_disabled", "backlog alerting is not enabled")
+
+// Thresholds above which the backlog raises an alert, 0 disables a check
+type Thresholds struct {
+	MaxAge    time.Duration `json:"maxAge"`
+	MaxSize   int64         `json:"maxSize"`
+	ChangedBy string        `json:"changedBy"`
+	// Since is when an operator changed the thresholds, nil for the configured ones
+	Since *time.Time `json:"since"`
+}
+
+// State is the outcome of the last backlog check
+type State struct {
+	// Firing is set while a threshold is exceeded, the webhook is only called when it changes
+	Firing bool `json:"firing"`
+	// Since is when the alert was raised or resolved, nil before the first alert
+	Since *time.Time `json:"since"`
+	// Breaches describe the exceeded thresholds, e.g. "oldest message waiting 20m0s > 15m0s"
+	Breaches []string `json:"breaches"`
+
+	Backlog   int64         `json:"backlog"`
+	Age       time.Duration `json:"age"`
+	CheckedAt *time.Time    `json:"checkedAt"`
+}
+
+// Service checks the age of the oldest due message and the backlog size every interval
+// and calls the alert webhook when a threshold is first exceeded and once the backlog recovers
+// Thresholds changed at runtime and the alert state are persisted, so every instance shares them
+// and an episode is alerted on once rather than by every instance
+type Service struct {
+	postgres   *postgres.Client
+	hook       *alerthook.Client
+	scheduler  *scheduler.Client
+	configured Thresholds
+
+	mu         sync.RWMutex
+	thresholds Thresholds
+	state      State
+}
+
+// NewService creates a new backlog alert service and starts checking every interval
+func NewService(postgresClient *postgres.Client, hook *alerthook.Client, interval time.Duration, thresholds Thresholds) *Service {
+	s := &Service{
+		postgres:   postgresClient,
+		hook:       hook,
+		scheduler:  scheduler.Run(),
+		configured: thresholds,
+		thresholds: thresholds,
+	}
+
+	if err := s.scheduler.Start(s.checkTask, interval); err != nil {
+		log.Printf("Warning: failed to start backlog alerting: %v", err)
+	} else {
+		log.Printf("✓ Backlog alerting started (interval: %s, max age: %s, max size: %d)", interval, thresholds.MaxAge, thresholds.MaxSize)
+	}
+
+	return s
+}
+
+// Stop stops the backlog checks, the alert state stays persisted
+func (s *Service) Stop() error {
+	return s.scheduler.Stop()
+}
+
+// Thresholds returns the thresholds in effect
+func (s *Service) Thresholds() Thresholds {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.thresholds
+}
+
+// State returns the outcome of the last check of this instance
+func (s *Service) State() State {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.state
+}
+
+// SetThresholds changes the thresholds of every instance, nil keeps a threshold
+// actor is the operator changing them, recorded with the thresholds and in an AUDIT log line
+func (s *Service) SetThresholds(ctx context.Context, maxAge *time.Duration, maxSize *int64, actor string) (Thresholds, error) {
+	thresholds := s.Thresholds()
+	if maxAge != nil {
+		thresholds.MaxAge = *maxAge
+	}
+	if maxSize != nil {
+		thresholds.MaxSize = *maxSize
+	}
+
+	now := time.Now()
+	thresholds.ChangedBy = actor
+	thresholds.Since = &now
+
+	if err := s.postgres.Settings.Set(ctx, thresholdsKey, thresholds); err != nil {
+		return Thresholds{}, fmt.Errorf("failed to persist backlog alert thresholds: %w", err)
+	}
+
+	log.Printf("AUDIT: %q set the backlog alert thresholds to max age %s, max size %d", actor, thresholds.MaxAge, thresholds.MaxSize)
+
+	s.mu.Lock()
+	s.thresholds = thresholds
+	s.mu.Unlock()
+
+	return thresholds, nil
+}
+
+// checkTask measures the backlog against the thresholds and alerts when the outcome changes
+// A failed webhook call leaves the persisted state, so the next check calls it again
+func (s *Service) checkTask(ctx context.Context) error {
+	thresholds, err := s.loadThresholds(ctx)
+	if err != nil {
+		return err
+	}
+
+	backlog, err := s.postgres.Messages.Backlog(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	state := State{Backlog: backlog.Count, CheckedAt: &now}
+	if backlog.OldestDue != nil {
+		state.Age = max(now.Sub(*backlog.OldestDue), 0)
+	}
+	state.Breaches = breaches(state, thresholds)
+	state.Firing = len(state.Breaches) > 0
+
+	var previous State
+	if _, err := s.postgres.Settings.Get(ctx, stateKey, &previous); err != nil {
+		return fmt.Errorf("failed to load backlog alert state: %w", err)
+	}
+	state.Since = previous.Since
+
+	if state.Firing != previous.Firing {
+		if err := s.notify(ctx, state, thresholds); err != nil {
+			s.replace(thresholds, state)
+			return fmt.Errorf("failed to call the backlog alert webhook: %w", err)
+		}
+
+		state.Since = &now
+		if err := s.postgres.Settings.Set(ctx, stateKey, state); err != nil {
+			return fmt.Errorf("failed to persist backlog alert state: %w", err)
+		}
+	}
+
+	s.replace(thresholds, state)
+
+	return nil
+}
+
+// breaches describes the thresholds the measured backlog exceeds
+func breaches(state State, thresholds Thresholds) []string {
+	var exceeded []string
+	if thresholds.MaxAge > 0 && state.Age > thresholds.MaxAge {
+		exceeded = append(exceeded, fmt.Sprintf("oldest message waiting %s > %s", state.Age.Round(time.Second), thresholds.MaxAge))
+	}
+	if thresholds.MaxSize > 0 && state.Backlog > thresholds.MaxSize {
+		exceeded = append(exceeded, fmt.Sprintf("%d messages due > %d", state.Backlog, thresholds.MaxSize))
+	}
+	return exceeded
+}
+
+// notify calls the webhook with the raised or resolved alert
+func (s *Service) notify(ctx context.Context, state State, thresholds Thresholds) error {
+	alert := alerthook.Alert{
+		Key: alertKey,
+		Details: map[string]any{
+			"backlog":    state.Backlog,
+			"ageSeconds": int64(state.Age.Seconds()),
+			"maxAge":     thresholds.MaxAge.String(),
+			"maxSize":    thresholds.MaxSize,
+		},
+	}
+
+	if state.Firing {
+		alert.Summary = "Message backlog exceeds its threshold: " + strings.Join(state.Breaches, ", ")
+		log.Printf("⚠ %s", alert.Summary)
+	} else {
+		alert.Resolved = true
+		alert.Summary = fmt.Sprintf("Message backlog recovered: %d messages due, oldest waiting %s", state.Backlog, state.Age.Round(time.Second))
+		log.Println(alert.Summary)
+	}
+
+	notifyCtx, cancel := context.WithTimeout(ctx, notifyTimeout)
+	defer cancel()
+
+	return s.hook.Notify(notifyCtx, alert)
+}
+
+// loadThresholds reads the thresholds set by any instance, the configured ones apply until an operator sets them
+func (s *Service) loadThresholds(ctx context.Context) (Thresholds, error) {
+	var thresholds Thresholds
+	found, err := s.postgres.Settings.Get(ctx, thresholdsKey, &thresholds)
+	if err != nil {
+		return Thresholds{}, fmt.Errorf("failed to load backlog alert thresholds: %w", err)
+	}
+
+	if !found {
+		thresholds = s.configured
+	}
+	return thresholds, nil
+}
+
+// replace swaps the thresholds and state served by Thresholds and State
+func (s *Service) replace(thresholds Thresholds, state State) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.thresholds = thresholds
+	s.state = state
+}
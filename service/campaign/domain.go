@@ -0,0 +1,258 @@
+package campaign
+
+import (
+	"fmt"
+	"hash/fnv"
+	"time"
+
+	"qubit/pkg/apperr"
+	"qubit/service/message"
+)
+
+// Campaign constraints
+const (
+	MaxNameLength        = 200
+	MaxRecipients        = 10000
+	MaxVariants          = 10
+	MaxVariantNameLength = 50
+)
+
+// Campaign errors
+var (
+	ErrValidation        = apperr.NewValidation(apperr.CodeValidation, "validation failed")
+	ErrInvalidTransition = apperr.NewConflict("invalid_status_transition", "invalid campaign status transition")
+	ErrSelfApproval      = apperr.NewForbidden("self_review", "campaign cannot be reviewed by its creator")
+	ErrNotFound          = apperr.NewNotFound("campaign_not_found", "campaign not found")
+	ErrConcurrentUpdate  = apperr.NewConflict("concurrent_update", "campaign status changed concurrently")
+)
+
+// Status represents the lifecycle state of a campaign
+type Status string
+
+// Campaign statuses
+const (
+	StatusDraft           Status = "draft"
+	StatusPendingApproval Status = "pending_approval"
+	StatusApproved        Status = "approved"
+	StatusScheduled       Status = "scheduled"
+	StatusRunning         Status = "running"
+	StatusCompleted       Status = "completed"
+)
+
+// transitions lists the allowed status transitions
+// A rejected campaign goes back to draft so it can be edited and resubmitted
+var transitions = map[Status][]Status{
+	StatusDraft:           {StatusPendingApproval},
+	StatusPendingApproval: {StatusApproved, StatusDraft},
+	StatusApproved:        {StatusScheduled},
+	StatusScheduled:       {StatusRunning},
+	StatusRunning:         {StatusCompleted},
+	StatusCompleted:       {},
+}
+
+// CanTransitionTo reports whether a campaign in status s may move to next
+func (s Status) CanTransitionTo(next Status) bool {
+	for _, allowed := range transitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// Campaign represents a bulk send that requires approval before it runs
+type Campaign struct {
+	ID         int64
+	Name       string
+	Content    string
+	Recipients []string
+	Status     Status
+	CreatedBy  string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+
+	ReviewedBy    *string
+	ReviewComment *string
+	ReviewedAt    *time.Time
+
+	ScheduledAt *time.Time
+	CompletedAt *time.Time
+
+	// Variants split the recipients between A/B contents, the campaign Content is empty when set
+	Variants []Variant
+}
+
+// Variant is an A/B content variant sent to Percent percent of the recipients
+type Variant struct {
+	Name    string
+	Content string
+	Percent int
+}
+
+// VariantStats reports the delivery and engagement of the messages created from a variant
+type VariantStats struct {
+	Variant string
+	Percent int
+	// Messages counts the messages created, 0 before the campaign ran
+	Messages  int64
+	Pending   int64
+	Sent      int64
+	Failed    int64
+	Cancelled int64
+	// Replied counts the sent messages the recipient replied to
+	Replied int64
+}
+
+// DeliveryRate is the share of the messages sent, 0 without messages
+func (s VariantStats) DeliveryRate() float64 {
+	if s.Messages == 0 {
+		return 0
+	}
+	return float64(s.Sent) / float64(s.Messages)
+}
+
+// ReplyRate is the share of the sent messages replied to, 0 without sent messages
+func (s VariantStats) ReplyRate() float64 {
+	if s.Sent == 0 {
+		return 0
+	}
+	return float64(s.Replied) / float64(s.Sent)
+}
+
+// Stats reports the delivery and engagement of a campaign, in total and per variant
+type Stats struct {
+	CampaignID int64
+	Totals     VariantStats
+	// Variants follows the order of the campaign variants, empty for a campaign without variants
+	Variants []VariantStats
+}
+
+// Validate checks if the campaign fields are valid
+func (c *Campaign) Validate() error {
+	if c.Name == "" {
+		return fmt.Errorf("campaign name is required")
+	}
+
+	if len(c.Name) > MaxNameLength {
+		return fmt.Errorf("campaign name exceeds maximum length of %d characters", MaxNameLength)
+	}
+
+	if len(c.Recipients) == 0 {
+		return fmt.Errorf("at least one recipient is required")
+	}
+
+	if len(c.Recipients) > MaxRecipients {
+		return fmt.Errorf("campaign exceeds maximum of %d recipients", MaxRecipients)
+	}
+
+	contents := []string{c.Content}
+	if len(c.Variants) > 0 {
+		if c.Content != "" {
+			return fmt.Errorf("content cannot be combined with variants, each variant has its own")
+		}
+		if err := c.validateVariants(); err != nil {
+			return err
+		}
+
+		contents = contents[:0]
+		for _, v := range c.Variants {
+			contents = append(contents, v.Content)
+		}
+	}
+
+	// Every recipient must form a valid message with every content
+	for _, recipient := range c.Recipients {
+		for _, content := range contents {
+			msg := &message.Message{PhoneNumber: recipient, Content: content}
+			if err := msg.Validate(); err != nil {
+				return fmt.Errorf("recipient %s: %w", recipient, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateVariants checks the variant names and that their percentages add up to 100
+func (c *Campaign) validateVariants() error {
+	if len(c.Variants) < 2 {
+		return fmt.Errorf("an A/B test needs at least 2 variants")
+	}
+	if len(c.Variants) > MaxVariants {
+		return fmt.Errorf("campaign exceeds maximum of %d variants", MaxVariants)
+	}
+
+	names := make(map[string]bool, len(c.Variants))
+	total := 0
+	for _, v := range c.Variants {
+		if v.Name == "" {
+			return fmt.Errorf("variant name is required")
+		}
+		if len(v.Name) > MaxVariantNameLength {
+			return fmt.Errorf("variant name exceeds maximum length of %d characters", MaxVariantNameLength)
+		}
+		if names[v.Name] {
+			return fmt.Errorf("variant %s is defined twice", v.Name)
+		}
+		names[v.Name] = true
+
+		if v.Percent <= 0 {
+			return fmt.Errorf("variant %s must get a positive percentage of the recipients", v.Name)
+		}
+		total += v.Percent
+	}
+
+	if total != 100 {
+		return fmt.Errorf("variant percentages must add up to 100, got %d", total)
+	}
+
+	return nil
+}
+
+// variantFor returns the variant sent to recipient, nil for a campaign without variants
+// The recipient is hashed together with the campaign ID, so it always gets the same variant within a campaign
+// while the split differs between campaigns
+func (c *Campaign) variantFor(recipient string) *Variant {
+	if len(c.Variants) == 0 {
+		return nil
+	}
+
+	h := fnv.New32a()
+	_, _ = fmt.Fprintf(h, "%d:%s", c.ID, recipient)
+	percentile := int(h.Sum32() % 100)
+
+	for i := range c.Variants {
+		if percentile < c.Variants[i].Percent {
+			return &c.Variants[i]
+		}
+		percentile -= c.Variants[i].Percent
+	}
+
+	// Unreachable while the percentages add up to 100
+	return &c.Variants[len(c.Variants)-1]
+}
+
+// messages returns the content and variant name of the message for each recipient
+func (c *Campaign) messages() (contents, variants []string) {
+	contents = make([]string, 0, len(c.Recipients))
+	variants = make([]string, 0, len(c.Recipients))
+	for _, recipient := range c.Recipients {
+		if v := c.variantFor(recipient); v != nil {
+			contents = append(contents, v.Content)
+			variants = append(variants, v.Name)
+		} else {
+			contents = append(contents, c.Content)
+			variants = append(variants, "")
+		}
+	}
+	return contents, variants
+}
+
+// TransitionTo moves the campaign to the next status if the transition is allowed
+func (c *Campaign) TransitionTo(next Status) error {
+	if !c.Status.CanTransitionTo(next) {
+		return fmt.Errorf("%w from %s to %s", ErrInvalidTransition, c.Status, next)
+	}
+	c.Status = next
+	return nil
+}
@@ -0,0 +1,107 @@
+package campaign
+
+import (
+	"qubit/env/postgres/campaigns"
+)
+
+// ToDomain converts a postgres Campaign model to a domain Campaign
+func ToDomain(c *campaigns.Campaign) *Campaign {
+	if c == nil {
+		return nil
+	}
+
+	return &Campaign{
+		ID:         c.ID,
+		Name:       c.Name,
+		Content:    c.Content,
+		Recipients: c.Recipients,
+		Status:     Status(c.Status),
+		CreatedBy:  c.CreatedBy,
+		CreatedAt:  c.CreatedAt,
+		UpdatedAt:  c.UpdatedAt,
+
+		ReviewedBy:    c.ReviewedBy,
+		ReviewComment: c.ReviewComment,
+		ReviewedAt:    c.ReviewedAt,
+
+		ScheduledAt: c.ScheduledAt,
+		CompletedAt: c.CompletedAt,
+
+		Variants: toDomainVariants(c.Variants),
+	}
+}
+
+// ToPostgres converts a domain Campaign to a postgres Campaign model
+func ToPostgres(c *Campaign) *campaigns.Campaign {
+	if c == nil {
+		return nil
+	}
+
+	return &campaigns.Campaign{
+		ID:         c.ID,
+		Name:       c.Name,
+		Content:    c.Content,
+		Recipients: c.Recipients,
+		Status:     string(c.Status),
+		CreatedBy:  c.CreatedBy,
+		CreatedAt:  c.CreatedAt,
+		UpdatedAt:  c.UpdatedAt,
+
+		ReviewedBy:    c.ReviewedBy,
+		ReviewComment: c.ReviewComment,
+		ReviewedAt:    c.ReviewedAt,
+
+		ScheduledAt: c.ScheduledAt,
+		CompletedAt: c.CompletedAt,
+
+		Variants: toPostgresVariants(c.Variants),
+	}
+}
+
+// toDomainVariants converts stored campaign variants to domain Variants
+func toDomainVariants(variants []campaigns.Variant) []Variant {
+	if len(variants) == 0 {
+		return nil
+	}
+
+	domainVariants := make([]Variant, 0, len(variants))
+	for _, v := range variants {
+		domainVariants = append(domainVariants, Variant{Name: v.Name, Content: v.Content, Percent: v.Percent})
+	}
+
+	return domainVariants
+}
+
+// toPostgresVariants converts domain Variants to their stored form
+func toPostgresVariants(variants []Variant) []campaigns.Variant {
+	dbVariants := make([]campaigns.Variant, 0, len(variants))
+	for _, v := range variants {
+		dbVariants = append(dbVariants, campaigns.Variant{Name: v.Name, Content: v.Content, Percent: v.Percent})
+	}
+
+	return dbVariants
+}
+
+// add adds the counts of stored variant stats
+func (s *VariantStats) add(vs *campaigns.VariantStats) {
+	s.Messages += vs.Messages
+	s.Pending += vs.Pending
+	s.Sent += vs.Sent
+	s.Failed += vs.Failed
+	s.Cancelled += vs.Cancelled
+	s.Replied += vs.Replied
+}
+
+// ToDomainSlice converts a slice of postgres Campaigns to domain Campaigns
+func ToDomainSlice(dbCampaigns []*campaigns.Campaign) []*Campaign {
+	if dbCampaigns == nil {
+		return nil
+	}
+
+	domainCampaigns := make([]*Campaign, 0, len(dbCampaigns))
+	for _, c := range dbCampaigns {
+		domainCampaigns = append(domainCampaigns, ToDomain(c))
+	}
+
+	return domainCampaigns
+}
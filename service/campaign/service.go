@@ -0,0 +1,276 @@
+package campaign
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"qubit/env/postgres"
+	"qubit/env/postgres/campaigns"
+	"qubit/pkg/scheduler"
+	"qubit/service/maintenance"
+)
+
+// launchBatchSize is the maximum number of due campaigns launched per tick
+const launchBatchSize = 10
+
+// Service handles the business logic for campaign operations
+type Service struct {
+	postgres    *postgres.Client
+	scheduler   *scheduler.Client
+	maintenance *maintenance.Service
+}
+
+// NewService creates a new campaign service and starts the launcher for scheduled campaigns
+func NewService(postgresClient *postgres.Client, launchIntervalMinutes int, maintenanceService *maintenance.Service) *Service {
+	s := &Service{
+		postgres:    postgresClient,
+		scheduler:   scheduler.Run(),
+		maintenance: maintenanceService,
+	}
+
+	if err := s.scheduler.Start(s.launchTask, time.Duration(launchIntervalMinutes)*time.Minute); err != nil {
+		log.Printf("Warning: failed to start campaign launcher: %v", err)
+	} else {
+		log.Printf("✓ Campaign launcher started (interval: %d minutes)", launchIntervalMinutes)
+	}
+
+	return s
+}
+
+// Stop stops the campaign launcher
+func (s *Service) Stop() error {
+	return s.scheduler.Stop()
+}
+
+// CreateCampaign creates a new campaign in draft status
+// With variants the recipients are split between their contents instead of all receiving content
+func (s *Service) CreateCampaign(ctx context.Context, name, content string, variants []Variant, recipients []string, createdBy string) (*Campaign, error) {
+	c := &Campaign{
+		Name:       name,
+		Content:    content,
+		Recipients: recipients,
+		Status:     StatusDraft,
+		CreatedBy:  createdBy,
+		CreatedAt:  time.Now(),
+		Variants:   variants,
+	}
+
+	if err := c.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrValidation, err)
+	}
+
+	dbCampaign := ToPostgres(c)
+	if err := s.postgres.Campaigns.Create(ctx, dbCampaign); err != nil {
+		return nil, fmt.Errorf("failed to create campaign: %w", err)
+	}
+
+	c.ID = dbCampaign.ID
+	c.UpdatedAt = dbCampaign.UpdatedAt
+
+	return c, nil
+}
+
+// GetCampaign retrieves a campaign by ID
+// Returns ErrNotFound if the campaign does not exist
+func (s *Service) GetCampaign(ctx context.Context, id int64) (*Campaign, error) {
+	dbCampaign, err := s.postgres.Campaigns.GetByID(ctx, id)
+	if errors.Is(err, campaigns.ErrNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get campaign: %w", err)
+	}
+
+	return ToDomain(dbCampaign), nil
+}
+
+// GetCampaigns retrieves all campaigns
+func (s *Service) GetCampaigns(ctx context.Context) ([]*Campaign, error) {
+	dbCampaigns, err := s.postgres.Campaigns.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get campaigns: %w", err)
+	}
+
+	return ToDomainSlice(dbCampaigns), nil
+}
+
+// GetStats reports the delivery and replies of the messages of a campaign, in total and per variant
+// Returns ErrNotFound if the campaign does not exist
+func (s *Service) GetStats(ctx context.Context, id int64) (*Stats, error) {
+	c, err := s.GetCampaign(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	dbStats, err := s.postgres.Campaigns.Stats(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get campaign stats: %w", err)
+	}
+
+	byVariant := make(map[string]*campaigns.VariantStats, len(dbStats))
+	for _, vs := range dbStats {
+		byVariant[vs.Variant] = vs
+	}
+
+	stats := &Stats{CampaignID: c.ID, Totals: VariantStats{Percent: 100}}
+	for _, vs := range dbStats {
+		stats.Totals.add(vs)
+	}
+
+	// Variants without messages yet are reported with zero counts
+	for _, v := range c.Variants {
+		variantStats := VariantStats{Variant: v.Name, Percent: v.Percent}
+		if vs, ok := byVariant[v.Name]; ok {
+			variantStats.add(vs)
+		}
+		stats.Variants = append(stats.Variants, variantStats)
+	}
+
+	return stats, nil
+}
+
+// SubmitForApproval moves a draft campaign to pending approval
+func (s *Service) SubmitForApproval(ctx context.Context, id int64) (*Campaign, error) {
+	return s.transition(ctx, id, func(c *Campaign) error {
+		return c.TransitionTo(StatusPendingApproval)
+	})
+}
+
+// Approve approves a campaign pending approval
+// The reviewer must be someone other than the campaign creator
+func (s *Service) Approve(ctx context.Context, id int64, reviewer, comment string) (*Campaign, error) {
+	return s.transition(ctx, id, func(c *Campaign) error {
+		if err := c.review(reviewer, comment); err != nil {
+			return err
+		}
+		return c.TransitionTo(StatusApproved)
+	})
+}
+
+// Reject sends a campaign pending approval back to draft with the reviewer's comment
+func (s *Service) Reject(ctx context.Context, id int64, reviewer, comment string) (*Campaign, error) {
+	if comment == "" {
+		return nil, fmt.Errorf("%w: a comment is required when rejecting a campaign", ErrValidation)
+	}
+
+	return s.transition(ctx, id, func(c *Campaign) error {
+		if err := c.review(reviewer, comment); err != nil {
+			return err
+		}
+		return c.TransitionTo(StatusDraft)
+	})
+}
+
+// Schedule sets the start time of an approved campaign
+func (s *Service) Schedule(ctx context.Context, id int64, scheduledAt time.Time) (*Campaign, error) {
+	return s.transition(ctx, id, func(c *Campaign) error {
+		if err := c.TransitionTo(StatusScheduled); err != nil {
+			return err
+		}
+		c.ScheduledAt = &scheduledAt
+		return nil
+	})
+}
+
+// review records the reviewer and comment on the campaign
+func (c *Campaign) review(reviewer, comment string) error {
+	if reviewer == c.CreatedBy {
+		return ErrSelfApproval
+	}
+
+	now := time.Now()
+	c.ReviewedBy = &reviewer
+	c.ReviewComment = &comment
+	c.ReviewedAt = &now
+
+	return nil
+}
+
+// transition loads a campaign, applies change and persists it if no one changed it in the meantime
+func (s *Service) transition(ctx context.Context, id int64, change func(c *Campaign) error) (*Campaign, error) {
+	c, err := s.GetCampaign(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	fromStatus := c.Status
+	if err := change(c); err != nil {
+		return nil, err
+	}
+
+	dbCampaign := ToPostgres(c)
+	err = s.postgres.Campaigns.Update(ctx, dbCampaign, string(fromStatus))
+	if errors.Is(err, campaigns.ErrStatusConflict) {
+		return nil, ErrConcurrentUpdate
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update campaign: %w", err)
+	}
+
+	c.UpdatedAt = dbCampaign.UpdatedAt
+
+	log.Printf("Campaign %d moved from %s to %s", c.ID, fromStatus, c.Status)
+
+	return c, nil
+}
+
+// launchTask is the task run by the campaign launcher, skipped while maintenance mode is enabled
+func (s *Service) launchTask(ctx context.Context) error {
+	if s.maintenance.Enabled() {
+		log.Println("Maintenance mode is enabled, skipping campaign launch")
+		return nil
+	}
+
+	return s.LaunchDueCampaigns(ctx)
+}
+
+// LaunchDueCampaigns enqueues the messages of every scheduled campaign whose start time has passed
+func (s *Service) LaunchDueCampaigns(ctx context.Context) error {
+	tx, err := s.postgres.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		// Rollback is a no-op once the transaction is committed
+		_ = tx.Rollback(ctx)
+	}()
+
+	dbCampaigns, err := s.postgres.Campaigns.ListAndLockDue(ctx, tx, launchBatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to fetch due campaigns: %w", err)
+	}
+
+	for _, c := range ToDomainSlice(dbCampaigns) {
+		if err := c.TransitionTo(StatusRunning); err != nil {
+			return err
+		}
+		if err := s.postgres.Campaigns.UpdateStatusWithTx(ctx, tx, c.ID, string(c.Status), nil); err != nil {
+			return fmt.Errorf("failed to start campaign %d: %w", c.ID, err)
+		}
+
+		contents, variants := c.messages()
+		count, err := s.postgres.Campaigns.EnqueueMessagesWithTx(ctx, tx, c.ID, c.Recipients, contents, variants)
+		if err != nil {
+			return fmt.Errorf("failed to run campaign %d: %w", c.ID, err)
+		}
+
+		if err := c.TransitionTo(StatusCompleted); err != nil {
+			return err
+		}
+		completedAt := time.Now()
+		if err := s.postgres.Campaigns.UpdateStatusWithTx(ctx, tx, c.ID, string(c.Status), &completedAt); err != nil {
+			return fmt.Errorf("failed to complete campaign %d: %w", c.ID, err)
+		}
+
+		log.Printf("✓ Campaign %d launched, %d messages enqueued", c.ID, count)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
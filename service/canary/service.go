@@ -0,0 +1,195 @@
+package canary
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"qubit/pkg/apperr"
+	"qubit/service/maintenance"
+	"qubit/service/message"
+)
+
+// pollInterval is how often a canary claimed by another instance is checked for its outcome
+const pollInterval = 500 * time.Millisecond
+
+// externalRefType links canary messages to the instance that sent them, e.g. canary:qubit-1
+const externalRefType = "canary"
+
+// DefaultContent is the content of the canary message when none is configured
+const DefaultContent = "qubit canary"
+
+// ErrDisabled is returned when a canary is triggered without a configured test number
+var ErrDisabled = apperr.NewNotFound("canary_disabled", "canary is not configured")
+
+// Triggers of a canary run
+const (
+	TriggerStartup = "startup"
+	TriggerManual  = "manual"
+)
+
+// Settings configures the canary message
+type Settings struct {
+	PhoneNumber string
+	// Provider is empty for the default provider
+	Provider string
+	Content  string
+	// Timeout bounds the whole run, from creating the message to its final status
+	Timeout time.Duration
+}
+
+// Result is the outcome of a single canary run
+type Result struct {
+	Trigger   string
+	Passed    bool
+	MessageID *int64
+	Status    message.Status
+	StartedAt time.Time
+	Duration  time.Duration
+	Error     *string
+}
+
+// Service sends a canary message through the full pipeline and keeps the outcome of the last run
+type Service struct {
+	messageService     *message.Service
+	maintenanceService *maintenance.Service
+	settings           Settings
+	instanceID         string
+
+	// runMu serializes runs so a manual trigger never overlaps the startup run
+	runMu sync.Mutex
+	last  atomic.Pointer[Result]
+}
+
+// NewService creates a new canary service
+func NewService(messageService *message.Service, maintenanceService *maintenance.Service, settings Settings, instanceID string) *Service {
+	if settings.Content == "" {
+		settings.Content = DefaultContent
+	}
+
+	return &Service{
+		messageService:     messageService,
+		maintenanceService: maintenanceService,
+		settings:           settings,
+		instanceID:         instanceID,
+	}
+}
+
+// Last returns the outcome of the last run, nil before the first run
+func (s *Service) Last() *Result {
+	return s.last.Load()
+}
+
+// Run creates a canary message, sends it right away and waits for its final status
+// The outcome is kept for the readiness report, a failed canary never fails readiness itself
+func (s *Service) Run(ctx context.Context, trigger string) *Result {
+	s.runMu.Lock()
+	defer s.runMu.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, s.settings.Timeout)
+	defer cancel()
+
+	result := &Result{
+		Trigger:   trigger,
+		StartedAt: time.Now(),
+	}
+
+	msg, err := s.send(ctx)
+	result.Duration = time.Since(result.StartedAt)
+	if msg != nil {
+		result.MessageID = &msg.ID
+		result.Status = msg.Status
+	}
+
+	switch {
+	case err != nil:
+		errMsg := err.Error()
+		result.Error = &errMsg
+	case msg.Status != message.StatusSent:
+		errMsg := s.failureReason(ctx, msg)
+		result.Error = &errMsg
+	default:
+		result.Passed = true
+	}
+
+	if result.Passed {
+		log.Printf("✓ Canary message %d sent in %v (%s)", msg.ID, result.Duration, trigger)
+	} else {
+		log.Printf("Warning: canary failed (%s): %s", trigger, *result.Error)
+	}
+
+	s.last.Store(result)
+	return result
+}
+
+// send creates the canary message and sends it without waiting for the next tick
+// The message is returned alongside the error once it was created
+func (s *Service) send(ctx context.Context) (*message.Message, error) {
+	if s.maintenanceService.Enabled() {
+		return nil, errors.New("skipped, maintenance mode is enabled")
+	}
+
+	msg, err := s.messageService.CreateMessage(ctx, s.settings.PhoneNumber, s.settings.Content, message.CreateOptions{
+		Provider:      s.settings.Provider,
+		Transactional: true,
+		Retry:         &message.RetryOverride{MaxAttempts: 1},
+		ExternalRef:   externalRefType + ":" + s.instanceID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create canary message: %w", err)
+	}
+
+	sent, err := s.messageService.SendNow(ctx, msg.ID)
+	if err == nil {
+		return sent, nil
+	}
+	if !errors.Is(err, message.ErrNotPending) {
+		return msg, fmt.Errorf("failed to send canary message: %w", err)
+	}
+
+	// The scheduler of some instance claimed the message first, wait for its outcome
+	return s.await(ctx, msg)
+}
+
+// await polls a message until it reached a final status or the context is done
+func (s *Service) await(ctx context.Context, msg *message.Message) (*message.Message, error) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return msg, fmt.Errorf("canary message %d is still %s: %w", msg.ID, msg.Status, ctx.Err())
+		case <-ticker.C:
+		}
+
+		current, err := s.messageService.GetMessage(ctx, msg.ID, nil)
+		if err != nil {
+			return msg, fmt.Errorf("failed to get canary message: %w", err)
+		}
+		msg = current
+
+		if msg.Status == message.StatusSent || msg.Status == message.StatusFailed {
+			return msg, nil
+		}
+	}
+}
+
+// failureReason returns the error of the last attempt of a message that was not sent
+func (s *Service) failureReason(ctx context.Context, msg *message.Message) string {
+	reason := fmt.Sprintf("canary message ended %s", msg.Status)
+
+	attempts, err := s.messageService.GetAttempts(context.WithoutCancel(ctx), msg.ID, nil)
+	if err != nil || len(attempts) == 0 {
+		return reason
+	}
+	if last := attempts[len(attempts)-1]; last.Error != nil {
+		reason += ": " + *last.Error
+	}
+
+	return reason
+}
@@ -0,0 +1,123 @@
+package configreload
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"qubit/env/config"
+	"qubit/env/provider"
+	"qubit/pkg/apperr"
+	"qubit/service/message"
+)
+
+// ErrInvalid is returned when the reloaded configuration fails to load or validate, the running one is kept
+var ErrInvalid = apperr.NewValidation("config_invalid", "reloaded configuration is invalid")
+
+// Configuration fields applied without a restart, besides the providers
+const (
+	fieldSchedulerInterval = "SchedulerInterval"
+	fieldSchedulerCron     = "SchedulerCron"
+	fieldMessageBatchSize  = "MessageBatchSize"
+	fieldProviders         = "Providers"
+)
+
+// Result is the outcome of a reload
+type Result struct {
+	ReloadedAt time.Time
+	// Applied lists the changed settings now in effect, e.g. "SchedulerInterval" or "provider primary"
+	Applied []string
+	// SchedulerRestarted is set when the scheduler restarted with reloaded defaults;
+	// with runtime overrides the defaults are only kept for the next scheduler reset
+	SchedulerRestarted bool
+	// RestartRequired lists the changed settings that only take effect after a restart
+	RestartRequired []string
+}
+
+// Service reloads the configuration of this instance and applies what can change without a restart:
+// the scheduler interval, cron expression and batch size, and the webhook URLs and auth keys of the providers
+type Service struct {
+	messageService *message.Service
+	providers      *provider.Registry
+
+	mu      sync.Mutex // serializes reloads
+	current *config.Config
+}
+
+// NewService creates a new config reload service starting from the configuration loaded at startup
+func NewService(cfg *config.Config, messageService *message.Service, providers *provider.Registry) *Service {
+	return &Service{
+		messageService: messageService,
+		providers:      providers,
+		current:        cfg,
+	}
+}
+
+// Reload reads the configuration again and applies the changes it can
+// actor is who asked for the reload, e.g. the operator or SIGHUP, recorded in an AUDIT log line
+func (s *Service) Reload(ctx context.Context, actor string) (*Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	next, err := config.Reload()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+
+	result := &Result{ReloadedAt: time.Now()}
+	schedulerChanged, providersChanged := false, false
+
+	for _, field := range s.current.Diff(next) {
+		switch field {
+		case fieldSchedulerInterval, fieldSchedulerCron, fieldMessageBatchSize:
+			schedulerChanged = true
+			result.Applied = append(result.Applied, field)
+		case fieldProviders:
+			providersChanged = true
+		default:
+			result.RestartRequired = append(result.RestartRequired, field)
+		}
+	}
+
+	// A reload is all or nothing: everything is validated before anything is applied, and the scheduler,
+	// the only part that can fail to apply, goes first, so a failure leaves the providers untouched
+	scheduler := message.SchedulerSettings{
+		Interval:  next.SchedulerInterval,
+		BatchSize: next.MessageBatchSize,
+		Cron:      next.SchedulerCron,
+	}
+	if schedulerChanged {
+		if err := scheduler.Validate(); err != nil {
+			return nil, fmt.Errorf("%w: scheduler: %v", ErrInvalid, err)
+		}
+
+		result.SchedulerRestarted, err = s.messageService.ReloadSchedulerDefaults(ctx, scheduler)
+		if err != nil {
+			return nil, fmt.Errorf("failed to apply the reloaded scheduler settings: %w", err)
+		}
+	}
+
+	if providersChanged {
+		updated, restartRequired := s.providers.Reconfigure(next.Providers)
+		for _, name := range updated {
+			result.Applied = append(result.Applied, "provider "+name)
+		}
+		result.RestartRequired = append(result.RestartRequired, restartRequired...)
+	}
+
+	// Only what was applied is taken over, the other changes keep being reported until a restart
+	current := *s.current
+	current.SchedulerInterval = next.SchedulerInterval
+	current.SchedulerCron = next.SchedulerCron
+	current.MessageBatchSize = next.MessageBatchSize
+	current.Providers = s.providers.Configs()
+	s.current = &current
+
+	log.Printf("AUDIT: %q reloaded the configuration, applied: [%s], restart required: [%s]",
+		actor, strings.Join(result.Applied, ", "), strings.Join(result.RestartRequired, ", "))
+
+	return result, nil
+}
@@ -0,0 +1,165 @@
+package errorstats
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Categories errors are counted under
+const (
+	CategoryValidation        = "validation"         // a request or message the caller has to correct
+	CategoryRouting           = "routing"            // no provider could be picked for a message
+	CategoryProviderPermanent = "provider_permanent" // a send failed for good, the message is failed
+	CategoryProviderTransient = "provider_transient" // a send failed and is retried
+	CategoryInternal          = "internal"           // a failure of the service itself
+)
+
+// Categories lists every category in the order they are reported
+var Categories = []string{CategoryValidation, CategoryRouting, CategoryProviderPermanent, CategoryProviderTransient, CategoryInternal}
+
+// maxErrors bounds the errors kept in memory, the oldest ones are dropped first
+const maxErrors = 10000
+
+// maxMessageLength bounds the characters kept of an error message
+const maxMessageLength = 200
+
+// MaxWindow is the longest window a summary covers
+const MaxWindow = 24 * time.Hour
+
+// entry is a single recorded error
+type entry struct {
+	at       time.Time
+	category string
+	provider string
+	code     string
+	message  string
+}
+
+// Service keeps the recent errors of this instance, from API responses and from sending messages,
+// and summarizes them by category and provider for incident response
+// Errors live in memory, so they reset on restart and only cover this instance
+type Service struct {
+	mu      sync.Mutex
+	entries []entry // ring buffer, next is the slot written next
+	next    int
+	full    bool
+}
+
+// NewService creates an empty error recorder
+func NewService() *Service {
+	return &Service{
+		entries: make([]entry, maxErrors),
+	}
+}
+
+// Record counts an error under category; provider is empty when the error is not tied to one
+func (s *Service) Record(category, provider, code, message string) {
+	if utf8.RuneCountInString(message) > maxMessageLength {
+		message = string([]rune(message)[:maxMessageLength]) + "…"
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[s.next] = entry{at: time.Now(), category: category, provider: provider, code: code, message: message}
+	s.next = (s.next + 1) % len(s.entries)
+	if s.next == 0 {
+		s.full = true
+	}
+}
+
+// Count is the number of errors of a category and provider
+type Count struct {
+	Category string
+	Provider string
+	Count    int64
+}
+
+// TopError is an error message recorded repeatedly within the window
+type TopError struct {
+	Category string
+	Provider string
+	Code     string
+	Message  string
+	Count    int64
+	LastSeen time.Time
+}
+
+// Summary aggregates the errors recorded within a window
+type Summary struct {
+	Window time.Duration
+	Total  int64
+	// ByCategory counts the errors of every category, including the ones without errors
+	ByCategory map[string]int64
+	// ByProvider counts the errors per category and provider, most frequent first
+	ByProvider []Count
+	// TopErrors are the most frequent error messages, most frequent first
+	TopErrors []TopError
+	// Truncated is set when older errors within the window were dropped to bound memory
+	Truncated bool
+}
+
+// Summarize aggregates the errors of the last window, listing up to top error messages
+func (s *Service) Summarize(window time.Duration, top int) Summary {
+	since := time.Now().Add(-min(window, MaxWindow))
+
+	summary := Summary{
+		Window:     window,
+		ByCategory: make(map[string]int64, len(Categories)),
+	}
+	for _, category := range Categories {
+		summary.ByCategory[category] = 0
+	}
+
+	byProvider := make(map[Count]int64)
+	byMessage := make(map[TopError]*TopError)
+
+	s.mu.Lock()
+	for _, e := range s.entries {
+		if e.at.IsZero() || e.at.Before(since) {
+			continue
+		}
+
+		summary.Total++
+		summary.ByCategory[e.category]++
+		byProvider[Count{Category: e.category, Provider: e.provider}]++
+
+		key := TopError{Category: e.category, Provider: e.provider, Code: e.code, Message: e.message}
+		if t, ok := byMessage[key]; ok {
+			t.Count++
+			if e.at.After(t.LastSeen) {
+				t.LastSeen = e.at
+			}
+		} else {
+			t := key
+			t.Count, t.LastSeen = 1, e.at
+			byMessage[key] = &t
+		}
+	}
+	// The ring is full and its oldest error is still within the window, so older ones were dropped
+	summary.Truncated = s.full && !s.entries[s.next].at.Before(since)
+	s.mu.Unlock()
+
+	for count, n := range byProvider {
+		count.Count = n
+		summary.ByProvider = append(summary.ByProvider, count)
+	}
+	slices.SortFunc(summary.ByProvider, func(a, b Count) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), cmp.Compare(a.Category, b.Category), cmp.Compare(a.Provider, b.Provider))
+	})
+
+	for _, t := range byMessage {
+		summary.TopErrors = append(summary.TopErrors, *t)
+	}
+	slices.SortFunc(summary.TopErrors, func(a, b TopError) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), b.LastSeen.Compare(a.LastSeen))
+	})
+	if len(summary.TopErrors) > top {
+		summary.TopErrors = summary.TopErrors[:top]
+	}
+
+	return summary
+}
@@ -0,0 +1,150 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"qubit/env/events"
+	"qubit/env/postgres"
+	"qubit/pkg/scheduler"
+)
+
+// publishTimeout bounds a single publish of a batch to the broker
+const publishTimeout = 30 * time.Second
+
+// pruneInterval is how often published events older than the retention are removed
+const pruneInterval = time.Hour
+
+// Settings configure the outbox relay
+type Settings struct {
+	Interval  time.Duration // how often the outbox is polled
+	BatchSize int           // events published per broker call
+	Retention time.Duration // how long published events are kept in the outbox
+}
+
+// Service relays the lifecycle events written to the outbox by the message service to the broker
+// Events are published at least once and in outbox order per relay; consumers deduplicate on the event ID
+type Service struct {
+	postgres   *postgres.Client
+	publisher  events.Publisher
+	scheduler  *scheduler.Client
+	settings   Settings
+	lastPruned time.Time
+}
+
+// NewService creates a new event service and starts the outbox relay
+func NewService(postgresClient *postgres.Client, publisher events.Publisher, settings Settings) *Service {
+	s := &Service{
+		postgres:  postgresClient,
+		publisher: publisher,
+		scheduler: scheduler.Run(),
+		settings:  settings,
+	}
+
+	if err := s.scheduler.Start(s.relayTask, settings.Interval); err != nil {
+		log.Printf("Warning: failed to start outbox relay: %v", err)
+	} else {
+		log.Printf("✓ Outbox relay started (interval: %s, batch size: %d)", settings.Interval, settings.BatchSize)
+	}
+
+	return s
+}
+
+// Stop stops the outbox relay and closes the broker connection
+func (s *Service) Stop() error {
+	if err := s.scheduler.Stop(); err != nil {
+		return err
+	}
+	return s.publisher.Close()
+}
+
+// relayTask publishes every pending event batch by batch, then prunes old published events
+func (s *Service) relayTask(ctx context.Context) error {
+	for {
+		published, err := s.relayBatch(ctx)
+		if err != nil {
+			if pending, countErr := s.postgres.Outbox.CountUnpublished(ctx); countErr == nil {
+				log.Printf("⚠ Event publishing failed, %d events waiting in the outbox", pending)
+			}
+			return err
+		}
+		if published < s.settings.BatchSize {
+			break
+		}
+	}
+
+	if time.Since(s.lastPruned) >= pruneInterval {
+		s.lastPruned = time.Now()
+		pruned, err := s.postgres.Outbox.DeletePublished(ctx, time.Now().Add(-s.settings.Retention))
+		if err != nil {
+			log.Printf("Warning: %v", err)
+		} else if pruned > 0 {
+			log.Printf("Pruned %d published events from the outbox", pruned)
+		}
+	}
+
+	return nil
+}
+
+// relayBatch publishes the next batch of unpublished events and returns how many were published
+// The events stay locked until the outcome is committed, so concurrent relays never publish the same batch;
+// if the commit fails after a successful publish the batch is published again
+func (s *Service) relayBatch(ctx context.Context) (int, error) {
+	tx, err := s.postgres.BeginTx(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if rbErr := tx.Rollback(ctx); rbErr != nil && !errors.Is(rbErr, pgx.ErrTxClosed) {
+			log.Printf("Warning: failed to rollback transaction: %v", rbErr)
+		}
+	}()
+
+	pending, err := s.postgres.Outbox.ClaimUnpublishedWithTx(ctx, tx, s.settings.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+	if len(pending) == 0 {
+		return 0, nil
+	}
+
+	ids := make([]int64, len(pending))
+	batch := make([]events.Event, len(pending))
+	for i, e := range pending {
+		ids[i] = e.ID
+		batch[i] = events.Event{
+			ID:      e.EventID,
+			Type:    e.EventType,
+			Key:     strconv.FormatInt(e.MessageID, 10),
+			Payload: e.Payload,
+		}
+	}
+
+	publishCtx, cancel := context.WithTimeout(ctx, publishTimeout)
+	publishErr := s.publisher.Publish(publishCtx, batch)
+	cancel()
+
+	if publishErr != nil {
+		if err := s.postgres.Outbox.MarkFailedWithTx(ctx, tx, ids, publishErr.Error()); err != nil {
+			log.Printf("Warning: %v", err)
+		} else if err := tx.Commit(ctx); err != nil {
+			log.Printf("Warning: failed to commit outbox publish failure: %v", err)
+		}
+		return 0, fmt.Errorf("failed to publish %d events: %w", len(batch), publishErr)
+	}
+
+	if err := s.postgres.Outbox.MarkPublishedWithTx(ctx, tx, ids, time.Now()); err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit published events: %w", err)
+	}
+
+	return len(batch), nil
+}
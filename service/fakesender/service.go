@@ -0,0 +1,152 @@
+package fakesender
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"qubit/env/postgres"
+	"qubit/env/provider"
+	"qubit/pkg/apperr"
+	"qubit/pkg/scheduler"
+)
+
+// settingsKey is the settings key holding the fake sender mode shared by all instances
+const settingsKey = "fake_sender"
+
+// syncInterval is how often an instance picks up a mode switched on another instance
+const syncInterval = time.Minute
+
+// Banner is shown by /health while messages go through the simulated sender
+const Banner = "FAKE SENDER: messages are simulated and never reach a real provider"
+
+// ErrLocked is returned when switching to the real providers while FAKE_SENDER_LOCKED is set
+var ErrLocked = apperr.NewForbidden("fake_sender_locked", "the fake sender is locked by configuration")
+
+// Mode describes whether messages go through the simulated sender instead of the real providers
+type Mode struct {
+	Enabled bool `json:"enabled"`
+	// Locked is set by configuration, it is never persisted
+	Locked    bool       `json:"-"`
+	Reason    string     `json:"reason"`
+	ChangedBy string     `json:"changedBy"`
+	Since     *time.Time `json:"since"`
+}
+
+// Service holds the fake sender mode, persisted as a runtime setting, and applies it to the provider registry
+// Without a persisted mode the configured one applies; the last known mode is kept when the database cannot be read
+type Service struct {
+	postgres  *postgres.Client
+	providers *provider.Registry
+	scheduler *scheduler.Client
+	initial   Mode
+
+	mu   sync.RWMutex
+	mode Mode
+}
+
+// NewService creates a new fake sender service, loads the persisted mode and keeps it in sync
+// The configured mode applies before anything is loaded, so no message reaches a real provider by accident
+func NewService(postgresClient *postgres.Client, providers *provider.Registry, enabled, locked bool) *Service {
+	s := &Service{
+		postgres:  postgresClient,
+		providers: providers,
+		scheduler: scheduler.Run(),
+		initial:   Mode{Enabled: enabled || locked, Locked: locked},
+	}
+	s.apply(s.initial)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := s.Sync(ctx); err != nil {
+		log.Printf("Warning: failed to load fake sender mode: %v", err)
+	}
+
+	if err := s.scheduler.Start(s.Sync, syncInterval); err != nil {
+		log.Printf("Warning: failed to start fake sender mode sync: %v", err)
+	}
+
+	return s
+}
+
+// Stop stops syncing the fake sender mode
+func (s *Service) Stop() error {
+	return s.scheduler.Stop()
+}
+
+// Mode returns the current fake sender mode
+func (s *Service) Mode() Mode {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.mode
+}
+
+// Set switches every instance to the simulated sender or back to the real providers
+// actor is the operator switching it, recorded with the mode and in an AUDIT log line
+func (s *Service) Set(ctx context.Context, enabled bool, reason, actor string) (Mode, error) {
+	if s.initial.Locked && !enabled {
+		return Mode{}, ErrLocked
+	}
+
+	now := time.Now()
+	mode := Mode{Enabled: enabled, Locked: s.initial.Locked, Reason: reason, ChangedBy: actor, Since: &now}
+
+	// Keep the original start when the mode does not change
+	if current := s.Mode(); current.Enabled == enabled && current.Since != nil {
+		mode.Since = current.Since
+	}
+
+	if err := s.postgres.Settings.Set(ctx, settingsKey, mode); err != nil {
+		return Mode{}, fmt.Errorf("failed to persist fake sender mode: %w", err)
+	}
+
+	state := "real providers"
+	if enabled {
+		state = "fake sender"
+	}
+	log.Printf("AUDIT: %q switched sending to the %s: %q", actor, state, reason)
+
+	s.apply(mode)
+
+	return mode, nil
+}
+
+// Sync reloads the fake sender mode persisted by any instance
+// This is the task run by the sync scheduler
+func (s *Service) Sync(ctx context.Context) error {
+	var mode Mode
+	found, err := s.postgres.Settings.Get(ctx, settingsKey, &mode)
+	if err != nil {
+		return fmt.Errorf("failed to load fake sender mode: %w", err)
+	}
+
+	if !found {
+		mode = s.initial
+	}
+	mode.Locked = s.initial.Locked
+	mode.Enabled = mode.Enabled || mode.Locked
+
+	s.apply(mode)
+
+	return nil
+}
+
+// apply switches the providers to mode, logging transitions
+func (s *Service) apply(mode Mode) {
+	s.mu.Lock()
+	previous := s.mode
+	s.mode = mode
+	s.mu.Unlock()
+
+	s.providers.SetFake(mode.Enabled)
+
+	switch {
+	case mode.Enabled && !previous.Enabled:
+		log.Println("⚠ Fake sender enabled, messages are simulated and never reach a real provider")
+	case !mode.Enabled && previous.Enabled:
+		log.Println("Fake sender disabled, messages are sent through the real providers")
+	}
+}
@@ -0,0 +1,173 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"qubit/env/postgres"
+	"qubit/env/provider"
+	"qubit/service/canary"
+	"qubit/service/message"
+)
+
+// checkTimeout bounds every single dependency check of a readiness probe
+const checkTimeout = 3 * time.Second
+
+// Check names
+const (
+	CheckDatabase = "database"
+	// CheckWebhookPrefix is followed by the provider name, e.g. webhook:primary
+	CheckWebhookPrefix = "webhook:"
+)
+
+// Check is the outcome of probing a single dependency
+type Check struct {
+	Name    string
+	Healthy bool
+	Latency time.Duration
+	Error   *string
+}
+
+// Report is the readiness of this instance
+// Ready is false as soon as one check failed; the scheduler state and the canary are reported but never fail readiness
+type Report struct {
+	Ready     bool
+	Checks    []Check
+	Scheduler message.SchedulerStatus
+	// Canary is the last canary run, nil when the canary is disabled or did not run yet
+	Canary *canary.Result
+}
+
+// Liveness is the liveness of this instance
+// Alive is false once the running scheduler loop stalled, so the instance is restarted; the API is not checked
+type Liveness struct {
+	Alive     bool
+	Scheduler message.SchedulerStatus
+	// HeartbeatAge is the time since the last scheduler heartbeat, zero before the scheduler first started
+	HeartbeatAge time.Duration
+	Stalled      bool
+}
+
+// Service probes the dependencies an instance needs to serve traffic
+type Service struct {
+	postgres       *postgres.Client
+	providers      *provider.Registry
+	messageService *message.Service
+	canaryService  *canary.Service
+	probeWebhooks  bool
+	stallTimeout   time.Duration
+}
+
+// NewService creates a new health service
+// probeWebhooks adds the reachability of every webhook provider to the readiness checks
+// canaryService is nil when no canary test number is configured
+// stallTimeout is how overdue the scheduler loop may be before liveness fails, zero never fails it
+func NewService(postgresClient *postgres.Client, providers *provider.Registry, messageService *message.Service, canaryService *canary.Service, probeWebhooks bool, stallTimeout time.Duration) *Service {
+	return &Service{
+		postgres:       postgresClient,
+		providers:      providers,
+		messageService: messageService,
+		canaryService:  canaryService,
+		probeWebhooks:  probeWebhooks,
+		stallTimeout:   stallTimeout,
+	}
+}
+
+// Liveness reports whether the scheduler loop of this instance still beats
+// It checks no dependency, a database outage only fails readiness
+func (s *Service) Liveness() Liveness {
+	liveness := Liveness{
+		Alive:     true,
+		Scheduler: s.messageService.SchedulerStatus(),
+	}
+	if heartbeat := liveness.Scheduler.Heartbeat; heartbeat != nil {
+		liveness.HeartbeatAge = time.Since(*heartbeat)
+	}
+	if s.stallTimeout > 0 && s.messageService.SchedulerStalled(s.stallTimeout) {
+		liveness.Alive = false
+		liveness.Stalled = true
+	}
+
+	return liveness
+}
+
+// RunCanary sends a canary message right away and returns its outcome
+// Returns canary.ErrDisabled when no canary test number is configured
+func (s *Service) RunCanary(ctx context.Context) (*canary.Result, error) {
+	if s.canaryService == nil {
+		return nil, canary.ErrDisabled
+	}
+
+	return s.canaryService.Run(ctx, canary.TriggerManual), nil
+}
+
+// Readiness runs every dependency check concurrently and reports the result in a stable order
+func (s *Service) Readiness(ctx context.Context) Report {
+	probes := []probe{
+		{name: CheckDatabase, run: s.postgres.Ping},
+	}
+
+	if s.probeWebhooks {
+		for _, name := range s.providers.Names() {
+			if prober, ok := s.providers.Prober(name); ok {
+				probes = append(probes, probe{name: CheckWebhookPrefix + name, run: prober.Probe})
+			}
+		}
+	}
+
+	checks := make([]Check, len(probes))
+
+	var wg sync.WaitGroup
+	for i, p := range probes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			checks[i] = p.check(ctx)
+		}()
+	}
+	wg.Wait()
+
+	report := Report{
+		Ready:     true,
+		Checks:    checks,
+		Scheduler: s.messageService.SchedulerStatus(),
+	}
+	if s.canaryService != nil {
+		report.Canary = s.canaryService.Last()
+	}
+	for _, check := range checks {
+		if !check.Healthy {
+			report.Ready = false
+		}
+	}
+
+	return report
+}
+
+// probe is a named dependency check
+type probe struct {
+	name string
+	run  func(ctx context.Context) error
+}
+
+// check runs the probe within checkTimeout and records its latency
+func (p probe) check(ctx context.Context) Check {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := p.run(ctx)
+
+	check := Check{
+		Name:    p.name,
+		Healthy: err == nil,
+		Latency: time.Since(start),
+	}
+	if err != nil {
+		errMsg := err.Error()
+		check.Error = &errMsg
+	}
+
+	return check
+}
@@ -0,0 +1,61 @@
+package ingest
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// maxRecipients bounds the fan-out of a single queued request, as on the HTTP API
+const maxRecipients = 100
+
+// Request is a message creation request read from the queue, with the fields of POST /messages
+// Setting UUID makes the request idempotent: redeliveries update the same pending message
+type Request struct {
+	UUID          *string           `json:"uuid"`
+	PhoneNumber   string            `json:"phoneNumber"`
+	Content       string            `json:"content"`
+	Provider      string            `json:"provider"`
+	ScheduledAt   *time.Time        `json:"scheduledAt"`
+	Transactional bool              `json:"transactional"`
+	TemplateID    *int64            `json:"templateId"`
+	Variables     map[string]string `json:"variables"`
+	Recipients    []string          `json:"recipients"`
+	RetryPolicy   *RetryPolicy      `json:"retryPolicy"`
+	ExternalRef   string            `json:"externalRef"`
+	Metadata      map[string]string `json:"metadata"`
+}
+
+// RetryPolicy overrides the configured retry policy of the message, zero fields keep the configuration
+type RetryPolicy struct {
+	MaxAttempts      int    `json:"maxAttempts"`
+	Backoff          string `json:"backoff"`
+	BaseDelaySeconds int    `json:"baseDelaySeconds"`
+	MaxDelaySeconds  int    `json:"maxDelaySeconds"`
+}
+
+// Validate checks the request shape, the message itself is validated by the message service
+func (r *Request) Validate() error {
+	if r.UUID != nil {
+		if _, err := uuid.Parse(*r.UUID); err != nil {
+			return fmt.Errorf("uuid must be a valid UUID")
+		}
+		if len(r.Recipients) > 0 {
+			return fmt.Errorf("recipients are only supported without uuid")
+		}
+	}
+
+	if len(r.Recipients) > 0 && r.PhoneNumber != "" {
+		return fmt.Errorf("phoneNumber and recipients are mutually exclusive")
+	}
+	if len(r.Recipients) > maxRecipients {
+		return fmt.Errorf("at most %d recipients are allowed", maxRecipients)
+	}
+
+	if r.TemplateID != nil && r.Content != "" {
+		return fmt.Errorf("content and templateId are mutually exclusive")
+	}
+
+	return nil
+}
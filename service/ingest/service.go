@@ -0,0 +1,164 @@
+package ingest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"qubit/env/queue"
+	"qubit/service/maintenance"
+	"qubit/service/message"
+	"qubit/service/template"
+)
+
+// restartDelay is the pause before consuming again after the broker connection failed
+const restartDelay = 5 * time.Second
+
+// errMaintenance holds deliveries back while maintenance mode is enabled, like the HTTP API rejects writes
+var errMaintenance = errors.New("maintenance mode is enabled")
+
+// Service ingests message creation requests from a queue through the same domain logic as the HTTP API
+type Service struct {
+	messages    *message.Service
+	maintenance *maintenance.Service
+	consumer    queue.Consumer
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewService creates a new ingest service and starts consuming
+func NewService(messageService *message.Service, maintenanceService *maintenance.Service, consumer queue.Consumer) *Service {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	s := &Service{
+		messages:    messageService,
+		maintenance: maintenanceService,
+		consumer:    consumer,
+		cancel:      cancel,
+		done:        make(chan struct{}),
+	}
+
+	go s.run(ctx)
+
+	log.Println("✓ Queue ingestion started")
+
+	return s
+}
+
+// Stop stops consuming, waits for the deliveries in flight and closes the broker connection
+func (s *Service) Stop() error {
+	s.cancel()
+	<-s.done
+	return s.consumer.Close()
+}
+
+// run consumes until Stop is called, reconnecting after broker failures
+func (s *Service) run(ctx context.Context) {
+	defer close(s.done)
+
+	for {
+		err := s.consumer.Run(ctx, s.ingest)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("Warning: queue ingestion stopped, restarting in %s: %v", restartDelay, err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(restartDelay):
+		}
+	}
+}
+
+// ingest creates the messages of one queued request
+// Requests the API would answer with 4xx can never succeed and are dropped, everything else is retried
+func (s *Service) ingest(ctx context.Context, d queue.Delivery) error {
+	if s.maintenance.Enabled() {
+		return errMaintenance
+	}
+
+	var req Request
+	decoder := json.NewDecoder(bytes.NewReader(d.Body))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		return queue.Permanent(fmt.Errorf("invalid request: %w", err))
+	}
+	if err := req.Validate(); err != nil {
+		return queue.Permanent(fmt.Errorf("invalid request: %w", err))
+	}
+
+	opts := createOptions(req)
+
+	var err error
+	switch {
+	case len(req.Recipients) > 0:
+		var fanout *message.Fanout
+		if fanout, err = s.messages.CreateFanout(ctx, req.Recipients, req.Content, opts); err == nil {
+			log.Printf("Ingested fan-out %s with %d messages from %s", fanout.ID, len(fanout.Messages), d.Source)
+		}
+	case req.UUID != nil:
+		var msg *message.Message
+		var created bool
+		msg, created, err = s.messages.UpsertMessage(ctx, *req.UUID, req.PhoneNumber, req.Content, opts)
+		if errors.Is(err, message.ErrNotPending) {
+			// A redelivery of a request whose message was already sent
+			log.Printf("Message %s from %s already left pending, skipping", *req.UUID, d.Source)
+			return nil
+		}
+		if err == nil && created {
+			log.Printf("Ingested message %d from %s", msg.ID, d.Source)
+		}
+	default:
+		var msg *message.Message
+		if msg, err = s.messages.CreateMessage(ctx, req.PhoneNumber, req.Content, opts); err == nil {
+			log.Printf("Ingested message %d from %s", msg.ID, d.Source)
+		}
+	}
+
+	if isRejected(err) {
+		return queue.Permanent(err)
+	}
+	return err
+}
+
+// createOptions converts a queued request into message creation options
+// Queued messages are live messages created without a caller role
+func createOptions(req Request) message.CreateOptions {
+	opts := message.CreateOptions{
+		Provider:      req.Provider,
+		ScheduledAt:   req.ScheduledAt,
+		Transactional: req.Transactional,
+		TemplateID:    req.TemplateID,
+		Variables:     req.Variables,
+		ExternalRef:   req.ExternalRef,
+		Metadata:      req.Metadata,
+	}
+
+	if req.RetryPolicy != nil {
+		opts.Retry = &message.RetryOverride{
+			MaxAttempts: req.RetryPolicy.MaxAttempts,
+			Backoff:     message.Backoff(req.RetryPolicy.Backoff),
+			BaseDelay:   time.Duration(req.RetryPolicy.BaseDelaySeconds) * time.Second,
+			MaxDelay:    time.Duration(req.RetryPolicy.MaxDelaySeconds) * time.Second,
+		}
+	}
+
+	return opts
+}
+
+// isRejected reports whether err rejects the request itself rather than reporting a transient failure
+func isRejected(err error) bool {
+	return errors.Is(err, message.ErrValidation) ||
+		errors.Is(err, message.ErrUnknownProvider) ||
+		errors.Is(err, message.ErrNotSandbox) ||
+		errors.Is(err, message.ErrProviderForbidden) ||
+		errors.Is(err, message.ErrSandboxProvider) ||
+		errors.Is(err, template.ErrNotFound) ||
+		errors.Is(err, template.ErrMissingVariable)
+}
@@ -0,0 +1,136 @@
+package leader
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"qubit/pkg/scheduler"
+)
+
+// Election backends
+const (
+	BackendPostgres = "postgres"
+	BackendRedis    = "redis"
+)
+
+// Elector takes and keeps leadership, implemented by postgres.LeaderLock and redis.LeaderLease
+type Elector interface {
+	// Campaign takes leadership when it is free and renews it while held
+	Campaign(ctx context.Context) (bool, error)
+	// Resign gives up leadership so another instance can take over right away
+	Resign(ctx context.Context) error
+}
+
+// Status is the leader election state of this instance
+type Status struct {
+	Backend string
+	Leader  bool
+	// Since is when this instance became leader or follower
+	Since time.Time
+	// LastError is the error of the last campaign, nil when it succeeded
+	LastError *string
+}
+
+// Service campaigns for leadership every renew interval until it is stopped
+// A failed campaign demotes the instance, so two instances never lead at the same time
+type Service struct {
+	elector   Elector
+	backend   string
+	renew     time.Duration
+	scheduler *scheduler.Client
+
+	mu         sync.RWMutex
+	status     Status
+	campaigned bool // the first outcome is logged even when it does not change the role
+}
+
+// NewService creates a new leader election service and starts campaigning right away
+// renew must be well below the lease TTL of the elector, so a live leader never loses its lease
+func NewService(elector Elector, backend string, renew time.Duration) *Service {
+	s := &Service{
+		elector:   elector,
+		backend:   backend,
+		renew:     renew,
+		scheduler: scheduler.Run(),
+		status: Status{
+			Backend: backend,
+			Since:   time.Now(),
+		},
+	}
+
+	if err := s.scheduler.Start(s.campaign, renew); err != nil {
+		log.Printf("Warning: failed to start leader election: %v", err)
+	}
+
+	return s
+}
+
+// IsLeader reports whether this instance currently leads
+func (s *Service) IsLeader() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.status.Leader
+}
+
+// Status returns the leader election state of this instance
+func (s *Service) Status() Status {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.status
+}
+
+// Stop stops campaigning and resigns, so a follower takes over without waiting for the lease to expire
+func (s *Service) Stop() error {
+	if err := s.scheduler.Stop(); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	s.setLeader(false, nil)
+	return s.elector.Resign(ctx)
+}
+
+// campaign takes or renews leadership within one renew interval
+func (s *Service) campaign(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, s.renew)
+	defer cancel()
+
+	leader, err := s.elector.Campaign(ctx)
+	s.setLeader(leader, err)
+
+	return err
+}
+
+// setLeader records the outcome of a campaign and logs leadership changes
+func (s *Service) setLeader(leader bool, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.status.LastError = nil
+	if err != nil {
+		errMsg := err.Error()
+		s.status.LastError = &errMsg
+	}
+
+	if s.status.Leader == leader && s.campaigned {
+		return
+	}
+
+	if s.status.Leader != leader {
+		s.status.Leader = leader
+		s.status.Since = time.Now()
+	}
+	s.campaigned = true
+
+	if leader {
+		log.Printf("✓ Elected scheduler leader (%s)", s.backend)
+	} else {
+		log.Printf("Scheduler leadership lost or held by another instance (%s), standing by", s.backend)
+	}
+}
@@ -0,0 +1,138 @@
+package maintenance
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"qubit/env/postgres"
+	"qubit/pkg/scheduler"
+)
+
+// settingsKey is the settings key holding the maintenance mode shared by all instances
+const settingsKey = "maintenance"
+
+// syncInterval is how often an instance picks up a mode toggled on another instance
+const syncInterval = time.Minute
+
+// DefaultMessage is returned to rejected callers when the operator gave no reason
+const DefaultMessage = "The service is in read-only maintenance mode, retry later"
+
+// Mode describes whether the API is read-only and the scheduler paused
+type Mode struct {
+	Enabled bool       `json:"enabled"`
+	Message string     `json:"message"`
+	Since   *time.Time `json:"since"`
+}
+
+// Service holds the maintenance mode, persisted as a runtime setting
+// The last known mode is kept when the database cannot be read, e.g. during a failover
+type Service struct {
+	postgres  *postgres.Client
+	scheduler *scheduler.Client
+
+	mu   sync.RWMutex
+	mode Mode
+}
+
+// NewService creates a new maintenance service, loads the persisted mode and keeps it in sync
+func NewService(postgresClient *postgres.Client) *Service {
+	s := &Service{
+		postgres:  postgresClient,
+		scheduler: scheduler.Run(),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := s.Sync(ctx); err != nil {
+		log.Printf("Warning: failed to load maintenance mode: %v", err)
+	} else if s.Enabled() {
+		log.Printf("✓ Maintenance mode is enabled: %s", s.Mode().Message)
+	}
+
+	if err := s.scheduler.Start(s.Sync, syncInterval); err != nil {
+		log.Printf("Warning: failed to start maintenance mode sync: %v", err)
+	}
+
+	return s
+}
+
+// Stop stops syncing the maintenance mode
+func (s *Service) Stop() error {
+	return s.scheduler.Stop()
+}
+
+// Mode returns the current maintenance mode
+func (s *Service) Mode() Mode {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.mode
+}
+
+// Enabled reports whether the API is read-only and the scheduler paused
+func (s *Service) Enabled() bool {
+	return s.Mode().Enabled
+}
+
+// Set enables or disables maintenance mode on every instance
+// An empty message falls back to DefaultMessage
+func (s *Service) Set(ctx context.Context, enabled bool, message string) (Mode, error) {
+	mode := Mode{}
+	if enabled {
+		if message == "" {
+			message = DefaultMessage
+		}
+
+		now := time.Now()
+		mode = Mode{Enabled: true, Message: message, Since: &now}
+
+		// Keep the original start when only the message changes
+		if current := s.Mode(); current.Enabled {
+			mode.Since = current.Since
+		}
+	}
+
+	if err := s.postgres.Settings.Set(ctx, settingsKey, mode); err != nil {
+		return Mode{}, fmt.Errorf("failed to persist maintenance mode: %w", err)
+	}
+
+	s.apply(mode)
+
+	return mode, nil
+}
+
+// Sync reloads the maintenance mode persisted by any instance
+// This is the task run by the sync scheduler
+func (s *Service) Sync(ctx context.Context) error {
+	var mode Mode
+	found, err := s.postgres.Settings.Get(ctx, settingsKey, &mode)
+	if err != nil {
+		return fmt.Errorf("failed to load maintenance mode: %w", err)
+	}
+
+	if !found {
+		mode = Mode{}
+	}
+
+	s.apply(mode)
+
+	return nil
+}
+
+// apply switches to mode, logging transitions
+func (s *Service) apply(mode Mode) {
+	s.mu.Lock()
+	previous := s.mode
+	s.mode = mode
+	s.mu.Unlock()
+
+	switch {
+	case mode.Enabled && !previous.Enabled:
+		log.Printf("Maintenance mode enabled, API is read-only and the scheduler is paused: %s", mode.Message)
+	case !mode.Enabled && previous.Enabled:
+		log.Println("Maintenance mode disabled")
+	}
+}
@@ -0,0 +1,213 @@
+package message
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"qubit/env/postgres/attempts"
+	"qubit/env/provider"
+)
+
+// Attempt records a single send attempt with its latency breakdown
+type Attempt struct {
+	ID            int64
+	MessageID     int64
+	AttemptNumber int
+	StartedAt     time.Time
+	Success       bool
+	Error         *string
+
+	// FailureCategory classifies a failed attempt, e.g. dns, tls or http_5xx
+	FailureCategory *string
+
+	// QueueWait is the time between the message becoming due and being locked
+	QueueWait time.Duration
+	// LockToSend is the time between locking the message and calling the webhook
+	LockToSend time.Duration
+	// Webhook is the duration of the webhook call
+	Webhook time.Duration
+	// DBUpdate is the duration of persisting the attempt outcome
+	DBUpdate time.Duration
+
+	// RequestPayload and ResponseBody hold the sanitized raw provider exchange of a failed attempt
+	RequestPayload *string
+	ResponseBody   *string
+}
+
+// PhaseStats holds aggregated latency of a single attempt phase
+type PhaseStats struct {
+	Avg time.Duration
+	P95 time.Duration
+	Max time.Duration
+}
+
+// AttemptStats holds aggregated latency of all attempts in a time window
+type AttemptStats struct {
+	Since     time.Time
+	Attempts  int64
+	Succeeded int64
+
+	// Failures counts the failed attempts per failure category
+	Failures map[string]int64
+
+	QueueWait  PhaseStats
+	LockToSend PhaseStats
+	Webhook    PhaseStats
+	DBUpdate   PhaseStats
+}
+
+// newAttempt starts tracing an attempt for a message locked at lockedAt
+func newAttempt(msg *Message, lockedAt time.Time) *Attempt {
+	// The message became due when it was created, its scheduled time came or its backoff elapsed
+	dueAt := msg.CreatedAt
+	if msg.ScheduledAt != nil && msg.ScheduledAt.After(dueAt) {
+		dueAt = *msg.ScheduledAt
+	}
+	if msg.NextAttemptAt != nil && msg.NextAttemptAt.After(dueAt) {
+		dueAt = *msg.NextAttemptAt
+	}
+
+	queueWait := lockedAt.Sub(dueAt)
+	if queueWait < 0 {
+		queueWait = 0
+	}
+
+	return &Attempt{
+		MessageID:     msg.ID,
+		AttemptNumber: msg.RetryCount + 1,
+		StartedAt:     lockedAt,
+		QueueWait:     queueWait,
+	}
+}
+
+// finish records the outcome of the attempt
+func (a *Attempt) finish(err error) {
+	a.Success = err == nil
+	if err != nil {
+		errMsg := err.Error()
+		a.Error = &errMsg
+
+		category := string(provider.Classify(err))
+		switch {
+		case errors.Is(err, ErrURLNotAllowed):
+			category = failureURLNotAllowed
+		case errors.Is(err, ErrTemplateRequired), errors.Is(err, ErrTemplateNotApproved):
+			category = failureTemplateNotApproved
+		}
+		a.FailureCategory = &category
+	}
+
+	if exchange, ok := provider.ExchangeOf(err); ok {
+		a.RequestPayload = &exchange.Request
+		if exchange.Response != "" {
+			a.ResponseBody = &exchange.Response
+		}
+	}
+}
+
+// GetAttempts retrieves all send attempts of a message
+// A non-nil tenantID returns ErrMessageNotFound for a message that is missing or belongs to another tenant
+func (s *Service) GetAttempts(ctx context.Context, messageID int64, tenantID *int64) ([]*Attempt, error) {
+	if tenantID != nil {
+		if _, err := s.GetMessage(ctx, messageID, tenantID); err != nil {
+			return nil, err
+		}
+	}
+
+	dbAttempts, err := s.repo.ListAttempts(ctx, messageID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attempts: %w", err)
+	}
+
+	return AttemptsToDomainSlice(dbAttempts), nil
+}
+
+// GetAttemptStats aggregates the latency breakdown of attempts within the given window
+// Attempts of test messages are only included when includeTest is set
+func (s *Service) GetAttemptStats(ctx context.Context, window time.Duration, includeTest bool) (*AttemptStats, error) {
+	since := time.Now().Add(-window)
+
+	dbStats, err := s.repo.AttemptStats(ctx, since, includeTest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attempt stats: %w", err)
+	}
+
+	stats := AttemptStatsToDomain(dbStats)
+	stats.Since = since
+
+	return stats, nil
+}
+
+// AttemptToPostgres converts a domain Attempt to a postgres Attempt model
+func AttemptToPostgres(a *Attempt) *attempts.Attempt {
+	return &attempts.Attempt{
+		ID:            a.ID,
+		MessageID:     a.MessageID,
+		AttemptNumber: a.AttemptNumber,
+		StartedAt:     a.StartedAt,
+		Success:       a.Success,
+		Error:         a.Error,
+		QueueWaitMs:   a.QueueWait.Milliseconds(),
+
+		FailureCategory: a.FailureCategory,
+		LockToSendMs:    a.LockToSend.Milliseconds(),
+		WebhookMs:       a.Webhook.Milliseconds(),
+		DBUpdateMs:      a.DBUpdate.Milliseconds(),
+
+		RequestPayload: a.RequestPayload,
+		ResponseBody:   a.ResponseBody,
+	}
+}
+
+// AttemptsToDomainSlice converts a slice of postgres Attempts to domain Attempts
+func AttemptsToDomainSlice(dbAttempts []*attempts.Attempt) []*Attempt {
+	if dbAttempts == nil {
+		return nil
+	}
+
+	domainAttempts := make([]*Attempt, 0, len(dbAttempts))
+	for _, a := range dbAttempts {
+		domainAttempts = append(domainAttempts, &Attempt{
+			ID:            a.ID,
+			MessageID:     a.MessageID,
+			AttemptNumber: a.AttemptNumber,
+			StartedAt:     a.StartedAt,
+			Success:       a.Success,
+			Error:         a.Error,
+			QueueWait:     time.Duration(a.QueueWaitMs) * time.Millisecond,
+			LockToSend:    time.Duration(a.LockToSendMs) * time.Millisecond,
+			Webhook:       time.Duration(a.WebhookMs) * time.Millisecond,
+			DBUpdate:      time.Duration(a.DBUpdateMs) * time.Millisecond,
+
+			FailureCategory: a.FailureCategory,
+
+			RequestPayload: a.RequestPayload,
+			ResponseBody:   a.ResponseBody,
+		})
+	}
+
+	return domainAttempts
+}
+
+// AttemptStatsToDomain converts postgres attempt Stats to domain AttemptStats
+func AttemptStatsToDomain(stats *attempts.Stats) *AttemptStats {
+	toPhase := func(p attempts.PhaseStats) PhaseStats {
+		return PhaseStats{
+			Avg: time.Duration(p.AvgMs * float64(time.Millisecond)),
+			P95: time.Duration(p.P95Ms * float64(time.Millisecond)),
+			Max: time.Duration(p.MaxMs) * time.Millisecond,
+		}
+	}
+
+	return &AttemptStats{
+		Attempts:   stats.Attempts,
+		Succeeded:  stats.Succeeded,
+		Failures:   stats.Failures,
+		QueueWait:  toPhase(stats.QueueWait),
+		LockToSend: toPhase(stats.LockToSend),
+		Webhook:    toPhase(stats.Webhook),
+		DBUpdate:   toPhase(stats.DBUpdate),
+	}
+}
@@ -0,0 +1,128 @@
+package message
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"unicode/utf8"
+
+	"qubit/env/postgres/templates"
+	"qubit/pkg/locale"
+	"qubit/service/template"
+)
+
+// resolveContent returns the content of a new message and the locale of its variant
+// Messages of a template-only provider get the rendered body of their approved provider template instead
+// A referenced template is rendered from its variant for the recipient's locale, see Template.RenderVariant;
+// otherwise the content is localized from opts.Translations, see localizeContent
+// Content without translations records no locale; a missing template is a validation error of the message,
+// wrapping template.ErrNotFound
+func (s *Service) resolveContent(ctx context.Context, content string, pinned *string, opts CreateOptions) (string, *ProviderTemplate, *string, error) {
+	name := s.providers.DefaultName()
+	if pinned != nil {
+		name = *pinned
+	}
+	if p, ok := s.providers.Config(name); ok && p.RequiresTemplate() {
+		content, providerTemplate, err := s.resolveProviderTemplate(ctx, name, content, opts)
+		return content, providerTemplate, nil, err
+	}
+	if opts.ProviderTemplate != nil {
+		return "", nil, nil, fmt.Errorf("%w: provider %s sends content, not provider templates", ErrValidation, name)
+	}
+
+	if opts.TemplateID == nil {
+		content, contentLocale, err := s.localizeContent(content, opts)
+		return content, nil, contentLocale, err
+	}
+	if len(opts.Translations) > 0 {
+		return "", nil, nil, fmt.Errorf("%w: translations of a templated message belong to its template", ErrInvalidTranslation)
+	}
+
+	dbTemplate, err := s.repo.GetTemplate(ctx, *opts.TemplateID)
+	if errors.Is(err, templates.ErrNotFound) {
+		return "", nil, nil, fmt.Errorf("%w: %w: %d", ErrValidation, template.ErrNotFound, *opts.TemplateID)
+	}
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("failed to get template: %w", err)
+	}
+
+	t := template.ToDomain(dbTemplate)
+	rendered, variant, err := t.RenderVariant(s.localeChain(opts), opts.Variables)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	if len(t.Translations) == 0 {
+		return rendered, nil, nil, nil
+	}
+
+	return rendered, nil, &variant, nil
+}
+
+// localizeContent returns the content variant of a new message for the recipient's locale and that locale
+// The variant is picked through the locale chain, see localeChain, where a locale is followed by its language;
+// without a match the default content is used and recorded as locale.Default
+// Messages without translations keep their content and record no locale
+func (s *Service) localizeContent(content string, opts CreateOptions) (string, *string, error) {
+	if len(opts.Translations) == 0 {
+		return content, nil, nil
+	}
+
+	variants, err := normalizeTranslations(opts.Translations)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if picked, ok := locale.Pick(variants, s.localeChain(opts)); ok {
+		return variants[picked], &picked, nil
+	}
+
+	picked := locale.Default
+	return content, &picked, nil
+}
+
+// localeChain returns the locales to try when picking a content variant: the recipient's locale, taken from the
+// "locale" metadata field or the tenant's default metadata when none was given, followed by the configured fallback locales
+func (s *Service) localeChain(opts CreateOptions) []string {
+	recipient := opts.Locale
+	if recipient == "" {
+		recipient = s.resolveMetadata(opts)[LocaleMetadataKey]
+	}
+	return append([]string{recipient}, s.localeFallback...)
+}
+
+// normalizeTranslations validates content variants and keys them by normalized locale
+func normalizeTranslations(translations map[string]string) (map[string]string, error) {
+	if len(translations) > MaxTranslations {
+		return nil, fmt.Errorf("%w: a message has at most %d translations", ErrInvalidTranslation, MaxTranslations)
+	}
+
+	// Sorted so the reported error does not depend on map order
+	keys := make([]string, 0, len(translations))
+	for key := range translations {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	variants := make(map[string]string, len(translations))
+	for _, key := range keys {
+		normalized := locale.Normalize(key)
+		if !locale.Valid(normalized) {
+			return nil, fmt.Errorf("%w: locale %q must be a language optionally followed by subtags, e.g. tr or pt-BR", ErrInvalidTranslation, key)
+		}
+		if _, ok := variants[normalized]; ok {
+			return nil, fmt.Errorf("%w: locale %q is given more than once", ErrInvalidTranslation, key)
+		}
+
+		content := translations[key]
+		if content == "" {
+			return nil, fmt.Errorf("%w: translation %q has no content", ErrInvalidTranslation, key)
+		}
+		if utf8.RuneCountInString(content) > MaxContentLength {
+			return nil, fmt.Errorf("%w: translation %q exceeds maximum length of %d characters", ErrInvalidTranslation, key, MaxContentLength)
+		}
+		variants[normalized] = content
+	}
+
+	return variants, nil
+}
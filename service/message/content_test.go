@@ -0,0 +1,75 @@
+package message
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestLocalizeContent(t *testing.T) {
+	s := &Service{localeFallback: []string{"en"}}
+	translations := map[string]string{"tr": "Merhaba", "pt": "Olá", "EN": "Hello"}
+
+	tests := []struct {
+		name        string
+		opts        CreateOptions
+		wantContent string
+		wantLocale  *string
+	}{
+		{"no translations", CreateOptions{Locale: "tr"}, "default content", nil},
+		{"recipient locale", CreateOptions{Locale: "tr", Translations: translations}, "Merhaba", ptr("tr")},
+		{"language of region", CreateOptions{Locale: "pt-BR", Translations: translations}, "Olá", ptr("pt")},
+		{"configured fallback", CreateOptions{Locale: "de", Translations: translations}, "Hello", ptr("en")},
+		{"default content", CreateOptions{Locale: "de", Translations: map[string]string{"tr": "Merhaba"}}, "default content", ptr("default")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, locale, err := s.localizeContent("default content", tt.opts)
+			if err != nil {
+				t.Fatalf("localizeContent() error = %v", err)
+			}
+			if content != tt.wantContent {
+				t.Errorf("content = %q, want %q", content, tt.wantContent)
+			}
+			if (locale == nil) != (tt.wantLocale == nil) || (locale != nil && *locale != *tt.wantLocale) {
+				t.Errorf("locale = %v, want %v", deref(locale), deref(tt.wantLocale))
+			}
+		})
+	}
+}
+
+func TestLocalizeContentRejectsInvalidTranslations(t *testing.T) {
+	s := &Service{}
+
+	tooMany := make(map[string]string)
+	for _, language := range strings.Fields("aa ab ae af ak am an ar as av ay az ba be bg bh bi bm bn bo br") {
+		tooMany[language] = "hello"
+	}
+
+	for name, translations := range map[string]map[string]string{
+		"invalid locale":   {"english": "Hello"},
+		"duplicate locale": {"tr": "Merhaba", "TR": "Merhaba"},
+		"empty content":    {"tr": ""},
+		"content too long": {"tr": strings.Repeat("a", MaxContentLength+1)},
+		"too many":         tooMany,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, _, err := s.localizeContent("hello", CreateOptions{Translations: translations})
+			if !errors.Is(err, ErrInvalidTranslation) {
+				t.Errorf("localizeContent() error = %v, want %v", err, ErrInvalidTranslation)
+			}
+		})
+	}
+}
+
+func ptr(s string) *string {
+	return &s
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return "<nil>"
+	}
+	return *s
+}
@@ -0,0 +1,105 @@
+package message
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"qubit/env/postgres/messages"
+	"qubit/env/redis"
+)
+
+// Delivery sources
+const (
+	DeliverySourceCache    = "cache"
+	DeliverySourceDatabase = "database"
+)
+
+// Delivery describes when a message was delivered and under which provider message ID
+type Delivery struct {
+	ID        int64
+	MessageID string
+	SentAt    time.Time
+	Source    string
+}
+
+// GetDelivery returns the delivery data of a message, reading the cache first
+// The cache holds no tenants, so a lookup scoped to a non-nil tenantID reads the database
+// Returns ErrMessageNotFound if the message does not exist or belongs to another tenant and ErrNotDelivered
+// if it was not sent yet
+func (s *Service) GetDelivery(ctx context.Context, id int64, tenantID *int64) (*Delivery, error) {
+	if s.deliveryCache != nil && tenantID == nil {
+		cached, err := s.deliveryCache.GetDelivery(ctx, id)
+		switch {
+		case errors.Is(err, redis.ErrCircuitOpen):
+			// Read from the database while Redis is bypassed
+		case err != nil:
+			log.Printf("Warning: delivery cache read failed for message %d: %v", id, err)
+		case cached != nil:
+			return &Delivery{
+				ID:        id,
+				MessageID: cached.MessageID,
+				SentAt:    cached.SentAt,
+				Source:    DeliverySourceCache,
+			}, nil
+		}
+	}
+
+	dbMsg, err := s.repo.GetMessage(ctx, id, tenantID)
+	if errors.Is(err, messages.ErrNotFound) {
+		return nil, ErrMessageNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get message: %w", err)
+	}
+
+	if dbMsg.MessageID == nil || dbMsg.ProcessedAt == nil {
+		return nil, ErrNotDelivered
+	}
+
+	delivery := &Delivery{
+		ID:        id,
+		MessageID: *dbMsg.MessageID,
+		SentAt:    *dbMsg.ProcessedAt,
+		Source:    DeliverySourceDatabase,
+	}
+
+	// Populate the cache for subsequent reads
+	s.cacheDelivery(ctx, delivery)
+
+	return delivery, nil
+}
+
+// cacheDeliveries caches the delivery data of sent messages
+// Cache failures are logged and never fail message processing
+func (s *Service) cacheDeliveries(ctx context.Context, sent []*Message) {
+	for _, msg := range sent {
+		if msg.MessageID == nil || msg.ProcessedAt == nil {
+			continue
+		}
+
+		s.cacheDelivery(ctx, &Delivery{
+			ID:        msg.ID,
+			MessageID: *msg.MessageID,
+			SentAt:    *msg.ProcessedAt,
+		})
+	}
+}
+
+// cacheDelivery writes a single delivery to the cache if it is enabled
+// While the Redis circuit is open the write is skipped silently, the breaker logs the outage once
+func (s *Service) cacheDelivery(ctx context.Context, delivery *Delivery) {
+	if s.deliveryCache == nil {
+		return
+	}
+
+	err := s.deliveryCache.SetDelivery(ctx, delivery.ID, redis.Delivery{
+		MessageID: delivery.MessageID,
+		SentAt:    delivery.SentAt,
+	})
+	if err != nil && !errors.Is(err, redis.ErrCircuitOpen) {
+		log.Printf("Warning: failed to cache delivery of message %d: %v", delivery.ID, err)
+	}
+}
@@ -0,0 +1,165 @@
+package message
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"qubit/env/provider"
+)
+
+// persistTimeout bounds persisting the outcomes of a chunk, which continues after the batch context is cancelled
+const persistTimeout = 30 * time.Second
+
+// BatchResult aggregates the outcome of one ProcessUnsentMessages run
+type BatchResult struct {
+	Claimed     int
+	Sent        int
+	Retried     int // failed and scheduled for another attempt
+	Failed      int // failed with no retries left
+	Throttled   int // deferred or rejected by the recipient limit
+	Deferred    int // returned to pending unsent because the provider circuit opened or it throttled mid-batch, or the send rate ran out
+	Quarantined int // linking outside the URL allow-list, violations failed instead count as failed
+	Duration    time.Duration
+	// Errors counts the failed attempts per failure category, e.g. dns or http_5xx
+	Errors map[string]int
+}
+
+// Attempted returns the number of messages handed to a provider whose outcome was persisted
+func (r *BatchResult) Attempted() int {
+	return r.Sent + r.Retried + r.Failed + r.Quarantined
+}
+
+// fail counts a failed attempt under its failure category
+func (r *BatchResult) fail(category string) {
+	if r.Errors == nil {
+		r.Errors = make(map[string]int)
+	}
+	r.Errors[category]++
+}
+
+// count adds a persisted outcome with the resulting message status
+func (r *BatchResult) count(status Status) {
+	switch status {
+	case StatusSent:
+		r.Sent++
+	case StatusFailed:
+		r.Failed++
+	case StatusQuarantined:
+		r.Quarantined++
+	default:
+		r.Retried++
+	}
+}
+
+// sendOutcome is the result of the webhook call for a single message
+type sendOutcome struct {
+	msg       *Message
+	attempt   *Attempt
+	messageID string
+	err       error
+}
+
+// dispatch sends the messages concurrently on up to dispatchWorkers goroutines, at the send rate until paceUntil
+// Outcomes are returned in input order and published on progress as they arrive; no database access happens here
+func (s *Service) dispatch(ctx context.Context, msgs []*Message, attempts []*Attempt, paceUntil time.Time, progress *batchProgress) []sendOutcome {
+	outcomes := make([]sendOutcome, len(msgs))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < min(max(s.dispatchWorkers, 1), len(msgs)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				outcomes[i] = s.send(ctx, msgs[i], attempts[i], paceUntil)
+				progress.messageDone(outcomes[i])
+			}
+		}()
+	}
+
+	for i := range msgs {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return outcomes
+}
+
+// send calls the webhook for a single message and records the phase durations on the attempt
+// With a send rate, the call waits for its slot; a message without a slot before paceUntil is not sent
+func (s *Service) send(ctx context.Context, msg *Message, attempt *Attempt, paceUntil time.Time) sendOutcome {
+	outcome := sendOutcome{msg: msg, attempt: attempt}
+
+	if err := ctx.Err(); err != nil {
+		outcome.err = fmt.Errorf("failed to send message: %w", err)
+		return outcome
+	}
+
+	// The allow-list may have changed since creation, and campaign messages are only checked here
+	if s.urlRecheck != "" {
+		if err := s.checkURLs(msg.Content); err != nil {
+			outcome.err = err
+			return outcome
+		}
+	}
+
+	// The template may have lost its approval since the message was created
+	if err := s.checkProviderTemplate(msg); err != nil {
+		outcome.err = err
+		return outcome
+	}
+
+	// Pinned messages use their provider, others the default one
+	sender, err := s.senderFor(msg)
+	if err != nil {
+		outcome.err = fmt.Errorf("failed to send message: %w", err)
+		return outcome
+	}
+
+	if s.sendPacer != nil {
+		if err := s.sendPacer.wait(ctx, paceUntil); err != nil {
+			outcome.err = fmt.Errorf("failed to send message: %w", err)
+			return outcome
+		}
+	}
+
+	// Once the provider asked to back off, the rest of its messages in the batch wait for the throttle to end
+	// It is checked after waiting for the send slot, the throttle may have started meanwhile
+	name := s.providerName(msg)
+	if until, ok := s.throttles.hold(name); ok {
+		outcome.err = fmt.Errorf("failed to send message: %w until %s", errProviderThrottled, until.Format(time.RFC3339))
+		return outcome
+	}
+
+	log.Printf("Sending message %d to %s", msg.ID, msg.PhoneNumber)
+
+	// Each call gets its own deadline so one slow response cannot stall the batch;
+	// a timed-out call is a failed send and retried, unlike a cancelled batch
+	sendCtx, cancel := context.WithTimeout(ctx, s.webhookTimeout)
+	defer cancel()
+	sendCtx = provider.WithMetadata(sendCtx, msg.Metadata)
+
+	webhookStart := time.Now()
+	attempt.LockToSend = webhookStart.Sub(attempt.StartedAt)
+	if templateSender, ok := sender.(provider.TemplateSender); ok && msg.ProviderTemplate != nil {
+		outcome.messageID, err = templateSender.SendTemplate(sendCtx, msg.PhoneNumber, msg.ProviderTemplate.message())
+	} else {
+		outcome.messageID, err = sender.SendMessage(sendCtx, msg.PhoneNumber, msg.Content)
+	}
+	attempt.Webhook = time.Since(webhookStart)
+	if err != nil {
+		outcome.err = fmt.Errorf("failed to send message: %w", err)
+
+		var exErr *provider.ExchangeError
+		if retryAfter, ok := provider.RetryAfterOf(err); ok && errors.As(err, &exErr) {
+			s.throttles.throttle(name, exErr.StatusCode, retryAfter)
+		}
+	}
+
+	return outcome
+}
@@ -0,0 +1,63 @@
+package message
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// maxEstimatedRuns bounds the scheduler runs walked to estimate a dispatch time
+const maxEstimatedRuns = 1000
+
+// DispatchEstimate is the operational context returned to the creator of a message
+type DispatchEstimate struct {
+	// QueueDepth counts the pending messages that are due now
+	QueueDepth int64
+	// EstimatedDispatch is the scheduler run expected to pick up a message created now,
+	// nil while the scheduler is stopped or the queue is too deep to estimate
+	EstimatedDispatch *time.Time
+}
+
+// EstimateDispatch estimates when a message created now is dispatched
+// Every scheduler run is assumed to drain a full batch of the queue in due order, so the estimate is
+// the run reaching the end of the queue; a message scheduled later is picked up by the first run after scheduledAt
+func (s *Service) EstimateDispatch(ctx context.Context, scheduledAt *time.Time) (*DispatchEstimate, error) {
+	depth, err := s.repo.CountDue(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to estimate dispatch: %w", err)
+	}
+
+	estimate := &DispatchEstimate{QueueDepth: depth}
+
+	nextRun := s.scheduler.NextRun()
+	schedule := s.scheduler.Schedule()
+	if nextRun == nil || schedule == nil {
+		return estimate, nil
+	}
+
+	run := *nextRun
+	if scheduledAt != nil && scheduledAt.After(run) {
+		// The queue due now is drained before the scheduled message becomes due
+		for i := 0; run.Before(*scheduledAt); i++ {
+			if i == maxEstimatedRuns || run.IsZero() {
+				return estimate, nil
+			}
+			run = schedule.Next(run)
+		}
+	} else {
+		batchSize := int64(s.SchedulerSettings().BatchSize)
+		runs := (depth + batchSize - 1) / batchSize
+		if runs > maxEstimatedRuns {
+			return estimate, nil
+		}
+		for i := int64(1); i < runs && !run.IsZero(); i++ {
+			run = schedule.Next(run)
+		}
+	}
+
+	if !run.IsZero() {
+		estimate.EstimatedDispatch = &run
+	}
+
+	return estimate, nil
+}
@@ -4,25 +4,92 @@ import (
 	"fmt"
 	"regexp"
 	"time"
+	"unicode/utf8"
+
+	"qubit/pkg/apperr"
 )
 
 // Message content constraints
 const (
 	MaxContentLength = 500
+	MaxTranslations  = 20
 )
 
+// Message errors
+var (
+	ErrMessageNotFound = apperr.NewNotFound("message_not_found", "message not found")
+	ErrNotDelivered    = apperr.NewNotFound("message_not_delivered", "message has not been delivered yet")
+	ErrNotPending      = apperr.NewConflict("message_not_pending", "message is no longer pending")
+	ErrUUIDTaken       = apperr.NewConflict("message_uuid_taken", "a message with this uuid already exists")
+	ErrValidation      = apperr.NewValidation(apperr.CodeValidation, "validation failed")
+	ErrListTooLarge    = apperr.NewValidation("list_too_large", "list exceeds the size of a single response")
+
+	ErrUnknownProvider   = apperr.NewValidation("unknown_provider", "unknown provider")
+	ErrProviderForbidden = apperr.NewForbidden("provider_forbidden", "caller is not allowed to use provider")
+	ErrSandboxProvider   = apperr.NewForbidden("sandbox_provider", "sandbox provider only accepts test messages")
+	ErrNotSandbox        = apperr.NewValidation("sandbox_required", "test messages require a sandbox provider")
+
+	ErrInvalidTranslation = apperr.NewValidation("invalid_translation", "invalid translation")
+)
+
+// RoutingCodes are the codes of the errors refusing the provider a message is pinned to
+var RoutingCodes = []string{ErrUnknownProvider.Code, ErrProviderForbidden.Code, ErrSandboxProvider.Code, ErrNotSandbox.Code}
+
 // phoneRegex validates international phone number format
 var phoneRegex = regexp.MustCompile(`^\+?[1-9]\d{1,14}$`)
 
 // Message represents a message domain entity with business logic
 type Message struct {
 	ID          int64
+	UUID        string
 	PhoneNumber string
 	Content     string
 	CreatedAt   time.Time
 
 	MessageID   *string
 	ProcessedAt *time.Time
+
+	RetryCount    int
+	NextAttemptAt *time.Time
+
+	Status Status
+
+	// Provider pins the message to a provider, nil uses the default one
+	Provider *string
+
+	// ScheduledAt is the earliest time the message may be sent, nil sends it right away
+	ScheduledAt *time.Time
+	// LockedAt is when the message was last claimed for sending
+	LockedAt *time.Time
+	// LockedBy is the instance that last claimed the message
+	LockedBy *string
+	// LeaseExpiresAt is when a claim still in sending is reaped back to pending
+	LeaseExpiresAt *time.Time
+
+	// IsTest marks a message created with a sandbox key, it is only sent through sandbox providers
+	IsTest bool
+	// Transactional messages are exempt from the per-recipient limit
+	Transactional bool
+	// FanoutID links the messages expanded from a single multi-recipient request
+	FanoutID *string
+	// RetryPolicy overrides the configured retry policy, nil uses the configured one
+	RetryPolicy *RetryPolicy
+	// ExternalRef links the message to an object of a business system, nil when none was given
+	ExternalRef *ExternalRef
+	// Metadata holds caller-defined fields such as a campaign or user ID, nil when none were given
+	Metadata Metadata
+	// TenantID is the tenant whose API key created the message, nil for operator keys
+	TenantID *int64
+	// ProviderTemplate is the approved template a template-only provider sends, nil for free-form content
+	// Content then holds the rendered template body
+	ProviderTemplate *ProviderTemplate
+
+	// ContentLocale is the locale of the content variant picked for the recipient, locale.Default for the
+	// default content; nil when the message was created without translations
+	ContentLocale *string
+
+	// Archived is set on a message read back from the archive, it no longer changes
+	Archived bool
 }
 
 // Validate checks if the message fields are valid
@@ -41,9 +108,52 @@ func (m *Message) Validate() error {
 		return fmt.Errorf("message content is required")
 	}
 
-	if len(m.Content) > MaxContentLength {
+	if utf8.RuneCountInString(m.Content) > MaxContentLength {
 		return fmt.Errorf("message content exceeds maximum length of %d characters", MaxContentLength)
 	}
 
+	return m.Metadata.Validate()
+}
+
+// ListFilter narrows a message listing, zero fields match every message
+// Ranges include their start and exclude their end; Search matches content case-insensitively
+// ExternalRef matches the type and ID of the reference exactly; IncludeArchived also lists archived messages
+// Metadata matches messages holding every given key with exactly the given value
+// Max fails ListMessages with ErrListTooLarge when more messages match, 0 lists all of them
+type ListFilter struct {
+	Status        Status
+	PhoneNumber   string
+	CreatedFrom   *time.Time
+	CreatedTo     *time.Time
+	ProcessedFrom *time.Time
+	ProcessedTo   *time.Time
+	Search        string
+	ExternalRef   *ExternalRef
+	Metadata      Metadata
+	TenantID      *int64 // nil lists the messages of every tenant
+
+	IncludeArchived bool
+
+	Max int
+}
+
+// Validate checks that the filter ranges are not inverted
+func (f ListFilter) Validate() error {
+	if f.CreatedFrom != nil && f.CreatedTo != nil && f.CreatedTo.Before(*f.CreatedFrom) {
+		return fmt.Errorf("createdTo must not be before createdFrom")
+	}
+
+	if f.ProcessedFrom != nil && f.ProcessedTo != nil && f.ProcessedTo.Before(*f.ProcessedFrom) {
+		return fmt.Errorf("processedTo must not be before processedFrom")
+	}
+
+	if err := f.Metadata.Validate(); err != nil {
+		return err
+	}
+
+	if utf8.RuneCountInString(f.Search) > MaxContentLength {
+		return fmt.Errorf("search exceeds maximum length of %d characters", MaxContentLength)
+	}
+
 	return nil
 }
@@ -0,0 +1,48 @@
+package message
+
+import (
+	"errors"
+
+	"qubit/pkg/apperr"
+	"qubit/service/errorstats"
+)
+
+// recordFailures counts the failed sends among persisted outcomes
+// A retried failure is transient and one that failed the message permanent; a send stopped by policy counts as
+// validation and one whose provider is no longer configured as routing
+func (s *Service) recordFailures(persisted []sendOutcome) {
+	if s.errorStats == nil {
+		return
+	}
+
+	for _, o := range persisted {
+		if o.err == nil {
+			continue
+		}
+
+		category := errorstats.CategoryProviderTransient
+		switch {
+		case errors.Is(o.err, ErrUnknownProvider):
+			category = errorstats.CategoryRouting
+		case s.isBlocked(o.err):
+			category = errorstats.CategoryValidation
+		case o.msg.Status == StatusFailed:
+			category = errorstats.CategoryProviderPermanent
+		}
+
+		code := ""
+		if o.attempt.FailureCategory != nil {
+			code = *o.attempt.FailureCategory
+		}
+
+		s.errorStats.Record(category, s.providerName(o.msg), code, o.err.Error())
+	}
+}
+
+// recordInternal counts a failure of the scheduler itself, e.g. a batch that could not claim or persist
+func (s *Service) recordInternal(err error) {
+	if s.errorStats == nil {
+		return
+	}
+	s.errorStats.Record(errorstats.CategoryInternal, "", apperr.CodeInternal, err.Error())
+}
@@ -0,0 +1,128 @@
+package message
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"qubit/env/postgres/outbox"
+	"qubit/pkg/jsonfmt"
+)
+
+// Lifecycle events written to the outbox together with the message change
+// message.failed is only emitted once the retries are exhausted, a retried attempt emits nothing
+const (
+	EventMessageCreated = "message.created"
+	EventMessageSent    = "message.sent"
+	EventMessageFailed  = "message.failed"
+)
+
+// Event is the JSON document published for a lifecycle event
+type Event struct {
+	EventID    string        `json:"eventId"`
+	Type       string        `json:"type"`
+	OccurredAt jsonfmt.Time  `json:"occurredAt"`
+	Message    EventMessage  `json:"message"`
+	Failure    *EventFailure `json:"failure,omitempty"`
+}
+
+// EventMessage is the state of the message after the change
+type EventMessage struct {
+	ID            int64         `json:"id"`
+	UUID          string        `json:"uuid"`
+	PhoneNumber   string        `json:"phoneNumber"`
+	Status        Status        `json:"status"`
+	Provider      *string       `json:"provider"`
+	MessageID     *string       `json:"messageId"`
+	RetryCount    int           `json:"retryCount"`
+	IsTest        bool          `json:"isTest"`
+	Transactional bool          `json:"transactional"`
+	FanoutID      *string       `json:"fanoutId"`
+	ExternalRef   *string       `json:"externalRef"`
+	Metadata      Metadata      `json:"metadata"`
+	CreatedAt     jsonfmt.Time  `json:"createdAt"`
+	ScheduledAt   *jsonfmt.Time `json:"scheduledAt"`
+	ProcessedAt   *jsonfmt.Time `json:"processedAt"`
+}
+
+// EventFailure describes the last attempt of a failed message
+type EventFailure struct {
+	Error    *string `json:"error"`
+	Category *string `json:"category"`
+}
+
+// buildEvent builds the lifecycle event of msg, attempt is the last attempt of a sent or failed message
+func buildEvent(eventType string, msg *Message, attempt *Attempt) Event {
+	e := Event{
+		EventID:    uuid.New().String(),
+		Type:       eventType,
+		OccurredAt: jsonfmt.NewTime(time.Now()),
+		Message: EventMessage{
+			ID:            msg.ID,
+			UUID:          msg.UUID,
+			PhoneNumber:   msg.PhoneNumber,
+			Status:        msg.Status,
+			Provider:      msg.Provider,
+			MessageID:     msg.MessageID,
+			RetryCount:    msg.RetryCount,
+			IsTest:        msg.IsTest,
+			Transactional: msg.Transactional,
+			FanoutID:      msg.FanoutID,
+			Metadata:      msg.Metadata,
+			CreatedAt:     jsonfmt.NewTime(msg.CreatedAt),
+			ScheduledAt:   jsonfmt.NewTimePtr(msg.ScheduledAt),
+			ProcessedAt:   jsonfmt.NewTimePtr(msg.ProcessedAt),
+		},
+	}
+	if msg.ExternalRef != nil {
+		ref := msg.ExternalRef.String()
+		e.Message.ExternalRef = &ref
+	}
+	if eventType == EventMessageFailed && attempt != nil {
+		e.Failure = &EventFailure{
+			Error:    attempt.Error,
+			Category: attempt.FailureCategory,
+		}
+	}
+
+	return e
+}
+
+// newEvent builds the outbox event of msg, attempt is the last attempt of a sent or failed message
+func newEvent(eventType string, msg *Message, attempt *Attempt) (*outbox.Event, error) {
+	e := buildEvent(eventType, msg, attempt)
+
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s event: %w", eventType, err)
+	}
+
+	return &outbox.Event{
+		EventID:   e.EventID,
+		EventType: eventType,
+		MessageID: msg.ID,
+		Payload:   payload,
+	}, nil
+}
+
+// recordEventsWithTx writes an event of eventType for every message to the outbox within tx
+// It is a no-op while event publishing is disabled, so the outbox does not grow without a relay
+func (s *Service) recordEventsWithTx(ctx context.Context, tx MessageTx, eventType string, msgs []*Message, attempt *Attempt) error {
+	if !s.publishEvents {
+		return nil
+	}
+
+	events := make([]*outbox.Event, 0, len(msgs))
+	for _, msg := range msgs {
+		e, err := newEvent(eventType, msg, attempt)
+		if err != nil {
+			return err
+		}
+		events = append(events, e)
+	}
+
+	return tx.CreateEvents(ctx, events)
+}
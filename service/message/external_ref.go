@@ -0,0 +1,96 @@
+package message
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// External reference constraints
+const (
+	MaxExternalRefTypeLength = 50
+	MaxExternalRefIDLength   = 255
+)
+
+// externalRefTypeRegex validates the type of an external reference, e.g. order or support_ticket
+var externalRefTypeRegex = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_.-]*$`)
+
+// ExternalRef links a message to an object of a business system such as an order or a ticket
+// It is written as type:id, e.g. order:12345
+type ExternalRef struct {
+	Type string
+	ID   string
+}
+
+// ParseExternalRef parses a type:id reference, the ID may itself contain colons
+func ParseExternalRef(value string) (*ExternalRef, error) {
+	refType, id, ok := strings.Cut(value, ":")
+	if !ok {
+		return nil, fmt.Errorf("external reference %q must be written as type:id", value)
+	}
+
+	ref := &ExternalRef{Type: refType, ID: id}
+	if err := ref.Validate(); err != nil {
+		return nil, err
+	}
+
+	return ref, nil
+}
+
+// String renders the reference as type:id
+func (r ExternalRef) String() string {
+	return r.Type + ":" + r.ID
+}
+
+// Validate checks the type and ID of the reference
+func (r ExternalRef) Validate() error {
+	if !externalRefTypeRegex.MatchString(r.Type) {
+		return fmt.Errorf("external reference type %q must start with a letter and contain only letters, digits, '_', '.' or '-'", r.Type)
+	}
+
+	if len(r.Type) > MaxExternalRefTypeLength {
+		return fmt.Errorf("external reference type exceeds maximum length of %d characters", MaxExternalRefTypeLength)
+	}
+
+	if strings.TrimSpace(r.ID) == "" {
+		return fmt.Errorf("external reference ID is required")
+	}
+
+	if len(r.ID) > MaxExternalRefIDLength {
+		return fmt.Errorf("external reference ID exceeds maximum length of %d characters", MaxExternalRefIDLength)
+	}
+
+	return nil
+}
+
+// resolveExternalRef parses the external reference of a new message, nil when none was given
+func resolveExternalRef(opts CreateOptions) (*ExternalRef, error) {
+	if opts.ExternalRef == "" {
+		return nil, nil
+	}
+
+	ref, err := ParseExternalRef(opts.ExternalRef)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrValidation, err)
+	}
+
+	return ref, nil
+}
+
+// externalRefToDomain converts the stored reference columns to an ExternalRef
+func externalRefToDomain(refType, id *string) *ExternalRef {
+	if refType == nil || id == nil {
+		return nil
+	}
+
+	return &ExternalRef{Type: *refType, ID: *id}
+}
+
+// externalRefToPostgres converts an ExternalRef to its stored type and ID columns
+func externalRefToPostgres(ref *ExternalRef) (refType, id *string) {
+	if ref == nil {
+		return nil, nil
+	}
+
+	return &ref.Type, &ref.ID
+}
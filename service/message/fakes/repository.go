@@ -0,0 +1,1084 @@
+// Package fakes provides in-memory implementations of the message service dependencies,
+// so the service can be exercised without PostgreSQL or a provider
+package fakes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"qubit/env/postgres/attempts"
+	"qubit/env/postgres/dispatchruns"
+	"qubit/env/postgres/inbound"
+	"qubit/env/postgres/messages"
+	"qubit/env/postgres/outbox"
+	"qubit/env/postgres/providertemplates"
+	"qubit/env/postgres/templates"
+	"qubit/service/message"
+	"qubit/service/shard"
+)
+
+// Repository is an in-memory message.MessageRepository
+// It mirrors the semantics of the PostgreSQL queries, including their sentinel errors
+// Writes of a transaction are applied on commit, IDs are assigned right away like a sequence
+type Repository struct {
+	mu sync.Mutex
+
+	messages  map[int64]*messages.Message
+	attempts  []*attempts.Attempt
+	inbound   []*inbound.Message
+	runs      []*dispatchruns.Run
+	events    []*outbox.Event
+	settings  map[string][]byte
+	templates map[int64]*templates.Template
+	// providerTemplates are keyed by provider, name and language
+	providerTemplates map[string]*providertemplates.Template
+
+	nextID        int64
+	schedulerLock bool
+}
+
+// NewRepository creates an empty in-memory repository
+func NewRepository() *Repository {
+	return &Repository{
+		messages:  make(map[int64]*messages.Message),
+		settings:  make(map[string][]byte),
+		templates: make(map[int64]*templates.Template),
+
+		providerTemplates: make(map[string]*providertemplates.Template),
+	}
+}
+
+// AddTemplate stores a template and assigns its ID
+func (r *Repository) AddTemplate(t *templates.Template) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	t.ID = r.sequence()
+	if t.CreatedAt.IsZero() {
+		t.CreatedAt = time.Now()
+	}
+	stored := *t
+	r.templates[t.ID] = &stored
+}
+
+// AddProviderTemplate stores a template of a template-only provider, replacing one with the same name and language
+func (r *Repository) AddProviderTemplate(t *providertemplates.Template) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if t.SyncedAt.IsZero() {
+		t.SyncedAt = time.Now()
+	}
+	stored := *t
+	r.providerTemplates[t.Provider+"/"+t.Name+"/"+t.Language] = &stored
+}
+
+// Events returns the committed outbox events in insertion order
+func (r *Repository) Events() []*outbox.Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	events := make([]*outbox.Event, 0, len(r.events))
+	for _, e := range r.events {
+		stored := *e
+		events = append(events, &stored)
+	}
+	return events
+}
+
+// sequence returns the next ID, shared by all tables; must be called with mu held
+func (r *Repository) sequence() int64 {
+	r.nextID++
+	return r.nextID
+}
+
+// snapshot returns a copy of a stored message, so callers never modify the store
+func snapshot(msg *messages.Message) *messages.Message {
+	stored := *msg
+	return &stored
+}
+
+// collect returns copies of the stored messages matching keep, ordered by less
+func (r *Repository) collect(keep func(*messages.Message) bool, less func(a, b *messages.Message) bool) []*messages.Message {
+	var matched []*messages.Message
+	for _, msg := range r.messages {
+		if keep(msg) {
+			matched = append(matched, snapshot(msg))
+		}
+	}
+
+	sort.Slice(matched, func(i, j int) bool { return less(matched[i], matched[j]) })
+	return matched
+}
+
+// byCreatedAt orders messages by creation time, then by ID
+func byCreatedAt(a, b *messages.Message) bool {
+	if !a.CreatedAt.Equal(b.CreatedAt) {
+		return a.CreatedAt.Before(b.CreatedAt)
+	}
+	return a.ID < b.ID
+}
+
+// limited truncates msgs to limit, 0 keeps all of them
+func limited(msgs []*messages.Message, limit int) []*messages.Message {
+	if limit > 0 && len(msgs) > limit {
+		return msgs[:limit]
+	}
+	return msgs
+}
+
+// due reports whether a timestamp is unset or not after now
+func due(t *time.Time, now time.Time) bool {
+	return t == nil || !t.After(now)
+}
+
+// shardKey stands in for the shard_key column, the hash differs from PostgreSQL's hashtext but has the same range
+func shardKey(phoneNumber string) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(phoneNumber))
+	return int(h.Sum32() % shard.Total)
+}
+
+// claim marks msg as sending for lockedBy until the lease expires; must be called with mu held
+func claim(msg *messages.Message, lockedBy string, lease time.Duration, now time.Time) {
+	expiresAt := now.Add(lease)
+	msg.Status = messages.StatusSending
+	msg.LockedAt = &now
+	msg.LockedBy = &lockedBy
+	msg.LeaseExpiresAt = &expiresAt
+}
+
+// ofTenant reports whether msg belongs to tenantID, every message does for a nil tenantID
+func ofTenant(msg *messages.Message, tenantID *int64) bool {
+	return tenantID == nil || (msg.TenantID != nil && *msg.TenantID == *tenantID)
+}
+
+// sameTenant reports whether two tenant IDs are equal, nil only equals nil like IS NOT DISTINCT FROM
+func sameTenant(a, b *int64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// unlock clears the claim of msg; must be called with mu held
+func unlock(msg *messages.Message) {
+	msg.LockedAt = nil
+	msg.LockedBy = nil
+	msg.LeaseExpiresAt = nil
+}
+
+// GetMessage returns messages.ErrNotFound if the message does not exist or belongs to another tenant
+func (r *Repository) GetMessage(ctx context.Context, id int64, tenantID *int64) (*messages.Message, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	msg, ok := r.messages[id]
+	if !ok || !ofTenant(msg, tenantID) {
+		return nil, messages.ErrNotFound
+	}
+	return snapshot(msg), nil
+}
+
+// GetArchivedMessage always returns messages.ErrNotFound, the fake keeps no archive
+func (r *Repository) GetArchivedMessage(ctx context.Context, id int64, tenantID *int64) (*messages.Message, error) {
+	return nil, messages.ErrNotFound
+}
+
+// CountRedrivable always returns 0, the fake keeps no archive
+func (r *Repository) CountRedrivable(ctx context.Context, filter messages.RedriveFilter) (int64, error) {
+	return 0, nil
+}
+
+// RedriveArchived creates no messages, the fake keeps no archive
+func (r *Repository) RedriveArchived(ctx context.Context, filter messages.RedriveFilter, provider *string, limit int) (int64, error) {
+	return 0, nil
+}
+
+// ListSent returns the sent messages in creation order, 0 returns all of them
+func (r *Repository) ListSent(ctx context.Context, limit int) ([]*messages.Message, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sent := r.collect(func(msg *messages.Message) bool { return msg.Status == messages.StatusSent }, byCreatedAt)
+	return limited(sent, limit), nil
+}
+
+// ListMessages returns the messages matching the filter in creation order
+func (r *Repository) ListMessages(ctx context.Context, f messages.Filter) ([]*messages.Message, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	search := strings.ToLower(f.Search)
+	matched := r.collect(func(msg *messages.Message) bool {
+		switch {
+		case f.Status != "" && msg.Status != f.Status,
+			f.PhoneNumber != "" && msg.PhoneNumber != f.PhoneNumber,
+			f.CreatedFrom != nil && msg.CreatedAt.Before(*f.CreatedFrom),
+			f.CreatedTo != nil && !msg.CreatedAt.Before(*f.CreatedTo),
+			f.ProcessedFrom != nil && (msg.ProcessedAt == nil || msg.ProcessedAt.Before(*f.ProcessedFrom)),
+			f.ProcessedTo != nil && (msg.ProcessedAt == nil || !msg.ProcessedAt.Before(*f.ProcessedTo)),
+			search != "" && !strings.Contains(strings.ToLower(msg.Content), search),
+			f.TenantID != nil && !ofTenant(msg, f.TenantID):
+			return false
+		}
+		for key, value := range f.Metadata {
+			if stored, ok := msg.Metadata[key]; !ok || stored != value {
+				return false
+			}
+		}
+		if f.ExternalRefType != "" && f.ExternalRefID != "" {
+			return msg.ExternalRefType != nil && *msg.ExternalRefType == f.ExternalRefType &&
+				msg.ExternalRefID != nil && *msg.ExternalRefID == f.ExternalRefID
+		}
+		return true
+	}, byCreatedAt)
+
+	return limited(matched, f.Limit), nil
+}
+
+// EachMessage calls fn with the messages matching the filter in creation order, see ListMessages
+func (r *Repository) EachMessage(ctx context.Context, f messages.Filter, fn func(*messages.Message) error) error {
+	matched, _ := r.ListMessages(ctx, f)
+	for _, msg := range matched {
+		if err := fn(msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListInFlight returns the messages in sending, soonest lease expiry first
+func (r *Repository) ListInFlight(ctx context.Context) ([]*messages.Message, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.collect(func(msg *messages.Message) bool { return msg.Status == messages.StatusSending }, func(a, b *messages.Message) bool {
+		switch {
+		case a.LeaseExpiresAt == nil:
+			return b.LeaseExpiresAt != nil
+		case b.LeaseExpiresAt == nil:
+			return false
+		}
+		return a.LeaseExpiresAt.Before(*b.LeaseExpiresAt)
+	}), nil
+}
+
+// ListByFanout returns the messages of a fan-out in creation order
+func (r *Repository) ListByFanout(ctx context.Context, fanoutID string) ([]*messages.Message, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.collect(func(msg *messages.Message) bool {
+		return msg.FanoutID != nil && *msg.FanoutID == fanoutID
+	}, func(a, b *messages.Message) bool { return a.ID < b.ID }), nil
+}
+
+// ClaimUnsent marks up to limit due pending messages as sending, oldest first
+// Only messages whose shard key is in shards are claimed, any shard when shards is empty
+func (r *Repository) ClaimUnsent(ctx context.Context, limit int, lockedBy string, lease time.Duration, defaultProvider string, skipProviders []string, shards []int) ([]*messages.Message, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	candidates := r.collect(func(msg *messages.Message) bool {
+		providerName := defaultProvider
+		if msg.Provider != nil {
+			providerName = *msg.Provider
+		}
+		return msg.Status == messages.StatusPending && due(msg.NextAttemptAt, now) && due(msg.ScheduledAt, now) &&
+			!slices.Contains(skipProviders, providerName) &&
+			(len(shards) == 0 || slices.Contains(shards, shardKey(msg.PhoneNumber)))
+	}, byCreatedAt)
+
+	claimed := make([]*messages.Message, 0, len(candidates))
+	for _, candidate := range limited(candidates, limit) {
+		msg := r.messages[candidate.ID]
+		claim(msg, lockedBy, lease, now)
+		claimed = append(claimed, snapshot(msg))
+	}
+
+	return claimed, nil
+}
+
+// ClaimByID marks a single pending message as sending regardless of its schedule or backoff
+func (r *Repository) ClaimByID(ctx context.Context, id int64, lockedBy string, lease time.Duration) (*messages.Message, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	msg, ok := r.messages[id]
+	if !ok {
+		return nil, messages.ErrNotFound
+	}
+	if msg.Status != messages.StatusPending {
+		return nil, messages.ErrNotPending
+	}
+
+	claim(msg, lockedBy, lease, time.Now())
+	return snapshot(msg), nil
+}
+
+// Release returns claimed messages back to pending
+func (r *Repository) Release(ctx context.Context, ids []int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, id := range ids {
+		if msg, ok := r.messages[id]; ok && msg.Status == messages.StatusSending {
+			msg.Status = messages.StatusPending
+			unlock(msg)
+		}
+	}
+
+	return nil
+}
+
+// ReapStuck returns messages whose lease expired back to pending
+func (r *Repository) ReapStuck(ctx context.Context) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	var reaped int64
+	for _, msg := range r.messages {
+		if msg.Status == messages.StatusSending && (msg.LeaseExpiresAt == nil || msg.LeaseExpiresAt.Before(now)) {
+			msg.Status = messages.StatusPending
+			unlock(msg)
+			reaped++
+		}
+	}
+
+	return reaped, nil
+}
+
+// CountSentTo counts the messages sent to each of phoneNumbers at or after since
+func (r *Repository) CountSentTo(ctx context.Context, phoneNumbers []string, since time.Time) (map[string]messages.RecipientCount, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	counts := make(map[string]messages.RecipientCount, len(phoneNumbers))
+	for _, msg := range r.messages {
+		if msg.Status != messages.StatusSent || msg.ProcessedAt == nil || msg.ProcessedAt.Before(since) ||
+			!slices.Contains(phoneNumbers, msg.PhoneNumber) {
+			continue
+		}
+
+		count := counts[msg.PhoneNumber]
+		if count.Count == 0 || msg.ProcessedAt.Before(count.Oldest) {
+			count.Oldest = *msg.ProcessedAt
+		}
+		count.Count++
+		counts[msg.PhoneNumber] = count
+	}
+
+	return counts, nil
+}
+
+// CountTenantUsage counts the messages of a tenant created since the start of the UTC day and within the last minute
+func (r *Repository) CountTenantUsage(ctx context.Context, tenantID int64) (*messages.TenantUsage, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now().UTC()
+	dayStart, minuteStart := now.Truncate(24*time.Hour), now.Add(-time.Minute)
+	usage := &messages.TenantUsage{}
+	for _, msg := range r.messages {
+		if msg.TenantID == nil || *msg.TenantID != tenantID {
+			continue
+		}
+		if !msg.CreatedAt.Before(dayStart) {
+			usage.Today++
+		}
+		if !msg.CreatedAt.Before(minuteStart) {
+			usage.LastMinute++
+		}
+	}
+
+	return usage, nil
+}
+
+// ExistsByUUID reports whether a message with the given UUID exists
+func (r *Repository) ExistsByUUID(ctx context.Context, uuid string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, msg := range r.messages {
+		if msg.UUID == uuid {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// MessageStats aggregates the messages like the PostgreSQL query
+func (r *Repository) MessageStats(ctx context.Context, includeTest bool) (*messages.Stats, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	stats := &messages.Stats{ByStatus: make(map[string]int64)}
+
+	for _, msg := range r.messages {
+		if msg.IsTest && !includeTest {
+			continue
+		}
+
+		stats.ByStatus[msg.Status]++
+
+		if msg.Status == messages.StatusSent && msg.ProcessedAt != nil {
+			if !msg.ProcessedAt.Before(now.Add(-time.Hour)) {
+				stats.SentLastHour++
+			}
+			if !msg.ProcessedAt.Before(now.Add(-24 * time.Hour)) {
+				stats.SentLastDay++
+			}
+		}
+
+		if msg.Status == messages.StatusPending && due(msg.NextAttemptAt, now) && due(msg.ScheduledAt, now) {
+			stats.Backlog++
+
+			dueAt := msg.CreatedAt
+			for _, t := range []*time.Time{msg.ScheduledAt, msg.NextAttemptAt} {
+				if t != nil && t.After(dueAt) {
+					dueAt = *t
+				}
+			}
+			if stats.OldestDue == nil || dueAt.Before(*stats.OldestDue) {
+				stats.OldestDue = &dueAt
+			}
+		}
+	}
+
+	return stats, nil
+}
+
+// CountDue counts the pending messages that are due now
+func (r *Repository) CountDue(ctx context.Context) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	var count int64
+	for _, msg := range r.messages {
+		if msg.Status == messages.StatusPending && due(msg.NextAttemptAt, now) && due(msg.ScheduledAt, now) {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// Throttle moves a claimed message to status and releases its claim
+func (r *Repository) Throttle(ctx context.Context, id int64, status string, nextAttemptAt *time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	msg, ok := r.messages[id]
+	if !ok || msg.Status != messages.StatusSending {
+		return fmt.Errorf("message with id %d is not being sent", id)
+	}
+
+	msg.Status = status
+	msg.NextAttemptAt = nextAttemptAt
+	unlock(msg)
+
+	return nil
+}
+
+// FindLatestSentTo returns the most recent message sent to phoneNumber at or after since, nil if there is none
+func (r *Repository) FindLatestSentTo(ctx context.Context, phoneNumber string, since time.Time) (*messages.Message, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sent := r.collect(func(msg *messages.Message) bool {
+		return msg.PhoneNumber == phoneNumber && msg.Status == messages.StatusSent &&
+			msg.ProcessedAt != nil && !msg.ProcessedAt.Before(since)
+	}, func(a, b *messages.Message) bool { return a.ProcessedAt.After(*b.ProcessedAt) })
+
+	if len(sent) == 0 {
+		return nil, nil
+	}
+	return sent[0], nil
+}
+
+// CancelMessage marks a pending message as cancelled
+func (r *Repository) CancelMessage(ctx context.Context, id int64, tenantID *int64) (*messages.Message, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	msg, ok := r.messages[id]
+	if !ok || !ofTenant(msg, tenantID) {
+		return nil, messages.ErrNotFound
+	}
+	if msg.Status != messages.StatusPending {
+		return nil, messages.ErrNotPending
+	}
+
+	msg.Status = messages.StatusCancelled
+	msg.NextAttemptAt = nil
+
+	return snapshot(msg), nil
+}
+
+// ListAttempts returns the attempts of a message in attempt order
+func (r *Repository) ListAttempts(ctx context.Context, messageID int64) ([]*attempts.Attempt, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var matched []*attempts.Attempt
+	for _, a := range r.attempts {
+		if a.MessageID == messageID {
+			stored := *a
+			matched = append(matched, &stored)
+		}
+	}
+
+	sort.SliceStable(matched, func(i, j int) bool { return matched[i].AttemptNumber < matched[j].AttemptNumber })
+	return matched, nil
+}
+
+// AttemptStats aggregates the attempts started at or after since like the PostgreSQL query
+func (r *Repository) AttemptStats(ctx context.Context, since time.Time, includeTest bool) (*attempts.Stats, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stats := &attempts.Stats{Failures: make(map[string]int64)}
+	var queueWait, lockToSend, webhook, dbUpdate []int64
+
+	for _, a := range r.attempts {
+		msg, ok := r.messages[a.MessageID]
+		if !ok || a.StartedAt.Before(since) || (msg.IsTest && !includeTest) {
+			continue
+		}
+
+		stats.Attempts++
+		if a.Success {
+			stats.Succeeded++
+		} else {
+			category := "other"
+			if a.FailureCategory != nil {
+				category = *a.FailureCategory
+			}
+			stats.Failures[category]++
+		}
+
+		queueWait = append(queueWait, a.QueueWaitMs)
+		lockToSend = append(lockToSend, a.LockToSendMs)
+		webhook = append(webhook, a.WebhookMs)
+		dbUpdate = append(dbUpdate, a.DBUpdateMs)
+	}
+
+	stats.QueueWait = phaseStats(queueWait)
+	stats.LockToSend = phaseStats(lockToSend)
+	stats.Webhook = phaseStats(webhook)
+	stats.DBUpdate = phaseStats(dbUpdate)
+
+	return stats, nil
+}
+
+// phaseStats computes the average, the interpolated 95th percentile like percentile_cont and the maximum
+func phaseStats(values []int64) attempts.PhaseStats {
+	if len(values) == 0 {
+		return attempts.PhaseStats{}
+	}
+
+	slices.Sort(values)
+
+	var sum int64
+	for _, v := range values {
+		sum += v
+	}
+
+	rank := 0.95 * float64(len(values)-1)
+	lower, upper := values[int(math.Floor(rank))], values[int(math.Ceil(rank))]
+	fraction := rank - math.Floor(rank)
+
+	return attempts.PhaseStats{
+		AvgMs: float64(sum) / float64(len(values)),
+		P95Ms: float64(lower) + fraction*float64(upper-lower),
+		MaxMs: values[len(values)-1],
+	}
+}
+
+// CreateInbound stores an inbound message and assigns its ID
+func (r *Repository) CreateInbound(ctx context.Context, msg *inbound.Message) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	msg.ID = r.sequence()
+	if msg.ReceivedAt.IsZero() {
+		msg.ReceivedAt = time.Now()
+	}
+	stored := *msg
+	r.inbound = append(r.inbound, &stored)
+
+	return nil
+}
+
+// ListInbound returns the inbound messages with the message they reply to, oldest first
+func (r *Repository) ListInbound(ctx context.Context, limit int) ([]*inbound.MessageWithReplyTo, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var result []*inbound.MessageWithReplyTo
+	for _, msg := range r.sortedInbound(func(*inbound.Message) bool { return true }) {
+		withReplyTo := &inbound.MessageWithReplyTo{Message: *msg}
+		if msg.ReplyToID != nil {
+			if original, ok := r.messages[*msg.ReplyToID]; ok {
+				withReplyTo.ReplyTo = &inbound.ReplyTo{
+					ID:          original.ID,
+					Content:     original.Content,
+					MessageID:   original.MessageID,
+					ProcessedAt: original.ProcessedAt,
+				}
+			}
+		}
+		result = append(result, withReplyTo)
+	}
+
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+// EachInbound calls fn with the inbound messages oldest first, see ListInbound
+func (r *Repository) EachInbound(ctx context.Context, fn func(*inbound.MessageWithReplyTo) error) error {
+	all, _ := r.ListInbound(ctx, 0)
+	for _, msg := range all {
+		if err := fn(msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListInboundByReplyTo returns the inbound messages correlated to messageID, oldest first
+func (r *Repository) ListInboundByReplyTo(ctx context.Context, messageID int64) ([]*inbound.Message, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.sortedInbound(func(msg *inbound.Message) bool {
+		return msg.ReplyToID != nil && *msg.ReplyToID == messageID
+	}), nil
+}
+
+// sortedInbound returns copies of the inbound messages matching keep by receive time; must be called with mu held
+func (r *Repository) sortedInbound(keep func(*inbound.Message) bool) []*inbound.Message {
+	var matched []*inbound.Message
+	for _, msg := range r.inbound {
+		if keep(msg) {
+			stored := *msg
+			matched = append(matched, &stored)
+		}
+	}
+
+	sort.SliceStable(matched, func(i, j int) bool { return matched[i].ReceivedAt.Before(matched[j].ReceivedAt) })
+	return matched
+}
+
+// CreateDispatchRun stores the report of a scheduler run and assigns its ID
+func (r *Repository) CreateDispatchRun(ctx context.Context, run *dispatchruns.Run) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	run.ID = r.sequence()
+	stored := *run
+	r.runs = append(r.runs, &stored)
+
+	return nil
+}
+
+// ListDispatchRuns returns up to limit runs with an ID below before, or all when before is 0, newest first
+func (r *Repository) ListDispatchRuns(ctx context.Context, before int64, limit int) ([]*dispatchruns.Run, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var result []*dispatchruns.Run
+	for i := len(r.runs) - 1; i >= 0 && len(result) < limit; i-- {
+		if before > 0 && r.runs[i].ID >= before {
+			continue
+		}
+		stored := *r.runs[i]
+		result = append(result, &stored)
+	}
+	return result, nil
+}
+
+// DeleteDispatchRunsBefore deletes the runs started before the cutoff
+func (r *Repository) DeleteDispatchRunsBefore(ctx context.Context, before time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	kept := r.runs[:0]
+	for _, run := range r.runs {
+		if !run.StartedAt.Before(before) {
+			kept = append(kept, run)
+		}
+	}
+	deleted := int64(len(r.runs) - len(kept))
+	r.runs = kept
+
+	return deleted, nil
+}
+
+// GetSetting decodes the value stored under key into dest, false if the key is not set
+func (r *Repository) GetSetting(ctx context.Context, key string, dest interface{}) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	raw, ok := r.settings[key]
+	if !ok {
+		return false, nil
+	}
+
+	if err := json.Unmarshal(raw, dest); err != nil {
+		return false, fmt.Errorf("failed to decode setting %s: %w", key, err)
+	}
+	return true, nil
+}
+
+// SetSetting stores value under key as JSON
+func (r *Repository) SetSetting(ctx context.Context, key string, value interface{}) error {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode setting %s: %w", key, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.settings[key] = raw
+	return nil
+}
+
+// DeleteSetting removes the value stored under key
+func (r *Repository) DeleteSetting(ctx context.Context, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.settings, key)
+	return nil
+}
+
+// GetTemplate returns a template stored with AddTemplate, templates.ErrNotFound otherwise
+func (r *Repository) GetTemplate(ctx context.Context, id int64) (*templates.Template, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	t, ok := r.templates[id]
+	if !ok {
+		return nil, templates.ErrNotFound
+	}
+	stored := *t
+	return &stored, nil
+}
+
+// GetProviderTemplate returns a template stored with AddProviderTemplate, providertemplates.ErrNotFound otherwise
+func (r *Repository) GetProviderTemplate(ctx context.Context, provider, name, language string) (*providertemplates.Template, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	t, ok := r.providerTemplates[provider+"/"+name+"/"+language]
+	if !ok {
+		return nil, providertemplates.ErrNotFound
+	}
+	stored := *t
+	return &stored, nil
+}
+
+// ListProviderTemplates returns the templates stored with AddProviderTemplate ordered by provider, name and language
+func (r *Repository) ListProviderTemplates(ctx context.Context) ([]*providertemplates.Template, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	list := make([]*providertemplates.Template, 0, len(r.providerTemplates))
+	for _, t := range r.providerTemplates {
+		stored := *t
+		list = append(list, &stored)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		a, b := list[i], list[j]
+		return a.Provider+"/"+a.Name+"/"+a.Language < b.Provider+"/"+b.Name+"/"+b.Language
+	})
+	return list, nil
+}
+
+// TrySchedulerLock takes the scheduler lock unless it is already held
+func (r *Repository) TrySchedulerLock(ctx context.Context) (func(), bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.schedulerLock {
+		return nil, false, nil
+	}
+	r.schedulerLock = true
+
+	release := func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		r.schedulerLock = false
+	}
+	return release, true, nil
+}
+
+// BeginTx starts a transaction whose writes are applied when it commits
+func (r *Repository) BeginTx(ctx context.Context) (message.MessageTx, error) {
+	return &tx{repo: r}, nil
+}
+
+// tx is an in-memory message.MessageTx
+// Every write is checked against the committed state right away and queued as an operation
+type tx struct {
+	repo   *Repository
+	parent *tx // nil for the outermost transaction
+
+	ops    []func()
+	closed bool
+}
+
+// queue adds an operation applied with the repository locked once the outermost transaction commits
+func (t *tx) queue(op func()) {
+	t.ops = append(t.ops, op)
+}
+
+// newMessage prepares a message insert, assigning ID and UUID like the database defaults
+func (t *tx) newMessage(msg *messages.Message) *messages.Message {
+	if msg.CreatedAt.IsZero() {
+		msg.CreatedAt = time.Now()
+	}
+	if msg.Status == "" {
+		msg.Status = messages.StatusPending
+	}
+	if msg.UUID == "" {
+		msg.UUID = uuid.NewString()
+	}
+
+	t.repo.mu.Lock()
+	msg.ID = t.repo.sequence()
+	t.repo.mu.Unlock()
+
+	return snapshot(msg)
+}
+
+// CreateMessage inserts a message and assigns its ID and UUID, unless it brings its own
+func (t *tx) CreateMessage(ctx context.Context, msg *messages.Message) error {
+	if t.closed {
+		return pgx.ErrTxClosed
+	}
+
+	if msg.UUID != "" {
+		t.repo.mu.Lock()
+		for _, stored := range t.repo.messages {
+			if stored.UUID == msg.UUID {
+				t.repo.mu.Unlock()
+				return messages.ErrDuplicateUUID
+			}
+		}
+		t.repo.mu.Unlock()
+	}
+
+	stored := t.newMessage(msg)
+	t.queue(func() { t.repo.messages[stored.ID] = stored })
+
+	return nil
+}
+
+// CreateFanout inserts one message per phone number sharing the settings of msg
+func (t *tx) CreateFanout(ctx context.Context, msg *messages.Message, fanoutID string, phoneNumbers []string) ([]*messages.Message, error) {
+	if t.closed {
+		return nil, pgx.ErrTxClosed
+	}
+
+	created := make([]*messages.Message, 0, len(phoneNumbers))
+	for _, phoneNumber := range phoneNumbers {
+		recipient := *msg
+		recipient.ID = 0
+		recipient.UUID = ""
+		recipient.PhoneNumber = phoneNumber
+		recipient.FanoutID = &fanoutID
+
+		stored := t.newMessage(&recipient)
+		t.queue(func() { t.repo.messages[stored.ID] = stored })
+		created = append(created, snapshot(stored))
+	}
+
+	return created, nil
+}
+
+// UpsertMessage inserts a message identified by its UUID or updates it while it is pending
+func (t *tx) UpsertMessage(ctx context.Context, msg *messages.Message) (bool, error) {
+	if t.closed {
+		return false, pgx.ErrTxClosed
+	}
+
+	t.repo.mu.Lock()
+	var existing *messages.Message
+	for _, stored := range t.repo.messages {
+		if stored.UUID == msg.UUID {
+			existing = snapshot(stored)
+			break
+		}
+	}
+	t.repo.mu.Unlock()
+
+	if existing == nil {
+		stored := t.newMessage(msg)
+		t.queue(func() { t.repo.messages[stored.ID] = stored })
+		return true, nil
+	}
+
+	if !sameTenant(existing.TenantID, msg.TenantID) {
+		return false, messages.ErrDuplicateUUID
+	}
+	if existing.Status != messages.StatusPending {
+		return false, messages.ErrNotPending
+	}
+
+	existing.PhoneNumber = msg.PhoneNumber
+	existing.Content = msg.Content
+	existing.Status = msg.Status
+	existing.Provider = msg.Provider
+	existing.ScheduledAt = msg.ScheduledAt
+	existing.Transactional = msg.Transactional
+	existing.RetryPolicy = msg.RetryPolicy
+	existing.ExternalRefType = msg.ExternalRefType
+	existing.ExternalRefID = msg.ExternalRefID
+	existing.Metadata = msg.Metadata
+	existing.ContentLocale = msg.ContentLocale
+
+	*msg = *existing
+	t.queue(func() { t.repo.messages[existing.ID] = existing })
+
+	return false, nil
+}
+
+// update queues the outcome of a send to a committed message
+// Fails with messages.ErrLeaseLost unless the message is still sending under the claim of lockedBy
+func (t *tx) update(id int64, lockedBy string, change func(msg *messages.Message)) error {
+	if t.closed {
+		return pgx.ErrTxClosed
+	}
+
+	t.repo.mu.Lock()
+	msg, ok := t.repo.messages[id]
+	leased := ok && msg.Status == messages.StatusSending && msg.LockedBy != nil && *msg.LockedBy == lockedBy
+	t.repo.mu.Unlock()
+	if !leased {
+		return fmt.Errorf("message %d: %w", id, messages.ErrLeaseLost)
+	}
+
+	t.queue(func() {
+		if msg, ok := t.repo.messages[id]; ok {
+			change(msg)
+		}
+	})
+	return nil
+}
+
+// MarkSent marks a message as sent
+func (t *tx) MarkSent(ctx context.Context, id int64, lockedBy string, messageID *string, processedAt *time.Time) error {
+	return t.update(id, lockedBy, func(msg *messages.Message) {
+		msg.MessageID = messageID
+		msg.ProcessedAt = processedAt
+		msg.Status = messages.StatusSent
+		msg.NextAttemptAt = nil
+	})
+}
+
+// MarkFailed records a failed send attempt with its resulting status and retry schedule
+func (t *tx) MarkFailed(ctx context.Context, id int64, lockedBy string, status string, retryCount int, nextAttemptAt *time.Time) error {
+	return t.update(id, lockedBy, func(msg *messages.Message) {
+		msg.Status = status
+		msg.RetryCount = retryCount
+		msg.NextAttemptAt = nextAttemptAt
+	})
+}
+
+// CreateAttempt stores a send attempt and assigns its ID
+func (t *tx) CreateAttempt(ctx context.Context, a *attempts.Attempt) error {
+	if t.closed {
+		return pgx.ErrTxClosed
+	}
+
+	t.repo.mu.Lock()
+	a.ID = t.repo.sequence()
+	t.repo.mu.Unlock()
+
+	stored := *a
+	t.queue(func() { t.repo.attempts = append(t.repo.attempts, &stored) })
+	return nil
+}
+
+// CreateEvents stores outbox events in order and assigns their IDs
+func (t *tx) CreateEvents(ctx context.Context, events []*outbox.Event) error {
+	if t.closed {
+		return pgx.ErrTxClosed
+	}
+
+	now := time.Now()
+	for _, e := range events {
+		t.repo.mu.Lock()
+		e.ID = t.repo.sequence()
+		t.repo.mu.Unlock()
+
+		if e.CreatedAt.IsZero() {
+			e.CreatedAt = now
+		}
+		stored := *e
+		t.queue(func() { t.repo.events = append(t.repo.events, &stored) })
+	}
+	return nil
+}
+
+// Begin starts a nested transaction whose writes are handed to t when it commits
+func (t *tx) Begin(ctx context.Context) (message.MessageTx, error) {
+	if t.closed {
+		return nil, pgx.ErrTxClosed
+	}
+	return &tx{repo: t.repo, parent: t}, nil
+}
+
+// Commit applies the queued writes, or hands them to the enclosing transaction
+func (t *tx) Commit(ctx context.Context) error {
+	if t.closed {
+		return pgx.ErrTxClosed
+	}
+	t.closed = true
+
+	if t.parent != nil {
+		t.parent.ops = append(t.parent.ops, t.ops...)
+		return nil
+	}
+
+	t.repo.mu.Lock()
+	defer t.repo.mu.Unlock()
+
+	for _, op := range t.ops {
+		op()
+	}
+	return nil
+}
+
+// Rollback discards the queued writes
+func (t *tx) Rollback(ctx context.Context) error {
+	if t.closed {
+		return pgx.ErrTxClosed
+	}
+	t.closed = true
+	t.ops = nil
+	return nil
+}
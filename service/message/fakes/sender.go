@@ -0,0 +1,242 @@
+package fakes
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"qubit/env/config"
+	"qubit/env/provider"
+)
+
+// Sent is a message handed to a fake provider
+type Sent struct {
+	PhoneNumber string
+	Content     string
+	MessageID   string
+	// Template is the provider template sent instead of content, nil for content
+	Template *provider.TemplateMessage
+}
+
+// Provider is an in-memory provider.Sender recording every message it accepts
+type Provider struct {
+	name string
+
+	mu   sync.Mutex
+	sent []Sent
+	err  error
+	hang chan struct{}
+	hung func()
+}
+
+// SendMessage records the message and returns a provider message ID, or the error set with FailWith
+func (p *Provider) SendMessage(ctx context.Context, phoneNumber, content string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	p.mu.Lock()
+	hang, hung := p.hang, p.hung
+	p.mu.Unlock()
+	if hang != nil {
+		hung()
+		<-hang
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.err != nil {
+		return "", p.err
+	}
+
+	messageID := fmt.Sprintf("%s-%d", p.name, len(p.sent)+1)
+	p.sent = append(p.sent, Sent{
+		PhoneNumber: phoneNumber,
+		Content:     content,
+		MessageID:   messageID,
+	})
+
+	return messageID, nil
+}
+
+// SendTemplate records the template like SendMessage records content
+func (p *Provider) SendTemplate(ctx context.Context, phoneNumber string, tmpl provider.TemplateMessage) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.err != nil {
+		return "", p.err
+	}
+
+	messageID := fmt.Sprintf("%s-%d", p.name, len(p.sent)+1)
+	p.sent = append(p.sent, Sent{
+		PhoneNumber: phoneNumber,
+		MessageID:   messageID,
+		Template:    &tmpl,
+	})
+
+	return messageID, nil
+}
+
+// FailWith makes every following send fail with err, nil accepts messages again
+func (p *Provider) FailWith(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.err = err
+}
+
+// Hang makes every following send block until resume is called, ignoring its context like a stalled instance
+// hung is closed once the first send blocks
+func (p *Provider) Hang() (hung <-chan struct{}, resume func()) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	blocked, release := make(chan struct{}), make(chan struct{})
+	var blockOnce, releaseOnce sync.Once
+	p.hang = release
+	p.hung = func() { blockOnce.Do(func() { close(blocked) }) }
+
+	return blocked, func() {
+		releaseOnce.Do(func() {
+			p.mu.Lock()
+			p.hang = nil
+			p.mu.Unlock()
+			close(release)
+		})
+	}
+}
+
+// Sent returns the accepted messages in send order
+func (p *Provider) Sent() []Sent {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]Sent(nil), p.sent...)
+}
+
+// Sender is an in-memory message.MessageSender with one fake Provider per configured provider
+type Sender struct {
+	configs   map[string]config.ProviderConfig
+	providers map[string]*Provider
+	names     []string
+
+	mu   sync.Mutex
+	open map[string]bool
+}
+
+// NewSender creates a fake provider per config, the first one is the default provider
+// Without configs a single provider named default is created
+func NewSender(configs ...config.ProviderConfig) *Sender {
+	if len(configs) == 0 {
+		configs = []config.ProviderConfig{{Name: "default"}}
+	}
+
+	s := &Sender{
+		configs:   make(map[string]config.ProviderConfig, len(configs)),
+		providers: make(map[string]*Provider, len(configs)),
+		open:      make(map[string]bool),
+	}
+
+	for _, cfg := range configs {
+		s.configs[cfg.Name] = cfg
+		s.providers[cfg.Name] = &Provider{name: cfg.Name}
+		s.names = append(s.names, cfg.Name)
+	}
+
+	return s
+}
+
+// Provider returns the fake provider of the named provider, nil if it is not configured
+func (s *Sender) Provider(name string) *Provider {
+	return s.providers[name]
+}
+
+// SetCircuitOpen opens or closes the circuit of the named provider, see OpenCircuits
+func (s *Sender) SetCircuitOpen(name string, open bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.open[name] = open
+}
+
+// Get returns the fake provider of the named provider
+func (s *Sender) Get(name string) (provider.Sender, bool) {
+	p, ok := s.providers[name]
+	if !ok {
+		return nil, false
+	}
+	return p, true
+}
+
+// Default returns the fake provider of the default provider
+func (s *Sender) Default() provider.Sender {
+	return s.providers[s.names[0]]
+}
+
+// DefaultName returns the name of the default provider
+func (s *Sender) DefaultName() string {
+	return s.names[0]
+}
+
+// SandboxName returns the name of the first sandbox provider
+func (s *Sender) SandboxName() (string, bool) {
+	for _, name := range s.names {
+		if s.configs[name].Sandbox {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// Names returns the provider names in configuration order
+func (s *Sender) Names() []string {
+	return append([]string(nil), s.names...)
+}
+
+// Config returns the configuration of the named provider
+func (s *Sender) Config(name string) (config.ProviderConfig, bool) {
+	cfg, ok := s.configs[name]
+	return cfg, ok
+}
+
+// OpenCircuits returns the names of the providers opened with SetCircuitOpen
+func (s *Sender) OpenCircuits() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var open []string
+	for _, name := range s.names {
+		if s.open[name] {
+			open = append(open, name)
+		}
+	}
+	return open
+}
+
+// Maintenance is a message.MaintenanceMode toggled by tests
+type Maintenance struct {
+	mu      sync.Mutex
+	enabled bool
+}
+
+// Set enables or disables maintenance mode
+func (m *Maintenance) Set(enabled bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.enabled = enabled
+}
+
+// Enabled reports whether maintenance mode is enabled
+func (m *Maintenance) Enabled() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.enabled
+}
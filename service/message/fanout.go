@@ -0,0 +1,173 @@
+package message
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"qubit/pkg/apperr"
+)
+
+// MaxFanoutRecipients caps the recipients of a single fan-out request
+const MaxFanoutRecipients = 100
+
+// ErrFanoutNotFound is returned when no message belongs to the fan-out
+var ErrFanoutNotFound = apperr.NewNotFound("fanout_not_found", "fan-out not found")
+
+// Fanout is a group of messages created from one multi-recipient request
+type Fanout struct {
+	ID       string
+	Messages []*Message
+}
+
+// StatusCounts returns the number of messages of the fan-out per status
+func (f *Fanout) StatusCounts() map[Status]int {
+	counts := make(map[Status]int)
+	for _, msg := range f.Messages {
+		counts[msg.Status]++
+	}
+	return counts
+}
+
+// Done reports whether every message of the fan-out reached a final status
+func (f *Fanout) Done() bool {
+	for _, msg := range f.Messages {
+		if msg.Status == StatusPending || msg.Status == StatusSending {
+			return false
+		}
+	}
+	return true
+}
+
+// CreateFanout creates one message per phone number sharing the same content, linked by a new fan-out ID
+// The content or template is resolved once; every recipient is validated before anything is inserted
+func (s *Service) CreateFanout(ctx context.Context, phoneNumbers []string, content string, opts CreateOptions) (*Fanout, error) {
+	if len(phoneNumbers) == 0 {
+		return nil, fmt.Errorf("%w: at least one recipient is required", ErrValidation)
+	}
+
+	if len(phoneNumbers) > MaxFanoutRecipients {
+		return nil, fmt.Errorf("%w: at most %d recipients are allowed", ErrValidation, MaxFanoutRecipients)
+	}
+
+	provider, err := s.resolveProvider(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	content, providerTemplate, contentLocale, err := s.resolveContent(ctx, content, provider, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	retryPolicy, err := s.resolveRetryPolicy(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	externalRef, err := resolveExternalRef(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	msg := &Message{
+		Content:       content,
+		CreatedAt:     time.Now(),
+		Status:        StatusPending,
+		Provider:      provider,
+		ScheduledAt:   opts.ScheduledAt,
+		IsTest:        opts.IsTest,
+		Transactional: opts.Transactional,
+		RetryPolicy:   retryPolicy,
+		ExternalRef:   externalRef,
+		Metadata:      s.resolveMetadata(opts),
+		TenantID:      opts.TenantID,
+
+		ProviderTemplate: providerTemplate,
+		ContentLocale:    contentLocale,
+	}
+
+	seen := make(map[string]bool, len(phoneNumbers))
+	for _, phoneNumber := range phoneNumbers {
+		if seen[phoneNumber] {
+			return nil, fmt.Errorf("%w: duplicate recipient %s", ErrValidation, phoneNumber)
+		}
+		seen[phoneNumber] = true
+
+		msg.PhoneNumber = phoneNumber
+		if err := msg.Validate(); err != nil {
+			return nil, fmt.Errorf("%w: recipient %s: %v", ErrValidation, phoneNumber, err)
+		}
+	}
+
+	// The recipients share the content, so they are all rejected or quarantined together
+	if err := s.screenURLs(msg); err != nil {
+		return nil, err
+	}
+
+	// Every recipient counts against the quota, so a fan-out over it creates nothing
+	if err := s.checkTenantQuota(ctx, msg.TenantID, len(phoneNumbers)); err != nil {
+		return nil, err
+	}
+
+	fanoutID := uuid.New().String()
+
+	tx, err := s.repo.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		// Rollback is a no-op once the transaction is committed
+		_ = tx.Rollback(ctx)
+	}()
+
+	dbMessages, err := tx.CreateFanout(ctx, ToPostgres(msg), fanoutID, phoneNumbers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create fan-out: %w", err)
+	}
+	created := ToDomainSlice(dbMessages)
+
+	if err := s.recordQuarantineWithTx(ctx, tx, created); err != nil {
+		return nil, err
+	}
+
+	if err := s.recordEventsWithTx(ctx, tx, EventMessageCreated, created, nil); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit fan-out: %w", err)
+	}
+	s.WakeScheduler()
+	s.publishLifecycle(EventMessageCreated, created, nil)
+
+	return &Fanout{ID: fanoutID, Messages: created}, nil
+}
+
+// GetFanout retrieves the messages of a fan-out
+// A non-nil tenantID only keeps the messages of that tenant
+// Returns ErrFanoutNotFound if no message belongs to it
+func (s *Service) GetFanout(ctx context.Context, fanoutID string, tenantID *int64) (*Fanout, error) {
+	dbMessages, err := s.repo.ListByFanout(ctx, fanoutID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fan-out: %w", err)
+	}
+
+	if tenantID != nil {
+		scoped := dbMessages[:0]
+		for _, dbMsg := range dbMessages {
+			if dbMsg.TenantID != nil && *dbMsg.TenantID == *tenantID {
+				scoped = append(scoped, dbMsg)
+			}
+		}
+		dbMessages = scoped
+	}
+
+	if len(dbMessages) == 0 {
+		return nil, ErrFanoutNotFound
+	}
+
+	return &Fanout{ID: fanoutID, Messages: ToDomainSlice(dbMessages)}, nil
+}
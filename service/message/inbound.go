@@ -0,0 +1,129 @@
+package message
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+	"unicode/utf8"
+
+	"qubit/env/postgres/inbound"
+)
+
+// InboundMessage represents a reply received from a recipient
+type InboundMessage struct {
+	ID          int64
+	PhoneNumber string
+	Content     string
+	ReceivedAt  time.Time
+
+	ReplyTo *ReplyTo
+}
+
+// ReplyTo describes the outbound message an inbound message was correlated to
+type ReplyTo struct {
+	ID          int64
+	Content     string
+	MessageID   *string
+	ProcessedAt *time.Time
+}
+
+// Validate checks if the inbound message fields are valid
+func (m *InboundMessage) Validate() error {
+	if m.PhoneNumber == "" {
+		return fmt.Errorf("phone number is required")
+	}
+
+	if !phoneRegex.MatchString(m.PhoneNumber) {
+		return fmt.Errorf("invalid phone number format (expected: +1234567890)")
+	}
+
+	if m.Content == "" {
+		return fmt.Errorf("message content is required")
+	}
+
+	if utf8.RuneCountInString(m.Content) > MaxContentLength {
+		return fmt.Errorf("message content exceeds maximum length of %d characters", MaxContentLength)
+	}
+
+	return nil
+}
+
+// ReceiveReply stores an inbound reply and correlates it to the most recent
+// outbound message sent to the same number within the reply window
+func (s *Service) ReceiveReply(ctx context.Context, phoneNumber, content string) (*InboundMessage, error) {
+	msg := &InboundMessage{
+		PhoneNumber: phoneNumber,
+		Content:     content,
+		ReceivedAt:  time.Now(),
+	}
+
+	if err := msg.Validate(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	// Correlate to the latest outbound message within the window
+	since := msg.ReceivedAt.Add(-s.replyWindow)
+	original, err := s.repo.FindLatestSentTo(ctx, phoneNumber, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to correlate reply: %w", err)
+	}
+
+	dbMsg := &inbound.Message{
+		PhoneNumber: msg.PhoneNumber,
+		Content:     msg.Content,
+		ReceivedAt:  msg.ReceivedAt,
+	}
+
+	if original != nil {
+		dbMsg.ReplyToID = &original.ID
+		msg.ReplyTo = &ReplyTo{
+			ID:          original.ID,
+			Content:     original.Content,
+			MessageID:   original.MessageID,
+			ProcessedAt: original.ProcessedAt,
+		}
+		log.Printf("Inbound reply from %s correlated to message %d", phoneNumber, original.ID)
+	}
+
+	if err := s.repo.CreateInbound(ctx, dbMsg); err != nil {
+		return nil, fmt.Errorf("failed to store inbound message: %w", err)
+	}
+
+	msg.ID = dbMsg.ID
+
+	return msg, nil
+}
+
+// GetInboundMessages retrieves all inbound messages with their correlation data
+// It fails with ErrListTooLarge when there are more than max of them, 0 returns all
+func (s *Service) GetInboundMessages(ctx context.Context, max int) ([]*InboundMessage, error) {
+	limit := 0
+	if max > 0 {
+		limit = max + 1
+	}
+
+	dbMessages, err := s.repo.ListInbound(ctx, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get inbound messages: %w", err)
+	}
+
+	if max > 0 && len(dbMessages) > max {
+		return nil, fmt.Errorf("%w: more than %d inbound messages, stream the list", ErrListTooLarge, max)
+	}
+
+	return InboundToDomainSlice(dbMessages), nil
+}
+
+// StreamInboundMessages calls fn with every inbound message and its correlation data one at a time, oldest first
+// The first error of fn stops the listing
+func (s *Service) StreamInboundMessages(ctx context.Context, fn func(*InboundMessage) error) error {
+	err := s.repo.EachInbound(ctx, func(dbMsg *inbound.MessageWithReplyTo) error {
+		return fn(InboundToDomain(dbMsg))
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get inbound messages: %w", err)
+	}
+
+	return nil
+}
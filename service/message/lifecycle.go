@@ -0,0 +1,31 @@
+package message
+
+// lifecycleBuffer is the number of lifecycle events buffered per subscriber before events are dropped
+const lifecycleBuffer = 256
+
+// publishLifecycle publishes an event of eventType for every message to the subscribers of this instance
+// It must only be called once the change is committed; attempt is the last attempt of a sent or failed message
+func (s *Service) publishLifecycle(eventType string, msgs []*Message, attempt *Attempt) {
+	for _, msg := range msgs {
+		s.lifecycle.Publish(buildEvent(eventType, msg, attempt))
+	}
+}
+
+// publishOutcomes publishes the lifecycle events of persisted outcomes, a retried attempt publishes nothing
+func (s *Service) publishOutcomes(persisted []sendOutcome) {
+	for _, o := range persisted {
+		switch o.msg.Status {
+		case StatusSent:
+			s.publishLifecycle(EventMessageSent, []*Message{o.msg}, o.attempt)
+		case StatusFailed:
+			s.publishLifecycle(EventMessageFailed, []*Message{o.msg}, o.attempt)
+		}
+	}
+}
+
+// SubscribeLifecycle streams the lifecycle events of the messages created and sent by this instance,
+// shaped like the outbox events but with their own event IDs, whether or not event publishing is enabled
+// Events are dropped for a subscriber that falls behind; the returned function unsubscribes
+func (s *Service) SubscribeLifecycle() (<-chan Event, func()) {
+	return s.lifecycle.Subscribe(lifecycleBuffer)
+}
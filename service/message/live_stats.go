@@ -0,0 +1,73 @@
+package message
+
+import (
+	"time"
+
+	"qubit/pkg/rolling"
+)
+
+// liveStatsSpan is the longest window kept by the in-memory counters
+const liveStatsSpan = 15 * time.Minute
+
+// LiveStatsWindows are the windows reported by LiveStats
+var LiveStatsWindows = []time.Duration{time.Minute, 5 * time.Minute, 15 * time.Minute}
+
+// liveStats keeps in-memory rolling counters of this instance's send activity
+type liveStats struct {
+	sent    *rolling.Counter
+	failed  *rolling.Counter
+	drained *rolling.Counter
+}
+
+func newLiveStats() *liveStats {
+	return &liveStats{
+		sent:    rolling.NewCounter(liveStatsSpan),
+		failed:  rolling.NewCounter(liveStatsSpan),
+		drained: rolling.NewCounter(liveStatsSpan),
+	}
+}
+
+// record adds the outcome of a committed batch
+func (l *liveStats) record(sent, failed, drained int) {
+	l.sent.Add(int64(sent))
+	l.failed.Add(int64(failed))
+	l.drained.Add(int64(drained))
+}
+
+// WindowStats holds rates of a single rolling window
+type WindowStats struct {
+	Window time.Duration
+
+	Sent     int64
+	Failed   int64
+	Drained  int64
+	SendRate float64 // sent messages per second
+	// FailureRate is the share of failed attempts among all attempts, 0 without attempts
+	FailureRate float64
+	DrainRate   float64 // messages leaving the pending queue per second
+}
+
+// LiveStats returns a snapshot of this instance's send activity over the last 1, 5 and 15 minutes
+// The counters live in memory, so they reset on restart and only cover this instance
+func (s *Service) LiveStats() []WindowStats {
+	snapshot := make([]WindowStats, 0, len(LiveStatsWindows))
+	for _, window := range LiveStatsWindows {
+		stats := WindowStats{
+			Window:  window,
+			Sent:    s.live.sent.Sum(window),
+			Failed:  s.live.failed.Sum(window),
+			Drained: s.live.drained.Sum(window),
+		}
+
+		seconds := window.Seconds()
+		stats.SendRate = float64(stats.Sent) / seconds
+		stats.DrainRate = float64(stats.Drained) / seconds
+		if attempts := stats.Sent + stats.Failed; attempts > 0 {
+			stats.FailureRate = float64(stats.Failed) / float64(attempts)
+		}
+
+		snapshot = append(snapshot, stats)
+	}
+
+	return snapshot
+}
@@ -1,6 +1,9 @@
 package message
 
 import (
+	"time"
+
+	"qubit/env/postgres/inbound"
 	"qubit/env/postgres/messages"
 )
 
@@ -12,11 +15,36 @@ func ToDomain(message *messages.Message) *Message {
 
 	return &Message{
 		ID:          message.ID,
+		UUID:        message.UUID,
 		PhoneNumber: message.PhoneNumber,
 		Content:     message.Content,
 		CreatedAt:   message.CreatedAt,
 		MessageID:   message.MessageID,
 		ProcessedAt: message.ProcessedAt,
+
+		RetryCount:    message.RetryCount,
+		NextAttemptAt: message.NextAttemptAt,
+
+		Status:   Status(message.Status),
+		Provider: message.Provider,
+
+		ScheduledAt: message.ScheduledAt,
+		LockedAt:    message.LockedAt,
+
+		LockedBy:       message.LockedBy,
+		LeaseExpiresAt: message.LeaseExpiresAt,
+
+		IsTest:        message.IsTest,
+		Transactional: message.Transactional,
+
+		FanoutID:    message.FanoutID,
+		RetryPolicy: retryPolicyToDomain(message.RetryPolicy),
+		ExternalRef: externalRefToDomain(message.ExternalRefType, message.ExternalRefID),
+		Metadata:    message.Metadata,
+		TenantID:    message.TenantID,
+
+		ProviderTemplate: providerTemplateToDomain(message.ProviderTemplate),
+		ContentLocale:    message.ContentLocale,
 	}
 }
 
@@ -26,13 +54,72 @@ func ToPostgres(domainMsg *Message) *messages.Message {
 		return nil
 	}
 
+	externalRefType, externalRefID := externalRefToPostgres(domainMsg.ExternalRef)
+
 	return &messages.Message{
 		ID:          domainMsg.ID,
+		UUID:        domainMsg.UUID,
 		PhoneNumber: domainMsg.PhoneNumber,
 		Content:     domainMsg.Content,
 		CreatedAt:   domainMsg.CreatedAt,
 		MessageID:   domainMsg.MessageID,
 		ProcessedAt: domainMsg.ProcessedAt,
+
+		RetryCount:    domainMsg.RetryCount,
+		NextAttemptAt: domainMsg.NextAttemptAt,
+
+		Status:   string(domainMsg.Status),
+		Provider: domainMsg.Provider,
+
+		ScheduledAt: domainMsg.ScheduledAt,
+		LockedAt:    domainMsg.LockedAt,
+
+		LockedBy:       domainMsg.LockedBy,
+		LeaseExpiresAt: domainMsg.LeaseExpiresAt,
+
+		IsTest:        domainMsg.IsTest,
+		Transactional: domainMsg.Transactional,
+
+		FanoutID:    domainMsg.FanoutID,
+		RetryPolicy: retryPolicyToPostgres(domainMsg.RetryPolicy),
+
+		ExternalRefType: externalRefType,
+		ExternalRefID:   externalRefID,
+
+		Metadata: domainMsg.Metadata,
+		TenantID: domainMsg.TenantID,
+
+		ProviderTemplate: providerTemplateToPostgres(domainMsg.ProviderTemplate),
+
+		ContentLocale: domainMsg.ContentLocale,
+	}
+}
+
+// retryPolicyToDomain converts a stored retry policy to a domain RetryPolicy
+func retryPolicyToDomain(policy *messages.RetryPolicy) *RetryPolicy {
+	if policy == nil {
+		return nil
+	}
+
+	return &RetryPolicy{
+		MaxRetries: policy.MaxRetries,
+		Backoff:    Backoff(policy.Backoff),
+		BaseDelay:  time.Duration(policy.BaseDelaySeconds) * time.Second,
+		MaxDelay:   time.Duration(policy.MaxDelaySeconds) * time.Second,
+	}
+}
+
+// retryPolicyToPostgres converts a domain RetryPolicy to its stored form
+func retryPolicyToPostgres(policy *RetryPolicy) *messages.RetryPolicy {
+	if policy == nil {
+		return nil
+	}
+
+	return &messages.RetryPolicy{
+		MaxRetries:       policy.MaxRetries,
+		Backoff:          string(policy.Backoff),
+		BaseDelaySeconds: int(policy.BaseDelay / time.Second),
+		MaxDelaySeconds:  int(policy.MaxDelay / time.Second),
 	}
 }
 
@@ -49,3 +136,42 @@ func ToDomainSlice(dbMessages []*messages.Message) []*Message {
 
 	return domainMessages
 }
+
+// InboundToDomain converts a postgres inbound message to a domain InboundMessage
+func InboundToDomain(message *inbound.MessageWithReplyTo) *InboundMessage {
+	if message == nil {
+		return nil
+	}
+
+	msg := &InboundMessage{
+		ID:          message.ID,
+		PhoneNumber: message.PhoneNumber,
+		Content:     message.Content,
+		ReceivedAt:  message.ReceivedAt,
+	}
+
+	if message.ReplyTo != nil {
+		msg.ReplyTo = &ReplyTo{
+			ID:          message.ReplyTo.ID,
+			Content:     message.ReplyTo.Content,
+			MessageID:   message.ReplyTo.MessageID,
+			ProcessedAt: message.ReplyTo.ProcessedAt,
+		}
+	}
+
+	return msg
+}
+
+// InboundToDomainSlice converts a slice of postgres inbound messages to domain InboundMessages
+func InboundToDomainSlice(dbMessages []*inbound.MessageWithReplyTo) []*InboundMessage {
+	if dbMessages == nil {
+		return nil
+	}
+
+	domainMessages := make([]*InboundMessage, 0, len(dbMessages))
+	for _, dbMsg := range dbMessages {
+		domainMessages = append(domainMessages, InboundToDomain(dbMsg))
+	}
+
+	return domainMessages
+}
@@ -0,0 +1,77 @@
+package message
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+// Metadata constraints
+const (
+	MaxMetadataKeys        = 20
+	MaxMetadataKeyLength   = 50
+	MaxMetadataValueLength = 255
+)
+
+// LocaleMetadataKey is the metadata field holding the recipient's locale, e.g. tr or pt-BR
+// It picks the content variant when the message is created without a locale
+const LocaleMetadataKey = "locale"
+
+// metadataKeyRegex validates a metadata key, e.g. campaignId or user_id
+var metadataKeyRegex = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_.-]*$`)
+
+// Metadata holds caller-defined string fields stored with a message, such as a campaign or user ID
+// Messages are filtered on exact values of one or more keys
+type Metadata map[string]string
+
+// Validate checks the number of fields and the length of every key and value
+func (m Metadata) Validate() error {
+	if len(m) > MaxMetadataKeys {
+		return fmt.Errorf("metadata exceeds maximum of %d keys", MaxMetadataKeys)
+	}
+
+	// Sorted so the first invalid key reported is stable
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if !metadataKeyRegex.MatchString(key) {
+			return fmt.Errorf("metadata key %q must start with a letter and contain only letters, digits, '_', '.' or '-'", key)
+		}
+
+		if len(key) > MaxMetadataKeyLength {
+			return fmt.Errorf("metadata key %q exceeds maximum length of %d characters", key, MaxMetadataKeyLength)
+		}
+
+		if len(m[key]) > MaxMetadataValueLength {
+			return fmt.Errorf("metadata value of %q exceeds maximum length of %d characters", key, MaxMetadataValueLength)
+		}
+	}
+
+	return nil
+}
+
+// resolveMetadata merges the default metadata of the caller's tenant into the metadata of the message
+// Fields given with the message take precedence over the defaults
+func (s *Service) resolveMetadata(opts CreateOptions) Metadata {
+	if s.tenants == nil || opts.TenantID == nil {
+		return opts.Metadata
+	}
+
+	defaults := s.tenants.DefaultMetadata(*opts.TenantID)
+	if len(defaults) == 0 {
+		return opts.Metadata
+	}
+
+	merged := make(Metadata, len(defaults)+len(opts.Metadata))
+	for key, value := range defaults {
+		merged[key] = value
+	}
+	for key, value := range opts.Metadata {
+		merged[key] = value
+	}
+	return merged
+}
@@ -0,0 +1,96 @@
+package message
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProgressEventType identifies a step of a ProcessUnsentMessages run
+type ProgressEventType string
+
+const (
+	ProgressBatchStarted  ProgressEventType = "batch_started"
+	ProgressMessageSent   ProgressEventType = "message_sent"
+	ProgressMessageFailed ProgressEventType = "message_failed"
+	ProgressBatchFinished ProgressEventType = "batch_finished"
+)
+
+// progressBuffer is the number of events buffered per subscriber before events are dropped
+const progressBuffer = 256
+
+// ProgressEvent reports the progress of a batch while it runs
+// Message events are published as soon as the webhook call returns, before the outcome is persisted
+type ProgressEvent struct {
+	Type    ProgressEventType
+	BatchID string
+	At      time.Time
+
+	// Done and Total count the webhook calls of the batch
+	Done  int
+	Total int
+
+	MessageID int64   // set on message events
+	Error     *string // set on message_failed
+
+	Result *BatchResult // set on batch_finished
+}
+
+// batchProgress publishes the progress events of a single batch
+type batchProgress struct {
+	s     *Service
+	id    string
+	total int
+	done  atomic.Int64
+}
+
+func (s *Service) newBatchProgress(total int) *batchProgress {
+	p := &batchProgress{
+		s:     s,
+		id:    uuid.New().String(),
+		total: total,
+	}
+	p.publish(ProgressEvent{Type: ProgressBatchStarted})
+
+	return p
+}
+
+// messageDone publishes the webhook outcome of a single message, safe for concurrent use
+func (p *batchProgress) messageDone(o sendOutcome) {
+	event := ProgressEvent{
+		Type:      ProgressMessageSent,
+		Done:      int(p.done.Add(1)),
+		MessageID: o.msg.ID,
+	}
+	if o.err != nil {
+		errMsg := o.err.Error()
+		event.Type = ProgressMessageFailed
+		event.Error = &errMsg
+	}
+
+	p.publish(event)
+}
+
+// finished publishes the batch summary once all outcomes are persisted
+func (p *batchProgress) finished(result *BatchResult) {
+	p.publish(ProgressEvent{
+		Type:   ProgressBatchFinished,
+		Done:   int(p.done.Load()),
+		Result: result,
+	})
+}
+
+func (p *batchProgress) publish(event ProgressEvent) {
+	event.BatchID = p.id
+	event.At = time.Now()
+	event.Total = p.total
+
+	p.s.progress.Publish(event)
+}
+
+// SubscribeProgress streams the progress events of the batches run by this instance
+// Events are dropped for a subscriber that falls behind; the returned function unsubscribes
+func (s *Service) SubscribeProgress() (<-chan ProgressEvent, func()) {
+	return s.progress.Subscribe(progressBuffer)
+}
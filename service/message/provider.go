@@ -0,0 +1,100 @@
+package message
+
+import (
+	"fmt"
+	"time"
+
+	"qubit/env/provider"
+)
+
+// CreateOptions holds optional settings for creating a message
+type CreateOptions struct {
+	// Provider pins the message to a configured provider, empty uses the default one
+	Provider string
+	// CallerRole is the role of the caller, checked against the provider's override roles
+	CallerRole string
+	// ScheduledAt delays delivery until the given moment, nil sends right away
+	ScheduledAt *time.Time
+	// IsTest creates a sandbox message, set for callers using a sandbox API key
+	IsTest bool
+	// Transactional exempts the message from the per-recipient limit
+	Transactional bool
+	// Locale is the recipient's stored locale, e.g. tr or pt-BR, picking the content variant of Translations
+	Locale string
+	// Translations hold content variants per locale, the content is the default variant
+	Translations map[string]string
+	// TemplateID renders the content from a stored template filled in with Variables
+	TemplateID *int64
+	Variables  map[string]string
+	// Retry overrides the configured retry policy, nil uses the configured one
+	Retry *RetryOverride
+	// ExternalRef links the message to a business object, written as type:id (e.g. order:12345)
+	ExternalRef string
+	// Metadata is stored with the message and returned as is, on top of the default metadata of the tenant
+	Metadata map[string]string
+	// UUID is the client's own identifier for a single message, empty generates one
+	UUID string
+	// TenantID attributes the message to the tenant of the caller's API key, nil for operator keys
+	TenantID *int64
+	// ProviderTemplate references an approved template, required instead of content on template-only providers
+	ProviderTemplate *ProviderTemplate
+}
+
+// resolveProvider validates a provider pin against the configured providers and caller permissions
+// Test messages are pinned to a sandbox provider, live messages never use one
+// Returns nil when a live message requests no provider
+func (s *Service) resolveProvider(opts CreateOptions) (*string, error) {
+	if opts.Provider == "" {
+		if !opts.IsTest {
+			return nil, nil
+		}
+
+		name, ok := s.providers.SandboxName()
+		if !ok {
+			return nil, ErrNotSandbox
+		}
+		return &name, nil
+	}
+
+	provider, ok := s.providers.Config(opts.Provider)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownProvider, opts.Provider)
+	}
+
+	if !provider.AllowsOverride(opts.CallerRole) {
+		return nil, fmt.Errorf("%w: %s", ErrProviderForbidden, opts.Provider)
+	}
+
+	switch {
+	case opts.IsTest && !provider.Sandbox:
+		return nil, fmt.Errorf("%w: %s", ErrNotSandbox, opts.Provider)
+	case !opts.IsTest && provider.Sandbox:
+		return nil, fmt.Errorf("%w: %s", ErrSandboxProvider, opts.Provider)
+	}
+
+	name := provider.Name
+	return &name, nil
+}
+
+// senderFor returns the provider sender a message is sent through
+// Pinned messages bypass routing and always use their provider
+func (s *Service) senderFor(msg *Message) (provider.Sender, error) {
+	if msg.Provider == nil {
+		return s.providers.Default(), nil
+	}
+
+	sender, ok := s.providers.Get(*msg.Provider)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownProvider, *msg.Provider)
+	}
+
+	return sender, nil
+}
+
+// providerName returns the name of the provider a message is sent through
+func (s *Service) providerName(msg *Message) string {
+	if msg.Provider == nil {
+		return s.providers.DefaultName()
+	}
+	return *msg.Provider
+}
@@ -0,0 +1,187 @@
+package message
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"qubit/env/postgres/messages"
+	"qubit/env/postgres/providertemplates"
+	"qubit/env/provider"
+	"qubit/pkg/apperr"
+)
+
+// Provider template errors
+// At creation they are wrapped in ErrValidation, when rechecked before sending they fail the message
+var (
+	ErrTemplateRequired    = apperr.NewValidation("provider_template_required", "provider only sends approved provider templates")
+	ErrTemplateNotApproved = apperr.NewValidation("provider_template_not_approved", "provider template is not approved")
+)
+
+// failureTemplateNotApproved is the failure category of attempts stopped by the provider template check
+const failureTemplateNotApproved = "template_not_approved"
+
+// ProviderTemplate references a template pre-approved at a template-only provider, e.g. WhatsApp
+// Its parameters fill the numbered slots of the template body in order
+type ProviderTemplate struct {
+	Name       string
+	Language   string
+	Parameters []string
+}
+
+// Validate checks that the template is named and every parameter has a value
+func (t ProviderTemplate) Validate() error {
+	if strings.TrimSpace(t.Name) == "" {
+		return fmt.Errorf("provider template name is required")
+	}
+
+	if strings.TrimSpace(t.Language) == "" {
+		return fmt.Errorf("provider template language is required")
+	}
+
+	for i, value := range t.Parameters {
+		if strings.TrimSpace(value) == "" {
+			return fmt.Errorf("provider template parameter %d is empty", i+1)
+		}
+	}
+
+	return nil
+}
+
+// templateApprovals maps provider/name/language to the approval status last synced from the provider
+type templateApprovals map[string]string
+
+// templateKey identifies a template of a provider in templateApprovals
+func templateKey(provider, name, language string) string {
+	return provider + "/" + name + "/" + language
+}
+
+// resolveProviderTemplate returns the content of a message for a template-only provider, the rendered template body
+// Free-form content and stored templates are rejected, only approved templates with all their parameters are accepted
+func (s *Service) resolveProviderTemplate(ctx context.Context, providerName, content string, opts CreateOptions) (string, *ProviderTemplate, error) {
+	ref := opts.ProviderTemplate
+	if ref == nil || content != "" || opts.TemplateID != nil {
+		return "", nil, fmt.Errorf("%w: %w: %s, reference one with providerTemplate instead of content", ErrValidation, ErrTemplateRequired, providerName)
+	}
+
+	if err := ref.Validate(); err != nil {
+		return "", nil, fmt.Errorf("%w: %v", ErrValidation, err)
+	}
+
+	dbTemplate, err := s.repo.GetProviderTemplate(ctx, providerName, ref.Name, ref.Language)
+	if errors.Is(err, providertemplates.ErrNotFound) {
+		return "", nil, fmt.Errorf("%w: %w: %s (%s) is not known for %s", ErrValidation, ErrTemplateNotApproved, ref.Name, ref.Language, providerName)
+	}
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get provider template: %w", err)
+	}
+
+	if dbTemplate.Status != provider.TemplateApproved {
+		return "", nil, fmt.Errorf("%w: %w: %s (%s) is %s", ErrValidation, ErrTemplateNotApproved, ref.Name, ref.Language, dbTemplate.Status)
+	}
+
+	if len(ref.Parameters) != dbTemplate.Parameters {
+		return "", nil, fmt.Errorf("%w: provider template %s (%s) expects %d parameters, got %d",
+			ErrValidation, ref.Name, ref.Language, dbTemplate.Parameters, len(ref.Parameters))
+	}
+
+	return provider.RenderTemplate(dbTemplate.Body, ref.Parameters), ref, nil
+}
+
+// loadTemplateApprovals reads the approval status of provider templates before claimed messages are sent
+// Nothing is read when no message goes through a template-only provider
+func (s *Service) loadTemplateApprovals(ctx context.Context, msgs []*Message) error {
+	needed := false
+	for _, msg := range msgs {
+		if msg.ProviderTemplate != nil {
+			needed = true
+			break
+		}
+	}
+	if !needed {
+		return nil
+	}
+
+	dbTemplates, err := s.repo.ListProviderTemplates(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to check provider templates: %w", err)
+	}
+
+	approvals := make(templateApprovals, len(dbTemplates))
+	for _, t := range dbTemplates {
+		approvals[templateKey(t.Provider, t.Name, t.Language)] = t.Status
+	}
+	s.approvals.Store(&approvals)
+
+	return nil
+}
+
+// checkProviderTemplate returns an error for a message its template-only provider would not accept
+// The template may have been paused or rejected since the message was created
+func (s *Service) checkProviderTemplate(msg *Message) error {
+	name := s.providerName(msg)
+	p, ok := s.providers.Config(name)
+	if !ok || !p.RequiresTemplate() {
+		return nil
+	}
+
+	ref := msg.ProviderTemplate
+	if ref == nil {
+		return fmt.Errorf("%w: %s", ErrTemplateRequired, name)
+	}
+
+	var approvals templateApprovals
+	if loaded := s.approvals.Load(); loaded != nil {
+		approvals = *loaded
+	}
+
+	status, ok := approvals[templateKey(name, ref.Name, ref.Language)]
+	switch {
+	case !ok:
+		return fmt.Errorf("%w: %s (%s) no longer exists at %s", ErrTemplateNotApproved, ref.Name, ref.Language, name)
+	case status != provider.TemplateApproved:
+		return fmt.Errorf("%w: %s (%s) is %s", ErrTemplateNotApproved, ref.Name, ref.Language, status)
+	}
+
+	return nil
+}
+
+// message returns the template as sent to the provider
+func (t *ProviderTemplate) message() provider.TemplateMessage {
+	return provider.TemplateMessage{Name: t.Name, Language: t.Language, Parameters: t.Parameters}
+}
+
+// providerTemplateToDomain converts a stored provider template reference to a domain ProviderTemplate
+func providerTemplateToDomain(t *messages.ProviderTemplate) *ProviderTemplate {
+	if t == nil {
+		return nil
+	}
+	return &ProviderTemplate{Name: t.Name, Language: t.Language, Parameters: t.Parameters}
+}
+
+// providerTemplateToPostgres converts a domain ProviderTemplate to its stored form
+func providerTemplateToPostgres(t *ProviderTemplate) *messages.ProviderTemplate {
+	if t == nil {
+		return nil
+	}
+	return &messages.ProviderTemplate{Name: t.Name, Language: t.Language, Parameters: t.Parameters}
+}
+
+// blocked returns the status of a message whose send was stopped by a policy rather than the provider
+// false for any other error
+func (s *Service) blocked(err error) (Status, bool) {
+	switch {
+	case errors.Is(err, ErrURLNotAllowed):
+		return s.urlRecheck, true
+	case errors.Is(err, ErrTemplateRequired), errors.Is(err, ErrTemplateNotApproved):
+		return StatusFailed, true
+	}
+	return "", false
+}
+
+// isBlocked reports whether err stopped a send by policy, see blocked
+func (s *Service) isBlocked(err error) bool {
+	_, ok := s.blocked(err)
+	return ok
+}
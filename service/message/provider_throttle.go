@@ -0,0 +1,95 @@
+package message
+
+import (
+	"errors"
+	"log"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// errProviderThrottled is returned for a message not sent because its provider asked to back off
+var errProviderThrottled = errors.New("provider throttled")
+
+// maxProviderThrottle caps the delay taken from a Retry-After header, so a provider cannot stall sending for days
+const maxProviderThrottle = time.Hour
+
+// ProviderThrottle is a provider answering 429 or 503 with a Retry-After header
+// Until then its messages are neither sent nor claimed; the rest of the batch goes back to pending
+type ProviderThrottle struct {
+	Provider   string
+	StatusCode int
+	Since      time.Time
+	Until      time.Time
+	// Deferred counts the messages returned to pending unsent while the provider was throttled
+	Deferred int64
+}
+
+// providerThrottles tracks the throttled providers of the instance
+type providerThrottles struct {
+	mu        sync.Mutex
+	providers map[string]*ProviderThrottle
+}
+
+// throttle pauses sending to a provider for retryAfter, extending a throttle that ends earlier
+func (t *providerThrottles) throttle(name string, statusCode int, retryAfter time.Duration) {
+	now := time.Now()
+	until := now.Add(min(retryAfter, maxProviderThrottle))
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	current, ok := t.providers[name]
+	if ok && current.Until.After(now) {
+		if until.After(current.Until) {
+			current.Until = until
+			current.StatusCode = statusCode
+		}
+		return
+	}
+
+	if t.providers == nil {
+		t.providers = make(map[string]*ProviderThrottle)
+	}
+	t.providers[name] = &ProviderThrottle{Provider: name, StatusCode: statusCode, Since: now, Until: until}
+
+	log.Printf("⚠ Provider %s throttled with HTTP %d, pausing its messages until %s", name, statusCode, until.Format(time.RFC3339))
+}
+
+// hold returns when the throttle of a provider ends, counting the message held back as deferred
+// Returns false when the provider is not throttled
+func (t *providerThrottles) hold(name string) (time.Time, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	current, ok := t.providers[name]
+	if !ok || !current.Until.After(time.Now()) {
+		return time.Time{}, false
+	}
+
+	current.Deferred++
+	return current.Until, true
+}
+
+// active returns the providers throttled now, sorted by name, and drops the throttles that ended
+func (t *providerThrottles) active() []ProviderThrottle {
+	now := time.Now()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var throttles []ProviderThrottle
+	for name, current := range t.providers {
+		if !current.Until.After(now) {
+			log.Printf("Provider %s throttle ended after %s, %d messages were deferred", name, current.Until.Sub(current.Since).Round(time.Second), current.Deferred)
+			delete(t.providers, name)
+			continue
+		}
+		throttles = append(throttles, *current)
+	}
+
+	slices.SortFunc(throttles, func(a, b ProviderThrottle) int { return strings.Compare(a.Provider, b.Provider) })
+
+	return throttles
+}
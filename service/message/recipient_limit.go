@@ -0,0 +1,102 @@
+package message
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+// RecipientLimit caps the messages a single phone number receives within Window
+// Transactional messages are exempt; a zero Max disables the guard
+type RecipientLimit struct {
+	Max    int
+	Window time.Duration
+	// Reject moves excess messages to throttled instead of deferring them until the window allows another send
+	Reject bool
+}
+
+// Enabled reports whether the guard is active
+func (l RecipientLimit) Enabled() bool {
+	return l.Max > 0
+}
+
+// guardRecipients enforces the recipient limit on claimed messages, in order
+// Returns the messages that may be sent now and the number of throttled ones
+func (s *Service) guardRecipients(ctx context.Context, msgs []*Message) ([]*Message, int, error) {
+	if !s.recipientLimit.Enabled() {
+		return msgs, 0, nil
+	}
+
+	var phoneNumbers []string
+	for _, msg := range msgs {
+		if !msg.Transactional {
+			phoneNumbers = append(phoneNumbers, msg.PhoneNumber)
+		}
+	}
+	if len(phoneNumbers) == 0 {
+		return msgs, 0, nil
+	}
+
+	now := time.Now()
+	dbCounts, err := s.repo.CountSentTo(ctx, phoneNumbers, now.Add(-s.recipientLimit.Window))
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to check recipient limit: %w", err)
+	}
+
+	allowed := make([]*Message, 0, len(msgs))
+	throttled := 0
+	for _, msg := range msgs {
+		if msg.Transactional {
+			allowed = append(allowed, msg)
+			continue
+		}
+
+		count, ok := dbCounts[msg.PhoneNumber]
+		if !ok {
+			count.Oldest = now
+		}
+
+		if count.Count < s.recipientLimit.Max {
+			// Messages allowed earlier in the batch count as sent now
+			count.Count++
+			dbCounts[msg.PhoneNumber] = count
+			allowed = append(allowed, msg)
+			continue
+		}
+
+		if err := s.throttle(ctx, msg, count.Oldest.Add(s.recipientLimit.Window)); err != nil {
+			log.Printf("Error throttling message %d: %v", msg.ID, err)
+			continue
+		}
+		throttled++
+	}
+
+	return allowed, throttled, nil
+}
+
+// throttle defers a message until notBefore, or rejects it when the limit is configured to reject
+func (s *Service) throttle(ctx context.Context, msg *Message, notBefore time.Time) error {
+	var nextAttemptAt *time.Time
+	if s.recipientLimit.Reject {
+		if err := msg.TransitionTo(StatusThrottled); err != nil {
+			return err
+		}
+		log.Printf("⚠ Message %d rejected, %s reached the limit of %d messages per %s",
+			msg.ID, msg.PhoneNumber, s.recipientLimit.Max, s.recipientLimit.Window)
+	} else {
+		if err := msg.TransitionTo(StatusPending); err != nil {
+			return err
+		}
+		nextAttemptAt = &notBefore
+		log.Printf("Message %d deferred until %s, %s reached the limit of %d messages per %s",
+			msg.ID, notBefore.Format(time.RFC3339), msg.PhoneNumber, s.recipientLimit.Max, s.recipientLimit.Window)
+	}
+
+	if err := s.repo.Throttle(ctx, msg.ID, string(msg.Status), nextAttemptAt); err != nil {
+		return err
+	}
+	msg.NextAttemptAt = nextAttemptAt
+
+	return nil
+}
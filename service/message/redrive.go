@@ -0,0 +1,90 @@
+package message
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"qubit/env/postgres/messages"
+)
+
+// redriveBatchSize is the number of archived messages copied per statement, keeping each transaction short
+const redriveBatchSize = 1000
+
+// RedriveFilter selects the archived messages sent again by RedriveArchived
+// Messages processed in [ProcessedFrom, ProcessedTo) are selected, PhoneNumber and CampaignID narrow the selection
+type RedriveFilter struct {
+	ProcessedFrom time.Time
+	ProcessedTo   time.Time
+	PhoneNumber   string
+	CampaignID    *int64
+	// Provider pins the re-driven messages, empty keeps the provider of every original
+	Provider string
+	// CallerRole is checked against the override roles of Provider
+	CallerRole string
+	// DryRun only counts the messages that would be re-driven
+	DryRun bool
+}
+
+// RedriveResult is the outcome of a re-drive
+type RedriveResult struct {
+	// Matched counts the archived messages selected by the filter that were not re-driven before
+	Matched int64
+	// Redriven counts the pending messages created, 0 on a dry run
+	Redriven int64
+	DryRun   bool
+}
+
+// RedriveArchived sends archived messages again, e.g. after a provider silently dropped a day of traffic
+// Every selected message is copied into a new pending message linked to its original; the original stays archived
+// An archived message is re-driven at most once, so an interrupted re-drive is completed by running it again
+// Test messages are never re-driven
+func (s *Service) RedriveArchived(ctx context.Context, f RedriveFilter) (*RedriveResult, error) {
+	if !f.ProcessedTo.After(f.ProcessedFrom) {
+		return nil, fmt.Errorf("%w: processedTo must be after processedFrom", ErrValidation)
+	}
+
+	if f.PhoneNumber != "" && !phoneRegex.MatchString(f.PhoneNumber) {
+		return nil, fmt.Errorf("%w: invalid phone number format (expected: +1234567890)", ErrValidation)
+	}
+
+	pinned, err := s.resolveProvider(CreateOptions{Provider: f.Provider, CallerRole: f.CallerRole})
+	if err != nil {
+		return nil, err
+	}
+
+	filter := messages.RedriveFilter{
+		ProcessedFrom: f.ProcessedFrom,
+		ProcessedTo:   f.ProcessedTo,
+		PhoneNumber:   f.PhoneNumber,
+		CampaignID:    f.CampaignID,
+	}
+
+	matched, err := s.repo.CountRedrivable(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RedriveResult{Matched: matched, DryRun: f.DryRun}
+	if f.DryRun || matched == 0 {
+		return result, nil
+	}
+
+	for {
+		created, err := s.repo.RedriveArchived(ctx, filter, pinned, redriveBatchSize)
+		result.Redriven += created
+		if err != nil {
+			log.Printf("Re-drive of archived messages stopped after %d of %d messages: %v", result.Redriven, matched, err)
+			return nil, err
+		}
+		if created < redriveBatchSize {
+			break
+		}
+	}
+
+	log.Printf("Re-drove %d archived messages processed between %s and %s",
+		result.Redriven, f.ProcessedFrom.Format(time.RFC3339), f.ProcessedTo.Format(time.RFC3339))
+
+	return result, nil
+}
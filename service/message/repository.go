@@ -0,0 +1,314 @@
+package message
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"qubit/env/postgres"
+	"qubit/env/postgres/attempts"
+	"qubit/env/postgres/dispatchruns"
+	"qubit/env/postgres/inbound"
+	"qubit/env/postgres/messages"
+	"qubit/env/postgres/outbox"
+	"qubit/env/postgres/providertemplates"
+	"qubit/env/postgres/templates"
+)
+
+// MessageRepository is the storage the message service runs on
+// It speaks the persistence models of env/postgres and returns their sentinel errors, e.g. messages.ErrNotFound
+type MessageRepository interface {
+	// Messages, see messages.Repository
+	// GetMessage, GetArchivedMessage and CancelMessage only match a message of tenantID unless it is nil
+	GetMessage(ctx context.Context, id int64, tenantID *int64) (*messages.Message, error)
+	GetArchivedMessage(ctx context.Context, id int64, tenantID *int64) (*messages.Message, error)
+	ListSent(ctx context.Context, limit int) ([]*messages.Message, error)
+	ListMessages(ctx context.Context, filter messages.Filter) ([]*messages.Message, error)
+	EachMessage(ctx context.Context, filter messages.Filter, fn func(*messages.Message) error) error
+	ListInFlight(ctx context.Context) ([]*messages.Message, error)
+	ListByFanout(ctx context.Context, fanoutID string) ([]*messages.Message, error)
+	ClaimUnsent(ctx context.Context, limit int, lockedBy string, lease time.Duration, defaultProvider string, skipProviders []string, shards []int) ([]*messages.Message, error)
+	ClaimByID(ctx context.Context, id int64, lockedBy string, lease time.Duration) (*messages.Message, error)
+	Release(ctx context.Context, ids []int64) error
+	ReapStuck(ctx context.Context) (int64, error)
+	CountSentTo(ctx context.Context, phoneNumbers []string, since time.Time) (map[string]messages.RecipientCount, error)
+	CountTenantUsage(ctx context.Context, tenantID int64) (*messages.TenantUsage, error)
+	ExistsByUUID(ctx context.Context, uuid string) (bool, error)
+	Throttle(ctx context.Context, id int64, status string, nextAttemptAt *time.Time) error
+	FindLatestSentTo(ctx context.Context, phoneNumber string, since time.Time) (*messages.Message, error)
+	CancelMessage(ctx context.Context, id int64, tenantID *int64) (*messages.Message, error)
+	MessageStats(ctx context.Context, includeTest bool) (*messages.Stats, error)
+	CountDue(ctx context.Context) (int64, error)
+	CountRedrivable(ctx context.Context, filter messages.RedriveFilter) (int64, error)
+	RedriveArchived(ctx context.Context, filter messages.RedriveFilter, provider *string, limit int) (int64, error)
+
+	// Attempts, see attempts.Repository
+	ListAttempts(ctx context.Context, messageID int64) ([]*attempts.Attempt, error)
+	AttemptStats(ctx context.Context, since time.Time, includeTest bool) (*attempts.Stats, error)
+
+	// Inbound messages, see inbound.Repository
+	CreateInbound(ctx context.Context, msg *inbound.Message) error
+	ListInbound(ctx context.Context, limit int) ([]*inbound.MessageWithReplyTo, error)
+	EachInbound(ctx context.Context, fn func(*inbound.MessageWithReplyTo) error) error
+	ListInboundByReplyTo(ctx context.Context, messageID int64) ([]*inbound.Message, error)
+
+	// Scheduler runs, see dispatchruns.Repository
+	CreateDispatchRun(ctx context.Context, run *dispatchruns.Run) error
+	ListDispatchRuns(ctx context.Context, before int64, limit int) ([]*dispatchruns.Run, error)
+	DeleteDispatchRunsBefore(ctx context.Context, before time.Time) (int64, error)
+
+	// Runtime settings stored as JSON, see settings.Repository
+	GetSetting(ctx context.Context, key string, dest interface{}) (bool, error)
+	SetSetting(ctx context.Context, key string, value interface{}) error
+	DeleteSetting(ctx context.Context, key string) error
+
+	// GetTemplate returns templates.ErrNotFound if the template does not exist
+	GetTemplate(ctx context.Context, id int64) (*templates.Template, error)
+
+	// Templates of template-only providers as last synced, see providertemplates.Repository
+	// GetProviderTemplate returns providertemplates.ErrNotFound if the template does not exist
+	GetProviderTemplate(ctx context.Context, provider, name, language string) (*providertemplates.Template, error)
+	ListProviderTemplates(ctx context.Context) ([]*providertemplates.Template, error)
+
+	// TrySchedulerLock takes the lock serializing scheduler ticks across instances without waiting
+	// release is nil when the lock is held elsewhere
+	TrySchedulerLock(ctx context.Context) (release func(), acquired bool, err error)
+
+	// BeginTx starts a transaction for the writes that must be committed together
+	BeginTx(ctx context.Context) (MessageTx, error)
+}
+
+// MessageTx is a transaction of a MessageRepository
+// Rollback returns pgx.ErrTxClosed once the transaction was committed or rolled back
+type MessageTx interface {
+	CreateMessage(ctx context.Context, msg *messages.Message) error
+	CreateFanout(ctx context.Context, msg *messages.Message, fanoutID string, phoneNumbers []string) ([]*messages.Message, error)
+	UpsertMessage(ctx context.Context, msg *messages.Message) (created bool, err error)
+	// MarkSent and MarkFailed return messages.ErrLeaseLost unless the message is still sending under the claim of lockedBy
+	MarkSent(ctx context.Context, id int64, lockedBy string, messageID *string, processedAt *time.Time) error
+	MarkFailed(ctx context.Context, id int64, lockedBy string, status string, retryCount int, nextAttemptAt *time.Time) error
+	CreateAttempt(ctx context.Context, a *attempts.Attempt) error
+	CreateEvents(ctx context.Context, events []*outbox.Event) error
+
+	// Begin starts a nested transaction, committed or rolled back on its own
+	Begin(ctx context.Context) (MessageTx, error)
+	Commit(ctx context.Context) error
+	Rollback(ctx context.Context) error
+}
+
+// postgresRepository implements MessageRepository on top of the PostgreSQL repositories
+type postgresRepository struct {
+	client *postgres.Client
+}
+
+// NewPostgresRepository creates a MessageRepository backed by PostgreSQL
+func NewPostgresRepository(client *postgres.Client) MessageRepository {
+	return &postgresRepository{client: client}
+}
+
+func (r *postgresRepository) GetMessage(ctx context.Context, id int64, tenantID *int64) (*messages.Message, error) {
+	return r.client.Messages.GetByID(ctx, id, tenantID)
+}
+
+func (r *postgresRepository) GetArchivedMessage(ctx context.Context, id int64, tenantID *int64) (*messages.Message, error) {
+	return r.client.Messages.GetArchivedByID(ctx, id, tenantID)
+}
+
+func (r *postgresRepository) ListSent(ctx context.Context, limit int) ([]*messages.Message, error) {
+	return r.client.Messages.ListSent(ctx, limit)
+}
+
+func (r *postgresRepository) ListMessages(ctx context.Context, filter messages.Filter) ([]*messages.Message, error) {
+	return r.client.Messages.List(ctx, filter)
+}
+
+func (r *postgresRepository) EachMessage(ctx context.Context, filter messages.Filter, fn func(*messages.Message) error) error {
+	return r.client.Messages.Each(ctx, filter, fn)
+}
+
+func (r *postgresRepository) ListInFlight(ctx context.Context) ([]*messages.Message, error) {
+	return r.client.Messages.ListInFlight(ctx)
+}
+
+func (r *postgresRepository) ListByFanout(ctx context.Context, fanoutID string) ([]*messages.Message, error) {
+	return r.client.Messages.ListByFanout(ctx, fanoutID)
+}
+
+func (r *postgresRepository) ClaimUnsent(ctx context.Context, limit int, lockedBy string, lease time.Duration, defaultProvider string, skipProviders []string, shards []int) ([]*messages.Message, error) {
+	return r.client.Messages.ClaimUnsent(ctx, limit, lockedBy, lease, defaultProvider, skipProviders, shards)
+}
+
+func (r *postgresRepository) ClaimByID(ctx context.Context, id int64, lockedBy string, lease time.Duration) (*messages.Message, error) {
+	return r.client.Messages.ClaimByID(ctx, id, lockedBy, lease)
+}
+
+func (r *postgresRepository) Release(ctx context.Context, ids []int64) error {
+	return r.client.Messages.Release(ctx, ids)
+}
+
+func (r *postgresRepository) ReapStuck(ctx context.Context) (int64, error) {
+	return r.client.Messages.ReapStuck(ctx)
+}
+
+func (r *postgresRepository) CountSentTo(ctx context.Context, phoneNumbers []string, since time.Time) (map[string]messages.RecipientCount, error) {
+	return r.client.Messages.CountSentTo(ctx, phoneNumbers, since)
+}
+
+func (r *postgresRepository) CountTenantUsage(ctx context.Context, tenantID int64) (*messages.TenantUsage, error) {
+	return r.client.Messages.CountTenantUsage(ctx, tenantID)
+}
+
+func (r *postgresRepository) ExistsByUUID(ctx context.Context, uuid string) (bool, error) {
+	return r.client.Messages.ExistsByUUID(ctx, uuid)
+}
+
+func (r *postgresRepository) Throttle(ctx context.Context, id int64, status string, nextAttemptAt *time.Time) error {
+	return r.client.Messages.Throttle(ctx, id, status, nextAttemptAt)
+}
+
+func (r *postgresRepository) FindLatestSentTo(ctx context.Context, phoneNumber string, since time.Time) (*messages.Message, error) {
+	return r.client.Messages.FindLatestSentTo(ctx, phoneNumber, since)
+}
+
+func (r *postgresRepository) CancelMessage(ctx context.Context, id int64, tenantID *int64) (*messages.Message, error) {
+	return r.client.Messages.Cancel(ctx, id, tenantID)
+}
+
+func (r *postgresRepository) MessageStats(ctx context.Context, includeTest bool) (*messages.Stats, error) {
+	return r.client.Messages.Stats(ctx, includeTest)
+}
+
+func (r *postgresRepository) CountDue(ctx context.Context) (int64, error) {
+	return r.client.Messages.CountDue(ctx)
+}
+
+func (r *postgresRepository) CountRedrivable(ctx context.Context, filter messages.RedriveFilter) (int64, error) {
+	return r.client.Messages.CountRedrivable(ctx, filter)
+}
+
+func (r *postgresRepository) RedriveArchived(ctx context.Context, filter messages.RedriveFilter, provider *string, limit int) (int64, error) {
+	return r.client.Messages.RedriveArchived(ctx, filter, provider, limit)
+}
+
+func (r *postgresRepository) ListAttempts(ctx context.Context, messageID int64) ([]*attempts.Attempt, error) {
+	return r.client.Attempts.ListByMessage(ctx, messageID)
+}
+
+func (r *postgresRepository) AttemptStats(ctx context.Context, since time.Time, includeTest bool) (*attempts.Stats, error) {
+	return r.client.Attempts.Stats(ctx, since, includeTest)
+}
+
+func (r *postgresRepository) CreateInbound(ctx context.Context, msg *inbound.Message) error {
+	return r.client.Inbound.Create(ctx, msg)
+}
+
+func (r *postgresRepository) ListInbound(ctx context.Context, limit int) ([]*inbound.MessageWithReplyTo, error) {
+	return r.client.Inbound.List(ctx, limit)
+}
+
+func (r *postgresRepository) EachInbound(ctx context.Context, fn func(*inbound.MessageWithReplyTo) error) error {
+	return r.client.Inbound.Each(ctx, fn)
+}
+
+func (r *postgresRepository) ListInboundByReplyTo(ctx context.Context, messageID int64) ([]*inbound.Message, error) {
+	return r.client.Inbound.ListByReplyTo(ctx, messageID)
+}
+
+func (r *postgresRepository) CreateDispatchRun(ctx context.Context, run *dispatchruns.Run) error {
+	return r.client.DispatchRuns.Create(ctx, run)
+}
+
+func (r *postgresRepository) ListDispatchRuns(ctx context.Context, before int64, limit int) ([]*dispatchruns.Run, error) {
+	return r.client.DispatchRuns.List(ctx, before, limit)
+}
+
+func (r *postgresRepository) DeleteDispatchRunsBefore(ctx context.Context, before time.Time) (int64, error) {
+	return r.client.DispatchRuns.DeleteBefore(ctx, before)
+}
+
+func (r *postgresRepository) GetSetting(ctx context.Context, key string, dest interface{}) (bool, error) {
+	return r.client.Settings.Get(ctx, key, dest)
+}
+
+func (r *postgresRepository) SetSetting(ctx context.Context, key string, value interface{}) error {
+	return r.client.Settings.Set(ctx, key, value)
+}
+
+func (r *postgresRepository) DeleteSetting(ctx context.Context, key string) error {
+	return r.client.Settings.Delete(ctx, key)
+}
+
+func (r *postgresRepository) GetTemplate(ctx context.Context, id int64) (*templates.Template, error) {
+	return r.client.Templates.GetByID(ctx, id)
+}
+
+func (r *postgresRepository) GetProviderTemplate(ctx context.Context, provider, name, language string) (*providertemplates.Template, error) {
+	return r.client.ProviderTemplates.Get(ctx, provider, name, language)
+}
+
+func (r *postgresRepository) ListProviderTemplates(ctx context.Context) ([]*providertemplates.Template, error) {
+	return r.client.ProviderTemplates.List(ctx)
+}
+
+func (r *postgresRepository) TrySchedulerLock(ctx context.Context) (func(), bool, error) {
+	return r.client.TryLock(ctx, postgres.SchedulerLockKey)
+}
+
+func (r *postgresRepository) BeginTx(ctx context.Context) (MessageTx, error) {
+	tx, err := r.client.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &postgresTx{client: r.client, tx: tx}, nil
+}
+
+// postgresTx implements MessageTx on top of a pgx transaction, nested transactions are savepoints
+type postgresTx struct {
+	client *postgres.Client
+	tx     pgx.Tx
+}
+
+func (t *postgresTx) CreateMessage(ctx context.Context, msg *messages.Message) error {
+	return t.client.Messages.CreateWithTx(ctx, t.tx, msg)
+}
+
+func (t *postgresTx) CreateFanout(ctx context.Context, msg *messages.Message, fanoutID string, phoneNumbers []string) ([]*messages.Message, error) {
+	return t.client.Messages.CreateFanoutWithTx(ctx, t.tx, msg, fanoutID, phoneNumbers)
+}
+
+func (t *postgresTx) UpsertMessage(ctx context.Context, msg *messages.Message) (bool, error) {
+	return t.client.Messages.UpsertWithTx(ctx, t.tx, msg)
+}
+
+func (t *postgresTx) MarkSent(ctx context.Context, id int64, lockedBy string, messageID *string, processedAt *time.Time) error {
+	return t.client.Messages.UpdateWithTx(ctx, t.tx, id, lockedBy, messageID, processedAt)
+}
+
+func (t *postgresTx) MarkFailed(ctx context.Context, id int64, lockedBy string, status string, retryCount int, nextAttemptAt *time.Time) error {
+	return t.client.Messages.MarkFailedWithTx(ctx, t.tx, id, lockedBy, status, retryCount, nextAttemptAt)
+}
+
+func (t *postgresTx) CreateAttempt(ctx context.Context, a *attempts.Attempt) error {
+	return t.client.Attempts.CreateWithTx(ctx, t.tx, a)
+}
+
+func (t *postgresTx) CreateEvents(ctx context.Context, events []*outbox.Event) error {
+	return t.client.Outbox.CreateWithTx(ctx, t.tx, events)
+}
+
+func (t *postgresTx) Begin(ctx context.Context) (MessageTx, error) {
+	savepoint, err := t.tx.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &postgresTx{client: t.client, tx: savepoint}, nil
+}
+
+func (t *postgresTx) Commit(ctx context.Context) error {
+	return t.tx.Commit(ctx)
+}
+
+func (t *postgresTx) Rollback(ctx context.Context) error {
+	return t.tx.Rollback(ctx)
+}
@@ -0,0 +1,144 @@
+package message
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// Backoff selects how the retry delay grows between attempts
+type Backoff string
+
+// Backoff strategies
+const (
+	BackoffExponential Backoff = "exponential"
+	BackoffLinear      Backoff = "linear"
+	BackoffFixed       Backoff = "fixed"
+)
+
+// Caps for per-message retry policies
+const (
+	MaxPolicyAttempts = 20
+	MaxPolicyDelay    = 24 * time.Hour
+)
+
+// RetryPolicy describes how failed sends are retried
+// MaxRetries is the number of attempts allowed after the first failed one
+// An empty Backoff is exponential
+type RetryPolicy struct {
+	MaxRetries int
+	Backoff    Backoff
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+}
+
+// RetryOverride is a per-message retry policy requested on creation
+// Zero fields fall back to the configured policy
+type RetryOverride struct {
+	MaxAttempts int
+	Backoff     Backoff
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// CanRetry reports whether another attempt is allowed after retryCount failures
+func (p RetryPolicy) CanRetry(retryCount int) bool {
+	return retryCount <= p.MaxRetries
+}
+
+// NextDelay returns the backoff delay before the next attempt
+// The delay grows with every failure according to Backoff, is capped at MaxDelay and has jitter applied
+func (p RetryPolicy) NextDelay(retryCount int) time.Duration {
+	delay := p.BaseDelay
+	switch p.Backoff {
+	case BackoffFixed:
+	case BackoffLinear:
+		if retryCount > 1 {
+			delay = p.BaseDelay * time.Duration(retryCount)
+		}
+	default:
+		for i := 1; i < retryCount && delay < p.MaxDelay; i++ {
+			delay *= 2
+		}
+	}
+
+	if delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+
+	if delay <= 0 {
+		return 0
+	}
+
+	// Equal jitter: random delay in [delay/2, delay]
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(delay-half)+1))
+}
+
+// Validate checks a per-message retry policy against the caps
+func (p RetryPolicy) Validate() error {
+	if p.MaxRetries < 0 || p.MaxRetries+1 > MaxPolicyAttempts {
+		return fmt.Errorf("retry policy maxAttempts must be between 1 and %d", MaxPolicyAttempts)
+	}
+
+	switch p.Backoff {
+	case BackoffExponential, BackoffLinear, BackoffFixed:
+	default:
+		return fmt.Errorf("retry policy backoff must be exponential, linear or fixed")
+	}
+
+	if p.BaseDelay < time.Second {
+		return fmt.Errorf("retry policy base delay must be at least 1 second")
+	}
+
+	if p.MaxDelay < p.BaseDelay || p.MaxDelay > MaxPolicyDelay {
+		return fmt.Errorf("retry policy max delay must be between the base delay and %s", MaxPolicyDelay)
+	}
+
+	return nil
+}
+
+// resolveRetryPolicy returns the retry policy stored with a new message, nil uses the configured one
+func (s *Service) resolveRetryPolicy(opts CreateOptions) (*RetryPolicy, error) {
+	if opts.Retry == nil {
+		return nil, nil
+	}
+
+	policy := RetryPolicy{
+		MaxRetries: s.retryPolicy.MaxRetries,
+		Backoff:    opts.Retry.Backoff,
+		BaseDelay:  s.retryPolicy.BaseDelay,
+		MaxDelay:   s.retryPolicy.MaxDelay,
+	}
+	if opts.Retry.MaxAttempts > 0 {
+		policy.MaxRetries = opts.Retry.MaxAttempts - 1
+	}
+	if policy.Backoff == "" {
+		policy.Backoff = BackoffExponential
+	}
+	if opts.Retry.BaseDelay > 0 {
+		policy.BaseDelay = opts.Retry.BaseDelay
+	}
+	if opts.Retry.MaxDelay > 0 {
+		policy.MaxDelay = opts.Retry.MaxDelay
+	}
+
+	// A base delay above the configured cap raises the cap unless one was requested
+	if opts.Retry.MaxDelay == 0 && policy.MaxDelay < policy.BaseDelay {
+		policy.MaxDelay = policy.BaseDelay
+	}
+
+	if err := policy.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrValidation, err)
+	}
+
+	return &policy, nil
+}
+
+// retryPolicyFor returns the policy a message is retried with
+func (s *Service) retryPolicyFor(msg *Message) RetryPolicy {
+	if msg.RetryPolicy != nil {
+		return *msg.RetryPolicy
+	}
+	return s.retryPolicy
+}
@@ -0,0 +1,120 @@
+package message
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyNextDelay(t *testing.T) {
+	tests := []struct {
+		name       string
+		policy     RetryPolicy
+		retryCount int
+		want       time.Duration // delay before jitter, the result is in [want/2, want]
+	}{
+		{"exponential first retry", RetryPolicy{Backoff: BackoffExponential, BaseDelay: time.Minute, MaxDelay: time.Hour}, 1, time.Minute},
+		{"exponential doubles", RetryPolicy{Backoff: BackoffExponential, BaseDelay: time.Minute, MaxDelay: time.Hour}, 3, 4 * time.Minute},
+		{"exponential capped", RetryPolicy{Backoff: BackoffExponential, BaseDelay: time.Minute, MaxDelay: 5 * time.Minute}, 10, 5 * time.Minute},
+		{"empty backoff is exponential", RetryPolicy{BaseDelay: time.Second, MaxDelay: time.Hour}, 4, 8 * time.Second},
+		{"linear first retry", RetryPolicy{Backoff: BackoffLinear, BaseDelay: time.Minute, MaxDelay: time.Hour}, 1, time.Minute},
+		{"linear grows by the base delay", RetryPolicy{Backoff: BackoffLinear, BaseDelay: time.Minute, MaxDelay: time.Hour}, 3, 3 * time.Minute},
+		{"linear capped", RetryPolicy{Backoff: BackoffLinear, BaseDelay: time.Minute, MaxDelay: 2 * time.Minute}, 5, 2 * time.Minute},
+		{"fixed", RetryPolicy{Backoff: BackoffFixed, BaseDelay: time.Minute, MaxDelay: time.Hour}, 7, time.Minute},
+		{"no delay", RetryPolicy{Backoff: BackoffFixed}, 2, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for range 20 {
+				got := tt.policy.NextDelay(tt.retryCount)
+				if got < tt.want/2 || got > tt.want {
+					t.Fatalf("NextDelay(%d) = %s, want between %s and %s", tt.retryCount, got, tt.want/2, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryPolicyCanRetry(t *testing.T) {
+	policy := RetryPolicy{MaxRetries: 2}
+
+	for retryCount, want := range []bool{true, true, true, false} {
+		if got := policy.CanRetry(retryCount); got != want {
+			t.Errorf("CanRetry(%d) = %v, want %v", retryCount, got, want)
+		}
+	}
+}
+
+func TestRetryPolicyValidate(t *testing.T) {
+	valid := RetryPolicy{MaxRetries: 3, Backoff: BackoffLinear, BaseDelay: time.Second, MaxDelay: time.Minute}
+
+	tests := []struct {
+		name    string
+		modify  func(p *RetryPolicy)
+		wantErr bool
+	}{
+		{"valid", func(p *RetryPolicy) {}, false},
+		{"negative retries", func(p *RetryPolicy) { p.MaxRetries = -1 }, true},
+		{"too many attempts", func(p *RetryPolicy) { p.MaxRetries = MaxPolicyAttempts }, true},
+		{"unknown backoff", func(p *RetryPolicy) { p.Backoff = "random" }, true},
+		{"base delay under a second", func(p *RetryPolicy) { p.BaseDelay = 500 * time.Millisecond }, true},
+		{"max delay under the base delay", func(p *RetryPolicy) { p.MaxDelay = p.BaseDelay / 2 }, true},
+		{"max delay over the cap", func(p *RetryPolicy) { p.MaxDelay = MaxPolicyDelay + time.Second }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := valid
+			tt.modify(&policy)
+			if err := policy.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestResolveRetryPolicy(t *testing.T) {
+	s := &Service{retryPolicy: RetryPolicy{MaxRetries: 3, Backoff: BackoffExponential, BaseDelay: 30 * time.Second, MaxDelay: 10 * time.Minute}}
+
+	tests := []struct {
+		name     string
+		override *RetryOverride
+		want     *RetryPolicy
+		wantErr  error
+	}{
+		{"no override uses the configured policy", nil, nil, nil},
+		{
+			"zero fields fall back",
+			&RetryOverride{Backoff: BackoffFixed},
+			&RetryPolicy{MaxRetries: 3, Backoff: BackoffFixed, BaseDelay: 30 * time.Second, MaxDelay: 10 * time.Minute},
+			nil,
+		},
+		{
+			"attempts include the first one",
+			&RetryOverride{MaxAttempts: 5},
+			&RetryPolicy{MaxRetries: 4, Backoff: BackoffExponential, BaseDelay: 30 * time.Second, MaxDelay: 10 * time.Minute},
+			nil,
+		},
+		{
+			"base delay above the cap raises it",
+			&RetryOverride{BaseDelay: time.Hour},
+			&RetryPolicy{MaxRetries: 3, Backoff: BackoffExponential, BaseDelay: time.Hour, MaxDelay: time.Hour},
+			nil,
+		},
+		{"requested cap under the base delay", &RetryOverride{BaseDelay: time.Hour, MaxDelay: time.Minute}, nil, ErrValidation},
+		{"too many attempts", &RetryOverride{MaxAttempts: MaxPolicyAttempts + 1}, nil, ErrValidation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.resolveRetryPolicy(CreateOptions{Retry: tt.override})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("resolveRetryPolicy() error = %v, want %v", err, tt.wantErr)
+			}
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Errorf("resolveRetryPolicy() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
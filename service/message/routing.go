@@ -0,0 +1,122 @@
+package message
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Reasons a provider is chosen for a message
+const (
+	RouteDefault = "default" // no pin, the default provider
+	RoutePinned  = "pinned"  // pinned by the caller
+	RouteSandbox = "sandbox" // a test message, pinned to the sandbox provider
+)
+
+// RoutingDecision explains how a message to a phone number would be dispatched right now
+// It answers why a message went through a provider or waited, without creating one
+type RoutingDecision struct {
+	PhoneNumber string
+	Provider    string
+	// Reason is why the provider was chosen, one of the Route constants
+	Reason string
+	// CircuitOpen is set while the circuit breaker of the provider keeps its messages pending
+	CircuitOpen bool
+	// ThrottledUntil is when the provider's Retry-After throttle ends, nil when it is not throttled
+	ThrottledUntil *time.Time
+	// SendRatePerSecond is the pace of the webhook calls of this instance, 0 when unlimited
+	SendRatePerSecond int
+	// RecipientLimit is the per-recipient bucket of the phone number, nil when the limit is disabled
+	RecipientLimit *RecipientBucket
+	// SchedulerPaused is set while an operator paused the scheduler
+	SchedulerPaused bool
+}
+
+// RecipientBucket is the state of the per-recipient limit for a phone number
+type RecipientBucket struct {
+	Max    int
+	Window time.Duration
+	// Sent counts the messages sent to the phone number within the window
+	Sent int
+	// Exempt is set for transactional messages, which the limit does not apply to
+	Exempt bool
+	// Action is what happens to a message over the limit: defer or reject
+	Action string
+	// AllowedAt is when the window allows the next send, nil when a message may be sent now
+	AllowedAt *time.Time
+}
+
+// SimulateRouting returns how a message to phoneNumber created with opts would be dispatched
+// Provider pins are checked like on creation, so a pin the caller may not use fails the same way
+func (s *Service) SimulateRouting(ctx context.Context, phoneNumber string, opts CreateOptions) (*RoutingDecision, error) {
+	if !phoneRegex.MatchString(phoneNumber) {
+		return nil, fmt.Errorf("%w: invalid phone number format (expected: +1234567890)", ErrValidation)
+	}
+
+	pinned, err := s.resolveProvider(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	decision := &RoutingDecision{
+		PhoneNumber:     phoneNumber,
+		Provider:        s.providers.DefaultName(),
+		Reason:          RouteDefault,
+		SchedulerPaused: s.paused.Load() != nil,
+	}
+	if pinned != nil {
+		decision.Provider = *pinned
+		decision.Reason = RoutePinned
+		if opts.Provider == "" {
+			decision.Reason = RouteSandbox
+		}
+	}
+
+	decision.CircuitOpen = slices.Contains(s.providers.OpenCircuits(), decision.Provider)
+	for _, throttle := range s.throttles.active() {
+		if throttle.Provider == decision.Provider {
+			decision.ThrottledUntil = &throttle.Until
+		}
+	}
+
+	if s.sendPacer != nil {
+		decision.SendRatePerSecond = int(time.Second / s.sendPacer.interval)
+	}
+
+	if s.recipientLimit.Enabled() {
+		decision.RecipientLimit, err = s.recipientBucket(ctx, phoneNumber, opts.Transactional)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return decision, nil
+}
+
+// recipientBucket counts the recent sends to a phone number against the recipient limit
+func (s *Service) recipientBucket(ctx context.Context, phoneNumber string, transactional bool) (*RecipientBucket, error) {
+	bucket := &RecipientBucket{
+		Max:    s.recipientLimit.Max,
+		Window: s.recipientLimit.Window,
+		Exempt: transactional,
+		Action: "defer",
+	}
+	if s.recipientLimit.Reject {
+		bucket.Action = "reject"
+	}
+
+	counts, err := s.repo.CountSentTo(ctx, []string{phoneNumber}, time.Now().Add(-s.recipientLimit.Window))
+	if err != nil {
+		return nil, fmt.Errorf("failed to check recipient limit: %w", err)
+	}
+
+	count := counts[phoneNumber]
+	bucket.Sent = count.Count
+	if !transactional && count.Count >= s.recipientLimit.Max {
+		allowedAt := count.Oldest.Add(s.recipientLimit.Window)
+		bucket.AllowedAt = &allowedAt
+	}
+
+	return bucket, nil
+}
@@ -0,0 +1,124 @@
+package message
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"qubit/env/postgres/dispatchruns"
+)
+
+// Run listing limits
+const (
+	DefaultRunLimit = 50
+	MaxRunLimit     = 500
+)
+
+// Run is the report of a scheduler run on one instance
+// Error is set when the run ended early, Result then counts the messages handled before
+type Run struct {
+	ID         int64
+	InstanceID string
+	StartedAt  time.Time
+	FinishedAt time.Time
+	Result     BatchResult
+	Error      *string
+}
+
+// RunPage is a page of scheduler runs, newest first
+// NextBefore continues the listing with the following page, nil on the last page
+type RunPage struct {
+	Runs       []*Run
+	NextBefore *int64
+}
+
+// ListRuns returns up to limit scheduler runs of every instance, newest first
+// A before greater than 0 continues a listing with the runs older than the run with that ID
+func (s *Service) ListRuns(ctx context.Context, before int64, limit int) (*RunPage, error) {
+	if limit <= 0 {
+		limit = DefaultRunLimit
+	}
+	if limit > MaxRunLimit {
+		return nil, fmt.Errorf("%w: limit must be at most %d", ErrValidation, MaxRunLimit)
+	}
+
+	dbRuns, err := s.repo.ListDispatchRuns(ctx, before, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scheduler runs: %w", err)
+	}
+
+	page := &RunPage{Runs: make([]*Run, 0, len(dbRuns))}
+	for _, dbRun := range dbRuns {
+		page.Runs = append(page.Runs, runToDomain(dbRun))
+	}
+	if len(dbRuns) == limit {
+		next := dbRuns[len(dbRuns)-1].ID
+		page.NextBefore = &next
+	}
+
+	return page, nil
+}
+
+// recordRun stores the report of a scheduler run and prunes the reports past the retention
+// Failures are only logged, a lost report must not fail the run
+func (s *Service) recordRun(batchCtx context.Context, started time.Time, result *BatchResult, runErr error) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(batchCtx), persistTimeout)
+	defer cancel()
+
+	if result == nil {
+		result = &BatchResult{}
+	}
+
+	dbRun := &dispatchruns.Run{
+		InstanceID:  s.instanceID,
+		StartedAt:   started,
+		FinishedAt:  time.Now(),
+		Claimed:     result.Claimed,
+		Attempted:   result.Attempted(),
+		Sent:        result.Sent,
+		Retried:     result.Retried,
+		Failed:      result.Failed,
+		Throttled:   result.Throttled,
+		Deferred:    result.Deferred,
+		Quarantined: result.Quarantined,
+		Errors:      result.Errors,
+	}
+	if runErr != nil {
+		errMsg := runErr.Error()
+		dbRun.Error = &errMsg
+	}
+
+	if err := s.repo.CreateDispatchRun(ctx, dbRun); err != nil {
+		log.Printf("Warning: failed to record scheduler run: %v", err)
+	}
+
+	if s.runRetention <= 0 {
+		return
+	}
+	if _, err := s.repo.DeleteDispatchRunsBefore(ctx, time.Now().Add(-s.runRetention)); err != nil {
+		log.Printf("Warning: failed to prune scheduler runs: %v", err)
+	}
+}
+
+// runToDomain converts a stored scheduler run to its domain form
+func runToDomain(dbRun *dispatchruns.Run) *Run {
+	return &Run{
+		ID:         dbRun.ID,
+		InstanceID: dbRun.InstanceID,
+		StartedAt:  dbRun.StartedAt,
+		FinishedAt: dbRun.FinishedAt,
+		Result: BatchResult{
+			Claimed:     dbRun.Claimed,
+			Sent:        dbRun.Sent,
+			Retried:     dbRun.Retried,
+			Failed:      dbRun.Failed,
+			Throttled:   dbRun.Throttled,
+			Deferred:    dbRun.Deferred,
+			Quarantined: dbRun.Quarantined,
+			Duration:    dbRun.FinishedAt.Sub(dbRun.StartedAt),
+			Errors:      dbRun.Errors,
+		},
+		Error: dbRun.Error,
+	}
+}
@@ -0,0 +1,83 @@
+package message
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+// schedulerPauseKey is the settings key holding the pause shared by all instances
+const schedulerPauseKey = "scheduler_paused"
+
+// SchedulerPause describes an operator pause of the scheduler
+type SchedulerPause struct {
+	Reason     string    `json:"reason,omitempty"`
+	Since      time.Time `json:"since"`
+	InstanceID string    `json:"instanceId"`
+}
+
+// PauseScheduler pauses message processing on every instance until ResumeScheduler is called
+// Unlike StopScheduler, the pause is persisted, so a restarted instance stays paused
+// Pausing again only updates the reason, keeping the original start
+func (s *Service) PauseScheduler(ctx context.Context, reason string) (SchedulerPause, error) {
+	pause := SchedulerPause{Reason: reason, Since: time.Now(), InstanceID: s.instanceID}
+	if current := s.paused.Load(); current != nil {
+		pause.Since = current.Since
+		pause.InstanceID = current.InstanceID
+	}
+
+	if err := s.repo.SetSetting(ctx, schedulerPauseKey, pause); err != nil {
+		return SchedulerPause{}, fmt.Errorf("failed to persist scheduler pause: %w", err)
+	}
+
+	s.applyPause(&pause)
+
+	return pause, nil
+}
+
+// ResumeScheduler lifts the pause on every instance; instances pick it up on their next tick
+func (s *Service) ResumeScheduler(ctx context.Context) error {
+	if err := s.repo.DeleteSetting(ctx, schedulerPauseKey); err != nil {
+		return fmt.Errorf("failed to clear scheduler pause: %w", err)
+	}
+
+	s.applyPause(nil)
+
+	return nil
+}
+
+// SchedulerPaused returns the pause of the scheduler, nil when it is not paused
+func (s *Service) SchedulerPaused() *SchedulerPause {
+	return s.paused.Load()
+}
+
+// syncSchedulerPause reloads the pause persisted by any instance
+// The last known pause is kept when the database cannot be read
+func (s *Service) syncSchedulerPause(ctx context.Context) {
+	var pause SchedulerPause
+	found, err := s.repo.GetSetting(ctx, schedulerPauseKey, &pause)
+	if err != nil {
+		log.Printf("Warning: failed to load scheduler pause: %v", err)
+		return
+	}
+
+	if !found {
+		s.applyPause(nil)
+		return
+	}
+
+	s.applyPause(&pause)
+}
+
+// applyPause switches to pause, logging transitions
+func (s *Service) applyPause(pause *SchedulerPause) {
+	previous := s.paused.Swap(pause)
+
+	switch {
+	case pause != nil && previous == nil:
+		log.Printf("Scheduler paused since %s, skipping batches until resumed: %q", pause.Since.Format(time.RFC3339), pause.Reason)
+	case pause == nil && previous != nil:
+		log.Println("Scheduler resumed")
+	}
+}
@@ -0,0 +1,262 @@
+package message
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"qubit/pkg/scheduler"
+	"qubit/service/leader"
+	"qubit/service/shard"
+)
+
+// schedulerSettingsKey is the settings key holding runtime scheduler overrides
+const schedulerSettingsKey = "scheduler"
+
+// SchedulerSettings holds the parameters the scheduler runs with
+// A cron expression takes precedence over the interval
+type SchedulerSettings struct {
+	Interval  time.Duration
+	BatchSize int
+	Cron      string
+}
+
+// persistedSchedulerSettings is the stored form of SchedulerSettings
+// IntervalMinutes is only read, overrides stored before sub-minute intervals have no interval
+type persistedSchedulerSettings struct {
+	Interval        string `json:"interval,omitempty"`
+	IntervalMinutes int    `json:"intervalMinutes,omitempty"`
+	BatchSize       int    `json:"batchSize"`
+	Cron            string `json:"cron,omitempty"`
+}
+
+// MarshalJSON stores the interval as a Go duration string
+func (s SchedulerSettings) MarshalJSON() ([]byte, error) {
+	return json.Marshal(persistedSchedulerSettings{
+		Interval:  s.Interval.String(),
+		BatchSize: s.BatchSize,
+		Cron:      s.Cron,
+	})
+}
+
+// UnmarshalJSON reads both the duration string and the legacy whole-minute interval
+func (s *SchedulerSettings) UnmarshalJSON(data []byte) error {
+	var persisted persistedSchedulerSettings
+	if err := json.Unmarshal(data, &persisted); err != nil {
+		return err
+	}
+
+	interval := time.Duration(persisted.IntervalMinutes) * time.Minute
+	if persisted.Interval != "" {
+		parsed, err := time.ParseDuration(persisted.Interval)
+		if err != nil {
+			return fmt.Errorf("invalid scheduler interval: %w", err)
+		}
+		interval = parsed
+	}
+
+	*s = SchedulerSettings{
+		Interval:  interval,
+		BatchSize: persisted.BatchSize,
+		Cron:      persisted.Cron,
+	}
+	return nil
+}
+
+// SchedulerStatus describes whether the scheduler runs and when it runs next
+// Standby is set while the last tick was skipped because another instance held the tick lock
+// or while another instance is the elected leader
+type SchedulerStatus struct {
+	Running  bool
+	Settings SchedulerSettings
+	Schedule string
+	NextRun  *time.Time
+	// Heartbeat is when the scheduler loop last ticked or scheduled its next run, nil before the first start
+	Heartbeat *time.Time
+	Standby   bool
+	// Leadership is the leader election state, nil when leader election is disabled
+	Leadership *leader.Status
+	// Sharding is the shard assignment, nil when sharding is disabled
+	Sharding *shard.Status
+	// Paused is the operator pause, nil when the scheduler is not paused
+	Paused *SchedulerPause
+	// Throttled lists the providers that asked this instance to back off, empty when none did
+	Throttled []ProviderThrottle
+}
+
+// Validate checks if the scheduler settings are valid
+func (s SchedulerSettings) Validate() error {
+	if s.Interval < scheduler.MinInterval {
+		return fmt.Errorf("interval must be at least %s", scheduler.MinInterval)
+	}
+
+	if s.BatchSize <= 0 {
+		return fmt.Errorf("batch size must be greater than 0")
+	}
+
+	if s.Cron != "" {
+		if _, err := s.schedule(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// schedule returns the scheduler schedule of the settings
+func (s SchedulerSettings) schedule() (scheduler.Schedule, error) {
+	if s.Cron == "" {
+		return scheduler.Every(s.Interval), nil
+	}
+
+	cron, err := scheduler.ParseCron(s.Cron)
+	if err != nil {
+		return nil, err
+	}
+
+	if cron.Next(time.Now()).IsZero() {
+		return nil, fmt.Errorf("cron expression %q never matches", s.Cron)
+	}
+
+	return cron, nil
+}
+
+// SchedulerSettings returns the settings the scheduler currently runs with
+func (s *Service) SchedulerSettings() SchedulerSettings {
+	return *s.settings.Load()
+}
+
+// SchedulerStatus returns whether the scheduler runs, its settings and its next run
+func (s *Service) SchedulerStatus() SchedulerStatus {
+	status := SchedulerStatus{
+		Running:   s.scheduler.Running(),
+		Settings:  s.SchedulerSettings(),
+		NextRun:   s.scheduler.NextRun(),
+		Heartbeat: s.scheduler.Heartbeat(),
+		Standby:   s.standby.Load(),
+		Paused:    s.paused.Load(),
+		Throttled: s.throttles.active(),
+	}
+
+	if s.leadership != nil {
+		leadership := s.leadership.Status()
+		status.Leadership = &leadership
+		status.Standby = status.Standby || !leadership.Leader
+	}
+
+	if s.sharding != nil {
+		sharding := s.sharding.Status()
+		status.Sharding = &sharding
+	}
+
+	if schedule := s.scheduler.Schedule(); schedule != nil {
+		status.Schedule = schedule.String()
+	}
+
+	return status
+}
+
+// SchedulerStalled reports whether the running scheduler loop is overdue by more than timeout
+// A loop that died or hangs in a tick stalls, a standby instance skipping its ticks does not
+func (s *Service) SchedulerStalled(timeout time.Duration) bool {
+	return s.scheduler.Stalled(timeout)
+}
+
+// BatchLockHeld returns how long the batch in progress on this instance has held the processing lock, zero without one
+func (s *Service) BatchLockHeld() time.Duration {
+	return s.mu.Held()
+}
+
+// DefaultSchedulerSettings returns the settings from configuration, ignoring runtime overrides
+func (s *Service) DefaultSchedulerSettings() SchedulerSettings {
+	return *s.defaults.Load()
+}
+
+// ReloadSchedulerDefaults replaces the settings from configuration, e.g. after a configuration reload
+// A running scheduler without runtime overrides restarts with them; with overrides they apply once the scheduler is reset
+// It reports whether the scheduler restarted with the new defaults
+func (s *Service) ReloadSchedulerDefaults(ctx context.Context, defaults SchedulerSettings) (bool, error) {
+	if err := defaults.Validate(); err != nil {
+		return false, fmt.Errorf("%w: %v", ErrValidation, err)
+	}
+
+	s.schedulerMu.Lock()
+	defer s.schedulerMu.Unlock()
+
+	var overrides SchedulerSettings
+	overridden, err := s.repo.GetSetting(ctx, schedulerSettingsKey, &overrides)
+	if err != nil {
+		return false, fmt.Errorf("failed to load scheduler overrides: %w", err)
+	}
+
+	previous := s.defaults.Swap(&defaults)
+
+	if overridden || !s.scheduler.Running() || s.SchedulerSettings() == defaults {
+		return false, nil
+	}
+
+	// A failed restart keeps the previous defaults, so a configuration reload applies completely or not at all
+	if err := s.restartScheduler(defaults); err != nil {
+		s.defaults.Store(previous)
+		return false, err
+	}
+
+	log.Printf("✓ Scheduler restarted with reloaded defaults (interval: %s, cron: %q, batch size: %d)", defaults.Interval, defaults.Cron, defaults.BatchSize)
+
+	return true, nil
+}
+
+// loadSchedulerOverrides returns the persisted runtime overrides, or the defaults if there are none
+func (s *Service) loadSchedulerOverrides() SchedulerSettings {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var overrides SchedulerSettings
+	found, err := s.repo.GetSetting(ctx, schedulerSettingsKey, &overrides)
+	if err != nil {
+		log.Printf("Warning: failed to load scheduler overrides, using defaults: %v", err)
+		return s.DefaultSchedulerSettings()
+	}
+
+	if !found {
+		return s.DefaultSchedulerSettings()
+	}
+
+	if err := overrides.Validate(); err != nil {
+		log.Printf("Warning: ignoring invalid persisted scheduler overrides: %v", err)
+		return s.DefaultSchedulerSettings()
+	}
+
+	log.Printf("✓ Loaded persisted scheduler overrides (interval: %s, cron: %q, batch size: %d)", overrides.Interval, overrides.Cron, overrides.BatchSize)
+
+	return overrides
+}
+
+// persistSchedulerOverrides stores settings as runtime overrides
+// Settings equal to the defaults clear the overrides instead
+func (s *Service) persistSchedulerOverrides(ctx context.Context, settings SchedulerSettings) error {
+	if settings == s.DefaultSchedulerSettings() {
+		return s.repo.DeleteSetting(ctx, schedulerSettingsKey)
+	}
+
+	return s.repo.SetSetting(ctx, schedulerSettingsKey, settings)
+}
+
+// ResetScheduler drops the persisted runtime overrides and restarts the scheduler with the defaults
+func (s *Service) ResetScheduler(ctx context.Context) (SchedulerSettings, error) {
+	s.schedulerMu.Lock()
+	defer s.schedulerMu.Unlock()
+
+	if err := s.repo.DeleteSetting(ctx, schedulerSettingsKey); err != nil {
+		return SchedulerSettings{}, fmt.Errorf("failed to reset scheduler overrides: %w", err)
+	}
+
+	defaults := s.DefaultSchedulerSettings()
+	if err := s.restartScheduler(defaults); err != nil {
+		return SchedulerSettings{}, err
+	}
+
+	return defaults, nil
+}
@@ -0,0 +1,85 @@
+package message
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestSchedulerSettingsJSONRoundTrip(t *testing.T) {
+	tests := []SchedulerSettings{
+		{Interval: 30 * time.Second, BatchSize: 50},
+		{Interval: 90 * time.Second, BatchSize: 10},
+		{Interval: 2 * time.Minute, BatchSize: 100, Cron: "*/15 * * * * *"},
+	}
+
+	for _, want := range tests {
+		t.Run(want.Interval.String(), func(t *testing.T) {
+			data, err := json.Marshal(want)
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+
+			var got SchedulerSettings
+			if err := json.Unmarshal(data, &got); err != nil {
+				t.Fatalf("Unmarshal(%s) error = %v", data, err)
+			}
+			if got != want {
+				t.Errorf("round trip of %+v = %+v", want, got)
+			}
+		})
+	}
+}
+
+func TestSchedulerSettingsUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    SchedulerSettings
+		wantErr bool
+	}{
+		{"sub-minute interval", `{"interval":"30s","batchSize":20}`, SchedulerSettings{Interval: 30 * time.Second, BatchSize: 20}, false},
+		{"compound interval", `{"interval":"1m30s","batchSize":20}`, SchedulerSettings{Interval: 90 * time.Second, BatchSize: 20}, false},
+		{"legacy minutes", `{"intervalMinutes":5,"batchSize":20}`, SchedulerSettings{Interval: 5 * time.Minute, BatchSize: 20}, false},
+		{"interval wins over legacy minutes", `{"interval":"10s","intervalMinutes":5,"batchSize":20}`, SchedulerSettings{Interval: 10 * time.Second, BatchSize: 20}, false},
+		{"cron", `{"interval":"1m0s","batchSize":20,"cron":"0 * * * *"}`, SchedulerSettings{Interval: time.Minute, BatchSize: 20, Cron: "0 * * * *"}, false},
+		{"invalid interval", `{"interval":"soon","batchSize":20}`, SchedulerSettings{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got SchedulerSettings
+			err := json.Unmarshal([]byte(tt.data), &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Unmarshal() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("Unmarshal() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSchedulerSettingsValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings SchedulerSettings
+		wantErr  bool
+	}{
+		{"one second", SchedulerSettings{Interval: time.Second, BatchSize: 1}, false},
+		{"sub-minute", SchedulerSettings{Interval: 15 * time.Second, BatchSize: 10}, false},
+		{"under one second", SchedulerSettings{Interval: 500 * time.Millisecond, BatchSize: 10}, true},
+		{"zero batch size", SchedulerSettings{Interval: time.Minute}, true},
+		{"valid cron", SchedulerSettings{Interval: time.Minute, BatchSize: 10, Cron: "*/10 * * * * *"}, false},
+		{"invalid cron", SchedulerSettings{Interval: time.Minute, BatchSize: 10, Cron: "every minute"}, true},
+		{"cron that never matches", SchedulerSettings{Interval: time.Minute, BatchSize: 10, Cron: "0 0 30 2 *"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.settings.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
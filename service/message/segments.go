@@ -0,0 +1,112 @@
+package message
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Encoding is the character set an SMS is sent in
+type Encoding string
+
+// Encodings
+const (
+	// EncodingGSM7 packs the GSM 03.38 alphabet into 7 bits, characters of its extension table take two
+	EncodingGSM7 Encoding = "gsm7"
+	// EncodingUCS2 sends any other text as UTF-16, characters outside the BMP take two code units
+	EncodingUCS2 Encoding = "ucs2"
+)
+
+// Segment capacities in GSM-7 septets or UCS-2 code units
+// A concatenated message loses room in every part to the header joining the parts
+const (
+	gsm7SingleCapacity = 160
+	gsm7PartCapacity   = 153
+	ucs2SingleCapacity = 70
+	ucs2PartCapacity   = 67
+)
+
+// gsm7Basic is the GSM 03.38 basic character set, one septet each
+const gsm7Basic = "@£$¥èéùìòÇ\nØø\rÅåΔ_ΦΓΛΩΠΨΣΘΞÆæßÉ !\"#¤%&'()*+,-./0123456789:;<=>?" +
+	"¡ABCDEFGHIJKLMNOPQRSTUVWXYZÄÖÑÜ§¿abcdefghijklmnopqrstuvwxyzäöñüà"
+
+// gsm7Extension is the GSM 03.38 extension table, two septets each as they are escaped
+const gsm7Extension = "\f^{}\\[~]|€"
+
+// Segments describes how many SMS parts a content is sent in
+// Length is counted in septets for GSM-7 and in UTF-16 code units for UCS-2
+type Segments struct {
+	Encoding Encoding
+	Length   int
+	Count    int
+}
+
+// Segments returns the SMS parts the content of the message is sent in
+func (m *Message) Segments() Segments {
+	return CountSegments(m.Content)
+}
+
+// CountSegments returns the encoding of content and the number of SMS parts it takes
+// Content outside the GSM-7 alphabet is sent as UCS-2; an escaped GSM-7 character
+// or a UTF-16 surrogate pair is never split across parts
+func CountSegments(content string) Segments {
+	units := gsm7Units(content)
+	encoding, single, part := EncodingGSM7, gsm7SingleCapacity, gsm7PartCapacity
+	if units == nil {
+		units = ucs2Units(content)
+		encoding, single, part = EncodingUCS2, ucs2SingleCapacity, ucs2PartCapacity
+	}
+
+	segments := Segments{Encoding: encoding}
+	for _, size := range units {
+		segments.Length += size
+	}
+	if segments.Length == 0 {
+		return segments
+	}
+	if segments.Length <= single {
+		segments.Count = 1
+		return segments
+	}
+
+	// Fill the parts character by character, a character that does not fit starts the next part
+	segments.Count = 1
+	used := 0
+	for _, size := range units {
+		if used+size > part {
+			segments.Count++
+			used = 0
+		}
+		used += size
+	}
+
+	return segments
+}
+
+// gsm7Units returns the septets every character of content takes, nil when content is not GSM-7
+func gsm7Units(content string) []int {
+	units := make([]int, 0, len(content))
+	for _, r := range content {
+		switch {
+		case strings.ContainsRune(gsm7Basic, r):
+			units = append(units, 1)
+		case strings.ContainsRune(gsm7Extension, r):
+			units = append(units, 2)
+		default:
+			return nil
+		}
+	}
+	return units
+}
+
+// ucs2Units returns the UTF-16 code units every character of content takes
+func ucs2Units(content string) []int {
+	units := make([]int, 0, utf8.RuneCountInString(content))
+	for _, r := range content {
+		if r > 0xFFFF {
+			units = append(units, 2)
+			continue
+		}
+		units = append(units, 1)
+	}
+	return units
+}
@@ -0,0 +1,62 @@
+package message
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"qubit/env/postgres/messages"
+	"qubit/env/provider"
+	"qubit/pkg/ctxerr"
+)
+
+// SendNow claims a single pending message and sends it right away instead of waiting for the next tick
+// The send goes through the same provider call, attempt recording and outcome persistence as a batch
+// Returns ErrMessageNotFound if the message does not exist and ErrNotPending if it was already claimed
+func (s *Service) SendNow(ctx context.Context, id int64) (*Message, error) {
+	dbMsg, err := s.repo.ClaimByID(ctx, id, s.instanceID, s.sendingTimeout)
+	if errors.Is(err, messages.ErrNotFound) {
+		return nil, ErrMessageNotFound
+	}
+	if errors.Is(err, messages.ErrNotPending) {
+		return nil, ErrNotPending
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	msg := ToDomain(dbMsg)
+	if err := s.loadTemplateApprovals(ctx, []*Message{msg}); err != nil {
+		if releaseErr := s.repo.Release(context.WithoutCancel(ctx), []int64{id}); releaseErr != nil {
+			log.Printf("Warning: %v", releaseErr)
+		}
+		return nil, err
+	}
+
+	// The send shares the send rate of the batches, waiting at most as long as a batch would
+	outcome := s.send(ctx, msg, newAttempt(msg, time.Now()), time.Now().Add(s.paceWindow()))
+
+	// A message never handed to the provider goes back to pending without counting a retry
+	if ctxerr.IsCanceled(outcome.err) || errors.Is(outcome.err, provider.ErrCircuitOpen) || errors.Is(outcome.err, errSendRateExceeded) ||
+		errors.Is(outcome.err, errProviderThrottled) {
+		releaseCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), persistTimeout)
+		defer cancel()
+
+		if err := s.repo.Release(releaseCtx, []int64{id}); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+		return nil, outcome.err
+	}
+
+	if len(s.persistChunk(ctx, []sendOutcome{outcome})) == 0 {
+		return nil, fmt.Errorf("failed to persist the outcome of message %d", id)
+	}
+
+	if msg.Status == StatusSent {
+		s.cacheDeliveries(ctx, []*Message{msg})
+	}
+
+	return msg, nil
+}
@@ -0,0 +1,84 @@
+package message
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"qubit/pkg/scheduler"
+)
+
+// errSendRateExceeded is returned for a message the send rate leaves no slot for within the batch
+var errSendRateExceeded = errors.New("send rate exceeded")
+
+// sendPacer spaces webhook calls evenly at a rate per second
+// It is shared by every batch of the instance, so consecutive ticks do not send in bursts
+type sendPacer struct {
+	interval time.Duration
+
+	mu   sync.Mutex
+	next time.Time // earliest time of the next send
+}
+
+// newSendPacer creates a pacer sending perSecond messages a second, nil when perSecond is 0
+func newSendPacer(perSecond int) *sendPacer {
+	if perSecond <= 0 {
+		return nil
+	}
+	return &sendPacer{interval: time.Second / time.Duration(perSecond)}
+}
+
+// capacity returns the number of sends the rate allows until deadline
+func (p *sendPacer) capacity(deadline time.Time) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	start := p.slot(time.Now())
+	if start.After(deadline) {
+		return 0
+	}
+	return int(deadline.Sub(start)/p.interval) + 1
+}
+
+// wait reserves the next send slot and blocks until it is due
+// Returns errSendRateExceeded without waiting when the slot lies after deadline
+func (p *sendPacer) wait(ctx context.Context, deadline time.Time) error {
+	p.mu.Lock()
+	slot := p.slot(time.Now())
+	if slot.After(deadline) {
+		p.mu.Unlock()
+		return errSendRateExceeded
+	}
+	p.next = slot.Add(p.interval)
+	p.mu.Unlock()
+
+	timer := time.NewTimer(time.Until(slot))
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// slot returns the time of the next send slot not before now
+func (p *sendPacer) slot(now time.Time) time.Time {
+	if p.next.Before(now) {
+		return now
+	}
+	return p.next
+}
+
+// paceWindow is how long a batch may trickle out its messages at the send rate
+// It ends with the interval, so the remainder is left to the next tick, and early enough
+// for the last webhook call to finish within the claim lease and the scheduler task timeout
+func (s *Service) paceWindow() time.Duration {
+	window := min(s.sendingTimeout, scheduler.TaskTimeout) - s.webhookTimeout
+	if settings := s.SchedulerSettings(); settings.Cron == "" && settings.Interval > 0 {
+		window = min(window, settings.Interval)
+	}
+	return max(window, 0)
+}
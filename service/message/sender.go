@@ -0,0 +1,52 @@
+package message
+
+import (
+	"qubit/env/config"
+	"qubit/env/provider"
+	"qubit/service/leader"
+	"qubit/service/shard"
+)
+
+// MessageSender resolves the provider a message is sent through, implemented by *provider.Registry
+type MessageSender interface {
+	// Get returns the provider registered under name
+	Get(name string) (provider.Sender, bool)
+	// Default returns the provider of messages without a provider pin
+	Default() provider.Sender
+	DefaultName() string
+	// SandboxName returns the provider test messages are pinned to, if one is configured
+	SandboxName() (string, bool)
+	Names() []string
+	Config(name string) (config.ProviderConfig, bool)
+	// OpenCircuits returns the providers whose circuit breaker currently rejects calls
+	OpenCircuits() []string
+}
+
+// MaintenanceMode reports whether the scheduler should skip its runs, implemented by *maintenance.Service
+type MaintenanceMode interface {
+	Enabled() bool
+}
+
+// Leadership reports whether this instance was elected to run the scheduler, implemented by *leader.Service
+type Leadership interface {
+	IsLeader() bool
+	Status() leader.Status
+}
+
+// ErrorRecorder counts errors by category and provider, implemented by *errorstats.Service
+type ErrorRecorder interface {
+	Record(category, provider, code, message string)
+}
+
+// Sharding assigns this instance the slice of the queue it claims from, implemented by *shard.Service
+type Sharding interface {
+	Shards() []int
+	Status() shard.Status
+}
+
+// TenantSettings provides the default metadata and message quotas of tenants, implemented by *tenant.Service
+type TenantSettings interface {
+	DefaultMetadata(tenantID int64) map[string]string
+	// Quota returns false for an unknown tenant
+	Quota(tenantID int64) (TenantQuota, bool)
+}
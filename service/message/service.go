@@ -2,54 +2,171 @@ package message
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"slices"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 
-	"qubit/env/postgres"
-	"qubit/env/webhook"
+	"qubit/env/postgres/messages"
+	"qubit/env/provider"
+	"qubit/env/redis"
+	"qubit/pkg/ctxerr"
+	"qubit/pkg/eventbus"
+	"qubit/pkg/locale"
+	"qubit/pkg/lockwatch"
 	"qubit/pkg/scheduler"
+	"qubit/pkg/urlcheck"
 )
 
+// idleRunsBeforeBackoff is the number of empty batches in a row before an adaptive interval starts stretching
+const idleRunsBeforeBackoff = 3
+
 // Service handles the business logic for message operations
 type Service struct {
-	postgres      *postgres.Client
-	webhookClient *webhook.Client
+	repo          MessageRepository
+	providers     MessageSender
+	deliveryCache *redis.Client // nil when Redis is disabled
 	scheduler     *scheduler.Client
+	maintenance   MaintenanceMode
+	leadership    Leadership     // nil when leader election is disabled
+	sharding      Sharding       // nil when every instance claims from the whole queue
+	errorStats    ErrorRecorder  // nil counts no errors
+	tenants       TenantSettings // nil adds no default metadata and enforces no quotas
+
+	// settings and defaults are swapped whole, so a tick or status read never sees half-applied settings
+	// schedulerMu serializes the restarts and reloads changing them
+	settings         atomic.Pointer[SchedulerSettings] // the settings the scheduler runs with
+	defaults         atomic.Pointer[SchedulerSettings] // the settings from configuration
+	schedulerMu      sync.Mutex
+	dispatchWorkers  int
+	sendPacer        *sendPacer // nil when the send rate is unlimited
+	persistChunkSize int
+	sendingTimeout   time.Duration
+	webhookTimeout   time.Duration
+	instanceID       string
+	retryPolicy      RetryPolicy
+	recipientLimit   RecipientLimit
+	urlAllowList     urlcheck.AllowList
+	urlQuarantine    bool
+	urlRecheck       Status // status of claimed messages violating the allow-list, empty skips the recheck
+	replyWindow      time.Duration
+	localeFallback   []string
+	publishEvents    bool
+	tickLock         bool
+	idleMaxInterval  time.Duration
+	notifyDispatch   bool
+	runRetention     time.Duration                      // how long scheduler run reports are kept, 0 keeps them forever
+	adaptive         atomic.Pointer[scheduler.Adaptive] // nil unless the interval stretches while idle
+	standby          atomic.Bool                        // the last tick was skipped because another instance held the tick lock
+	paused           atomic.Pointer[SchedulerPause]     // nil unless an operator paused the scheduler
+	live             *liveStats
+	throttles        providerThrottles
+	approvals        atomic.Pointer[templateApprovals] // approval status of provider templates as of the last claim
+	lastBatch        atomic.Pointer[CompletedBatch]    // the last batch that claimed messages
+	progress         *eventbus.Bus[ProgressEvent]
+	lifecycle        *eventbus.Bus[Event]
+
+	mu lockwatch.Mutex // Mutex to prevent concurrent processing within the same instance, watched by the watchdog
+}
+
+// Deps are the collaborators of the message service
+// Production wires NewPostgresRepository and the provider registry, tests the in-memory fakes
+type Deps struct {
+	Repo          MessageRepository
+	Providers     MessageSender
+	DeliveryCache *redis.Client // nil when Redis is disabled
+	Maintenance   MaintenanceMode
+	Leadership    Leadership     // nil when leader election is disabled
+	Sharding      Sharding       // nil when the queue is not sharded
+	ErrorStats    ErrorRecorder  // nil counts no errors
+	Tenants       TenantSettings // nil adds no default metadata and enforces no quotas
+}
 
-	intervalMinutes  int
-	messageBatchSize int
+// Options configure the message service
+type Options struct {
+	// Interval, Cron and BatchSize are the configured scheduler defaults, see SchedulerSettings
+	Interval  time.Duration
+	Cron      string
+	BatchSize int
 
-	mu sync.Mutex // Mutex to prevent concurrent processing within the same instance
+	DispatchWorkers   int
+	SendRatePerSecond int // 0 sends without a rate limit
+	PersistChunkSize  int
+	SendingTimeout    time.Duration // lease of a claimed message, once expired it returns to pending
+	WebhookTimeout    time.Duration // deadline of a single provider call
+	InstanceID        string
+	RetryPolicy       RetryPolicy
+	RecipientLimit    RecipientLimit
+	URLPolicy         URLPolicy
+	ReplyWindow       time.Duration
+	LocaleFallback    []string // locales tried after the recipient's own, see localeChain
+	PublishEvents     bool
+	TickLock          bool
+	IdleMaxInterval   time.Duration // 0 keeps the interval fixed while idle
+	NotifyDispatch    bool
+	RunRetention      time.Duration // how long scheduler run reports are kept, 0 keeps them forever
 }
 
 // NewService creates a new message service and starts the scheduler
-func NewService(
-	postgresClient *postgres.Client,
-	webhookClient *webhook.Client,
-	intervalMinutes int,
-	messageBatchSize int,
-) *Service {
+func NewService(deps Deps, opts Options) *Service {
 	s := &Service{
-		postgres:         postgresClient,
-		webhookClient:    webhookClient,
+		repo:             deps.Repo,
+		providers:        deps.Providers,
+		deliveryCache:    deps.DeliveryCache,
 		scheduler:        scheduler.Run(),
-		intervalMinutes:  intervalMinutes,
-		messageBatchSize: messageBatchSize,
+		dispatchWorkers:  opts.DispatchWorkers,
+		sendPacer:        newSendPacer(opts.SendRatePerSecond),
+		persistChunkSize: max(opts.PersistChunkSize, 1),
+		sendingTimeout:   opts.SendingTimeout,
+		webhookTimeout:   opts.WebhookTimeout,
+		instanceID:       opts.InstanceID,
+		retryPolicy:      opts.RetryPolicy,
+		recipientLimit:   opts.RecipientLimit,
+		urlAllowList:     urlcheck.NewAllowList(opts.URLPolicy.Domains),
+		urlQuarantine:    opts.URLPolicy.Quarantine,
+		urlRecheck:       opts.URLPolicy.recheckStatus(),
+		replyWindow:      opts.ReplyWindow,
+		localeFallback:   locale.NormalizeAll(opts.LocaleFallback),
+		publishEvents:    opts.PublishEvents,
+		tickLock:         opts.TickLock,
+		idleMaxInterval:  opts.IdleMaxInterval,
+		notifyDispatch:   opts.NotifyDispatch,
+		runRetention:     opts.RunRetention,
+		maintenance:      deps.Maintenance,
+		leadership:       deps.Leadership,
+		sharding:         deps.Sharding,
+		errorStats:       deps.ErrorStats,
+		tenants:          deps.Tenants,
+		live:             newLiveStats(),
+		progress:         eventbus.New[ProgressEvent](),
+		lifecycle:        eventbus.New[Event](),
 	}
+	s.defaults.Store(&SchedulerSettings{
+		Interval:  opts.Interval,
+		BatchSize: opts.BatchSize,
+		Cron:      opts.Cron,
+	})
+	s.settings.Store(s.defaults.Load())
 
-	// Start the scheduler automatically
-	task := func(ctx context.Context) error {
-		return s.ProcessUnsentMessages(ctx, s.messageBatchSize)
-	}
+	// Runtime overrides persisted by an operator take precedence over configuration
+	settings := s.loadSchedulerOverrides()
 
-	if err := s.scheduler.Start(task, s.intervalMinutes); err != nil {
+	// A pause persisted by an operator survives the restart, the scheduler starts but skips its batches
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	s.syncSchedulerPause(ctx)
+	cancel()
+
+	// Start the scheduler automatically
+	if err := s.startScheduler(settings); err != nil {
 		log.Printf("Warning: failed to start scheduler: %v", err)
 	} else {
-		log.Printf("✓ Scheduler started (interval: %d minutes, batch size: %d)", intervalMinutes, messageBatchSize)
+		log.Printf("✓ Scheduler started (interval: %s, cron: %q, batch size: %d)", settings.Interval, settings.Cron, settings.BatchSize)
 	}
 
 	return s
@@ -57,7 +174,7 @@ func NewService(
 
 // GetSentMessages retrieves all sent messages
 func (s *Service) GetSentMessages(ctx context.Context) ([]*Message, error) {
-	dbMessages, err := s.postgres.Messages.ListSent(ctx, 0)
+	dbMessages, err := s.repo.ListSent(ctx, 0)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get sent messages: %w", err)
 	}
@@ -65,135 +182,894 @@ func (s *Service) GetSentMessages(ctx context.Context) ([]*Message, error) {
 	return ToDomainSlice(dbMessages), nil
 }
 
+// GetMessage retrieves a single message by its ID regardless of status
+// A non-nil tenantID scopes the lookup to the messages of that tenant
+// Returns ErrMessageNotFound if the message does not exist or belongs to another tenant
+func (s *Service) GetMessage(ctx context.Context, id int64, tenantID *int64) (*Message, error) {
+	dbMsg, err := s.repo.GetMessage(ctx, id, tenantID)
+	if errors.Is(err, messages.ErrNotFound) {
+		return s.getArchivedMessage(ctx, id, tenantID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get message: %w", err)
+	}
+
+	return ToDomain(dbMsg), nil
+}
+
+// getArchivedMessage looks up a message no longer in messages in the archive, marking it as Archived
+// Returns ErrMessageNotFound if it was never created or was deleted by retention
+func (s *Service) getArchivedMessage(ctx context.Context, id int64, tenantID *int64) (*Message, error) {
+	dbMsg, err := s.repo.GetArchivedMessage(ctx, id, tenantID)
+	if errors.Is(err, messages.ErrNotFound) {
+		return nil, ErrMessageNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get archived message: %w", err)
+	}
+
+	msg := ToDomain(dbMsg)
+	msg.Archived = true
+
+	return msg, nil
+}
+
+// ListMessages retrieves all messages matching the filter
+func (s *Service) ListMessages(ctx context.Context, filter ListFilter) ([]*Message, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrValidation, err)
+	}
+
+	dbFilter := filter.toPostgres()
+	if filter.Max > 0 {
+		dbFilter.Limit = filter.Max + 1
+	}
+
+	dbMessages, err := s.repo.ListMessages(ctx, dbFilter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list messages: %w", err)
+	}
+
+	if filter.Max > 0 && len(dbMessages) > filter.Max {
+		return nil, fmt.Errorf("%w: more than %d messages match, narrow the filters or stream the list", ErrListTooLarge, filter.Max)
+	}
+
+	return ToDomainSlice(dbMessages), nil
+}
+
+// StreamMessages calls fn with the messages matching the filter one at a time, in creation order
+// Only the current message is held in memory, so Max does not apply; the first error of fn stops the listing
+func (s *Service) StreamMessages(ctx context.Context, filter ListFilter, fn func(*Message) error) error {
+	if err := filter.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrValidation, err)
+	}
+
+	err := s.repo.EachMessage(ctx, filter.toPostgres(), func(dbMsg *messages.Message) error {
+		return fn(ToDomain(dbMsg))
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list messages: %w", err)
+	}
+
+	return nil
+}
+
+// toPostgres converts the filter to the filter of the messages repository
+func (f ListFilter) toPostgres() messages.Filter {
+	dbFilter := messages.Filter{
+		Status:        string(f.Status),
+		PhoneNumber:   f.PhoneNumber,
+		CreatedFrom:   f.CreatedFrom,
+		CreatedTo:     f.CreatedTo,
+		ProcessedFrom: f.ProcessedFrom,
+		ProcessedTo:   f.ProcessedTo,
+		Search:        f.Search,
+		Metadata:      f.Metadata,
+		TenantID:      f.TenantID,
+
+		IncludeArchived: f.IncludeArchived,
+	}
+	if f.ExternalRef != nil {
+		dbFilter.ExternalRefType = f.ExternalRef.Type
+		dbFilter.ExternalRefID = f.ExternalRef.ID
+	}
+
+	return dbFilter
+}
+
+// GetInFlightMessages retrieves the messages currently claimed for sending by any instance
+func (s *Service) GetInFlightMessages(ctx context.Context) ([]*Message, error) {
+	dbMessages, err := s.repo.ListInFlight(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get in-flight messages: %w", err)
+	}
+
+	return ToDomainSlice(dbMessages), nil
+}
+
 // CreateMessage creates a new message
-func (s *Service) CreateMessage(ctx context.Context, phoneNumber, content string) (*Message, error) {
+func (s *Service) CreateMessage(ctx context.Context, phoneNumber, content string, opts CreateOptions) (*Message, error) {
+	provider, err := s.resolveProvider(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	content, providerTemplate, contentLocale, err := s.resolveContent(ctx, content, provider, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	retryPolicy, err := s.resolveRetryPolicy(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	externalRef, err := resolveExternalRef(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	messageUUID, err := resolveUUID(opts)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create domain message with validation
 	msg := &Message{
-		PhoneNumber: phoneNumber,
-		Content:     content,
-		CreatedAt:   time.Now(),
+		UUID:          messageUUID,
+		PhoneNumber:   phoneNumber,
+		Content:       content,
+		CreatedAt:     time.Now(),
+		Status:        StatusPending,
+		Provider:      provider,
+		ScheduledAt:   opts.ScheduledAt,
+		IsTest:        opts.IsTest,
+		Transactional: opts.Transactional,
+		RetryPolicy:   retryPolicy,
+		ExternalRef:   externalRef,
+		Metadata:      s.resolveMetadata(opts),
+		TenantID:      opts.TenantID,
+
+		ProviderTemplate: providerTemplate,
+		ContentLocale:    contentLocale,
 	}
 
 	// Validate before inserting
 	if err := msg.Validate(); err != nil {
-		return nil, fmt.Errorf("validation failed: %w", err)
+		return nil, fmt.Errorf("%w: %v", ErrValidation, err)
+	}
+
+	if err := s.screenURLs(msg); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkTenantQuota(ctx, msg.TenantID, 1); err != nil {
+		return nil, err
 	}
 
+	tx, err := s.repo.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		// Rollback is a no-op once the transaction is committed
+		_ = tx.Rollback(ctx)
+	}()
+
 	// Insert into database
 	dbMsg := ToPostgres(msg)
 
-	if err := s.postgres.Messages.Create(ctx, dbMsg); err != nil {
+	err = tx.CreateMessage(ctx, dbMsg)
+	if errors.Is(err, messages.ErrDuplicateUUID) {
+		return nil, ErrUUIDTaken
+	}
+	if err != nil {
 		return nil, fmt.Errorf("failed to create message: %w", err)
 	}
 
-	// Update domain model with generated ID
+	// Update domain model with generated identifiers
 	msg.ID = dbMsg.ID
+	msg.UUID = dbMsg.UUID
+
+	if err := s.recordQuarantineWithTx(ctx, tx, []*Message{msg}); err != nil {
+		return nil, err
+	}
+
+	if err := s.recordEventsWithTx(ctx, tx, EventMessageCreated, []*Message{msg}, nil); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit message: %w", err)
+	}
+	s.WakeScheduler()
+	s.publishLifecycle(EventMessageCreated, []*Message{msg}, nil)
 
 	return msg, nil
 }
 
-// ProcessUnsentMessages fetches and sends unsent messages
+// resolveUUID validates a client-supplied UUID in its canonical lowercase form, empty when none was given
+func resolveUUID(opts CreateOptions) (string, error) {
+	if opts.UUID == "" {
+		return "", nil
+	}
+
+	id, err := uuid.Parse(opts.UUID)
+	if err != nil {
+		return "", fmt.Errorf("%w: uuid must be a valid UUID", ErrValidation)
+	}
+
+	return id.String(), nil
+}
+
+// UpsertMessage creates or updates the message identified by the given public UUID
+// Re-syncing the same definition is idempotent; messages that already left pending return ErrNotPending
+// and a UUID held by a message of another tenant returns ErrUUIDTaken
+// created reports whether a new message was inserted
+func (s *Service) UpsertMessage(ctx context.Context, uuid, phoneNumber, content string, opts CreateOptions) (msg *Message, created bool, err error) {
+	provider, err := s.resolveProvider(opts)
+	if err != nil {
+		return nil, false, err
+	}
+
+	content, providerTemplate, contentLocale, err := s.resolveContent(ctx, content, provider, opts)
+	if err != nil {
+		return nil, false, err
+	}
+
+	retryPolicy, err := s.resolveRetryPolicy(opts)
+	if err != nil {
+		return nil, false, err
+	}
+
+	externalRef, err := resolveExternalRef(opts)
+	if err != nil {
+		return nil, false, err
+	}
+
+	msg = &Message{
+		UUID:          uuid,
+		PhoneNumber:   phoneNumber,
+		Content:       content,
+		CreatedAt:     time.Now(),
+		Status:        StatusPending,
+		Provider:      provider,
+		ScheduledAt:   opts.ScheduledAt,
+		IsTest:        opts.IsTest,
+		Transactional: opts.Transactional,
+		RetryPolicy:   retryPolicy,
+		ExternalRef:   externalRef,
+		Metadata:      s.resolveMetadata(opts),
+		TenantID:      opts.TenantID,
+
+		ProviderTemplate: providerTemplate,
+		ContentLocale:    contentLocale,
+	}
+
+	if err := msg.Validate(); err != nil {
+		return nil, false, fmt.Errorf("%w: %v", ErrValidation, err)
+	}
+
+	if err := s.screenURLs(msg); err != nil {
+		return nil, false, err
+	}
+
+	// Only an insert counts against the quota, re-syncing an existing message does not
+	exists, err := s.repo.ExistsByUUID(ctx, uuid)
+	if err != nil {
+		return nil, false, err
+	}
+	if !exists {
+		if err := s.checkTenantQuota(ctx, msg.TenantID, 1); err != nil {
+			return nil, false, err
+		}
+	}
+
+	tx, err := s.repo.BeginTx(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	defer func() {
+		// Rollback is a no-op once the transaction is committed
+		_ = tx.Rollback(ctx)
+	}()
+
+	dbMsg := ToPostgres(msg)
+
+	created, err = tx.UpsertMessage(ctx, dbMsg)
+	if errors.Is(err, messages.ErrNotPending) {
+		return nil, false, ErrNotPending
+	}
+	if errors.Is(err, messages.ErrDuplicateUUID) {
+		return nil, false, ErrUUIDTaken
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to upsert message: %w", err)
+	}
+	msg = ToDomain(dbMsg)
+
+	if err := s.recordQuarantineWithTx(ctx, tx, []*Message{msg}); err != nil {
+		return nil, false, err
+	}
+
+	// Re-syncing an existing message is not a lifecycle change
+	if created {
+		if err := s.recordEventsWithTx(ctx, tx, EventMessageCreated, []*Message{msg}, nil); err != nil {
+			return nil, false, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, false, fmt.Errorf("failed to commit message: %w", err)
+	}
+	s.WakeScheduler()
+	if created {
+		s.publishLifecycle(EventMessageCreated, []*Message{msg}, nil)
+	}
+
+	return msg, created, nil
+}
+
+// CancelMessage cancels a pending message so it is never sent
+// A non-nil tenantID only cancels a message of that tenant
+// Returns ErrMessageNotFound if the message does not exist or belongs to another tenant and ErrNotPending if
+// it was already sent or failed
+func (s *Service) CancelMessage(ctx context.Context, id int64, tenantID *int64) (*Message, error) {
+	dbMsg, err := s.repo.CancelMessage(ctx, id, tenantID)
+	if errors.Is(err, messages.ErrNotFound) {
+		return nil, ErrMessageNotFound
+	}
+	if errors.Is(err, messages.ErrNotPending) {
+		return nil, ErrNotPending
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel message: %w", err)
+	}
+
+	log.Printf("Message %d cancelled", id)
+
+	return ToDomain(dbMsg), nil
+}
+
+// ProcessUnsentMessages claims due messages and sends them
 // This is the core function called by the scheduler
-// Uses SELECT FOR UPDATE SKIP LOCKED to prevent duplicate processing across multiple instances
-func (s *Service) ProcessUnsentMessages(ctx context.Context, batchSize int) error {
+// The claim phase marks the messages as sending and commits, so no row locks are held during webhook calls
+// The send phase calls the webhook on the dispatch worker pool chunk by chunk, committing the outcomes of each
+// chunk before the next one is sent, so a crash late in a large batch only loses the statuses of the last chunk
+// Each claim holds a lease of the sending timeout; messages whose lease expired, e.g. after a crash,
+// are returned to pending by the reaper before each claim
+// The result is returned with an error as well, counting the messages handled before the batch failed
+func (s *Service) ProcessUnsentMessages(ctx context.Context, batchSize int) (*BatchResult, error) {
 	// Lock to prevent concurrent processing within same instance
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	// Begin transaction
-	tx, err := s.postgres.BeginTx(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+	result := &BatchResult{}
+	started := time.Now()
+
+	s.reapStuckMessages(ctx)
+
+	// Messages of providers whose circuit is open or that asked to back off are left pending rather than claimed
+	paused := s.providers.OpenCircuits()
+	for _, throttle := range s.throttles.active() {
+		if !slices.Contains(paused, throttle.Provider) {
+			paused = append(paused, throttle.Provider)
+		}
+	}
+	if len(paused) == len(s.providers.Names()) {
+		log.Println("⚠ All providers have an open circuit or are throttled, dispatching paused")
+		return result, nil
 	}
 
-	// Ensure transaction is rolled back on error
-	defer func() {
-		if err != nil {
-			if rbErr := tx.Rollback(ctx); rbErr != nil {
-				log.Printf("Warning: failed to rollback transaction: %v", rbErr)
-			}
+	// With a send rate, only the messages it allows before the next tick are claimed, the rest wait for that tick
+	paceUntil := started.Add(s.paceWindow())
+	if s.sendPacer != nil {
+		allowed := s.sendPacer.capacity(paceUntil)
+		if allowed == 0 {
+			log.Println("Send rate leaves no room in this tick, messages carried over to the next one")
+			return result, nil
 		}
-	}()
+		batchSize = min(batchSize, allowed)
+	}
+
+	// With sharding, only the messages of the shards assigned to this instance are claimed
+	var shards []int
+	if s.sharding != nil {
+		if shards = s.sharding.Shards(); len(shards) == 0 {
+			log.Println("No shards assigned to this instance yet, skipping batch")
+			return result, nil
+		}
+	}
 
-	// Fetch and lock unsent messages atomically
-	dbMessages, err := s.postgres.Messages.ListAndLockUnsent(ctx, tx, batchSize)
+	// Claim due messages, committed immediately
+	dbMessages, err := s.repo.ClaimUnsent(ctx, batchSize, s.instanceID, s.sendingTimeout, s.providers.DefaultName(), paused, shards)
 	if err != nil {
-		return fmt.Errorf("failed to fetch and lock unsent messages: %w", err)
+		return result, fmt.Errorf("failed to claim unsent messages: %w", err)
 	}
 
 	if len(dbMessages) == 0 {
-		// No messages to process, commit empty transaction
-		if err := tx.Commit(ctx); err != nil {
-			return fmt.Errorf("failed to commit transaction: %w", err)
-		}
 		log.Println("No unsent messages to process")
-		return nil
+		return result, nil
 	}
 
-	log.Printf("Processing %d unsent messages (locked for this instance)", len(dbMessages))
+	lockedAt := time.Now()
+	result.Claimed = len(dbMessages)
+
+	log.Printf("Processing %d unsent messages (claimed by this instance)", len(dbMessages))
 
 	// Convert to domain models
-	unsentMessages := ToDomainSlice(dbMessages)
+	claimed := ToDomainSlice(dbMessages)
+
+	// Messages over the per-recipient limit are deferred or rejected before any webhook call
+	claimed, result.Throttled, err = s.guardRecipients(ctx, claimed)
+	if err == nil {
+		// Provider templates are checked against the approval status last synced from the provider
+		err = s.loadTemplateApprovals(ctx, claimed)
+	}
+	if err != nil {
+		ids := make([]int64, 0, len(dbMessages))
+		for _, dbMsg := range dbMessages {
+			ids = append(ids, dbMsg.ID)
+		}
+		if releaseErr := s.repo.Release(context.WithoutCancel(ctx), ids); releaseErr != nil {
+			log.Printf("Warning: %v", releaseErr)
+		}
+		return result, err
+	}
+
+	attempts := make([]*Attempt, len(claimed))
+	for i, msg := range claimed {
+		attempts[i] = newAttempt(msg, lockedAt)
+	}
+
+	progress := s.newBatchProgress(len(claimed))
+
+	var (
+		sent       []*Message
+		unattended []int64
+		deferred   []int64
+		live       BatchResult // excludes test messages
+	)
+	for start := 0; start < len(claimed); start += s.persistChunkSize {
+		end := min(start+s.persistChunkSize, len(claimed))
+		outcomes := s.dispatch(ctx, claimed[start:end], attempts[start:end], paceUntil, progress)
+
+		// Messages never handed to the webhook go back to pending without counting a retry
+		handed := outcomes[:0]
+		for _, o := range outcomes {
+			switch {
+			case ctxerr.IsCanceled(o.err):
+				unattended = append(unattended, o.msg.ID)
+			case errors.Is(o.err, provider.ErrCircuitOpen), errors.Is(o.err, errSendRateExceeded), errors.Is(o.err, errProviderThrottled):
+				deferred = append(deferred, o.msg.ID)
+			default:
+				handed = append(handed, o)
+			}
+		}
+
+		for _, o := range s.persistChunk(ctx, handed) {
+			if o.msg.Status == StatusSent {
+				sent = append(sent, o.msg)
+			}
+			result.count(o.msg.Status)
+			if category := o.attempt.FailureCategory; category != nil {
+				result.fail(*category)
+			}
+			if !o.msg.IsTest {
+				live.count(o.msg.Status)
+			}
+		}
+	}
+
+	if released := append(unattended, deferred...); len(released) > 0 {
+		releaseCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), persistTimeout)
+		defer cancel()
+
+		if err := s.repo.Release(releaseCtx, released); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+	}
+	result.Deferred = len(deferred)
+
+	if len(unattended) > 0 {
+		result.Duration = time.Since(started)
+		progress.finished(result)
+		return result, fmt.Errorf("batch processing cancelled, %d messages released: %w", len(unattended), ctx.Err())
+	}
+
+	result.Duration = time.Since(started)
+	log.Printf("✓ Batch processing complete (claimed: %d, sent: %d, retried: %d, failed: %d, throttled: %d, deferred: %d, quarantined: %d, took %s)",
+		result.Claimed, result.Sent, result.Retried, result.Failed, result.Throttled, result.Deferred, result.Quarantined, result.Duration.Round(time.Millisecond))
+
+	s.live.record(live.Sent, live.Retried+live.Failed, live.Sent+live.Failed)
+	progress.finished(result)
+	s.lastBatch.Store(&CompletedBatch{FinishedAt: time.Now(), Result: *result})
+
+	// Cache deliveries only once they are persisted
+	s.cacheDeliveries(ctx, sent)
+
+	return result, nil
+}
 
-	// Send each message and update within transaction
-	for _, msg := range unsentMessages {
-		if sendErr := s.sendMessageWithTx(ctx, tx, msg); sendErr != nil {
-			log.Printf("Error sending message %d: %v", msg.ID, sendErr)
-			// Continue processing other messages even if one fails
+// persistChunk stores the outcomes of a chunk of the batch and returns the persisted ones
+// Outcomes are persisted even when the batch context was cancelled, a delivered message must not be sent twice
+// The chunk is written in one transaction with a savepoint per outcome, so a failing outcome only rolls back its own
+// writes; when the transaction itself fails, the outcomes are written again in a transaction each
+func (s *Service) persistChunk(batchCtx context.Context, outcomes []sendOutcome) []sendOutcome {
+	if len(outcomes) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(batchCtx), persistTimeout)
+	defer cancel()
+
+	persisted, err := s.persistOutcomes(ctx, outcomes)
+	if err == nil {
+		s.publishOutcomes(persisted)
+		s.recordFailures(persisted)
+		return persisted
+	}
+	log.Printf("Error persisting outcomes of %d messages: %v", len(outcomes), err)
+	s.recordInternal(err)
+
+	if len(outcomes) == 1 {
+		logUnrecorded(outcomes[0])
+		return nil
+	}
+
+	persisted = make([]sendOutcome, 0, len(outcomes))
+	for _, o := range outcomes {
+		single, err := s.persistOutcomes(ctx, []sendOutcome{o})
+		if err != nil {
+			log.Printf("Error persisting outcome of message %d: %v", o.msg.ID, err)
+			logUnrecorded(o)
+			continue
+		}
+		persisted = append(persisted, single...)
+	}
+	s.publishOutcomes(persisted)
+	s.recordFailures(persisted)
+
+	return persisted
+}
+
+// persistOutcomes writes the outcomes in one transaction and returns the persisted ones
+// A delivered message whose outcome fails to persist is still recorded as sent, one that lost its lease is skipped;
+// on error nothing was persisted and the messages are left as claimed
+func (s *Service) persistOutcomes(ctx context.Context, outcomes []sendOutcome) ([]sendOutcome, error) {
+	claimed := make([]Message, len(outcomes))
+	for i, o := range outcomes {
+		claimed[i] = *o.msg
+	}
+	// Nothing is persisted once the transaction fails, so the messages are retried from their claimed state
+	restore := func() {
+		for i, o := range outcomes {
+			*o.msg = claimed[i]
+		}
+	}
+
+	tx, err := s.repo.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if rbErr := tx.Rollback(ctx); rbErr != nil && !errors.Is(rbErr, pgx.ErrTxClosed) {
+			log.Printf("Warning: failed to rollback transaction: %v", rbErr)
+		}
+	}()
+
+	persisted := make([]sendOutcome, 0, len(outcomes))
+	for i, o := range outcomes {
+		err := s.persistOutcome(ctx, tx, o)
+		if err == nil {
+			persisted = append(persisted, o)
+			continue
 		}
+		*o.msg = claimed[i]
+		if errors.Is(err, messages.ErrLeaseLost) {
+			// The lease expired and the message was reaped or claimed again, its new holder owns the outcome
+			log.Printf("⚠ Message %d lost its lease before its outcome was stored, skipping it", o.msg.ID)
+			continue
+		}
+		log.Printf("Error persisting outcome of message %d: %v", o.msg.ID, err)
+
+		if o.err != nil {
+			logUnrecorded(o)
+			continue
+		}
+		if err := s.recordDeliveryWithTx(ctx, tx, o); err != nil {
+			log.Printf("Error recording delivery of message %d: %v", o.msg.ID, err)
+			*o.msg = claimed[i]
+			logUnrecorded(o)
+			continue
+		}
+		log.Printf("⚠ Message %d recorded as sent without its attempt and events", o.msg.ID)
+		persisted = append(persisted, o)
 	}
 
-	// Commit transaction to release locks and persist updates
 	if err := tx.Commit(ctx); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
+		restore()
+		return nil, fmt.Errorf("failed to commit: %w", err)
 	}
 
-	log.Printf("✓ Batch processing complete, transaction committed")
+	return persisted, nil
+}
+
+// persistOutcome stores the outcome of a webhook call together with its attempt under a savepoint of tx
+// A delivered message whose sent status cannot be stored fails the savepoint, it is never recorded as a failed send
+func (s *Service) persistOutcome(ctx context.Context, tx MessageTx, o sendOutcome) error {
+	savepoint, err := tx.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create savepoint: %w", err)
+	}
+	defer func() {
+		if rbErr := savepoint.Rollback(ctx); rbErr != nil && !errors.Is(rbErr, pgx.ErrTxClosed) {
+			log.Printf("Warning: failed to rollback savepoint: %v", rbErr)
+		}
+	}()
+
+	switch {
+	case o.err == nil:
+		if err := s.markSentWithTx(ctx, savepoint, o.msg, o.attempt, o.messageID); err != nil {
+			return err
+		}
+	case s.isBlocked(o.err):
+		if err := s.blockWithTx(ctx, savepoint, o.msg, o.attempt, o.err); err != nil {
+			return err
+		}
+	default:
+		log.Printf("Error sending message %d: %v", o.msg.ID, o.err)
+		if err := s.scheduleRetryWithTx(ctx, savepoint, o.msg, o.attempt, o.err); err != nil {
+			return fmt.Errorf("failed to schedule retry: %w", err)
+		}
+	}
+
+	// Record the attempt with its latency breakdown
+	o.attempt.finish(o.err)
+	if err := savepoint.CreateAttempt(ctx, AttemptToPostgres(o.attempt)); err != nil {
+		return err
+	}
+
+	switch o.msg.Status {
+	case StatusSent:
+		err = s.recordEventsWithTx(ctx, savepoint, EventMessageSent, []*Message{o.msg}, o.attempt)
+	case StatusFailed:
+		err = s.recordEventsWithTx(ctx, savepoint, EventMessageFailed, []*Message{o.msg}, o.attempt)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := savepoint.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to release savepoint: %w", err)
+	}
+
+	return nil
+}
+
+// recordDeliveryWithTx stores only the sent status of a delivered message whose outcome failed to persist,
+// under a savepoint of tx; the attempt and events are lost, but the message is not sent again
+func (s *Service) recordDeliveryWithTx(ctx context.Context, tx MessageTx, o sendOutcome) error {
+	savepoint, err := tx.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create savepoint: %w", err)
+	}
+	defer func() {
+		if rbErr := savepoint.Rollback(ctx); rbErr != nil && !errors.Is(rbErr, pgx.ErrTxClosed) {
+			log.Printf("Warning: failed to rollback savepoint: %v", rbErr)
+		}
+	}()
+
+	if err := s.markSentWithTx(ctx, savepoint, o.msg, o.attempt, o.messageID); err != nil {
+		return err
+	}
+
+	if err := savepoint.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to release savepoint: %w", err)
+	}
 
 	return nil
 }
 
-// sendMessageWithTx sends a single message and updates its status within a transaction
-func (s *Service) sendMessageWithTx(ctx context.Context, tx pgx.Tx, msg *Message) error {
-	log.Printf("Sending message %d to %s", msg.ID, msg.PhoneNumber)
+// logUnrecorded reports an outcome that could not be stored
+// The message stays claimed until its lease expires and the reaper returns it to pending without counting a retry
+func logUnrecorded(o sendOutcome) {
+	if o.err == nil {
+		log.Printf("⚠ Message %d was delivered but its status could not be stored, it is sent again once its lease expires", o.msg.ID)
+		return
+	}
+	log.Printf("Message %d left claimed, it returns to pending once its lease expires", o.msg.ID)
+}
 
-	// Send message via webhook
-	messageID, err := s.webhookClient.SendMessage(ctx, msg.PhoneNumber, msg.Content)
+// reapStuckMessages returns messages whose lease expired while in sending to pending
+// Their outcome is unknown, so no retry is counted
+func (s *Service) reapStuckMessages(ctx context.Context) {
+	reaped, err := s.repo.ReapStuck(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to send message: %w", err)
+		log.Printf("Warning: %v", err)
+		return
+	}
+	if reaped > 0 {
+		log.Printf("⚠ Returned %d messages with an expired lease to pending", reaped)
 	}
+}
 
-	// Mark as sent within the transaction
+// markSentWithTx records a successful webhook call within a transaction
+func (s *Service) markSentWithTx(ctx context.Context, tx MessageTx, msg *Message, attempt *Attempt, messageID string) error {
+	if err := msg.TransitionTo(StatusSent); err != nil {
+		return err
+	}
 	sentAt := time.Now()
-	err = s.postgres.Messages.UpdateWithTx(ctx, tx, msg.ID, &messageID, &sentAt)
+	err := tx.MarkSent(ctx, msg.ID, s.instanceID, &messageID, &sentAt)
+	attempt.DBUpdate = time.Since(sentAt)
 	if err != nil {
 		return fmt.Errorf("failed to update message status: %w", err)
 	}
 
+	msg.MessageID = &messageID
+	msg.ProcessedAt = &sentAt
+
 	log.Printf("✓ Message %d sent successfully (messageId: %s)", msg.ID, messageID)
 
 	return nil
 }
 
+// scheduleRetryWithTx records a failed attempt and schedules the next one using the retry policy
+// The message goes back to pending while retries remain and to failed once they are exhausted
+// A provider asking to back off with Retry-After pushes the next attempt past that delay
+func (s *Service) scheduleRetryWithTx(ctx context.Context, tx MessageTx, msg *Message, attempt *Attempt, sendErr error) error {
+	retryCount := msg.RetryCount + 1
+
+	// Failures before the sending transition are treated as failed sends
+	if msg.Status == StatusPending {
+		msg.Status = StatusSending
+	}
+
+	policy := s.retryPolicyFor(msg)
+
+	var nextAttemptAt *time.Time
+	if policy.CanRetry(retryCount) {
+		if err := msg.TransitionTo(StatusPending); err != nil {
+			return err
+		}
+		next := time.Now().Add(policy.NextDelay(retryCount))
+		if retryAfter, ok := provider.RetryAfterOf(sendErr); ok {
+			if throttledUntil := time.Now().Add(min(retryAfter, maxProviderThrottle)); throttledUntil.After(next) {
+				next = throttledUntil
+			}
+		}
+		nextAttemptAt = &next
+		log.Printf("Message %d will be retried at %s (attempt %d of %d)", msg.ID, next.Format(time.RFC3339), retryCount+1, policy.MaxRetries+1)
+	} else {
+		if err := msg.TransitionTo(StatusFailed); err != nil {
+			return err
+		}
+		log.Printf("⚠ Message %d exhausted all %d retries, giving up", msg.ID, policy.MaxRetries)
+	}
+
+	updateStart := time.Now()
+	err := tx.MarkFailed(ctx, msg.ID, s.instanceID, string(msg.Status), retryCount, nextAttemptAt)
+	attempt.DBUpdate = time.Since(updateStart)
+	if err != nil {
+		return fmt.Errorf("failed to record failed attempt: %w", err)
+	}
+
+	msg.RetryCount = retryCount
+	msg.NextAttemptAt = nextAttemptAt
+
+	return nil
+}
+
 // StartScheduler restarts the automatic message processing
-func (s *Service) StartScheduler(intervalMinutes, batchSize int) error {
-	if err := s.scheduler.Stop(); err != nil {
-		log.Printf("Warning: failed to stop scheduler before restart: %v", err)
+// The settings are persisted so they survive a restart
+func (s *Service) StartScheduler(ctx context.Context, settings SchedulerSettings) error {
+	if err := settings.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrValidation, err)
+	}
+
+	s.schedulerMu.Lock()
+	defer s.schedulerMu.Unlock()
+
+	if err := s.persistSchedulerOverrides(ctx, settings); err != nil {
+		return fmt.Errorf("failed to persist scheduler settings: %w", err)
 	}
 
-	// Update configuration
-	s.intervalMinutes = intervalMinutes
-	s.messageBatchSize = batchSize
+	return s.restartScheduler(settings)
+}
+
+// restartScheduler stops the scheduler and starts it again with the given settings; must be called with schedulerMu held
+// A tick in progress finishes with the settings it started with, it is not cancelled
+func (s *Service) restartScheduler(settings SchedulerSettings) error {
+	if err := s.scheduler.Drain(); err != nil {
+		log.Printf("Warning: failed to stop scheduler before restart: %v", err)
+	}
 
 	// Start with new parameters
-	task := func(ctx context.Context) error {
-		return s.ProcessUnsentMessages(ctx, s.messageBatchSize)
+	return s.startScheduler(settings)
+}
+
+// startScheduler applies the settings and starts the scheduler on their interval or cron schedule
+// A cron schedule first runs at its next matching time, an interval right away
+func (s *Service) startScheduler(settings SchedulerSettings) error {
+	schedule, err := settings.schedule()
+	if err != nil {
+		return err
+	}
+
+	s.settings.Store(&settings)
+
+	if settings.Cron != "" {
+		s.adaptive.Store(nil)
+		return s.scheduler.StartSchedule(s.scheduledBatch, schedule)
 	}
 
-	return s.scheduler.Start(task, s.intervalMinutes)
+	if s.idleMaxInterval > settings.Interval {
+		adaptive := scheduler.NewAdaptive(settings.Interval, s.idleMaxInterval, idleRunsBeforeBackoff)
+		s.adaptive.Store(adaptive)
+		return s.scheduler.StartAdaptive(s.scheduledBatch, adaptive)
+	}
+
+	s.adaptive.Store(nil)
+	return s.scheduler.Start(s.scheduledBatch, settings.Interval)
 }
 
-// StopScheduler stops the automatic message processing
+// scheduledBatch is the task run by the scheduler, skipped while maintenance mode is enabled
+// or while the scheduler is paused, which is reloaded every tick to pick up pauses from other instances
+// With leader election, only the leader runs it; the leader election service logs every change
+// With the tick lock enabled, it is also skipped while another instance runs a tick
+func (s *Service) scheduledBatch(ctx context.Context) error {
+	release, err := s.beginBatch(ctx)
+	switch {
+	case errors.Is(err, ErrMaintenance):
+		log.Println("Maintenance mode is enabled, skipping batch")
+		return nil
+	case errors.Is(err, ErrSchedulerPaused), errors.Is(err, ErrNotLeader):
+		return nil
+	case errors.Is(err, ErrTickLockHeld):
+		s.setStandby(true)
+		return nil
+	case err != nil:
+		return err
+	}
+	defer release()
+	if s.tickLock {
+		s.setStandby(false)
+	}
+
+	started := time.Now()
+	result, err := s.ProcessUnsentMessages(ctx, s.SchedulerSettings().BatchSize)
+	s.recordRun(ctx, started, result, err)
+	if err != nil && !ctxerr.IsCanceled(err) {
+		s.recordInternal(err)
+	}
+	if adaptive := s.adaptive.Load(); adaptive != nil && err == nil {
+		if result.Claimed == 0 {
+			adaptive.Idle()
+		} else {
+			adaptive.Reset()
+		}
+	}
+	return err
+}
+
+// WakeScheduler returns a scheduler idling at a stretched interval to its base interval
+// and, with immediate dispatch enabled, runs a batch right away; a cron schedule is never run outside its times
+// Called when messages are created on this instance or another one announces new messages
+func (s *Service) WakeScheduler() {
+	if adaptive := s.adaptive.Load(); adaptive != nil && adaptive.Reset() {
+		log.Println("New messages arrived, scheduler back to its base interval")
+		s.scheduler.Wake()
+	}
+
+	if s.notifyDispatch && s.SchedulerSettings().Cron == "" {
+		s.scheduler.Trigger()
+	}
+}
+
+// StopScheduler stops the automatic message processing, cancelling a tick in progress
 func (s *Service) StopScheduler() error {
+	s.schedulerMu.Lock()
+	defer s.schedulerMu.Unlock()
+
 	return s.scheduler.Stop()
 }
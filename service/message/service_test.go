@@ -0,0 +1,416 @@
+package message_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"qubit/service/message"
+	"qubit/service/message/fakes"
+)
+
+// newTestService creates a service on in-memory fakes whose scheduler only runs on New Year
+// so batches run when a test calls them; a repository set in deps is shared rather than created
+func newTestService(t *testing.T, deps message.Deps, opts message.Options) (*message.Service, *fakes.Repository, *fakes.Sender) {
+	t.Helper()
+
+	repo, ok := deps.Repo.(*fakes.Repository)
+	if !ok {
+		repo = fakes.NewRepository()
+	}
+	sender := fakes.NewSender()
+	deps.Repo = repo
+	deps.Providers = sender
+	if deps.Maintenance == nil {
+		deps.Maintenance = &fakes.Maintenance{}
+	}
+
+	opts.Interval = time.Minute
+	opts.Cron = "0 0 1 1 *"
+	opts.BatchSize = 10
+	if opts.InstanceID == "" {
+		opts.InstanceID = "test-instance"
+	}
+	if opts.SendingTimeout == 0 {
+		opts.SendingTimeout = time.Minute
+	}
+	opts.WebhookTimeout = 5 * time.Second
+
+	s := message.NewService(deps, opts)
+	t.Cleanup(func() { _ = s.StopScheduler() })
+
+	return s, repo, sender
+}
+
+func createMessage(t *testing.T, s *message.Service, opts message.CreateOptions) *message.Message {
+	t.Helper()
+
+	msg, err := s.CreateMessage(context.Background(), "+15551234567", "hello", opts)
+	if err != nil {
+		t.Fatalf("CreateMessage() error = %v", err)
+	}
+	return msg
+}
+
+func getMessage(t *testing.T, s *message.Service, id int64) *message.Message {
+	t.Helper()
+
+	msg, err := s.GetMessage(context.Background(), id, nil)
+	if err != nil {
+		t.Fatalf("GetMessage(%d) error = %v", id, err)
+	}
+	return msg
+}
+
+func TestProcessUnsentMessagesSends(t *testing.T) {
+	ctx := context.Background()
+	s, _, sender := newTestService(t, message.Deps{}, message.Options{})
+	msg := createMessage(t, s, message.CreateOptions{})
+
+	result, err := s.ProcessUnsentMessages(ctx, 10)
+	if err != nil {
+		t.Fatalf("ProcessUnsentMessages() error = %v", err)
+	}
+	if result.Claimed != 1 || result.Sent != 1 {
+		t.Errorf("ProcessUnsentMessages() claimed %d and sent %d, want 1 and 1", result.Claimed, result.Sent)
+	}
+
+	got := getMessage(t, s, msg.ID)
+	if got.Status != message.StatusSent || got.MessageID == nil {
+		t.Errorf("status = %s, messageID = %v, want sent with a provider message ID", got.Status, got.MessageID)
+	}
+	if sent := sender.Provider("default").Sent(); len(sent) != 1 || sent[0].PhoneNumber != msg.PhoneNumber {
+		t.Errorf("provider received %v, want the message to %s", sent, msg.PhoneNumber)
+	}
+}
+
+func TestProcessUnsentMessagesRetriesWithBackoff(t *testing.T) {
+	ctx := context.Background()
+	policy := message.RetryPolicy{MaxRetries: 1, Backoff: message.BackoffFixed, BaseDelay: time.Minute, MaxDelay: time.Minute}
+	s, _, sender := newTestService(t, message.Deps{}, message.Options{RetryPolicy: policy})
+	msg := createMessage(t, s, message.CreateOptions{})
+	sender.Provider("default").FailWith(errors.New("connection refused"))
+
+	before := time.Now()
+	result, err := s.ProcessUnsentMessages(ctx, 10)
+	if err != nil {
+		t.Fatalf("ProcessUnsentMessages() error = %v", err)
+	}
+	if result.Retried != 1 {
+		t.Fatalf("ProcessUnsentMessages() retried %d messages, want 1", result.Retried)
+	}
+
+	got := getMessage(t, s, msg.ID)
+	if got.Status != message.StatusPending || got.RetryCount != 1 {
+		t.Errorf("status = %s, retryCount = %d, want pending after 1 failure", got.Status, got.RetryCount)
+	}
+	if got.NextAttemptAt == nil || got.NextAttemptAt.Before(before.Add(30*time.Second)) || got.NextAttemptAt.After(time.Now().Add(time.Minute)) {
+		t.Errorf("nextAttemptAt = %v, want within the jittered minute of backoff", got.NextAttemptAt)
+	}
+
+	// Backed off messages are not claimed before their next attempt
+	result, err = s.ProcessUnsentMessages(ctx, 10)
+	if err != nil {
+		t.Fatalf("ProcessUnsentMessages() error = %v", err)
+	}
+	if result.Claimed != 0 {
+		t.Errorf("ProcessUnsentMessages() claimed %d backed off messages, want 0", result.Claimed)
+	}
+}
+
+func TestProcessUnsentMessagesFailsWithoutRetriesLeft(t *testing.T) {
+	ctx := context.Background()
+	policy := message.RetryPolicy{Backoff: message.BackoffFixed, BaseDelay: time.Minute, MaxDelay: time.Minute}
+	s, _, sender := newTestService(t, message.Deps{}, message.Options{RetryPolicy: policy})
+	msg := createMessage(t, s, message.CreateOptions{Retry: &message.RetryOverride{MaxAttempts: 1}})
+	sender.Provider("default").FailWith(errors.New("connection refused"))
+
+	result, err := s.ProcessUnsentMessages(ctx, 10)
+	if err != nil {
+		t.Fatalf("ProcessUnsentMessages() error = %v", err)
+	}
+	if result.Failed != 1 {
+		t.Errorf("ProcessUnsentMessages() failed %d messages, want 1", result.Failed)
+	}
+
+	if got := getMessage(t, s, msg.ID); got.Status != message.StatusFailed {
+		t.Errorf("status = %s, want %s", got.Status, message.StatusFailed)
+	}
+}
+
+// stallBatch runs a batch of s whose provider hangs, leaving its claims leased to s
+// finish resumes the provider and returns the result of the batch once it ended; the test ends it otherwise
+func stallBatch(t *testing.T, s *message.Service, sender *fakes.Sender) (finish func() *message.BatchResult) {
+	t.Helper()
+
+	hung, resume := sender.Provider("default").Hang()
+	done := make(chan struct{})
+	var result *message.BatchResult
+	go func() {
+		defer close(done)
+		result, _ = s.ProcessUnsentMessages(context.Background(), 10)
+	}()
+	finish = func() *message.BatchResult {
+		resume()
+		<-done
+		return result
+	}
+	t.Cleanup(func() { finish() })
+
+	select {
+	case <-hung:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the stalled batch never reached the provider")
+	}
+	return finish
+}
+
+func TestProcessUnsentMessagesReapsExpiredLeases(t *testing.T) {
+	ctx := context.Background()
+	s, repo, sender := newTestService(t, message.Deps{}, message.Options{})
+	stalled, _, stalledSender := newTestService(t, message.Deps{Repo: repo}, message.Options{InstanceID: "stalled-instance", SendingTimeout: time.Millisecond})
+	msg := createMessage(t, s, message.CreateOptions{})
+
+	// Another instance claimed the message and stopped responding until its lease expired
+	stallBatch(t, stalled, stalledSender)
+	if got := getMessage(t, s, msg.ID); got.Status != message.StatusSending || got.LockedBy == nil || *got.LockedBy != "stalled-instance" {
+		t.Fatalf("status = %s, lockedBy = %v, want sending by stalled-instance", got.Status, got.LockedBy)
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	result, err := s.ProcessUnsentMessages(ctx, 10)
+	if err != nil {
+		t.Fatalf("ProcessUnsentMessages() error = %v", err)
+	}
+	if result.Claimed != 1 || result.Sent != 1 {
+		t.Errorf("ProcessUnsentMessages() claimed %d and sent %d, want 1 and 1", result.Claimed, result.Sent)
+	}
+
+	got := getMessage(t, s, msg.ID)
+	if got.Status != message.StatusSent || got.LockedBy == nil || *got.LockedBy != "test-instance" {
+		t.Errorf("status = %s, lockedBy = %v, want sent by test-instance", got.Status, got.LockedBy)
+	}
+	if sent := sender.Provider("default").Sent(); len(sent) != 1 {
+		t.Errorf("provider received %d messages, want 1", len(sent))
+	}
+}
+
+func TestProcessUnsentMessagesSkipsOutcomesOfLostLeases(t *testing.T) {
+	ctx := context.Background()
+	s, repo, sender := newTestService(t, message.Deps{}, message.Options{})
+	stalled, _, stalledSender := newTestService(t, message.Deps{Repo: repo}, message.Options{InstanceID: "stalled-instance", SendingTimeout: time.Millisecond})
+	msg := createMessage(t, s, message.CreateOptions{})
+
+	// The stalled instance's lease expires and the message is claimed and sent again before its call returns
+	finish := stallBatch(t, stalled, stalledSender)
+	time.Sleep(5 * time.Millisecond)
+	if _, err := s.ProcessUnsentMessages(ctx, 10); err != nil {
+		t.Fatalf("ProcessUnsentMessages() error = %v", err)
+	}
+	sent := getMessage(t, s, msg.ID)
+
+	if result := finish(); result == nil || result.Sent != 0 || result.Failed != 0 {
+		t.Errorf("stalled batch result = %+v, want no outcome stored", result)
+	}
+	got := getMessage(t, s, msg.ID)
+	if got.Status != message.StatusSent || got.RetryCount != 0 || *got.LockedBy != "test-instance" || *got.MessageID != *sent.MessageID {
+		t.Errorf("status = %s, retryCount = %d, messageId = %v, want the outcome of test-instance kept", got.Status, got.RetryCount, got.MessageID)
+	}
+	if attempts := sender.Provider("default").Sent(); len(attempts) != 1 {
+		t.Errorf("provider of test-instance received %d messages, want 1", len(attempts))
+	}
+}
+
+func TestCreateMessageTakesLocaleFromMetadata(t *testing.T) {
+	s, _, _ := newTestService(t, message.Deps{}, message.Options{})
+
+	msg := createMessage(t, s, message.CreateOptions{
+		Translations: map[string]string{"tr": "merhaba"},
+		Metadata:     map[string]string{message.LocaleMetadataKey: "tr-TR"},
+	})
+	if msg.Content != "merhaba" || msg.ContentLocale == nil || *msg.ContentLocale != "tr" {
+		t.Errorf("content = %q, contentLocale = %v, want the tr variant", msg.Content, msg.ContentLocale)
+	}
+
+	msg = createMessage(t, s, message.CreateOptions{
+		Locale:       "en",
+		Translations: map[string]string{"tr": "merhaba"},
+		Metadata:     map[string]string{message.LocaleMetadataKey: "tr"},
+	})
+	if msg.Content != "hello" {
+		t.Errorf("content = %q, want the default content for the given locale", msg.Content)
+	}
+}
+
+// tenants is a message.TenantSettings with fixed default metadata and quotas per tenant
+type tenants struct {
+	defaults map[int64]map[string]string
+	quotas   map[int64]message.TenantQuota
+}
+
+func (t tenants) DefaultMetadata(tenantID int64) map[string]string {
+	return t.defaults[tenantID]
+}
+
+func (t tenants) Quota(tenantID int64) (message.TenantQuota, bool) {
+	quota, ok := t.quotas[tenantID]
+	return quota, ok
+}
+
+func TestCreateMessageTakesLocaleFromTenantDefaults(t *testing.T) {
+	s, _, _ := newTestService(t, message.Deps{
+		Tenants: tenants{defaults: map[int64]map[string]string{7: {message.LocaleMetadataKey: "tr"}}},
+	}, message.Options{})
+	tenantID := int64(7)
+
+	msg := createMessage(t, s, message.CreateOptions{
+		TenantID:     &tenantID,
+		Translations: map[string]string{"tr": "merhaba"},
+	})
+	if msg.Content != "merhaba" || msg.Metadata[message.LocaleMetadataKey] != "tr" {
+		t.Errorf("content = %q, metadata = %v, want the tr variant of the tenant default", msg.Content, msg.Metadata)
+	}
+
+	msg = createMessage(t, s, message.CreateOptions{
+		TenantID:     &tenantID,
+		Translations: map[string]string{"tr": "merhaba"},
+		Metadata:     map[string]string{message.LocaleMetadataKey: "en"},
+	})
+	if msg.Content != "hello" {
+		t.Errorf("content = %q, want the default content for the locale of the message", msg.Content)
+	}
+}
+
+func TestTenantScoping(t *testing.T) {
+	ctx := context.Background()
+	s, _, _ := newTestService(t, message.Deps{}, message.Options{})
+
+	tenantA, tenantB := int64(1), int64(2)
+	ofA := createMessage(t, s, message.CreateOptions{TenantID: &tenantA})
+	ofB := createMessage(t, s, message.CreateOptions{TenantID: &tenantB})
+	operator := createMessage(t, s, message.CreateOptions{})
+
+	t.Run("get", func(t *testing.T) {
+		if _, err := s.GetMessage(ctx, ofA.ID, &tenantA); err != nil {
+			t.Errorf("GetMessage() of own message error = %v", err)
+		}
+		for _, id := range []int64{ofB.ID, operator.ID} {
+			if _, err := s.GetMessage(ctx, id, &tenantA); !errors.Is(err, message.ErrMessageNotFound) {
+				t.Errorf("GetMessage(%d) of another tenant error = %v, want %v", id, err, message.ErrMessageNotFound)
+			}
+		}
+		if _, err := s.GetMessage(ctx, ofB.ID, nil); err != nil {
+			t.Errorf("GetMessage() without a tenant error = %v", err)
+		}
+	})
+
+	t.Run("list", func(t *testing.T) {
+		list, err := s.ListMessages(ctx, message.ListFilter{TenantID: &tenantA})
+		if err != nil {
+			t.Fatalf("ListMessages() error = %v", err)
+		}
+		if len(list) != 1 || list[0].ID != ofA.ID {
+			t.Errorf("ListMessages() of tenant %d returned %d messages, want only message %d", tenantA, len(list), ofA.ID)
+		}
+
+		all, err := s.ListMessages(ctx, message.ListFilter{})
+		if err != nil {
+			t.Fatalf("ListMessages() error = %v", err)
+		}
+		if len(all) != 3 {
+			t.Errorf("ListMessages() without a tenant returned %d messages, want 3", len(all))
+		}
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		if _, err := s.CancelMessage(ctx, ofB.ID, &tenantA); !errors.Is(err, message.ErrMessageNotFound) {
+			t.Errorf("CancelMessage() of another tenant error = %v, want %v", err, message.ErrMessageNotFound)
+		}
+		if got := getMessage(t, s, ofB.ID); got.Status != message.StatusPending {
+			t.Errorf("status of another tenant's message = %s, want %s", got.Status, message.StatusPending)
+		}
+
+		cancelled, err := s.CancelMessage(ctx, ofA.ID, &tenantA)
+		if err != nil {
+			t.Fatalf("CancelMessage() of own message error = %v", err)
+		}
+		if cancelled.Status != message.StatusCancelled {
+			t.Errorf("status = %s, want %s", cancelled.Status, message.StatusCancelled)
+		}
+	})
+}
+
+func TestUpsertMessageKeepsMessagesOfAnotherTenant(t *testing.T) {
+	ctx := context.Background()
+	s, _, _ := newTestService(t, message.Deps{}, message.Options{})
+
+	const uuid = "4b0e7c1e-6a53-4d6e-9a3e-1f2a7c9d8e01"
+	tenantA, tenantB := int64(1), int64(2)
+	owned, _, err := s.UpsertMessage(ctx, uuid, "+15551234567", "hello", message.CreateOptions{TenantID: &tenantA})
+	if err != nil {
+		t.Fatalf("UpsertMessage() error = %v", err)
+	}
+
+	for _, tenantID := range []*int64{&tenantB, nil} {
+		_, _, err := s.UpsertMessage(ctx, uuid, "+15557654321", "hijacked", message.CreateOptions{TenantID: tenantID})
+		if !errors.Is(err, message.ErrUUIDTaken) {
+			t.Errorf("UpsertMessage() by tenant %v error = %v, want %v", tenantID, err, message.ErrUUIDTaken)
+		}
+	}
+	if got := getMessage(t, s, owned.ID); got.Content != "hello" {
+		t.Errorf("content = %q, want the message of tenant %d unchanged", got.Content, tenantA)
+	}
+}
+
+func TestTenantQuota(t *testing.T) {
+	ctx := context.Background()
+	limited, daily, unlimited := int64(1), int64(2), int64(3)
+	s, _, _ := newTestService(t, message.Deps{Tenants: tenants{quotas: map[int64]message.TenantQuota{
+		limited: {RateLimitPerMinute: 2},
+		daily:   {DailyMessages: 1},
+	}}}, message.Options{})
+
+	for range 2 {
+		createMessage(t, s, message.CreateOptions{TenantID: &limited})
+	}
+	if _, err := s.CreateMessage(ctx, "+15551234567", "hello", message.CreateOptions{TenantID: &limited}); !errors.Is(err, message.ErrTenantRateLimited) {
+		t.Errorf("CreateMessage() over the rate limit error = %v, want %v", err, message.ErrTenantRateLimited)
+	}
+
+	createMessage(t, s, message.CreateOptions{TenantID: &daily})
+	if _, err := s.CreateMessage(ctx, "+15551234567", "hello", message.CreateOptions{TenantID: &daily}); !errors.Is(err, message.ErrTenantDailyQuota) {
+		t.Errorf("CreateMessage() over the daily quota error = %v, want %v", err, message.ErrTenantDailyQuota)
+	}
+
+	// Neither a tenant without a quota nor operator keys are limited
+	for range 3 {
+		createMessage(t, s, message.CreateOptions{TenantID: &unlimited})
+		createMessage(t, s, message.CreateOptions{})
+	}
+}
+
+func TestTenantQuotaLimitsOnlyUpsertInserts(t *testing.T) {
+	ctx := context.Background()
+	tenantID := int64(1)
+	s, repo, _ := newTestService(t, message.Deps{Tenants: tenants{quotas: map[int64]message.TenantQuota{
+		tenantID: {DailyMessages: 1},
+	}}}, message.Options{})
+
+	const synced, refused = "4b0e7c1e-6a53-4d6e-9a3e-1f2a7c9d8e01", "4b0e7c1e-6a53-4d6e-9a3e-1f2a7c9d8e02"
+	opts := message.CreateOptions{TenantID: &tenantID}
+	if _, _, err := s.UpsertMessage(ctx, synced, "+15551234567", "hello", opts); err != nil {
+		t.Fatalf("UpsertMessage() error = %v", err)
+	}
+
+	// Re-syncing the message takes no quota, inserting another one is refused before it is stored
+	if _, created, err := s.UpsertMessage(ctx, synced, "+15551234567", "updated", opts); err != nil || created {
+		t.Errorf("UpsertMessage() of the existing message created = %v, error = %v, want an update", created, err)
+	}
+	if _, _, err := s.UpsertMessage(ctx, refused, "+15551234567", "hello", opts); !errors.Is(err, message.ErrTenantDailyQuota) {
+		t.Errorf("UpsertMessage() over the daily quota error = %v, want %v", err, message.ErrTenantDailyQuota)
+	}
+	if exists, _ := repo.ExistsByUUID(ctx, refused); exists {
+		t.Error("the refused message was stored")
+	}
+}
@@ -0,0 +1,78 @@
+package message
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// statsLatencyWindow is the window the average webhook latency of Stats is computed over
+const statsLatencyWindow = time.Hour
+
+// Stats is an overview of the message pipeline for administrators
+type Stats struct {
+	// ByStatus counts the messages per status, statuses without messages are missing
+	ByStatus map[Status]int64
+
+	SentLastHour int64
+	SentLastDay  int64
+
+	// AvgWebhookLatency is the average webhook call duration of the attempts of the last hour
+	AvgWebhookLatency time.Duration
+
+	// Backlog counts the pending messages that are due now
+	Backlog int64
+	// OldestDue is when the oldest due pending message became due, nil without backlog
+	OldestDue *time.Time
+	// BacklogAge is how long the oldest due pending message has been waiting
+	BacklogAge time.Duration
+
+	Scheduler SchedulerStatus
+	// LastBatch is the last batch of this instance that claimed messages, nil if none did yet
+	LastBatch *CompletedBatch
+	// Live is the send activity of this instance, see LiveStats
+	Live []WindowStats
+}
+
+// CompletedBatch is a batch that ran to completion
+type CompletedBatch struct {
+	FinishedAt time.Time
+	Result     BatchResult
+}
+
+// GetStats aggregates message counts, recent sends, webhook latency and the backlog from the database
+// and adds the scheduler state and the send activity of this instance
+// Test messages are skipped unless includeTest is set
+func (s *Service) GetStats(ctx context.Context, includeTest bool) (*Stats, error) {
+	dbStats, err := s.repo.MessageStats(ctx, includeTest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get message stats: %w", err)
+	}
+
+	attemptStats, err := s.GetAttemptStats(ctx, statsLatencyWindow, includeTest)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &Stats{
+		ByStatus:          make(map[Status]int64, len(dbStats.ByStatus)),
+		SentLastHour:      dbStats.SentLastHour,
+		SentLastDay:       dbStats.SentLastDay,
+		AvgWebhookLatency: attemptStats.Webhook.Avg,
+		Backlog:           dbStats.Backlog,
+		OldestDue:         dbStats.OldestDue,
+		Scheduler:         s.SchedulerStatus(),
+		LastBatch:         s.lastBatch.Load(),
+		Live:              s.LiveStats(),
+	}
+
+	for status, count := range dbStats.ByStatus {
+		stats.ByStatus[Status(status)] = count
+	}
+
+	if stats.OldestDue != nil {
+		stats.BacklogAge = max(time.Since(*stats.OldestDue), 0)
+	}
+
+	return stats, nil
+}
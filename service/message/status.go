@@ -0,0 +1,59 @@
+package message
+
+import (
+	"fmt"
+)
+
+// Status represents the lifecycle state of a message
+type Status string
+
+// Message statuses
+const (
+	StatusPending   Status = "pending"
+	StatusSending   Status = "sending"
+	StatusSent      Status = "sent"
+	StatusFailed    Status = "failed"
+	StatusCancelled Status = "cancelled"
+	StatusThrottled Status = "throttled"
+	// StatusQuarantined holds a message linking outside the URL allow-list for review, it is never sent
+	StatusQuarantined Status = "quarantined"
+)
+
+// transitions lists the allowed status transitions
+var transitions = map[Status][]Status{
+	StatusPending:     {StatusSending, StatusCancelled},
+	StatusSending:     {StatusSent, StatusPending, StatusFailed, StatusThrottled, StatusQuarantined},
+	StatusSent:        {},
+	StatusFailed:      {StatusPending},
+	StatusCancelled:   {},
+	StatusThrottled:   {},
+	StatusQuarantined: {},
+}
+
+// ParseStatus converts a string into a Status
+func ParseStatus(value string) (Status, error) {
+	status := Status(value)
+	if _, ok := transitions[status]; !ok {
+		return "", fmt.Errorf("unknown message status %q", value)
+	}
+	return status, nil
+}
+
+// CanTransitionTo reports whether a message in status s may move to next
+func (s Status) CanTransitionTo(next Status) bool {
+	for _, allowed := range transitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// TransitionTo moves the message to the next status if the transition is allowed
+func (m *Message) TransitionTo(next Status) error {
+	if !m.Status.CanTransitionTo(next) {
+		return fmt.Errorf("invalid status transition from %s to %s", m.Status, next)
+	}
+	m.Status = next
+	return nil
+}
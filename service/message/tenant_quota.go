@@ -0,0 +1,48 @@
+package message
+
+import (
+	"context"
+	"fmt"
+
+	"qubit/pkg/apperr"
+)
+
+// Tenant quota errors, answered with 429
+var (
+	ErrTenantDailyQuota  = apperr.NewRateLimited("tenant_daily_quota_exceeded", "tenant daily message quota exceeded")
+	ErrTenantRateLimited = apperr.NewRateLimited("tenant_rate_limited", "tenant message rate limit exceeded")
+)
+
+// TenantQuota caps the messages a tenant creates; a zero field leaves that limit off
+type TenantQuota struct {
+	DailyMessages      int // messages per UTC day
+	RateLimitPerMinute int // messages within any minute
+}
+
+// checkTenantQuota returns ErrTenantDailyQuota or ErrTenantRateLimited if creating count more messages would
+// take the tenant over its quotas; messages of no tenant, or of a tenant missing from the cache, are not limited
+// Usage is counted from committed messages, so concurrent requests can overshoot by the messages in flight
+func (s *Service) checkTenantQuota(ctx context.Context, tenantID *int64, count int) error {
+	if s.tenants == nil || tenantID == nil {
+		return nil
+	}
+
+	quota, ok := s.tenants.Quota(*tenantID)
+	if !ok || (quota.DailyMessages <= 0 && quota.RateLimitPerMinute <= 0) {
+		return nil
+	}
+
+	usage, err := s.repo.CountTenantUsage(ctx, *tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to check tenant quota: %w", err)
+	}
+
+	if quota.DailyMessages > 0 && usage.Today+int64(count) > int64(quota.DailyMessages) {
+		return fmt.Errorf("%w: %d of %d messages created today", ErrTenantDailyQuota, usage.Today, quota.DailyMessages)
+	}
+	if quota.RateLimitPerMinute > 0 && usage.LastMinute+int64(count) > int64(quota.RateLimitPerMinute) {
+		return fmt.Errorf("%w: %d of %d messages created in the last minute", ErrTenantRateLimited, usage.LastMinute, quota.RateLimitPerMinute)
+	}
+
+	return nil
+}
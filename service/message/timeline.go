@@ -0,0 +1,147 @@
+package message
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Timeline event types
+const (
+	TimelineCreated        = "created"
+	TimelineScheduled      = "scheduled"
+	TimelineLocked         = "locked"
+	TimelineAttemptFailed  = "attempt_failed"
+	TimelineAttemptSent    = "attempt_succeeded"
+	TimelineRetryScheduled = "retry_scheduled"
+	TimelineSent           = "sent"
+	TimelineFailed         = "failed"
+	TimelineReplyReceived  = "reply_received"
+)
+
+// TimelineEvent is a single step in the life of a message
+type TimelineEvent struct {
+	At     time.Time
+	Type   string
+	Detail string
+}
+
+// Timeline is the chronological history of a message assembled from its stored traces
+type Timeline struct {
+	Message *Message
+	Events  []TimelineEvent
+}
+
+// GetTimeline assembles the history of a message from the message row, its send attempts and correlated replies
+// Archived messages are looked up in the archive, their attempts and replies stay in place
+// A non-nil tenantID scopes the lookup to the messages of that tenant
+// Returns ErrMessageNotFound if the message does not exist or belongs to another tenant
+func (s *Service) GetTimeline(ctx context.Context, id int64, tenantID *int64) (*Timeline, error) {
+	msg, err := s.GetMessage(ctx, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The message was already looked up within the tenant
+	attempts, err := s.GetAttempts(ctx, id, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	replies, err := s.repo.ListInboundByReplyTo(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get replies: %w", err)
+	}
+
+	events := []TimelineEvent{{
+		At:     msg.CreatedAt,
+		Type:   TimelineCreated,
+		Detail: "created for " + msg.PhoneNumber,
+	}}
+	if msg.Provider != nil {
+		events[0].Detail += " pinned to provider " + *msg.Provider
+	}
+	if msg.ScheduledAt != nil {
+		events = append(events, TimelineEvent{
+			At:     *msg.ScheduledAt,
+			Type:   TimelineScheduled,
+			Detail: "became due at its scheduled time",
+		})
+	}
+
+	var lastFinished time.Time
+	for _, a := range attempts {
+		events = append(events, TimelineEvent{
+			At:     a.StartedAt,
+			Type:   TimelineLocked,
+			Detail: fmt.Sprintf("attempt %d locked after waiting %s in queue", a.AttemptNumber, a.QueueWait),
+		})
+
+		lastFinished = a.StartedAt.Add(a.LockToSend + a.Webhook)
+		if a.Success {
+			events = append(events, TimelineEvent{
+				At:     lastFinished,
+				Type:   TimelineAttemptSent,
+				Detail: fmt.Sprintf("attempt %d accepted by webhook in %s", a.AttemptNumber, a.Webhook),
+			})
+			continue
+		}
+
+		detail := fmt.Sprintf("attempt %d failed", a.AttemptNumber)
+		if a.Error != nil {
+			detail += ": " + *a.Error
+		}
+		events = append(events, TimelineEvent{
+			At:     lastFinished,
+			Type:   TimelineAttemptFailed,
+			Detail: detail,
+		})
+	}
+
+	switch msg.Status {
+	case StatusPending:
+		if msg.RetryCount > 0 && msg.NextAttemptAt != nil {
+			events = append(events, TimelineEvent{
+				At:     *msg.NextAttemptAt,
+				Type:   TimelineRetryScheduled,
+				Detail: fmt.Sprintf("attempt %d due", msg.RetryCount+1),
+			})
+		}
+	case StatusSent:
+		if msg.ProcessedAt != nil {
+			detail := "delivered"
+			if msg.MessageID != nil {
+				detail += " as " + *msg.MessageID
+			}
+			events = append(events, TimelineEvent{
+				At:     *msg.ProcessedAt,
+				Type:   TimelineSent,
+				Detail: detail,
+			})
+		}
+	case StatusFailed:
+		events = append(events, TimelineEvent{
+			At:     lastFinished,
+			Type:   TimelineFailed,
+			Detail: fmt.Sprintf("gave up after %d attempts", len(attempts)),
+		})
+	}
+
+	for _, r := range replies {
+		events = append(events, TimelineEvent{
+			At:     r.ReceivedAt,
+			Type:   TimelineReplyReceived,
+			Detail: "reply received: " + r.Content,
+		})
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].At.Before(events[j].At)
+	})
+
+	return &Timeline{
+		Message: msg,
+		Events:  events,
+	}, nil
+}
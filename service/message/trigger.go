@@ -0,0 +1,95 @@
+package message
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"qubit/pkg/apperr"
+	"qubit/pkg/ctxerr"
+	"qubit/pkg/scheduler"
+)
+
+// Errors of a batch refused by the checks every batch passes, see beginBatch
+var (
+	ErrSchedulerPaused = apperr.NewConflict("scheduler_paused", "scheduler is paused, resume it first")
+	ErrMaintenance     = apperr.NewConflict(apperr.CodeMaintenance, "maintenance mode is enabled")
+	ErrNotLeader       = apperr.NewConflict("scheduler_not_leader", "this instance is not the scheduler leader")
+	ErrTickLockHeld    = apperr.NewConflict("scheduler_tick_locked", "another instance holds the scheduler tick lock")
+)
+
+// beginBatch runs the checks a batch must pass, scheduled or triggered: no maintenance mode, no operator
+// pause, leadership when leader election is enabled and, with the tick lock enabled, the lock
+// Returns the function releasing the lock once the batch is done, or the error of the first failed check
+func (s *Service) beginBatch(ctx context.Context) (release func(), err error) {
+	if s.maintenance.Enabled() {
+		return nil, ErrMaintenance
+	}
+
+	s.syncSchedulerPause(ctx)
+	if s.paused.Load() != nil {
+		return nil, ErrSchedulerPaused
+	}
+
+	if s.leadership != nil && !s.leadership.IsLeader() {
+		return nil, ErrNotLeader
+	}
+
+	if !s.tickLock {
+		return func() {}, nil
+	}
+
+	release, acquired, err := s.repo.TrySchedulerLock(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to take scheduler tick lock: %w", err)
+	}
+	if !acquired {
+		return nil, ErrTickLockHeld
+	}
+	return release, nil
+}
+
+// setStandby records whether the last tick found the tick lock held by another instance
+// Only changes are logged, a standby instance would otherwise log every tick
+func (s *Service) setStandby(standby bool) {
+	if s.standby.Swap(standby) == standby {
+		return
+	}
+
+	if standby {
+		log.Println("Scheduler tick lock is held by another instance, standing by")
+	} else {
+		log.Println("✓ Scheduler tick lock acquired, processing batches")
+	}
+}
+
+// TriggerBatch runs a batch right away instead of waiting for the next tick, e.g. to clear a backlog
+// batchSize overrides the configured batch size for this run only, 0 keeps it
+// It passes the same checks as a scheduled batch, so it is refused in maintenance mode, while the scheduler
+// is paused, on an instance that is not the leader and while a batch of any instance holds the tick lock
+// Without the tick lock it waits for a batch running on this instance to finish; the run is recorded like a scheduled one
+// Like a tick it is cancelled after scheduler.TaskTimeout, well within the lease of its claims
+func (s *Service) TriggerBatch(ctx context.Context, batchSize int) (*BatchResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, scheduler.TaskTimeout)
+	defer cancel()
+
+	release, err := s.beginBatch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	if batchSize <= 0 {
+		batchSize = s.SchedulerSettings().BatchSize
+	}
+
+	started := time.Now()
+	result, err := s.ProcessUnsentMessages(ctx, batchSize)
+	s.recordRun(ctx, started, result, err)
+	if err != nil && !ctxerr.IsCanceled(err) {
+		s.recordInternal(err)
+	}
+
+	return result, err
+}
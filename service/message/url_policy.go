@@ -0,0 +1,111 @@
+package message
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"qubit/pkg/apperr"
+)
+
+// ErrURLNotAllowed is returned for content linking to a domain outside the URL allow-list
+// At creation it is wrapped in ErrValidation
+var ErrURLNotAllowed = apperr.NewValidation("url_not_allowed", "URL domain not allowed")
+
+// failureURLNotAllowed is the failure category of attempts stopped by the URL allow-list
+const failureURLNotAllowed = "url_not_allowed"
+
+// URLPolicy restricts the links in message content to approved domains and their subdomains
+// An empty Domains list disables the check
+type URLPolicy struct {
+	Domains []string
+	// Quarantine moves violating messages to quarantined instead of rejecting them
+	Quarantine bool
+	// Recheck checks claimed messages again before sending, the allow-list may have changed since creation
+	Recheck bool
+
+	// QuarantineAtSend quarantines messages violating the allow-list when rechecked, even if Quarantine is off,
+	// so content approved under the previous rules is held for review instead of failed
+	QuarantineAtSend bool
+}
+
+// recheckStatus is the status of a message violating the allow-list when rechecked, empty without a recheck
+func (p URLPolicy) recheckStatus() Status {
+	switch {
+	case !p.Recheck:
+		return ""
+	case p.Quarantine || p.QuarantineAtSend:
+		return StatusQuarantined
+	default:
+		return StatusFailed
+	}
+}
+
+// checkURLs returns ErrURLNotAllowed listing the links in content that point outside the allow-list
+func (s *Service) checkURLs(content string) error {
+	violations := s.urlAllowList.Violations(content)
+	if len(violations) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s", ErrURLNotAllowed, strings.Join(violations, ", "))
+}
+
+// screenURLs applies the URL allow-list to a message being created
+// A violation is a validation error, or quarantines the message when the policy says so
+func (s *Service) screenURLs(msg *Message) error {
+	err := s.checkURLs(msg.Content)
+	if err == nil {
+		return nil
+	}
+
+	if !s.urlQuarantine {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+
+	msg.Status = StatusQuarantined
+	return nil
+}
+
+// recordQuarantineWithTx stores an attempt naming the offending links of each message quarantined at creation
+func (s *Service) recordQuarantineWithTx(ctx context.Context, tx MessageTx, msgs []*Message) error {
+	for _, msg := range msgs {
+		if msg.Status != StatusQuarantined {
+			continue
+		}
+
+		err := s.checkURLs(msg.Content)
+		log.Printf("⚠ Message %d quarantined: %v", msg.ID, err)
+
+		attempt := newAttempt(msg, time.Now())
+		attempt.finish(err)
+		if err := tx.CreateAttempt(ctx, AttemptToPostgres(attempt)); err != nil {
+			return fmt.Errorf("failed to record quarantine: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// blockWithTx stops a claimed message whose content violates the URL allow-list when rechecked
+// It is quarantined or failed without retries, the attempt recorded afterwards names the offending links
+func (s *Service) blockWithTx(ctx context.Context, tx MessageTx, msg *Message, attempt *Attempt, violation error) error {
+	status, _ := s.blocked(violation)
+	if err := msg.TransitionTo(status); err != nil {
+		return err
+	}
+
+	updateStart := time.Now()
+	err := tx.MarkFailed(ctx, msg.ID, s.instanceID, string(msg.Status), msg.RetryCount, nil)
+	attempt.DBUpdate = time.Since(updateStart)
+	if err != nil {
+		return fmt.Errorf("failed to record blocked message: %w", err)
+	}
+	msg.NextAttemptAt = nil
+
+	log.Printf("⚠ Message %d moved to %s: %v", msg.ID, msg.Status, violation)
+
+	return nil
+}
@@ -0,0 +1,29 @@
+package providertemplate
+
+import (
+	"time"
+)
+
+// Template is a template of a template-only provider with the approval status last synced from it
+type Template struct {
+	Provider   string
+	Name       string
+	Language   string
+	ExternalID *string
+	// Body is the template text with numbered parameter slots, e.g. "Hi {{1}}, order {{2}} has shipped"
+	Body       string
+	Parameters int
+	// Status is the approval status: approved, pending, rejected, paused or disabled
+	Status   string
+	SyncedAt time.Time
+}
+
+// SyncResult is the outcome of syncing the templates of one provider
+type SyncResult struct {
+	Provider  string
+	Templates int
+	Approved  int
+	Deleted   int64
+	// Err is set when the provider could not be synced, its stored templates are left unchanged
+	Err error
+}
@@ -0,0 +1,32 @@
+package providertemplate
+
+import (
+	"qubit/env/postgres/providertemplates"
+)
+
+// ToDomain converts a postgres Template model to a domain Template
+func ToDomain(t *providertemplates.Template) *Template {
+	if t == nil {
+		return nil
+	}
+
+	return &Template{
+		Provider:   t.Provider,
+		Name:       t.Name,
+		Language:   t.Language,
+		ExternalID: t.ExternalID,
+		Body:       t.Body,
+		Parameters: t.Parameters,
+		Status:     t.Status,
+		SyncedAt:   t.SyncedAt,
+	}
+}
+
+// ToDomainSlice converts a slice of postgres Template models to domain Templates
+func ToDomainSlice(dbTemplates []*providertemplates.Template) []*Template {
+	result := make([]*Template, 0, len(dbTemplates))
+	for _, t := range dbTemplates {
+		result = append(result, ToDomain(t))
+	}
+	return result
+}
@@ -0,0 +1,156 @@
+package providertemplate
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"qubit/env/postgres"
+	"qubit/env/postgres/providertemplates"
+	"qubit/env/provider"
+	"qubit/pkg/apperr"
+	"qubit/pkg/scheduler"
+)
+
+// ErrNoTemplateProviders is returned when syncing without a template-only provider configured
+var ErrNoTemplateProviders = apperr.NewNotFound("no_template_providers", "no template-only provider is configured")
+
+// Service syncs the templates of template-only providers and their approval status into the database,
+// where message creation and the scheduler check them
+type Service struct {
+	postgres  *postgres.Client
+	providers *provider.Registry
+	scheduler *scheduler.Client
+	names     []string // the providers reporting their templates
+}
+
+// NewService creates a new provider template service and starts syncing every interval
+// The sync only runs when a configured provider reports its templates
+func NewService(postgresClient *postgres.Client, providers *provider.Registry, interval time.Duration) *Service {
+	s := &Service{
+		postgres:  postgresClient,
+		providers: providers,
+		scheduler: scheduler.Run(),
+	}
+
+	for _, name := range providers.Names() {
+		if _, ok := providers.TemplateLister(name); ok {
+			s.names = append(s.names, name)
+		}
+	}
+	if len(s.names) == 0 {
+		return s
+	}
+
+	if err := s.scheduler.Start(s.syncTask, interval); err != nil {
+		log.Printf("Warning: failed to start provider template sync: %v", err)
+	} else {
+		log.Printf("✓ Provider template sync started (interval: %s, providers: %v)", interval, s.names)
+	}
+
+	return s
+}
+
+// Stop stops the template sync, the stored approval status stays until the next sync
+func (s *Service) Stop() error {
+	return s.scheduler.Stop()
+}
+
+// ListTemplates returns the synced templates of all providers
+func (s *Service) ListTemplates(ctx context.Context) ([]*Template, error) {
+	dbTemplates, err := s.postgres.ProviderTemplates.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get provider templates: %w", err)
+	}
+
+	return ToDomainSlice(dbTemplates), nil
+}
+
+// Sync fetches the templates of every template-only provider and stores their approval status
+// A provider that fails keeps its stored templates, the others are synced regardless
+func (s *Service) Sync(ctx context.Context) ([]SyncResult, error) {
+	if len(s.names) == 0 {
+		return nil, ErrNoTemplateProviders
+	}
+
+	stored, err := s.postgres.ProviderTemplates.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get provider templates: %w", err)
+	}
+	previous := make(map[string]string, len(stored))
+	for _, t := range stored {
+		previous[t.Provider+"/"+t.Name+"/"+t.Language] = t.Status
+	}
+
+	results := make([]SyncResult, 0, len(s.names))
+	for _, name := range s.names {
+		result := s.syncProvider(ctx, name, previous)
+		if result.Err != nil {
+			log.Printf("Warning: failed to sync templates of provider %s: %v", name, result.Err)
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// syncProvider replaces the stored templates of a provider with the ones it reports now
+// Status changes of known templates are logged, e.g. a template paused by the provider
+func (s *Service) syncProvider(ctx context.Context, name string, previous map[string]string) SyncResult {
+	result := SyncResult{Provider: name}
+
+	lister, _ := s.providers.TemplateLister(name)
+	templates, err := lister.ListTemplates(ctx)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	now := time.Now()
+	dbTemplates := make([]*providertemplates.Template, 0, len(templates))
+	for _, t := range templates {
+		if status, ok := previous[name+"/"+t.Name+"/"+t.Language]; ok && status != t.Status {
+			log.Printf("Provider %s template %s (%s) changed from %s to %s", name, t.Name, t.Language, status, t.Status)
+		}
+		if t.Status == provider.TemplateApproved {
+			result.Approved++
+		}
+
+		dbTemplate := &providertemplates.Template{
+			Provider:   name,
+			Name:       t.Name,
+			Language:   t.Language,
+			Body:       t.Body,
+			Parameters: t.Parameters,
+			Status:     t.Status,
+			SyncedAt:   now,
+		}
+		if t.ID != "" {
+			id := t.ID
+			dbTemplate.ExternalID = &id
+		}
+		dbTemplates = append(dbTemplates, dbTemplate)
+	}
+
+	result.Templates = len(dbTemplates)
+	result.Deleted, result.Err = s.postgres.ProviderTemplates.Replace(ctx, name, dbTemplates)
+
+	return result
+}
+
+// syncTask runs Sync on the schedule, failures of single providers are logged by Sync
+func (s *Service) syncTask(ctx context.Context) error {
+	results, err := s.Sync(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, result := range results {
+		if result.Err == nil {
+			log.Printf("✓ Synced %d templates of provider %s (%d approved, %d removed)", result.Templates, result.Provider, result.Approved, result.Deleted)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,153 @@
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"qubit/pkg/apperr"
+)
+
+// Header marks a request replayed by support, which is not captured again
+const Header = "X-Qubit-Replay"
+
+// MaxBodySize is the number of body bytes stored per request, longer bodies are truncated
+const MaxBodySize = 64 << 10
+
+// redacted replaces credentials in stored headers and bodies
+const redacted = "[REDACTED]"
+
+// Replay errors
+var (
+	ErrNotFound      = apperr.NewNotFound("rejected_request_not_found", "rejected request not found")
+	ErrNotReplayable = apperr.NewConflict("request_not_replayable", "the body was truncated when the request was captured, it cannot be replayed")
+)
+
+// redactedHeaders are dropped from stored requests, a replay authenticates with the key of the operator
+var redactedHeaders = map[string]bool{
+	"Authorization":       true,
+	"Cookie":              true,
+	"Proxy-Authorization": true,
+	"X-Api-Key":           true,
+}
+
+// redactedFields are the lower-case fragments of JSON field names whose values are redacted
+var redactedFields = []string{"secret", "password", "token", "apikey"}
+
+// Request is an API request rejected as invalid, stored with its credentials redacted
+type Request struct {
+	ID            int64
+	Method        string
+	Path          string // including the query string
+	Headers       map[string]string
+	Body          string
+	BodyTruncated bool
+	Status        int
+	Error         string
+	APIKeyID      *int64 // nil for unauthenticated requests and the bootstrap admin key
+	TenantID      *int64 // the tenant of the key, nil for operator keys
+	CreatedAt     time.Time
+}
+
+// Replayable reports whether the request was captured completely
+func (r *Request) Replayable() error {
+	if r.BodyTruncated {
+		return ErrNotReplayable
+	}
+	return nil
+}
+
+// NewHTTPRequest rebuilds the request for a replay, authenticated with the credentials of the operator
+// Only the credential headers of credentials are used, the stored headers are kept otherwise
+func (r *Request) NewHTTPRequest(ctx context.Context, credentials http.Header) (*http.Request, error) {
+	if err := r.Replayable(); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, r.Method, r.Path, strings.NewReader(r.Body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to rebuild request %d: %w", r.ID, err)
+	}
+
+	for name, value := range r.Headers {
+		req.Header.Set(name, value)
+	}
+	// The length follows the stored body, which differs from the original once redacted
+	req.Header.Del("Content-Length")
+	for name := range redactedHeaders {
+		if value := credentials.Get(name); value != "" {
+			req.Header.Set(name, value)
+		}
+	}
+	req.Header.Set(Header, strconv.FormatInt(r.ID, 10))
+
+	return req, nil
+}
+
+// RedactHeaders returns the first value of every header except credentials
+func RedactHeaders(header http.Header) map[string]string {
+	headers := make(map[string]string, len(header))
+	for name, values := range header {
+		name = http.CanonicalHeaderKey(name)
+		if redactedHeaders[name] || len(values) == 0 {
+			continue
+		}
+		headers[name] = values[0]
+	}
+	return headers
+}
+
+// RedactBody replaces the values of credential fields in a JSON body, other bodies are only made storable as text
+func RedactBody(body []byte) string {
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil || !redactValue(value) {
+		return storableText(body)
+	}
+
+	out, err := json.Marshal(value)
+	if err != nil {
+		return storableText(body)
+	}
+	return string(out)
+}
+
+// storableText replaces invalid UTF-8, e.g. a sequence cut by truncation, and NUL bytes, which text columns reject
+func storableText(b []byte) string {
+	return strings.ReplaceAll(strings.ToValidUTF8(string(b), "\uFFFD"), "\x00", "\uFFFD")
+}
+
+// redactValue redacts the credential fields of value in place and reports whether any was found
+func redactValue(value interface{}) bool {
+	found := false
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if isCredentialField(key) {
+				v[key] = redacted
+				found = true
+				continue
+			}
+			found = redactValue(field) || found
+		}
+	case []interface{}:
+		for _, item := range v {
+			found = redactValue(item) || found
+		}
+	}
+	return found
+}
+
+// isCredentialField reports whether a JSON field name denotes a credential
+func isCredentialField(name string) bool {
+	name = strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(name))
+	for _, fragment := range redactedFields {
+		if strings.Contains(name, fragment) {
+			return true
+		}
+	}
+	return false
+}
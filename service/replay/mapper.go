@@ -0,0 +1,26 @@
+package replay
+
+import (
+	"qubit/env/postgres/rejections"
+)
+
+// ToDomain converts a postgres RejectedRequest model to a domain Request
+func ToDomain(r *rejections.RejectedRequest) *Request {
+	if r == nil {
+		return nil
+	}
+
+	return &Request{
+		ID:            r.ID,
+		Method:        r.Method,
+		Path:          r.Path,
+		Headers:       r.Headers,
+		Body:          r.Body,
+		BodyTruncated: r.BodyTruncated,
+		Status:        r.Status,
+		Error:         r.Error,
+		APIKeyID:      r.APIKeyID,
+		TenantID:      r.TenantID,
+		CreatedAt:     r.CreatedAt,
+	}
+}
@@ -0,0 +1,113 @@
+package replay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"qubit/env/postgres"
+	"qubit/env/postgres/rejections"
+	"qubit/pkg/scheduler"
+)
+
+// maxListedRequests bounds the rejected requests returned by a listing
+const maxListedRequests = 100
+
+// pruneInterval is how often requests older than the retention are deleted
+const pruneInterval = time.Hour
+
+// Service keeps the API requests rejected as invalid for a short window,
+// so support can inspect and replay them instead of asking the caller to resend
+type Service struct {
+	postgres  *postgres.Client
+	scheduler *scheduler.Client
+	retention time.Duration
+}
+
+// NewService creates a new replay service and starts pruning requests older than retention
+func NewService(postgresClient *postgres.Client, retention time.Duration) *Service {
+	s := &Service{
+		postgres:  postgresClient,
+		scheduler: scheduler.Run(),
+		retention: retention,
+	}
+
+	if err := s.scheduler.Start(s.pruneTask, pruneInterval); err != nil {
+		log.Printf("Warning: failed to start rejected request pruning: %v", err)
+	} else {
+		log.Printf("✓ Capturing rejected requests (retention: %s)", retention)
+	}
+
+	return s
+}
+
+// Stop stops pruning old requests
+func (s *Service) Stop() error {
+	return s.scheduler.Stop()
+}
+
+// Capture stores a rejected request, its credentials must already be redacted
+// Failures are logged, capturing never affects the response of the request
+func (s *Service) Capture(ctx context.Context, r *Request) {
+	dbReq := &rejections.RejectedRequest{
+		Method:        r.Method,
+		Path:          r.Path,
+		Headers:       r.Headers,
+		Body:          r.Body,
+		BodyTruncated: r.BodyTruncated,
+		Status:        r.Status,
+		Error:         r.Error,
+		APIKeyID:      r.APIKeyID,
+		TenantID:      r.TenantID,
+		CreatedAt:     r.CreatedAt,
+	}
+	if err := s.postgres.Rejections.Create(ctx, dbReq); err != nil {
+		log.Printf("Warning: %v", err)
+		return
+	}
+	r.ID = dbReq.ID
+}
+
+// ListRequests retrieves the most recent rejected requests, newest first
+func (s *Service) ListRequests(ctx context.Context) ([]*Request, error) {
+	dbReqs, err := s.postgres.Rejections.List(ctx, maxListedRequests)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rejected requests: %w", err)
+	}
+
+	found := make([]*Request, 0, len(dbReqs))
+	for _, r := range dbReqs {
+		found = append(found, ToDomain(r))
+	}
+
+	return found, nil
+}
+
+// GetRequest retrieves a rejected request by ID
+// Returns ErrNotFound if it does not exist or was pruned
+func (s *Service) GetRequest(ctx context.Context, id int64) (*Request, error) {
+	dbReq, err := s.postgres.Rejections.GetByID(ctx, id)
+	if errors.Is(err, rejections.ErrNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rejected request: %w", err)
+	}
+
+	return ToDomain(dbReq), nil
+}
+
+// pruneTask deletes the requests older than the retention
+func (s *Service) pruneTask(ctx context.Context) error {
+	pruned, err := s.postgres.Rejections.DeleteBefore(ctx, time.Now().Add(-s.retention))
+	if err != nil {
+		return err
+	}
+	if pruned > 0 {
+		log.Printf("Pruned %d rejected requests", pruned)
+	}
+
+	return nil
+}
@@ -0,0 +1,227 @@
+package shard
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"qubit/pkg/scheduler"
+)
+
+// Total is the number of shard keys, matching the shard_key column of messages
+const Total = 1024
+
+// Assignment modes
+const (
+	ModeStatic = "static"
+	ModeAuto   = "auto"
+)
+
+// Range is an inclusive range of shard keys
+type Range struct {
+	From int
+	To   int
+}
+
+// String formats the range the way ParseRanges reads it
+func (r Range) String() string {
+	return fmt.Sprintf("%d-%d", r.From, r.To)
+}
+
+// ParseRanges parses comma separated shard ranges, e.g. "0-511,768-1023"; a single key like "7" is a range of one
+// Ranges must not overlap
+func ParseRanges(value string) ([]Range, error) {
+	var ranges []Range
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		from, to, isRange := strings.Cut(part, "-")
+		if !isRange {
+			to = from
+		}
+
+		r := Range{}
+		var err error
+		if r.From, err = strconv.Atoi(strings.TrimSpace(from)); err != nil {
+			return nil, fmt.Errorf("invalid shard range %q", part)
+		}
+		if r.To, err = strconv.Atoi(strings.TrimSpace(to)); err != nil {
+			return nil, fmt.Errorf("invalid shard range %q", part)
+		}
+		if r.From < 0 || r.To >= Total || r.From > r.To {
+			return nil, fmt.Errorf("shard range %q must lie within 0-%d", part, Total-1)
+		}
+
+		ranges = append(ranges, r)
+	}
+
+	if len(ranges) == 0 {
+		return nil, fmt.Errorf("no shard range given")
+	}
+
+	sorted := slices.Clone(ranges)
+	slices.SortFunc(sorted, func(a, b Range) int { return a.From - b.From })
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i].From <= sorted[i-1].To {
+			return nil, fmt.Errorf("shard ranges %s and %s overlap", sorted[i-1], sorted[i])
+		}
+	}
+
+	return ranges, nil
+}
+
+// Registry tracks the live instances sharing the queue, implemented by schedulermembers.Repository
+type Registry interface {
+	// Heartbeat registers the instance as live and returns all live instances, sorted
+	Heartbeat(ctx context.Context, instanceID string, ttl time.Duration) ([]string, error)
+	// Leave removes the instance right away, without waiting for its heartbeat to expire
+	Leave(ctx context.Context, instanceID string) error
+}
+
+// Status is the shard assignment of this instance
+type Status struct {
+	Mode   string
+	Ranges []Range
+	// Members is the number of live instances splitting the shards, 0 with static ranges
+	Members int
+	// Index is the position of this instance among the members
+	Index int
+	// Since is when the ranges were last assigned
+	Since time.Time
+	// LastError is the error of the last heartbeat, nil when it succeeded
+	LastError *string
+}
+
+// Service assigns this instance the shard ranges it claims messages from
+// Static ranges are fixed; in auto mode the shards are split evenly between the live instances,
+// which heartbeat every third of the TTL, so ranges are rebalanced when an instance joins or leaves
+type Service struct {
+	registry   Registry
+	instanceID string
+	ttl        time.Duration
+	scheduler  *scheduler.Client
+
+	mu     sync.RWMutex
+	status Status
+	shards []int
+}
+
+// NewStatic creates a service with fixed shard ranges
+func NewStatic(ranges []Range) *Service {
+	s := &Service{}
+	s.assign(Status{Mode: ModeStatic, Ranges: ranges, Since: time.Now()})
+	return s
+}
+
+// NewAuto creates a service splitting the shards between the instances of registry and starts heartbeating right away
+// Until the first heartbeat succeeds no shard is assigned
+func NewAuto(registry Registry, instanceID string, ttl time.Duration) *Service {
+	s := &Service{
+		registry:   registry,
+		instanceID: instanceID,
+		ttl:        ttl,
+		scheduler:  scheduler.Run(),
+		status: Status{
+			Mode:  ModeAuto,
+			Since: time.Now(),
+		},
+	}
+
+	if err := s.scheduler.Start(s.heartbeat, ttl/3); err != nil {
+		log.Printf("Warning: failed to start shard assignment: %v", err)
+	}
+
+	return s
+}
+
+// Shards returns the shard keys assigned to this instance, empty when none is
+func (s *Service) Shards() []int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.shards
+}
+
+// Status returns the shard assignment of this instance
+func (s *Service) Status() Status {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.status
+}
+
+// Stop stops heartbeating and leaves, so the other instances take over the shards on their next heartbeat
+func (s *Service) Stop() error {
+	if s.scheduler == nil {
+		return nil
+	}
+	if err := s.scheduler.Stop(); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return s.registry.Leave(ctx, s.instanceID)
+}
+
+// heartbeat refreshes the membership of this instance and rebalances its ranges
+// A failed heartbeat keeps the last ranges; once the TTL passes the other instances may take them over as well,
+// which only costs lock contention since claims skip locked messages
+func (s *Service) heartbeat(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, s.ttl/3)
+	defer cancel()
+
+	members, err := s.registry.Heartbeat(ctx, s.instanceID, s.ttl)
+	if err != nil {
+		s.mu.Lock()
+		errMsg := err.Error()
+		s.status.LastError = &errMsg
+		s.mu.Unlock()
+		return err
+	}
+
+	index := slices.Index(members, s.instanceID)
+	if index < 0 {
+		return fmt.Errorf("instance %s missing from scheduler members", s.instanceID)
+	}
+
+	r := Range{From: index * Total / len(members), To: (index+1)*Total/len(members) - 1}
+
+	current := s.Status()
+	if current.Members == len(members) && current.Index == index && len(current.Ranges) == 1 {
+		s.mu.Lock()
+		s.status.LastError = nil
+		s.mu.Unlock()
+		return nil
+	}
+
+	log.Printf("✓ Assigned shards %s (instance %d of %d)", r, index+1, len(members))
+	s.assign(Status{Mode: ModeAuto, Ranges: []Range{r}, Members: len(members), Index: index, Since: time.Now()})
+	return nil
+}
+
+// assign replaces the ranges of this instance
+func (s *Service) assign(status Status) {
+	var shards []int
+	for _, r := range status.Ranges {
+		for key := r.From; key <= r.To; key++ {
+			shards = append(shards, key)
+		}
+	}
+	slices.Sort(shards)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.status = status
+	s.shards = shards
+}
@@ -0,0 +1,158 @@
+package template
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"qubit/pkg/apperr"
+	"qubit/pkg/locale"
+)
+
+// Template constraints
+const (
+	MaxNameLength    = 100
+	MaxContentLength = 1000
+	MaxTranslations  = 20
+)
+
+// Template errors
+var (
+	ErrValidation      = apperr.NewValidation(apperr.CodeValidation, "validation failed")
+	ErrNotFound        = apperr.NewNotFound("template_not_found", "template not found")
+	ErrDuplicateName   = apperr.NewConflict("template_name_taken", "template name already exists")
+	ErrMissingVariable = apperr.NewValidation("missing_template_variable", "missing template variable")
+)
+
+// placeholderRegex matches {{name}} placeholders, whitespace inside the braces is ignored
+var placeholderRegex = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+// Template is named message content with {{placeholders}} filled in when a message is created
+type Template struct {
+	ID      int64
+	Name    string
+	Content string
+	// Translations hold the content per normalized locale, Content is the default variant
+	Translations map[string]string
+	CreatedAt    time.Time
+}
+
+// Validate checks if the template fields are valid
+func (t *Template) Validate() error {
+	if strings.TrimSpace(t.Name) == "" {
+		return fmt.Errorf("template name is required")
+	}
+
+	if len(t.Name) > MaxNameLength {
+		return fmt.Errorf("template name exceeds maximum length of %d characters", MaxNameLength)
+	}
+
+	if t.Content == "" {
+		return fmt.Errorf("template content is required")
+	}
+
+	if utf8.RuneCountInString(t.Content) > MaxContentLength {
+		return fmt.Errorf("template content exceeds maximum length of %d characters", MaxContentLength)
+	}
+
+	if len(t.Translations) > MaxTranslations {
+		return fmt.Errorf("a template has at most %d translations", MaxTranslations)
+	}
+	for variant, content := range t.Translations {
+		if !locale.Valid(variant) {
+			return fmt.Errorf("translation locale %q must be a language optionally followed by subtags, e.g. tr or pt-br", variant)
+		}
+		if content == "" {
+			return fmt.Errorf("translation %q has no content", variant)
+		}
+		if utf8.RuneCountInString(content) > MaxContentLength {
+			return fmt.Errorf("translation %q exceeds maximum length of %d characters", variant, MaxContentLength)
+		}
+	}
+
+	return nil
+}
+
+// Variant returns the locale and content of the translation picked for the locale chain, see locale.Pick
+// Without a match the content is returned as locale.Default
+func (t *Template) Variant(chain []string) (string, string) {
+	if picked, ok := locale.Pick(t.Translations, chain); ok {
+		return picked, t.Translations[picked]
+	}
+	return locale.Default, t.Content
+}
+
+// Placeholders returns the distinct placeholder names of every variant of the template in sorted order
+func (t *Template) Placeholders() []string {
+	contents := []string{t.Content}
+	for _, content := range t.Translations {
+		contents = append(contents, content)
+	}
+
+	seen := make(map[string]bool)
+	var names []string
+	for _, content := range contents {
+		for _, name := range placeholders(content) {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Render fills in every placeholder of the default variant from variables, see RenderVariant
+func (t *Template) Render(variables map[string]string) (string, error) {
+	return render(t.Content, variables)
+}
+
+// RenderVariant fills in every placeholder of the variant picked for the locale chain, see Variant
+// Returns the rendered content and the locale of the variant used
+func (t *Template) RenderVariant(chain []string, variables map[string]string) (string, string, error) {
+	variant, content := t.Variant(chain)
+
+	rendered, err := render(content, variables)
+	if err != nil {
+		return "", "", err
+	}
+	return rendered, variant, nil
+}
+
+// placeholders returns the distinct placeholder names of content in sorted order
+func placeholders(content string) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, match := range placeholderRegex.FindAllStringSubmatch(content, -1) {
+		if !seen[match[1]] {
+			seen[match[1]] = true
+			names = append(names, match[1])
+		}
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// render fills in every placeholder of content from variables
+// Returns ErrMissingVariable naming the placeholders without a value; extra variables are ignored
+func render(content string, variables map[string]string) (string, error) {
+	var missing []string
+	for _, name := range placeholders(content) {
+		if _, ok := variables[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return "", fmt.Errorf("%w: %s", ErrMissingVariable, strings.Join(missing, ", "))
+	}
+
+	return placeholderRegex.ReplaceAllStringFunc(content, func(placeholder string) string {
+		return variables[placeholderRegex.FindStringSubmatch(placeholder)[1]]
+	}), nil
+}
@@ -0,0 +1,62 @@
+package template
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRenderVariant(t *testing.T) {
+	tmpl := &Template{
+		Content:      "Your code is {{code}}",
+		Translations: map[string]string{"tr": "Kodunuz {{code}}", "pt": "Seu código é {{code}}"},
+	}
+	variables := map[string]string{"code": "1234"}
+
+	tests := []struct {
+		name        string
+		chain       []string
+		want        string
+		wantVariant string
+	}{
+		{"recipient locale", []string{"tr", "en"}, "Kodunuz 1234", "tr"},
+		{"language of region", []string{"pt-BR", "en"}, "Seu código é 1234", "pt"},
+		{"default content", []string{"de", "en"}, "Your code is 1234", "default"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, variant, err := tmpl.RenderVariant(tt.chain, variables)
+			if err != nil {
+				t.Fatalf("RenderVariant() error = %v", err)
+			}
+			if got != tt.want || variant != tt.wantVariant {
+				t.Errorf("RenderVariant(%v) = %q, %q, want %q, %q", tt.chain, got, variant, tt.want, tt.wantVariant)
+			}
+		})
+	}
+}
+
+func TestRenderVariantMissingVariable(t *testing.T) {
+	tmpl := &Template{Content: "Hello", Translations: map[string]string{"tr": "Merhaba {{name}}"}}
+
+	if _, _, err := tmpl.RenderVariant([]string{"tr"}, nil); !errors.Is(err, ErrMissingVariable) {
+		t.Errorf("RenderVariant() error = %v, want %v", err, ErrMissingVariable)
+	}
+	if got := tmpl.Placeholders(); len(got) != 1 || got[0] != "name" {
+		t.Errorf("Placeholders() = %v, want placeholders of every variant", got)
+	}
+}
+
+func TestValidateTranslations(t *testing.T) {
+	for name, translations := range map[string]map[string]string{
+		"invalid locale": {"english": "Hello"},
+		"empty content":  {"tr": ""},
+	} {
+		t.Run(name, func(t *testing.T) {
+			tmpl := &Template{Name: "otp", Content: "Hello", Translations: translations}
+			if err := tmpl.Validate(); err == nil {
+				t.Error("Validate() error = nil, want an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package template
+
+import (
+	"qubit/env/postgres/templates"
+)
+
+// ToDomain converts a postgres Template model to a domain Template
+func ToDomain(t *templates.Template) *Template {
+	if t == nil {
+		return nil
+	}
+
+	return &Template{
+		ID:           t.ID,
+		Name:         t.Name,
+		Content:      t.Content,
+		Translations: t.Translations,
+		CreatedAt:    t.CreatedAt,
+	}
+}
+
+// ToPostgres converts a domain Template to a postgres Template model
+func ToPostgres(t *Template) *templates.Template {
+	if t == nil {
+		return nil
+	}
+
+	return &templates.Template{
+		ID:           t.ID,
+		Name:         t.Name,
+		Content:      t.Content,
+		Translations: t.Translations,
+		CreatedAt:    t.CreatedAt,
+	}
+}
+
+// ToDomainSlice converts a slice of postgres Templates to domain Templates
+func ToDomainSlice(dbTemplates []*templates.Template) []*Template {
+	if dbTemplates == nil {
+		return nil
+	}
+
+	domainTemplates := make([]*Template, 0, len(dbTemplates))
+	for _, t := range dbTemplates {
+		domainTemplates = append(domainTemplates, ToDomain(t))
+	}
+
+	return domainTemplates
+}
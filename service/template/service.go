@@ -0,0 +1,81 @@
+package template
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"qubit/env/postgres"
+	"qubit/env/postgres/templates"
+	"qubit/pkg/locale"
+)
+
+// Service handles the business logic for message templates
+type Service struct {
+	postgres *postgres.Client
+}
+
+// NewService creates a new template service
+func NewService(postgresClient *postgres.Client) *Service {
+	return &Service{
+		postgres: postgresClient,
+	}
+}
+
+// CreateTemplate stores a new named template with its translations, keyed by locale
+// Locales are normalized, e.g. tr_TR is stored as tr-tr
+func (s *Service) CreateTemplate(ctx context.Context, name, content string, translations map[string]string) (*Template, error) {
+	t := &Template{
+		Name:         name,
+		Content:      content,
+		Translations: make(map[string]string, len(translations)),
+		CreatedAt:    time.Now(),
+	}
+	for variant, translated := range translations {
+		normalized := locale.Normalize(variant)
+		if _, ok := t.Translations[normalized]; ok {
+			return nil, fmt.Errorf("%w: translation locale %q is given more than once", ErrValidation, normalized)
+		}
+		t.Translations[normalized] = translated
+	}
+
+	if err := t.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrValidation, err)
+	}
+
+	dbTemplate := ToPostgres(t)
+	err := s.postgres.Templates.Create(ctx, dbTemplate)
+	if errors.Is(err, templates.ErrDuplicateName) {
+		return nil, fmt.Errorf("%w: %s", ErrDuplicateName, name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create template: %w", err)
+	}
+	t.ID = dbTemplate.ID
+
+	return t, nil
+}
+
+// GetTemplate retrieves a template by ID
+func (s *Service) GetTemplate(ctx context.Context, id int64) (*Template, error) {
+	dbTemplate, err := s.postgres.Templates.GetByID(ctx, id)
+	if errors.Is(err, templates.ErrNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get template: %w", err)
+	}
+
+	return ToDomain(dbTemplate), nil
+}
+
+// ListTemplates retrieves all templates
+func (s *Service) ListTemplates(ctx context.Context) ([]*Template, error) {
+	dbTemplates, err := s.postgres.Templates.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get templates: %w", err)
+	}
+
+	return ToDomainSlice(dbTemplates), nil
+}
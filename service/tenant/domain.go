@@ -0,0 +1,130 @@
+package tenant
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"qubit/pkg/apperr"
+	"qubit/service/apikey"
+	"qubit/service/message"
+)
+
+// Tenant constraints
+const (
+	MaxNameLength         = 100
+	MaxSettings           = 50
+	MaxSettingKeyLength   = 64
+	MaxSettingValueLength = 1000
+	MaxRateLimitPerMinute = 60000
+)
+
+// Tenant errors
+var (
+	ErrValidation    = apperr.NewValidation(apperr.CodeValidation, "validation failed")
+	ErrNotFound      = apperr.NewNotFound("tenant_not_found", "tenant not found")
+	ErrDuplicateName = apperr.NewConflict("tenant_name_taken", "tenant name already exists")
+)
+
+// DefaultKeyScopes are granted to the initial key of a tenant when none are requested
+var DefaultKeyScopes = []string{string(apikey.ScopeMessagesRead), string(apikey.ScopeMessagesWrite)}
+
+// Quotas limit the traffic of a tenant
+type Quotas struct {
+	DailyMessages      int
+	RateLimitPerMinute int
+}
+
+// Validate checks the quota values
+func (q Quotas) Validate() error {
+	if q.DailyMessages <= 0 {
+		return fmt.Errorf("daily message quota must be greater than 0")
+	}
+	if q.RateLimitPerMinute <= 0 || q.RateLimitPerMinute > MaxRateLimitPerMinute {
+		return fmt.Errorf("rate limit per minute must be between 1 and %d", MaxRateLimitPerMinute)
+	}
+	return nil
+}
+
+// QuotaOverride replaces the default quotas of a new tenant, nil fields keep the default
+type QuotaOverride struct {
+	DailyMessages      *int
+	RateLimitPerMinute *int
+}
+
+// apply returns the defaults with the set fields of the override
+func (o *QuotaOverride) apply(defaults Quotas) Quotas {
+	if o == nil {
+		return defaults
+	}
+	if o.DailyMessages != nil {
+		defaults.DailyMessages = *o.DailyMessages
+	}
+	if o.RateLimitPerMinute != nil {
+		defaults.RateLimitPerMinute = *o.RateLimitPerMinute
+	}
+	return defaults
+}
+
+// Tenant is a customer of the platform sending messages with its own API keys
+type Tenant struct {
+	ID       int64
+	Name     string
+	Settings map[string]string
+	// DefaultMetadata is merged into the metadata of every message the tenant creates
+	DefaultMetadata map[string]string
+	Quotas          Quotas
+	CreatedAt       time.Time
+}
+
+// Validate checks if the tenant fields are valid
+func (t *Tenant) Validate() error {
+	name := strings.TrimSpace(t.Name)
+	if name == "" {
+		return fmt.Errorf("tenant name is required")
+	}
+	if len(name) > MaxNameLength {
+		return fmt.Errorf("tenant name exceeds maximum length of %d characters", MaxNameLength)
+	}
+
+	if len(t.Settings) > MaxSettings {
+		return fmt.Errorf("a tenant has at most %d settings", MaxSettings)
+	}
+	for key, value := range t.Settings {
+		if strings.TrimSpace(key) == "" {
+			return fmt.Errorf("setting names must not be empty")
+		}
+		if len(key) > MaxSettingKeyLength {
+			return fmt.Errorf("setting %q exceeds maximum name length of %d characters", key, MaxSettingKeyLength)
+		}
+		if len(value) > MaxSettingValueLength {
+			return fmt.Errorf("setting %q exceeds maximum value length of %d characters", key, MaxSettingValueLength)
+		}
+	}
+
+	if err := message.Metadata(t.DefaultMetadata).Validate(); err != nil {
+		return fmt.Errorf("default %v", err)
+	}
+
+	return t.Quotas.Validate()
+}
+
+// OnboardOptions describe a tenant to provision
+type OnboardOptions struct {
+	Name            string
+	Settings        map[string]string
+	DefaultMetadata map[string]string
+	Quotas          *QuotaOverride
+
+	// KeyScopes are granted to the initial API key, DefaultKeyScopes when empty
+	KeyScopes []string
+	IsTest    bool
+}
+
+// Onboarding is a provisioned tenant with its initial API key
+// The secret is only available here, only its hash is stored
+type Onboarding struct {
+	Tenant *Tenant
+	Key    *apikey.Key
+	Secret string
+}
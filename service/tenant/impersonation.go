@@ -0,0 +1,141 @@
+package tenant
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"qubit/env/postgres/impersonations"
+	"qubit/pkg/apperr"
+	"qubit/service/apikey"
+)
+
+// maxListedImpersonations bounds the audit records returned for a tenant
+const maxListedImpersonations = 100
+
+// ErrImpersonationForbidden is returned when a key that does not grant admin:* tries to act as a tenant
+var ErrImpersonationForbidden = apperr.NewForbidden("impersonation_forbidden", "acting as a tenant requires an admin:* key")
+
+// Impersonation is an audited request of an admin key acting as a tenant
+type Impersonation struct {
+	ID         int64
+	TenantID   int64
+	ActorKeyID *int64 // nil for the bootstrap admin key
+	ActorName  string
+	Method     string
+	Path       string
+	ClientIP   string
+	Status     *int // nil until the request completed
+	CreatedAt  time.Time
+}
+
+// ImpersonationToDomain converts a postgres Impersonation model to a domain Impersonation
+func ImpersonationToDomain(i *impersonations.Impersonation) *Impersonation {
+	if i == nil {
+		return nil
+	}
+
+	return &Impersonation{
+		ID:         i.ID,
+		TenantID:   i.TenantID,
+		ActorKeyID: i.ActorKeyID,
+		ActorName:  i.ActorName,
+		Method:     i.Method,
+		Path:       i.Path,
+		ClientIP:   i.ClientIP,
+		Status:     i.Status,
+		CreatedAt:  i.CreatedAt,
+	}
+}
+
+// StartImpersonation lets an admin key act as a tenant for one request and records it in the audit trail
+// It returns the key to serve the request with, carrying the tenant and the scopes of a tenant key
+// The request must not be served when an error is returned, an impersonation is never left unaudited
+// Returns ErrImpersonationForbidden for non-admin keys and ErrNotFound if the tenant does not exist
+func (s *Service) StartImpersonation(ctx context.Context, admin *apikey.Key, tenantID int64, method, path, clientIP string) (*Impersonation, *apikey.Key, error) {
+	if !admin.HasScope(apikey.ScopeAdmin) || admin.ImpersonatedBy != nil {
+		return nil, nil, ErrImpersonationForbidden
+	}
+
+	if _, ok := s.CachedTenant(tenantID); !ok {
+		// Tenants onboarded a moment ago may not be cached yet
+		if _, err := s.GetTenant(ctx, tenantID); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	scopes := make([]apikey.Scope, 0, len(DefaultKeyScopes))
+	for _, scope := range DefaultKeyScopes {
+		scopes = append(scopes, apikey.Scope(scope))
+	}
+
+	imp := &Impersonation{
+		TenantID:  tenantID,
+		ActorName: admin.Name,
+		Method:    method,
+		Path:      path,
+		ClientIP:  clientIP,
+		CreatedAt: time.Now(),
+	}
+	if admin.ID != 0 {
+		imp.ActorKeyID = &admin.ID
+	}
+
+	dbImp := &impersonations.Impersonation{
+		TenantID:   imp.TenantID,
+		ActorKeyID: imp.ActorKeyID,
+		ActorName:  imp.ActorName,
+		Method:     imp.Method,
+		Path:       imp.Path,
+		ClientIP:   imp.ClientIP,
+		CreatedAt:  imp.CreatedAt,
+	}
+	if err := s.postgres.Impersonations.Create(ctx, dbImp); err != nil {
+		return nil, nil, fmt.Errorf("failed to audit impersonation: %w", err)
+	}
+	imp.ID = dbImp.ID
+
+	log.Printf("AUDIT: key %s acting as tenant %d: %s %s from %s (impersonation %d)", actorLabel(imp), tenantID, method, path, clientIP, imp.ID)
+
+	return imp, admin.ActAs(tenantID, scopes), nil
+}
+
+// FinishImpersonation records the response status of an impersonated request
+func (s *Service) FinishImpersonation(ctx context.Context, imp *Impersonation, status int) {
+	imp.Status = &status
+
+	log.Printf("AUDIT: key %s acting as tenant %d: %s %s completed with status %d (impersonation %d)", actorLabel(imp), imp.TenantID, imp.Method, imp.Path, status, imp.ID)
+
+	if err := s.postgres.Impersonations.SetStatus(ctx, imp.ID, status); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+}
+
+// ListImpersonations retrieves the most recent impersonations of a tenant, newest first
+// Returns ErrNotFound if the tenant does not exist
+func (s *Service) ListImpersonations(ctx context.Context, tenantID int64) ([]*Impersonation, error) {
+	if _, err := s.GetTenant(ctx, tenantID); err != nil {
+		return nil, err
+	}
+
+	dbImps, err := s.postgres.Impersonations.ListByTenant(ctx, tenantID, maxListedImpersonations)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get impersonations: %w", err)
+	}
+
+	found := make([]*Impersonation, 0, len(dbImps))
+	for _, i := range dbImps {
+		found = append(found, ImpersonationToDomain(i))
+	}
+
+	return found, nil
+}
+
+// actorLabel identifies the admin key of an impersonation in audit logs
+func actorLabel(imp *Impersonation) string {
+	if imp.ActorKeyID == nil {
+		return fmt.Sprintf("%q", imp.ActorName)
+	}
+	return fmt.Sprintf("%d (%q)", *imp.ActorKeyID, imp.ActorName)
+}
@@ -0,0 +1,55 @@
+package tenant
+
+import (
+	"qubit/env/postgres/tenants"
+)
+
+// ToDomain converts a postgres Tenant model to a domain Tenant
+func ToDomain(t *tenants.Tenant) *Tenant {
+	if t == nil {
+		return nil
+	}
+
+	return &Tenant{
+		ID:              t.ID,
+		Name:            t.Name,
+		Settings:        t.Settings,
+		DefaultMetadata: t.DefaultMetadata,
+		Quotas: Quotas{
+			DailyMessages:      t.DailyMessageQuota,
+			RateLimitPerMinute: t.RateLimitPerMinute,
+		},
+		CreatedAt: t.CreatedAt,
+	}
+}
+
+// ToPostgres converts a domain Tenant to a postgres Tenant model
+func ToPostgres(t *Tenant) *tenants.Tenant {
+	if t == nil {
+		return nil
+	}
+
+	return &tenants.Tenant{
+		ID:                 t.ID,
+		Name:               t.Name,
+		Settings:           t.Settings,
+		DefaultMetadata:    t.DefaultMetadata,
+		DailyMessageQuota:  t.Quotas.DailyMessages,
+		RateLimitPerMinute: t.Quotas.RateLimitPerMinute,
+		CreatedAt:          t.CreatedAt,
+	}
+}
+
+// ToDomainSlice converts a slice of postgres Tenants to domain Tenants
+func ToDomainSlice(dbTenants []*tenants.Tenant) []*Tenant {
+	if dbTenants == nil {
+		return nil
+	}
+
+	domainTenants := make([]*Tenant, 0, len(dbTenants))
+	for _, t := range dbTenants {
+		domainTenants = append(domainTenants, ToDomain(t))
+	}
+
+	return domainTenants
+}
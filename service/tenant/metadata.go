@@ -0,0 +1,58 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"qubit/env/postgres/tenants"
+	"qubit/service/message"
+)
+
+// DefaultMetadata returns the default metadata of a tenant from the cache, nil for an unknown tenant
+// Implements message.TenantSettings; the returned map is shared and must not be modified
+func (s *Service) DefaultMetadata(tenantID int64) map[string]string {
+	t, ok := s.CachedTenant(tenantID)
+	if !ok {
+		return nil
+	}
+	return t.DefaultMetadata
+}
+
+// Quota returns the message quotas of a tenant from the cache, false for an unknown tenant
+// Implements message.TenantSettings
+func (s *Service) Quota(tenantID int64) (message.TenantQuota, bool) {
+	t, ok := s.CachedTenant(tenantID)
+	if !ok {
+		return message.TenantQuota{}, false
+	}
+	return message.TenantQuota{
+		DailyMessages:      t.Quotas.DailyMessages,
+		RateLimitPerMinute: t.Quotas.RateLimitPerMinute,
+	}, true
+}
+
+// SetDefaultMetadata replaces the default metadata merged into the messages the tenant creates
+// Messages created before keep their metadata; an empty map stops adding defaults
+// Returns ErrNotFound if the tenant does not exist
+func (s *Service) SetDefaultMetadata(ctx context.Context, tenantID int64, metadata map[string]string) (*Tenant, error) {
+	if err := message.Metadata(metadata).Validate(); err != nil {
+		return nil, fmt.Errorf("%w: default %v", ErrValidation, err)
+	}
+
+	dbTenant, err := s.postgres.Tenants.SetDefaultMetadata(ctx, tenantID, metadata)
+	if errors.Is(err, tenants.ErrNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("Tenant %d set %d default metadata fields", tenantID, len(dbTenant.DefaultMetadata))
+
+	// Other instances are notified by the tenants trigger, this one does not wait for the round trip
+	s.cache.Invalidate()
+
+	return ToDomain(dbTenant), nil
+}
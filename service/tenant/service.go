@@ -0,0 +1,175 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"qubit/env/postgres"
+	"qubit/env/postgres/tenants"
+	"qubit/pkg/warmcache"
+	"qubit/service/apikey"
+)
+
+// Service handles the business logic for tenant onboarding
+type Service struct {
+	postgres      *postgres.Client
+	defaultQuotas Quotas
+
+	// cache serves tenant settings and quotas to per-message processing without a database round trip
+	cache *warmcache.Cache[int64, *Tenant]
+}
+
+// NewService creates a new tenant service and warms the tenant cache
+// defaultQuotas apply to tenants onboarded without a quota override
+// The cache is reloaded on InvalidateCache and at least every cacheRefresh
+func NewService(postgresClient *postgres.Client, defaultQuotas Quotas, cacheRefresh time.Duration) *Service {
+	s := &Service{
+		postgres:      postgresClient,
+		defaultQuotas: defaultQuotas,
+	}
+
+	s.cache = warmcache.New("Tenant", cacheRefresh, s.loadTenants)
+	s.cache.Start()
+
+	return s
+}
+
+// Stop stops refreshing the tenant cache
+func (s *Service) Stop() {
+	s.cache.Stop()
+}
+
+// CachedTenant returns a tenant from the in-memory cache, for lookups on the message processing path
+// Changes reach the cache within the refresh interval, usually right away through InvalidateCache
+// The returned tenant is shared and must not be modified
+func (s *Service) CachedTenant(id int64) (*Tenant, bool) {
+	return s.cache.Get(id)
+}
+
+// InvalidateCache reloads the tenant cache in the background, e.g. when the tenants table changed
+func (s *Service) InvalidateCache() {
+	s.cache.Invalidate()
+}
+
+// loadTenants loads every tenant into the cache
+func (s *Service) loadTenants(ctx context.Context) (map[int64]*Tenant, error) {
+	dbTenants, err := s.postgres.Tenants.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load tenants: %w", err)
+	}
+
+	byID := make(map[int64]*Tenant, len(dbTenants))
+	for _, t := range ToDomainSlice(dbTenants) {
+		byID[t.ID] = t
+	}
+
+	return byID, nil
+}
+
+// Onboard provisions a tenant with its settings, quotas and initial API key in one transaction
+// Either everything is stored or nothing is, a failed onboarding can simply be retried
+func (s *Service) Onboard(ctx context.Context, opts OnboardOptions) (*Onboarding, error) {
+	t := &Tenant{
+		Name:            strings.TrimSpace(opts.Name),
+		Settings:        opts.Settings,
+		DefaultMetadata: opts.DefaultMetadata,
+		Quotas:          opts.Quotas.apply(s.defaultQuotas),
+		CreatedAt:       time.Now(),
+	}
+	if t.Settings == nil {
+		t.Settings = map[string]string{}
+	}
+	if t.DefaultMetadata == nil {
+		t.DefaultMetadata = map[string]string{}
+	}
+
+	if err := t.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrValidation, err)
+	}
+
+	scopes := opts.KeyScopes
+	if len(scopes) == 0 {
+		scopes = DefaultKeyScopes
+	}
+	for _, scope := range scopes {
+		if scope == string(apikey.ScopeAdmin) {
+			return nil, fmt.Errorf("%w: tenant keys cannot be granted the %s scope", ErrValidation, apikey.ScopeAdmin)
+		}
+	}
+
+	key, secret, err := apikey.NewKey(t.Name, scopes, opts.IsTest)
+	if errors.Is(err, apikey.ErrValidation) {
+		return nil, fmt.Errorf("%w: %v", ErrValidation, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.postgres.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		// Rollback is a no-op once the transaction is committed
+		_ = tx.Rollback(ctx)
+	}()
+
+	dbTenant := ToPostgres(t)
+	err = s.postgres.Tenants.CreateWithTx(ctx, tx, dbTenant)
+	if errors.Is(err, tenants.ErrDuplicateName) {
+		return nil, fmt.Errorf("%w: %s", ErrDuplicateName, t.Name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tenant: %w", err)
+	}
+	t.ID = dbTenant.ID
+
+	key.TenantID = &t.ID
+	dbKey := apikey.ToPostgres(key, secret)
+	if err := s.postgres.APIKeys.CreateWithTx(ctx, tx, dbKey); err != nil {
+		return nil, fmt.Errorf("failed to create tenant api key: %w", err)
+	}
+	key.ID = dbKey.ID
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit tenant onboarding: %w", err)
+	}
+
+	log.Printf("Tenant %d (%s) onboarded with API key %d and scopes %v", t.ID, t.Name, key.ID, scopes)
+
+	// Other instances are notified by the tenants trigger, this one does not wait for the round trip
+	s.cache.Invalidate()
+
+	return &Onboarding{
+		Tenant: t,
+		Key:    key,
+		Secret: secret,
+	}, nil
+}
+
+// GetTenant retrieves a tenant by ID
+func (s *Service) GetTenant(ctx context.Context, id int64) (*Tenant, error) {
+	dbTenant, err := s.postgres.Tenants.GetByID(ctx, id)
+	if errors.Is(err, tenants.ErrNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant: %w", err)
+	}
+
+	return ToDomain(dbTenant), nil
+}
+
+// ListTenants retrieves all tenants
+func (s *Service) ListTenants(ctx context.Context) ([]*Tenant, error) {
+	dbTenants, err := s.postgres.Tenants.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenants: %w", err)
+	}
+
+	return ToDomainSlice(dbTenants), nil
+}
@@ -0,0 +1,175 @@
+package tenant
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"qubit/env/postgres/signingkeys"
+	"qubit/pkg/apperr"
+	"qubit/pkg/signing"
+)
+
+// SignatureHeader carries the signatures of a callback sent to a tenant
+const SignatureHeader = "X-Qubit-Signature"
+
+// signingKeyGrace is how long a rotated key keeps signing callbacks next to its successor,
+// giving the tenant time to switch its receivers to the new key
+const signingKeyGrace = 24 * time.Hour
+
+// Signing key errors
+var (
+	ErrNoTenant     = apperr.NewForbidden("tenant_required", "signing keys belong to tenants, use a tenant key or act as a tenant")
+	ErrNoSigningKey = apperr.NewNotFound("signing_key_not_found", "tenant has no signing key")
+)
+
+// SigningKey signs the callbacks of a tenant
+// Secret is the HMAC secret or the Ed25519 private key seed and must only leave the service on creation
+type SigningKey struct {
+	ID        int64
+	TenantID  int64
+	Algorithm signing.Algorithm
+	Secret    []byte
+	PublicKey []byte // nil for HMAC algorithms
+	CreatedAt time.Time
+	ExpiresAt *time.Time // nil for the active key
+}
+
+// Active reports whether the key is the current key of its tenant, rotated keys sign until they expire
+func (k *SigningKey) Active() bool {
+	return k.ExpiresAt == nil
+}
+
+// SigningKeyToDomain converts a postgres SigningKey model to a domain SigningKey
+func SigningKeyToDomain(k *signingkeys.SigningKey) *SigningKey {
+	if k == nil {
+		return nil
+	}
+
+	return &SigningKey{
+		ID:        k.ID,
+		TenantID:  k.TenantID,
+		Algorithm: signing.Algorithm(k.Algorithm),
+		Secret:    k.Secret,
+		PublicKey: k.PublicKey,
+		CreatedAt: k.CreatedAt,
+		ExpiresAt: k.ExpiresAt,
+	}
+}
+
+// RotateSigningKey creates a new active signing key for a tenant with the given algorithm
+// The previous active key keeps signing for signingKeyGrace; the returned key carries its secret, which cannot be retrieved again
+// Returns ErrNotFound if the tenant does not exist
+func (s *Service) RotateSigningKey(ctx context.Context, tenantID int64, algorithm string) (*SigningKey, error) {
+	alg, err := signing.ParseAlgorithm(algorithm)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrValidation, err)
+	}
+
+	if _, err := s.GetTenant(ctx, tenantID); err != nil {
+		return nil, err
+	}
+
+	secret, publicKey, err := signing.GenerateKey(alg)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.postgres.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		// Rollback is a no-op once the transaction is committed
+		_ = tx.Rollback(ctx)
+	}()
+
+	now := time.Now()
+	if err := s.postgres.SigningKeys.ExpireActiveWithTx(ctx, tx, tenantID, now.Add(signingKeyGrace)); err != nil {
+		return nil, err
+	}
+
+	dbKey := &signingkeys.SigningKey{
+		TenantID:  tenantID,
+		Algorithm: string(alg),
+		Secret:    secret,
+		PublicKey: publicKey,
+		CreatedAt: now,
+	}
+	if err := s.postgres.SigningKeys.CreateWithTx(ctx, tx, dbKey); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit signing key rotation: %w", err)
+	}
+
+	log.Printf("Tenant %d rotated its signing key to %d (%s)", tenantID, dbKey.ID, alg)
+
+	return SigningKeyToDomain(dbKey), nil
+}
+
+// SigningKeys retrieves the keys signing the callbacks of a tenant, the active key first, without their secrets
+// Returns ErrNotFound if the tenant does not exist
+func (s *Service) SigningKeys(ctx context.Context, tenantID int64) ([]*SigningKey, error) {
+	if _, err := s.GetTenant(ctx, tenantID); err != nil {
+		return nil, err
+	}
+
+	keys, err := s.validSigningKeys(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, k := range keys {
+		k.Secret = nil
+	}
+
+	return keys, nil
+}
+
+// SignCallback returns the SignatureHeader value for a callback body sent to a tenant at now
+// The body is signed with every valid key as "t=<unix>,v1=<key id>:<base64 signature>[,v1=...]",
+// so receivers still holding a rotated key keep verifying until it expires
+// Returns ErrNoSigningKey if the tenant never created one
+func (s *Service) SignCallback(ctx context.Context, tenantID int64, body []byte, now time.Time) (string, error) {
+	keys, err := s.validSigningKeys(ctx, tenantID)
+	if err != nil {
+		return "", err
+	}
+	if len(keys) == 0 {
+		return "", ErrNoSigningKey
+	}
+
+	payload := signing.SignedPayload(now.Unix(), body)
+
+	parts := []string{"t=" + strconv.FormatInt(now.Unix(), 10)}
+	for _, k := range keys {
+		signature, err := signing.Sign(k.Algorithm, k.Secret, payload)
+		if err != nil {
+			return "", fmt.Errorf("failed to sign with key %d: %w", k.ID, err)
+		}
+		parts = append(parts, fmt.Sprintf("v1=%d:%s", k.ID, base64.StdEncoding.EncodeToString(signature)))
+	}
+
+	return strings.Join(parts, ","), nil
+}
+
+// validSigningKeys loads the keys of a tenant that did not expire yet, with their secrets
+func (s *Service) validSigningKeys(ctx context.Context, tenantID int64) ([]*SigningKey, error) {
+	dbKeys, err := s.postgres.SigningKeys.ListValid(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get signing keys: %w", err)
+	}
+
+	keys := make([]*SigningKey, 0, len(dbKeys))
+	for _, k := range dbKeys {
+		keys = append(keys, SigningKeyToDomain(k))
+	}
+
+	return keys, nil
+}
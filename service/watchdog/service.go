@@ -0,0 +1,158 @@
+package watchdog
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"runtime"
+	"runtime/pprof"
+	"time"
+
+	"qubit/env/alerthook"
+	"qubit/pkg/scheduler"
+	"qubit/service/message"
+)
+
+// notifyTimeout bounds calling the alert webhook
+const notifyTimeout = 10 * time.Second
+
+// maxDumpBytes bounds the goroutine stack dump logged when a check fires
+const maxDumpBytes = 1 << 20
+
+// Checks run by the watchdog
+const (
+	CheckSchedulerStalled = "scheduler_stalled"
+	CheckBatchLockHeld    = "batch_lock_held"
+	CheckGoroutineGrowth  = "goroutine_growth"
+)
+
+// Settings configure the watchdog, a zero threshold skips its check
+type Settings struct {
+	Interval time.Duration
+	// StallTimeout is how overdue the scheduler loop may be, see message.Service.SchedulerStalled
+	StallTimeout time.Duration
+	// LockHold is how long a batch may hold the processing lock
+	LockHold time.Duration
+	// GoroutineGrowth is how many goroutines may be added to the lowest count seen since startup
+	GoroutineGrowth int
+}
+
+// Service watches this instance for wedged ticks, locks held for too long and leaking goroutines
+// When a check first fires, it logs the stacks of every goroutine and calls the alert webhook, once the check passes
+// again it logs and resolves the alert; checks never block, so the watchdog keeps running while the scheduler hangs
+type Service struct {
+	messageService *message.Service
+	hook           *alerthook.Client // nil without an alert webhook
+	scheduler      *scheduler.Client
+	settings       Settings
+	instanceID     string
+
+	// Only touched by the check task, which never runs concurrently
+	firing            map[string]bool
+	goroutineBaseline int
+}
+
+// NewService creates a new watchdog and starts checking every interval
+func NewService(messageService *message.Service, hook *alerthook.Client, instanceID string, settings Settings) *Service {
+	s := &Service{
+		messageService: messageService,
+		hook:           hook,
+		scheduler:      scheduler.Run(),
+		settings:       settings,
+		instanceID:     instanceID,
+		firing:         make(map[string]bool),
+	}
+
+	if err := s.scheduler.Start(s.checkTask, settings.Interval); err != nil {
+		log.Printf("Warning: failed to start watchdog: %v", err)
+	} else {
+		log.Printf("✓ Watchdog started (interval: %s, stall timeout: %s, lock hold: %s, goroutine growth: %d)",
+			settings.Interval, settings.StallTimeout, settings.LockHold, settings.GoroutineGrowth)
+	}
+
+	return s
+}
+
+// Stop stops the watchdog
+func (s *Service) Stop() error {
+	return s.scheduler.Stop()
+}
+
+// checkTask runs every check and reports the ones that started or stopped firing
+func (s *Service) checkTask(ctx context.Context) error {
+	if s.settings.StallTimeout > 0 {
+		stalled := s.messageService.SchedulerStalled(s.settings.StallTimeout)
+		s.report(ctx, CheckSchedulerStalled, stalled, 2, func() string {
+			status := s.messageService.SchedulerStatus()
+			heartbeat := "never"
+			if status.Heartbeat != nil {
+				heartbeat = time.Since(*status.Heartbeat).Round(time.Second).String() + " ago"
+			}
+			return fmt.Sprintf("scheduler loop overdue by more than %s, last heartbeat %s", s.settings.StallTimeout, heartbeat)
+		})
+	}
+
+	if s.settings.LockHold > 0 {
+		held := s.messageService.BatchLockHeld()
+		s.report(ctx, CheckBatchLockHeld, held > s.settings.LockHold, 2, func() string {
+			return fmt.Sprintf("batch processing lock held for %s, more than %s", held.Round(time.Second), s.settings.LockHold)
+		})
+	}
+
+	if s.settings.GoroutineGrowth > 0 {
+		count := runtime.NumGoroutine()
+		if s.goroutineBaseline == 0 || count < s.goroutineBaseline {
+			s.goroutineBaseline = count
+		}
+		s.report(ctx, CheckGoroutineGrowth, count-s.goroutineBaseline > s.settings.GoroutineGrowth, 1, func() string {
+			return fmt.Sprintf("%d goroutines, %d more than the lowest count of %d", count, count-s.goroutineBaseline, s.goroutineBaseline)
+		})
+	}
+
+	return nil
+}
+
+// report logs and alerts when a check starts or stops firing
+// describe is only called then; dumpDebug is the pprof debug level of the stack dump, 2 for full stacks with wait times,
+// 1 for identical stacks grouped with their count
+func (s *Service) report(ctx context.Context, check string, failing bool, dumpDebug int, describe func() string) {
+	if failing == s.firing[check] {
+		return
+	}
+	s.firing[check] = failing
+
+	alert := alerthook.Alert{Key: "qubit-watchdog-" + check + "-" + s.instanceID, Details: map[string]any{"check": check}}
+	if failing {
+		alert.Summary = "Watchdog: " + describe()
+		log.Printf("⚠ %s, goroutine stacks:\n%s", alert.Summary, dumpGoroutines(dumpDebug))
+	} else {
+		alert.Resolved = true
+		alert.Summary = fmt.Sprintf("Watchdog: %s recovered", check)
+		log.Println(alert.Summary)
+	}
+
+	if s.hook == nil {
+		return
+	}
+
+	notifyCtx, cancel := context.WithTimeout(ctx, notifyTimeout)
+	defer cancel()
+
+	if err := s.hook.Notify(notifyCtx, alert); err != nil {
+		log.Printf("Warning: failed to call the alert webhook for watchdog check %s: %v", check, err)
+	}
+}
+
+// dumpGoroutines returns the stacks of every goroutine, truncated to maxDumpBytes
+func dumpGoroutines(debug int) string {
+	var buf bytes.Buffer
+	if err := pprof.Lookup("goroutine").WriteTo(&buf, debug); err != nil {
+		return fmt.Sprintf("failed to dump goroutines: %v", err)
+	}
+
+	if buf.Len() > maxDumpBytes {
+		return buf.String()[:maxDumpBytes] + "\n... truncated"
+	}
+	return buf.String()
+}